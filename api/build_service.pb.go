@@ -0,0 +1,605 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: build_service.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StartBuildRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Async         bool                   `protobuf:"varint,1,opt,name=async,proto3" json:"async,omitempty"`
+	AllowErrors   bool                   `protobuf:"varint,2,opt,name=allow_errors,json=allowErrors,proto3" json:"allow_errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartBuildRequest) Reset() {
+	*x = StartBuildRequest{}
+	mi := &file_build_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBuildRequest) ProtoMessage() {}
+
+func (x *StartBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBuildRequest.ProtoReflect.Descriptor instead.
+func (*StartBuildRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StartBuildRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+func (x *StartBuildRequest) GetAllowErrors() bool {
+	if x != nil {
+		return x.AllowErrors
+	}
+	return false
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_build_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type BuildStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // pending/running/succeeded/failed/canceled
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	FinishedAt    string                 `protobuf:"bytes,5,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Degraded      []string               `protobuf:"bytes,6,rep,name=degraded,proto3" json:"degraded,omitempty"` // 构建成功但不可用的可选功能，为空表示完整构建
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildStatus) Reset() {
+	*x = BuildStatus{}
+	mi := &file_build_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildStatus) ProtoMessage() {}
+
+func (x *BuildStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildStatus.ProtoReflect.Descriptor instead.
+func (*BuildStatus) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BuildStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BuildStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BuildStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BuildStatus) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *BuildStatus) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *BuildStatus) GetDegraded() []string {
+	if x != nil {
+		return x.Degraded
+	}
+	return nil
+}
+
+type LogLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line          string                 `protobuf:"bytes,1,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogLine) Reset() {
+	*x = LogLine{}
+	mi := &file_build_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLine) ProtoMessage() {}
+
+func (x *LogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLine.ProtoReflect.Descriptor instead.
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LogLine) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+type ListSheetsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSheetsRequest) Reset() {
+	*x = ListSheetsRequest{}
+	mi := &file_build_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSheetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSheetsRequest) ProtoMessage() {}
+
+func (x *ListSheetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSheetsRequest.ProtoReflect.Descriptor instead.
+func (*ListSheetsRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{4}
+}
+
+type SheetSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Columns       int32                  `protobuf:"varint,2,opt,name=columns,proto3" json:"columns,omitempty"`
+	Rows          int32                  `protobuf:"varint,3,opt,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SheetSummary) Reset() {
+	*x = SheetSummary{}
+	mi := &file_build_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SheetSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SheetSummary) ProtoMessage() {}
+
+func (x *SheetSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SheetSummary.ProtoReflect.Descriptor instead.
+func (*SheetSummary) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SheetSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SheetSummary) GetColumns() int32 {
+	if x != nil {
+		return x.Columns
+	}
+	return 0
+}
+
+func (x *SheetSummary) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+type ListSheetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sheets        []*SheetSummary        `protobuf:"bytes,1,rep,name=sheets,proto3" json:"sheets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSheetsResponse) Reset() {
+	*x = ListSheetsResponse{}
+	mi := &file_build_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSheetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSheetsResponse) ProtoMessage() {}
+
+func (x *ListSheetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSheetsResponse.ProtoReflect.Descriptor instead.
+func (*ListSheetsResponse) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListSheetsResponse) GetSheets() []*SheetSummary {
+	if x != nil {
+		return x.Sheets
+	}
+	return nil
+}
+
+type GetSheetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSheetRequest) Reset() {
+	*x = GetSheetRequest{}
+	mi := &file_build_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSheetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSheetRequest) ProtoMessage() {}
+
+func (x *GetSheetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSheetRequest.ProtoReflect.Descriptor instead.
+func (*GetSheetRequest) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSheetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Sheet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ColumnsJson   string                 `protobuf:"bytes,2,opt,name=columns_json,json=columnsJson,proto3" json:"columns_json,omitempty"`
+	RowsJson      string                 `protobuf:"bytes,3,opt,name=rows_json,json=rowsJson,proto3" json:"rows_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sheet) Reset() {
+	*x = Sheet{}
+	mi := &file_build_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sheet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sheet) ProtoMessage() {}
+
+func (x *Sheet) ProtoReflect() protoreflect.Message {
+	mi := &file_build_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sheet.ProtoReflect.Descriptor instead.
+func (*Sheet) Descriptor() ([]byte, []int) {
+	return file_build_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Sheet) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Sheet) GetColumnsJson() string {
+	if x != nil {
+		return x.ColumnsJson
+	}
+	return ""
+}
+
+func (x *Sheet) GetRowsJson() string {
+	if x != nil {
+		return x.RowsJson
+	}
+	return ""
+}
+
+var File_build_service_proto protoreflect.FileDescriptor
+
+const file_build_service_proto_rawDesc = "" +
+	"\n" +
+	"\x13build_service.proto\x12\n" +
+	"builder.v1\"L\n" +
+	"\x11StartBuildRequest\x12\x14\n" +
+	"\x05async\x18\x01 \x01(\bR\x05async\x12!\n" +
+	"\fallow_errors\x18\x02 \x01(\bR\vallowErrors\"\"\n" +
+	"\x10GetStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa7\x01\n" +
+	"\vBuildStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x05 \x01(\tR\n" +
+	"finishedAt\x12\x1a\n" +
+	"\bdegraded\x18\x06 \x03(\tR\bdegraded\"\x1d\n" +
+	"\aLogLine\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\tR\x04line\"\x13\n" +
+	"\x11ListSheetsRequest\"P\n" +
+	"\fSheetSummary\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acolumns\x18\x02 \x01(\x05R\acolumns\x12\x12\n" +
+	"\x04rows\x18\x03 \x01(\x05R\x04rows\"F\n" +
+	"\x12ListSheetsResponse\x120\n" +
+	"\x06sheets\x18\x01 \x03(\v2\x18.builder.v1.SheetSummaryR\x06sheets\"%\n" +
+	"\x0fGetSheetRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"[\n" +
+	"\x05Sheet\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fcolumns_json\x18\x02 \x01(\tR\vcolumnsJson\x12\x1b\n" +
+	"\trows_json\x18\x03 \x01(\tR\browsJson2\xaa\x03\n" +
+	"\fBuildService\x12D\n" +
+	"\n" +
+	"StartBuild\x12\x1d.builder.v1.StartBuildRequest\x1a\x17.builder.v1.BuildStatus\x12B\n" +
+	"\tGetStatus\x12\x1c.builder.v1.GetStatusRequest\x1a\x17.builder.v1.BuildStatus\x12A\n" +
+	"\n" +
+	"StreamLogs\x12\x1c.builder.v1.GetStatusRequest\x1a\x13.builder.v1.LogLine0\x01\x12D\n" +
+	"\vCancelBuild\x12\x1c.builder.v1.GetStatusRequest\x1a\x17.builder.v1.BuildStatus\x12K\n" +
+	"\n" +
+	"ListSheets\x12\x1d.builder.v1.ListSheetsRequest\x1a\x1e.builder.v1.ListSheetsResponse\x12:\n" +
+	"\bGetSheet\x12\x1b.builder.v1.GetSheetRequest\x1a\x11.builder.v1.SheetB&Z$github.com/game-data-builder/api;apib\x06proto3"
+
+var (
+	file_build_service_proto_rawDescOnce sync.Once
+	file_build_service_proto_rawDescData []byte
+)
+
+func file_build_service_proto_rawDescGZIP() []byte {
+	file_build_service_proto_rawDescOnce.Do(func() {
+		file_build_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_build_service_proto_rawDesc), len(file_build_service_proto_rawDesc)))
+	})
+	return file_build_service_proto_rawDescData
+}
+
+var file_build_service_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_build_service_proto_goTypes = []any{
+	(*StartBuildRequest)(nil),  // 0: builder.v1.StartBuildRequest
+	(*GetStatusRequest)(nil),   // 1: builder.v1.GetStatusRequest
+	(*BuildStatus)(nil),        // 2: builder.v1.BuildStatus
+	(*LogLine)(nil),            // 3: builder.v1.LogLine
+	(*ListSheetsRequest)(nil),  // 4: builder.v1.ListSheetsRequest
+	(*SheetSummary)(nil),       // 5: builder.v1.SheetSummary
+	(*ListSheetsResponse)(nil), // 6: builder.v1.ListSheetsResponse
+	(*GetSheetRequest)(nil),    // 7: builder.v1.GetSheetRequest
+	(*Sheet)(nil),              // 8: builder.v1.Sheet
+}
+var file_build_service_proto_depIdxs = []int32{
+	5, // 0: builder.v1.ListSheetsResponse.sheets:type_name -> builder.v1.SheetSummary
+	0, // 1: builder.v1.BuildService.StartBuild:input_type -> builder.v1.StartBuildRequest
+	1, // 2: builder.v1.BuildService.GetStatus:input_type -> builder.v1.GetStatusRequest
+	1, // 3: builder.v1.BuildService.StreamLogs:input_type -> builder.v1.GetStatusRequest
+	1, // 4: builder.v1.BuildService.CancelBuild:input_type -> builder.v1.GetStatusRequest
+	4, // 5: builder.v1.BuildService.ListSheets:input_type -> builder.v1.ListSheetsRequest
+	7, // 6: builder.v1.BuildService.GetSheet:input_type -> builder.v1.GetSheetRequest
+	2, // 7: builder.v1.BuildService.StartBuild:output_type -> builder.v1.BuildStatus
+	2, // 8: builder.v1.BuildService.GetStatus:output_type -> builder.v1.BuildStatus
+	3, // 9: builder.v1.BuildService.StreamLogs:output_type -> builder.v1.LogLine
+	2, // 10: builder.v1.BuildService.CancelBuild:output_type -> builder.v1.BuildStatus
+	6, // 11: builder.v1.BuildService.ListSheets:output_type -> builder.v1.ListSheetsResponse
+	8, // 12: builder.v1.BuildService.GetSheet:output_type -> builder.v1.Sheet
+	7, // [7:13] is the sub-list for method output_type
+	1, // [1:7] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_build_service_proto_init() }
+func file_build_service_proto_init() {
+	if File_build_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_build_service_proto_rawDesc), len(file_build_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_build_service_proto_goTypes,
+		DependencyIndexes: file_build_service_proto_depIdxs,
+		MessageInfos:      file_build_service_proto_msgTypes,
+	}.Build()
+	File_build_service_proto = out.File
+	file_build_service_proto_goTypes = nil
+	file_build_service_proto_depIdxs = nil
+}
@@ -0,0 +1,4 @@
+// Package api 构建编排服务的接口契约，build_service.pb.go 和 build_service_grpc.pb.go 由 build_service.proto 生成
+package api
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative build_service.proto
@@ -0,0 +1,158 @@
+// Package checks 供嵌入构建器的程序以 Go 代码注册自定义验证规则
+//
+// 规则在 init 中通过 Register 注册，构建时在验证阶段与内置验证一起执行，
+// 返回的错误与内置验证的错误一样计入构建报告。
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+)
+
+// Sheet 数据表
+type Sheet = model.DataSheet
+
+// Column 数据表的列定义
+type Column = model.ColumnInfo
+
+// ErrorInfo 验证错误，Row 为 0 表示整张表的错误，Column 为空表示整行的错误
+type ErrorInfo = model.ErrorInfo
+
+// Config 构建配置
+type Config = config.Config
+
+// Manifest 上次构建的输出清单
+type Manifest = output.Manifest
+
+// Context 规则执行时可以访问的构建数据
+type Context struct {
+	Sheets           []*Sheet  // 本次构建的全部表，已完成合并和标签筛选
+	RefSheets        []*Sheet  // 只用于引用、本次不构建的表
+	Config           *Config   // 构建配置
+	PreviousManifest *Manifest // 上次构建的输出清单，首次构建时为空清单
+}
+
+// Sheet 按名称查找本次构建或只用于引用的表，不存在时返回 nil
+func (c *Context) Sheet(name string) *Sheet {
+	for _, sheets := range [][]*Sheet{c.Sheets, c.RefSheets} {
+		for _, sheet := range sheets {
+			if sheet.Name == name {
+				return sheet
+			}
+		}
+	}
+	return nil
+}
+
+// Rule 自定义验证规则
+type Rule interface {
+	// Check 检查数据，返回发现的错误，没有错误时返回空
+	Check(ctx *Context) []*ErrorInfo
+}
+
+// RuleFunc 以函数实现的规则
+type RuleFunc func(ctx *Context) []*ErrorInfo
+
+// Check 实现 Rule 接口
+func (f RuleFunc) Check(ctx *Context) []*ErrorInfo {
+	return f(ctx)
+}
+
+// NamedRule 已注册的规则
+type NamedRule struct {
+	Name string
+	Rule Rule
+}
+
+var (
+	mu    sync.RWMutex
+	rules = make(map[string]Rule)
+)
+
+// Register 注册规则，名称用于错误信息和排序，重复注册同一名称时返回错误
+func Register(name string, rule Rule) error {
+	if name == "" {
+		return fmt.Errorf("规则名称不能为空")
+	}
+	if rule == nil {
+		return fmt.Errorf("规则 %s 不能为空", name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := rules[name]; exists {
+		return fmt.Errorf("规则 %s 已注册", name)
+	}
+	rules[name] = rule
+	return nil
+}
+
+// MustRegister 注册规则，失败时 panic，适合在 init 中使用
+func MustRegister(name string, rule Rule) {
+	if err := Register(name, rule); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterFunc 以函数注册规则
+func RegisterFunc(name string, check func(ctx *Context) []*ErrorInfo) error {
+	return Register(name, RuleFunc(check))
+}
+
+// Unregister 移除规则，规则不存在时不做任何事
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(rules, name)
+}
+
+// Rules 按名称排序的全部已注册规则
+func Rules() []NamedRule {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	registered := make([]NamedRule, 0, len(rules))
+	for name, rule := range rules {
+		registered = append(registered, NamedRule{Name: name, Rule: rule})
+	}
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Name < registered[j].Name
+	})
+	return registered
+}
+
+// Run 依次执行全部已注册的规则，错误信息以规则名称开头；规则 panic 时记录为一条错误，不影响其他规则
+func Run(ctx *Context) []*ErrorInfo {
+	errors := make([]*ErrorInfo, 0)
+	for _, named := range Rules() {
+		errors = append(errors, run(named, ctx)...)
+	}
+	return errors
+}
+
+// run 执行单条规则
+func run(named NamedRule, ctx *Context) (errors []*ErrorInfo) {
+	defer func() {
+		if r := recover(); r != nil {
+			errors = []*ErrorInfo{{Msg: fmt.Sprintf("[%s] 规则执行失败: %v", named.Name, r)}}
+		}
+	}()
+
+	for _, err := range named.Rule.Check(ctx) {
+		if err == nil {
+			continue
+		}
+		errors = append(errors, &ErrorInfo{
+			Sheet:  err.Sheet,
+			Row:    err.Row,
+			Column: err.Column,
+			Msg:    fmt.Sprintf("[%s] %s", named.Name, err.Msg),
+		})
+	}
+	return errors
+}
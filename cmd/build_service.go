@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/model"
+)
+
+// 构建任务状态
+const (
+	BuildPending   = "pending"
+	BuildRunning   = "running"
+	BuildSucceeded = "succeeded"
+	BuildFailed    = "failed"
+	BuildCanceled  = "canceled"
+)
+
+// 已结束任务的保留策略，超过保留时间或数量上限的任务连同日志一起清除
+const (
+	finishedJobTTL  = time.Hour
+	maxFinishedJobs = 100
+)
+
+// BuildRequest 启动构建的参数
+type BuildRequest struct {
+	Async       bool `json:"async"`       // 是否异步转换
+	AllowErrors bool `json:"allowErrors"` // 是否为预览构建
+}
+
+// BuildJob 构建任务
+type BuildJob struct {
+	ID         string    // 任务编号
+	Status     string    // 任务状态
+	Error      string    // 失败原因
+	CreatedAt  time.Time // 创建时间
+	FinishedAt time.Time // 结束时间
+	Degraded   []string  // 构建成功但不可用的可选功能
+
+	mu      sync.Mutex
+	logs    []string
+	updated chan struct{} // 日志或状态更新时关闭并替换
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// snapshot 获取任务状态的副本
+func (j *BuildJob) snapshot() map[string]interface{} {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := map[string]interface{}{
+		"id":        j.ID,
+		"status":    j.Status,
+		"createdAt": j.CreatedAt,
+	}
+	if j.Error != "" {
+		status["error"] = j.Error
+	}
+	if !j.FinishedAt.IsZero() {
+		status["finishedAt"] = j.FinishedAt
+	}
+	if len(j.Degraded) > 0 {
+		status["degraded"] = j.Degraded
+	}
+	return status
+}
+
+// appendLog 追加一行日志并通知等待者
+func (j *BuildJob) appendLog(line string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.logs = append(j.logs, line)
+	j.notifyLocked()
+}
+
+// setStatus 更新任务状态并通知等待者
+func (j *BuildJob) setStatus(status string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Status = status
+	if err != nil {
+		j.Error = err.Error()
+	}
+	if finished(status) {
+		j.FinishedAt = time.Now()
+	}
+	j.notifyLocked()
+}
+
+// finished 任务状态是否表示已结束
+func finished(status string) bool {
+	return status == BuildSucceeded || status == BuildFailed || status == BuildCanceled
+}
+
+// notifyLocked 唤醒所有等待者，调用方需持有锁
+func (j *BuildJob) notifyLocked() {
+	close(j.updated)
+	j.updated = make(chan struct{})
+}
+
+// logsFrom 获取从指定行开始的日志，以及任务是否结束和下一次更新的通知
+func (j *BuildJob) logsFrom(from int) ([]string, bool, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	lines := append([]string(nil), j.logs[from:]...)
+	return lines, finished(j.Status), j.updated
+}
+
+// BuildService 构建编排服务，供内部工具通过接口启动构建、查询状态、获取日志和数据
+type BuildService struct {
+	config *liveConfig // 配置文件修改后自动重新加载
+
+	mu      sync.Mutex
+	jobs    map[string]*BuildJob
+	nextID  int
+	jobTTL  time.Duration // 已结束任务的保留时间
+	maxJobs int           // 保留的已结束任务数量上限
+	run     sync.Mutex    // 同一时间只执行一个构建，上传检查等会写日志的请求同样持有
+}
+
+// NewBuildService 创建构建编排服务，profile 为叠加的环境配置
+func NewBuildService(confDir, profile string) *BuildService {
+	return &BuildService{
+		config:  newLiveConfig(confDir, profile),
+		jobs:    make(map[string]*BuildJob),
+		jobTTL:  finishedJobTTL,
+		maxJobs: maxFinishedJobs,
+	}
+}
+
+// StartBuild 创建构建任务并在后台排队执行
+func (s *BuildService) StartBuild(req BuildRequest) *BuildJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.evictLocked(time.Now())
+	s.nextID++
+	job := &BuildJob{
+		ID:        strconv.Itoa(s.nextID),
+		Status:    BuildPending,
+		CreatedAt: time.Now(),
+		logs:      make([]string, 0),
+		updated:   make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	go s.runJob(job, req)
+	return job
+}
+
+// evictLocked 清除超过保留时间的已结束任务，剩余的已结束任务超过数量上限时从最早结束的开始清除，调用方需持有锁
+func (s *BuildService) evictLocked(now time.Time) {
+	done := make([]*BuildJob, 0)
+	for id, job := range s.jobs {
+		job.mu.Lock()
+		finishedAt := job.FinishedAt
+		job.mu.Unlock()
+		if finishedAt.IsZero() {
+			continue
+		}
+		if now.Sub(finishedAt) > s.jobTTL {
+			delete(s.jobs, id)
+			continue
+		}
+		done = append(done, job)
+	}
+	if len(done) <= s.maxJobs {
+		return
+	}
+
+	sort.Slice(done, func(i, j int) bool {
+		return done[i].FinishedAt.Before(done[j].FinishedAt)
+	})
+	for _, job := range done[:len(done)-s.maxJobs] {
+		delete(s.jobs, job.ID)
+	}
+}
+
+// GetJob 根据编号获取构建任务
+func (s *BuildService) GetJob(id string) *BuildJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[id]
+}
+
+// CancelBuild 取消排队中或执行中的构建任务，已结束的任务不受影响；任务不存在时返回 nil
+func (s *BuildService) CancelBuild(id string) *BuildJob {
+	job := s.GetJob(id)
+	if job != nil {
+		job.cancel()
+	}
+	return job
+}
+
+// runJob 执行构建任务并收集日志
+func (s *BuildService) runJob(job *BuildJob, req BuildRequest) {
+	defer job.cancel()
+	s.run.Lock()
+	defer s.run.Unlock()
+
+	// 排队期间已被取消
+	if err := job.ctx.Err(); err != nil {
+		job.setStatus(BuildCanceled, err)
+		return
+	}
+
+	job.setStatus(BuildRunning, nil)
+	remove := logger.Default().AddSink(jobLogWriter{job: job})
+	degraded, err := s.build(job.ctx, req)
+	remove()
+
+	if errors.Is(err, context.Canceled) {
+		job.setStatus(BuildCanceled, err)
+		return
+	}
+	if err != nil {
+		job.setStatus(BuildFailed, err)
+		return
+	}
+	job.mu.Lock()
+	job.Degraded = degraded
+	job.mu.Unlock()
+	job.setStatus(BuildSucceeded, nil)
+}
+
+// build 使用当前配置执行一次构建，返回构建中不可用的可选功能，ctx 取消时中止构建
+func (s *BuildService) build(ctx context.Context, req BuildRequest) ([]string, error) {
+	builder, err := s.config.newBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %v", err)
+	}
+	builder.allowErrors = req.AllowErrors
+	if req.Async {
+		// 快照与其他请求共享配置，修改前先复制
+		cfg := *builder.configManager.Config
+		cfg.Async = true
+		builder.configManager.Config = &cfg
+	}
+	err = builder.BuildContext(ctx)
+	return builder.Degraded(), err
+}
+
+// jobLogWriter 构建期间接收日志的输出目标，logger 每次写入一条完整的日志
+type jobLogWriter struct {
+	job *BuildJob
+}
+
+// Write 按行追加到任务日志
+func (w jobLogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		w.job.appendLog(line)
+	}
+	return len(p), nil
+}
+
+// ListSheets 读取当前项目中的所有表
+//
+// 与构建共用执行锁：读取过程中的日志写入默认日志记录器，与构建同时执行会混入构建任务的日志
+func (s *BuildService) ListSheets() ([]*model.DataSheet, error) {
+	s.run.Lock()
+	defer s.run.Unlock()
+
+	builder, err := s.config.newBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %v", err)
+	}
+	return builder.readSourceFiles()
+}
+
+// GetSheet 读取当前项目中的指定表，表不存在时返回 nil
+func (s *BuildService) GetSheet(name string) (*model.DataSheet, error) {
+	sheets, err := s.ListSheets()
+	if err != nil {
+		return nil, err
+	}
+	for _, sheet := range sheets {
+		if sheet.Name == name {
+			return sheet, nil
+		}
+	}
+	return nil, nil
+}
+
+// registerRoutes 注册构建编排接口
+func (s *BuildService) registerRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/builds", s.handleStartBuild)
+	mux.HandleFunc("GET /api/builds/{id}", s.handleGetStatus)
+	mux.HandleFunc("GET /api/builds/{id}/logs", s.handleStreamLogs)
+	mux.HandleFunc("POST /api/builds/{id}/cancel", s.handleCancelBuild)
+	mux.HandleFunc("GET /api/sheets", s.handleListSheets)
+	mux.HandleFunc("GET /api/sheets/{name}", s.handleGetSheet)
+}
+
+// handleStartBuild 启动构建
+func (s *BuildService) handleStartBuild(w http.ResponseWriter, r *http.Request) {
+	var req BuildRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("解析请求失败: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	job := s.StartBuild(req)
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, job.snapshot())
+}
+
+// handleGetStatus 查询构建状态
+func (s *BuildService) handleGetStatus(w http.ResponseWriter, r *http.Request) {
+	job := s.GetJob(r.PathValue("id"))
+	if job == nil {
+		http.Error(w, "构建任务不存在", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, job.snapshot())
+}
+
+// handleCancelBuild 取消构建，返回取消请求发出时的任务状态
+func (s *BuildService) handleCancelBuild(w http.ResponseWriter, r *http.Request) {
+	job := s.CancelBuild(r.PathValue("id"))
+	if job == nil {
+		http.Error(w, "构建任务不存在", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, job.snapshot())
+}
+
+// handleStreamLogs 以流的形式逐行返回构建日志，直到构建结束
+func (s *BuildService) handleStreamLogs(w http.ResponseWriter, r *http.Request) {
+	job := s.GetJob(r.PathValue("id"))
+	if job == nil {
+		http.Error(w, "构建任务不存在", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	flusher, _ := w.(http.Flusher)
+	sent := 0
+	for {
+		lines, finished, updated := job.logsFrom(sent)
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		sent += len(lines)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if finished {
+			return
+		}
+
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleListSheets 列出所有表及其行数
+func (s *BuildService) handleListSheets(w http.ResponseWriter, r *http.Request) {
+	sheets, err := s.ListSheets()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	list := make([]map[string]interface{}, 0, len(sheets))
+	for _, sheet := range sheets {
+		list = append(list, map[string]interface{}{
+			"name":    sheet.Name,
+			"columns": len(sheet.Columns),
+			"rows":    len(sheet.Rows),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i]["name"].(string) < list[j]["name"].(string)
+	})
+	writeJSON(w, list)
+}
+
+// handleGetSheet 获取单张表的列和行数据
+func (s *BuildService) handleGetSheet(w http.ResponseWriter, r *http.Request) {
+	sheet, err := s.GetSheet(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if sheet == nil {
+		http.Error(w, "表不存在", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"name":    sheet.Name,
+		"columns": sheet.Columns,
+		"rows":    sheet.Rows,
+	})
+}
+
+// writeJSON 以 JSON 格式写入响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
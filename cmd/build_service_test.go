@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/game-data-builder/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// waitJob 等待构建任务结束，返回全部日志
+func waitJob(t *testing.T, job *BuildJob) []string {
+	t.Helper()
+	timeout := time.After(30 * time.Second)
+	for {
+		lines, finished, updated := job.logsFrom(0)
+		if finished {
+			return lines
+		}
+		select {
+		case <-updated:
+		case <-timeout:
+			t.Fatalf("构建任务 %s 未在限定时间内结束", job.ID)
+		}
+	}
+}
+
+// TestBuildServiceLogsAndListSheets 测试构建日志只收集到任务中，并与读取表的请求并发执行
+func TestBuildServiceLogsAndListSheets(t *testing.T) {
+	writeTestProject(t)
+	service := NewBuildService("conf", "")
+
+	job := service.StartBuild(BuildRequest{})
+	var wg sync.WaitGroup
+	listed := make([][]string, 3)
+	errs := make([]error, 3)
+	for i := range listed {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sheets, err := service.ListSheets()
+			errs[i] = err
+			for _, sheet := range sheets {
+				listed[i] = append(listed[i], sheet.Name)
+			}
+		}(i)
+	}
+	logs := waitJob(t, job)
+	wg.Wait()
+
+	if snapshot := job.snapshot(); snapshot["status"] != BuildSucceeded {
+		t.Fatalf("构建失败: %v\n%s", snapshot, strings.Join(logs, "\n"))
+	}
+	if !strings.Contains(strings.Join(logs, "\n"), "读取文件") {
+		t.Errorf("任务日志中缺少构建日志: %q", logs)
+	}
+	for i := range listed {
+		if errs[i] != nil {
+			t.Fatalf("读取表失败: %v", errs[i])
+		}
+		if len(listed[i]) != 3 {
+			t.Errorf("期望 3 张表，实际为 %v", listed[i])
+		}
+	}
+
+	// 构建结束后的日志不再写入任务
+	count := len(logs)
+	if _, err := service.ListSheets(); err != nil {
+		t.Fatal(err)
+	}
+	if lines, _, _ := job.logsFrom(0); len(lines) != count {
+		t.Errorf("构建结束后任务日志仍在增加: %q", lines[count:])
+	}
+}
+
+// TestBuildServiceCancel 测试取消排队中的构建任务
+func TestBuildServiceCancel(t *testing.T) {
+	writeTestProject(t)
+	service := NewBuildService("conf", "")
+
+	// 持有执行锁，使任务停留在排队状态
+	service.run.Lock()
+	job := service.StartBuild(BuildRequest{})
+	if service.CancelBuild(job.ID) != job {
+		t.Fatal("CancelBuild 未返回任务")
+	}
+	service.run.Unlock()
+	waitJob(t, job)
+
+	if snapshot := job.snapshot(); snapshot["status"] != BuildCanceled || snapshot["finishedAt"] == nil {
+		t.Errorf("期望任务已取消，实际为 %v", snapshot)
+	}
+	if service.CancelBuild("missing") != nil {
+		t.Error("不存在的任务应返回 nil")
+	}
+}
+
+// TestBuildServiceEvictsFinishedJobs 测试超过数量上限的已结束任务被清除
+func TestBuildServiceEvictsFinishedJobs(t *testing.T) {
+	writeTestProject(t)
+	service := NewBuildService("conf", "")
+	service.maxJobs = 1
+
+	first := service.StartBuild(BuildRequest{})
+	waitJob(t, first)
+	second := service.StartBuild(BuildRequest{})
+	waitJob(t, second)
+	third := service.StartBuild(BuildRequest{})
+	waitJob(t, third)
+
+	if service.GetJob(first.ID) != nil {
+		t.Error("最早结束的任务应被清除")
+	}
+	if service.GetJob(second.ID) == nil || service.GetJob(third.ID) == nil {
+		t.Error("未超过上限的任务不应被清除")
+	}
+
+	// 超过保留时间的任务同样清除
+	service.jobTTL = 0
+	fourth := service.StartBuild(BuildRequest{})
+	if service.GetJob(second.ID) != nil || service.GetJob(third.ID) != nil {
+		t.Error("超过保留时间的任务应被清除")
+	}
+	waitJob(t, fourth)
+}
+
+// TestGrpcBuildService 通过 gRPC 客户端调用构建编排服务的所有方法
+func TestGrpcBuildService(t *testing.T) {
+	writeTestProject(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newGrpcServer(NewBuildService("conf", ""))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := api.NewBuildServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	started, err := client.StartBuild(ctx, &api.StartBuildRequest{})
+	if err != nil {
+		t.Fatalf("StartBuild 失败: %v", err)
+	}
+	if started.GetId() == "" || started.GetCreatedAt() == "" {
+		t.Errorf("任务状态不完整: %v", started)
+	}
+
+	// 日志流在构建结束后关闭
+	stream, err := client.StreamLogs(ctx, &api.GetStatusRequest{Id: started.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := make([]string, 0)
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("StreamLogs 失败: %v", err)
+		}
+		lines = append(lines, line.GetLine())
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), "读取文件") {
+		t.Errorf("日志流中缺少构建日志: %q", lines)
+	}
+
+	finished, err := client.GetStatus(ctx, &api.GetStatusRequest{Id: started.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finished.GetStatus() != BuildSucceeded || finished.GetFinishedAt() == "" {
+		t.Errorf("构建未成功结束: %v", finished)
+	}
+
+	list, err := client.ListSheets(ctx, &api.ListSheetsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0)
+	for _, sheet := range list.GetSheets() {
+		names = append(names, sheet.GetName())
+	}
+	if !reflect.DeepEqual(names, []string{"items", "quality", "shop.goods"}) {
+		t.Errorf("表列表不正确: %v", names)
+	}
+
+	sheet, err := client.GetSheet(ctx, &api.GetSheetRequest{Name: "shop.goods"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal([]byte(sheet.GetRowsJson()), &rows); err != nil {
+		t.Fatalf("行数据不是合法的 JSON: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["price"] != float64(100) {
+		t.Errorf("行数据不正确: %v", rows)
+	}
+
+	// 不存在的任务和表返回 NotFound
+	if _, err := client.GetStatus(ctx, &api.GetStatusRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("期望 NotFound，实际为 %v", err)
+	}
+	if _, err := client.GetSheet(ctx, &api.GetSheetRequest{Name: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("期望 NotFound，实际为 %v", err)
+	}
+	if _, err := client.CancelBuild(ctx, &api.GetStatusRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("期望 NotFound，实际为 %v", err)
+	}
+
+	// 取消已结束的任务不改变其状态
+	canceled, err := client.CancelBuild(ctx, &api.GetStatusRequest{Id: started.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canceled.GetStatus() != BuildSucceeded {
+		t.Errorf("已结束的任务状态被改变: %v", canceled)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// testProjectConfig 测试项目的配置：tables 目录不加命名空间，shop 目录使用 shop 命名空间
+const testProjectConfig = `{
+  "sourceDir": "./tables",
+  "sourceRoots": [{"dir": "./shop", "namespace": "shop"}],
+  "outputDir": "./output",
+  "formats": ["json"],
+  "readers": {"default": {"type": "default", "enabled": true, "options": {"skipEmptyRows": true}}},
+  "converters": {"json": {"type": "json", "enabled": true, "outputPath": "json", "options": {}}},
+  "validators": {"default": {"type": "default", "enabled": true, "options": {"strict": true}}}
+}`
+
+// writeTestProject 在临时目录中创建测试项目并切换到该目录，返回项目目录
+//
+// shop.goods 引用了 items，items 又引用了 quality
+func writeTestProject(t *testing.T) string {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	files := map[string]string{
+		"conf/config.json": testProjectConfig,
+		"tables/quality.csv": "id,name\n" +
+			"int,string\n" +
+			"ID|主键,名称\n" +
+			"1,common\n" +
+			"2,rare\n",
+		"tables/items.csv": "id,name,quality\n" +
+			"int,string,int\n" +
+			"ID|主键,名称,品质|引用:quality.id\n" +
+			"1,sword,1\n" +
+			"2,shield,2\n",
+		"shop/goods.csv": "id,item,price\n" +
+			"int,int,int\n" +
+			"ID|主键,物品|引用:items.id,价格\n" +
+			"1,1,100\n" +
+			"2,2,80\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// newTestBuilder 加载测试项目配置的构建器
+func newTestBuilder(t *testing.T) *Builder {
+	builder := NewBuilder()
+	if err := builder.LoadConfig("conf"); err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	return builder
+}
+
+// outputSheets 输出目录中生成的 JSON 文件，使用/分隔的相对路径
+func outputSheets(t *testing.T, dir string) []string {
+	root := filepath.Join(dir, "output", "json")
+	names := make([]string, 0)
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && filepath.Ext(path) == ".json" {
+			relPath, _ := filepath.Rel(root, path)
+			names = append(names, filepath.ToSlash(relPath))
+		}
+		return nil
+	})
+	return names
+}
+
+// TestLockedBuildDetectsChanges 测试 -locked 构建在源文件或配置与 build.lock 不一致时失败
+func TestLockedBuildDetectsChanges(t *testing.T) {
+	dir := writeTestProject(t)
+	if err := newTestBuilder(t).BuildContext(context.Background()); err != nil {
+		t.Fatalf("首次构建失败: %v", err)
+	}
+
+	locked := newTestBuilder(t)
+	locked.locked = true
+	if err := locked.BuildContext(context.Background()); err != nil {
+		t.Fatalf("输入未变化时锁定构建失败: %v", err)
+	}
+
+	itemsPath := filepath.Join(dir, "tables", "items.csv")
+	content, _ := os.ReadFile(itemsPath)
+	if err := os.WriteFile(itemsPath, append(content, "3,bow,1\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	newPath := filepath.Join(dir, "tables", "extra.csv")
+	if err := os.WriteFile(newPath, []byte("id\nint\nID|主键\n1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	locked = newTestBuilder(t)
+	locked.locked = true
+	err := locked.BuildContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "共 2 处差异") {
+		t.Errorf("期望源文件修改和新增两处差异，实际为 %v", err)
+	}
+}
+
+// TestSelectSheetsOnlyAndFile 测试 -only 指定带命名空间的表、-file 指定相对路径的文件时，
+// 只输出指定的表，只用于引用校验的表仍然读取
+func TestSelectSheetsOnlyAndFile(t *testing.T) {
+	dir := writeTestProject(t)
+
+	builder := newTestBuilder(t)
+	builder.only = splitList(" shop.goods ,")
+	if err := builder.BuildContext(context.Background()); err != nil {
+		t.Fatalf("-only 构建失败: %v", err)
+	}
+	if got := outputSheets(t, dir); !reflect.DeepEqual(got, []string{"shop/goods.json"}) {
+		t.Errorf("期望只输出 shop.goods，实际为 %v", got)
+	}
+
+	builder = newTestBuilder(t)
+	builder.files = splitList(filepath.Join("tables", "items.csv"))
+	sheets, err := builder.readSourceFiles()
+	if err != nil {
+		t.Fatalf("读取源文件失败: %v", err)
+	}
+	selected, err := builder.selectSheets(sheets)
+	if err != nil {
+		t.Fatalf("-file 筛选失败: %v", err)
+	}
+	if len(selected) != 1 || selected[0].Name != "items" {
+		t.Errorf("期望只选中 items，实际为 %v", sheetNames(selected))
+	}
+	refNames := sheetNames(builder.refSheets)
+	if !reflect.DeepEqual(refNames, []string{"quality", "shop.goods"}) {
+		t.Errorf("期望 quality 和 shop.goods 只用于引用校验，实际为 %v", refNames)
+	}
+
+	builder = newTestBuilder(t)
+	builder.only = []string{"goods"}
+	sheets, _ = builder.readSourceFiles()
+	if _, err := builder.selectSheets(sheets); err == nil {
+		t.Error("期望不带命名空间的表名 goods 报错")
+	}
+}
+
+// TestSplitList 测试解析逗号分隔的表名和文件路径
+func TestSplitList(t *testing.T) {
+	got := splitList(" a.csv, tables/b 1.xlsx ,,a.csv,")
+	if !reflect.DeepEqual(got, []string{"a.csv", "tables/b 1.xlsx"}) {
+		t.Errorf("解析结果错误: %v", got)
+	}
+	if got := splitList(""); len(got) != 0 {
+		t.Errorf("期望空列表，实际为 %v", got)
+	}
+}
+
+// sheetNames 表名列表，按名称排序
+func sheetNames(sheets []*model.DataSheet) []string {
+	names := make([]string, 0, len(sheets))
+	for _, sheet := range sheets {
+		names = append(names, sheet.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/lock"
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/output"
+)
+
+// configHashes 配置目录中各配置文件以及 fragments 引入的片段的哈希，表版本是构建产生的状态，不属于构建输入
+//
+// 配置目录之外的片段以相对配置目录的路径记录；配置无法解析时只记录已解析到的片段，由加载配置时报告错误
+func configHashes(confDir string) (map[string]string, error) {
+	hashes, err := lock.HashFiles(confDir, func(path string) bool {
+		return filepath.Ext(path) == ".json" && filepath.Base(path) != output.SheetVersionsFileName
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fragments, _ := config.ConfigFiles(confDir)
+	absDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range fragments {
+		relPath, err := filepath.Rel(absDir, path)
+		if err != nil {
+			return nil, err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if _, exists := hashes[relPath]; exists {
+			continue
+		}
+		if hashes[relPath], err = lock.HashFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
+
+// liveConfig 守护进程使用的配置：每次使用前检查配置目录中的文件是否变化，变化时重新加载并校验，
+// 校验失败时继续使用上一次有效的配置，修改配置不需要重启进程
+type liveConfig struct {
+	confDir string
+	profile string // 叠加在 config.json 上的环境配置名
+
+	mu      sync.Mutex
+	manager *config.ConfigManager // 最近一次有效的配置，首次加载成功前为空
+	hashes  map[string]string     // 上次检查时配置文件的哈希
+}
+
+// newLiveConfig 创建守护进程使用的配置，首次使用时加载，profile 为叠加的环境配置
+func newLiveConfig(confDir, profile string) *liveConfig {
+	return &liveConfig{confDir: confDir, profile: profile}
+}
+
+// Snapshot 获取当前有效配置的快照，配置文件有变化时先重新加载
+func (c *liveConfig) Snapshot() (*config.ConfigManager, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hashes, err := configHashes(c.confDir)
+	if err != nil {
+		return nil, err
+	}
+	if c.manager != nil && reflect.DeepEqual(hashes, c.hashes) {
+		return c.manager.Snapshot(), nil
+	}
+
+	if c.manager == nil {
+		// 还没有有效的配置时每次都重新尝试加载
+		manager := config.NewConfigManager()
+		manager.SetProfile(c.profile)
+		if err := manager.Load(c.confDir); err != nil {
+			return nil, err
+		}
+		c.manager = manager
+	} else if err := c.manager.Reload(); err != nil {
+		logger.Errorf("重新加载配置失败，继续使用原配置: %v", err)
+	} else {
+		logger.Infof("配置已重新加载")
+	}
+	c.hashes = hashes
+	return c.manager.Snapshot(), nil
+}
+
+// newBuilder 基于当前有效配置的快照创建构建器
+func (c *liveConfig) newBuilder() (*Builder, error) {
+	snapshot, err := c.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	builder := NewBuilder()
+	builder.confDir = c.confDir
+	builder.configManager = snapshot
+	return builder, nil
+}
+
+// buildSettingsChanged 两份配置中影响构建结果的部分是否不同；
+// 调试推送地址和构建通知只在构建之后使用，只修改它们时不需要重新构建
+func buildSettingsChanged(previous, current *config.ConfigManager) bool {
+	settings := func(cm *config.ConfigManager) []interface{} {
+		cfg := *cm.Config
+		cfg.DevPush = config.DevPushConfig{}
+		cfg.Webhooks = nil
+		return []interface{}{cfg, cm.CombineConfig, cm.ReplaceConfig, cm.FrozenConfig, cm.Permissions,
+			cm.Transforms, cm.Constants, cm.SheetsConfig, cm.ColumnGroups, cm.Sources, cm.Filters}
+	}
+	return !reflect.DeepEqual(settings(previous), settings(current))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+)
+
+// TestBuildSettingsChangedIgnoresPostBuildSettings 测试只修改调试推送和构建通知时不需要重新构建
+func TestBuildSettingsChangedIgnoresPostBuildSettings(t *testing.T) {
+	writeTestProject(t)
+	previous := newTestBuilder(t).configManager.Snapshot()
+
+	current := previous.Snapshot()
+	cfg := *previous.Config
+	cfg.DevPush = config.DevPushConfig{Addr: "127.0.0.1:9000", TimeoutMs: 500}
+	cfg.Webhooks = []config.WebhookConfig{{URL: "https://example.com/hook", Format: "slack"}}
+	current.Config = &cfg
+	if buildSettingsChanged(previous, current) {
+		t.Error("期望只修改 devPush 和 webhooks 时不需要重新构建")
+	}
+
+	cfg.Formats = append([]string{}, cfg.Formats...)
+	cfg.Formats = append(cfg.Formats, "php")
+	if !buildSettingsChanged(previous, current) {
+		t.Error("期望修改 formats 时需要重新构建")
+	}
+}
+
+// TestLiveConfigKeepsLastValidConfig 测试配置修改后重新加载，重新加载失败时继续使用上一次有效的配置
+func TestLiveConfigKeepsLastValidConfig(t *testing.T) {
+	dir := writeTestProject(t)
+	configPath := filepath.Join(dir, "conf", "config.json")
+	live := newLiveConfig("conf", "")
+
+	snapshot, err := live.Snapshot()
+	if err != nil {
+		t.Fatalf("首次加载失败: %v", err)
+	}
+	if snapshot.Config.FastMode {
+		t.Fatal("首次加载的配置不应开启快速模式")
+	}
+
+	updated := strings.Replace(testProjectConfig, `"formats": ["json"],`, `"formats": ["json"], "fastMode": true,`, 1)
+	if err := os.WriteFile(configPath, []byte(updated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err = live.Snapshot()
+	if err != nil || !snapshot.Config.FastMode {
+		t.Fatalf("期望重新加载后开启快速模式: %v", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"sourceDir": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err = live.Snapshot()
+	if err != nil {
+		t.Fatalf("重新加载失败时应继续使用原配置: %v", err)
+	}
+	if !snapshot.Config.FastMode {
+		t.Error("期望保留上一次有效的配置")
+	}
+
+	// 修复后再次重新加载
+	if err := os.WriteFile(configPath, []byte(testProjectConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err = live.Snapshot()
+	if err != nil || snapshot.Config.FastMode {
+		t.Errorf("期望修复后重新加载并关闭快速模式: %v", err)
+	}
+}
+
+// TestLiveConfigInitialLoadFailure 测试首次加载失败时返回错误，修复后可以加载
+func TestLiveConfigInitialLoadFailure(t *testing.T) {
+	dir := writeTestProject(t)
+	configPath := filepath.Join(dir, "conf", "config.json")
+	if err := os.WriteFile(configPath, []byte(`{`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	live := newLiveConfig("conf", "")
+	if _, err := live.Snapshot(); err == nil {
+		t.Fatal("期望首次加载无效配置时报错")
+	}
+	if err := os.WriteFile(configPath, []byte(testProjectConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := live.Snapshot(); err != nil {
+		t.Errorf("期望修复后加载成功: %v", err)
+	}
+}
+
+// TestLiveConfigReloadsOutsideFragments 测试配置目录之外的片段修改后同样重新加载
+func TestLiveConfigReloadsOutsideFragments(t *testing.T) {
+	dir := writeTestProject(t)
+	fragmentPath := filepath.Join(dir, "shared", "mode.json")
+	if err := os.MkdirAll(filepath.Dir(fragmentPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fragmentPath, []byte(`{"fastMode": false}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mainConfig := strings.Replace(testProjectConfig, `"formats": ["json"],`, `"formats": ["json"], "fragments": ["../shared/*.json"],`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "conf", "config.json"), []byte(mainConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	live := newLiveConfig("conf", "")
+	snapshot, err := live.Snapshot()
+	if err != nil || snapshot.Config.FastMode {
+		t.Fatalf("首次加载失败或快速模式已开启: %v", err)
+	}
+
+	if err := os.WriteFile(fragmentPath, []byte(`{"fastMode": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err = live.Snapshot()
+	if err != nil || !snapshot.Config.FastMode {
+		t.Errorf("期望片段修改后重新加载并开启快速模式: %v", err)
+	}
+
+	hashes, err := configHashes("conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashes["../shared/mode.json"] == "" {
+		t.Errorf("配置哈希中缺少配置目录之外的片段: %v", hashes)
+	}
+}
+
+// TestLiveConfigProfile 测试守护进程使用的配置叠加指定的环境配置
+func TestLiveConfigProfile(t *testing.T) {
+	dir := writeTestProject(t)
+	if err := os.WriteFile(filepath.Join(dir, "conf", "config.dev.json"), []byte(`{"fastMode": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := newLiveConfig("conf", "dev").Snapshot()
+	if err != nil || !snapshot.Config.FastMode {
+		t.Errorf("期望叠加 config.dev.json 后开启快速模式: %v", err)
+	}
+	snapshot, err = newLiveConfig("conf", "").Snapshot()
+	if err != nil || snapshot.Config.FastMode {
+		t.Errorf("未指定环境配置时不应叠加: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/report"
+)
+
+// 降级构建中不可用的可选子系统
+const (
+	subsystemUpload  = "上传"    // remoteSync、syncTargets 和 sinks 中标记为 optional 的输出目标
+	subsystemNotify  = "通知"    // 构建完成的 Webhook 通知
+	subsystemPush    = "调试推送"  // 推送变更到运行中的游戏
+	subsystemCache   = "数据源缓存" // 远程数据源不可用时使用了上次的缓存
+	subsystemToolset = "外部工具"  // 转换器依赖的外部工具缺失，已跳过或改用替代转换器
+	subsystemStatus  = "状态文件"  // 构建状态文件和徽章
+)
+
+// degradation 一个已配置但本次不可用的可选子系统
+type degradation struct {
+	Subsystem string // 子系统
+	Target    string // 不可用的目标，如输出目标名称、Webhook 地址
+	Reason    string // 原因
+}
+
+// String 报告中的一行
+func (d degradation) String() string {
+	if d.Target == "" {
+		return fmt.Sprintf("[%s] %s", d.Subsystem, d.Reason)
+	}
+	return fmt.Sprintf("[%s] %s: %s", d.Subsystem, d.Target, d.Reason)
+}
+
+// degrade 记录不可用的可选子系统，构建继续进行，结束时在报告的“降级构建”一节列出
+func (b *Builder) degrade(subsystem, target string, reason error) {
+	item := degradation{Subsystem: subsystem, Target: target, Reason: reason.Error()}
+	b.degraded = append(b.degraded, item)
+	logger.WithFields(logger.Fields{"subsystem": subsystem}).Warnf("降级构建: %s", item)
+}
+
+// Degraded 本次构建中不可用的可选子系统，为空表示完整构建
+func (b *Builder) Degraded() []string {
+	lines := make([]string, 0, len(b.degraded))
+	for _, item := range b.degraded {
+		lines = append(lines, item.String())
+	}
+	return lines
+}
+
+// logDegraded 输出“降级构建”一节；通知在构建报告之后发送，因此单独输出
+func (b *Builder) logDegraded() {
+	if len(b.degraded) == 0 {
+		return
+	}
+
+	section := &report.Section{Title: "降级构建"}
+	for _, item := range b.degraded {
+		section.Addf("%s", item)
+	}
+	(&report.Report{Sections: []*report.Section{section}}).Log()
+	logger.Warnf("构建完成，但 %d 个可选功能不可用", len(b.degraded))
+}
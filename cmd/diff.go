@@ -0,0 +1,173 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/diff"
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// loadSourceAt 读取源文件目录在指定 git 提交中的内容，经过与构建相同的预处理后返回
+func (b *Builder) loadSourceAt(ref string) ([]*model.DataSheet, error) {
+	tempDir, err := os.MkdirTemp("", "builder-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	// 每个源文件目录导出到单独的子目录，读取时临时替换配置中的目录
+	cfg := b.configManager.Config
+	sourceDir, sourceRoots := cfg.SourceDir, cfg.SourceRoots
+	defer func() { cfg.SourceDir, cfg.SourceRoots = sourceDir, sourceRoots }()
+
+	roots := cfg.AllSourceRoots()
+	for i, root := range roots {
+		dest := filepath.Join(tempDir, strconv.Itoa(i))
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return nil, err
+		}
+		if err := extractGitTree(root.Dir, ref, dest); err != nil {
+			return nil, err
+		}
+		roots[i].Dir = dest
+	}
+	cfg.SourceDir, cfg.SourceRoots = roots[0].Dir, roots[1:]
+
+	sheets, err := b.readSourceFiles()
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 中的源文件失败: %v", ref, err)
+	}
+	return sheets, nil
+}
+
+// extractGitTree 把目录在指定提交中的文件导出到 dest
+func extractGitTree(dir, ref, dest string) error {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "archive", "--format=tar", ref, ".")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("导出 %s 失败: %v %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	archive := tar.NewReader(&stdout)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("解析 %s 的归档失败: %v", ref, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		path := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dest)+string(filepath.Separator)) {
+			return fmt.Errorf("归档中的路径无效: %s", header.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		content, err := io.ReadAll(archive)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+	}
+}
+
+// runDiff 执行 diff 子命令，比较两个输出目录，或源数据在某个 git 提交与工作区之间的差异
+func runDiff(args []string) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	ref := flags.String("ref", "", "与工作区比较的 git 提交")
+	format := flags.String("format", "text", "报告格式：text、html 或 json")
+	out := flags.String("out", "", "报告输出文件，默认输出到标准输出")
+	tags := flags.String("tags", "", "只比较带有这些标签的表，以逗号分隔，需与 -ref 一起使用")
+	flags.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  builder diff [options] OLD_OUTPUT_DIR NEW_OUTPUT_DIR")
+		fmt.Println("  builder diff -ref REF [options]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var report *diff.Report
+	switch {
+	case *ref != "" && flags.NArg() == 0:
+		// 读取过程中的进度输出改写到标准错误，避免混入报告
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		builder := NewBuilder()
+		if err := builder.LoadConfig(*confDir); err != nil {
+			logger.Errorf("加载配置失败: %v", err)
+			os.Exit(1)
+		}
+		// 只有 HTML 和 JSON 报告展示批注和条件格式
+		builder.annotations = *format != "text"
+		oldSheets, err := builder.loadSourceAt(*ref)
+		if err != nil {
+			logger.Errorf("%v", err)
+			os.Exit(1)
+		}
+		newSheets, err := builder.readSourceFiles()
+		if err != nil {
+			logger.Errorf("读取源文件失败: %v", err)
+			os.Exit(1)
+		}
+		os.Stdout = stdout
+		if selected := reader.ParseTags(*tags); len(selected) > 0 {
+			oldSheets = reader.SelectTags(oldSheets, selected)
+			newSheets = reader.SelectTags(newSheets, selected)
+		}
+		report = diff.Compare(oldSheets, newSheets)
+		report.Old, report.New = *ref, "工作区"
+	case *ref == "" && flags.NArg() == 2 && *tags == "":
+		oldSheets, err := diff.LoadOutputDir(flags.Arg(0))
+		if err != nil {
+			logger.Errorf("%v", err)
+			os.Exit(1)
+		}
+		newSheets, err := diff.LoadOutputDir(flags.Arg(1))
+		if err != nil {
+			logger.Errorf("%v", err)
+			os.Exit(1)
+		}
+		report = diff.Compare(oldSheets, newSheets)
+		report.Old, report.New = flags.Arg(0), flags.Arg(1)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	writer := io.Writer(os.Stdout)
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			logger.Errorf("创建报告文件失败: %v", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		writer = file
+	}
+	if err := report.Write(writer, *format); err != nil {
+		logger.Errorf("输出报告失败: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/game-data-builder/internal/fixture"
+	"github.com/game-data-builder/internal/logger"
+)
+
+// runFixtures 执行 fixtures 子命令，从源数据截取少量行并补齐引用，按启用的格式输出测试夹具，
+// 供客户端和服务端的单元测试使用真实结构的配置，而不必带上完整的线上数据
+func runFixtures(args []string) {
+	flags := flag.NewFlagSet("fixtures", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	rows := flags.Int("rows", 5, "每张表保留的行数，被引用的行会额外补入")
+	out := flags.String("out", "./fixtures", "夹具输出目录")
+	flags.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  builder fixtures [options]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *rows < 1 {
+		logger.Errorf("-rows 必须大于 0")
+		os.Exit(2)
+	}
+
+	builder := NewBuilder()
+	if err := builder.LoadConfig(*confDir); err != nil {
+		logger.Errorf("加载配置失败: %v", err)
+		os.Exit(1)
+	}
+	sheets, err := builder.readSourceFiles()
+	if err != nil {
+		logger.Errorf("读取源文件失败: %v", err)
+		os.Exit(1)
+	}
+
+	// 截取时考虑所有表的引用，被引用表即使只有补入的行也会输出
+	fixtures := fixture.Select(sheets, *rows)
+	for i, sheet := range fixtures {
+		logger.Debugf("%s: 保留 %d/%d 行", sheet.Name, len(sheet.Rows), len(sheets[i].Rows))
+	}
+	results, err := builder.convertData(fixtures)
+	if err != nil {
+		logger.Errorf("转换失败: %v", err)
+		os.Exit(1)
+	}
+
+	files := builder.outputFiles(results)
+	for _, file := range files {
+		path := filepath.Join(*out, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			logger.Errorf("创建目录失败: %v", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, file.Content, 0644); err != nil {
+			logger.Errorf("写入夹具失败: %v", err)
+			os.Exit(1)
+		}
+	}
+	logger.Infof("已生成 %d 张表的测试夹具，共 %d 个文件: %s", len(fixtures), len(files), *out)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/game-data-builder/api"
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcBuildServer 以 gRPC 提供构建编排服务，与 serve 子命令的 HTTP 接口共用 BuildService
+type grpcBuildServer struct {
+	api.UnimplementedBuildServiceServer
+	builds *BuildService
+}
+
+// newGrpcServer 创建注册了 BuildService 的 gRPC 服务
+func newGrpcServer(builds *BuildService) *grpc.Server {
+	server := grpc.NewServer()
+	api.RegisterBuildServiceServer(server, &grpcBuildServer{builds: builds})
+	return server
+}
+
+// StartBuild 创建构建任务并在后台排队执行
+func (s *grpcBuildServer) StartBuild(ctx context.Context, req *api.StartBuildRequest) (*api.BuildStatus, error) {
+	job := s.builds.StartBuild(BuildRequest{Async: req.GetAsync(), AllowErrors: req.GetAllowErrors()})
+	return buildStatus(job), nil
+}
+
+// GetStatus 查询构建状态
+func (s *grpcBuildServer) GetStatus(ctx context.Context, req *api.GetStatusRequest) (*api.BuildStatus, error) {
+	job := s.builds.GetJob(req.GetId())
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "构建任务 %s 不存在", req.GetId())
+	}
+	return buildStatus(job), nil
+}
+
+// CancelBuild 取消排队中或执行中的构建任务
+func (s *grpcBuildServer) CancelBuild(ctx context.Context, req *api.GetStatusRequest) (*api.BuildStatus, error) {
+	job := s.builds.CancelBuild(req.GetId())
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "构建任务 %s 不存在", req.GetId())
+	}
+	return buildStatus(job), nil
+}
+
+// StreamLogs 逐行发送构建日志，直到构建结束或客户端断开
+func (s *grpcBuildServer) StreamLogs(req *api.GetStatusRequest, stream grpc.ServerStreamingServer[api.LogLine]) error {
+	job := s.builds.GetJob(req.GetId())
+	if job == nil {
+		return status.Errorf(codes.NotFound, "构建任务 %s 不存在", req.GetId())
+	}
+
+	sent := 0
+	for {
+		lines, finished, updated := job.logsFrom(sent)
+		for _, line := range lines {
+			if err := stream.Send(&api.LogLine{Line: line}); err != nil {
+				return err
+			}
+		}
+		sent += len(lines)
+		if finished {
+			return nil
+		}
+
+		select {
+		case <-updated:
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+// ListSheets 列出所有表及其列数、行数，按表名排序
+func (s *grpcBuildServer) ListSheets(ctx context.Context, req *api.ListSheetsRequest) (*api.ListSheetsResponse, error) {
+	sheets, err := s.builds.ListSheets()
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	resp := &api.ListSheetsResponse{Sheets: make([]*api.SheetSummary, 0, len(sheets))}
+	for _, sheet := range sheets {
+		resp.Sheets = append(resp.Sheets, &api.SheetSummary{
+			Name:    sheet.Name,
+			Columns: int32(len(sheet.Columns)),
+			Rows:    int32(len(sheet.Rows)),
+		})
+	}
+	sort.Slice(resp.Sheets, func(i, j int) bool {
+		return resp.Sheets[i].Name < resp.Sheets[j].Name
+	})
+	return resp, nil
+}
+
+// GetSheet 获取单张表的列定义和行数据，以 JSON 编码
+func (s *grpcBuildServer) GetSheet(ctx context.Context, req *api.GetSheetRequest) (*api.Sheet, error) {
+	sheet, err := s.builds.GetSheet(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if sheet == nil {
+		return nil, status.Errorf(codes.NotFound, "表 %s 不存在", req.GetName())
+	}
+
+	columns, err := json.Marshal(sheet.Columns)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	rows, err := json.Marshal(sheet.Rows)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.Sheet{Name: sheet.Name, ColumnsJson: string(columns), RowsJson: string(rows)}, nil
+}
+
+// buildStatus 将任务状态转换为接口消息，时间使用 RFC 3339 格式
+func buildStatus(job *BuildJob) *api.BuildStatus {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	result := &api.BuildStatus{
+		Id:        job.ID,
+		Status:    job.Status,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt.Format(time.RFC3339Nano),
+		Degraded:  append([]string(nil), job.Degraded...),
+	}
+	if !job.FinishedAt.IsZero() {
+		result.FinishedAt = job.FinishedAt.Format(time.RFC3339Nano)
+	}
+	return result
+}
+
+// runGrpcServe 执行 grpc-serve 子命令，以 gRPC 提供构建编排服务
+func runGrpcServe(args []string) {
+	flags := flag.NewFlagSet("grpc-serve", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	addr := flags.String("addr", ":9090", "监听地址")
+	profile := flags.String("profile", os.Getenv(config.ProfileEnv), "环境配置，将 config.<profile>.json 合并到 config.json 上")
+	logOptions := addLogFlags(flags)
+	flags.Parse(args)
+	logOptions.apply()
+	if *profile != "" {
+		logger.Infof("使用环境配置: %s", *profile)
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		logger.Errorf("监听 %s 失败: %v", *addr, err)
+		os.Exit(1)
+	}
+
+	server := newGrpcServer(NewBuildService(*confDir, *profile))
+	logger.Infof("gRPC 服务已启动: %s", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		logger.Errorf("服务异常退出: %v", err)
+		os.Exit(1)
+	}
+}
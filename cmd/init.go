@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/logger"
+)
+
+// templateFS 内嵌的项目模板
+//
+//go:embed templates
+var templateFS embed.FS
+
+// InitOptions 初始化项目的选项
+type InitOptions struct {
+	Dir       string   // 项目目录
+	SourceDir string   // 源文件目录（相对于项目目录）
+	OutputDir string   // 输出目录（相对于项目目录）
+	Formats   []string // 输出格式
+	Force     bool     // 是否覆盖已存在的文件
+}
+
+// InitProject 根据模板生成配置目录、示例表格和 .gitignore 并创建输出目录，返回生成的文件
+func InitProject(opts InitOptions) ([]string, error) {
+	factory := converter.NewConverterFactory()
+	for _, format := range opts.Formats {
+		if factory.GetConverter(format) == nil {
+			return nil, fmt.Errorf("不支持的格式: %s", format)
+		}
+	}
+
+	data := map[string]interface{}{
+		"SourceDir":     configPath(opts.SourceDir),
+		"OutputDir":     configPath(opts.OutputDir),
+		"IgnoredOutput": filepath.ToSlash(filepath.Clean(opts.OutputDir)),
+		"Formats":       opts.Formats,
+	}
+
+	files := []struct {
+		template string
+		target   string
+	}{
+		{"templates/config.json.tmpl", filepath.Join("conf", "config.json")},
+		{"templates/combine.json", filepath.Join("conf", "combine.json")},
+		{"templates/replaceColumn.json", filepath.Join("conf", "replaceColumn.json")},
+		{"templates/items.csv", filepath.Join(opts.SourceDir, "items.csv")},
+		{"templates/gitignore.tmpl", ".gitignore"},
+	}
+
+	created := make([]string, 0, len(files))
+	for _, file := range files {
+		content, err := renderTemplate(file.template, data)
+		if err != nil {
+			return nil, err
+		}
+
+		target := filepath.Join(opts.Dir, file.target)
+		if _, err := os.Stat(target); err == nil && !opts.Force {
+			logger.Infof("跳过已存在的文件: %s", target)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return nil, err
+		}
+		created = append(created, target)
+	}
+
+	// 创建空的输出目录
+	if err := os.MkdirAll(filepath.Join(opts.Dir, opts.OutputDir), 0755); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// configPath 配置文件中的目录写法，相对路径以 ./ 开头
+func configPath(dir string) string {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return "./" + dir
+}
+
+// renderTemplate 渲染内嵌模板，非 .tmpl 文件原样返回
+func renderTemplate(name string, data interface{}) ([]byte, error) {
+	content, err := templateFS.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".tmpl") {
+		return content, nil
+	}
+
+	tmpl, err := template.New(filepath.Base(name)).Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			encoded, err := json.Marshal(v)
+			return string(encoded), err
+		},
+	}).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// prompt 提示输入，直接回车时使用默认值
+func prompt(in *bufio.Reader, out io.Writer, question, defaultValue string) string {
+	fmt.Fprintf(out, "%s [%s]: ", question, defaultValue)
+	line, _ := in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return defaultValue
+	}
+	return line
+}
+
+// isTerminal 文件是否为终端
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runInit 执行 init 子命令
+func runInit(args []string) {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	dir := flags.String("dir", ".", "项目目录")
+	sourceDir := flags.String("source", "examples", "源文件目录")
+	outputDir := flags.String("output", "output", "输出目录")
+	formats := flags.String("formats", "json,php", "输出格式，以逗号分隔")
+	yes := flags.Bool("yes", false, "不进行交互，直接使用参数或默认值")
+	force := flags.Bool("force", false, "覆盖已存在的文件")
+	logOptions := addLogFlags(flags)
+	flags.Parse(args)
+	logOptions.apply()
+
+	// 交互模式下逐项询问
+	if !*yes && isTerminal(os.Stdin) {
+		in := bufio.NewReader(os.Stdin)
+		*sourceDir = prompt(in, os.Stdout, "源文件目录", *sourceDir)
+		*outputDir = prompt(in, os.Stdout, "输出目录", *outputDir)
+		*formats = prompt(in, os.Stdout, "输出格式（json,php,fbs）", *formats)
+	}
+
+	formatList := make([]string, 0)
+	for _, format := range strings.Split(*formats, ",") {
+		if format = strings.TrimSpace(format); format != "" {
+			formatList = append(formatList, format)
+		}
+	}
+
+	created, err := InitProject(InitOptions{
+		Dir:       *dir,
+		SourceDir: *sourceDir,
+		OutputDir: *outputDir,
+		Formats:   formatList,
+		Force:     *force,
+	})
+	if err != nil {
+		logger.Errorf("初始化失败: %v", err)
+		os.Exit(1)
+	}
+
+	for _, path := range created {
+		logger.Infof("生成文件: %s", path)
+	}
+	logger.Infof("初始化完成，在 %s 目录下运行 builder 即可生成第一份输出", *dir)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestInitProjectBuilds 测试 init 生成的项目无需修改即可构建出输出
+func TestInitProjectBuilds(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	created, err := InitProject(InitOptions{
+		Dir:       ".",
+		SourceDir: "tables",
+		OutputDir: "build",
+		Formats:   []string{"json", "php"},
+	})
+	if err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	if len(created) != 5 {
+		t.Errorf("期望生成 5 个文件，实际为 %v", created)
+	}
+
+	builder := NewBuilder()
+	if err := builder.LoadConfig("conf"); err != nil {
+		t.Fatalf("加载生成的配置失败: %v", err)
+	}
+	if err := builder.BuildContext(context.Background()); err != nil {
+		t.Fatalf("构建生成的项目失败: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "build", "json", "items.json"))
+	if err != nil {
+		t.Fatalf("读取 JSON 输出失败: %v", err)
+	}
+	if !json.Valid(content) {
+		t.Errorf("JSON 输出无效: %s", content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build", "php", "items.php")); err != nil {
+		t.Errorf("缺少 PHP 输出: %v", err)
+	}
+
+	// 再次初始化时跳过已存在的文件
+	created, err = InitProject(InitOptions{Dir: ".", SourceDir: "tables", OutputDir: "build", Formats: []string{"json"}})
+	if err != nil || len(created) != 0 {
+		t.Errorf("期望跳过已存在的文件，实际生成 %v: %v", created, err)
+	}
+}
@@ -1,27 +1,84 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/game-data-builder/internal/analysis"
 	"github.com/game-data-builder/internal/config"
 	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/devpush"
+	"github.com/game-data-builder/internal/expr"
+	"github.com/game-data-builder/internal/freeze"
+	"github.com/game-data-builder/internal/lock"
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/metrics"
 	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/notify"
+	"github.com/game-data-builder/internal/output"
 	"github.com/game-data-builder/internal/reader"
+	"github.com/game-data-builder/internal/replace"
+	"github.com/game-data-builder/internal/report"
+	"github.com/game-data-builder/internal/scheduler"
+	"github.com/game-data-builder/internal/sink"
+	"github.com/game-data-builder/internal/transform"
 	"github.com/game-data-builder/internal/validator"
 )
 
+// Version 构建工具版本
+const Version = "1.0.0"
+
+// statsTop 耗时统计中每类列出的条目数
+const statsTop = 10
+
 // Builder 数据构建器
 type Builder struct {
+	confDir          string
+	locked           bool                  // 是否要求输入与 build.lock 完全一致
+	keepStaging      bool                  // 是否保留输出暂存目录，便于调试
+	allowErrors      bool                  // 预览构建：跳过验证失败的表，输出其余的表
+	failedSheets     []string              // 预览构建中验证失败而跳过的表
+	tags             []string              // 只构建带有这些标签的表，为空时构建全部表
+	only             []string              // 只构建这些表，为空时不限制
+	files            []string              // 只构建这些源文件中的表，为空时不限制
+	sheetFiles       map[string]string     // 表名 -> 读取该表的源文件
+	refSheets        []*model.DataSheet    // 按标签或指定表构建时未选中的表，只用于校验引用
+	prune            bool                  // 是否清理不再对应任何表的过期输出文件
+	pruneDryRun      bool                  // 只列出过期输出文件而不删除
+	syncDryRun       bool                  // 只比较将同步的文件与各目标目录的差异，不写入输出目标
+	buildTime        time.Time             // 本次构建的开始时间
+	changedFiles     []string              // 本次构建中内容有变化的输出文件
+	outputVersion    string                // 本次输出的版本：cas 结构为版本号，否则为源数据的 git 提交
+	pusher           *devpush.Pusher       // 开发模式下向运行中的游戏推送变更，为空时不推送
+	sheetVersions    *output.SheetVersions // 表版本，内容变化时自动加一
+	metrics          *metrics.Recorder     // 各阶段以及每个文件、表、转换器的耗时
+	progressOut      io.Writer             // 进度条输出目标，为空时不显示进度条
+	sampleValidation bool                  // 监听模式下对大表抽样验证
+	stats            bool                  // 构建报告中是否包含耗时统计
+	annotations      bool                  // 读取源文件中的批注和条件格式，供差异报告使用
+	ctx              context.Context       // 当前构建的上下文，取消后尽快停止并清理临时文件
 	configManager    *config.ConfigManager
 	readerFactory    *reader.ReaderFactory
 	converterFactory *converter.ConverterFactory
 	validator        *validator.DefaultValidator
+	enums            map[string]*model.EnumDef
+	combineErrors    []*model.ErrorInfo // 合并表的验证错误，在验证阶段与其他错误一起报告
+	degraded         []degradation      // 本次构建中不可用的可选子系统
+	report           *report.Report
 }
 
 // NewBuilder 创建数据构建器
@@ -31,466 +88,1936 @@ func NewBuilder() *Builder {
 		readerFactory:    reader.NewReaderFactory(),
 		converterFactory: converter.NewConverterFactory(),
 		validator:        validator.NewDefaultValidator(),
+		report:           report.NewReport(),
 	}
 }
 
 // LoadConfig 加载配置
 func (b *Builder) LoadConfig(confDir string) error {
+	b.confDir = confDir
 	return b.configManager.Load(confDir)
 }
 
-// Build 执行构建过程
+// Build 执行构建过程，完成后发送构建通知
 func (b *Builder) Build() error {
-	startTime := time.Now()
+	return b.BuildContext(context.Background())
+}
 
-	// 1. 读取源文件
-	sheets, err := b.readSourceFiles()
-	if err != nil {
-		return fmt.Errorf("读取源文件失败: %v", err)
+// BuildContext 执行可取消的构建过程，ctx 取消后终止进行中的读取、转换和外部命令，回滚未提交的输出
+func (b *Builder) BuildContext(ctx context.Context) error {
+	b.ctx = ctx
+	defer func() { b.ctx = nil }()
+	b.changedFiles = nil
+	b.outputVersion = ""
+	b.failedSheets = nil
+	b.refSheets = nil
+	b.metrics = metrics.NewRecorder()
+	b.degraded = nil
+	err := b.build()
+	b.notify(err)
+	b.writeStatus(err)
+	if err == nil {
+		b.logDegraded()
 	}
+	return err
+}
 
-	// 2. 验证数据
-	errors := b.validateData(sheets)
-	if len(errors) > 0 {
-		// 打印验证错误
-		for _, err := range errors {
-			fmt.Printf("[ERROR] %s:%s[%d]: %s\n", err.Sheet, err.Column, err.Row, err.Msg)
-		}
-		return fmt.Errorf("数据验证失败，共 %d 个错误", len(errors))
+// notify 向配置的 Webhook 发送构建摘要，发送失败时记录为降级构建
+func (b *Builder) notify(buildErr error) {
+	webhooks := b.configManager.Config.Webhooks
+	if len(webhooks) == 0 {
+		return
 	}
 
-	// 3. 转换数据
-	results, err := b.convertData(sheets)
-	if err != nil {
-		return fmt.Errorf("转换数据失败: %v", err)
+	summary := notify.NewSummary(buildErr, time.Since(b.buildTime), b.changedFiles)
+	summary.Degraded = b.Degraded()
+	for _, err := range notify.Send(webhooks, summary) {
+		b.degrade(subsystemNotify, "", err)
 	}
+}
 
-	// 4. 输出处理
-	if err := b.outputResults(results); err != nil {
-		return fmt.Errorf("输出处理失败: %v", err)
-	}
+// build 依次执行构建的各个阶段
+func (b *Builder) build() error {
+	startTime := time.Now()
+	b.buildTime = startTime
 
-	// 5. 同步更新
-	if b.configManager.Config.SyncToGame {
-		if err := b.syncToGame(results); err != nil {
-			return fmt.Errorf("同步到游戏目录失败: %v", err)
+	// 0. 锁定模式下校验输入
+	b.metrics.StartStage("校验锁文件")
+	if b.locked {
+		if err := b.verifyLock(); err != nil {
+			return err
 		}
 	}
 
-	// 6. 打印构建信息
-	fmt.Printf("构建完成，耗时 %v，共处理 %d 个表，生成 %d 个文件\n",
-		time.Since(startTime), len(sheets), len(results))
-
-	return nil
-}
-
-// readSourceFiles 读取源文件
-func (b *Builder) readSourceFiles() ([]*model.DataSheet, error) {
-	allSheets := make([]*model.DataSheet, 0)
+	// 1. 读取源文件
+	b.metrics.StartStage("读取")
+	sheets, err := b.readSourceFiles()
+	if err != nil {
+		return fmt.Errorf("读取源文件失败: %w", err)
+	}
+	if err := b.buildContext().Err(); err != nil {
+		return fmt.Errorf("构建已取消: %w", err)
+	}
 
-	// 遍历源文件目录
-	err := filepath.WalkDir(b.configManager.Config.SourceDir, func(path string, d fs.DirEntry, err error) error {
+	// 按标签以及指定的表和文件筛选要构建的表
+	if b.selective() {
+		sheets, err = b.selectSheets(sheets)
 		if err != nil {
 			return err
 		}
+	}
 
-		if d.IsDir() {
-			return nil
-		}
+	// 2. 检查冻结表
+	b.metrics.StartStage("检查冻结表")
+	if err := b.checkFrozen(sheets); err != nil {
+		return err
+	}
 
-		// 检查文件扩展名
-		reader := b.readerFactory.GetReader(path)
-		if reader == nil {
-			return nil // 跳过不支持的文件
+	// 3. 验证数据
+	b.metrics.StartStage("验证")
+	validationErrors := b.validateData(sheets)
+	if len(validationErrors) > 0 {
+		// 打印验证错误
+		for _, err := range validationErrors {
+			logger.WithFields(logger.Fields{"sheet": err.Sheet, "column": err.Column, "row": err.Row}).
+				Errorf("%s:%s[%d]: %s", err.Sheet, err.Column, err.Row, err.Msg)
 		}
-
-		// 快速模式：检查文件是否修改
-		if b.configManager.Config.FastMode {
-			if !b.needProcess(path) {
-				fmt.Printf("跳过未修改文件: %s\n", path)
-				return nil
-			}
+		if !b.allowErrors {
+			return &model.ValidationError{Errors: validationErrors}
 		}
 
-		// 创建并初始化读取器
-		r, err := b.readerFactory.CreateReader(path, b.configManager.Config.Readers["default"].Options)
-		if err != nil {
-			return err
-		}
+		// 预览构建：跳过验证失败的表，继续输出其余的表
+		sheets = b.dropFailedSheets(sheets, validationErrors)
+	}
 
-		// 读取文件
-		fmt.Printf("读取文件: %s\n", path)
-		sheets, err := r.ReadAll(path)
-		if err != nil {
-			return fmt.Errorf("读取 %s 失败: %v", path, err)
-		}
+	// 4. 转换前处理
+	b.metrics.StartStage("转换前处理")
+	if err := b.transformData(sheets); err != nil {
+		return fmt.Errorf("转换前处理失败: %v", err)
+	}
 
-		allSheets = append(allSheets, sheets...)
-		return nil
-	})
+	// 5. 分析数据
+	b.metrics.StartStage("分析")
+	if err := b.analyzeData(sheets); err != nil {
+		return fmt.Errorf("分析数据失败: %v", err)
+	}
 
-	if err != nil {
-		return nil, err
+	// 6. 更新表版本
+	b.metrics.StartStage("更新表版本")
+	if err := b.assignVersions(sheets); err != nil {
+		return fmt.Errorf("更新表版本失败: %v", err)
 	}
 
-	// 应用合并配置
-	allSheets = b.applyCombineConfig(allSheets)
+	// 7. 转换数据
+	b.metrics.StartStage("转换")
+	results, err := b.convertData(sheets)
+	if err != nil {
+		// 逐个打印转换错误，便于一次修复所有的表
+		var convertErrs *model.ConvertErrors
+		if errors.As(err, &convertErrs) && len(convertErrs.Errors) > 1 {
+			for _, convertErr := range convertErrs.Errors {
+				logger.WithFields(logger.Fields{"sheet": convertErr.Sheet, "format": convertErr.Format}).Errorf("%v", convertErr)
+			}
+		}
+		return fmt.Errorf("转换数据失败: %w", err)
+	}
 
-	// 应用列替换配置
-	allSheets = b.applyReplaceConfig(allSheets)
+	// 生成相对上次构建的 JSON Patch
+	results = append(results, b.jsonPatches(results)...)
 
-	return allSheets, nil
-}
+	// 8. 包体预估
+	b.metrics.StartStage("包体预估")
+	if err := b.forecastBundle(sheets, results); err != nil {
+		return fmt.Errorf("包体预估失败: %v", err)
+	}
 
-// needProcess 检查文件是否需要处理
-func (b *Builder) needProcess(filePath string) bool {
-	// 获取文件修改时间
-	fileInfo, err := os.Stat(filePath)
+	// 9. 压缩打包
+	b.metrics.StartStage("压缩打包")
+	results, err = b.packageResults(results)
 	if err != nil {
-		return true
+		return fmt.Errorf("压缩打包失败: %v", err)
 	}
 
-	fileModTime := fileInfo.ModTime()
+	// 10. 输出处理
+	b.metrics.StartStage("写入")
+	if err := b.buildContext().Err(); err != nil {
+		return fmt.Errorf("构建已取消: %w", err)
+	}
+	files := b.outputFiles(results)
+	if err := b.outputResults(files); err != nil {
+		return fmt.Errorf("输出处理失败: %w", err)
+	}
 
-	// 检查输出文件是否存在且修改时间晚于源文件
-	for _, format := range b.configManager.Config.Formats {
-		convConfig := b.configManager.GetConverterConfig(format)
-		if convConfig == nil || !convConfig.Enabled {
-			continue
-		}
+	// 11. 同步到游戏目录、远程服务器、对象存储等其他输出目标
+	b.metrics.StartStage("同步")
+	if err := b.syncSinks(files); err != nil {
+		return fmt.Errorf("同步失败: %w", err)
+	}
 
-		// 构建输出路径
-		outputDir := b.configManager.Config.OutputDir
-		if convConfig.OutputPath != "" {
-			outputDir = filepath.Join(outputDir, convConfig.OutputPath)
-		}
+	// 12. 推送变更到调试端
+	b.metrics.StartStage("推送")
+	b.pushResults(results)
 
-		// 构建输出文件名
-		fileName := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
-		var outputFileName string
-		switch format {
-		case "json":
-			outputFileName = fmt.Sprintf("%s.json", fileName)
-		case "php":
-			outputFileName = fmt.Sprintf("%s.php", fileName)
-		case "fbs":
-			outputFileName = fmt.Sprintf("%s.bin", fileName)
-		default:
-			continue
+	// 13. 更新锁文件和表版本
+	b.metrics.StartStage("更新锁文件")
+	if !b.locked {
+		if err := b.writeLock(); err != nil {
+			return fmt.Errorf("写入锁文件失败: %v", err)
 		}
-
-		outputPath := filepath.Join(outputDir, outputFileName)
-
-		// 检查输出文件是否存在
-		outputInfo, err := os.Stat(outputPath)
-		if err != nil {
-			return true // 输出文件不存在，需要处理
+	}
+	if b.sheetVersions.Changed() {
+		if err := b.writeState(output.SheetVersionsFileName, b.sheetVersions.Save); err != nil {
+			return fmt.Errorf("保存表版本失败: %v", err)
 		}
+	}
 
-		if outputInfo.ModTime().Before(fileModTime) {
-			return true // 输出文件早于源文件，需要处理
-		}
+	// 14. 打印构建报告
+	b.metrics.EndStage()
+	if b.stats {
+		b.metrics.AddToReport(b.report, statsTop)
 	}
+	b.report.Log()
+	logger.WithFields(logger.Fields{"sheets": len(sheets), "files": len(results)}).
+		Infof("构建完成，耗时 %v，共处理 %d 个表，生成 %d 个文件", time.Since(startTime), len(sheets), len(results))
 
-	return false // 所有输出文件都存在且最新，不需要处理
+	return nil
 }
 
-// applyCombineConfig 应用合并配置
-func (b *Builder) applyCombineConfig(sheets []*model.DataSheet) []*model.DataSheet {
-	if b.configManager.CombineConfig == nil {
-		return sheets
+// selective 是否只构建部分表
+func (b *Builder) selective() bool {
+	return len(b.tags) > 0 || len(b.only) > 0 || len(b.files) > 0
+}
+
+// selectSheets 选出需要构建的表：带有 -tags 中的标签，并且是 -only 指定的表或 -file 指定的文件中的表；
+// 其余的表仍然读取和预处理，只用于校验引用
+func (b *Builder) selectSheets(sheets []*model.DataSheet) ([]*model.DataSheet, error) {
+	selected := sheets
+	if len(b.tags) > 0 {
+		selected = b.selectTags(selected)
+	}
+	if len(b.only) > 0 || len(b.files) > 0 {
+		targets, err := b.targetSheets(sheets)
+		if err != nil {
+			return nil, err
+		}
+		kept := make([]*model.DataSheet, 0, len(targets))
+		for _, sheet := range selected {
+			if targets[sheet.Name] {
+				kept = append(kept, sheet)
+			}
+		}
+		selected = kept
+		logger.Infof("按指定的表和文件构建 %d/%d 个表", len(selected), len(sheets))
 	}
 
-	// 构建表名到表的映射
-	sheetMap := make(map[string]*model.DataSheet)
+	names := make(map[string]bool, len(selected))
+	for _, sheet := range selected {
+		names[sheet.Name] = true
+	}
+	b.refSheets = make([]*model.DataSheet, 0, len(sheets)-len(selected))
 	for _, sheet := range sheets {
-		sheetMap[sheet.Name] = sheet
+		if !names[sheet.Name] {
+			b.refSheets = append(b.refSheets, sheet)
+		}
 	}
+	return selected, nil
+}
 
-	// 处理合并表
-	combinedSheets := make([]*model.DataSheet, 0)
-	processedSheets := make(map[string]bool)
-
-	// 先添加非合并表
+// targetSheets -only 和 -file 指定的表名，指定的表不存在或文件中没有读取到表时报错
+func (b *Builder) targetSheets(sheets []*model.DataSheet) (map[string]bool, error) {
+	exists := make(map[string]bool, len(sheets))
 	for _, sheet := range sheets {
-		processedSheets[sheet.Name] = false
+		exists[sheet.Name] = true
 	}
 
-	// 处理合并配置
-	for _, combineSheet := range b.configManager.CombineConfig.Sheets {
-		// 检查所有源表是否存在
-		allExists := true
-		for _, sourceSheetName := range combineSheet.SourceSheets {
-			if _, exists := sheetMap[sourceSheetName]; !exists {
-				allExists = false
-				break
-			}
-		}
-
-		if !allExists {
-			continue
+	targets := make(map[string]bool)
+	for _, name := range b.only {
+		if !exists[name] {
+			return nil, fmt.Errorf("-only 指定的表 %s 不存在", name)
 		}
+		targets[name] = true
+	}
 
-		// 创建合并表
-		combinedSheet := &model.DataSheet{
-			Name:    combineSheet.OutputName,
-			Columns: []model.ColumnInfo{},
-			Rows:    []map[string]interface{}{},
-			Meta:    make(map[string]interface{}),
+	for _, file := range b.files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
 		}
-
-		// 合并列信息（使用第一个表的列）
-		if len(combineSheet.SourceSheets) > 0 {
-			firstSheet := sheetMap[combineSheet.SourceSheets[0]]
-			combinedSheet.Columns = firstSheet.Columns
+		found := false
+		for name, source := range b.sheetFiles {
+			if sourcePath, err := filepath.Abs(source); err == nil && sourcePath == path && exists[name] {
+				targets[name] = true
+				found = true
+			}
 		}
-
-		// 合并行数据
-		for _, sourceSheetName := range combineSheet.SourceSheets {
-			sourceSheet := sheetMap[sourceSheetName]
-			combinedSheet.Rows = append(combinedSheet.Rows, sourceSheet.Rows...)
-			processedSheets[sourceSheetName] = true
+		if !found {
+			return nil, fmt.Errorf("-file 指定的文件 %s 不在源文件目录中或没有读取到表", file)
 		}
-
-		combinedSheets = append(combinedSheets, combinedSheet)
 	}
+	return targets, nil
+}
 
-	// 添加未合并的表
-	for _, sheet := range sheets {
-		if !processedSheets[sheet.Name] {
-			combinedSheets = append(combinedSheets, sheet)
+// selectTags 选出带有指定标签的表，各标签包含的表记录到构建报告
+func (b *Builder) selectTags(sheets []*model.DataSheet) []*model.DataSheet {
+	selected := reader.SelectTags(sheets, b.tags)
+
+	section := b.report.Section("标签")
+	for _, tag := range b.tags {
+		names := make([]string, 0)
+		for _, sheet := range reader.SelectTags(selected, []string{tag}) {
+			names = append(names, sheet.Name)
+		}
+		if len(names) == 0 {
+			logger.Warnf("没有表带有标签 %s", tag)
+			section.Addf("%s: 没有表", tag)
+			continue
 		}
+		section.Addf("%s: %s", tag, strings.Join(names, ", "))
 	}
-
-	return combinedSheets
+	logger.Infof("按标签 %s 构建 %d/%d 个表", strings.Join(b.tags, ","), len(selected), len(sheets))
+	return selected
 }
 
-// applyReplaceConfig 应用列替换配置
-func (b *Builder) applyReplaceConfig(sheets []*model.DataSheet) []*model.DataSheet {
-	if b.configManager.ReplaceConfig == nil {
-		return sheets
+// dropFailedSheets 移除存在验证错误的表，并记录到构建报告
+func (b *Builder) dropFailedSheets(sheets []*model.DataSheet, validationErrors []*model.ErrorInfo) []*model.DataSheet {
+	failed := make(map[string]int)
+	for _, err := range validationErrors {
+		failed[err.Sheet]++
 	}
 
-	// 遍历每个表
+	section := b.report.Section("预览构建跳过的表")
+	kept := make([]*model.DataSheet, 0, len(sheets))
 	for _, sheet := range sheets {
-		// 检查是否有替换规则
-		replaceRules, exists := b.configManager.ReplaceConfig.Sheets[sheet.Name]
-		if !exists {
+		if count, exists := failed[sheet.Name]; exists {
+			b.failedSheets = append(b.failedSheets, sheet.Name)
+			section.Addf("%s: %d 个错误，保留上一次的输出", sheet.Name, count)
 			continue
 		}
+		kept = append(kept, sheet)
+	}
+	return kept
+}
 
-		// 遍历每行数据
-		for _, row := range sheet.Rows {
-			// 遍历每个替换规则
-			for columnName, rule := range replaceRules.Columns {
-				// 检查列是否存在
-				if val, exists := row[columnName]; exists {
-					// 替换值
-					if strVal, ok := val.(string); ok {
-						strVal = strings.ReplaceAll(strVal, rule.From, rule.To)
-						row[columnName] = strVal
-					}
-				}
+// buildLock 根据当前输入生成锁文件内容
+func (b *Builder) buildLock() (*lock.LockFile, error) {
+	lockFile := lock.NewLockFile()
+
+	// 额外源文件目录中的文件以目录路径为前缀记录
+	for i, root := range b.configManager.Config.AllSourceRoots() {
+		sources, err := lock.HashFiles(root.Dir, func(path string) bool {
+			return b.sourceFileMatch(root.Dir, path)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for relPath, hash := range sources {
+			if i > 0 {
+				relPath = filepath.ToSlash(filepath.Join(root.Dir, relPath))
 			}
+			lockFile.Sources[relPath] = hash
 		}
 	}
 
-	return sheets
-}
+	configs, err := configHashes(b.confDir)
+	if err != nil {
+		return nil, err
+	}
+	lockFile.Configs = configs
 
-// validateData 验证数据
-func (b *Builder) validateData(sheets []*model.DataSheet) []*model.ErrorInfo {
-	return b.validator.ValidateAll(sheets)
+	lockFile.Tools["builder"] = Version
+	lockFile.Tools["go"] = runtime.Version()
+	if flatcVersion := lock.ToolVersion("flatc", "--version"); flatcVersion != "" {
+		lockFile.Tools["flatc"] = flatcVersion
+	}
+
+	return lockFile, nil
 }
 
-// convertData 转换数据
-func (b *Builder) convertData(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
-	results := make([]*model.ConvertResult, 0)
+// verifyLock 校验当前输入与锁文件一致
+func (b *Builder) verifyLock() error {
+	lockedFile, err := lock.Load(b.stateReadPath(lock.FileName))
+	if err != nil {
+		return fmt.Errorf("读取锁文件失败: %v", err)
+	}
 
-	// 异步处理
-	if b.configManager.Config.Async {
-		return b.asyncConvertData(sheets)
+	current, err := b.buildLock()
+	if err != nil {
+		return fmt.Errorf("计算输入哈希失败: %v", err)
 	}
 
-	// 同步处理
-	// 遍历每个格式
-	for _, format := range b.configManager.Config.Formats {
-		convConfig := b.configManager.GetConverterConfig(format)
-		if convConfig == nil || !convConfig.Enabled {
-			continue
+	diffs := lockedFile.Diff(current)
+	if len(diffs) > 0 {
+		for _, diff := range diffs {
+			logger.Errorf("%s: %s", lock.FileName, diff)
 		}
+		return fmt.Errorf("构建输入与 %s 不一致，共 %d 处差异", lock.FileName, len(diffs))
+	}
 
-		// 创建并初始化转换器
-		conv, err := b.converterFactory.CreateConverter(format, convConfig.Options)
-		if err != nil {
-			return nil, err
+	return nil
+}
+
+// writeLock 写入锁文件
+func (b *Builder) writeLock() error {
+	lockFile, err := b.buildLock()
+	if err != nil {
+		return err
+	}
+	return b.writeState(lock.FileName, lockFile.Save)
+}
+
+// checkFrozen 检查冻结表是否有未经批准的内容变化，只构建部分表时不检查未选中的冻结表
+func (b *Builder) checkFrozen(sheets []*model.DataSheet) error {
+	frozenConfig := b.configManager.FrozenConfig
+	checked := frozenConfig
+	if b.selective() {
+		selected := *frozenConfig
+		selected.Sheets = make(map[string]config.FrozenSheet)
+		for _, sheet := range sheets {
+			if frozenSheet, exists := frozenConfig.Sheets[sheet.Name]; exists {
+				selected.Sheets[sheet.Name] = frozenSheet
+			}
 		}
+		checked = &selected
+	}
+	violations, err := freeze.Check(sheets, checked)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
 
-		// 转换数据
-		fmt.Printf("转换为 %s 格式\n", format)
-		convResults, err := conv.BatchConvert(sheets)
-		if err != nil {
-			return nil, err
+	if frozenConfig.Mode == freeze.ModeWarn {
+		section := b.report.Section("冻结表变化")
+		for _, violation := range violations {
+			logger.Warnf("%v", violation)
+			section.Addf("%v", violation)
 		}
+		return nil
+	}
 
-		results = append(results, convResults...)
+	for _, violation := range violations {
+		logger.Errorf("%v", violation)
 	}
+	return fmt.Errorf("%d 个冻结表的内容发生了未经批准的变化", len(violations))
+}
 
-	return results, nil
+// readSourceFiles 读取源文件
+func (b *Builder) readSourceFiles() ([]*model.DataSheet, error) {
+	allSheets, err := b.readRawSheets()
+	if err != nil {
+		return nil, err
+	}
+	return b.prepareSheets(allSheets)
 }
 
-// asyncConvertData 异步转换数据
-func (b *Builder) asyncConvertData(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
-	results := make([]*model.ConvertResult, 0)
-	resultChan := make(chan []*model.ConvertResult, len(b.configManager.Config.Formats))
-	errChan := make(chan error, len(b.configManager.Config.Formats))
+// readRawSheets 读取源文件目录下的所有原始数据表
+func (b *Builder) readRawSheets() ([]*model.DataSheet, error) {
+	roots := b.configManager.Config.AllSourceRoots()
 
-	// 遍历每个格式
-	for _, format := range b.configManager.Config.Formats {
-		go func(f string) {
-			convConfig := b.configManager.GetConverterConfig(f)
-			if convConfig == nil || !convConfig.Enabled {
-				resultChan <- nil
-				errChan <- nil
-				return
+	// 遍历所有源文件目录，先收集需要读取的文件以便显示进度
+	files := make([]sourceFile, 0)
+	for i, root := range roots {
+		err := filepath.WalkDir(root.Dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return &model.ReadError{File: path, Err: err}
 			}
 
-			// 创建并初始化转换器
-			conv, err := b.converterFactory.CreateConverter(f, convConfig.Options)
-			if err != nil {
-				resultChan <- nil
-				errChan <- err
-				return
+			if d.IsDir() {
+				return nil
 			}
 
-			// 转换数据
-			fmt.Printf("异步转换为 %s 格式\n", f)
-			convResults, err := conv.BatchConvert(sheets)
-			resultChan <- convResults
-			errChan <- err
-		}(format)
-	}
+			// 检查文件扩展名和文件过滤规则
+			if !b.sourceFileMatch(root.Dir, path) {
+				return nil // 跳过不支持或被过滤的文件
+			}
+
+			// 快速模式：检查文件是否修改
+			if b.configManager.Config.FastMode {
+				if !b.needProcess(path, root.Namespace) {
+					logger.Debugf("跳过未修改文件: %s", path)
+					return nil
+				}
+			}
 
-	// 收集结果
-	for i := 0; i < len(b.configManager.Config.Formats); i++ {
-		convResults := <-resultChan
-		err := <-errChan
+			files = append(files, sourceFile{path: path, root: i})
+			return nil
+		})
 		if err != nil {
 			return nil, err
 		}
-		if convResults != nil {
-			results = append(results, convResults...)
-		}
 	}
 
-	return results, nil
-}
-
-// outputResults 输出结果
-func (b *Builder) outputResults(results []*model.ConvertResult) error {
-	// 遍历每个转换结果
-	for _, result := range results {
-		// 获取转换器配置
-		convConfig := b.configManager.GetConverterConfig(result.Format)
-		if convConfig == nil {
-			continue
+	rootSheets := make([][]*model.DataSheet, len(roots))
+	sheetFiles := make(map[*model.DataSheet]string)
+	progress := b.newProgress("读取文件", len(files))
+	defer progress.Finish()
+	for _, file := range files {
+		if err := b.buildContext().Err(); err != nil {
+			return nil, err
 		}
 
-		// 构建输出路径
-		outputDir := b.configManager.Config.OutputDir
-		if convConfig.OutputPath != "" {
-			outputDir = filepath.Join(outputDir, convConfig.OutputPath)
-		}
+		// 读取文件
+		logger.Infof("读取文件: %s", file.path)
+		start := time.Now()
+		sheets, err := b.readFile(file.path)
+		b.recordTiming(metrics.KindFile, file.path, start)
+		progress.Add(1)
+		if err != nil {
+			if !b.allowErrors {
+				return nil, err
+			}
 
-		// 创建输出目录
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return fmt.Errorf("创建输出目录失败: %v", err)
+			// 预览构建：跳过读取失败的文件，以文件名记录
+			logger.Errorf("%v", err)
+			name := model.QualifyName(roots[file.root].Namespace, strings.TrimSuffix(filepath.Base(file.path), filepath.Ext(file.path)))
+			b.failedSheets = append(b.failedSheets, name)
+			b.report.Section("预览构建跳过的表").Addf("%s: 读取失败，保留上一次的输出", name)
+			continue
 		}
 
-		// 构建输出文件路径
-		outputPath := filepath.Join(outputDir, result.FileName)
-
-		// 写入文件
-		if err := os.WriteFile(outputPath, result.Content, 0644); err != nil {
-			return fmt.Errorf("写入文件失败: %v", err)
+		rootSheets[file.root] = append(rootSheets[file.root], sheets...)
+		for _, sheet := range sheets {
+			sheetFiles[sheet] = file.path
 		}
+	}
 
-		fmt.Printf("生成文件: %s\n", outputPath)
+	// 按源文件目录加上命名空间前缀，不同目录中的表不能重名
+	allSheets := make([]*model.DataSheet, 0)
+	owners := make(map[string]string)
+	b.sheetFiles = make(map[string]string, len(sheetFiles))
+	for i, sheets := range rootSheets {
+		model.ApplyNamespace(sheets, roots[i].Namespace)
+		for _, sheet := range sheets {
+			b.sheetFiles[sheet.Name] = sheetFiles[sheet]
+			if owner, exists := owners[sheet.Name]; exists && owner != roots[i].Dir {
+				return nil, &model.ReadError{File: roots[i].Dir, Sheet: sheet.Name, Err: fmt.Errorf("与 %s 中的表重名，请为源文件目录配置 namespace", owner)}
+			}
+			owners[sheet.Name] = roots[i].Dir
+		}
+		allSheets = append(allSheets, sheets...)
 	}
 
-	return nil
-}
+	imported, err := b.readImports(allSheets)
+	if err != nil {
+		return nil, err
+	}
+	allSheets = append(allSheets, imported...)
 
-// syncToGame 同步到游戏目录
-func (b *Builder) syncToGame(results []*model.ConvertResult) error {
-	if b.configManager.Config.GameDir == "" {
-		return nil
+	urlSheets, err := b.readURLSources(allSheets)
+	if err != nil {
+		return nil, err
 	}
+	allSheets = append(allSheets, urlSheets...)
 
-	// 遍历每个转换结果
+	databaseSheets, err := b.readDatabases(allSheets)
+	if err != nil {
+		return nil, err
+	}
+	allSheets = append(allSheets, databaseSheets...)
+
+	// constants.json 中定义的常量表与源文件中的表一样参与后续处理
+	constants, err := reader.ConstantsSheet(b.configManager.Constants)
+	if err != nil {
+		return nil, &model.ReadError{File: "constants.json", Err: err}
+	}
+	if constants != nil {
+		for _, sheet := range allSheets {
+			if sheet.Name == constants.Name {
+				return nil, &model.ReadError{File: "constants.json", Sheet: constants.Name, Err: fmt.Errorf("与已有的表重名")}
+			}
+		}
+		allSheets = append(allSheets, constants)
+	}
+
+	return allSheets, nil
+}
+
+// sourceFile 待读取的源文件
+type sourceFile struct {
+	path string
+	root int // 所属源文件目录在 AllSourceRoots 中的下标
+}
+
+// buildContext 当前构建的上下文，不在构建中时（如 serve 检查上传）返回不会取消的上下文
+func (b *Builder) buildContext() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
+// newProgress 创建阶段进度条，未开启进度条时不输出
+func (b *Builder) newProgress(label string, total int) *metrics.Progress {
+	return metrics.NewProgress(b.progressOut, label, total)
+}
+
+// recordTiming 记录从 start 开始的耗时；单独读取文件（如 serve 检查上传）时不记录
+func (b *Builder) recordTiming(kind, name string, start time.Time) {
+	if b.metrics != nil {
+		b.metrics.Add(kind, name, time.Since(start))
+	}
+}
+
+// readImports 读取从其他项目导入的共享表，导入的表不能与本项目的表同名
+func (b *Builder) readImports(local []*model.DataSheet) ([]*model.DataSheet, error) {
+	names := make(map[string]bool, len(local))
+	for _, sheet := range local {
+		names[sheet.Name] = true
+	}
+
+	imported := make([]*model.DataSheet, 0)
+	for _, imp := range b.configManager.Config.Imports {
+		source, err := reader.NewImportSource(imp.From, b.withStateCache(imp.Options, "cacheDir", stateSourcesDir))
+		if err != nil {
+			return nil, &model.ReadError{File: imp.From, Err: err}
+		}
+
+		logger.Infof("导入共享表: %s", imp.From)
+		sheets, err := source.ReadSheetsContext(b.buildContext(), imp.Sheets)
+		if err != nil {
+			return nil, &model.ReadError{File: imp.From, Err: err}
+		}
+		for _, cached := range source.CachedSources() {
+			b.degrade(subsystemCache, cached, fmt.Errorf("数据源不可用，使用了上次成功获取的缓存数据"))
+		}
+		model.ApplyNamespace(sheets, imp.Namespace)
+		for _, sheet := range sheets {
+			if names[sheet.Name] {
+				return nil, &model.ReadError{File: imp.From, Sheet: sheet.Name, Err: fmt.Errorf("与已有的表重名")}
+			}
+			names[sheet.Name] = true
+		}
+		imported = append(imported, sheets...)
+	}
+	return imported, nil
+}
+
+// readURLSources 下载 sources.json 中配置的远程源文件并按文件类型读取，不能与已有的表重名
+func (b *Builder) readURLSources(existing []*model.DataSheet) ([]*model.DataSheet, error) {
+	sources := b.configManager.Sources
+	if sources == nil || len(sources.Sources) == 0 {
+		return nil, nil
+	}
+
+	fetcher, err := reader.NewURLSourceFetcher(b.withStateCache(sources.Options, "cacheDir", stateSourcesDir))
+	if err != nil {
+		return nil, &model.ReadError{File: "sources.json", Err: err}
+	}
+
+	names := make(map[string]bool, len(existing))
+	for _, sheet := range existing {
+		names[sheet.Name] = true
+	}
+
+	sheets := make([]*model.DataSheet, 0)
+	for _, source := range sources.Sources {
+		if err := b.buildContext().Err(); err != nil {
+			return nil, err
+		}
+
+		logger.Infof("下载源文件: %s", source.URL)
+		start := time.Now()
+		path, err := fetcher.FetchContext(b.buildContext(), source)
+		if err != nil {
+			return nil, &model.ReadError{File: source.URL, Err: err}
+		}
+		read, err := b.readFile(path)
+		b.recordTiming(metrics.KindFile, source.URL, start)
+		if err != nil {
+			// 错误信息中使用地址而不是缓存中的文件路径
+			var readErr *model.ReadError
+			if errors.As(err, &readErr) {
+				readErr.File = source.URL
+			}
+			return nil, err
+		}
+
+		model.ApplyNamespace(read, source.Namespace)
+		for _, sheet := range read {
+			if names[sheet.Name] {
+				return nil, &model.ReadError{File: source.URL, Sheet: sheet.Name, Err: fmt.Errorf("与已有的表重名")}
+			}
+			names[sheet.Name] = true
+		}
+		sheets = append(sheets, read...)
+	}
+	for _, cached := range fetcher.CachedSources() {
+		b.degrade(subsystemCache, cached, fmt.Errorf("源文件地址不可用，使用了上次下载的缓存"))
+	}
+	return sheets, nil
+}
+
+// readDatabases 读取 readers 中 type 为 db 的数据库读取器配置的表，按配置名称的顺序读取，不能与已有的表重名
+func (b *Builder) readDatabases(existing []*model.DataSheet) ([]*model.DataSheet, error) {
+	readers := b.configManager.Config.Readers
+	names := make([]string, 0)
+	for name, readerConfig := range readers {
+		if readerConfig.Type == config.ReaderDB && readerConfig.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	sheetNames := make(map[string]bool, len(existing))
+	for _, sheet := range existing {
+		sheetNames[sheet.Name] = true
+	}
+
+	sheets := make([]*model.DataSheet, 0)
+	for _, name := range names {
+		source := "db:" + name
+		dbReader := reader.NewDBReader()
+		if err := dbReader.Init(b.withStateCache(readers[name].Options, "cacheDir", stateSourcesDir)); err != nil {
+			return nil, &model.ReadError{File: source, Err: err}
+		}
+
+		logger.Infof("读取数据库: %s", name)
+		start := time.Now()
+		read, err := dbReader.ReadAllContext(b.buildContext(), name)
+		b.recordTiming(metrics.KindFile, source, start)
+		if err != nil {
+			return nil, &model.ReadError{File: source, Err: err}
+		}
+		for _, cached := range dbReader.CachedSources() {
+			b.degrade(subsystemCache, cached, fmt.Errorf("数据库不可用，使用了上次成功读取的缓存数据"))
+		}
+		for _, sheet := range read {
+			if sheetNames[sheet.Name] {
+				return nil, &model.ReadError{File: source, Sheet: sheet.Name, Err: fmt.Errorf("与已有的表重名")}
+			}
+			sheetNames[sheet.Name] = true
+		}
+		sheets = append(sheets, read...)
+	}
+	return sheets, nil
+}
+
+// sourceFileMatch 源文件目录中的文件是否需要读取：有对应的读取器且符合 include/exclude 规则
+func (b *Builder) sourceFileMatch(rootDir, path string) bool {
+	if b.readerFactory.GetReader(path) == nil {
+		return false
+	}
+	relPath, err := filepath.Rel(rootDir, path)
+	if err != nil {
+		relPath = path
+	}
+	return b.configManager.Config.MatchSourceFile(relPath)
+}
+
+// sourceRelPath 文件相对所在源文件目录的路径，不在任何源文件目录中（如远程源文件的缓存）时为文件名
+func (b *Builder) sourceRelPath(path string) string {
+	for _, root := range b.configManager.Config.AllSourceRoots() {
+		if relPath, err := filepath.Rel(root.Dir, path); err == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return relPath
+		}
+	}
+	return filepath.Base(path)
+}
+
+// readFile 使用对应的读取器读取单个文件，跳过名称匹配 excludeSheets 的表
+func (b *Builder) readFile(path string) ([]*model.DataSheet, error) {
+	// 创建并初始化读取器
+	options := b.configManager.ReaderOptions(b.sourceRelPath(path))
+	if b.annotations {
+		options["readAnnotations"] = true
+	}
+	r, err := b.readerFactory.CreateReader(path, options)
+	if err != nil {
+		return nil, &model.ReadError{File: path, Err: err}
+	}
+	if r == nil {
+		return nil, &model.ReadError{File: path, Err: fmt.Errorf("不支持的文件类型: %s", filepath.Ext(path))}
+	}
+
+	sheets, err := reader.ReadAllContext(b.buildContext(), r, path)
+	if err != nil {
+		return nil, &model.ReadError{File: path, Err: err}
+	}
+
+	kept := sheets[:0]
+	for _, sheet := range sheets {
+		if !b.configManager.Config.MatchSheet(sheet.Name) {
+			logger.Debugf("跳过表: %s", sheet.Name)
+			continue
+		}
+		kept = append(kept, sheet)
+	}
+	return kept, nil
+}
+
+// prepareSheets 对读取的原始数据表进行枚举、模板、合并和列替换处理
+func (b *Builder) prepareSheets(allSheets []*model.DataSheet) ([]*model.DataSheet, error) {
+	var err error
+
+	// 提取枚举定义
+	allSheets, b.enums, err = reader.ExtractEnums(allSheets)
+	if err != nil {
+		return nil, err
+	}
+
+	// 引入共享列组，模板表也可以引入列组
+	if err := reader.ResolveColumnGroups(allSheets, b.configManager.ColumnGroups); err != nil {
+		return nil, err
+	}
+
+	// 处理模板继承
+	allSheets, err = reader.ResolveTemplates(allSheets)
+	if err != nil {
+		return nil, err
+	}
+
+	// 应用 sheets.json 中配置的主键列
+	if err := reader.ResolveKeys(allSheets, b.configManager.SheetsConfig); err != nil {
+		return nil, err
+	}
+
+	// 按 filters.json 去除不再使用的行，枚举列按表格中填写的成员名比较
+	filtered, err := reader.FilterRows(allSheets, b.configManager.Filters)
+	if err != nil {
+		return nil, err
+	}
+	if len(filtered) > 0 {
+		names := make([]string, 0, len(filtered))
+		for name := range filtered {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		section := b.report.Section("过滤的行")
+		for _, name := range names {
+			section.Addf("%s: 去除 %d 行", name, filtered[name])
+		}
+	}
+
+	// 解析枚举列
+	reader.ResolveEnums(allSheets, b.enums)
+
+	// 应用合并配置
+	allSheets, err = b.applyCombineConfig(allSheets)
+	if err != nil {
+		return nil, err
+	}
+
+	// 应用列替换配置
+	if err := replace.Apply(allSheets, b.configManager.ReplaceConfig); err != nil {
+		return nil, err
+	}
+
+	// 追加 sheets.json 中配置的派生列，在列替换之后计算，参与验证和所有格式的转换
+	if err := reader.ResolveDerived(allSheets, b.configManager.SheetsConfig); err != nil {
+		return nil, err
+	}
+
+	// 按 sheets.json 或表元数据中的排序键排列行，转换器的 sortRowsBy 在此基础上再排序
+	if err := reader.ResolveSort(allSheets, b.configManager.SheetsConfig); err != nil {
+		return nil, err
+	}
+
+	// 合并表元数据和 sheets.json 中的标签
+	reader.ResolveTags(allSheets, b.configManager.SheetsConfig)
+
+	return allSheets, nil
+}
+
+// needProcess 检查文件是否需要处理，namespace 为文件所属源文件目录的命名空间
+func (b *Builder) needProcess(filePath, namespace string) bool {
+	// 获取文件修改时间
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return true
+	}
+
+	fileModTime := fileInfo.ModTime()
+
+	// 检查输出文件是否存在且修改时间晚于源文件
+	for _, format := range b.configManager.Config.Formats {
+		convConfig := b.configManager.GetConverterConfig(format)
+		if convConfig == nil || !convConfig.Enabled {
+			continue
+		}
+
+		// 构建输出路径
+		sheetName := model.QualifyName(namespace, strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)))
+		convConfig = convConfig.ForSheet(sheetName)
+		outputDir := b.configManager.Config.OutputDir
+		if convConfig.OutputPath != "" {
+			outputDir = filepath.Join(outputDir, convConfig.OutputPath)
+		}
+
+		// 构建输出文件名
+		fileName := model.SheetPath(sheetName)
+		var outputFileName string
+		switch format {
+		case "json":
+			outputFileName = fmt.Sprintf("%s.json", fileName)
+		case "php":
+			outputFileName = fmt.Sprintf("%s.php", fileName)
+		case "xml":
+			outputFileName = fmt.Sprintf("%s.xml", fileName)
+		case "fbs":
+			outputFileName = fmt.Sprintf("%s.bin", fileName)
+		case "gdb":
+			outputFileName = fmt.Sprintf("%s.gdb", fileName)
+		case "cbor":
+			outputFileName = fmt.Sprintf("%s.cbor", fileName)
+		case "csv":
+			outputFileName = fmt.Sprintf("%s.csv", fileName)
+		case "erlang":
+			outputFileName = converter.ErlangFileName(sheetName, convConfig.Options)
+		case "java":
+			outputFileName = converter.JavaFileName(sheetName, convConfig.Options)
+		case "cpp":
+			outputFileName = fmt.Sprintf("%s.h", fileName)
+		case "rust":
+			outputFileName = converter.RustFileName(sheetName)
+		case "godot":
+			outputFileName = converter.GodotFileName(sheetName, convConfig.Options)
+		default:
+			continue
+		}
+
+		outputPath := filepath.Join(outputDir, outputFileName)
+
+		// 检查输出文件是否存在
+		outputInfo, err := os.Stat(outputPath)
+		if err != nil {
+			return true // 输出文件不存在，需要处理
+		}
+
+		if outputInfo.ModTime().Before(fileModTime) {
+			return true // 输出文件早于源文件，需要处理
+		}
+	}
+
+	return false // 所有输出文件都存在且最新，不需要处理
+}
+
+// applyCombineConfig 应用合并配置，合并表的主键和列类型问题记录到 combineErrors，在验证阶段报告
+func (b *Builder) applyCombineConfig(sheets []*model.DataSheet) ([]*model.DataSheet, error) {
+	b.combineErrors = nil
+	if b.configManager.CombineConfig == nil {
+		return sheets, nil
+	}
+
+	// 构建表名到表的映射
+	sheetMap := make(map[string]*model.DataSheet)
+	for _, sheet := range sheets {
+		sheetMap[sheet.Name] = sheet
+	}
+
+	// 处理合并表
+	combinedSheets := make([]*model.DataSheet, 0)
+	processedSheets := make(map[string]bool)
+
+	// 先添加非合并表
+	for _, sheet := range sheets {
+		processedSheets[sheet.Name] = false
+	}
+
+	// 处理合并配置
+	for _, combineSheet := range b.configManager.CombineConfig.Sheets {
+		// 检查所有源表是否存在
+		allExists := true
+		for _, sourceSheetName := range combineSheet.SourceSheets {
+			if _, exists := sheetMap[sourceSheetName]; !exists {
+				allExists = false
+				break
+			}
+		}
+
+		if !allExists {
+			continue
+		}
+
+		// 创建合并表
+		combinedSheet := &model.DataSheet{
+			Name:      combineSheet.OutputName,
+			Columns:   []model.ColumnInfo{},
+			Rows:      []map[string]interface{}{},
+			Meta:      make(map[string]interface{}),
+			KeyColumn: combineSheet.KeyColumn,
+		}
+
+		// 未指定主键时使用第一个表的主键（按列名映射后的名称）
+		if combinedSheet.KeyColumn == "" && len(combineSheet.SourceSheets) > 0 {
+			firstName := combineSheet.SourceSheets[0]
+			combinedSheet.KeyColumn = sheetMap[firstName].PrimaryKey()
+			if mapped, exists := combineSheet.Columns[firstName][combinedSheet.KeyColumn]; exists {
+				combinedSheet.KeyColumn = mapped
+			}
+		}
+
+		// 按配置过滤并映射每个源表
+		parts := make([]*validator.CombinePart, 0, len(combineSheet.SourceSheets))
+		for _, sourceSheetName := range combineSheet.SourceSheets {
+			part, err := combinePart(sheetMap[sourceSheetName], combineSheet)
+			if err != nil {
+				return nil, fmt.Errorf("合并表 %s: %v", combineSheet.OutputName, err)
+			}
+			processedSheets[sourceSheetName] = true
+			parts = append(parts, part)
+		}
+
+		// 横向连接：按连接键把其余源表的列连接到第一个源表的行上
+		if join := combineSheet.Join; join != nil {
+			var joinErrors []*model.ErrorInfo
+			combinedSheet.Columns, combinedSheet.Rows, joinErrors = validator.JoinCombine(
+				combinedSheet.Name, combinedSheet.KeyColumn, parts, join.On, join.Type == config.CombineJoinInner)
+			b.combineErrors = append(b.combineErrors, joinErrors...)
+			combinedSheets = append(combinedSheets, combinedSheet)
+			continue
+		}
+
+		// 纵向合并：列按源表顺序取并集
+		columnCounts := make(map[string]int)
+		for _, part := range parts {
+			for _, col := range part.Columns {
+				if columnCounts[col.Name] == 0 {
+					combinedSheet.Columns = append(combinedSheet.Columns, col)
+				}
+				columnCounts[col.Name]++
+			}
+		}
+		if combineSheet.ColumnMode == config.CombineColumnsStrict {
+			b.combineErrors = append(b.combineErrors, validator.ValidateCombineColumns(combinedSheet.Name, parts)...)
+		}
+
+		// 按配置保留重复主键中的一行，去除的行记录到构建报告
+		if policy := combineSheet.OnDuplicateKey; policy == config.CombineDuplicateFirst || policy == config.CombineDuplicateLast {
+			dropped := validator.ResolveCombineKeys(combinedSheet.KeyColumn, parts, policy == config.CombineDuplicateLast)
+			section := b.report.Section("合并表去除的重复行")
+			for _, message := range dropped {
+				section.Addf("%s: %s", combinedSheet.Name, message)
+			}
+		}
+		for _, part := range parts {
+			combinedSheet.Rows = append(combinedSheet.Rows, part.Rows...)
+		}
+
+		// 只在部分源表中存在的列对其他源表的行没有值，在合并表中改为选填
+		for i, col := range combinedSheet.Columns {
+			if columnCounts[col.Name] < len(parts) {
+				combinedSheet.Columns[i].Required = false
+			}
+		}
+		b.combineErrors = append(b.combineErrors, validator.ValidateCombine(combinedSheet.Name, combinedSheet.KeyColumn, parts)...)
+
+		combinedSheets = append(combinedSheets, combinedSheet)
+	}
+
+	// 添加未合并的表
+	for _, sheet := range sheets {
+		if !processedSheets[sheet.Name] {
+			combinedSheets = append(combinedSheets, sheet)
+		}
+	}
+
+	return combinedSheets, nil
+}
+
+// combinePart 按合并配置过滤源表的行并映射列名；过滤条件使用源表的列名
+func combinePart(sheet *model.DataSheet, combine config.CombineSheet) (*validator.CombinePart, error) {
+	var where *expr.Expr
+	if source := combine.Where[sheet.Name]; source != "" {
+		compiled, err := expr.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("源表 %s 的过滤条件无效: %v", sheet.Name, err)
+		}
+		where = compiled
+	}
+
+	mapping := combine.Columns[sheet.Name]
+	part := &validator.CombinePart{Sheet: sheet.Name}
+	for _, col := range sheet.Columns {
+		if name, exists := mapping[col.Name]; exists {
+			col.Name = name
+		}
+		part.Columns = append(part.Columns, col)
+	}
+
+	for rowIndex, row := range sheet.Rows {
+		if where != nil {
+			matched, err := where.EvalBool(row)
+			if err != nil {
+				return nil, fmt.Errorf("源表 %s 第 %d 行: %v", sheet.Name, sheet.RowNumber(rowIndex), err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		part.Rows = append(part.Rows, renameRow(row, mapping))
+		part.RowNumbers = append(part.RowNumbers, sheet.RowNumber(rowIndex))
+	}
+	return part, nil
+}
+
+// renameRow 按列名映射复制一行，没有映射时直接返回原行
+func renameRow(row map[string]interface{}, mapping map[string]string) map[string]interface{} {
+	if len(mapping) == 0 {
+		return row
+	}
+
+	renamed := make(map[string]interface{}, len(row))
+	for name, val := range row {
+		renamed[name] = val
+	}
+	// 先删除再写入，列名互换时不会互相覆盖
+	for from := range mapping {
+		delete(renamed, from)
+	}
+	for from, to := range mapping {
+		if val, exists := model.RowValue(row, from); exists {
+			model.SetRowValue(renamed, to, val)
+		}
+	}
+	return renamed
+}
+
+// validateData 验证数据
+func (b *Builder) validateData(sheets []*model.DataSheet) []*model.ErrorInfo {
+	b.validator.SetEnums(b.enums)
+	b.validator.SetSampling(b.sampling(sheets))
+	b.validator.SetRefSheets(b.refSheets)
+	manifest, err := output.LoadManifest(b.configManager.Config.OutputDir)
+	if err != nil {
+		logger.Warnf("自定义规则无法读取上次构建的输出清单: %v", err)
+		manifest = &output.Manifest{Files: make([]string, 0)}
+	}
+	b.validator.SetCheckContext(b.configManager.Config, manifest)
+	whitelists, whitelistErrors := b.loadWhitelists(sheets)
+	b.validator.SetWhitelists(whitelists)
+	errors := append(append([]*model.ErrorInfo{}, b.combineErrors...), whitelistErrors...)
+	return append(errors, b.validator.ValidateAll(sheets)...)
+}
+
+// loadWhitelists 读取 sheets.json 中为各表的列配置的白名单文件，路径相对配置目录，同一文件只读取一次；
+// 读取失败或配置的列不存在时记为验证错误
+func (b *Builder) loadWhitelists(sheets []*model.DataSheet) (map[string]map[string]*validator.Whitelist, []*model.ErrorInfo) {
+	cfg := b.configManager.SheetsConfig
+	if cfg == nil {
+		return nil, nil
+	}
+
+	whitelists := make(map[string]map[string]*validator.Whitelist)
+	loaded := make(map[string]*validator.Whitelist)
+	var errors []*model.ErrorInfo
+	for _, sheet := range sheets {
+		for pattern, settings := range cfg.Sheets {
+			if matched, _ := path.Match(pattern, sheet.Name); !matched {
+				continue
+			}
+			for column, file := range settings.Whitelists {
+				if !sheet.HasColumn(column) {
+					errors = append(errors, &model.ErrorInfo{Sheet: sheet.Name, Column: column, Msg: fmt.Sprintf("sheets.json 中配置白名单 %s 的列不存在", file)})
+					continue
+				}
+				if !filepath.IsAbs(file) {
+					file = filepath.Join(b.confDir, file)
+				}
+				whitelist, exists := loaded[file]
+				if !exists {
+					var err error
+					if whitelist, err = validator.LoadWhitelist(file); err != nil {
+						errors = append(errors, &model.ErrorInfo{Sheet: sheet.Name, Column: column, Msg: fmt.Sprintf("读取白名单失败: %v", err)})
+						continue
+					}
+					loaded[file] = whitelist
+				}
+				if whitelists[sheet.Name] == nil {
+					whitelists[sheet.Name] = make(map[string]*validator.Whitelist)
+				}
+				whitelists[sheet.Name][column] = whitelist
+			}
+		}
+	}
+	return whitelists, errors
+}
+
+// sampling 监听模式下的抽样验证参数，未开启时返回 nil；被抽样的表记录到构建报告
+func (b *Builder) sampling(sheets []*model.DataSheet) *validator.Sampling {
+	if !b.sampleValidation {
+		return nil
+	}
+
+	cfg := b.configManager.Config.Sampling
+	sampling := &validator.Sampling{Threshold: 5000, Head: 500, Random: 500}
+	if cfg.Threshold > 0 {
+		sampling.Threshold = cfg.Threshold
+	}
+	if cfg.Head > 0 {
+		sampling.Head = cfg.Head
+	}
+	if cfg.Random > 0 {
+		sampling.Random = cfg.Random
+	}
+
+	for _, sheet := range sheets {
+		if sampling.Applies(len(sheet.Rows)) {
+			b.report.Section("抽样验证").Addf("%s: 共 %d 行，验证了 %d 行", sheet.Name, len(sheet.Rows), sampling.SampleSize(len(sheet.Rows)))
+		}
+	}
+	return sampling
+}
+
+// transformData 执行 transforms.json 中配置的处理步骤
+func (b *Builder) transformData(sheets []*model.DataSheet) error {
+	pipeline, err := transform.NewPipeline(b.configManager.Transforms)
+	if err != nil {
+		return err
+	}
+	if pipeline.Empty() {
+		return nil
+	}
+
+	logger.Infof("执行转换前处理")
+	return pipeline.RunContext(b.buildContext(), sheets)
+}
+
+// assignVersions 按内容哈希更新本次处理的表的版本，并写入表的元数据，由转换器随输出一起生成
+// 快速模式、按标签构建和预览构建中未处理的表保持原版本
+func (b *Builder) assignVersions(sheets []*model.DataSheet) error {
+	versions, err := output.LoadSheetVersions(b.stateReadPath(output.SheetVersionsFileName))
+	if err != nil {
+		return err
+	}
+
+	for _, sheet := range sheets {
+		version, err := versions.Bump(sheet)
+		if err != nil {
+			return err
+		}
+		if sheet.Meta == nil {
+			sheet.Meta = make(map[string]interface{})
+		}
+		sheet.Meta[output.SheetVersionMetaKey] = version
+	}
+	b.sheetVersions = versions
+	return nil
+}
+
+// analyzeData 执行可选的数据分析
+func (b *Builder) analyzeData(sheets []*model.DataSheet) error {
+	patterns := b.configManager.Config.Analysis.UsageManifests
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	// 死列检测
+	manifests, err := analysis.LoadUsageManifests(patterns)
+	if err != nil {
+		return fmt.Errorf("加载字段使用清单失败: %v", err)
+	}
+
+	deadColumns, unusedSheets := analysis.FindDeadColumns(sheets, manifests)
+	section := b.report.Section("未使用的列")
+	for _, sheetName := range unusedSheets {
+		section.Addf("%s: 整张表未被任何消费方读取", sheetName)
+	}
+	for _, col := range deadColumns {
+		section.Addf("%s.%s", col.Sheet, col.Column)
+	}
+
+	return nil
+}
+
+// forecastBundle 预估每张表在各格式下压缩后的包体占用
+func (b *Builder) forecastBundle(sheets []*model.DataSheet, results []*model.ConvertResult) error {
+	if !b.configManager.Config.Analysis.BundleForecast {
+		return nil
+	}
+
+	estimates, err := analysis.EstimateBundleSizes(sheets, results)
+	if err != nil {
+		return err
+	}
+
+	section := b.report.Section("包体预估")
+	totals := make(map[string]int)
+	formats := make([]string, 0)
+	for _, estimate := range estimates {
+		sheetName := estimate.Sheet
+		if sheetName == "" {
+			sheetName = "(汇总)"
+		}
+		section.Addf("%s [%s]: %d 行，原始 %s，压缩后 %s，平均 %s/行", sheetName, estimate.Format,
+			estimate.Rows, analysis.FormatSize(estimate.RawSize), analysis.FormatSize(estimate.CompressedSize),
+			analysis.FormatSize(estimate.PerRow()))
+
+		if _, exists := totals[estimate.Format]; !exists {
+			formats = append(formats, estimate.Format)
+		}
+		totals[estimate.Format] += estimate.CompressedSize
+	}
+	for _, format := range formats {
+		section.Addf("合计 [%s]: 压缩后 %s", format, analysis.FormatSize(totals[format]))
+	}
+
+	return nil
+}
+
+// createConverter 创建格式对应的转换器，外部工具缺失时按 onMissingTool 策略处理；返回 nil 表示跳过该格式
+func (b *Builder) createConverter(format string, convConfig *config.ConverterConfig) (converter.IConverter, error) {
+	conv, err := b.converterFactory.CreateConverter(format, convConfig.Options)
+	if err != nil || conv == nil {
+		return conv, err
+	}
+
+	checker, ok := conv.(converter.IToolchainConverter)
+	if !ok {
+		return conv, nil
+	}
+	toolErr := checker.CheckToolchain()
+	if toolErr == nil {
+		return conv, nil
+	}
+
+	section := b.report.Section("外部工具缺失")
+	switch convConfig.OnMissingTool {
+	case config.MissingToolSkip:
+		section.Addf("%s: %v，已跳过该格式", format, toolErr)
+		b.degrade(subsystemToolset, format, fmt.Errorf("%v，已跳过该格式", toolErr))
+		return nil, nil
+	case config.MissingToolFallback:
+		fallback, err := b.converterFactory.CreateConverter(convConfig.Fallback, convConfig.Options)
+		if err != nil {
+			return nil, err
+		}
+		if fallback == nil {
+			return nil, fmt.Errorf("%v，且替代转换器 %q 不存在", toolErr, convConfig.Fallback)
+		}
+		section.Addf("%s: %v，改用 %s 转换器", format, toolErr, convConfig.Fallback)
+		b.degrade(subsystemToolset, format, fmt.Errorf("%v，改用 %s 转换器", toolErr, convConfig.Fallback))
+		return fallback, nil
+	case "", config.MissingToolFail:
+		return nil, toolErr
+	default:
+		return nil, fmt.Errorf("不支持的 onMissingTool 策略: %s", convConfig.OnMissingTool)
+	}
+}
+
+// convertData 转换数据，单张表转换失败不影响其他表，返回所有转换错误
+func (b *Builder) convertData(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	// 同步处理时只使用一个工作协程
+	workers := 1
+	if b.configManager.Config.Async {
+		workers = runtime.NumCPU()
+		if b.configManager.Config.MaxWorkers > 0 {
+			workers = b.configManager.Config.MaxWorkers
+		}
+	}
+
+	// 以（表，格式）为单位构建任务，每个任务的结果写入独立的槽位，保证输出顺序稳定
+	tasks := make([]*scheduler.Task, 0)
+	slots := make([][]*model.ConvertResult, 0)
+	var progress *metrics.Progress // 任务全部创建后才知道总数
+	ctx := b.buildContext()
+
+	// 任务并发执行，错误加锁记录；失败的任务仍返回错误，使调度器跳过依赖它的汇总任务
+	var errMu sync.Mutex
+	convertErrs := &model.ConvertErrors{}
+	fail := func(err *model.ConvertError) error {
+		errMu.Lock()
+		defer errMu.Unlock()
+		convertErrs.Errors = append(convertErrs.Errors, err)
+		return err
+	}
+
+	// 遍历每个格式
+	for _, format := range b.configManager.Config.Formats {
+		convConfig := b.configManager.GetConverterConfig(format)
+		if convConfig == nil || !convConfig.Enabled {
+			continue
+		}
+
+		// 创建并初始化转换器
+		conv, err := b.createConverter(format, convConfig)
+		if err != nil {
+			fail(&model.ConvertError{Format: format, Err: err})
+			continue
+		}
+		if conv == nil {
+			continue
+		}
+
+		// 限制单个转换器的并发数，如调用外部工具的转换器
+		var limit chan struct{}
+		if convConfig.MaxWorkers > 0 {
+			limit = make(chan struct{}, convConfig.MaxWorkers)
+		}
+
+		logger.Infof("转换为 %s 格式", format)
+		sheetTaskIDs := make([]string, 0, len(sheets))
+		for _, sheet := range sheets {
+			// 配置了 sheetOverrides 的表使用单独创建的转换器
+			sheetConv := conv
+			if _, exists := convConfig.SheetOverrides[sheet.Name]; exists {
+				sheetConv, err = b.createConverter(format, convConfig.ForSheet(sheet.Name))
+				if err != nil {
+					fail(&model.ConvertError{Sheet: sheet.Name, Format: format, Err: err})
+					continue
+				}
+				if sheetConv == nil {
+					continue
+				}
+			}
+
+			slot := len(slots)
+			slots = append(slots, nil)
+
+			taskID := fmt.Sprintf("%s/%s", format, sheet.Name)
+			sheetTaskIDs = append(sheetTaskIDs, taskID)
+
+			tasks = append(tasks, &scheduler.Task{
+				ID: taskID,
+				Run: func() error {
+					if limit != nil {
+						limit <- struct{}{}
+						defer func() { <-limit }()
+					}
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					start := time.Now()
+					result, err := converter.ConvertContext(ctx, sheetConv, sheet)
+					b.recordTiming(metrics.KindSheet, sheet.Name, start)
+					b.recordTiming(metrics.KindConverter, format, start)
+					progress.Add(1)
+					if err != nil {
+						if ctx.Err() != nil {
+							return ctx.Err()
+						}
+						return fail(&model.ConvertError{Sheet: sheet.Name, Format: format, Err: err})
+					}
+					result.Sheet = sheet.Name
+					result.Format = format
+					slots[slot] = []*model.ConvertResult{result}
+					return nil
+				},
+			})
+		}
+
+		// 汇总文件依赖该格式下所有表的转换
+		if indexConv, ok := conv.(converter.IIndexConverter); ok {
+			slot := len(slots)
+			slots = append(slots, nil)
+
+			tasks = append(tasks, &scheduler.Task{
+				ID:   fmt.Sprintf("%s/@index", format),
+				Deps: sheetTaskIDs,
+				Run: func() error {
+					start := time.Now()
+					indexResults, err := indexConv.ConvertIndex(sheets)
+					b.recordTiming(metrics.KindConverter, format, start)
+					progress.Add(1)
+					if err != nil {
+						return fail(&model.ConvertError{Format: format, Err: err})
+					}
+					for _, result := range indexResults {
+						result.Format = format
+					}
+					slots[slot] = indexResults
+					return nil
+				},
+			})
+		}
+	}
+
+	progress = b.newProgress("转换", len(tasks))
+	err := scheduler.NewScheduler(workers).Run(tasks)
+	progress.Finish()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err := convertErrs.Err(); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.ConvertResult, 0, len(slots))
+	for _, slotResults := range slots {
+		results = append(results, slotResults...)
+	}
+
+	return results, nil
+}
+
+// pushResults 将变更的结果推送到运行中的游戏，调试端不可用时记录为降级构建
+func (b *Builder) pushResults(results []*model.ConvertResult) {
+	if b.pusher == nil {
+		return
+	}
+
+	count, err := b.pusher.Push(results)
+	if err != nil {
+		b.degrade(subsystemPush, "", fmt.Errorf("推送到调试端失败: %v", err))
+		return
+	}
+	if count > 0 {
+		logger.Infof("推送 %d 个变更文件到调试端", count)
+	}
+}
+
+// packageResults 按转换器选项 compress 和 bundle 压缩或打包各格式的输出
+func (b *Builder) packageResults(results []*model.ConvertResult) ([]*model.ConvertResult, error) {
+	// 按格式分组，保持原有顺序
+	formats := make([]string, 0)
+	grouped := make(map[string][]*model.ConvertResult)
 	for _, result := range results {
-		// 获取转换器配置
-		convConfig := b.configManager.GetConverterConfig(result.Format)
+		if _, exists := grouped[result.Format]; !exists {
+			formats = append(formats, result.Format)
+		}
+		grouped[result.Format] = append(grouped[result.Format], result)
+	}
+
+	packed := make([]*model.ConvertResult, 0, len(results))
+	for _, format := range formats {
+		convConfig := b.configManager.GetConverterConfig(format)
 		if convConfig == nil {
+			packed = append(packed, grouped[format]...)
 			continue
 		}
 
-		// 构建游戏目录下的输出路径
-		gameOutputDir := b.configManager.Config.GameDir
-		if convConfig.OutputPath != "" {
-			gameOutputDir = filepath.Join(gameOutputDir, convConfig.OutputPath)
+		opts, err := output.ParsePackOptions(convConfig.Options)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", format, err)
+		}
+		formatResults, err := output.Pack(format, grouped[format], opts)
+		if err != nil {
+			return nil, err
 		}
+		packed = append(packed, formatResults...)
+	}
+
+	return packed, nil
+}
+
+// partialOutput 本次构建是否只输出了部分表
+func (b *Builder) partialOutput() bool {
+	return b.configManager.Config.FastMode || b.selective() || len(b.failedSheets) > 0
+}
+
+// writeJSONFile 序列化并写入事务
+func writeJSONFile(tx *output.Transaction, relPath string, marshal func() ([]byte, error)) error {
+	content, err := marshal()
+	if err != nil {
+		return err
+	}
+	return tx.Write(relPath, content)
+}
 
-		// 创建目录
-		if err := os.MkdirAll(gameOutputDir, 0755); err != nil {
-			return fmt.Errorf("创建游戏输出目录失败: %v", err)
+// outputFiles 按各格式的输出路径整理待输出的文件
+func (b *Builder) outputFiles(results []*model.ConvertResult) []sink.File {
+	files := make([]sink.File, 0, len(results))
+	for _, result := range results {
+		convConfig := b.configManager.GetConverterConfig(result.Format)
+		if convConfig == nil {
+			continue
 		}
+		relPath := filepath.Join(convConfig.ForSheet(result.Sheet).OutputPath, result.FileName)
+		files = append(files, sink.File{Path: filepath.ToSlash(relPath), Format: result.Format, Content: result.Content})
+	}
+	return files
+}
+
+// outputResults 输出结果
+func (b *Builder) outputResults(files []sink.File) error {
+	root := b.configManager.Config.OutputDir
+	write := b.writeResults
+	if b.configManager.Config.Layout == config.LayoutCAS {
+		write = b.publishResults
+	}
+	changed, err := write(root, files, "生成文件")
+	if err != nil {
+		return &model.OutputError{Path: root, Err: err}
+	}
+	b.changedFiles = changed
+	return nil
+}
+
+// writeOptions 输出文件的写入选项
+func (b *Builder) writeOptions() output.WriteOptions {
+	return output.WriteOptions{Workers: b.configManager.Config.OutputWorkers, Fsync: b.configManager.Config.Fsync}
+}
+
+// stalePolicy 目标目录中过期文件（清单中有、本次未生成的文件）的处理方式
+type stalePolicy struct {
+	remove   bool   // 是否清理过期文件
+	trashDir string // 不为空时先将过期文件复制到该目录下以构建时间命名的子目录，再从目标目录删除
+}
 
-		// 构建输出文件路径
-		outputPath := filepath.Join(gameOutputDir, result.FileName)
+// writeResults 以事务方式写入转换结果：先写入暂存目录，全部成功后再替换到目标目录；返回内容有变化的文件
+func (b *Builder) writeResults(root string, files []sink.File, action string) ([]string, error) {
+	return b.writeDir(root, files, action, stalePolicy{remove: b.prune})
+}
+
+// writeDir 以事务方式写入目标目录，按 policy 处理过期文件；返回内容有变化的文件
+func (b *Builder) writeDir(root string, files []sink.File, action string, policy stalePolicy) ([]string, error) {
+	previous, err := output.LoadManifest(root)
+	if err != nil {
+		return nil, err
+	}
+	previousVersion, err := output.LoadVersionFile(root)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := output.BeginWithOptions(root, b.keepStaging, b.writeOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	// 并发写入暂存目录
+	version := output.NewVersionFile(b.buildTime, output.GitCommit(b.configManager.Config.SourceDir))
+	b.outputVersion = version.Commit
+	if len(b.outputVersion) > 12 {
+		b.outputVersion = b.outputVersion[:12]
+	}
+	generated := make([]string, 0, len(files))
+	pending := make([]output.File, 0, len(files))
+	for _, file := range files {
+		relPath := filepath.FromSlash(file.Path)
+		generated = append(generated, relPath)
+		pending = append(pending, output.File{Path: relPath, Content: file.Content})
+		version.Add(relPath, file.Content)
+	}
+	progress := b.newProgress(action, len(files))
+	err = tx.WriteAll(pending, func() { progress.Add(1) })
+	progress.Finish()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
-		// 写入文件
-		if err := os.WriteFile(outputPath, result.Content, 0644); err != nil {
-			return fmt.Errorf("写入游戏文件失败: %v", err)
+	// 清理不再对应任何表的过期文件，快速模式和按标签构建中未处理的表、预览构建中跳过的表没有输出，无法判断是否过期
+	stale := previous.Stale(generated)
+	manifest := &output.Manifest{Files: generated, FailedSheets: b.failedSheets}
+	version.FailedSheets = b.failedSheets
+	version.Sheets = b.sheetVersions.Versions()
+	switch {
+	case len(stale) == 0:
+	case b.partialOutput():
+		manifest.Files = append(manifest.Files, stale...)
+		version.Inherit(previousVersion, stale)
+		if policy.remove || b.pruneDryRun {
+			logger.Warnf("快速模式、按标签构建或预览构建中跳过了部分表，不清理过期文件")
+		}
+	case policy.remove:
+		if policy.trashDir != "" {
+			if err := b.trashStale(root, stale, policy.trashDir); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		for _, relPath := range stale {
+			if err := tx.Remove(relPath); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	default:
+		// 未清理的文件保留在清单中，以便之后清理
+		manifest.Files = append(manifest.Files, stale...)
+		for _, relPath := range stale {
+			if b.pruneDryRun {
+				logger.Infof("[DRY-RUN] 将清理过期文件: %s", filepath.Join(root, relPath))
+			}
 		}
+	}
+
+	// 更新输出清单和版本文件
+	if err := writeJSONFile(tx, output.ManifestFileName, manifest.Marshal); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := writeJSONFile(tx, output.VersionFileName, version.Marshal); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 全部写入成功后统一提交，提交前取消时删除暂存目录，输出目录保持不变
+	if err := b.buildContext().Err(); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
-		fmt.Printf("同步到游戏目录: %s\n", outputPath)
+	for _, relPath := range generated {
+		logger.Infof("%s: %s", action, filepath.Join(root, relPath))
+	}
+	if policy.remove && !b.partialOutput() {
+		for _, relPath := range stale {
+			logger.Infof("清理过期文件: %s", filepath.Join(root, relPath))
+		}
 	}
+	return version.Changed(previousVersion), nil
+}
 
+// trashStale 将过期文件复制到回收目录下以构建时间命名的子目录，保持相对路径；之后由事务从目标目录删除
+func (b *Builder) trashStale(root string, stale []string, trashDir string) error {
+	dir := filepath.Join(trashDir, b.buildTime.Format("20060102-150405"))
+	for _, relPath := range stale {
+		content, err := os.ReadFile(filepath.Join(root, relPath))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, relPath)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("移入回收目录失败: %v", err)
+		}
+		if err := output.WriteFileAtomic(target, content, b.configManager.Config.Fsync); err != nil {
+			return fmt.Errorf("移入回收目录失败: %v", err)
+		}
+		logger.Infof("过期文件已移入回收目录: %s", target)
+	}
 	return nil
 }
 
+// publishResults 以内容寻址结构发布转换结果：写入内容文件和新版本后切换 latest；返回内容有变化的文件
+func (b *Builder) publishResults(root string, files []sink.File, action string) ([]string, error) {
+	store := output.NewCASStoreWithOptions(root, b.writeOptions())
+	latest, err := store.Latest()
+	if err != nil {
+		return nil, err
+	}
+	previous, err := store.LoadVersion(latest)
+	if err != nil {
+		return nil, err
+	}
+
+	version := output.NewVersionFile(b.buildTime, output.GitCommit(b.configManager.Config.SourceDir))
+	version.FailedSheets = b.failedSheets
+	version.Sheets = b.sheetVersions.Versions()
+	generated := make([]string, 0, len(files))
+	contents := make([][]byte, 0, len(files))
+	for _, file := range files {
+		relPath := filepath.FromSlash(file.Path)
+		generated = append(generated, relPath)
+		contents = append(contents, file.Content)
+		version.Add(relPath, file.Content)
+	}
+	progress := b.newProgress(action, len(files))
+	err = store.WriteBlobs(contents, func() { progress.Add(1) })
+	progress.Finish()
+	if err != nil {
+		return nil, err
+	}
+
+	// 快速模式、按标签构建和预览构建中未处理的表沿用上一个版本的文件，完整构建时不再出现的文件自然不属于新版本
+	if b.partialOutput() {
+		stale := make([]string, 0)
+		current := make(map[string]bool, len(generated))
+		for _, relPath := range generated {
+			current[filepath.ToSlash(relPath)] = true
+		}
+		for _, entry := range previous.Files {
+			if !current[entry.Path] {
+				stale = append(stale, entry.Path)
+			}
+		}
+		version.Inherit(previous, stale)
+	}
+
+	if err := b.buildContext().Err(); err != nil {
+		return nil, err
+	}
+	id := store.NewVersionID(b.buildTime)
+	if err := store.Publish(id, version); err != nil {
+		return nil, err
+	}
+	for _, relPath := range generated {
+		logger.Infof("%s: %s", action, filepath.Join(root, output.VersionsDir, id, relPath))
+	}
+	logger.Infof("发布版本: %s", id)
+	b.outputVersion = id
+
+	removed, err := store.Retain(b.configManager.Config.Retain)
+	if err != nil {
+		return nil, err
+	}
+	for _, old := range removed {
+		logger.Infof("清理历史版本: %s", old)
+	}
+	return version.Changed(previous), nil
+}
+
 func main() {
+	// 解析子命令，默认为 build
+	args := os.Args[1:]
+	command := "build"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
+		args = args[1:]
+	}
+
+	switch command {
+	case "build":
+		runBuild(args)
+	case "freeze":
+		runFreeze(args)
+	case "approve":
+		runApprove(args)
+	case "serve":
+		runServe(args)
+	case "grpc-serve":
+		runGrpcServe(args)
+	case "watch":
+		runWatch(args)
+	case "init":
+		runInit(args)
+	case "rollback":
+		runRollback(args)
+	case "diff":
+		runDiff(args)
+	case "qa":
+		runQA(args)
+	case "fixtures":
+		runFixtures(args)
+	default:
+		fmt.Printf("未知命令: %s\n", command)
+		os.Exit(2)
+	}
+}
+
+// splitList 解析命令行中逗号分隔的表名或文件路径，去除空白和重复项
+func splitList(text string) []string {
+	items := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// logFlags 日志相关的命令行参数
+type logFlags struct {
+	quiet   *bool
+	verbose *bool
+	format  *string
+}
+
+// addLogFlags 注册 -quiet、-verbose 和 -log-format 参数
+func addLogFlags(flags *flag.FlagSet) *logFlags {
+	return &logFlags{
+		quiet:   flags.Bool("quiet", false, "只输出警告和错误"),
+		verbose: flags.Bool("verbose", false, "输出调试日志"),
+		format:  flags.String("log-format", logger.FormatText, "日志格式：text 或 json"),
+	}
+}
+
+// apply 按参数设置默认日志记录器，参数无效时退出
+func (f *logFlags) apply() {
+	log := logger.Default()
+	if err := log.SetFormat(*f.format); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	switch {
+	case *f.verbose:
+		log.SetLevel(logger.LevelDebug)
+	case *f.quiet:
+		log.SetLevel(logger.LevelWarn)
+	}
+}
+
+// runBuild 执行 build 子命令
+func runBuild(args []string) {
 	// 解析命令行参数
-	confDir := flag.String("conf", "./conf", "配置文件目录")
-	fastMode := flag.Bool("fast", false, "快速模式，只处理修改过的文件")
-	async := flag.Bool("async", false, "异步处理")
-	help := flag.Bool("help", false, "显示帮助信息")
-	flag.Parse()
+	flags := flag.NewFlagSet("build", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	fastMode := flags.Bool("fast", false, "快速模式，只处理修改过的文件")
+	async := flags.Bool("async", false, "异步处理")
+	locked := flags.Bool("locked", false, "要求输入与 build.lock 完全一致")
+	keepStaging := flags.Bool("keep-staging", false, "保留输出暂存目录")
+	allowErrors := flags.Bool("allow-errors", false, "预览构建：跳过验证失败的表，输出其余的表")
+	prune := flags.Bool("prune", false, "清理不再对应任何表的过期输出文件")
+	pruneDryRun := flags.Bool("prune-dry-run", false, "只列出过期输出文件而不删除")
+	syncDryRun := flags.Bool("sync-dry-run", false, "只列出同步阶段将在游戏目录等目标中新增、修改和删除的文件，不写入")
+	progress := flags.Bool("progress", false, "在终端中显示各阶段进度条，只输出警告和错误日志")
+	stats := flags.Bool("stats", false, "构建报告中列出各阶段以及最慢的文件、表和转换器的耗时")
+	tags := flags.String("tags", "", "只构建带有这些标签的表，以逗号分隔")
+	only := flags.String("only", "", "只构建这些表，以逗号分隔，其余的表只用于校验引用")
+	files := flags.String("file", "", "只构建这些源文件中的表，以逗号分隔，其余的表只用于校验引用")
+	stateDir := flags.String("state-dir", "", "状态文件目录，覆盖配置中的 stateDir")
+	profile := flags.String("profile", os.Getenv(config.ProfileEnv), "环境配置，将 config.<profile>.json 合并到 config.json 上")
+	logOptions := addLogFlags(flags)
+	help := flags.Bool("help", false, "显示帮助信息")
+	flags.Parse(args)
 
 	// 显示帮助信息
 	if *help {
 		fmt.Println("游戏数据构建工具")
 		fmt.Println("Usage:")
-		fmt.Println("  builder [options]")
+		fmt.Println("  builder [build] [options]")
 		fmt.Println("Options:")
 		fmt.Println("  -conf string   配置文件目录 (default \"./conf\")")
 		fmt.Println("  -fast          快速模式，只处理修改过的文件")
 		fmt.Println("  -async         异步处理")
+		fmt.Println("  -locked        要求输入与 build.lock 完全一致")
+		fmt.Println("  -keep-staging  保留输出暂存目录")
+		fmt.Println("  -allow-errors  预览构建：跳过验证失败的表，输出其余的表")
+		fmt.Println("  -prune         清理不再对应任何表的过期输出文件")
+		fmt.Println("  -prune-dry-run 只列出过期输出文件而不删除")
+		fmt.Println("  -sync-dry-run  只列出同步阶段将在游戏目录等目标中新增、修改和删除的文件，不写入")
+		fmt.Println("  -progress      在终端中显示各阶段进度条，只输出警告和错误日志")
+		fmt.Println("  -stats         构建报告中列出各阶段以及最慢的文件、表和转换器的耗时")
+		fmt.Println("  -tags string   只构建带有这些标签的表，以逗号分隔")
+		fmt.Println("  -only string   只构建这些表，以逗号分隔，其余的表只用于校验引用")
+		fmt.Println("  -file string   只构建这些源文件中的表，以逗号分隔，其余的表只用于校验引用")
+		fmt.Println("  -state-dir     状态文件目录，覆盖配置中的 stateDir")
+		fmt.Println("  -profile       环境配置，将 config.<profile>.json 合并到 config.json 上")
+		fmt.Println("  -quiet         只输出警告和错误")
+		fmt.Println("  -verbose       输出调试日志")
+		fmt.Println("  -log-format    日志格式：text 或 json (default \"text\")")
 		fmt.Println("  -help          显示帮助信息")
 		return
 	}
+	logOptions.apply()
 
 	// 创建构建器
 	builder := NewBuilder()
+	builder.locked = *locked
+	builder.keepStaging = *keepStaging
+	builder.allowErrors = *allowErrors
+	builder.prune = *prune
+	builder.pruneDryRun = *pruneDryRun
+	builder.syncDryRun = *syncDryRun
+	builder.stats = *stats
+	builder.tags = reader.ParseTags(*tags)
+	builder.only = splitList(*only)
+	builder.files = splitList(*files)
+
+	// 进度条输出到标准错误，只在终端中显示；逐文件的日志由进度条代替
+	if *progress && isTerminal(os.Stderr) {
+		builder.progressOut = os.Stderr
+		if !*logOptions.verbose {
+			logger.Default().SetLevel(logger.LevelWarn)
+		}
+	}
 
 	// 加载配置
+	builder.configManager.SetProfile(*profile)
 	if err := builder.LoadConfig(*confDir); err != nil {
-		fmt.Printf("加载配置失败: %v\n", err)
+		logger.Errorf("加载配置失败: %v", err)
 		os.Exit(1)
 	}
+	if *profile != "" {
+		logger.Infof("使用环境配置: %s", *profile)
+	}
 
 	// 覆盖配置
+	if *stateDir != "" {
+		builder.configManager.Config.StateDir = *stateDir
+	}
 	if *fastMode {
 		builder.configManager.Config.FastMode = true
 	}
@@ -498,9 +2025,199 @@ func main() {
 		builder.configManager.Config.Async = true
 	}
 
+	// 收到 SIGINT/SIGTERM 时取消构建，再次中断时直接退出
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	// 执行构建
-	if err := builder.Build(); err != nil {
-		fmt.Printf("构建失败: %v\n", err)
+	if err := builder.BuildContext(ctx); err != nil {
+		logger.Errorf("构建失败: %v", err)
+		os.Exit(exitCode(err))
+	}
+	if len(builder.degraded) > 0 {
+		os.Exit(exitDegraded)
+	}
+}
+
+// 构建失败时按错误类别返回的退出码
+const (
+	exitFailure    = 1   // 其他错误
+	exitRead       = 3   // 读取源文件失败
+	exitValidation = 4   // 数据验证失败
+	exitConvert    = 5   // 转换失败
+	exitOutput     = 6   // 写入输出失败
+	exitDegraded   = 7   // 构建完成，但部分已配置的可选功能不可用
+	exitCanceled   = 130 // 被中断（Ctrl-C）取消
+)
+
+// exitCode 根据错误类别确定退出码
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return exitCanceled
+	case errors.Is(err, model.ErrValidation):
+		return exitValidation
+	case errors.Is(err, model.ErrRead):
+		return exitRead
+	case errors.Is(err, model.ErrConvert):
+		return exitConvert
+	case errors.Is(err, model.ErrOutput):
+		return exitOutput
+	default:
+		return exitFailure
+	}
+}
+
+// runFreeze 执行 freeze 子命令，以当前内容冻结指定的表
+func runFreeze(args []string) {
+	flags := flag.NewFlagSet("freeze", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	reason := flags.String("reason", "", "冻结原因")
+	flags.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  builder freeze [options] sheet...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	builder := NewBuilder()
+	if err := builder.LoadConfig(*confDir); err != nil {
+		logger.Errorf("加载配置失败: %v", err)
+		os.Exit(1)
+	}
+
+	sheets, err := builder.readSourceFiles()
+	if err != nil {
+		logger.Errorf("读取源文件失败: %v", err)
+		os.Exit(1)
+	}
+
+	frozenConfig := builder.configManager.FrozenConfig
+	if err := freeze.Freeze(sheets, frozenConfig, flags.Args(), *reason); err != nil {
+		logger.Errorf("冻结失败: %v", err)
+		os.Exit(1)
+	}
+	if err := builder.configManager.SaveFrozenConfig(*confDir); err != nil {
+		logger.Errorf("保存冻结配置失败: %v", err)
+		os.Exit(1)
+	}
+
+	logger.Infof("已冻结 %d 个表", flags.NArg())
+}
+
+// runApprove 执行 approve 子命令，批准冻结表的当前内容
+func runApprove(args []string) {
+	flags := flag.NewFlagSet("approve", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	token := flags.String("token", "", "批准人的令牌，SHA-256 须与 frozen.json 中 approvers 配置的一致")
+	by := flags.String("by", "", "批准人，须在 frozen.json 的 approvers 中")
+	flags.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  builder approve -by NAME -token TOKEN [options] sheet")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	builder := NewBuilder()
+	if err := builder.LoadConfig(*confDir); err != nil {
+		logger.Errorf("加载配置失败: %v", err)
+		os.Exit(1)
+	}
+
+	sheets, err := builder.readSourceFiles()
+	if err != nil {
+		logger.Errorf("读取源文件失败: %v", err)
+		os.Exit(1)
+	}
+
+	frozenConfig := builder.configManager.FrozenConfig
+	if err := freeze.Approve(sheets, frozenConfig, flags.Arg(0), *token, *by); err != nil {
+		logger.Errorf("批准失败: %v", err)
+		os.Exit(1)
+	}
+	if err := builder.configManager.SaveFrozenConfig(*confDir); err != nil {
+		logger.Errorf("保存冻结配置失败: %v", err)
+		os.Exit(1)
+	}
+
+	logger.Infof("已批准表 %s 的当前内容", flags.Arg(0))
+}
+
+// runRollback 执行 rollback 子命令，把 cas 输出结构的 latest 切换到指定版本，未指定时切换到上一个版本
+func runRollback(args []string) {
+	flags := flag.NewFlagSet("rollback", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	list := flags.Bool("list", false, "列出所有版本")
+	flags.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  builder rollback [options] [version]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	builder := NewBuilder()
+	if err := builder.LoadConfig(*confDir); err != nil {
+		logger.Errorf("加载配置失败: %v", err)
+		os.Exit(1)
+	}
+	if builder.configManager.Config.Layout != config.LayoutCAS {
+		logger.Errorf("只有 cas 输出结构支持回滚")
+		os.Exit(1)
+	}
+
+	store := output.NewCASStoreWithOptions(builder.configManager.Config.OutputDir, builder.writeOptions())
+	versions, err := store.Versions()
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+	latest, err := store.Latest()
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	if *list {
+		for _, id := range versions {
+			if id == latest {
+				fmt.Printf("* %s\n", id)
+			} else {
+				fmt.Printf("  %s\n", id)
+			}
+		}
+		return
+	}
+
+	target := flags.Arg(0)
+	if target == "" {
+		for i, id := range versions {
+			if id == latest && i > 0 {
+				target = versions[i-1]
+			}
+		}
+		if target == "" {
+			logger.Errorf("没有更早的版本")
+			os.Exit(1)
+		}
+	}
+
+	if err := store.SetLatest(target); err != nil {
+		logger.Errorf("回滚失败: %v", err)
 		os.Exit(1)
 	}
+	logger.Infof("当前版本: %s", target)
 }
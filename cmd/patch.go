@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/diff"
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+)
+
+// patchSuffix JSON Patch 文件名后缀，如 items.json 的补丁为 items.patch.json
+const patchSuffix = ".patch.json"
+
+// jsonPatches 为开启 patch 选项的 JSON 输出生成相对上次构建的 RFC 6902 补丁，与完整文件一起输出；
+// 上次构建没有该文件或无法解析（如压缩、加密后的文件）时不生成补丁
+func (b *Builder) jsonPatches(results []*model.ConvertResult) []*model.ConvertResult {
+	patches := make([]*model.ConvertResult, 0)
+	for _, result := range results {
+		if result.Format != "json" || !strings.HasSuffix(result.FileName, ".json") || strings.HasSuffix(result.FileName, patchSuffix) {
+			continue
+		}
+		convConfig := b.configManager.GetConverterConfig(result.Format)
+		if convConfig == nil {
+			continue
+		}
+		convConfig = convConfig.ForSheet(result.Sheet)
+		if enabled, _ := convConfig.Options["patch"].(bool); !enabled {
+			continue
+		}
+
+		previous, err := os.ReadFile(b.previousOutputPath(filepath.Join(convConfig.OutputPath, result.FileName)))
+		if err != nil {
+			logger.Debugf("%s 没有上次构建的输出，不生成补丁", result.FileName)
+			continue
+		}
+		ops, err := diff.JSONPatch(previous, result.Content, output.SheetVersionMetaKey)
+		if err != nil {
+			logger.Warnf("生成 %s 的补丁失败: %v", result.FileName, err)
+			continue
+		}
+		content, err := json.Marshal(ops)
+		if err != nil {
+			logger.Warnf("生成 %s 的补丁失败: %v", result.FileName, err)
+			continue
+		}
+
+		if len(ops) > 0 {
+			b.report.Section("JSON Patch").Addf("%s: %d 个操作", result.FileName, len(ops))
+		}
+		patches = append(patches, &model.ConvertResult{
+			FileName: strings.TrimSuffix(result.FileName, ".json") + patchSuffix,
+			Content:  content,
+			Format:   result.Format,
+		})
+	}
+	return patches
+}
+
+// previousOutputPath 上次构建输出的文件路径，内容寻址结构下为当前版本中的文件
+func (b *Builder) previousOutputPath(relPath string) string {
+	cfg := b.configManager.Config
+	if cfg.Layout == config.LayoutCAS {
+		return filepath.Join(cfg.OutputDir, output.LatestLink, relPath)
+	}
+	return filepath.Join(cfg.OutputDir, relPath)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/game-data-builder/internal/diff"
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// lastTag 获取目录所在仓库中离当前提交最近的标签
+func lastTag(dir string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("查找最近的标签失败: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// runQA 执行 qa 子命令，为相对某个 git 提交（默认为最近的标签）有新增或修改行的表生成回归测试清单
+func runQA(args []string) {
+	flags := flag.NewFlagSet("qa", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	ref := flags.String("ref", "", "与工作区比较的 git 提交，默认为最近的标签")
+	format := flags.String("format", "markdown", "清单格式：markdown 或 xlsx")
+	out := flags.String("out", "./qa", "清单输出目录")
+	ownerColumn := flags.String("owner-column", "owner", "记录负责人的列，行中没有该列时使用表元数据 owner")
+	fields := flags.String("fields", "name", "每行列出的关键字段，以逗号分隔，主键总是列出")
+	tags := flags.String("tags", "", "只处理带有这些标签的表，以逗号分隔")
+	flags.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  builder qa [options]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var extension string
+	switch *format {
+	case "markdown", "md":
+		extension = ".md"
+	case "xlsx":
+		extension = ".xlsx"
+	default:
+		logger.Errorf("不支持的清单格式: %s", *format)
+		os.Exit(2)
+	}
+
+	// 读取过程中的进度输出改写到标准错误，与 diff 子命令保持一致
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	builder := NewBuilder()
+	if err := builder.LoadConfig(*confDir); err != nil {
+		logger.Errorf("加载配置失败: %v", err)
+		os.Exit(1)
+	}
+	if *ref == "" {
+		tag, err := lastTag(builder.configManager.Config.AllSourceRoots()[0].Dir)
+		if err != nil {
+			logger.Errorf("%v，请使用 -ref 指定比较的提交", err)
+			os.Exit(1)
+		}
+		*ref = tag
+	}
+	oldSheets, err := builder.loadSourceAt(*ref)
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+	newSheets, err := builder.readSourceFiles()
+	if err != nil {
+		logger.Errorf("读取源文件失败: %v", err)
+		os.Exit(1)
+	}
+	os.Stdout = stdout
+	if selected := reader.ParseTags(*tags); len(selected) > 0 {
+		oldSheets = reader.SelectTags(oldSheets, selected)
+		newSheets = reader.SelectTags(newSheets, selected)
+	}
+
+	report := diff.Compare(oldSheets, newSheets)
+	report.Old, report.New = *ref, "工作区"
+	keyFields := make([]string, 0)
+	for _, field := range strings.Split(*fields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			keyFields = append(keyFields, field)
+		}
+	}
+	checklists := diff.BuildChecklists(report, newSheets, *ownerColumn, keyFields)
+	if len(checklists) == 0 {
+		logger.Infof("相对 %s 没有新增或修改的行", *ref)
+		return
+	}
+
+	for _, checklist := range checklists {
+		var content bytes.Buffer
+		if extension == ".xlsx" {
+			err = checklist.WriteXLSX(&content)
+		} else {
+			err = checklist.WriteMarkdown(&content)
+		}
+		if err != nil {
+			logger.Errorf("生成 %s 的清单失败: %v", checklist.Sheet, err)
+			os.Exit(1)
+		}
+
+		path := filepath.Join(*out, model.SheetPath(checklist.Sheet)+extension)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			logger.Errorf("创建目录失败: %v", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, content.Bytes(), 0644); err != nil {
+			logger.Errorf("写入清单失败: %v", err)
+			os.Exit(1)
+		}
+		logger.Infof("%s: %d 行需要测试，清单: %s", checklist.Sheet, checklist.Count(), path)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/permission"
+)
+
+// maxUploadSize 上传文件大小上限
+const maxUploadSize = 32 << 20
+
+// CheckResult 上传检查结果
+type CheckResult struct {
+	OK               bool               `json:"ok"`               // 是否通过检查
+	Sheets           []string           `json:"sheets"`           // 上传文件中的表
+	Errors           []*model.ErrorInfo `json:"errors"`           // 验证错误
+	PermissionErrors []string           `json:"permissionErrors"` // 越权修改
+}
+
+// Server 守护进程HTTP服务
+type Server struct {
+	config *liveConfig // 与构建服务共用，配置文件修改后自动重新加载
+	builds *BuildService
+}
+
+// NewServer 创建HTTP服务，profile 为叠加的环境配置
+func NewServer(confDir, profile string) *Server {
+	builds := NewBuildService(confDir, profile)
+	return &Server{config: builds.config, builds: builds}
+}
+
+// Handler 注册所有接口
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/check", s.handleCheck)
+	s.builds.registerRoutes(mux)
+	return mux
+}
+
+// handleCheck 接收上传的表格，在当前项目上下文中读取并验证，不写入任何文件
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("读取上传文件失败: %v", err), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("读取上传文件失败: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// 角色只从 permissions.json 中配置的令牌确定，不接受客户端直接声明的角色
+	token, hasToken := bearerToken(r)
+	result, err := s.checkUpload(header.Filename, content, token, hasToken)
+	if errors.Is(err, errUnknownToken) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// errUnknownToken 请求携带了未在 permissions.json 中配置的令牌
+var errUnknownToken = errors.New("无效的访问令牌")
+
+// bearerToken 读取 Authorization: Bearer 请求头中的访问令牌
+func bearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && token != ""
+}
+
+// checkUpload 用上传的表替换项目中的同名表后执行读取和验证，未携带令牌时以匿名（空角色）检查写权限
+//
+// 与构建共用执行锁：检查过程中的日志写入默认日志记录器，与构建同时执行会混入构建任务的日志
+func (s *Server) checkUpload(fileName string, content []byte, token string, hasToken bool) (*CheckResult, error) {
+	s.builds.run.Lock()
+	defer s.builds.run.Unlock()
+
+	builder, err := s.config.newBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %v", err)
+	}
+
+	checker := permission.NewChecker(builder.configManager.Permissions)
+	role := ""
+	if hasToken {
+		var ok bool
+		if role, ok = checker.Role(token); !ok {
+			return nil, errUnknownToken
+		}
+	}
+
+	// 保存到临时目录，保留文件名以便推导表名
+	tempDir, err := os.MkdirTemp("", "builder-upload-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	uploadPath := filepath.Join(tempDir, filepath.Base(fileName))
+	if err := os.WriteFile(uploadPath, content, 0644); err != nil {
+		return nil, err
+	}
+
+	result := &CheckResult{
+		Sheets:           make([]string, 0),
+		Errors:           make([]*model.ErrorInfo, 0),
+		PermissionErrors: make([]string, 0),
+	}
+
+	// 上传文件本身无法读取时作为验证错误返回
+	uploaded, err := builder.readFile(uploadPath)
+	if err != nil {
+		result.Errors = append(result.Errors, &model.ErrorInfo{
+			Sheet: fileName,
+			Msg:   strings.ReplaceAll(err.Error(), uploadPath, fileName),
+		})
+		return result, nil
+	}
+
+	projectSheets, err := builder.readRawSheets()
+	if err != nil {
+		return nil, fmt.Errorf("读取项目数据失败: %v", err)
+	}
+
+	// 检查列级写权限
+	uploadedNames := make(map[string]bool)
+	for _, sheet := range uploaded {
+		uploadedNames[sheet.Name] = true
+		result.Sheets = append(result.Sheets, sheet.Name)
+
+		var current *model.DataSheet
+		for _, projectSheet := range projectSheets {
+			if projectSheet.Name == sheet.Name {
+				current = projectSheet
+				break
+			}
+		}
+		for _, violation := range checker.CheckChanges(role, current, sheet) {
+			result.PermissionErrors = append(result.PermissionErrors, violation.Error())
+		}
+	}
+
+	// 用上传的表替换项目中的同名表
+	merged := make([]*model.DataSheet, 0, len(projectSheets)+len(uploaded))
+	for _, sheet := range projectSheets {
+		if !uploadedNames[sheet.Name] {
+			merged = append(merged, sheet)
+		}
+	}
+	merged = append(merged, uploaded...)
+
+	sheets, err := builder.prepareSheets(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Errors = append(result.Errors, builder.validateData(sheets)...)
+	result.OK = len(result.Errors) == 0 && len(result.PermissionErrors) == 0
+	return result, nil
+}
+
+// runServe 执行 serve 子命令，启动守护进程HTTP服务
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	addr := flags.String("addr", ":8080", "监听地址")
+	profile := flags.String("profile", os.Getenv(config.ProfileEnv), "环境配置，将 config.<profile>.json 合并到 config.json 上")
+	logOptions := addLogFlags(flags)
+	flags.Parse(args)
+	logOptions.apply()
+	if *profile != "" {
+		logger.Infof("使用环境配置: %s", *profile)
+	}
+
+	server := NewServer(*confDir, *profile)
+	logger.Infof("服务已启动: %s", *addr)
+	if err := http.ListenAndServe(*addr, server.Handler()); err != nil {
+		logger.Errorf("服务异常退出: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// checkRequest 构造上传 items.csv 的检查请求
+func checkRequest(t *testing.T, content, token string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "items.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	form.WriteField("role", "economy")
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/check", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("X-Role", "economy")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+// TestServeCheckRoleFromToken 测试提交者的角色只由配置的令牌确定，忽略客户端声明的角色
+func TestServeCheckRoleFromToken(t *testing.T) {
+	writeTestProject(t)
+	os.WriteFile("conf/permissions.json", []byte(`{
+		"sheets": {"items": {"name": ["economy"]}},
+		"tokens": {"economy-token": "economy"}
+	}`), 0644)
+	handler := NewServer("conf", "").Handler()
+	upload := "id,name,quality\n" +
+		"int,string,int\n" +
+		"ID|主键,名称,品质|引用:quality.id\n" +
+		"1,long sword,1\n" +
+		"2,shield,2\n"
+
+	cases := []struct {
+		token      string
+		code       int
+		violations int
+	}{
+		{token: "", code: http.StatusOK, violations: 1},
+		{token: "economy-token", code: http.StatusOK, violations: 0},
+		{token: "forged", code: http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, checkRequest(t, upload, c.token))
+		if recorder.Code != c.code {
+			t.Fatalf("token %q: expected status %d, got %d: %s", c.token, c.code, recorder.Code, recorder.Body.String())
+		}
+		if c.code != http.StatusOK {
+			continue
+		}
+
+		var result CheckResult
+		if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		if len(result.PermissionErrors) != c.violations {
+			t.Errorf("token %q: expected %d permission errors, got %v", c.token, c.violations, result.PermissionErrors)
+		}
+	}
+}
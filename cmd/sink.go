@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+	"github.com/game-data-builder/internal/remote"
+	"github.com/game-data-builder/internal/sink"
+)
+
+// dirSink 本地目录输出目标，与输出目录一样以事务方式写入并维护输出清单和版本文件
+type dirSink struct {
+	builder *Builder
+	root    string      // 目标目录
+	action  string      // 日志和进度条中的动作名
+	stale   stalePolicy // 过期文件的处理方式
+}
+
+// Name 目标目录
+func (s *dirSink) Name() string {
+	return s.root
+}
+
+// Write 写入目标目录，返回内容有变化的文件数量
+func (s *dirSink) Write(files []sink.File) (int, error) {
+	changed, err := s.builder.writeDir(s.root, files, s.action, s.stale)
+	return len(changed), err
+}
+
+// targetSink 输出目标及其输出的格式
+type targetSink struct {
+	sink     sink.ISink
+	formats  []string // 为空表示全部格式
+	optional bool     // 不可用时不中止构建，记录为降级构建
+}
+
+// outputSinks 输出目录之外的所有输出目标：游戏目录、remoteSync、syncTargets 和 sinks 中配置的目标；
+// 标记为 optional 的目标创建失败时记录为降级构建并跳过
+func (b *Builder) outputSinks() ([]targetSink, error) {
+	cfg := b.configManager.Config
+	targets := make([]targetSink, 0)
+
+	if cfg.SyncToGame && cfg.GameDir != "" {
+		// 镜像模式下无论是否指定 -prune 都清理游戏目录中的过期文件
+		policy := stalePolicy{remove: b.prune || cfg.GameMirror, trashDir: cfg.GameTrashDir}
+		targets = append(targets, targetSink{sink: &dirSink{builder: b, root: cfg.GameDir, action: "同步到游戏目录", stale: policy}})
+	}
+	if cfg.RemoteSync.Enabled {
+		syncer, err := remote.NewSyncer(cfg.RemoteSync)
+		if err != nil && !cfg.RemoteSync.Optional {
+			return nil, err
+		}
+		if err != nil {
+			b.degrade(subsystemUpload, cfg.RemoteSync.Host, err)
+		} else {
+			targets = append(targets, targetSink{sink: sink.NewSyncerSink(cfg.RemoteSync.Host, syncer), optional: cfg.RemoteSync.Optional})
+		}
+	}
+	for _, target := range cfg.SyncTargets {
+		name := fmt.Sprintf("%s://%s/%s", target.Type, target.Bucket, target.Prefix)
+		syncer, err := remote.NewTargetSyncer(b.withStateResumeDir(target))
+		if err != nil && !target.Optional {
+			return nil, err
+		}
+		if err != nil {
+			b.degrade(subsystemUpload, name, err)
+			continue
+		}
+		targets = append(targets, targetSink{sink: sink.NewSyncerSink(name, syncer), optional: target.Optional})
+	}
+
+	for i, sinkConfig := range cfg.Sinks {
+		var target sink.ISink
+		if sinkConfig.Type == config.SinkDir {
+			dir, _ := sinkConfig.Options["dir"].(string)
+			if dir == "" {
+				return nil, fmt.Errorf("第 %d 个输出目标: dir 输出目标必须配置 dir", i+1)
+			}
+			target = &dirSink{builder: b, root: dir, action: "同步到目录", stale: stalePolicy{remove: b.prune}}
+		} else {
+			switch sinkConfig.Type {
+			case "s3", "oss", "cos":
+				sinkConfig.Options = b.withStateCache(sinkConfig.Options, "resumeDir", stateUploadsDir)
+			}
+			created, err := sink.New(sinkConfig)
+			if err != nil && !sinkConfig.Optional {
+				return nil, fmt.Errorf("第 %d 个输出目标: %v", i+1, err)
+			}
+			if err != nil {
+				b.degrade(subsystemUpload, fmt.Sprintf("第 %d 个输出目标（%s）", i+1, sinkConfig.Type), err)
+				continue
+			}
+			target = created
+		}
+		targets = append(targets, targetSink{sink: target, formats: sinkConfig.Formats, optional: sinkConfig.Optional})
+	}
+	return targets, nil
+}
+
+// syncSinks 依次写入各输出目标，每个目标只接收其配置的格式；optional 目标写入失败时记录为降级构建并继续
+func (b *Builder) syncSinks(files []sink.File) error {
+	targets, err := b.outputSinks()
+	if err != nil {
+		return err
+	}
+	if b.syncDryRun {
+		return b.previewSinks(targets, files)
+	}
+
+	for _, target := range targets {
+		if err := b.buildContext().Err(); err != nil {
+			return err
+		}
+		count, err := target.sink.Write(sink.Filter(files, target.formats))
+		if err != nil && target.optional {
+			b.degrade(subsystemUpload, target.sink.Name(), err)
+			continue
+		}
+		if err != nil {
+			return &model.OutputError{Path: target.sink.Name(), Err: err}
+		}
+		logger.Infof("同步 %d 个文件到 %s", count, target.sink.Name())
+	}
+	return nil
+}
+
+// previewSinks 同步预览：按内容哈希比较将同步到各本地目录的文件与目录现有内容，列出新增、修改和删除的文件，不写入任何输出目标
+func (b *Builder) previewSinks(targets []targetSink, files []sink.File) error {
+	section := b.report.Section("同步预览")
+	for _, target := range targets {
+		dir, ok := target.sink.(*dirSink)
+		if !ok {
+			logger.Infof("[DRY-RUN] 跳过输出目标 %s：只能预览本地目录", target.sink.Name())
+			section.Addf("%s: 不是本地目录，未比较", target.sink.Name())
+			continue
+		}
+
+		diff, err := b.diffSinkDir(dir, sink.Filter(files, target.formats))
+		if err != nil {
+			return &model.OutputError{Path: dir.root, Err: err}
+		}
+		for _, relPath := range diff.Added {
+			logger.Infof("[DRY-RUN] 新增: %s", filepath.Join(dir.root, relPath))
+		}
+		for _, relPath := range diff.Updated {
+			logger.Infof("[DRY-RUN] 修改: %s", filepath.Join(dir.root, relPath))
+		}
+		for _, relPath := range diff.Deleted {
+			logger.Infof("[DRY-RUN] 删除: %s", filepath.Join(dir.root, relPath))
+		}
+		section.Addf("%s: 新增 %d，修改 %d，删除 %d，未变化 %d", dir.root, len(diff.Added), len(diff.Updated), len(diff.Deleted), diff.Unchanged)
+	}
+	return nil
+}
+
+// diffSinkDir 比较将写入目录的文件与目录现有内容；与实际同步一样，只有目录的过期文件策略要求清理且不是部分输出时才删除过期文件
+func (b *Builder) diffSinkDir(dir *dirSink, files []sink.File) (*output.DirDiff, error) {
+	pending := make([]output.File, 0, len(files))
+	generated := make([]string, 0, len(files))
+	for _, file := range files {
+		relPath := filepath.FromSlash(file.Path)
+		pending = append(pending, output.File{Path: relPath, Content: file.Content})
+		generated = append(generated, relPath)
+	}
+
+	removed := make([]string, 0)
+	if dir.stale.remove && !b.partialOutput() {
+		previous, err := output.LoadManifest(dir.root)
+		if err != nil {
+			return nil, err
+		}
+		removed = previous.Stale(generated)
+	}
+	return output.DiffDir(dir.root, pending, removed)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/game-data-builder/internal/config"
+)
+
+// 状态目录下的缓存子目录
+const (
+	stateSourcesDir = "sources" // 远程数据源的缓存
+	stateUploadsDir = "uploads" // 分片上传的进度
+)
+
+// statePath 状态文件的写入路径
+func (b *Builder) statePath(name string) string {
+	return b.configManager.Config.StatePath(b.confDir, name)
+}
+
+// stateReadPath 状态文件的读取路径：状态目录中还没有该文件时读取随配置提交的版本，
+// 使只读挂载的源码树在首次使用状态目录时沿用已有的表版本和锁文件
+func (b *Builder) stateReadPath(name string) string {
+	path := b.statePath(name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return filepath.Join(b.confDir, name)
+	}
+	return path
+}
+
+// writeState 保存状态文件，先创建状态目录
+func (b *Builder) writeState(name string, save func(path string) error) error {
+	path := b.statePath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return save(path)
+}
+
+// withStateCache 为选项补上状态目录下的缓存目录，选项中已配置 key 或未配置 stateDir 时原样返回
+func (b *Builder) withStateCache(options map[string]interface{}, key, name string) map[string]interface{} {
+	dir := b.configManager.Config.StateCacheDir(name)
+	if dir == "" {
+		return options
+	}
+	if _, exists := options[key]; exists {
+		return options
+	}
+
+	merged := make(map[string]interface{}, len(options)+1)
+	for k, v := range options {
+		merged[k] = v
+	}
+	merged[key] = dir
+	return merged
+}
+
+// withStateResumeDir 未配置 resumeDir 的对象存储目标将分片上传进度保存到状态目录
+func (b *Builder) withStateResumeDir(target config.SyncTarget) config.SyncTarget {
+	if target.ResumeDir == "" {
+		target.ResumeDir = b.configManager.Config.StateCacheDir(stateUploadsDir)
+	}
+	return target
+}
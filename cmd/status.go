@@ -0,0 +1,34 @@
+package main
+
+import (
+	"time"
+
+	"github.com/game-data-builder/internal/status"
+)
+
+// writeStatus 写入构建状态文件和徽章；构建失败时保留上一次成功构建的时间和版本，写入失败时记录为降级构建
+func (b *Builder) writeStatus(buildErr error) {
+	cfg := b.configManager.Config.Status
+	if !cfg.Enabled {
+		return
+	}
+	dir := cfg.Dir
+	if dir == "" {
+		dir = b.configManager.Config.OutputDir
+	}
+
+	current := status.New(buildErr, b.buildTime, time.Since(b.buildTime), b.outputVersion)
+	current.Degraded = b.Degraded()
+	if buildErr != nil {
+		previous, err := status.Load(dir)
+		if err != nil {
+			b.degrade(subsystemStatus, dir, err)
+		} else if previous != nil {
+			current.LastSuccess = previous.LastSuccess
+		}
+	}
+
+	if err := current.Write(dir, cfg.Label, b.configManager.Config.Fsync); err != nil {
+		b.degrade(subsystemStatus, dir, err)
+	}
+}
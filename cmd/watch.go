@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/devpush"
+	"github.com/game-data-builder/internal/lock"
+	"github.com/game-data-builder/internal/logger"
+)
+
+// Watcher 监听源文件和配置文件的变化并自动重新构建
+type Watcher struct {
+	confDir        string
+	pushAddr       string // 覆盖配置中的调试端地址
+	fullValidation bool   // 是否验证大表的全部行，默认只抽样验证
+	config         *config.ConfigManager
+	pusher         *devpush.Pusher
+	last           *lock.LockFile
+}
+
+// NewWatcher 创建监听器并加载配置，profile 为叠加的环境配置
+func NewWatcher(confDir, pushAddr, profile string) (*Watcher, error) {
+	w := &Watcher{
+		confDir:  confDir,
+		pushAddr: pushAddr,
+		config:   config.NewConfigManager(),
+	}
+	w.config.SetProfile(profile)
+
+	// 配置变更时更新推送器，推送器在多次构建间复用，只推送内容变化的文件
+	w.config.Subscribe(w.updatePusher)
+	if err := w.config.Load(confDir); err != nil {
+		return nil, fmt.Errorf("加载配置失败: %v", err)
+	}
+	return w, nil
+}
+
+// updatePusher 根据配置创建或更新推送器
+func (w *Watcher) updatePusher(snapshot *config.ConfigManager) {
+	addr := w.pushAddr
+	if addr == "" {
+		addr = snapshot.Config.DevPush.Addr
+	}
+	if addr == "" {
+		w.pusher = nil
+		return
+	}
+	if w.pusher != nil && w.pusher.Addr() == addr {
+		return
+	}
+
+	timeout := time.Duration(snapshot.Config.DevPush.TimeoutMs) * time.Millisecond
+	w.pusher = devpush.NewPusher(addr, timeout)
+}
+
+// newBuilder 基于当前配置快照创建构建器
+func (w *Watcher) newBuilder() *Builder {
+	builder := NewBuilder()
+	builder.confDir = w.confDir
+	builder.configManager = w.config.Snapshot()
+	builder.pusher = w.pusher
+	builder.sampleValidation = !w.fullValidation
+	return builder
+}
+
+// Poll 检查输入是否变化，首次调用或有变化时重新构建
+func (w *Watcher) Poll() error {
+	return w.PollContext(context.Background())
+}
+
+// PollContext 同 Poll，ctx 取消时中止正在进行的构建
+func (w *Watcher) PollContext(ctx context.Context) error {
+	builder := w.newBuilder()
+	current, err := builder.buildLock()
+	if err != nil {
+		return fmt.Errorf("计算输入哈希失败: %v", err)
+	}
+	if w.last != nil && reflect.DeepEqual(w.last.Sources, current.Sources) &&
+		reflect.DeepEqual(w.last.Configs, current.Configs) {
+		return nil
+	}
+
+	// 配置文件变化时重新加载，校验失败时保留原配置；源文件未变且只修改了不影响构建的设置时不重新构建
+	configChanged := w.last != nil && !reflect.DeepEqual(w.last.Configs, current.Configs)
+	sourcesChanged := w.last == nil || !reflect.DeepEqual(w.last.Sources, current.Sources)
+	w.last = current
+	if configChanged {
+		previous := w.config.Snapshot()
+		if err := w.config.Reload(); err != nil {
+			return fmt.Errorf("重新加载配置失败，继续使用原配置: %v", err)
+		}
+		logger.Infof("配置已重新加载")
+		if !sourcesChanged && !buildSettingsChanged(previous, w.config.Snapshot()) {
+			logger.Infof("修改的配置不影响构建结果，不重新构建")
+			return nil
+		}
+		builder = w.newBuilder()
+	}
+
+	return builder.BuildContext(ctx)
+}
+
+// runWatch 执行 watch 子命令
+func runWatch(args []string) {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	confDir := flags.String("conf", "./conf", "配置文件目录")
+	interval := flags.Duration("interval", time.Second, "检查文件变化的间隔")
+	push := flags.String("push", "", "游戏调试端地址，覆盖配置中的 devPush.addr")
+	fullValidation := flags.Bool("full-validation", false, "验证大表的全部行，默认只抽样验证")
+	profile := flags.String("profile", os.Getenv(config.ProfileEnv), "环境配置，将 config.<profile>.json 合并到 config.json 上")
+	logOptions := addLogFlags(flags)
+	flags.Parse(args)
+	logOptions.apply()
+	if *profile != "" {
+		logger.Infof("使用环境配置: %s", *profile)
+	}
+
+	watcher, err := NewWatcher(*confDir, *push, *profile)
+	if err != nil {
+		logger.Errorf("%v", err)
+		os.Exit(1)
+	}
+	watcher.fullValidation = *fullValidation
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Infof("开始监听文件变化，间隔 %v", *interval)
+	for {
+		if err := watcher.PollContext(ctx); err != nil && ctx.Err() == nil {
+			logger.Errorf("构建失败: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Infof("已停止监听")
+			return
+		case <-time.After(*interval):
+		}
+	}
+}
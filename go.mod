@@ -4,15 +4,26 @@ go 1.24.0
 
 toolchain go1.24.11
 
-require github.com/xuri/excelize/v2 v2.10.0
+require (
+	github.com/go-sql-driver/mysql v1.9.3
+	github.com/google/flatbuffers v25.2.10+incompatible
+	github.com/klauspost/compress v1.18.0
+	github.com/mattn/go-sqlite3 v1.14.32
+	github.com/richardlehane/mscfb v1.0.4
+	github.com/xuri/excelize/v2 v2.10.0
+	google.golang.org/grpc v1.80.0
+	google.golang.org/protobuf v1.36.11
+)
 
 require (
-	github.com/richardlehane/mscfb v1.0.4 // indirect
+	filippo.io/edwards25519 v1.1.0 // indirect
 	github.com/richardlehane/msoleps v1.0.4 // indirect
 	github.com/tiendc/go-deepcopy v1.7.1 // indirect
 	github.com/xuri/efp v0.0.1 // indirect
 	github.com/xuri/nfp v0.0.2-0.20250530014748-2ddeb826f9a9 // indirect
-	golang.org/x/crypto v0.43.0 // indirect
-	golang.org/x/net v0.46.0 // indirect
-	golang.org/x/text v0.30.0 // indirect
+	golang.org/x/crypto v0.47.0 // indirect
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260120221211-b8f7ae30c516 // indirect
 )
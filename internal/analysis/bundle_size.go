@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// BundleEstimate 单张表在某个格式下的包体占用预估
+type BundleEstimate struct {
+	Sheet          string // 表名，汇总文件为空
+	Format         string // 格式类型
+	Rows           int    // 数据行数
+	RawSize        int    // 原始字节数
+	CompressedSize int    // 压缩后字节数
+}
+
+// PerRow 平均每行压缩后的字节数
+func (e BundleEstimate) PerRow() int {
+	if e.Rows == 0 {
+		return 0
+	}
+	return e.CompressedSize / e.Rows
+}
+
+// EstimateBundleSizes 按 gzip 压缩后的大小预估每张表在各格式下的包体占用，顺序与转换结果一致
+func EstimateBundleSizes(sheets []*model.DataSheet, results []*model.ConvertResult) ([]BundleEstimate, error) {
+	rowCounts := make(map[string]int)
+	for _, sheet := range sheets {
+		rowCounts[sheet.Name] = len(sheet.Rows)
+	}
+
+	estimates := make([]BundleEstimate, 0, len(results))
+	for _, result := range results {
+		compressed, err := compressedSize(result.Content)
+		if err != nil {
+			return nil, fmt.Errorf("压缩 %s 失败: %v", result.FileName, err)
+		}
+
+		estimates = append(estimates, BundleEstimate{
+			Sheet:          result.Sheet,
+			Format:         result.Format,
+			Rows:           rowCounts[result.Sheet],
+			RawSize:        len(result.Content),
+			CompressedSize: compressed,
+		})
+	}
+	return estimates, nil
+}
+
+// compressedSize 计算内容以最高压缩级别 gzip 压缩后的字节数
+func compressedSize(content []byte) (int, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+	return buf.Len(), nil
+}
+
+// FormatSize 将字节数格式化为易读的大小
+func FormatSize(size int) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/1024/1024)
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
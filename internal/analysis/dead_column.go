@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// UsageManifest 消费方字段使用清单（由客户端/服务器代码生成导出）
+type UsageManifest struct {
+	Consumer string              `json:"consumer"` // 消费方名称
+	Sheets   map[string][]string `json:"sheets"`   // 表名 -> 实际读取的列
+}
+
+// DeadColumn 未被任何消费方读取的列
+type DeadColumn struct {
+	Sheet  string // 表名
+	Column string // 列名
+}
+
+// LoadUsageManifests 按通配符加载所有字段使用清单
+func LoadUsageManifests(patterns []string) ([]*UsageManifest, error) {
+	manifests := make([]*UsageManifest, 0)
+	for _, pattern := range patterns {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			var manifest UsageManifest
+			if err := json.Unmarshal(content, &manifest); err != nil {
+				return nil, err
+			}
+			if manifest.Consumer == "" {
+				manifest.Consumer = filepath.Base(path)
+			}
+			manifests = append(manifests, &manifest)
+		}
+	}
+	return manifests, nil
+}
+
+// FindDeadColumns 找出没有任何消费方读取的列，以及完全未被引用的表
+func FindDeadColumns(sheets []*model.DataSheet, manifests []*UsageManifest) ([]DeadColumn, []string) {
+	// 汇总所有消费方读取的字段
+	used := make(map[string]map[string]bool)
+	for _, manifest := range manifests {
+		for sheetName, columns := range manifest.Sheets {
+			if used[sheetName] == nil {
+				used[sheetName] = make(map[string]bool)
+			}
+			for _, column := range columns {
+				used[sheetName][column] = true
+			}
+		}
+	}
+
+	deadColumns := make([]DeadColumn, 0)
+	unusedSheets := make([]string, 0)
+	for _, sheet := range sheets {
+		usedColumns, exists := used[sheet.Name]
+		if !exists {
+			unusedSheets = append(unusedSheets, sheet.Name)
+			continue
+		}
+
+		for _, col := range sheet.Columns {
+			if !usedColumns[col.Name] {
+				deadColumns = append(deadColumns, DeadColumn{Sheet: sheet.Name, Column: col.Name})
+			}
+		}
+	}
+
+	sort.Strings(unusedSheets)
+	return deadColumns, unusedSheets
+}
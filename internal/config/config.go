@@ -2,39 +2,182 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/game-data-builder/internal/model"
 )
 
 // Config 主配置结构
 type Config struct {
-	SourceDir  string                     `json:"sourceDir"`  // 源文件目录
-	OutputDir  string                     `json:"outputDir"`  // 输出目录
-	Formats    []string                   `json:"formats"`    // 转换格式
-	Async      bool                       `json:"async"`      // 是否异步处理
-	FastMode   bool                       `json:"fastMode"`   // 快速模式
-	SyncToGame bool                       `json:"syncToGame"` // 是否同步到游戏目录
-	GameDir    string                     `json:"gameDir"`    // 游戏目录
-	Readers    map[string]ReaderConfig    `json:"readers"`    // 读取器配置
-	Converters map[string]ConverterConfig `json:"converters"` // 转换器配置
-	Validators map[string]ValidatorConfig `json:"validators"` // 验证器配置
+	SourceDir     string                     `json:"sourceDir"`     // 源文件目录
+	Namespace     string                     `json:"namespace"`     // sourceDir 中的表使用的命名空间，为空表示不加前缀
+	SourceRoots   []SourceRootConfig         `json:"sourceRoots"`   // 额外的源文件目录
+	Include       []string                   `json:"include"`       // 只读取匹配这些模式的源文件，为空时读取全部
+	Exclude       []string                   `json:"exclude"`       // 跳过匹配这些模式的源文件，如 ~$*.xlsx
+	ExcludeSheets []string                   `json:"excludeSheets"` // 跳过名称匹配这些模式的表，如 Draft*
+	OutputDir     string                     `json:"outputDir"`     // 输出目录
+	StateDir      string                     `json:"stateDir"`      // 构建器写入的状态文件目录，为空时沿用配置目录和 .builder-cache
+	Layout        string                     `json:"layout"`        // 输出目录结构：files（默认）或 cas
+	Retain        int                        `json:"retain"`        // cas 结构下保留的历史版本数，0 表示全部保留
+	OutputWorkers int                        `json:"outputWorkers"` // 并发写入输出文件的数量，0 表示使用 CPU 核数
+	Fsync         bool                       `json:"fsync"`         // 写入输出文件后同步到磁盘，适用于网络文件系统
+	Formats       []string                   `json:"formats"`       // 转换格式
+	Async         bool                       `json:"async"`         // 是否异步处理
+	MaxWorkers    int                        `json:"maxWorkers"`    // 异步处理时的工作协程数，0 表示使用 CPU 核数
+	FastMode      bool                       `json:"fastMode"`      // 快速模式
+	SyncToGame    bool                       `json:"syncToGame"`    // 是否同步到游戏目录
+	GameDir       string                     `json:"gameDir"`       // 游戏目录
+	GameMirror    bool                       `json:"gameMirror"`    // 镜像模式：同步到游戏目录时清理本次构建不再生成的文件
+	GameTrashDir  string                     `json:"gameTrashDir"`  // 镜像模式下过期文件移入的回收目录，为空时直接删除
+	Readers       map[string]ReaderConfig    `json:"readers"`       // 读取器配置
+	Converters    map[string]ConverterConfig `json:"converters"`    // 转换器配置
+	Validators    map[string]ValidatorConfig `json:"validators"`    // 验证器配置
+	Analysis      AnalysisConfig             `json:"analysis"`      // 分析配置
+	DevPush       DevPushConfig              `json:"devPush"`       // 开发模式推送配置
+	Sampling      SamplingConfig             `json:"sampling"`      // 监听模式下大表的抽样验证
+	IDSpaces      map[string]IDSpaceConfig   `json:"idSpaces"`      // 主键需要跨表唯一的 ID 空间
+	Assets        AssetConfig                `json:"assets"`        // 资源列的文件存在性检查
+	RemoteSync    RemoteSyncConfig           `json:"remoteSync"`    // 远程同步配置
+	SyncTargets   []SyncTarget               `json:"syncTargets"`   // 对象存储同步目标
+	Sinks         []SinkConfig               `json:"sinks"`         // 额外的输出目标
+	Webhooks      []WebhookConfig            `json:"webhooks"`      // 构建完成通知
+	Imports       []ImportConfig             `json:"imports"`       // 从其他项目导入的共享表
+	Status        StatusConfig               `json:"status"`        // 构建状态文件和徽章
+}
+
+// SourceRootConfig 额外的源文件目录
+type SourceRootConfig struct {
+	Dir       string `json:"dir"`       // 源文件目录
+	Namespace string `json:"namespace"` // 命名空间，表名加上 "<namespace>." 前缀，输出到同名子目录
+}
+
+// AllSourceRoots 所有源文件目录，sourceDir 排在最前
+func (c *Config) AllSourceRoots() []SourceRootConfig {
+	roots := []SourceRootConfig{{Dir: c.SourceDir, Namespace: c.Namespace}}
+	return append(roots, c.SourceRoots...)
+}
+
+// MatchSourceFile 源文件是否参与构建，relPath 为相对源文件目录的路径；
+// 不含 / 的模式匹配文件名，含 / 的模式匹配整个相对路径，同时匹配 include 和 exclude 时跳过
+func (c *Config) MatchSourceFile(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	if len(c.Include) > 0 && !matchAnyPattern(c.Include, relPath) {
+		return false
+	}
+	return !matchAnyPattern(c.Exclude, relPath)
+}
+
+// MatchSheet 表是否参与构建，name 为源文件中的表名（不含命名空间）
+func (c *Config) MatchSheet(name string) bool {
+	return !matchAnyPattern(c.ExcludeSheets, name)
 }
 
+// matchAnyPattern 名称或路径是否匹配任一 glob 模式
+func matchAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		target := name
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(name)
+		}
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// StatePath 构建器写入的状态文件（表版本、锁文件）路径：配置了 stateDir 时位于状态目录，否则位于配置目录
+func (c *Config) StatePath(confDir, name string) string {
+	if c.StateDir != "" {
+		return filepath.Join(c.StateDir, name)
+	}
+	return filepath.Join(confDir, name)
+}
+
+// StateCacheDir 状态目录下的缓存目录，未配置 stateDir 时返回空字符串，由各模块使用自己的默认目录
+func (c *Config) StateCacheDir(name string) string {
+	if c.StateDir == "" {
+		return ""
+	}
+	return filepath.Join(c.StateDir, name)
+}
+
+// 输出目录结构
+const (
+	LayoutFiles = "files" // 按路径直接写入文件
+	LayoutCAS   = "cas"   // 内容寻址存储，按版本发布并通过 latest 指向当前版本
+)
+
 // ReaderConfig 读取器配置
+//
+// 源文件的读取选项由 default、以扩展名为名的配置（如 csv、xlsx）和 files 匹配该文件的配置依次合并，后者覆盖前者的同名选项
 type ReaderConfig struct {
 	Type    string                 `json:"type"`    // 读取器类型
 	Enabled bool                   `json:"enabled"` // 是否启用
+	Files   []string               `json:"files"`   // 只用于匹配这些模式的源文件，规则与 include 相同
 	Options map[string]interface{} `json:"options"` // 选项
 }
 
+// ReaderDefault 所有源文件共用的读取器配置名
+const ReaderDefault = "default"
+
+// ReaderDB 数据库读取器类型，按选项中的连接串读取配置的表，不对应源文件
+const ReaderDB = "db"
+
 // ConverterConfig 转换器配置
 type ConverterConfig struct {
-	Type       string                 `json:"type"`       // 转换器类型
-	Enabled    bool                   `json:"enabled"`    // 是否启用
-	OutputPath string                 `json:"outputPath"` // 输出路径
-	Options    map[string]interface{} `json:"options"`    // 选项
+	Type          string                 `json:"type"`          // 转换器类型
+	Enabled       bool                   `json:"enabled"`       // 是否启用
+	OutputPath    string                 `json:"outputPath"`    // 输出路径
+	Options       map[string]interface{} `json:"options"`       // 选项
+	OnMissingTool string                 `json:"onMissingTool"` // 外部工具缺失时的处理策略
+	Fallback      string                 `json:"fallback"`      // 策略为 fallback 时使用的替代转换器
+	MaxWorkers    int                    `json:"maxWorkers"`    // 该转换器同时处理的表数上限，0 表示不限制
+
+	SheetOverrides map[string]SheetOverride `json:"sheetOverrides"` // 表名 -> 该表覆盖的输出路径和选项
+}
+
+// SheetOverride 单张表覆盖的转换器配置
+type SheetOverride struct {
+	OutputPath string                 `json:"outputPath"` // 输出路径，为空时使用转换器的 outputPath
+	Options    map[string]interface{} `json:"options"`    // 与转换器选项合并，覆盖同名选项
+}
+
+// ForSheet 指定表使用的转换器配置，合并 sheetOverrides 中该表的输出路径和选项；没有覆盖时返回自身
+func (c *ConverterConfig) ForSheet(sheet string) *ConverterConfig {
+	override, exists := c.SheetOverrides[sheet]
+	if !exists {
+		return c
+	}
+
+	merged := *c
+	if override.OutputPath != "" {
+		merged.OutputPath = override.OutputPath
+	}
+	merged.Options = make(map[string]interface{}, len(c.Options)+len(override.Options))
+	for key, value := range c.Options {
+		merged.Options[key] = value
+	}
+	for key, value := range override.Options {
+		merged.Options[key] = value
+	}
+	return &merged
 }
 
+// 外部工具缺失时的处理策略
+const (
+	MissingToolFail     = "fail"     // 构建失败（默认）
+	MissingToolSkip     = "skip"     // 跳过该格式并记录到构建报告
+	MissingToolFallback = "fallback" // 改用替代转换器
+)
+
 // ValidatorConfig 验证器配置
 type ValidatorConfig struct {
 	Type    string                 `json:"type"`    // 验证器类型
@@ -42,6 +185,130 @@ type ValidatorConfig struct {
 	Options map[string]interface{} `json:"options"` // 选项
 }
 
+// AnalysisConfig 分析配置
+type AnalysisConfig struct {
+	UsageManifests []string `json:"usageManifests"` // 消费方字段使用清单（支持通配符）
+	BundleForecast bool     `json:"bundleForecast"` // 是否预估每张表在各格式下的包体占用
+}
+
+// SamplingConfig 监听模式下大表的抽样验证配置，完整构建始终验证全部行
+type SamplingConfig struct {
+	Threshold int `json:"threshold"` // 行数超过该值的表才抽样，默认 5000
+	Head      int `json:"head"`      // 始终验证的前若干行，默认 500
+	Random    int `json:"random"`    // 从其余行中随机抽取的行数，默认 500
+}
+
+// IDSpaceConfig ID 空间，其中所有表的主键必须全局唯一，如所有物品类表的 id
+type IDSpaceConfig struct {
+	Sheets map[string]IDRange `json:"sheets"` // 表名或通配符模式 -> 该表主键的取值范围，与表名完全相同的配置优先
+}
+
+// IDRange 主键的取值范围，包含两端；min 和 max 都为 0 时不限定范围
+type IDRange struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+// Limited 是否限定了范围
+func (r IDRange) Limited() bool {
+	return r.Min != 0 || r.Max != 0
+}
+
+// AssetConfig 资源存在性检查配置，注释中带 资源:<类型> 的列的值必须是资源目录中存在的文件；未配置 roots 时不检查
+type AssetConfig struct {
+	Roots []string             `json:"roots"` // 项目资源目录，按顺序查找
+	Types map[string]AssetType `json:"types"` // 资源类型 -> 查找方式，未配置的类型直接在资源目录下查找
+}
+
+// AssetType 一种资源的查找方式
+type AssetType struct {
+	Dir        string   `json:"dir"`        // 资源所在的子目录，相对每个资源目录
+	Extensions []string `json:"extensions"` // 值不是已存在的文件时依次追加的扩展名，如 [".prefab"]
+}
+
+// DevPushConfig 开发模式推送配置
+type DevPushConfig struct {
+	Addr      string `json:"addr"`      // 游戏调试端地址（host:port）
+	TimeoutMs int    `json:"timeoutMs"` // 连接和写入超时（毫秒）
+}
+
+// RemoteSyncConfig 远程同步配置
+type RemoteSyncConfig struct {
+	Enabled        bool   `json:"enabled"`        // 是否同步到远程服务器
+	Type           string `json:"type"`           // 同步方式，目前支持 sftp（默认）
+	Host           string `json:"host"`           // 服务器地址
+	Port           int    `json:"port"`           // 端口，默认 22
+	User           string `json:"user"`           // 登录用户
+	KeyFile        string `json:"keyFile"`        // 私钥文件
+	RemoteDir      string `json:"remoteDir"`      // 远程目录
+	Retries        int    `json:"retries"`        // 最大尝试次数，默认 3
+	RetryBackoffMs int    `json:"retryBackoffMs"` // 首次重试前的等待时间（毫秒），之后每次翻倍，默认 1000
+	Parallel       int    `json:"parallel"`       // 并发上传的连接数，默认 1
+	Optional       bool   `json:"optional"`       // 同步失败时不中止构建，记录为降级构建
+}
+
+// SyncTarget 对象存储同步目标
+type SyncTarget struct {
+	Type         string `json:"type"`         // 服务商：s3、oss 或 cos
+	Endpoint     string `json:"endpoint"`     // 接口地址，为空时按服务商和地域生成
+	Region       string `json:"region"`       // 地域
+	Bucket       string `json:"bucket"`       // 存储桶
+	Prefix       string `json:"prefix"`       // 对象键前缀
+	AccessKeyEnv string `json:"accessKeyEnv"` // 保存访问密钥 ID 的环境变量
+	SecretKeyEnv string `json:"secretKeyEnv"` // 保存访问密钥的环境变量
+	CacheControl string `json:"cacheControl"` // 上传时设置的 Cache-Control 头
+	PathStyle    bool   `json:"pathStyle"`    // 是否使用路径形式的地址（如自建的 S3 兼容服务）
+	Parallel     int    `json:"parallel"`     // 并发上传数，默认 4
+
+	Retries            int    `json:"retries"`            // 单个请求的最大尝试次数，默认 3
+	RetryBackoffMs     int    `json:"retryBackoffMs"`     // 首次重试前的等待时间（毫秒），之后每次翻倍，默认 1000
+	MultipartThreshold int    `json:"multipartThreshold"` // 超过该大小（MB）的文件使用分片上传，默认 64
+	PartSize           int    `json:"partSize"`           // 分片大小（MB），默认 8，最小 5
+	PartParallel       int    `json:"partParallel"`       // 单个文件并发上传的分片数，默认 4
+	MaxBandwidth       int    `json:"maxBandwidth"`       // 所有上传共享的带宽上限（KB/s），0 表示不限制
+	ResumeDir          string `json:"resumeDir"`          // 保存分片上传进度的目录，默认为系统临时目录下的 game-data-builder-uploads
+	Optional           bool   `json:"optional"`           // 同步失败时不中止构建，记录为降级构建
+}
+
+// SinkConfig 输出目标配置
+type SinkConfig struct {
+	Type     string                 `json:"type"`     // 类型：dir、archive、http、redis、sftp、s3、oss 或 cos
+	Formats  []string               `json:"formats"`  // 输出的格式，为空表示全部格式
+	Options  map[string]interface{} `json:"options"`  // 选项，sftp 和对象存储的选项与 remoteSync、syncTargets 相同
+	Optional bool                   `json:"optional"` // 写入失败时不中止构建，记录为降级构建
+}
+
+// 输出目标类型
+const (
+	SinkDir     = "dir"     // 本地目录，与 outputDir 一样以事务方式写入
+	SinkArchive = "archive" // 打成一个 zip 或 tar.gz 归档
+	SinkHTTP    = "http"    // 逐个文件 PUT 到 HTTP 服务
+	SinkRedis   = "redis"   // 写入 Redis，每个文件一个键
+	SinkSFTP    = "sftp"    // 通过 sftp 上传到远程服务器
+)
+
+// StatusConfig 构建状态文件配置，每次构建后写入 status.json 和 status.svg 徽章
+type StatusConfig struct {
+	Enabled bool   `json:"enabled"` // 是否写入状态文件
+	Dir     string `json:"dir"`     // 状态文件目录，默认为 outputDir
+	Label   string `json:"label"`   // 徽章左侧的文字，默认 data
+}
+
+// WebhookConfig 构建完成通知配置
+type WebhookConfig struct {
+	URL    string `json:"url"`    // 通知地址
+	Format string `json:"format"` // 消息格式：json（默认）、slack、feishu 或 dingtalk
+	When   string `json:"when"`   // 发送时机：always（默认）、change 或 failure
+}
+
+// ImportConfig 从其他项目的构建输出导入共享表
+type ImportConfig struct {
+	From      string                 `json:"from"`      // 对方的输出目录或 HTTP(S) 地址，需包含 version.json
+	Sheets    map[string]int         `json:"sheets"`    // 导入的表 -> 固定的数据版本，0 表示使用最新版本
+	Namespace string                 `json:"namespace"` // 导入的表使用的命名空间，为空表示不加前缀
+	Options   map[string]interface{} `json:"options"`   // 远程获取的重试选项
+}
+
 // CombineConfig 合并配置
 type CombineConfig struct {
 	Sheets map[string]CombineSheet `json:"sheets"` // 合并表配置
@@ -49,41 +316,598 @@ type CombineConfig struct {
 
 // CombineSheet 合并表配置
 type CombineSheet struct {
-	SourceSheets []string `json:"sourceSheets"` // 源表列表
-	KeyColumn    string   `json:"keyColumn"`    // 主键列
-	OutputName   string   `json:"outputName"`   // 输出表名
+	SourceSheets []string                     `json:"sourceSheets"` // 源表列表
+	KeyColumn    string                       `json:"keyColumn"`    // 主键列
+	OutputName   string                       `json:"outputName"`   // 输出表名
+	Where        map[string]string            `json:"where"`        // 源表 -> 行过滤表达式，只合并表达式为真的行
+	Columns      map[string]map[string]string `json:"columns"`      // 源表 -> 列名映射（源表列名 -> 合并表列名）
+
+	ColumnMode     string `json:"columnMode"`     // 列的合并方式：union（默认，取并集）或 strict（各源表映射后的列必须相同）
+	OnDuplicateKey string `json:"onDuplicateKey"` // 主键重复时的处理：error（默认）、first（保留先出现的行）或 last（保留后出现的行）
+
+	Join *CombineJoin `json:"join"` // 按键横向连接源表的列，为空时纵向合并各源表的行
 }
 
+// CombineJoin 横向连接配置，第一个源表为左表，其余源表按连接键把列连接到左表的行上
+type CombineJoin struct {
+	Type string            `json:"type"` // left（默认，保留左表所有行）或 inner（只保留在所有源表中都有匹配的行）
+	On   map[string]string `json:"on"`   // 源表 -> 连接列（映射后的列名），与左表的主键匹配，未配置时使用合并表的主键列名
+}
+
+// 横向连接类型
+const (
+	CombineJoinLeft  = "left"
+	CombineJoinInner = "inner"
+)
+
+// 合并表的列合并方式
+const (
+	CombineColumnsUnion  = "union"
+	CombineColumnsStrict = "strict"
+)
+
+// 合并表主键重复时的处理方式
+const (
+	CombineDuplicateError = "error"
+	CombineDuplicateFirst = "first"
+	CombineDuplicateLast  = "last"
+)
+
 // ReplaceColumnConfig 列替换配置
 type ReplaceColumnConfig struct {
-	Sheets map[string]ReplaceRules `json:"sheets"` // 表替换规则
+	Sheets map[string]ReplaceRules `json:"sheets"` // 表名或通配符模式 -> 表替换规则
 }
 
 // ReplaceRules 替换规则
 type ReplaceRules struct {
-	Columns map[string]ReplaceRule `json:"columns"` // 列替换规则
+	Columns map[string]ReplaceRule `json:"columns"` // 列名或通配符模式 -> 列替换规则
 }
 
 // ReplaceRule 替换规则
 type ReplaceRule struct {
-	From string `json:"from"` // 原内容
-	To   string `json:"to"`   // 替换内容
+	From  string `json:"from"`  // 原内容，为空时替换整个值
+	To    string `json:"to"`    // 替换内容，可以用 {{.列名}} 引用同一行其他列的值
+	Regex bool   `json:"regex"` // from 是否为正则表达式，为 true 时 to 中可以用 $1、${name} 引用捕获组
+}
+
+// FrozenConfig 冻结配置，发布窗口内锁定的表
+type FrozenConfig struct {
+	Mode      string                 `json:"mode"`                // 内容变化时的处理方式：fail（默认）或 warn
+	Sheets    map[string]FrozenSheet `json:"sheets"`              // 冻结的表
+	Approvers map[string]string      `json:"approvers,omitempty"` // 批准人 -> 批准令牌的 SHA-256（十六进制）
+}
+
+// FrozenSheet 冻结表配置
+type FrozenSheet struct {
+	Hash      string     `json:"hash"`                // 冻结时的内容哈希
+	Reason    string     `json:"reason"`              // 冻结原因
+	Approvals []Approval `json:"approvals,omitempty"` // 已批准的内容变更
 }
 
+// Approval 冻结表内容变更的批准记录
+type Approval struct {
+	Hash string `json:"hash"` // 批准的内容哈希
+	By   string `json:"by"`   // 批准人，须在 approvers 中
+}
+
+// PermissionConfig 列级写权限配置，用于编辑前端通过接口提交的修改
+type PermissionConfig struct {
+	Default string                         `json:"default"` // 未配置列的默认策略：allow（默认）或 deny
+	Sheets  map[string]map[string][]string `json:"sheets"`  // 表名 -> 列名（* 表示其余列）-> 允许写入的角色
+	Tokens  map[string]string              `json:"tokens"`  // 访问令牌 -> 角色，提交者的角色只从令牌确定
+}
+
+// TransformConfig 转换前处理配置
+type TransformConfig struct {
+	Transforms []TransformRule `json:"transforms"` // 按顺序执行的处理步骤
+}
+
+// TransformRule 单个处理步骤
+type TransformRule struct {
+	Sheets     []string `json:"sheets"`     // 适用的表名（支持通配符）
+	Type       string   `json:"type"`       // 类型：expr（表达式）或 command（外部命令）
+	Column     string   `json:"column"`     // expr：写入的列名
+	ColumnType string   `json:"columnType"` // expr：新列的数据类型
+	Expr       string   `json:"expr"`       // expr：表达式
+	Command    []string `json:"command"`    // command：命令及参数，通过标准输入输出交换JSON
+	TimeoutMs  int      `json:"timeoutMs"`  // command：超时时间（毫秒）
+}
+
+// ConstantsConfig 常量配置，constants.json 中的常量组成一个只有一行的虚拟表，每个常量是其中的一列
+type ConstantsConfig struct {
+	Sheet     string          `json:"sheet"`     // 表名，默认为 constants
+	Constants []ConstantEntry `json:"constants"` // 常量列表，按顺序生成列
+}
+
+// ConstantEntry 单个常量
+type ConstantEntry struct {
+	Key      string      `json:"key"`      // 常量名，即列名
+	Type     string      `json:"type"`     // 数据类型，与表格中的列类型相同
+	Value    interface{} `json:"value"`    // 常量值
+	Comment  string      `json:"comment"`  // 注释
+	Required bool        `json:"required"` // 是否必填
+	Ref      string      `json:"ref"`      // 引用的表和列，如 items.id
+}
+
+// SheetsConfig 表级配置，键为表名（支持通配符）
+type SheetsConfig struct {
+	Sheets map[string]SheetSettings `json:"sheets"`
+}
+
+// SheetSettings 单个表的配置
+type SheetSettings struct {
+	Tags []string `json:"tags"` // 表标签，与表元数据中的 tags 合并
+	Key  string   `json:"key"`  // 主键列名，覆盖注释中的主键标记，未指定时使用第一列
+
+	Derived []DerivedColumn `json:"derived"` // 由表达式计算的派生列，按顺序追加到表的末尾
+	Sort    []string        `json:"sort"`    // 行排序键，列名前加 - 表示降序，如 ["-price", "id"]，覆盖表元数据中的排序
+
+	Whitelists map[string]string `json:"whitelists"` // 列名 -> 白名单文件（相对配置目录），列中的值必须在白名单中
+}
+
+// DerivedColumn 派生列，每行按表达式计算值，与表格中的列一样参与验证和转换
+type DerivedColumn struct {
+	Name    string `json:"name"`    // 列名，不能与表中已有的列重名
+	Type    string `json:"type"`    // 数据类型，表达式的结果转换为该类型
+	Expr    string `json:"expr"`    // 表达式，可以引用同一行的列和之前定义的派生列
+	Comment string `json:"comment"` // 注释
+}
+
+// ColumnGroupsConfig 共享列组配置，表通过元数据 include:reward 引入列组中的列定义
+type ColumnGroupsConfig struct {
+	Groups map[string][]ColumnGroupEntry `json:"groups"` // 列组名 -> 列定义
+}
+
+// ColumnGroupEntry 列组中的单个列定义
+type ColumnGroupEntry struct {
+	Name     string      `json:"name"`     // 列名，可以是 reward.itemId 形式的嵌套列
+	Type     string      `json:"type"`     // 数据类型，与表格中的列类型相同
+	Comment  string      `json:"comment"`  // 注释
+	Required bool        `json:"required"` // 是否必填，必填列必须出现在引入列组的表中
+	Default  interface{} `json:"default"`  // 默认值
+	Options  []string    `json:"options"`  // 可选值
+	Ref      string      `json:"ref"`      // 引用的表和列，如 items.id
+}
+
+// SourcesConfig 远程源文件配置（sources.json），下载后按内容类型交给对应的读取器
+type SourcesConfig struct {
+	Sources []URLSource            `json:"sources"` // 远程源文件
+	Options map[string]interface{} `json:"options"` // 下载的重试选项，与远程数据源的读取器选项相同
+}
+
+// URLSource 以 HTTP(S) 地址发布的源文件
+type URLSource struct {
+	URL       string `json:"url"`       // 下载地址
+	Name      string `json:"name"`      // 保存的文件名，决定使用的读取器和单表文件的表名；为空时由响应头或地址推断
+	Namespace string `json:"namespace"` // 文件中的表使用的命名空间
+}
+
+// FiltersConfig 行过滤配置（filters.json），在验证和转换前去除匹配的行，用于保留在表格中但不再使用的行
+type FiltersConfig struct {
+	Filters map[string][]string `json:"filters"` // 表名或通配符模式 -> 过滤条件，行满足任一条件时被去除
+}
+
+// DefaultConstantsSheet 常量表的默认表名
+const DefaultConstantsSheet = "constants"
+
 // ConfigManager 配置管理器
+//
+// 加载后的配置视为只读，Reload 整体替换而不修改已有配置；
+// 并发场景下通过 Snapshot 获取一致的配置视图，再直接读取其字段
 type ConfigManager struct {
 	Config        *Config
 	CombineConfig *CombineConfig
 	ReplaceConfig *ReplaceColumnConfig
+	FrozenConfig  *FrozenConfig
+	Permissions   *PermissionConfig
+	Transforms    *TransformConfig
+	Constants     *ConstantsConfig
+	SheetsConfig  *SheetsConfig
+	ColumnGroups  *ColumnGroupsConfig
+	Sources       *SourcesConfig
+	Filters       *FiltersConfig
+
+	mu          sync.RWMutex
+	confDir     string
+	profile     string // 叠加在 config.json 上的环境配置名
+	subscribers []func(snapshot *ConfigManager)
 }
 
-// NewConfigManager 创建配置管理器
+// profilePattern 环境配置名，只能包含字母、数字、下划线和连字符
+var profilePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ProfileEnv 未指定 -profile 时选择环境配置的环境变量
+const ProfileEnv = "BUILDER_PROFILE"
+
+// NewConfigManager 创建配置管理器，环境配置默认取自环境变量 BUILDER_PROFILE
 func NewConfigManager() *ConfigManager {
-	return &ConfigManager{}
+	return &ConfigManager{profile: os.Getenv(ProfileEnv)}
 }
 
-// Load 加载所有配置文件
+// SetProfile 选择环境配置，之后加载时将 config.<profile>.json 深度合并到 config.json 上，为空表示只使用 config.json
+func (cm *ConfigManager) SetProfile(profile string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.profile = profile
+}
+
+// Profile 当前选择的环境配置
+func (cm *ConfigManager) Profile() string {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.profile
+}
+
+// Load 加载所有配置文件，校验通过后替换当前配置
 func (cm *ConfigManager) Load(confDir string) error {
+	next := NewConfigManager()
+	next.profile = cm.Profile()
+	if err := next.loadAll(confDir); err != nil {
+		return err
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("配置校验失败: %v", err)
+	}
+
+	cm.mu.Lock()
+	cm.confDir = confDir
+	cm.profile = next.profile
+	cm.Config = next.Config
+	cm.CombineConfig = next.CombineConfig
+	cm.ReplaceConfig = next.ReplaceConfig
+	cm.FrozenConfig = next.FrozenConfig
+	cm.Permissions = next.Permissions
+	cm.Transforms = next.Transforms
+	cm.Constants = next.Constants
+	cm.SheetsConfig = next.SheetsConfig
+	cm.ColumnGroups = next.ColumnGroups
+	cm.Sources = next.Sources
+	cm.Filters = next.Filters
+	subscribers := append([]func(snapshot *ConfigManager){}, cm.subscribers...)
+	cm.mu.Unlock()
+
+	// 通知订阅者
+	snapshot := cm.Snapshot()
+	for _, callback := range subscribers {
+		callback(snapshot)
+	}
+	return nil
+}
+
+// Reload 从上次加载的目录重新加载配置，加载或校验失败时保留原配置
+func (cm *ConfigManager) Reload() error {
+	cm.mu.RLock()
+	confDir := cm.confDir
+	cm.mu.RUnlock()
+
+	if confDir == "" {
+		return fmt.Errorf("配置尚未加载")
+	}
+	return cm.Load(confDir)
+}
+
+// Subscribe 订阅配置变更，每次加载成功后以新配置的快照调用回调
+func (cm *ConfigManager) Subscribe(callback func(snapshot *ConfigManager)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.subscribers = append(cm.subscribers, callback)
+}
+
+// Snapshot 获取当前配置的快照，之后的 Reload 不会影响快照
+func (cm *ConfigManager) Snapshot() *ConfigManager {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return &ConfigManager{
+		Config:        cm.Config,
+		CombineConfig: cm.CombineConfig,
+		ReplaceConfig: cm.ReplaceConfig,
+		FrozenConfig:  cm.FrozenConfig,
+		Permissions:   cm.Permissions,
+		Transforms:    cm.Transforms,
+		Constants:     cm.Constants,
+		SheetsConfig:  cm.SheetsConfig,
+		ColumnGroups:  cm.ColumnGroups,
+		Sources:       cm.Sources,
+		Filters:       cm.Filters,
+		confDir:       cm.confDir,
+		profile:       cm.profile,
+	}
+}
+
+// Validate 校验配置的完整性
+func (cm *ConfigManager) Validate() error {
+	if cm.Config == nil {
+		return fmt.Errorf("缺少主配置")
+	}
+	if cm.Config.SourceDir == "" {
+		return fmt.Errorf("未配置 sourceDir")
+	}
+	if cm.Config.OutputDir == "" {
+		return fmt.Errorf("未配置 outputDir")
+	}
+	if cm.Config.Layout != "" && cm.Config.Layout != LayoutFiles && cm.Config.Layout != LayoutCAS {
+		return fmt.Errorf("不支持的输出目录结构: %s", cm.Config.Layout)
+	}
+	if cm.Config.SyncToGame && cm.Config.GameDir == "" {
+		return fmt.Errorf("开启 syncToGame 时必须配置 gameDir")
+	}
+	if cm.Config.GameTrashDir != "" && !cm.Config.GameMirror {
+		return fmt.Errorf("配置 gameTrashDir 时必须开启 gameMirror")
+	}
+	if cm.Config.RemoteSync.Enabled && (cm.Config.RemoteSync.Host == "" || cm.Config.RemoteSync.RemoteDir == "") {
+		return fmt.Errorf("开启 remoteSync 时必须配置 host 和 remoteDir")
+	}
+	for i, sink := range cm.Config.Sinks {
+		switch sink.Type {
+		case SinkDir, SinkArchive, SinkHTTP, SinkRedis, SinkSFTP, "s3", "oss", "cos":
+		default:
+			return fmt.Errorf("第 %d 个输出目标的类型 %s 不支持", i+1, sink.Type)
+		}
+		for _, format := range sink.Formats {
+			if _, exists := cm.Config.Converters[format]; !exists {
+				return fmt.Errorf("第 %d 个输出目标的格式 %s 缺少转换器配置", i+1, format)
+			}
+		}
+	}
+	for i, root := range cm.Config.SourceRoots {
+		if root.Dir == "" {
+			return fmt.Errorf("第 %d 个额外源文件目录未配置 dir", i+1)
+		}
+	}
+	patternLists := [][]string{cm.Config.Include, cm.Config.Exclude, cm.Config.ExcludeSheets}
+	for _, reader := range cm.Config.Readers {
+		patternLists = append(patternLists, reader.Files)
+	}
+	for _, patterns := range patternLists {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("文件或表过滤模式 %s 无效: %v", pattern, err)
+			}
+		}
+	}
+	namespaces := make(map[string]string)
+	for _, root := range cm.Config.AllSourceRoots() {
+		if root.Namespace == "" {
+			continue
+		}
+		if !model.ValidNamespace(root.Namespace) {
+			return fmt.Errorf("源文件目录 %s 的命名空间 %s 不合法", root.Dir, root.Namespace)
+		}
+		if dir, exists := namespaces[root.Namespace]; exists {
+			return fmt.Errorf("源文件目录 %s 与 %s 使用了相同的命名空间 %s", root.Dir, dir, root.Namespace)
+		}
+		namespaces[root.Namespace] = root.Dir
+	}
+	for i, imp := range cm.Config.Imports {
+		if imp.From == "" || len(imp.Sheets) == 0 {
+			return fmt.Errorf("第 %d 个导入必须配置 from 和 sheets", i+1)
+		}
+		if imp.Namespace != "" && !model.ValidNamespace(imp.Namespace) {
+			return fmt.Errorf("第 %d 个导入的命名空间 %s 不合法", i+1, imp.Namespace)
+		}
+	}
+	if cm.Config.MaxWorkers < 0 {
+		return fmt.Errorf("maxWorkers 不能为负数")
+	}
+	if sampling := cm.Config.Sampling; sampling.Threshold < 0 || sampling.Head < 0 || sampling.Random < 0 {
+		return fmt.Errorf("sampling 的 threshold、head 和 random 不能为负数")
+	}
+	for name, space := range cm.Config.IDSpaces {
+		if err := validateIDSpace(space); err != nil {
+			return fmt.Errorf("ID 空间 %s: %v", name, err)
+		}
+	}
+	for name, assetType := range cm.Config.Assets.Types {
+		for _, ext := range assetType.Extensions {
+			if !strings.HasPrefix(ext, ".") {
+				return fmt.Errorf("资源类型 %s 的扩展名 %s 应以 . 开头", name, ext)
+			}
+		}
+	}
+	if cm.Config.OutputWorkers < 0 {
+		return fmt.Errorf("outputWorkers 不能为负数")
+	}
+	for name, converter := range cm.Config.Converters {
+		if converter.MaxWorkers < 0 {
+			return fmt.Errorf("转换器 %s 的 maxWorkers 不能为负数", name)
+		}
+	}
+	for _, format := range cm.Config.Formats {
+		if _, exists := cm.Config.Converters[format]; !exists {
+			return fmt.Errorf("格式 %s 缺少转换器配置", format)
+		}
+	}
+
+	if cm.FrozenConfig != nil && cm.FrozenConfig.Mode != "fail" && cm.FrozenConfig.Mode != "warn" {
+		return fmt.Errorf("frozen.json: 不支持的 mode: %s", cm.FrozenConfig.Mode)
+	}
+	if cm.Permissions != nil && cm.Permissions.Default != "allow" && cm.Permissions.Default != "deny" {
+		return fmt.Errorf("permissions.json: 不支持的 default: %s", cm.Permissions.Default)
+	}
+	if cm.CombineConfig != nil {
+		for name, combine := range cm.CombineConfig.Sheets {
+			sources := make(map[string]bool, len(combine.SourceSheets))
+			for _, source := range combine.SourceSheets {
+				sources[source] = true
+			}
+			for source := range combine.Where {
+				if !sources[source] {
+					return fmt.Errorf("combine.json: %s 的 where 中的 %s 不是源表", name, source)
+				}
+			}
+			for source := range combine.Columns {
+				if !sources[source] {
+					return fmt.Errorf("combine.json: %s 的 columns 中的 %s 不是源表", name, source)
+				}
+			}
+			if join := combine.Join; join != nil {
+				if join.Type != "" && join.Type != CombineJoinLeft && join.Type != CombineJoinInner {
+					return fmt.Errorf("combine.json: %s 不支持的连接类型: %s", name, join.Type)
+				}
+				if len(combine.SourceSheets) < 2 {
+					return fmt.Errorf("combine.json: %s 的连接至少需要两个源表", name)
+				}
+				for source := range join.On {
+					if !sources[source] || source == combine.SourceSheets[0] {
+						return fmt.Errorf("combine.json: %s 的 join.on 中的 %s 不是被连接的源表", name, source)
+					}
+				}
+			}
+			switch combine.ColumnMode {
+			case "", CombineColumnsUnion, CombineColumnsStrict:
+			default:
+				return fmt.Errorf("combine.json: %s 不支持的 columnMode: %s", name, combine.ColumnMode)
+			}
+			switch combine.OnDuplicateKey {
+			case "", CombineDuplicateError, CombineDuplicateFirst, CombineDuplicateLast:
+			default:
+				return fmt.Errorf("combine.json: %s 不支持的 onDuplicateKey: %s", name, combine.OnDuplicateKey)
+			}
+		}
+	}
+	if cm.Constants != nil {
+		keys := make(map[string]bool, len(cm.Constants.Constants))
+		for i, entry := range cm.Constants.Constants {
+			if entry.Key == "" || entry.Type == "" {
+				return fmt.Errorf("constants.json: 第 %d 个常量必须配置 key 和 type", i+1)
+			}
+			if keys[entry.Key] {
+				return fmt.Errorf("constants.json: 常量 %s 重复", entry.Key)
+			}
+			keys[entry.Key] = true
+		}
+	}
+	if cm.SheetsConfig != nil {
+		for name, settings := range cm.SheetsConfig.Sheets {
+			if _, err := path.Match(name, ""); err != nil {
+				return fmt.Errorf("sheets.json: 表名模式 %s 不合法", name)
+			}
+			for _, tag := range settings.Tags {
+				if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+					return fmt.Errorf("sheets.json: %s 的标签 %q 不合法", name, tag)
+				}
+			}
+			for _, key := range settings.Sort {
+				if strings.TrimLeft(strings.TrimSpace(key), "+-") == "" {
+					return fmt.Errorf("sheets.json: %s 的排序键 %q 不合法", name, key)
+				}
+			}
+			for column, file := range settings.Whitelists {
+				if column == "" || file == "" {
+					return fmt.Errorf("sheets.json: %s 的白名单必须配置列名和文件", name)
+				}
+			}
+			derived := make(map[string]bool, len(settings.Derived))
+			for i, column := range settings.Derived {
+				if column.Name == "" || column.Type == "" || column.Expr == "" {
+					return fmt.Errorf("sheets.json: %s 的第 %d 个派生列必须配置 name、type 和 expr", name, i+1)
+				}
+				if derived[column.Name] {
+					return fmt.Errorf("sheets.json: %s 的派生列 %s 重复", name, column.Name)
+				}
+				derived[column.Name] = true
+			}
+		}
+	}
+	if cm.ColumnGroups != nil {
+		for name, entries := range cm.ColumnGroups.Groups {
+			columns := make(map[string]bool, len(entries))
+			for i, entry := range entries {
+				if entry.Name == "" || entry.Type == "" {
+					return fmt.Errorf("columnGroups.json: 列组 %s 的第 %d 列必须配置 name 和 type", name, i+1)
+				}
+				if columns[entry.Name] {
+					return fmt.Errorf("columnGroups.json: 列组 %s 的列 %s 重复", name, entry.Name)
+				}
+				columns[entry.Name] = true
+				if index := strings.LastIndex(entry.Ref, "."); entry.Ref != "" && (index <= 0 || index == len(entry.Ref)-1) {
+					return fmt.Errorf("columnGroups.json: 列组 %s 的列 %s 的引用 %s 应为 表名.列名", name, entry.Name, entry.Ref)
+				}
+			}
+		}
+	}
+	if cm.Sources != nil {
+		urls := make(map[string]bool)
+		for i, source := range cm.Sources.Sources {
+			if !strings.HasPrefix(source.URL, "http://") && !strings.HasPrefix(source.URL, "https://") {
+				return fmt.Errorf("sources.json: 第 %d 个源文件的地址 %q 不是 HTTP(S) 地址", i+1, source.URL)
+			}
+			if urls[source.URL] {
+				return fmt.Errorf("sources.json: 源文件 %s 重复", source.URL)
+			}
+			urls[source.URL] = true
+			if source.Name != "" && (source.Name != filepath.Base(source.Name) || filepath.Ext(source.Name) == "") {
+				return fmt.Errorf("sources.json: 源文件 %s 的 name 应为带扩展名的文件名: %s", source.URL, source.Name)
+			}
+		}
+	}
+	if cm.ReplaceConfig != nil {
+		for sheet, rules := range cm.ReplaceConfig.Sheets {
+			for column, rule := range rules.Columns {
+				if !rule.Regex {
+					continue
+				}
+				if _, err := regexp.Compile(rule.From); rule.From == "" || err != nil {
+					return fmt.Errorf("replaceColumn.json: %s.%s 的正则表达式 %q 无效", sheet, column, rule.From)
+				}
+			}
+		}
+	}
+	if cm.Filters != nil {
+		for pattern, conditions := range cm.Filters.Filters {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("filters.json: 表名模式 %s 无效: %v", pattern, err)
+			}
+			for _, condition := range conditions {
+				if strings.TrimSpace(condition) == "" {
+					return fmt.Errorf("filters.json: %s 的过滤条件不能为空", pattern)
+				}
+			}
+		}
+	}
+	if cm.Transforms != nil {
+		for i, rule := range cm.Transforms.Transforms {
+			if rule.Type != "expr" && rule.Type != "command" {
+				return fmt.Errorf("transforms.json: 第 %d 个步骤的类型 %s 不支持", i+1, rule.Type)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateIDSpace 校验 ID 空间的表名模式和取值范围，不同表的范围不能重叠
+func validateIDSpace(space IDSpaceConfig) error {
+	if len(space.Sheets) == 0 {
+		return fmt.Errorf("至少需要配置一张表")
+	}
+	patterns := make([]string, 0, len(space.Sheets))
+	for pattern, idRange := range space.Sheets {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("表名模式 %s 不合法", pattern)
+		}
+		if idRange.Limited() {
+			if idRange.Min > idRange.Max {
+				return fmt.Errorf("%s 的范围 %d-%d 不合法", pattern, idRange.Min, idRange.Max)
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		return space.Sheets[patterns[i]].Min < space.Sheets[patterns[j]].Min
+	})
+	for i := 1; i < len(patterns); i++ {
+		previous, current := space.Sheets[patterns[i-1]], space.Sheets[patterns[i]]
+		if current.Min <= previous.Max {
+			return fmt.Errorf("%s 和 %s 的范围重叠", patterns[i-1], patterns[i])
+		}
+	}
+	return nil
+}
+
+// loadAll 依次加载所有配置文件
+func (cm *ConfigManager) loadAll(confDir string) error {
 	// 加载主配置
 	if err := cm.loadMainConfig(confDir); err != nil {
 		return err
@@ -99,16 +923,61 @@ func (cm *ConfigManager) Load(confDir string) error {
 		return err
 	}
 
+	// 加载冻结配置
+	if err := cm.loadFrozenConfig(confDir); err != nil {
+		return err
+	}
+
+	// 加载列权限配置
+	if err := cm.loadPermissionConfig(confDir); err != nil {
+		return err
+	}
+
+	// 加载转换前处理配置
+	if err := cm.loadTransformConfig(confDir); err != nil {
+		return err
+	}
+
+	// 加载常量配置
+	if err := cm.loadConstantsConfig(confDir); err != nil {
+		return err
+	}
+
+	// 加载表级配置
+	if err := cm.loadSheetsConfig(confDir); err != nil {
+		return err
+	}
+
+	// 加载共享列组配置
+	if err := cm.loadColumnGroupsConfig(confDir); err != nil {
+		return err
+	}
+
+	// 加载远程源文件配置
+	if err := cm.loadSourcesConfig(confDir); err != nil {
+		return err
+	}
+
+	// 加载行过滤配置
+	if err := cm.loadFiltersConfig(confDir); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// loadMainConfig 加载主配置
+// loadMainConfig 加载主配置，合并 fragments 引入的配置片段；选择了环境配置时再合并 config.<profile>.json
 func (cm *ConfigManager) loadMainConfig(confDir string) error {
 	path := filepath.Join(confDir, "config.json")
-	content, err := os.ReadFile(path)
+	content, err := readConfigFile(path)
 	if err != nil {
 		return err
 	}
+	if cm.profile != "" {
+		if content, err = overlayProfile(content, confDir, cm.profile); err != nil {
+			return err
+		}
+	}
 
 	var config Config
 	if err := json.Unmarshal(content, &config); err != nil {
@@ -119,6 +988,52 @@ func (cm *ConfigManager) loadMainConfig(confDir string) error {
 	return nil
 }
 
+// overlayProfile 将环境配置 config.<profile>.json 深度合并到主配置上：对象逐个键合并，
+// 数组和其他值整体替换，值为 null 的键从主配置中删除
+func overlayProfile(content []byte, confDir, profile string) ([]byte, error) {
+	if !profilePattern.MatchString(profile) {
+		return nil, fmt.Errorf("环境配置名 %s 不合法", profile)
+	}
+	path := filepath.Join(confDir, "config."+profile+".json")
+	overlayContent, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("环境配置 %s 不存在: %s", profile, path)
+		}
+		return nil, err
+	}
+
+	var base, overlay map[string]interface{}
+	if err := json.Unmarshal(content, &base); err != nil {
+		return nil, fmt.Errorf("config.json: %v", err)
+	}
+	if err := json.Unmarshal(overlayContent, &overlay); err != nil {
+		return nil, fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+	return json.Marshal(mergeConfig(base, overlay))
+}
+
+// mergeConfig 深度合并两个 JSON 对象，overlay 优先
+func mergeConfig(base, overlay map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = make(map[string]interface{})
+	}
+	for key, value := range overlay {
+		if value == nil {
+			delete(base, key)
+			continue
+		}
+		overlayObject, isObject := value.(map[string]interface{})
+		baseObject, baseIsObject := base[key].(map[string]interface{})
+		if isObject && baseIsObject {
+			base[key] = mergeConfig(baseObject, overlayObject)
+			continue
+		}
+		base[key] = value
+	}
+	return base
+}
+
 // loadCombineConfig 加载合并配置
 func (cm *ConfigManager) loadCombineConfig(confDir string) error {
 	path := filepath.Join(confDir, "combine.json")
@@ -128,7 +1043,7 @@ func (cm *ConfigManager) loadCombineConfig(confDir string) error {
 		return nil
 	}
 
-	content, err := os.ReadFile(path)
+	content, err := readConfigFile(path)
 	if err != nil {
 		return err
 	}
@@ -151,7 +1066,7 @@ func (cm *ConfigManager) loadReplaceConfig(confDir string) error {
 		return nil
 	}
 
-	content, err := os.ReadFile(path)
+	content, err := readConfigFile(path)
 	if err != nil {
 		return err
 	}
@@ -165,8 +1080,222 @@ func (cm *ConfigManager) loadReplaceConfig(confDir string) error {
 	return nil
 }
 
+// loadFrozenConfig 加载冻结配置
+func (cm *ConfigManager) loadFrozenConfig(confDir string) error {
+	path := filepath.Join(confDir, "frozen.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，使用默认值
+		cm.FrozenConfig = &FrozenConfig{Mode: "fail", Sheets: make(map[string]FrozenSheet)}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var frozenConfig FrozenConfig
+	if err := json.Unmarshal(content, &frozenConfig); err != nil {
+		return err
+	}
+	if frozenConfig.Mode == "" {
+		frozenConfig.Mode = "fail"
+	}
+	if frozenConfig.Sheets == nil {
+		frozenConfig.Sheets = make(map[string]FrozenSheet)
+	}
+
+	cm.FrozenConfig = &frozenConfig
+	return nil
+}
+
+// loadPermissionConfig 加载列权限配置
+func (cm *ConfigManager) loadPermissionConfig(confDir string) error {
+	path := filepath.Join(confDir, "permissions.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，使用默认值
+		cm.Permissions = &PermissionConfig{Default: "allow", Sheets: make(map[string]map[string][]string)}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var permissions PermissionConfig
+	if err := json.Unmarshal(content, &permissions); err != nil {
+		return err
+	}
+	if permissions.Default == "" {
+		permissions.Default = "allow"
+	}
+
+	cm.Permissions = &permissions
+	return nil
+}
+
+// loadTransformConfig 加载转换前处理配置
+func (cm *ConfigManager) loadTransformConfig(confDir string) error {
+	path := filepath.Join(confDir, "transforms.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，使用默认值
+		cm.Transforms = &TransformConfig{Transforms: make([]TransformRule, 0)}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var transforms TransformConfig
+	if err := json.Unmarshal(content, &transforms); err != nil {
+		return err
+	}
+
+	cm.Transforms = &transforms
+	return nil
+}
+
+// loadConstantsConfig 加载常量配置
+func (cm *ConfigManager) loadConstantsConfig(confDir string) error {
+	path := filepath.Join(confDir, "constants.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，不生成常量表
+		cm.Constants = &ConstantsConfig{Sheet: DefaultConstantsSheet}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var constants ConstantsConfig
+	if err := json.Unmarshal(content, &constants); err != nil {
+		return fmt.Errorf("constants.json: %v", err)
+	}
+	if constants.Sheet == "" {
+		constants.Sheet = DefaultConstantsSheet
+	}
+
+	cm.Constants = &constants
+	return nil
+}
+
+// loadSheetsConfig 加载表级配置
+func (cm *ConfigManager) loadSheetsConfig(confDir string) error {
+	path := filepath.Join(confDir, "sheets.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，使用默认值
+		cm.SheetsConfig = &SheetsConfig{Sheets: make(map[string]SheetSettings)}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var sheetsConfig SheetsConfig
+	if err := json.Unmarshal(content, &sheetsConfig); err != nil {
+		return fmt.Errorf("sheets.json: %v", err)
+	}
+	if sheetsConfig.Sheets == nil {
+		sheetsConfig.Sheets = make(map[string]SheetSettings)
+	}
+
+	cm.SheetsConfig = &sheetsConfig
+	return nil
+}
+
+// loadColumnGroupsConfig 加载共享列组配置
+func (cm *ConfigManager) loadColumnGroupsConfig(confDir string) error {
+	path := filepath.Join(confDir, "columnGroups.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，没有可引入的列组
+		cm.ColumnGroups = &ColumnGroupsConfig{Groups: make(map[string][]ColumnGroupEntry)}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var groups ColumnGroupsConfig
+	if err := json.Unmarshal(content, &groups); err != nil {
+		return fmt.Errorf("columnGroups.json: %v", err)
+	}
+	if groups.Groups == nil {
+		groups.Groups = make(map[string][]ColumnGroupEntry)
+	}
+
+	cm.ColumnGroups = &groups
+	return nil
+}
+
+// loadSourcesConfig 加载远程源文件配置
+func (cm *ConfigManager) loadSourcesConfig(confDir string) error {
+	path := filepath.Join(confDir, "sources.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，没有远程源文件
+		cm.Sources = &SourcesConfig{}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var sources SourcesConfig
+	if err := json.Unmarshal(content, &sources); err != nil {
+		return fmt.Errorf("sources.json: %v", err)
+	}
+
+	cm.Sources = &sources
+	return nil
+}
+
+// loadFiltersConfig 加载行过滤配置
+func (cm *ConfigManager) loadFiltersConfig(confDir string) error {
+	path := filepath.Join(confDir, "filters.json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// 配置文件不存在，不过滤任何行
+		cm.Filters = &FiltersConfig{}
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var filters FiltersConfig
+	if err := json.Unmarshal(content, &filters); err != nil {
+		return fmt.Errorf("filters.json: %v", err)
+	}
+
+	cm.Filters = &filters
+	return nil
+}
+
+// SaveFrozenConfig 保存冻结配置
+func (cm *ConfigManager) SaveFrozenConfig(confDir string) error {
+	content, err := json.MarshalIndent(cm.FrozenConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(confDir, "frozen.json"), append(content, '\n'), 0644)
+}
+
 // GetReaderConfig 获取读取器配置
 func (cm *ConfigManager) GetReaderConfig(readerType string) *ReaderConfig {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
 	if cm.Config == nil || cm.Config.Readers == nil {
 		return nil
 	}
@@ -174,8 +1303,48 @@ func (cm *ConfigManager) GetReaderConfig(readerType string) *ReaderConfig {
 	return &cfg
 }
 
+// ReaderOptions 读取源文件使用的选项，relPath 为相对源文件目录的路径：
+// 依次合并 default、与扩展名同名（不含点，如 xlsx）的配置和 files 匹配该文件的配置（按名称顺序），未启用的配置只有 default 生效
+func (cm *ConfigManager) ReaderOptions(relPath string) map[string]interface{} {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	options := make(map[string]interface{})
+	if cm.Config == nil {
+		return options
+	}
+	merge := func(cfg ReaderConfig) {
+		for key, value := range cfg.Options {
+			options[key] = value
+		}
+	}
+	readers := cm.Config.Readers
+	merge(readers[ReaderDefault])
+
+	relPath = filepath.ToSlash(relPath)
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(relPath), "."))
+	if cfg, exists := readers[ext]; exists && ext != ReaderDefault && cfg.Enabled && cfg.Type != ReaderDB && len(cfg.Files) == 0 {
+		merge(cfg)
+	}
+
+	names := make([]string, 0)
+	for name, cfg := range readers {
+		if cfg.Enabled && len(cfg.Files) > 0 && matchAnyPattern(cfg.Files, relPath) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		merge(readers[name])
+	}
+	return options
+}
+
 // GetConverterConfig 获取转换器配置
 func (cm *ConfigManager) GetConverterConfig(format string) *ConverterConfig {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
 	if cm.Config == nil || cm.Config.Converters == nil {
 		return nil
 	}
@@ -185,6 +1354,9 @@ func (cm *ConfigManager) GetConverterConfig(format string) *ConverterConfig {
 
 // GetValidatorConfig 获取验证器配置
 func (cm *ConfigManager) GetValidatorConfig(validatorType string) *ValidatorConfig {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
 	if cm.Config == nil || cm.Config.Validators == nil {
 		return nil
 	}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FragmentsKey 配置文件中引入其他配置片段的键，include/exclude 是源文件过滤的键，不能复用
+const FragmentsKey = "fragments"
+
+// readConfigFile 读取配置文件并合并 fragments 引入的配置片段，返回合并后的 JSON
+//
+// fragments 为相对该文件所在目录的路径或 glob 模式列表，匹配的片段按路径顺序合并，片段中也可以继续引入。
+// 对象逐个键合并，同一个值（包括数组）在多个文件中重复定义时报错，避免不同团队维护的片段互相覆盖
+func readConfigFile(path string) ([]byte, error) {
+	config, err := loadConfigObject(path, make(map[string]bool), make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// loadConfigObject 读取配置文件为 JSON 对象并递归合并其中的 fragments，数字保留原文以免 64 位 ID 丢失精度，
+// visiting 为正在读取的文件，用于检测循环引入；loaded 记录读取过的所有文件（绝对路径），包括配置文件本身
+func loadConfigObject(path string, visiting, loaded map[string]bool) (map[string]interface{}, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if visiting[absPath] {
+		return nil, fmt.Errorf("%s: 循环引入", path)
+	}
+	visiting[absPath] = true
+	defer delete(visiting, absPath)
+	loaded[absPath] = true
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+
+	patterns, err := fragmentPatterns(config[FragmentsKey])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	delete(config, FragmentsKey)
+
+	for _, fragment := range patterns {
+		matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), fragment))
+		if err != nil {
+			return nil, fmt.Errorf("%s: fragments 模式 %s 无效: %v", path, fragment, err)
+		}
+		if len(matches) == 0 {
+			// 写错的模式不报错会让片段中的配置静默缺失
+			if strings.ContainsAny(fragment, "*?[") {
+				return nil, fmt.Errorf("%s: 引入的配置 %s 没有匹配任何文件", path, fragment)
+			}
+			return nil, fmt.Errorf("%s: 引入的配置 %s 不存在", path, fragment)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			included, err := loadConfigObject(match, visiting, loaded)
+			if err != nil {
+				return nil, err
+			}
+			if err := mergeFragment(config, included, ""); err != nil {
+				return nil, fmt.Errorf("合并 %s 失败: %v", match, err)
+			}
+		}
+	}
+	return config, nil
+}
+
+// fragmentConfigFiles 支持 fragments 的配置文件
+var fragmentConfigFiles = []string{"config.json", "combine.json", "replaceColumn.json"}
+
+// ConfigFiles 返回配置目录中支持 fragments 的配置文件及其递归引入的所有片段（绝对路径，按路径排序），
+// 片段可以位于配置目录之外；解析失败时同时返回已解析到的文件和错误
+func ConfigFiles(confDir string) ([]string, error) {
+	loaded := make(map[string]bool)
+	var firstErr error
+	for _, name := range fragmentConfigFiles {
+		path := filepath.Join(confDir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if _, err := loadConfigObject(path, make(map[string]bool), loaded); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	files := make([]string, 0, len(loaded))
+	for file := range loaded {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, firstErr
+}
+
+// fragmentPatterns 解析 fragments 的值，可以是单个字符串或字符串数组
+func fragmentPatterns(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		patterns := make([]string, 0, len(v))
+		for _, item := range v {
+			pattern, ok := item.(string)
+			if !ok || pattern == "" {
+				return nil, fmt.Errorf("fragments 只能包含路径字符串")
+			}
+			patterns = append(patterns, pattern)
+		}
+		return patterns, nil
+	default:
+		return nil, fmt.Errorf("fragments 必须是路径或路径数组")
+	}
+}
+
+// mergeFragment 将配置片段合并到 config 中，两侧都是对象时递归合并，其余重复的键报错
+func mergeFragment(config, fragment map[string]interface{}, prefix string) error {
+	for key, value := range fragment {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		existing, exists := config[key]
+		if !exists {
+			config[key] = value
+			continue
+		}
+		existingObject, existingIsObject := existing.(map[string]interface{})
+		valueObject, valueIsObject := value.(map[string]interface{})
+		if !existingIsObject || !valueIsObject {
+			return fmt.Errorf("%s 重复定义", path)
+		}
+		if err := mergeFragment(existingObject, valueObject, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// CBORConverter CBOR转换器实现，按 RFC 8949 的确定性编码规则输出：
+// 整数和长度使用最短编码，浮点数使用不丢失精度的最短宽度，映射的键按编码后的字节序排列
+type CBORConverter struct {
+	config        map[string]interface{}
+	stripMetadata bool // 只输出 name 和 rows，省略列定义和元数据
+}
+
+// NewCBORConverter 创建CBOR转换器
+func NewCBORConverter() *CBORConverter {
+	return &CBORConverter{}
+}
+
+// Init 初始化转换器
+func (c *CBORConverter) Init(config map[string]interface{}) error {
+	c.config = config
+	c.stripMetadata = false
+	if strip, ok := config["stripMetadata"].(bool); ok {
+		c.stripMetadata = strip
+	}
+	return nil
+}
+
+// Convert 将数据转换为CBOR格式，结构与JSON输出相同
+func (c *CBORConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	rowValues := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		rowValues = append(rowValues, row)
+	}
+	var rowsValue interface{} = rowValues
+
+	// 按主键输出行数据，键保持原类型
+	if rowsAsMap(c.config) {
+		if _, err := rowKeys(sheet); err != nil {
+			return nil, err
+		}
+		keyed := make(cborMap, 0, len(rows))
+		for _, row := range rows {
+			keyed = append(keyed, cborPair{key: rowKey(sheet, row), value: row})
+		}
+		rowsValue = keyed
+	}
+
+	data := map[string]interface{}{
+		"name": sheet.Name,
+		"rows": rowsValue,
+	}
+	if !c.stripMetadata {
+		// 列定义通过 JSON 标签转换为通用结构，与 JSON 输出的字段名一致
+		columns, err := jsonValue(sheet.Columns)
+		if err != nil {
+			return nil, err
+		}
+		data["columns"] = columns
+		data["meta"] = sheet.Meta
+	}
+
+	encoder := &cborEncoder{}
+	if err := encoder.encode(data); err != nil {
+		return nil, err
+	}
+
+	result := &model.ConvertResult{
+		FileName: fmt.Sprintf("%s.cbor", model.SheetPath(sheet.Name)),
+		Content:  encoder.buf.Bytes(),
+		Format:   "cbor",
+	}
+	return result, nil
+}
+
+// GetFormat 获取支持的格式类型
+func (c *CBORConverter) GetFormat() string {
+	return "cbor"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *CBORConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
+
+// jsonValue 将任意值经 JSON 转换为通用结构，整数保持为整数
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// cborPair 映射中的一项，键可以是任意类型
+type cborPair struct {
+	key   interface{}
+	value interface{}
+}
+
+// cborMap 键不限于字符串的映射
+type cborMap []cborPair
+
+// CBOR 主类型
+const (
+	cborUnsigned = 0 << 5
+	cborNegative = 1 << 5
+	cborBytes    = 2 << 5
+	cborText     = 3 << 5
+	cborArray    = 4 << 5
+	cborMapType  = 5 << 5
+)
+
+// cborEncoder 确定性 CBOR 编码器
+type cborEncoder struct {
+	buf bytes.Buffer
+}
+
+// head 写入主类型和参数，参数使用最短编码
+func (e *cborEncoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		e.buf.WriteByte(major | 24)
+		e.buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		e.buf.WriteByte(major | 25)
+		binary.Write(&e.buf, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		e.buf.WriteByte(major | 26)
+		binary.Write(&e.buf, binary.BigEndian, uint32(n))
+	default:
+		e.buf.WriteByte(major | 27)
+		binary.Write(&e.buf, binary.BigEndian, n)
+	}
+}
+
+// encodeInt 编码有符号整数
+func (e *cborEncoder) encodeInt(n int64) {
+	if n >= 0 {
+		e.head(cborUnsigned, uint64(n))
+	} else {
+		e.head(cborNegative, uint64(-(n + 1)))
+	}
+}
+
+// encodeFloat 使用不丢失精度的最短宽度编码浮点数
+func (e *cborEncoder) encodeFloat(f float64) {
+	if math.IsNaN(f) {
+		e.buf.Write([]byte{0xf9, 0x7e, 0x00})
+		return
+	}
+	if f32 := float32(f); float64(f32) == f || math.IsInf(f, 0) {
+		if half, ok := float16Bits(f32); ok {
+			e.buf.WriteByte(0xf9)
+			binary.Write(&e.buf, binary.BigEndian, half)
+			return
+		}
+		e.buf.WriteByte(0xfa)
+		binary.Write(&e.buf, binary.BigEndian, math.Float32bits(f32))
+		return
+	}
+	e.buf.WriteByte(0xfb)
+	binary.Write(&e.buf, binary.BigEndian, math.Float64bits(f))
+}
+
+// float16Bits 将单精度浮点数转换为半精度，无法精确表示时返回 false
+func float16Bits(f float32) (uint16, bool) {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int((bits>>23)&0xff) - 127
+	mant := bits & 0x7fffff
+
+	switch {
+	case f == 0:
+		return sign, true
+	case math.IsInf(float64(f), 0):
+		return sign | 0x7c00, true
+	case exp >= -14 && exp <= 15:
+		// 规格化数：尾数低 13 位必须为 0
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(exp+15)<<10 | uint16(mant>>13), true
+	case exp >= -24 && exp < -14:
+		// 非规格化数：值必须是 2^-24 的整数倍
+		full := mant | 0x800000
+		shift := uint(-(exp + 1))
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	default:
+		return 0, false
+	}
+}
+
+// encode 编码任意值
+func (e *cborEncoder) encode(v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		e.buf.WriteByte(0xf6)
+	case bool:
+		if val {
+			e.buf.WriteByte(0xf5)
+		} else {
+			e.buf.WriteByte(0xf4)
+		}
+	case int:
+		e.encodeInt(int64(val))
+	case int32:
+		e.encodeInt(int64(val))
+	case int64:
+		e.encodeInt(val)
+	case uint64:
+		e.head(cborUnsigned, val)
+	case float32:
+		e.encodeFloat(float64(val))
+	case float64:
+		e.encodeFloat(val)
+	case json.Number:
+		if n, err := strconv.ParseInt(string(val), 10, 64); err == nil {
+			e.encodeInt(n)
+			return nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return err
+		}
+		e.encodeFloat(f)
+	case string:
+		e.head(cborText, uint64(len(val)))
+		e.buf.WriteString(val)
+	case []byte:
+		e.head(cborBytes, uint64(len(val)))
+		e.buf.Write(val)
+	case []interface{}:
+		e.head(cborArray, uint64(len(val)))
+		for _, item := range val {
+			if err := e.encode(item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		pairs := make(cborMap, 0, len(val))
+		for key, item := range val {
+			pairs = append(pairs, cborPair{key: key, value: item})
+		}
+		return e.encodeMap(pairs)
+	case cborMap:
+		return e.encodeMap(val)
+	default:
+		// 其他类型（如 []string、结构体）经 JSON 转换为通用结构
+		generic, err := jsonValue(val)
+		if err != nil {
+			return fmt.Errorf("无法编码为 CBOR 的值 %v: %v", val, err)
+		}
+		return e.encode(generic)
+	}
+	return nil
+}
+
+// encodeMap 编码映射，各项按键编码后的字节序排列，键重复时报错
+func (e *cborEncoder) encodeMap(pairs cborMap) error {
+	type encodedPair struct {
+		raw   interface{}
+		key   []byte
+		value interface{}
+	}
+	encoded := make([]encodedPair, 0, len(pairs))
+	for _, pair := range pairs {
+		keyEncoder := &cborEncoder{}
+		if err := keyEncoder.encode(pair.key); err != nil {
+			return err
+		}
+		encoded = append(encoded, encodedPair{raw: pair.key, key: keyEncoder.buf.Bytes(), value: pair.value})
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i].key, encoded[j].key) < 0
+	})
+
+	e.head(cborMapType, uint64(len(encoded)))
+	for i, pair := range encoded {
+		if i > 0 && bytes.Equal(encoded[i-1].key, pair.key) {
+			return fmt.Errorf("映射中的键重复: %v", pair.raw)
+		}
+		e.buf.Write(pair.key)
+		if err := e.encode(pair.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
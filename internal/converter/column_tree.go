@@ -0,0 +1,36 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// columnNode 嵌套列结构中的一个节点
+type columnNode struct {
+	Name     string        // 当前层级的字段名
+	Children []*columnNode // 子字段，为空时表示叶子列
+}
+
+// buildColumnTree 按列顺序将 reward.itemId 形式的列组织为嵌套结构
+func buildColumnTree(columns []model.ColumnInfo) []*columnNode {
+	roots := make([]*columnNode, 0)
+	for _, col := range columns {
+		nodes := &roots
+		for _, part := range strings.Split(col.Name, ".") {
+			var node *columnNode
+			for _, existing := range *nodes {
+				if existing.Name == part {
+					node = existing
+					break
+				}
+			}
+			if node == nil {
+				node = &columnNode{Name: part}
+				*nodes = append(*nodes, node)
+			}
+			nodes = &node.Children
+		}
+	}
+	return roots
+}
@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// ConvertOptions ConvertSheet 的选项
+type ConvertOptions struct {
+	Context    context.Context                   // 取消后终止支持取消的转换器，为空时不可取消
+	Converters map[string]map[string]interface{} // 格式 -> 转换器选项，与 config.json 中 converters.<format>.options 相同，未配置的格式使用默认选项
+	Factory    *ConverterFactory                 // 创建转换器的工厂，为空时使用内置转换器
+}
+
+// ConvertSheet 对内存中的单张数据表并发运行多个格式的转换器，不读写输出目录，供编辑器等工具即时预览各格式的输出
+//
+// 成功的结果按 formats 的顺序返回；某个格式失败或不存在时其余格式照常转换，错误以 *model.ConvertErrors 一起返回
+func ConvertSheet(sheet *model.DataSheet, formats []string, opts ConvertOptions) ([]*model.ConvertResult, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	factory := opts.Factory
+	if factory == nil {
+		factory = NewConverterFactory()
+	}
+
+	// 每个格式的结果和错误写入独立的槽位，保证顺序稳定
+	slots := make([]*model.ConvertResult, len(formats))
+	errs := make([]error, len(formats))
+	var wg sync.WaitGroup
+	for i, format := range formats {
+		conv, err := factory.CreateConverter(format, opts.Converters[format])
+		if err == nil && conv == nil {
+			err = fmt.Errorf("不支持的格式: %s", format)
+		}
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, format string, conv IConverter) {
+			defer wg.Done()
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+			result, err := ConvertContext(ctx, conv, sheet)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			result.Sheet = sheet.Name
+			result.Format = format
+			slots[i] = result
+		}(i, format, conv)
+	}
+	wg.Wait()
+
+	results := make([]*model.ConvertResult, 0, len(formats))
+	convertErrs := &model.ConvertErrors{}
+	for i, format := range formats {
+		if errs[i] != nil {
+			convertErrs.Add(sheet.Name, format, errs[i])
+			continue
+		}
+		results = append(results, slots[i])
+	}
+	return results, convertErrs.Err()
+}
+
+// ConvertContext 转换单张表，转换器支持取消时传入 ctx
+func ConvertContext(ctx context.Context, conv IConverter, sheet *model.DataSheet) (*model.ConvertResult, error) {
+	if contextConv, ok := conv.(IContextConverter); ok {
+		return contextConv.ConvertContext(ctx, sheet)
+	}
+	return conv.Convert(sheet)
+}
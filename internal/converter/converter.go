@@ -1,6 +1,8 @@
 package converter
 
 import (
+	"context"
+
 	"github.com/game-data-builder/internal/model"
 )
 
@@ -18,3 +20,38 @@ type IConverter interface {
 	// BatchConvert 批量转换多个数据表
 	BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error)
 }
+
+// IIndexConverter 可选接口，在同一格式的所有表转换完成后生成汇总文件（如代码生成的索引或加载器）
+type IIndexConverter interface {
+	// ConvertIndex 根据所有数据表生成汇总文件，可以生成多个
+	ConvertIndex(sheets []*model.DataSheet) ([]*model.ConvertResult, error)
+}
+
+// IToolchainConverter 可选接口，依赖外部工具（如 flatc、protoc、数据库驱动）的转换器通过它报告工具是否可用
+type IToolchainConverter interface {
+	// CheckToolchain 检查外部工具是否可用，不可用时返回错误
+	CheckToolchain() error
+}
+
+// IContextConverter 可选接口，调用外部命令等耗时较长的转换器通过它支持取消，ctx 取消后应尽快终止并清理临时文件
+type IContextConverter interface {
+	// ConvertContext 将数据转换为目标格式
+	ConvertContext(ctx context.Context, sheet *model.DataSheet) (*model.ConvertResult, error)
+}
+
+// batchConvert 依次转换多个数据表，单张表失败时继续转换其余的表，返回成功的结果和所有表的错误
+func batchConvert(conv IConverter, sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	results := make([]*model.ConvertResult, 0, len(sheets))
+	errs := &model.ConvertErrors{}
+
+	for _, sheet := range sheets {
+		result, err := conv.Convert(sheet)
+		if err != nil {
+			errs.Add(sheet.Name, conv.GetFormat(), err)
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, errs.Err()
+}
@@ -15,6 +15,15 @@ func NewConverterFactory() *ConverterFactory {
 	factory.RegisterConverter(&JSONConverter{})
 	factory.RegisterConverter(&PHPConverter{})
 	factory.RegisterConverter(&FBSConverter{})
+	factory.RegisterConverter(&XMLConverter{})
+	factory.RegisterConverter(&GDBConverter{})
+	factory.RegisterConverter(&CBORConverter{})
+	factory.RegisterConverter(&ErlangConverter{})
+	factory.RegisterConverter(&CSVConverter{})
+	factory.RegisterConverter(&JavaConverter{})
+	factory.RegisterConverter(&CppConverter{})
+	factory.RegisterConverter(&RustConverter{})
+	factory.RegisterConverter(&GodotConverter{})
 
 	return factory
 }
@@ -45,6 +54,24 @@ func (f *ConverterFactory) CreateConverter(format string, config map[string]inte
 		newConverter = NewPHPConverter()
 	case *FBSConverter:
 		newConverter = NewFBSConverter()
+	case *XMLConverter:
+		newConverter = NewXMLConverter()
+	case *GDBConverter:
+		newConverter = NewGDBConverter()
+	case *CBORConverter:
+		newConverter = NewCBORConverter()
+	case *ErlangConverter:
+		newConverter = NewErlangConverter()
+	case *CSVConverter:
+		newConverter = NewCSVConverter()
+	case *JavaConverter:
+		newConverter = NewJavaConverter()
+	case *CppConverter:
+		newConverter = NewCppConverter()
+	case *RustConverter:
+		newConverter = NewRustConverter()
+	case *GodotConverter:
+		newConverter = NewGodotConverter()
 	default:
 		return nil, nil
 	}
@@ -0,0 +1,579 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// C++ 头文件的生成方式
+const (
+	cppModeAuto      = "auto"      // 按行数选择：不超过 constexprMaxRows 时使用 constexpr，否则使用 loader（默认）
+	cppModeConstexpr = "constexpr" // 数据直接编译进头文件的 constexpr 数组，运行时无需解析
+	cppModeLoader    = "loader"    // 只生成结构体和读取 gdb 输出的加载函数，适合大表
+)
+
+// cppRuntimeFile loader 方式依赖的 gdb 读取代码，位于输出目录根部
+const cppRuntimeFile = "gdb_table.h"
+
+// cppNamespacePattern 合法的 C++ 命名空间，可以用 :: 嵌套
+var cppNamespacePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(::[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// cppKeywords C++ 关键字，作为字段名时加下划线后缀
+var cppKeywords = map[string]bool{
+	"alignas": true, "alignof": true, "and": true, "and_eq": true, "asm": true, "auto": true,
+	"bitand": true, "bitor": true, "bool": true, "break": true, "case": true, "catch": true,
+	"char": true, "char8_t": true, "char16_t": true, "char32_t": true, "class": true, "compl": true,
+	"concept": true, "const": true, "consteval": true, "constexpr": true, "constinit": true,
+	"const_cast": true, "continue": true, "co_await": true, "co_return": true, "co_yield": true,
+	"decltype": true, "default": true, "delete": true, "do": true, "double": true,
+	"dynamic_cast": true, "else": true, "enum": true, "explicit": true, "export": true,
+	"extern": true, "false": true, "float": true, "for": true, "friend": true, "goto": true,
+	"if": true, "inline": true, "int": true, "long": true, "mutable": true, "namespace": true,
+	"new": true, "noexcept": true, "not": true, "not_eq": true, "nullptr": true, "operator": true,
+	"or": true, "or_eq": true, "private": true, "protected": true, "public": true, "register": true,
+	"reinterpret_cast": true, "requires": true, "return": true, "short": true, "signed": true,
+	"sizeof": true, "static": true, "static_assert": true, "static_cast": true, "struct": true,
+	"switch": true, "template": true, "this": true, "thread_local": true, "throw": true,
+	"true": true, "try": true, "typedef": true, "typeid": true, "typename": true, "union": true,
+	"unsigned": true, "using": true, "virtual": true, "void": true, "volatile": true,
+	"wchar_t": true, "while": true, "xor": true, "xor_eq": true,
+}
+
+// CppConverter C++头文件生成器：小表生成 constexpr 数组，运行时零解析；大表生成结构体和读取 gdb 输出的加载函数
+type CppConverter struct {
+	config           map[string]interface{}
+	namespace        string            // 命名空间
+	mode             string            // 默认的生成方式
+	constexprMaxRows int               // auto 方式下使用 constexpr 的最大行数
+	sheetModes       map[string]string // 按表名（支持通配符）指定的生成方式
+}
+
+// NewCppConverter 创建C++头文件生成器
+func NewCppConverter() *CppConverter {
+	return &CppConverter{}
+}
+
+// Init 初始化转换器
+func (c *CppConverter) Init(config map[string]interface{}) error {
+	c.config = config
+
+	c.namespace = "gamedata"
+	if namespace, ok := config["namespace"].(string); ok && namespace != "" {
+		if !cppNamespacePattern.MatchString(namespace) {
+			return fmt.Errorf("不合法的 C++ 命名空间: %s", namespace)
+		}
+		c.namespace = namespace
+	}
+
+	c.mode = cppModeAuto
+	if mode, ok := config["mode"].(string); ok && mode != "" {
+		if !validCppMode(mode) {
+			return fmt.Errorf("不支持的 mode: %s", mode)
+		}
+		c.mode = mode
+	}
+
+	c.constexprMaxRows = 256
+	if maxRows, ok := config["constexprMaxRows"].(float64); ok {
+		if maxRows < 0 {
+			return fmt.Errorf("constexprMaxRows 不能为负数: %v", maxRows)
+		}
+		c.constexprMaxRows = int(maxRows)
+	}
+
+	c.sheetModes = make(map[string]string)
+	switch modes := config["sheetModes"].(type) {
+	case nil:
+	case map[string]interface{}:
+		for pattern, item := range modes {
+			mode, ok := item.(string)
+			if !ok || !validCppMode(mode) {
+				return fmt.Errorf("表 %s 不支持的 mode: %v", pattern, item)
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("不合法的表名模式 %s: %v", pattern, err)
+			}
+			c.sheetModes[pattern] = mode
+		}
+	default:
+		return fmt.Errorf("sheetModes 必须是对象: %v", modes)
+	}
+	return nil
+}
+
+// validCppMode 检查生成方式是否支持
+func validCppMode(mode string) bool {
+	return mode == cppModeAuto || mode == cppModeConstexpr || mode == cppModeLoader
+}
+
+// sheetMode 表实际使用的生成方式：精确的表名优先于通配符，多个通配符匹配时取字典序最小的模式
+func (c *CppConverter) sheetMode(sheet *model.DataSheet) string {
+	mode := c.mode
+	if m, ok := c.sheetModes[sheet.Name]; ok {
+		mode = m
+	} else {
+		patterns := make([]string, 0, len(c.sheetModes))
+		for pattern := range c.sheetModes {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, sheet.Name); matched {
+				mode = c.sheetModes[pattern]
+				break
+			}
+		}
+	}
+
+	if mode == cppModeAuto {
+		if len(sheet.Rows) <= c.constexprMaxRows {
+			return cppModeConstexpr
+		}
+		return cppModeLoader
+	}
+	return mode
+}
+
+// cppField 列对应的字段名，如 reward.itemId -> reward_itemId，与关键字重复时加下划线后缀
+func cppField(columnName string) string {
+	ident := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			return r
+		}
+		return '_'
+	}, columnName)
+	if ident == "" || unicode.IsDigit(rune(ident[0])) {
+		ident = "_" + ident
+	}
+	if cppKeywords[ident] {
+		ident += "_"
+	}
+	return ident
+}
+
+// cppColumn 列在生成代码中的信息
+type cppColumn struct {
+	name    string // 列名
+	field   string // 字段名
+	typ     uint8  // gdb 类型
+	comment string // 注释
+}
+
+// cppType gdb 类型对应的 C++ 类型，字符串在 constexpr 方式下为 std::string_view，loader 方式下为 std::string
+func cppType(typ uint8, owned bool) string {
+	switch typ {
+	case GDBTypeInt:
+		return "int64_t"
+	case GDBTypeFloat:
+		return "double"
+	case GDBTypeBool:
+		return "bool"
+	default:
+		if owned {
+			return "std::string"
+		}
+		return "std::string_view"
+	}
+}
+
+// Convert 生成表的头文件
+func (c *CppConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	typeName := pascalIdent(model.SheetIdent(sheet.Name))
+
+	columns := make([]cppColumn, 0, len(sheet.Columns))
+	fields := make(map[string]string, len(sheet.Columns))
+	for _, col := range sheet.Columns {
+		field := cppField(col.Name)
+		if other, exists := fields[field]; exists {
+			return nil, fmt.Errorf("列 %s 与 %s 对应相同的字段名 %s", col.Name, other, field)
+		}
+		fields[field] = col.Name
+		columns = append(columns, cppColumn{name: col.Name, field: field, typ: gdbColumnType(col.Type), comment: columnDoc(col)})
+	}
+
+	fileName := fmt.Sprintf("%s.h", model.SheetPath(sheet.Name))
+	mode := c.sheetMode(sheet)
+
+	var builder strings.Builder
+	builder.WriteString("// 由 game-data-builder 生成，请勿手动修改\n#pragma once\n\n")
+	if mode == cppModeConstexpr {
+		builder.WriteString("#include <array>\n#include <cstdint>\n#include <string_view>\n\n")
+	} else {
+		runtime, _ := filepath.Rel(filepath.Dir(fileName), cppRuntimeFile)
+		builder.WriteString("#include <cstddef>\n#include <cstdint>\n#include <string>\n#include <utility>\n#include <vector>\n\n")
+		builder.WriteString(fmt.Sprintf("#include \"%s\"\n\n", filepath.ToSlash(runtime)))
+	}
+	builder.WriteString(fmt.Sprintf("namespace %s {\n\n", c.namespace))
+
+	builder.WriteString(fmt.Sprintf("// %s\nstruct %s {\n", sheet.Name, typeName))
+	for _, col := range columns {
+		line := fmt.Sprintf("    %s %s;", cppType(col.typ, mode == cppModeLoader), col.field)
+		if col.comment != "" {
+			line += " // " + col.comment
+		}
+		builder.WriteString(line + "\n")
+	}
+	builder.WriteString("};\n\n")
+
+	var err error
+	if mode == cppModeConstexpr {
+		err = c.writeConstexpr(&builder, sheet, typeName, columns)
+	} else {
+		c.writeLoader(&builder, sheet, typeName, columns)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	builder.WriteString(fmt.Sprintf("}  // namespace %s\n", c.namespace))
+
+	result := &model.ConvertResult{
+		FileName: fileName,
+		Content:  []byte(builder.String()),
+		Format:   "cpp",
+	}
+	return result, nil
+}
+
+// writeConstexpr 输出 constexpr 数组 k<类型名>，有主键时同时输出按主键查找的 Find<类型名>
+func (c *CppConverter) writeConstexpr(builder *strings.Builder, sheet *model.DataSheet, typeName string, columns []cppColumn) error {
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return err
+	}
+
+	arrayName := "k" + typeName
+	if len(rows) == 0 {
+		builder.WriteString(fmt.Sprintf("inline constexpr std::array<%s, 0> %s{};\n", typeName, arrayName))
+	} else {
+		builder.WriteString(fmt.Sprintf("inline constexpr std::array<%s, %d> %s{{\n", typeName, len(rows), arrayName))
+		for i, row := range rows {
+			values := make([]string, 0, len(columns))
+			for _, col := range columns {
+				val, _ := model.RowValue(row, col.name)
+				value, err := cppValue(col.typ, val)
+				if err != nil {
+					return fmt.Errorf("第 %d 行 %s 列: %v", sheet.RowNumber(i), col.name, err)
+				}
+				values = append(values, value)
+			}
+			builder.WriteString("    {" + strings.Join(values, ", ") + "},\n")
+		}
+		builder.WriteString("}};\n")
+	}
+
+	for _, col := range columns {
+		if col.name != sheet.PrimaryKey() {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("\n// 按主键 %s 查找，不存在时返回 nullptr\n", col.name))
+		builder.WriteString(fmt.Sprintf("constexpr const %s* Find%s(%s key) {\n", typeName, typeName, cppType(col.typ, false)))
+		builder.WriteString(fmt.Sprintf("    for (const auto& row : %s) {\n", arrayName))
+		builder.WriteString(fmt.Sprintf("        if (row.%s == key) {\n            return &row;\n        }\n    }\n", col.field))
+		builder.WriteString("    return nullptr;\n}\n")
+	}
+	builder.WriteString("\n")
+	return nil
+}
+
+// writeLoader 输出读取 gdb 输出的加载函数 Load<类型名>
+func (c *CppConverter) writeLoader(builder *strings.Builder, sheet *model.DataSheet, typeName string, columns []cppColumn) {
+	builder.WriteString(fmt.Sprintf("// 解析 gdb 转换器输出的 %s，格式不正确时返回 false\n", sheet.Name))
+	builder.WriteString(fmt.Sprintf("inline bool Load%s(const uint8_t* data, size_t size, std::vector<%s>& rows) {\n", typeName, typeName))
+	builder.WriteString("    GdbTable table;\n    if (!table.Parse(data, size)) {\n        return false;\n    }\n")
+	for i, col := range columns {
+		builder.WriteString(fmt.Sprintf("    const int c%d = table.ColumnIndex(%s);\n", i, cppString(col.name)))
+	}
+	builder.WriteString("    rows.clear();\n    rows.reserve(table.RowCount());\n")
+	builder.WriteString("    for (uint32_t r = 0; r < table.RowCount(); ++r) {\n")
+	builder.WriteString(fmt.Sprintf("        %s row{};\n", typeName))
+	for i, col := range columns {
+		var getter string
+		switch col.typ {
+		case GDBTypeInt:
+			getter = fmt.Sprintf("table.GetInt(r, c%d)", i)
+		case GDBTypeFloat:
+			getter = fmt.Sprintf("table.GetFloat(r, c%d)", i)
+		case GDBTypeBool:
+			getter = fmt.Sprintf("table.GetBool(r, c%d)", i)
+		default:
+			getter = fmt.Sprintf("std::string(table.GetString(r, c%d))", i)
+		}
+		builder.WriteString(fmt.Sprintf("        if (c%d >= 0) {\n            row.%s = %s;\n        }\n", i, col.field, getter))
+	}
+	builder.WriteString("        rows.push_back(std::move(row));\n    }\n    return true;\n}\n\n")
+}
+
+// cppValue 将值转换为C++字面量，空值为类型的默认值
+func cppValue(typ uint8, val interface{}) (string, error) {
+	switch typ {
+	case GDBTypeInt:
+		var n int64
+		switch v := val.(type) {
+		case nil:
+		case int:
+			n = int64(v)
+		case int32:
+			n = int64(v)
+		case int64:
+			n = v
+		case float64:
+			if v != math.Trunc(v) {
+				return "", fmt.Errorf("%v 不是整数", v)
+			}
+			n = int64(v)
+		default:
+			return "", fmt.Errorf("%v 不是整数", val)
+		}
+		if n == math.MinInt64 {
+			// -9223372036854775808 会被解析为对超出范围的字面量取负
+			return "INT64_MIN", nil
+		}
+		return strconv.FormatInt(n, 10), nil
+	case GDBTypeFloat:
+		var f float64
+		switch v := val.(type) {
+		case nil:
+		case float64:
+			f = v
+		case float32:
+			f = float64(v)
+		case int:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		default:
+			return "", fmt.Errorf("%v 不是数字", val)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("C++ 字面量不支持的浮点数 %v", f)
+		}
+		text := strconv.FormatFloat(f, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".e") {
+			text += ".0"
+		}
+		return text, nil
+	case GDBTypeBool:
+		switch v := val.(type) {
+		case nil:
+			return "false", nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		default:
+			return "", fmt.Errorf("%v 不是布尔值", val)
+		}
+	default:
+		// 列表、对象等非字符串的值以 JSON 文本输出
+		switch v := val.(type) {
+		case nil:
+			return "{}", nil
+		case string:
+			return cppString(v), nil
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			return cppString(string(data)), nil
+		}
+	}
+}
+
+// cppString 字符串字面量，非 ASCII 字节和控制字符以八进制转义输出，不依赖编译器的源文件编码设置
+func cppString(text string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for i := 0; i < len(text); i++ {
+		b := text[i]
+		switch {
+		case b == '"' || b == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(b)
+		case b == '\n':
+			builder.WriteString("\\n")
+		case b == '\t':
+			builder.WriteString("\\t")
+		case b == '?':
+			// 避免形成三字符组
+			builder.WriteString("\\?")
+		case b < 0x20 || b >= 0x7f:
+			builder.WriteString(fmt.Sprintf("\\%03o", b))
+		default:
+			builder.WriteByte(b)
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// ConvertIndex 有表使用 loader 方式时，生成其依赖的 gdb 读取代码
+func (c *CppConverter) ConvertIndex(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	for _, sheet := range sheets {
+		if c.sheetMode(sheet) == cppModeLoader {
+			content := fmt.Sprintf("// 由 game-data-builder 生成，请勿手动修改\n#pragma once\n\n%snamespace %s {\n\n%s}  // namespace %s\n",
+				cppRuntimeIncludes, c.namespace, cppRuntimeSource, c.namespace)
+			return []*model.ConvertResult{{
+				FileName: cppRuntimeFile,
+				Content:  []byte(content),
+				Format:   "cpp",
+			}}, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetFormat 获取支持的格式类型
+func (c *CppConverter) GetFormat() string {
+	return "cpp"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *CppConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
+
+// cppRuntimeIncludes gdb 读取代码依赖的标准库头文件
+const cppRuntimeIncludes = `#include <cstddef>
+#include <cstdint>
+#include <cstring>
+#include <string_view>
+#include <vector>
+
+`
+
+// cppRuntimeSource gdb 读取代码
+const cppRuntimeSource = `// gdb 二进制表，按列下标读取各行的值，空值返回类型默认值
+// 解析后直接引用传入的数据，调用方需保证数据在使用期间有效
+class GdbTable {
+public:
+    enum Type : uint8_t { kInt = 1, kFloat = 2, kBool = 3, kString = 4 };
+
+    bool Parse(const uint8_t* data, size_t size) {
+        if (size < 28 || std::memcmp(data, "GDB1", 4) != 0 || U16(data + 4) != 1) {
+            return false;
+        }
+        const uint32_t columnCount = U16(data + 6);
+        rowCount_ = U32(data + 8);
+        rowSize_ = U32(data + 12);
+        const uint32_t sheetName = U32(data + 16);
+        const uint32_t stringCount = U32(data + 20);
+        const uint32_t stringDataSize = U32(data + 24);
+
+        const size_t columnTable = 28;
+        const size_t stringTable = columnTable + size_t(columnCount) * 5;
+        const size_t stringData = stringTable + size_t(stringCount) * 8;
+        rowStart_ = stringData + stringDataSize;
+        if (rowStart_ > size || uint64_t(rowSize_) * rowCount_ > size - rowStart_) {
+            return false;
+        }
+
+        strings_.resize(stringCount);
+        for (uint32_t i = 0; i < stringCount; ++i) {
+            const uint32_t offset = U32(data + stringTable + size_t(i) * 8);
+            const uint32_t length = U32(data + stringTable + size_t(i) * 8 + 4);
+            if (uint64_t(offset) + length > stringDataSize) {
+                return false;
+            }
+            strings_[i] = std::string_view(reinterpret_cast<const char*>(data + stringData + offset), length);
+        }
+        if (sheetName >= stringCount) {
+            return false;
+        }
+        name_ = strings_[sheetName];
+
+        columns_.resize(columnCount);
+        uint32_t fieldOffset = (columnCount + 7) / 8;
+        for (uint32_t i = 0; i < columnCount; ++i) {
+            const uint32_t nameIndex = U32(data + columnTable + size_t(i) * 5);
+            if (nameIndex >= stringCount) {
+                return false;
+            }
+            columns_[i].name = strings_[nameIndex];
+            columns_[i].type = data[columnTable + size_t(i) * 5 + 4];
+            columns_[i].offset = fieldOffset;
+            fieldOffset += columns_[i].type == kBool ? 1 : columns_[i].type == kString ? 4 : 8;
+        }
+        data_ = data;
+        return true;
+    }
+
+    std::string_view Name() const { return name_; }
+    uint32_t RowCount() const { return rowCount_; }
+    size_t ColumnCount() const { return columns_.size(); }
+
+    // 按列名查找列下标，不存在时返回 -1
+    int ColumnIndex(std::string_view name) const {
+        for (size_t i = 0; i < columns_.size(); ++i) {
+            if (columns_[i].name == name) {
+                return int(i);
+            }
+        }
+        return -1;
+    }
+
+    bool IsNull(uint32_t row, int column) const {
+        return (Row(row)[column / 8] & (1u << (column % 8))) != 0;
+    }
+
+    int64_t GetInt(uint32_t row, int column) const {
+        return IsNull(row, column) ? 0 : int64_t(U64(Field(row, column)));
+    }
+
+    double GetFloat(uint32_t row, int column) const {
+        if (IsNull(row, column)) {
+            return 0.0;
+        }
+        const uint64_t bits = U64(Field(row, column));
+        double value;
+        std::memcpy(&value, &bits, sizeof(value));
+        return value;
+    }
+
+    bool GetBool(uint32_t row, int column) const {
+        return !IsNull(row, column) && *Field(row, column) != 0;
+    }
+
+    std::string_view GetString(uint32_t row, int column) const {
+        if (IsNull(row, column)) {
+            return {};
+        }
+        const uint32_t index = U32(Field(row, column));
+        return index < strings_.size() ? strings_[index] : std::string_view();
+    }
+
+private:
+    struct Column {
+        std::string_view name;
+        uint8_t type = 0;
+        uint32_t offset = 0;
+    };
+
+    static uint16_t U16(const uint8_t* p) { return uint16_t(p[0] | p[1] << 8); }
+    static uint32_t U32(const uint8_t* p) {
+        return uint32_t(p[0]) | uint32_t(p[1]) << 8 | uint32_t(p[2]) << 16 | uint32_t(p[3]) << 24;
+    }
+    static uint64_t U64(const uint8_t* p) { return uint64_t(U32(p)) | uint64_t(U32(p + 4)) << 32; }
+
+    const uint8_t* Row(uint32_t row) const { return data_ + rowStart_ + size_t(row) * rowSize_; }
+    const uint8_t* Field(uint32_t row, int column) const { return Row(row) + columns_[column].offset; }
+
+    const uint8_t* data_ = nullptr;
+    std::string_view name_;
+    uint32_t rowCount_ = 0;
+    uint32_t rowSize_ = 0;
+    size_t rowStart_ = 0;
+    std::vector<Column> columns_;
+    std::vector<std::string_view> strings_;
+};
+
+`
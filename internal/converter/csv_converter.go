@@ -0,0 +1,210 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// CSV 的引号策略
+const (
+	csvQuoteMinimal = "minimal" // 只在字段包含分隔符、引号、换行或首尾空白时加引号（默认）
+	csvQuoteAll     = "all"     // 所有字段都加引号
+	csvQuoteNone    = "none"    // 不加引号，字段包含分隔符、引号或换行时报错
+)
+
+// CSV 可输出的表头行
+const (
+	csvHeaderName    = "name"    // 列名
+	csvHeaderType    = "type"    // 列类型
+	csvHeaderComment = "comment" // 列注释
+)
+
+// CSVConverter CSV转换器实现，输出经过合并、转换前处理和验证后的最终数据，便于 BI 导入和人工核对
+type CSVConverter struct {
+	config     map[string]interface{}
+	text       textPolicy
+	delimiter  rune     // 字段分隔符
+	quoting    string   // 引号策略
+	headerRows []string // 依次输出的表头行
+	encoding   string   // 输出编码：UTF-8 或 UTF-16
+}
+
+// NewCSVConverter 创建CSV转换器
+func NewCSVConverter() *CSVConverter {
+	return &CSVConverter{}
+}
+
+// Init 初始化转换器
+func (c *CSVConverter) Init(config map[string]interface{}) error {
+	c.config = config
+
+	text, err := parseTextPolicy(config)
+	if err != nil {
+		return err
+	}
+	c.text = text
+
+	c.delimiter = ','
+	if delimiter, ok := config["delimiter"].(string); ok && delimiter != "" {
+		switch delimiter {
+		case "tab", "\\t":
+			delimiter = "\t"
+		}
+		r, size := utf8.DecodeRuneInString(delimiter)
+		if size != len(delimiter) || r == '"' || r == '\r' || r == '\n' {
+			return fmt.Errorf("不支持的分隔符: %q", delimiter)
+		}
+		c.delimiter = r
+	}
+
+	c.quoting = csvQuoteMinimal
+	if quoting, ok := config["quoting"].(string); ok && quoting != "" {
+		if quoting != csvQuoteMinimal && quoting != csvQuoteAll && quoting != csvQuoteNone {
+			return fmt.Errorf("不支持的 quoting: %s", quoting)
+		}
+		c.quoting = quoting
+	}
+
+	c.headerRows = []string{csvHeaderName}
+	switch rows := config["headerRows"].(type) {
+	case nil:
+	case []interface{}:
+		c.headerRows = make([]string, 0, len(rows))
+		for _, item := range rows {
+			role, ok := item.(string)
+			if !ok || (role != csvHeaderName && role != csvHeaderType && role != csvHeaderComment) {
+				return fmt.Errorf("不支持的表头行: %v", item)
+			}
+			c.headerRows = append(c.headerRows, role)
+		}
+	default:
+		return fmt.Errorf("headerRows 必须是数组: %v", rows)
+	}
+
+	c.encoding = "UTF-8"
+	if encoding, ok := config["encoding"].(string); ok && encoding != "" {
+		switch strings.ToUpper(encoding) {
+		case "UTF-8", "UTF8":
+			c.encoding = "UTF-8"
+		case "UTF-16", "UTF16":
+			c.encoding = "UTF-16"
+		default:
+			return fmt.Errorf("不支持的编码: %s", encoding)
+		}
+	}
+	return nil
+}
+
+// Convert 将数据转换为CSV格式，嵌套列以 reward.itemId 形式的列名展开
+func (c *CSVConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	for _, role := range c.headerRows {
+		fields := make([]string, 0, len(sheet.Columns))
+		for _, col := range sheet.Columns {
+			switch role {
+			case csvHeaderName:
+				fields = append(fields, col.Name)
+			case csvHeaderType:
+				fields = append(fields, col.Type)
+			case csvHeaderComment:
+				fields = append(fields, col.Comment)
+			}
+		}
+		if err := c.writeRecord(&builder, fields); err != nil {
+			return nil, fmt.Errorf("表头 %s 行: %v", role, err)
+		}
+	}
+
+	for i, row := range rows {
+		fields := make([]string, 0, len(sheet.Columns))
+		for _, col := range sheet.Columns {
+			val, _ := model.RowValue(row, col.Name)
+			field, err := csvValue(val)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行 %s 列: %v", sheet.RowNumber(i), col.Name, err)
+			}
+			fields = append(fields, field)
+		}
+		if err := c.writeRecord(&builder, fields); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+		}
+	}
+
+	// 换行符在写入记录时处理，字段内的换行保持原样
+	content := []byte(builder.String())
+	if c.encoding == "UTF-16" {
+		content = encodeUTF16(content)
+	} else if c.text.bom {
+		content = append(append([]byte(nil), utf8BOM...), content...)
+	}
+
+	result := &model.ConvertResult{
+		FileName: fmt.Sprintf("%s.csv", model.SheetPath(sheet.Name)),
+		Content:  content,
+		Format:   "csv",
+	}
+	return result, nil
+}
+
+// writeRecord 按引号策略写入一条记录
+func (c *CSVConverter) writeRecord(builder *strings.Builder, fields []string) error {
+	for i, field := range fields {
+		if i > 0 {
+			builder.WriteRune(c.delimiter)
+		}
+
+		special := strings.ContainsRune(field, c.delimiter) || strings.ContainsAny(field, "\"\r\n")
+		switch {
+		case c.quoting == csvQuoteNone && special:
+			return fmt.Errorf("字段 %q 包含分隔符、引号或换行，无法在不加引号时输出", field)
+		case c.quoting == csvQuoteAll, c.quoting == csvQuoteMinimal && (special || strings.TrimSpace(field) != field):
+			builder.WriteString("\"" + strings.ReplaceAll(field, "\"", "\"\"") + "\"")
+		default:
+			builder.WriteString(field)
+		}
+	}
+
+	if c.text.lineEnding == "crlf" {
+		builder.WriteString("\r\n")
+	} else {
+		builder.WriteString("\n")
+	}
+	return nil
+}
+
+// csvValue 单元格值的文本形式，列表和对象以 JSON 文本输出
+func csvValue(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int32, int64, float32, float64:
+		return fmt.Sprintf("%v", v), nil
+	default:
+		content, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	}
+}
+
+// GetFormat 获取支持的格式类型
+func (c *CSVConverter) GetFormat() string {
+	return "csv"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *CSVConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
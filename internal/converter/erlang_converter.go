@@ -0,0 +1,345 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// Erlang 转换器的输出方式
+const (
+	erlangModeTerm   = "term"   // 可由 file:consult/1 读取的项式文件（默认）
+	erlangModeModule = "module" // 按主键生成 get/1 子句的模块源码
+)
+
+// ErlangConverter Erlang转换器实现，行数据输出为以列名为键的 map，字符串输出为 UTF-8 二进制
+type ErlangConverter struct {
+	config        map[string]interface{}
+	text          textPolicy
+	mode          string // 输出方式：term 或 module
+	termExtension string // 项式文件的扩展名：term 或 config
+	modulePrefix  string // 模块名前缀，避免与其他模块重名
+}
+
+// NewErlangConverter 创建Erlang转换器
+func NewErlangConverter() *ErlangConverter {
+	return &ErlangConverter{}
+}
+
+// Init 初始化转换器
+func (c *ErlangConverter) Init(config map[string]interface{}) error {
+	c.config = config
+
+	text, err := parseTextPolicy(config)
+	if err != nil {
+		return err
+	}
+	c.text = text
+
+	c.mode = erlangModeTerm
+	if mode, ok := config["mode"].(string); ok && mode != "" {
+		if mode != erlangModeTerm && mode != erlangModeModule {
+			return fmt.Errorf("不支持的 mode: %s", mode)
+		}
+		c.mode = mode
+	}
+
+	c.termExtension = "term"
+	if ext, ok := config["termExtension"].(string); ok && ext != "" {
+		if ext != "term" && ext != "config" {
+			return fmt.Errorf("不支持的 termExtension: %s", ext)
+		}
+		c.termExtension = ext
+	}
+
+	c.modulePrefix = "cfg_"
+	if prefix, ok := config["modulePrefix"].(string); ok {
+		c.modulePrefix = prefix
+	}
+	return nil
+}
+
+// ErlangFileName 表在指定选项下的输出文件名：项式文件与表同名，模块源码以模块名命名
+func ErlangFileName(sheetName string, config map[string]interface{}) string {
+	conv := NewErlangConverter()
+	if err := conv.Init(config); err != nil {
+		return fmt.Sprintf("%s.term", model.SheetPath(sheetName))
+	}
+	return conv.fileName(sheetName)
+}
+
+// fileName 输出文件名
+func (c *ErlangConverter) fileName(sheetName string) string {
+	if c.mode == erlangModeModule {
+		return filepath.Join(filepath.Dir(model.SheetPath(sheetName)), c.moduleName(sheetName)+".erl")
+	}
+	return fmt.Sprintf("%s.%s", model.SheetPath(sheetName), c.termExtension)
+}
+
+// moduleName 表对应的模块名，如 shop.item -> cfg_shop_item
+func (c *ErlangConverter) moduleName(sheetName string) string {
+	ident := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, model.SheetIdent(sheetName))
+	return c.modulePrefix + ident
+}
+
+// Convert 将数据转换为Erlang格式
+func (c *ErlangConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%%%% 自动生成的 %s 数据文件\n", sheet.Name))
+
+	columnTree := buildColumnTree(sheet.Columns)
+	if c.mode == erlangModeModule {
+		err = c.writeModule(&builder, sheet, rows, columnTree)
+	} else {
+		err = c.writeTerms(&builder, sheet, rows, columnTree)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ConvertResult{
+		FileName: c.fileName(sheet.Name),
+		Content:  c.text.apply([]byte(builder.String())),
+		Format:   "erlang",
+	}
+	return result, nil
+}
+
+// writeTerms 每行输出一个项，按主键输出时为 {Key, Row}，便于直接载入 ets
+func (c *ErlangConverter) writeTerms(builder *strings.Builder, sheet *model.DataSheet, rows []map[string]interface{}, columnTree []*columnNode) error {
+	asMap := rowsAsMap(c.config)
+	if asMap {
+		if _, err := rowKeys(sheet); err != nil {
+			return err
+		}
+	}
+
+	builder.WriteString(fmt.Sprintf("%%%% 表名: %s\n\n", sheet.Name))
+	for i, row := range rows {
+		term, err := erlRow(columnTree, row)
+		if err != nil {
+			return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+		}
+		if asMap {
+			key, err := erlValue(rowKey(sheet, row))
+			if err != nil {
+				return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+			}
+			term = fmt.Sprintf("{%s, %s}", key, term)
+		}
+		builder.WriteString(term + ".\n")
+	}
+	return nil
+}
+
+// writeModule 输出模块源码：get/1 按主键返回行，keys/0 返回全部主键，all/0 返回全部行
+func (c *ErlangConverter) writeModule(builder *strings.Builder, sheet *model.DataSheet, rows []map[string]interface{}, columnTree []*columnNode) error {
+	if _, err := rowKeys(sheet); err != nil {
+		return err
+	}
+
+	builder.WriteString(fmt.Sprintf("-module(%s).\n", erlAtom(c.moduleName(sheet.Name))))
+	builder.WriteString("-export([get/1, keys/0, all/0]).\n\n")
+
+	keys := make([]string, 0, len(rows))
+	for i, row := range rows {
+		key, err := erlValue(rowKey(sheet, row))
+		if err != nil {
+			return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+		}
+		term, err := erlRow(columnTree, row)
+		if err != nil {
+			return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+		}
+		builder.WriteString(fmt.Sprintf("get(%s) -> %s;\n", key, term))
+		keys = append(keys, key)
+	}
+	builder.WriteString("get(_) -> undefined.\n\n")
+
+	builder.WriteString(fmt.Sprintf("keys() -> [%s].\n\n", strings.Join(keys, ", ")))
+	builder.WriteString("all() -> [get(Key) || Key <- keys()].\n")
+	return nil
+}
+
+// erlRow 按列结构输出行数据，嵌套列输出为嵌套 map，缺失的值为 undefined
+func erlRow(nodes []*columnNode, row map[string]interface{}) (string, error) {
+	fields := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		val := row[node.Name]
+		var term string
+		if len(node.Children) > 0 {
+			child, _ := val.(map[string]interface{})
+			nested, err := erlRow(node.Children, child)
+			if err != nil {
+				return "", err
+			}
+			term = nested
+		} else {
+			value, err := erlValue(val)
+			if err != nil {
+				return "", fmt.Errorf("%s 列: %v", node.Name, err)
+			}
+			term = value
+		}
+		fields = append(fields, fmt.Sprintf("%s => %s", erlAtom(node.Name), term))
+	}
+	return "#{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// erlValue 将值转换为Erlang项的文本
+func erlValue(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "undefined", nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float32:
+		return erlFloat(float64(v))
+	case float64:
+		return erlFloat(v)
+	case json.Number:
+		if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return strconv.FormatInt(n, 10), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return "", err
+		}
+		return erlFloat(f)
+	case string:
+		return erlBinary(v), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			term, err := erlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, term)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(keys))
+		for _, key := range keys {
+			term, err := erlValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, fmt.Sprintf("%s => %s", erlAtom(key), term))
+		}
+		return "#{" + strings.Join(fields, ", ") + "}", nil
+	default:
+		// 其他类型（如 []string）经 JSON 转换为通用结构
+		generic, err := jsonValue(v)
+		if err != nil {
+			return "", fmt.Errorf("无法转换为 Erlang 项的值 %v: %v", v, err)
+		}
+		return erlValue(generic)
+	}
+}
+
+// erlFloat 浮点数文本，Erlang 要求浮点数必须带小数点
+func erlFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("Erlang 不支持的浮点数 %v", f)
+	}
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if strings.ContainsAny(text, ".") {
+		return text, nil
+	}
+	if index := strings.IndexByte(text, 'e'); index >= 0 {
+		return text[:index] + ".0" + text[index:], nil
+	}
+	return text + ".0", nil
+}
+
+// erlBinary 字符串输出为 UTF-8 二进制
+func erlBinary(text string) string {
+	var builder strings.Builder
+	builder.WriteString("<<\"")
+	for _, r := range text {
+		switch r {
+		case '"':
+			builder.WriteString("\\\"")
+		case '\\':
+			builder.WriteString("\\\\")
+		case '\n':
+			builder.WriteString("\\n")
+		case '\r':
+			builder.WriteString("\\r")
+		case '\t':
+			builder.WriteString("\\t")
+		default:
+			if r < 0x20 || r == 0x7f {
+				builder.WriteString(fmt.Sprintf("\\x{%x}", r))
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	builder.WriteString("\"/utf8>>")
+	return builder.String()
+}
+
+// erlangAtomPattern 不需要加引号的原子
+var erlangAtomPattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9_@]*$`)
+
+// erlangReserved Erlang 保留字，作为原子时必须加引号
+var erlangReserved = map[string]bool{
+	"after": true, "and": true, "andalso": true, "band": true, "begin": true, "bnot": true,
+	"bor": true, "bsl": true, "bsr": true, "bxor": true, "case": true, "catch": true,
+	"cond": true, "div": true, "else": true, "end": true, "fun": true, "if": true,
+	"let": true, "maybe": true, "not": true, "of": true, "or": true, "orelse": true,
+	"receive": true, "rem": true, "try": true, "when": true, "xor": true,
+}
+
+// erlAtom 原子文本，不是合法的裸原子时加单引号
+func erlAtom(name string) string {
+	if erlangAtomPattern.MatchString(name) && !erlangReserved[name] {
+		return name
+	}
+	escaped := strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(name)
+	return "'" + escaped + "'"
+}
+
+// GetFormat 获取支持的格式类型
+func (c *ErlangConverter) GetFormat() string {
+	return "erlang"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *ErlangConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
@@ -2,19 +2,35 @@ package converter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 
+	flatbuffers "github.com/google/flatbuffers/go"
+
 	"github.com/game-data-builder/internal/model"
 )
 
-// FBSConverter FlatBuffers转换器实现
+// FlatBuffers 字段的标量类型
+const (
+	fbsInt32   = "int32"
+	fbsFloat64 = "float64"
+	fbsBool    = "bool"
+	fbsString  = "string"
+)
+
+// FBSConverter FlatBuffers转换器实现，在进程内按 buildSchema 生成的 schema 编码二进制，不依赖 flatc
 type FBSConverter struct {
-	config map[string]interface{}
+	config     map[string]interface{}
+	verify     bool   // 是否同时用 flatc 从 JSON 生成二进制，并校验与进程内编码的内容一致
+	namespace  string // schema 的命名空间
+	emitSchema bool   // 是否同时输出 common.fbs 和每张表的 .fbs
 }
 
 // NewFBSConverter 创建FlatBuffers转换器
@@ -25,72 +41,41 @@ func NewFBSConverter() *FBSConverter {
 // Init 初始化转换器
 func (c *FBSConverter) Init(config map[string]interface{}) error {
 	c.config = config
+	c.verify, _ = config["verify"].(bool)
+	c.emitSchema, _ = config["emitSchema"].(bool)
+
+	c.namespace = fbsDefaultNamespace
+	if namespace, ok := config["namespace"].(string); ok && namespace != "" {
+		if !fbsNamespacePattern.MatchString(namespace) {
+			return fmt.Errorf("namespace 不是合法的 FlatBuffers 命名空间: %s", namespace)
+		}
+		c.namespace = namespace
+	}
 	return nil
 }
 
 // Convert 将数据转换为FlatBuffers格式
 func (c *FBSConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
-	// 构建FlatBuffers schema
-	schema := c.buildSchema(sheet)
-
-	// 构建JSON数据
-	jsonData := c.buildJSONData(sheet)
-
-	// 保存schema和JSON数据到临时文件
-	tempDir := os.TempDir()
-	schemaPath := filepath.Join(tempDir, fmt.Sprintf("%s.fbs", sheet.Name))
-	jsonPath := filepath.Join(tempDir, fmt.Sprintf("%s.json", sheet.Name))
-	outputPath := filepath.Join(tempDir, fmt.Sprintf("%s.bin", sheet.Name))
-
-	// 写入schema文件
-	if err := os.WriteFile(schemaPath, []byte(schema), 0644); err != nil {
-		return nil, err
-	}
-	defer os.Remove(schemaPath)
+	return c.ConvertContext(context.Background(), sheet)
+}
 
-	// 写入JSON文件
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
+// ConvertContext 将数据转换为FlatBuffers格式，开启 verify 时 ctx 取消会终止 flatc 并删除临时文件
+func (c *FBSConverter) ConvertContext(ctx context.Context, sheet *model.DataSheet) (*model.ConvertResult, error) {
+	rows, err := c.buildRows(sheet)
+	if err != nil {
 		return nil, err
 	}
-	defer os.Remove(jsonPath)
-
-	// 检查flatc命令是否存在
-	if _, err := exec.LookPath("flatc"); err != nil {
-		// flatc命令不存在，返回schema和JSON数据
-		result := &model.ConvertResult{
-			FileName: fmt.Sprintf("%s.fbs", sheet.Name),
-			Content:  []byte(schema),
-			Format:   "fbs",
-		}
-		return result, nil
-	}
+	binContent := c.encode(sheet, rows)
 
-	// 运行flatc命令生成二进制文件
-	cmd := exec.Command("flatc", "-b", schemaPath, jsonPath)
-	cmd.Dir = tempDir
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		// 命令执行失败，返回schema和JSON数据
-		result := &model.ConvertResult{
-			FileName: fmt.Sprintf("%s.fbs", sheet.Name),
-			Content:  []byte(schema),
-			Format:   "fbs",
+	if c.verify {
+		if err := c.verifyWithFlatc(ctx, sheet, rows, binContent); err != nil {
+			return nil, err
 		}
-		return result, nil
 	}
 
-	// 读取生成的二进制文件
-	binContent, err := os.ReadFile(outputPath)
-	if err != nil {
-		return nil, err
-	}
-	defer os.Remove(outputPath)
-
 	// 创建转换结果
 	result := &model.ConvertResult{
-		FileName: fmt.Sprintf("%s.bin", sheet.Name),
+		FileName: fmt.Sprintf("%s.bin", model.SheetPath(sheet.Name)),
 		Content:  binContent,
 		Format:   "fbs",
 	}
@@ -98,78 +83,252 @@ func (c *FBSConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, er
 	return result, nil
 }
 
+// CheckToolchain 开启 verify 时检查 flatc 命令是否可用，否则不依赖外部工具
+func (c *FBSConverter) CheckToolchain() error {
+	if !c.verify {
+		return nil
+	}
+	if _, err := exec.LookPath("flatc"); err != nil {
+		return fmt.Errorf("未找到 flatc 命令")
+	}
+	return nil
+}
+
 // GetFormat 获取支持的格式类型
 func (c *FBSConverter) GetFormat() string {
 	return "fbs"
 }
 
-// BatchConvert 批量转换多个数据表
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
 func (c *FBSConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
-	results := make([]*model.ConvertResult, 0)
+	return batchConvert(c, sheets)
+}
 
-	for _, sheet := range sheets {
-		result, err := c.Convert(sheet)
-		if err != nil {
-			return nil, err
+// buildRows 按输出顺序把行数据转换为各列 FlatBuffers 类型的值，值为空的列不输出（读取时为字段默认值）
+func (c *FBSConverter) buildRows(sheet *model.DataSheet) ([]map[string]interface{}, error) {
+	sorted, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]interface{}, 0, len(sorted))
+	for i, row := range sorted {
+		rowData := make(map[string]interface{})
+		for _, col := range sheet.Columns {
+			val, exists := model.RowValue(row, col.Name)
+			if !exists || val == nil {
+				continue
+			}
+			value, err := fbsValue(val, c.getFBSType(col.Type))
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行 %s 列: %v", sheet.RowNumber(i), col.Name, err)
+			}
+			rowData[c.fieldName(col.Name)] = value
 		}
-		results = append(results, result)
+		rows = append(rows, rowData)
 	}
+	return rows, nil
+}
 
-	return results, nil
+// fbsValue 将单元格的值转换为字段类型的值
+func fbsValue(val interface{}, fbsType string) (interface{}, error) {
+	switch fbsType {
+	case fbsInt32:
+		n, ok := fbsNumber(val)
+		if !ok || n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, fmt.Errorf("无法转换为 int32 的值 %v", val)
+		}
+		return int32(n), nil
+	case fbsFloat64:
+		n, ok := fbsNumber(val)
+		if !ok {
+			return nil, fmt.Errorf("无法转换为 float64 的值 %v", val)
+		}
+		return n, nil
+	case fbsBool:
+		if b, ok := val.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("无法转换为 bool 的值 %v", val)
+	default:
+		switch v := val.(type) {
+		case string:
+			return v, nil
+		case []interface{}, map[string]interface{}:
+			// 列表和对象以 JSON 文本保存
+			content, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(content), nil
+		default:
+			return fmt.Sprintf("%v", v), nil
+		}
+	}
 }
 
-// buildSchema 构建FlatBuffers schema
-func (c *FBSConverter) buildSchema(sheet *model.DataSheet) string {
-	var builder strings.Builder
+// fbsNumber 数值类型的值
+func fbsNumber(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
 
-	// 添加文件头
-	builder.WriteString(fmt.Sprintf("// 自动生成的 %s 数据schema\n\n", sheet.Name))
+// encode 按 schema 的字段顺序编码整张表，内容相同时输出逐字节一致
+func (c *FBSConverter) encode(sheet *model.DataSheet, rows []map[string]interface{}) []byte {
+	b := flatbuffers.NewBuilder(1024)
 
-	// 定义数据结构
-	builder.WriteString(fmt.Sprintf("namespace %s;\n\n", sheet.Name))
+	// 子对象必须在父对象开始之前创建，按从里到外的顺序写入
+	columns := make([]flatbuffers.UOffsetT, 0, len(sheet.Columns))
+	for _, col := range sheet.Columns {
+		name := b.CreateString(col.Name)
+		comment := b.CreateString(col.Comment)
+		var defaultValue flatbuffers.UOffsetT
+		if col.Default != nil {
+			defaultValue = b.CreateString(fmt.Sprintf("%v", col.Default))
+		}
+		options := fbsStringVector(b, col.Options)
+
+		b.StartObject(6)
+		b.PrependUOffsetTSlot(0, name, 0)
+		b.PrependByteSlot(1, byte(c.getColumnTypeValue(col.Type)), 0)
+		b.PrependUOffsetTSlot(2, comment, 0)
+		b.PrependBoolSlot(3, col.Required, true)
+		b.PrependUOffsetTSlot(4, defaultValue, 0)
+		b.PrependUOffsetTSlot(5, options, 0)
+		columns = append(columns, b.EndObject())
+	}
 
-	// 定义列类型枚举
-	builder.WriteString("enum ColumnType : byte {\n")
-	builder.WriteString("    INT,\n")
-	builder.WriteString("    FLOAT,\n")
-	builder.WriteString("    BOOL,\n")
-	builder.WriteString("    STRING,\n")
-	builder.WriteString("}\n\n")
+	rowOffsets := make([]flatbuffers.UOffsetT, 0, len(rows))
+	for _, row := range rows {
+		strs := make(map[int]flatbuffers.UOffsetT)
+		for slot, col := range sheet.Columns {
+			if s, ok := row[c.fieldName(col.Name)].(string); ok {
+				strs[slot] = b.CreateString(s)
+			}
+		}
 
-	// 定义列信息结构
-	builder.WriteString("table ColumnInfo {\n")
-	builder.WriteString("    name:string;\n")
-	builder.WriteString("    type:ColumnType;\n")
-	builder.WriteString("    comment:string;\n")
-	builder.WriteString("    required:bool = true;\n")
-	builder.WriteString("    default:string;\n")
-	builder.WriteString("    options:[string];\n")
-	builder.WriteString("}\n\n")
+		b.StartObject(len(sheet.Columns))
+		for slot, col := range sheet.Columns {
+			switch v := row[c.fieldName(col.Name)].(type) {
+			case int32:
+				b.PrependInt32Slot(slot, v, 0)
+			case float64:
+				b.PrependFloat64Slot(slot, v, 0)
+			case bool:
+				b.PrependBoolSlot(slot, v, false)
+			case string:
+				b.PrependUOffsetTSlot(slot, strs[slot], 0)
+			}
+		}
+		rowOffsets = append(rowOffsets, b.EndObject())
+	}
 
-	// 定义行数据结构
-	builder.WriteString(fmt.Sprintf("table RowData_%s {\n", sheet.Name))
-	for _, col := range sheet.Columns {
-		fbsType := c.getFBSType(col.Type)
-		builder.WriteString(fmt.Sprintf("    %s:%s;\n", col.Name, fbsType))
+	meta := make([]string, 0, len(sheet.Meta))
+	for _, key := range sortedMetaKeys(sheet.Meta) {
+		meta = append(meta, fmt.Sprintf("%s:%v", key, sheet.Meta[key]))
 	}
-	builder.WriteString("}\n\n")
 
-	// 定义数据表结构
-	builder.WriteString(fmt.Sprintf("table Data_%s {\n", sheet.Name))
-	builder.WriteString("    name:string;\n")
-	builder.WriteString("    columns:[ColumnInfo];\n")
-	builder.WriteString(fmt.Sprintf("    rows:[RowData_%s];\n", sheet.Name))
-	builder.WriteString("    meta:[string];\n")
-	builder.WriteString("}\n\n")
+	name := b.CreateString(sheet.Name)
+	columnVector := b.CreateVectorOfTables(columns)
+	rowVector := b.CreateVectorOfTables(rowOffsets)
+	metaVector := fbsStringVector(b, meta)
+
+	b.StartObject(4)
+	b.PrependUOffsetTSlot(0, name, 0)
+	b.PrependUOffsetTSlot(1, columnVector, 0)
+	b.PrependUOffsetTSlot(2, rowVector, 0)
+	b.PrependUOffsetTSlot(3, metaVector, 0)
+	b.Finish(b.EndObject())
+	return b.FinishedBytes()
+}
 
-	// 定义根类型
-	builder.WriteString(fmt.Sprintf("root_type Data_%s;\n", sheet.Name))
+// fbsStringVector 创建字符串向量
+func fbsStringVector(b *flatbuffers.Builder, items []string) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, 0, len(items))
+	for _, item := range items {
+		offsets = append(offsets, b.CreateString(item))
+	}
+	b.StartVector(4, len(offsets), 4)
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	return b.EndVector(len(offsets))
+}
 
-	return builder.String()
+// verifyWithFlatc 用 flatc 从 JSON 生成二进制，解码后与进程内编码的结果逐字段比较
+func (c *FBSConverter) verifyWithFlatc(ctx context.Context, sheet *model.DataSheet, rows []map[string]interface{}, binContent []byte) error {
+	tempDir, err := os.MkdirTemp("", "fbs-verify-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	ident := model.SheetIdent(sheet.Name)
+	commonPath := filepath.Join(tempDir, fbsCommonSchema)
+	schemaPath := filepath.Join(tempDir, fmt.Sprintf("%s.fbs", ident))
+	jsonPath := filepath.Join(tempDir, fmt.Sprintf("%s.json", ident))
+	outputPath := filepath.Join(tempDir, fmt.Sprintf("%s.bin", ident))
+
+	jsonData, err := c.buildJSONData(sheet, rows)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(commonPath, []byte(c.buildCommonSchema()), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(schemaPath, []byte(c.buildSchema(sheet, fbsCommonSchema)), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "flatc", "-b", schemaPath, jsonPath)
+	cmd.Dir = tempDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("flatc 生成 %s 失败: %v: %s", sheet.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	expected, err := os.ReadFile(outputPath)
+	if err != nil {
+		return err
+	}
+
+	want, err := c.decode(sheet, expected)
+	if err != nil {
+		return fmt.Errorf("解析 flatc 输出失败: %v", err)
+	}
+	got, err := c.decode(sheet, binContent)
+	if err != nil {
+		return fmt.Errorf("解析 %s 的编码结果失败: %v", sheet.Name, err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		return fmt.Errorf("%s 的编码结果与 flatc 不一致", sheet.Name)
+	}
+	return nil
 }
 
-// buildJSONData 构建JSON数据
-func (c *FBSConverter) buildJSONData(sheet *model.DataSheet) []byte {
+// buildJSONData 构建 flatc 使用的JSON数据
+func (c *FBSConverter) buildJSONData(sheet *model.DataSheet, rows []map[string]interface{}) ([]byte, error) {
 	// 转换数据
 	data := make(map[string]interface{})
 	data["name"] = sheet.Name
@@ -189,50 +348,150 @@ func (c *FBSConverter) buildJSONData(sheet *model.DataSheet) []byte {
 		columns = append(columns, colData)
 	}
 	data["columns"] = columns
+	data["rows"] = rows
+
+	// 转换元数据
+	meta := make([]string, 0)
+	for _, key := range sortedMetaKeys(sheet.Meta) {
+		meta = append(meta, fmt.Sprintf("%s:%v", key, sheet.Meta[key]))
+	}
+	data["meta"] = meta
+
+	// 格式化JSON
+	return json.MarshalIndent(data, "", "  ")
+}
+
+// decode 按表的 schema 解码二进制，返回与字段布局无关的内容，用于比较两种编码结果
+func (c *FBSConverter) decode(sheet *model.DataSheet, content []byte) (result map[string]interface{}, err error) {
+	// 损坏的数据会导致越界访问
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("数据损坏: %v", r)
+		}
+	}()
+
+	root := &flatbuffers.Table{Bytes: content, Pos: flatbuffers.GetUOffsetT(content)}
+	result = map[string]interface{}{"name": fbsTableString(root, 0)}
+
+	columns := make([]map[string]interface{}, 0)
+	for _, col := range fbsTables(root, 1) {
+		column := map[string]interface{}{
+			"name":     fbsTableString(col, 0),
+			"type":     byte(0),
+			"comment":  fbsTableString(col, 2),
+			"required": true,
+			"default":  fbsTableString(col, 4),
+			"options":  fbsStrings(col, 5),
+		}
+		if o := flatbuffers.UOffsetT(col.Offset(fbsSlot(1))); o != 0 {
+			column["type"] = col.GetByte(o + col.Pos)
+		}
+		if o := flatbuffers.UOffsetT(col.Offset(fbsSlot(3))); o != 0 {
+			column["required"] = col.GetBool(o + col.Pos)
+		}
+		columns = append(columns, column)
+	}
+	result["columns"] = columns
 
-	// 转换行数据
 	rows := make([]map[string]interface{}, 0)
-	for _, row := range sheet.Rows {
+	for _, row := range fbsTables(root, 2) {
 		rowData := make(map[string]interface{})
-		for _, col := range sheet.Columns {
-			if val, exists := row[col.Name]; exists {
-				rowData[col.Name] = val
+		for slot, col := range sheet.Columns {
+			o := flatbuffers.UOffsetT(row.Offset(fbsSlot(slot)))
+			if o == 0 {
+				continue
+			}
+			switch c.getFBSType(col.Type) {
+			case fbsInt32:
+				rowData[col.Name] = row.GetInt32(o + row.Pos)
+			case fbsFloat64:
+				rowData[col.Name] = row.GetFloat64(o + row.Pos)
+			case fbsBool:
+				rowData[col.Name] = row.GetBool(o + row.Pos)
+			default:
+				rowData[col.Name] = row.String(o + row.Pos)
 			}
 		}
 		rows = append(rows, rowData)
 	}
-	data["rows"] = rows
+	result["rows"] = rows
+	result["meta"] = fbsStrings(root, 3)
+	return result, nil
+}
 
-	// 转换元数据
-	meta := make([]string, 0)
-	for key, val := range sheet.Meta {
-		meta = append(meta, fmt.Sprintf("%s:%v", key, val))
+// fbsSlot 字段在 vtable 中的偏移
+func fbsSlot(slot int) flatbuffers.VOffsetT {
+	return flatbuffers.VOffsetT(4 + 2*slot)
+}
+
+// fbsTableString 读取字符串字段，不存在时为空字符串
+func fbsTableString(t *flatbuffers.Table, slot int) string {
+	if o := flatbuffers.UOffsetT(t.Offset(fbsSlot(slot))); o != 0 {
+		return t.String(o + t.Pos)
 	}
-	data["meta"] = meta
+	return ""
+}
 
-	// 格式化JSON
-	content, _ := json.MarshalIndent(data, "", "  ")
-	return content
+// fbsStrings 读取字符串向量字段
+func fbsStrings(t *flatbuffers.Table, slot int) []string {
+	items := make([]string, 0)
+	o := flatbuffers.UOffsetT(t.Offset(fbsSlot(slot)))
+	if o == 0 {
+		return items
+	}
+	vector := t.Vector(o)
+	for i := 0; i < t.VectorLen(o); i++ {
+		items = append(items, t.String(vector+flatbuffers.UOffsetT(i*4)))
+	}
+	return items
+}
+
+// fbsTables 读取表向量字段
+func fbsTables(t *flatbuffers.Table, slot int) []*flatbuffers.Table {
+	tables := make([]*flatbuffers.Table, 0)
+	o := flatbuffers.UOffsetT(t.Offset(fbsSlot(slot)))
+	if o == 0 {
+		return tables
+	}
+	vector := t.Vector(o)
+	for i := 0; i < t.VectorLen(o); i++ {
+		pos := t.Indirect(vector + flatbuffers.UOffsetT(i*4))
+		tables = append(tables, &flatbuffers.Table{Bytes: t.Bytes, Pos: pos})
+	}
+	return tables
+}
+
+// fieldName 获取FlatBuffers字段名，嵌套列展开为 reward_itemId 形式
+func (c *FBSConverter) fieldName(colName string) string {
+	return strings.ReplaceAll(colName, ".", "_")
 }
 
 // getFBSType 获取FlatBuffers类型
 func (c *FBSConverter) getFBSType(colType string) string {
+	if _, ok := model.EnumName(colType); ok {
+		return fbsInt32
+	}
+
 	switch colType {
 	case "int", "integer":
-		return "int32"
+		return fbsInt32
 	case "float", "double", "number":
-		return "float64"
+		return fbsFloat64
 	case "bool", "boolean":
-		return "bool"
+		return fbsBool
 	case "string":
-		return "string"
+		return fbsString
 	default:
-		return "string"
+		return fbsString
 	}
 }
 
 // getColumnTypeValue 获取列类型枚举值
 func (c *FBSConverter) getColumnTypeValue(colType string) int {
+	if _, ok := model.EnumName(colType); ok {
+		return 0
+	}
+
 	switch colType {
 	case "int", "integer":
 		return 0
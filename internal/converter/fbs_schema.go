@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// fbsCommonSchema 各表共用的 ColumnType、ColumnInfo 所在的 schema 文件
+const fbsCommonSchema = "common.fbs"
+
+// fbsDefaultNamespace 未配置 namespace 时 schema 使用的命名空间
+const fbsDefaultNamespace = "GameData"
+
+// fbsNamespacePattern 合法的 FlatBuffers 命名空间，如 Game.Data
+var fbsNamespacePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// buildCommonSchema 构建各表共用的 schema：列类型枚举和列信息表
+func (c *FBSConverter) buildCommonSchema() string {
+	var builder strings.Builder
+
+	builder.WriteString("// 由 game-data-builder 生成，请勿手动修改\n\n")
+	builder.WriteString(fmt.Sprintf("namespace %s;\n\n", c.namespace))
+
+	// 定义列类型枚举
+	builder.WriteString("enum ColumnType : byte {\n")
+	builder.WriteString("    INT,\n")
+	builder.WriteString("    FLOAT,\n")
+	builder.WriteString("    BOOL,\n")
+	builder.WriteString("    STRING,\n")
+	builder.WriteString("}\n\n")
+
+	// 定义列信息结构
+	builder.WriteString("table ColumnInfo {\n")
+	builder.WriteString("    name:string;\n")
+	builder.WriteString("    type:ColumnType;\n")
+	builder.WriteString("    comment:string;\n")
+	builder.WriteString("    required:bool = true;\n")
+	builder.WriteString("    default:string;\n")
+	builder.WriteString("    options:[string];\n")
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// buildSchema 构建表的 schema，通过 include 引用共用的 schema，include 为相对于该 schema 所在目录的路径
+func (c *FBSConverter) buildSchema(sheet *model.DataSheet, include string) string {
+	var builder strings.Builder
+
+	// 添加文件头
+	builder.WriteString("// 由 game-data-builder 生成，请勿手动修改\n")
+	builder.WriteString(fmt.Sprintf("// 表名: %s\n\n", sheet.Name))
+	builder.WriteString(fmt.Sprintf("include %q;\n\n", include))
+	builder.WriteString(fmt.Sprintf("namespace %s;\n\n", c.namespace))
+
+	// 定义行数据结构，带命名空间的表名在标识符中用下划线连接
+	ident := model.SheetIdent(sheet.Name)
+	builder.WriteString(fmt.Sprintf("table RowData_%s {\n", ident))
+	for _, col := range sheet.Columns {
+		fbsType := c.getFBSType(col.Type)
+		builder.WriteString(fmt.Sprintf("    %s:%s;\n", c.fieldName(col.Name), fbsType))
+	}
+	builder.WriteString("}\n\n")
+
+	// 定义数据表结构
+	builder.WriteString(fmt.Sprintf("table Data_%s {\n", ident))
+	builder.WriteString("    name:string;\n")
+	builder.WriteString("    columns:[ColumnInfo];\n")
+	builder.WriteString(fmt.Sprintf("    rows:[RowData_%s];\n", ident))
+	builder.WriteString("    meta:[string];\n")
+	builder.WriteString("}\n\n")
+
+	// 定义根类型
+	builder.WriteString(fmt.Sprintf("root_type Data_%s;\n", ident))
+
+	return builder.String()
+}
+
+// fbsIncludePath 表的 schema 引用共用 schema 的相对路径，如 shop.item 的 shop/item.fbs 引用 ../common.fbs
+func fbsIncludePath(sheetName string) string {
+	depth := strings.Count(sheetName, model.NamespaceSeparator)
+	return strings.Repeat("../", depth) + fbsCommonSchema
+}
+
+// ConvertIndex 开启 emitSchema 时输出共用的 common.fbs 和每张表的 <表名>.fbs，供客户端用 flatc 生成读取代码
+func (c *FBSConverter) ConvertIndex(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	if !c.emitSchema {
+		return nil, nil
+	}
+
+	results := []*model.ConvertResult{{
+		FileName: fbsCommonSchema,
+		Content:  []byte(c.buildCommonSchema()),
+		Format:   "fbs",
+	}}
+	for _, sheet := range sheets {
+		if model.SheetPath(sheet.Name)+".fbs" == fbsCommonSchema {
+			return nil, fmt.Errorf("表 %s 的 schema 与共用的 %s 同名", sheet.Name, fbsCommonSchema)
+		}
+		results = append(results, &model.ConvertResult{
+			FileName: fmt.Sprintf("%s.fbs", model.SheetPath(sheet.Name)),
+			Content:  []byte(c.buildSchema(sheet, fbsIncludePath(sheet.Name))),
+			Format:   "fbs",
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,283 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// GDBMagic gdb 文件头标识
+const GDBMagic = "GDB1"
+
+// GDBVersion gdb 格式版本
+const GDBVersion uint16 = 1
+
+// GDBHeaderSize gdb 文件头长度
+const GDBHeaderSize = 28
+
+// gdb 列类型
+const (
+	GDBTypeInt    uint8 = 1 // int64
+	GDBTypeFloat  uint8 = 2 // float64
+	GDBTypeBool   uint8 = 3 // uint8，0 或 1
+	GDBTypeString uint8 = 4 // uint32 字符串池下标
+)
+
+// GDBConverter 内置的二进制转换器，不依赖外部工具
+//
+// 格式（小端序）：
+// 文件头 magic[4] | version uint16 | columnCount uint16 | rowCount uint32 | rowSize uint32 |
+// sheetName uint32 | stringCount uint32 | stringDataSize uint32
+// 列描述 columnCount 个 { name uint32 | type uint8 }，name 为字符串池下标
+// 字符串表 stringCount 个 { offset uint32 | length uint32 }，offset 相对于字符串数据起始位置
+// 字符串数据 stringDataSize 字节的 UTF-8 文本
+// 行记录 rowCount 个定长记录，每条为空值位图 (columnCount+7)/8 字节，之后按列顺序存放各列的值
+type GDBConverter struct {
+	config map[string]interface{}
+	stubs  []string // 生成读取代码的语言：csharp、go
+}
+
+// NewGDBConverter 创建 gdb 转换器
+func NewGDBConverter() *GDBConverter {
+	return &GDBConverter{}
+}
+
+// Init 初始化转换器
+func (c *GDBConverter) Init(config map[string]interface{}) error {
+	c.config = config
+	c.stubs = nil
+
+	switch v := config["stubs"].(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range v {
+			language, ok := item.(string)
+			if !ok || (language != stubCSharp && language != stubGo) {
+				return fmt.Errorf("不支持的读取代码语言: %v", item)
+			}
+			c.stubs = append(c.stubs, language)
+		}
+	default:
+		return fmt.Errorf("stubs 必须是语言数组: %v", v)
+	}
+	return nil
+}
+
+// gdbColumnType 列类型对应的 gdb 类型，枚举按整数存储，其余类型按字符串存储
+func gdbColumnType(colType string) uint8 {
+	if _, ok := model.EnumName(colType); ok {
+		return GDBTypeInt
+	}
+	switch colType {
+	case "int", "integer":
+		return GDBTypeInt
+	case "float", "double", "number":
+		return GDBTypeFloat
+	case "bool", "boolean":
+		return GDBTypeBool
+	default:
+		return GDBTypeString
+	}
+}
+
+// gdbTypeWidth 各类型在行记录中的宽度
+func gdbTypeWidth(typ uint8) int {
+	switch typ {
+	case GDBTypeBool:
+		return 1
+	case GDBTypeString:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// stringPool 去重的字符串池
+type stringPool struct {
+	index   map[string]uint32
+	strings []string
+}
+
+// add 加入字符串并返回下标
+func (p *stringPool) add(text string) uint32 {
+	if index, exists := p.index[text]; exists {
+		return index
+	}
+	index := uint32(len(p.strings))
+	p.index[text] = index
+	p.strings = append(p.strings, text)
+	return index
+}
+
+// Convert 将数据转换为 gdb 格式
+func (c *GDBConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	if len(sheet.Columns) > math.MaxUint16 {
+		return nil, fmt.Errorf("列数 %d 超过上限 %d", len(sheet.Columns), math.MaxUint16)
+	}
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &stringPool{index: make(map[string]uint32)}
+	sheetName := pool.add(sheet.Name)
+
+	// 列描述
+	types := make([]uint8, len(sheet.Columns))
+	var columns bytes.Buffer
+	nullBytes := (len(sheet.Columns) + 7) / 8
+	rowSize := nullBytes
+	for i, col := range sheet.Columns {
+		types[i] = gdbColumnType(col.Type)
+		binary.Write(&columns, binary.LittleEndian, pool.add(col.Name))
+		columns.WriteByte(types[i])
+		rowSize += gdbTypeWidth(types[i])
+	}
+
+	// 行记录
+	records := make([]byte, rowSize*len(rows))
+	for rowIndex, row := range rows {
+		record := records[rowIndex*rowSize : (rowIndex+1)*rowSize]
+		offset := nullBytes
+		for i, col := range sheet.Columns {
+			val, exists := model.RowValue(row, col.Name)
+			if !exists || val == nil {
+				record[i/8] |= 1 << (i % 8)
+			} else if err := putGDBValue(record[offset:], types[i], val, pool); err != nil {
+				return nil, fmt.Errorf("第 %d 行 %s 列: %v", sheet.RowNumber(rowIndex), col.Name, err)
+			}
+			offset += gdbTypeWidth(types[i])
+		}
+	}
+
+	// 字符串表和字符串数据
+	var stringTable, stringData bytes.Buffer
+	for _, text := range pool.strings {
+		binary.Write(&stringTable, binary.LittleEndian, uint32(stringData.Len()))
+		binary.Write(&stringTable, binary.LittleEndian, uint32(len(text)))
+		stringData.WriteString(text)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(GDBMagic)
+	binary.Write(&buf, binary.LittleEndian, GDBVersion)
+	binary.Write(&buf, binary.LittleEndian, uint16(len(sheet.Columns)))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(rows)))
+	binary.Write(&buf, binary.LittleEndian, uint32(rowSize))
+	binary.Write(&buf, binary.LittleEndian, sheetName)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(pool.strings)))
+	binary.Write(&buf, binary.LittleEndian, uint32(stringData.Len()))
+	buf.Write(columns.Bytes())
+	buf.Write(stringTable.Bytes())
+	buf.Write(stringData.Bytes())
+	buf.Write(records)
+
+	result := &model.ConvertResult{
+		FileName: fmt.Sprintf("%s.gdb", model.SheetPath(sheet.Name)),
+		Content:  buf.Bytes(),
+		Format:   "gdb",
+	}
+	return result, nil
+}
+
+// putGDBValue 将值写入行记录中该列的位置
+func putGDBValue(field []byte, typ uint8, val interface{}, pool *stringPool) error {
+	switch typ {
+	case GDBTypeInt:
+		var n int64
+		switch v := val.(type) {
+		case int:
+			n = int64(v)
+		case int32:
+			n = int64(v)
+		case int64:
+			n = v
+		case float64:
+			if v != math.Trunc(v) {
+				return fmt.Errorf("%v 不是整数", v)
+			}
+			n = int64(v)
+		default:
+			return fmt.Errorf("%v 不是整数", val)
+		}
+		binary.LittleEndian.PutUint64(field, uint64(n))
+	case GDBTypeFloat:
+		var f float64
+		switch v := val.(type) {
+		case float64:
+			f = v
+		case float32:
+			f = float64(v)
+		case int:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		default:
+			return fmt.Errorf("%v 不是数字", val)
+		}
+		binary.LittleEndian.PutUint64(field, math.Float64bits(f))
+	case GDBTypeBool:
+		v, ok := val.(bool)
+		if !ok {
+			return fmt.Errorf("%v 不是布尔值", val)
+		}
+		if v {
+			field[0] = 1
+		}
+	default:
+		// 列表、对象等非字符串的值以 JSON 文本存储
+		text, ok := val.(string)
+		if !ok {
+			data, err := json.Marshal(val)
+			if err != nil {
+				return err
+			}
+			text = string(data)
+		}
+		binary.LittleEndian.PutUint32(field, pool.add(text))
+	}
+	return nil
+}
+
+// ConvertIndex 按 stubs 选项生成各语言的读取代码，未配置时不生成
+func (c *GDBConverter) ConvertIndex(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	results := make([]*model.ConvertResult, 0, len(c.stubs))
+	for _, language := range c.stubs {
+		var result *model.ConvertResult
+		switch language {
+		case stubCSharp:
+			namespace, _ := c.config["stubNamespace"].(string)
+			if namespace == "" {
+				namespace = "GameData"
+			}
+			result = &model.ConvertResult{FileName: "GdbTables.cs", Content: []byte(csharpStub(namespace, sheets))}
+		case stubGo:
+			pkg, _ := c.config["stubPackage"].(string)
+			if pkg == "" {
+				pkg = "gamedata"
+			}
+			source, err := goStub(pkg, sheets)
+			if err != nil {
+				return nil, err
+			}
+			result = &model.ConvertResult{FileName: "gdb_tables.go", Content: source}
+		}
+		result.Format = "gdb"
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetFormat 获取支持的格式类型
+func (c *GDBConverter) GetFormat() string {
+	return "gdb"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *GDBConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
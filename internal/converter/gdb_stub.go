@@ -0,0 +1,505 @@
+package converter
+
+import (
+	"fmt"
+	"go/format"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// 读取代码的语言
+const (
+	stubCSharp = "csharp"
+	stubGo     = "go"
+)
+
+// pascalIdent 将表名或列名转换为首字母大写的标识符，非字母数字的字符作为单词分隔
+func pascalIdent(name string) string {
+	ident := identWords(name)
+	if ident == "" || unicode.IsDigit([]rune(ident)[0]) {
+		return "T" + ident
+	}
+	return ident
+}
+
+// identWords 将文本中的每个单词首字母大写后连接，去掉非字母数字的字符，结果可能以数字开头
+func identWords(name string) string {
+	var builder strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// columnDoc 生成代码中列的单行说明：注释中的说明文字加上类型行中声明的列转换，两者都没有时为空
+func columnDoc(col model.ColumnInfo) string {
+	doc := strings.NewReplacer("\r", "", "\n", " ").Replace(commentText(col.Comment))
+	if len(col.Transforms) > 0 {
+		doc = strings.TrimSpace(fmt.Sprintf("%s (转换: %s)", doc, strings.Join(col.Transforms, "|")))
+	}
+	return doc
+}
+
+// commentText 去掉注释中的主键、必填、默认值、选项、引用和资源等元数据，只保留策划写的说明文字
+func commentText(comment string) string {
+	parts := make([]string, 0)
+	for _, part := range strings.Split(comment, "|") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "", part == "主键", strings.EqualFold(part, "key"),
+			strings.HasPrefix(part, "必填"), strings.HasPrefix(part, "选填"),
+			strings.HasPrefix(part, "默认:"), strings.HasPrefix(part, "选项:"), strings.HasPrefix(part, "引用:"), strings.HasPrefix(part, "资源:"):
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// optionConstant 列的一个可选值对应的常量
+type optionConstant struct {
+	ident  string // 常量名中可选值的部分
+	value  string // 常量的字面量，C# 和 Go 中写法相同
+	option string // 原始的可选值
+}
+
+// optionConstants 列的可选值（注释中的 选项:a,b,c）对应的常量；布尔列和与列类型不符的可选值不生成常量，
+// 可选值转换后的标识符为空或重复时以序号命名
+func optionConstants(col model.ColumnInfo) []optionConstant {
+	typ := gdbColumnType(col.Type)
+	if typ == GDBTypeBool {
+		return nil
+	}
+
+	constants := make([]optionConstant, 0, len(col.Options))
+	seen := make(map[string]bool)
+	for i, option := range col.Options {
+		option = strings.TrimSpace(option)
+		var value string
+		switch typ {
+		case GDBTypeInt:
+			number, err := strconv.ParseInt(option, 10, 64)
+			if err != nil {
+				continue
+			}
+			value = strconv.FormatInt(number, 10)
+		case GDBTypeFloat:
+			number, err := strconv.ParseFloat(option, 64)
+			if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+				continue
+			}
+			value = strconv.FormatFloat(number, 'g', -1, 64)
+		default:
+			value = strconv.Quote(option)
+		}
+
+		ident := identWords(option)
+		if ident == "" || seen[ident] {
+			ident = fmt.Sprintf("Option%d", i+1)
+		}
+		seen[ident] = true
+		constants = append(constants, optionConstant{ident: ident, value: value, option: option})
+	}
+	return constants
+}
+
+// optionDoc 可选值常量的说明：列的说明文字加上可选值
+func optionDoc(col model.ColumnInfo, constant optionConstant) string {
+	if doc := columnDoc(col); doc != "" {
+		return fmt.Sprintf("%s: %s", doc, constant.option)
+	}
+	return fmt.Sprintf("%s: %s", col.Name, constant.option)
+}
+
+// stubSheets 需要生成读取代码的表，跳过枚举定义等内部表
+func stubSheets(sheets []*model.DataSheet) []*model.DataSheet {
+	filtered := make([]*model.DataSheet, 0, len(sheets))
+	for _, sheet := range sheets {
+		if strings.HasPrefix(sheet.Name, "@") {
+			continue
+		}
+		filtered = append(filtered, sheet)
+	}
+	return filtered
+}
+
+// csharpStub 生成 C# 读取代码：通用的 GdbTable 和每张表的行类型
+func csharpStub(namespace string, sheets []*model.DataSheet) string {
+	var builder strings.Builder
+	builder.WriteString("// <auto-generated>\n// 由 game-data-builder 生成，请勿手动修改\n// </auto-generated>\n")
+	builder.WriteString("using System;\nusing System.Collections.Generic;\nusing System.Text;\n\n")
+	builder.WriteString(fmt.Sprintf("namespace %s\n{\n", namespace))
+	builder.WriteString(csharpRuntime)
+
+	for _, sheet := range stubSheets(sheets) {
+		typeName := pascalIdent(model.SheetIdent(sheet.Name))
+		builder.WriteString(fmt.Sprintf("\n    /// <summary>%s</summary>\n", sheet.Name))
+		builder.WriteString(fmt.Sprintf("    public sealed class %s\n    {\n", typeName))
+		for _, col := range sheet.Columns {
+			if doc := columnDoc(col); doc != "" {
+				builder.WriteString(fmt.Sprintf("        /// <summary>%s</summary>\n", doc))
+			}
+			builder.WriteString(fmt.Sprintf("        public %s %s;\n", csharpType(gdbColumnType(col.Type)), pascalIdent(col.Name)))
+		}
+		for _, col := range sheet.Columns {
+			constants := optionConstants(col)
+			if len(constants) > 0 {
+				builder.WriteString("\n")
+			}
+			for _, constant := range constants {
+				builder.WriteString(fmt.Sprintf("        /// <summary>%s</summary>\n", optionDoc(col, constant)))
+				builder.WriteString(fmt.Sprintf("        public const %s %s%s = %s;\n", csharpType(gdbColumnType(col.Type)), pascalIdent(col.Name), constant.ident, constant.value))
+			}
+		}
+
+		builder.WriteString(fmt.Sprintf("\n        public static List<%s> Load(byte[] data)\n        {\n", typeName))
+		builder.WriteString("            var table = new GdbTable(data);\n")
+		for i, col := range sheet.Columns {
+			builder.WriteString(fmt.Sprintf("            int c%d = table.ColumnIndex(%q);\n", i, col.Name))
+		}
+		builder.WriteString(fmt.Sprintf("            var rows = new List<%s>(table.RowCount);\n", typeName))
+		builder.WriteString("            for (int r = 0; r < table.RowCount; r++)\n            {\n")
+		builder.WriteString(fmt.Sprintf("                var row = new %s();\n", typeName))
+		for i, col := range sheet.Columns {
+			builder.WriteString(fmt.Sprintf("                if (c%d >= 0) row.%s = table.%s(r, c%d);\n", i, pascalIdent(col.Name), gdbGetter(gdbColumnType(col.Type)), i))
+		}
+		builder.WriteString("                rows.Add(row);\n            }\n            return rows;\n        }\n    }\n")
+	}
+
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// csharpType gdb 类型对应的 C# 类型
+func csharpType(typ uint8) string {
+	switch typ {
+	case GDBTypeInt:
+		return "long"
+	case GDBTypeFloat:
+		return "double"
+	case GDBTypeBool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// gdbGetter gdb 类型对应的读取方法，C# 和 Go 的读取代码中同名
+func gdbGetter(typ uint8) string {
+	switch typ {
+	case GDBTypeInt:
+		return "GetInt"
+	case GDBTypeFloat:
+		return "GetFloat"
+	case GDBTypeBool:
+		return "GetBool"
+	default:
+		return "GetString"
+	}
+}
+
+// goStub 生成 Go 读取代码：通用的 GDBTable 和每张表的行类型，输出经过 gofmt 格式化
+func goStub(pkg string, sheets []*model.DataSheet) ([]byte, error) {
+	var builder strings.Builder
+	builder.WriteString("// Code generated by game-data-builder. DO NOT EDIT.\n\n")
+	builder.WriteString(fmt.Sprintf("package %s\n", pkg))
+	builder.WriteString(goRuntime)
+
+	for _, sheet := range stubSheets(sheets) {
+		typeName := pascalIdent(model.SheetIdent(sheet.Name))
+		builder.WriteString(fmt.Sprintf("\n// %s %s\ntype %s struct {\n", typeName, sheet.Name, typeName))
+		for _, col := range sheet.Columns {
+			if doc := columnDoc(col); doc != "" {
+				builder.WriteString(fmt.Sprintf("\t// %s %s\n", pascalIdent(col.Name), doc))
+			}
+			builder.WriteString(fmt.Sprintf("\t%s %s\n", pascalIdent(col.Name), goType(gdbColumnType(col.Type))))
+		}
+		builder.WriteString("}\n")
+
+		for _, col := range sheet.Columns {
+			constants := optionConstants(col)
+			if len(constants) == 0 {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("\n// %s.%s 的可选值\nconst (\n", typeName, pascalIdent(col.Name)))
+			for _, constant := range constants {
+				name := typeName + pascalIdent(col.Name) + constant.ident
+				builder.WriteString(fmt.Sprintf("\t// %s %s\n\t%s %s = %s\n", name, optionDoc(col, constant), name, goType(gdbColumnType(col.Type)), constant.value))
+			}
+			builder.WriteString(")\n")
+		}
+
+		builder.WriteString(fmt.Sprintf("\n// Load%s 解析 %s 的 gdb 数据\n", typeName, sheet.Name))
+		builder.WriteString(fmt.Sprintf("func Load%s(data []byte) ([]*%s, error) {\n", typeName, typeName))
+		builder.WriteString("\ttable, err := ParseGDBTable(data)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+		for i, col := range sheet.Columns {
+			builder.WriteString(fmt.Sprintf("\tc%d := table.ColumnIndex(%q)\n", i, col.Name))
+		}
+		builder.WriteString(fmt.Sprintf("\trows := make([]*%s, table.RowCount)\n", typeName))
+		builder.WriteString(fmt.Sprintf("\tfor r := range rows {\n\t\trow := &%s{}\n", typeName))
+		for i, col := range sheet.Columns {
+			builder.WriteString(fmt.Sprintf("\t\tif c%d >= 0 {\n\t\t\trow.%s = table.%s(r, c%d)\n\t\t}\n", i, pascalIdent(col.Name), gdbGetter(gdbColumnType(col.Type)), i))
+		}
+		builder.WriteString("\t\trows[r] = row\n\t}\n\treturn rows, nil\n}\n")
+	}
+
+	source, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		return nil, fmt.Errorf("生成的 Go 读取代码有误: %v", err)
+	}
+	return source, nil
+}
+
+// goType gdb 类型对应的 Go 类型
+func goType(typ uint8) string {
+	switch typ {
+	case GDBTypeInt:
+		return "int64"
+	case GDBTypeFloat:
+		return "float64"
+	case GDBTypeBool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// csharpRuntime C# 读取代码中与表无关的部分
+const csharpRuntime = `    /// <summary>gdb 二进制表，按列下标读取各行的值，空值返回类型默认值</summary>
+    public sealed class GdbTable
+    {
+        public const byte TypeInt = 1, TypeFloat = 2, TypeBool = 3, TypeString = 4;
+
+        public string Name { get; private set; }
+        public int RowCount { get; private set; }
+
+        readonly byte[] data;
+        readonly string[] columnNames;
+        readonly byte[] columnTypes;
+        readonly int[] columnOffsets;
+        readonly string[] strings;
+        readonly int rowStart, rowSize, nullBytes;
+
+        public GdbTable(byte[] data)
+        {
+            if (data.Length < 28 || Encoding.ASCII.GetString(data, 0, 4) != "GDB1")
+                throw new FormatException("not a gdb file");
+            if (ReadU16(data, 4) != 1)
+                throw new FormatException("unsupported gdb version");
+
+            this.data = data;
+            int columnCount = ReadU16(data, 6);
+            RowCount = (int)ReadU32(data, 8);
+            rowSize = (int)ReadU32(data, 12);
+            int sheetName = (int)ReadU32(data, 16);
+            int stringCount = (int)ReadU32(data, 20);
+            int stringDataSize = (int)ReadU32(data, 24);
+
+            int pos = 28;
+            int stringTable = pos + columnCount * 5;
+            int stringData = stringTable + stringCount * 8;
+            strings = new string[stringCount];
+            for (int i = 0; i < stringCount; i++)
+            {
+                int offset = (int)ReadU32(data, stringTable + i * 8);
+                int length = (int)ReadU32(data, stringTable + i * 8 + 4);
+                strings[i] = Encoding.UTF8.GetString(data, stringData + offset, length);
+            }
+            rowStart = stringData + stringDataSize;
+            if (rowStart + RowCount * rowSize > data.Length)
+                throw new FormatException("truncated gdb file");
+            Name = strings[sheetName];
+
+            nullBytes = (columnCount + 7) / 8;
+            columnNames = new string[columnCount];
+            columnTypes = new byte[columnCount];
+            columnOffsets = new int[columnCount];
+            int fieldOffset = nullBytes;
+            for (int i = 0; i < columnCount; i++)
+            {
+                columnNames[i] = strings[ReadU32(data, pos + i * 5)];
+                columnTypes[i] = data[pos + i * 5 + 4];
+                columnOffsets[i] = fieldOffset;
+                fieldOffset += columnTypes[i] == TypeBool ? 1 : columnTypes[i] == TypeString ? 4 : 8;
+            }
+        }
+
+        public int ColumnCount { get { return columnNames.Length; } }
+        public string ColumnName(int column) { return columnNames[column]; }
+        public byte ColumnType(int column) { return columnTypes[column]; }
+
+        /// <summary>按列名查找列下标，不存在时返回 -1</summary>
+        public int ColumnIndex(string name) { return Array.IndexOf(columnNames, name); }
+
+        public bool IsNull(int row, int column)
+        {
+            return (data[rowStart + row * rowSize + column / 8] & (1 << (column % 8))) != 0;
+        }
+
+        public long GetInt(int row, int column)
+        {
+            return IsNull(row, column) ? 0 : (long)ReadU64(data, Field(row, column));
+        }
+
+        public double GetFloat(int row, int column)
+        {
+            return IsNull(row, column) ? 0 : BitConverter.Int64BitsToDouble((long)ReadU64(data, Field(row, column)));
+        }
+
+        public bool GetBool(int row, int column)
+        {
+            return !IsNull(row, column) && data[Field(row, column)] != 0;
+        }
+
+        public string GetString(int row, int column)
+        {
+            return IsNull(row, column) ? null : strings[ReadU32(data, Field(row, column))];
+        }
+
+        int Field(int row, int column) { return rowStart + row * rowSize + columnOffsets[column]; }
+
+        static int ReadU16(byte[] b, int i) { return b[i] | b[i + 1] << 8; }
+        static uint ReadU32(byte[] b, int i) { return (uint)(b[i] | b[i + 1] << 8 | b[i + 2] << 16 | b[i + 3] << 24); }
+        static ulong ReadU64(byte[] b, int i) { return ReadU32(b, i) | (ulong)ReadU32(b, i + 4) << 32; }
+    }
+`
+
+// goRuntime Go 读取代码中与表无关的部分
+const goRuntime = `
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// GDB column types
+const (
+	GDBTypeInt    = 1
+	GDBTypeFloat  = 2
+	GDBTypeBool   = 3
+	GDBTypeString = 4
+)
+
+// GDBTable is a parsed gdb binary table; getters return zero values for nulls.
+type GDBTable struct {
+	Name     string
+	RowCount int
+
+	data          []byte
+	columnNames   []string
+	columnTypes   []byte
+	columnOffsets []int
+	strings       []string
+	rowStart      int
+	rowSize       int
+}
+
+// ParseGDBTable parses a gdb file produced by game-data-builder.
+func ParseGDBTable(data []byte) (*GDBTable, error) {
+	if len(data) < 28 || string(data[:4]) != "GDB1" {
+		return nil, errors.New("not a gdb file")
+	}
+	if binary.LittleEndian.Uint16(data[4:]) != 1 {
+		return nil, errors.New("unsupported gdb version")
+	}
+
+	columnCount := int(binary.LittleEndian.Uint16(data[6:]))
+	t := &GDBTable{data: data, RowCount: int(binary.LittleEndian.Uint32(data[8:])), rowSize: int(binary.LittleEndian.Uint32(data[12:]))}
+	sheetName := binary.LittleEndian.Uint32(data[16:])
+	stringCount := int(binary.LittleEndian.Uint32(data[20:]))
+	stringDataSize := int(binary.LittleEndian.Uint32(data[24:]))
+
+	pos := 28
+	stringTable := pos + columnCount*5
+	stringData := stringTable + stringCount*8
+	t.rowStart = stringData + stringDataSize
+	if t.rowStart+t.RowCount*t.rowSize > len(data) {
+		return nil, errors.New("truncated gdb file")
+	}
+	t.strings = make([]string, stringCount)
+	for i := range t.strings {
+		offset := stringData + int(binary.LittleEndian.Uint32(data[stringTable+i*8:]))
+		length := int(binary.LittleEndian.Uint32(data[stringTable+i*8+4:]))
+		t.strings[i] = string(data[offset : offset+length])
+	}
+	t.Name = t.strings[sheetName]
+
+	fieldOffset := (columnCount + 7) / 8
+	for i := 0; i < columnCount; i++ {
+		t.columnNames = append(t.columnNames, t.strings[binary.LittleEndian.Uint32(data[pos+i*5:])])
+		typ := data[pos+i*5+4]
+		t.columnTypes = append(t.columnTypes, typ)
+		t.columnOffsets = append(t.columnOffsets, fieldOffset)
+		switch typ {
+		case GDBTypeBool:
+			fieldOffset++
+		case GDBTypeString:
+			fieldOffset += 4
+		default:
+			fieldOffset += 8
+		}
+	}
+	return t, nil
+}
+
+// ColumnIndex returns the index of the named column, or -1 if absent.
+func (t *GDBTable) ColumnIndex(name string) int {
+	for i, columnName := range t.columnNames {
+		if columnName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsNull reports whether the cell is empty.
+func (t *GDBTable) IsNull(row, column int) bool {
+	return t.data[t.rowStart+row*t.rowSize+column/8]&(1<<(column%8)) != 0
+}
+
+func (t *GDBTable) field(row, column int) []byte {
+	return t.data[t.rowStart+row*t.rowSize+t.columnOffsets[column]:]
+}
+
+// GetInt reads an int cell.
+func (t *GDBTable) GetInt(row, column int) int64 {
+	if t.IsNull(row, column) {
+		return 0
+	}
+	return int64(binary.LittleEndian.Uint64(t.field(row, column)))
+}
+
+// GetFloat reads a float cell.
+func (t *GDBTable) GetFloat(row, column int) float64 {
+	if t.IsNull(row, column) {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(t.field(row, column)))
+}
+
+// GetBool reads a bool cell.
+func (t *GDBTable) GetBool(row, column int) bool {
+	return !t.IsNull(row, column) && t.field(row, column)[0] != 0
+}
+
+// GetString reads a string cell.
+func (t *GDBTable) GetString(row, column int) string {
+	if t.IsNull(row, column) {
+		return ""
+	}
+	return t.strings[binary.LittleEndian.Uint32(t.field(row, column))]
+}
+`
@@ -0,0 +1,339 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// Godot 转换器的输出方式
+const (
+	godotModeScript   = "gd"   // 以常量保存数据的 GDScript 脚本（默认）
+	godotModeResource = "tres" // 使用共享 GameDataTable 脚本的文本资源
+)
+
+// godotTableScript tres 模式下所有资源共用的脚本文件名
+const godotTableScript = "game_data_table.gd"
+
+// godotClassPattern 合法的 GDScript 类名
+var godotClassPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// GodotConverter Godot转换器实现，每张表输出一个 GDScript 脚本或 .tres 资源，行数据为以列名为键的 Dictionary
+type GodotConverter struct {
+	config      map[string]interface{}
+	text        textPolicy
+	mode        string // 输出方式：gd 或 tres
+	resPath     string // 输出目录在 Godot 项目中的 res:// 路径（以 / 结尾），tres 资源据此引用共享脚本
+	classPrefix string // 不为空时 gd 脚本以 前缀+表名 声明 class_name
+}
+
+// NewGodotConverter 创建Godot转换器
+func NewGodotConverter() *GodotConverter {
+	return &GodotConverter{}
+}
+
+// Init 初始化转换器
+func (c *GodotConverter) Init(config map[string]interface{}) error {
+	c.config = config
+
+	text, err := parseTextPolicy(config)
+	if err != nil {
+		return err
+	}
+	c.text = text
+
+	c.mode = godotModeScript
+	if mode, ok := config["mode"].(string); ok && mode != "" {
+		if mode != godotModeScript && mode != godotModeResource {
+			return fmt.Errorf("不支持的 mode: %s", mode)
+		}
+		c.mode = mode
+	}
+
+	c.resPath = "res://data/"
+	if resPath, ok := config["resPath"].(string); ok && resPath != "" {
+		if !strings.HasPrefix(resPath, "res://") {
+			return fmt.Errorf("resPath 必须以 res:// 开头: %s", resPath)
+		}
+		c.resPath = strings.TrimSuffix(resPath, "/") + "/"
+		if resPath == "res://" {
+			c.resPath = resPath
+		}
+	}
+
+	if prefix, ok := config["classPrefix"].(string); ok && prefix != "" {
+		if !godotClassPattern.MatchString(prefix) {
+			return fmt.Errorf("classPrefix 不是合法的类名前缀: %s", prefix)
+		}
+		c.classPrefix = prefix
+	}
+	return nil
+}
+
+// GodotFileName 表在指定选项下的输出文件名：gd 模式为 .gd 脚本，tres 模式为 .tres 资源
+func GodotFileName(sheetName string, config map[string]interface{}) string {
+	conv := NewGodotConverter()
+	if err := conv.Init(config); err != nil {
+		return model.SheetPath(sheetName) + ".gd"
+	}
+	return conv.fileName(sheetName)
+}
+
+// fileName 输出文件名
+func (c *GodotConverter) fileName(sheetName string) string {
+	return model.SheetPath(sheetName) + "." + c.mode
+}
+
+// Convert 将数据转换为Godot格式
+func (c *GodotConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	columnTree := buildColumnTree(sheet.Columns)
+	values := make([]string, 0, len(rows))
+	for i, row := range rows {
+		value, err := godotRow(columnTree, row)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+		}
+		values = append(values, value)
+	}
+
+	var builder strings.Builder
+	if c.mode == godotModeResource {
+		c.writeResource(&builder, sheet, values)
+	} else if err := c.writeScript(&builder, sheet, rows, values); err != nil {
+		return nil, err
+	}
+
+	result := &model.ConvertResult{
+		FileName: c.fileName(sheet.Name),
+		Content:  c.text.apply([]byte(builder.String())),
+		Format:   "godot",
+	}
+	return result, nil
+}
+
+// writeScript 输出 GDScript 脚本：ROWS 为全部行，有主键时 INDEX 为主键到行下标的映射，get_row 按主键返回行
+func (c *GodotConverter) writeScript(builder *strings.Builder, sheet *model.DataSheet, rows []map[string]interface{}, values []string) error {
+	builder.WriteString("# 由 game-data-builder 生成，请勿手动修改\n")
+	builder.WriteString(fmt.Sprintf("# 表名: %s\n", sheet.Name))
+	if c.classPrefix != "" {
+		builder.WriteString(fmt.Sprintf("class_name %s%s\n", c.classPrefix, pascalIdent(model.SheetIdent(sheet.Name))))
+	}
+	builder.WriteString("extends RefCounted\n\n")
+
+	keyColumn := sheet.PrimaryKey()
+	builder.WriteString(fmt.Sprintf("const KEY := %s\n\n", godotString(keyColumn)))
+	builder.WriteString("const ROWS := [\n")
+	for _, value := range values {
+		builder.WriteString("\t" + value + ",\n")
+	}
+	builder.WriteString("]\n")
+	if keyColumn == "" {
+		return nil
+	}
+
+	if _, err := rowKeys(sheet); err != nil {
+		return err
+	}
+	builder.WriteString("\nconst INDEX := {\n")
+	for i, row := range rows {
+		key, err := godotValue(rowKey(sheet, row))
+		if err != nil {
+			return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+		}
+		builder.WriteString(fmt.Sprintf("\t%s: %d,\n", key, i))
+	}
+	builder.WriteString("}\n\n")
+	builder.WriteString("static func get_row(key) -> Dictionary:\n")
+	builder.WriteString("\treturn ROWS[INDEX[key]] if INDEX.has(key) else {}\n")
+	return nil
+}
+
+// writeResource 输出以共享脚本 GameDataTable 为类型的 .tres 资源
+func (c *GodotConverter) writeResource(builder *strings.Builder, sheet *model.DataSheet, values []string) {
+	builder.WriteString("[gd_resource type=\"Resource\" script_class=\"GameDataTable\" load_steps=2 format=3]\n\n")
+	builder.WriteString(fmt.Sprintf("[ext_resource type=\"Script\" path=%s id=\"1\"]\n\n", godotString(c.resPath+godotTableScript)))
+	builder.WriteString("[resource]\n")
+	builder.WriteString("script = ExtResource(\"1\")\n")
+	builder.WriteString(fmt.Sprintf("sheet = %s\n", godotString(sheet.Name)))
+	builder.WriteString(fmt.Sprintf("key = %s\n", godotString(sheet.PrimaryKey())))
+	builder.WriteString("rows = [" + strings.Join(values, ", ") + "]\n")
+}
+
+// godotTableSource tres 资源共用的脚本，首次按主键查询时建立索引
+const godotTableSource = `# 由 game-data-builder 生成，请勿手动修改
+class_name GameDataTable
+extends Resource
+
+@export var sheet: String = ""
+@export var key: String = ""
+@export var rows: Array = []
+
+var _index: Dictionary = {}
+
+func get_row(value) -> Dictionary:
+	if _index.is_empty() and key != "":
+		for i in rows.size():
+			_index[rows[i].get(key)] = i
+	return rows[_index[value]] if _index.has(value) else {}
+`
+
+// ConvertIndex tres 模式下生成资源共用的 GameDataTable 脚本，gd 模式的脚本各自独立，不生成汇总文件
+func (c *GodotConverter) ConvertIndex(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	if c.mode != godotModeResource {
+		return nil, nil
+	}
+	return []*model.ConvertResult{{
+		FileName: godotTableScript,
+		Content:  []byte(godotTableSource),
+		Format:   "godot",
+	}}, nil
+}
+
+// godotRow 按列结构输出行数据，嵌套列输出为嵌套 Dictionary，缺失的值为 null
+func godotRow(nodes []*columnNode, row map[string]interface{}) (string, error) {
+	fields := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		val := row[node.Name]
+		var value string
+		if len(node.Children) > 0 {
+			child, _ := val.(map[string]interface{})
+			nested, err := godotRow(node.Children, child)
+			if err != nil {
+				return "", err
+			}
+			value = nested
+		} else {
+			text, err := godotValue(val)
+			if err != nil {
+				return "", fmt.Errorf("%s 列: %v", node.Name, err)
+			}
+			value = text
+		}
+		fields = append(fields, fmt.Sprintf("%s: %s", godotString(node.Name), value))
+	}
+	return "{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// godotValue 将值转换为 GDScript 和 Godot 文本资源通用的字面量
+func godotValue(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float32:
+		return godotFloat(float64(v))
+	case float64:
+		return godotFloat(v)
+	case json.Number:
+		if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return strconv.FormatInt(n, 10), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return "", err
+		}
+		return godotFloat(f)
+	case string:
+		return godotString(v), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			value, err := godotValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, value)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(keys))
+		for _, key := range keys {
+			value, err := godotValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, fmt.Sprintf("%s: %s", godotString(key), value))
+		}
+		return "{" + strings.Join(fields, ", ") + "}", nil
+	default:
+		// 其他类型（如 []string）经 JSON 转换为通用结构
+		generic, err := jsonValue(v)
+		if err != nil {
+			return "", fmt.Errorf("无法转换为 Godot 字面量的值 %v: %v", v, err)
+		}
+		return godotValue(generic)
+	}
+}
+
+// godotFloat 浮点数文本，必须带小数点或指数，否则会被解析为整数
+func godotFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("不支持的浮点数 %v", f)
+	}
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if strings.ContainsAny(text, ".e") {
+		return text, nil
+	}
+	return text + ".0", nil
+}
+
+// godotString 双引号字符串，转义引号、反斜杠和控制字符
+func godotString(text string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, r := range text {
+		switch r {
+		case '"':
+			builder.WriteString("\\\"")
+		case '\\':
+			builder.WriteString("\\\\")
+		case '\n':
+			builder.WriteString("\\n")
+		case '\r':
+			builder.WriteString("\\r")
+		case '\t':
+			builder.WriteString("\\t")
+		default:
+			if r < 0x20 || r == 0x7f {
+				builder.WriteString(fmt.Sprintf("\\u%04x", r))
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// GetFormat 获取支持的格式类型
+func (c *GodotConverter) GetFormat() string {
+	return "godot"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *GodotConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
@@ -0,0 +1,551 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// Java 代码的生成方式
+const (
+	javaStyleClass  = "class"  // 不可变的 POJO，字段通过 getter 访问（默认）
+	javaStyleRecord = "record" // Java 16 及以上的 record
+)
+
+// Java 读取代码支持的数据格式
+const (
+	javaLoaderJSON = "json" // 读取 JSON 转换器的输出，依赖 org.json（Android 内置）
+	javaLoaderGDB  = "gdb"  // 读取 gdb 转换器的输出，不依赖第三方库
+)
+
+// Java 运行时类名，表的类名不能与之重复
+const (
+	javaJSONRuntime = "GameDataJson"
+	javaGDBRuntime  = "GdbTable"
+)
+
+// javaPackagePattern 合法的 Java 包名
+var javaPackagePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// javaKeywords Java 关键字和保留字，作为字段名时加下划线后缀
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true, "case": true,
+	"catch": true, "char": true, "class": true, "const": true, "continue": true, "default": true,
+	"do": true, "double": true, "else": true, "enum": true, "extends": true, "final": true,
+	"finally": true, "float": true, "for": true, "goto": true, "if": true, "implements": true,
+	"import": true, "instanceof": true, "int": true, "interface": true, "long": true, "native": true,
+	"new": true, "package": true, "private": true, "protected": true, "public": true, "return": true,
+	"short": true, "static": true, "strictfp": true, "super": true, "switch": true, "synchronized": true,
+	"this": true, "throw": true, "throws": true, "transient": true, "try": true, "void": true,
+	"volatile": true, "while": true, "true": true, "false": true, "null": true, "var": true,
+	"record": true, "yield": true,
+}
+
+// JavaConverter Java代码生成器，为每张表生成一个类型安全的行类型，以及读取 JSON 或 gdb 输出的加载方法
+type JavaConverter struct {
+	config      map[string]interface{}
+	packageName string   // 包名
+	style       string   // 生成方式：class 或 record
+	loaders     []string // 生成加载方法的数据格式：json、gdb
+}
+
+// NewJavaConverter 创建Java代码生成器
+func NewJavaConverter() *JavaConverter {
+	return &JavaConverter{}
+}
+
+// Init 初始化转换器
+func (c *JavaConverter) Init(config map[string]interface{}) error {
+	c.config = config
+
+	c.packageName = "gamedata"
+	if pkg, ok := config["package"].(string); ok && pkg != "" {
+		if !javaPackagePattern.MatchString(pkg) {
+			return fmt.Errorf("不合法的 Java 包名: %s", pkg)
+		}
+		for _, part := range strings.Split(pkg, ".") {
+			if javaKeywords[part] {
+				return fmt.Errorf("不合法的 Java 包名: %s", pkg)
+			}
+		}
+		c.packageName = pkg
+	}
+
+	c.style = javaStyleClass
+	if style, ok := config["style"].(string); ok && style != "" {
+		if style != javaStyleClass && style != javaStyleRecord {
+			return fmt.Errorf("不支持的 style: %s", style)
+		}
+		c.style = style
+	}
+
+	c.loaders = []string{javaLoaderJSON}
+	switch loaders := config["loaders"].(type) {
+	case nil:
+	case []interface{}:
+		c.loaders = make([]string, 0, len(loaders))
+		for _, item := range loaders {
+			loader, ok := item.(string)
+			if !ok || (loader != javaLoaderJSON && loader != javaLoaderGDB) {
+				return fmt.Errorf("不支持的加载格式: %v", item)
+			}
+			c.loaders = append(c.loaders, loader)
+		}
+	default:
+		return fmt.Errorf("loaders 必须是数组: %v", loaders)
+	}
+	return nil
+}
+
+// JavaFileName 表在指定选项下生成的源文件名，位于包名对应的目录中
+func JavaFileName(sheetName string, config map[string]interface{}) string {
+	conv := NewJavaConverter()
+	if err := conv.Init(config); err != nil {
+		conv.packageName = "gamedata"
+	}
+	return conv.sourcePath(javaTypeName(sheetName))
+}
+
+// sourcePath 类对应的源文件路径
+func (c *JavaConverter) sourcePath(typeName string) string {
+	return filepath.Join(filepath.Join(strings.Split(c.packageName, ".")...), typeName+".java")
+}
+
+// hasLoader 是否生成指定格式的加载方法
+func (c *JavaConverter) hasLoader(loader string) bool {
+	for _, item := range c.loaders {
+		if item == loader {
+			return true
+		}
+	}
+	return false
+}
+
+// javaTypeName 表对应的类名，如 shop.item -> ShopItem
+func javaTypeName(sheetName string) string {
+	return pascalIdent(model.SheetIdent(sheetName))
+}
+
+// javaField 列对应的字段名，首字母小写，与关键字重复时加下划线后缀
+func javaField(columnName string) string {
+	ident := pascalIdent(columnName)
+	r, size := utf8.DecodeRuneInString(ident)
+	ident = string(unicode.ToLower(r)) + ident[size:]
+	if javaKeywords[ident] {
+		ident += "_"
+	}
+	return ident
+}
+
+// javaDoc 可安全放入文档注释的文本
+func javaDoc(text string) string {
+	text = strings.ReplaceAll(text, "\r", "")
+	text = strings.ReplaceAll(text, "\n", " ")
+	return strings.ReplaceAll(text, "*/", "* /")
+}
+
+// javaType gdb 类型对应的 Java 类型，列表、对象等以 JSON 文本的字符串表示
+func javaType(typ uint8) string {
+	switch typ {
+	case GDBTypeInt:
+		return "long"
+	case GDBTypeFloat:
+		return "double"
+	case GDBTypeBool:
+		return "boolean"
+	default:
+		return "String"
+	}
+}
+
+// javaZero Java 类型的默认值
+func javaZero(typ uint8) string {
+	switch typ {
+	case GDBTypeInt:
+		return "0L"
+	case GDBTypeFloat:
+		return "0.0"
+	case GDBTypeBool:
+		return "false"
+	default:
+		return "null"
+	}
+}
+
+// javaJSONGetter 读取 JSON 字段的辅助方法
+func javaJSONGetter(typ uint8) string {
+	switch typ {
+	case GDBTypeInt:
+		return "getLong"
+	case GDBTypeFloat:
+		return "getDouble"
+	case GDBTypeBool:
+		return "getBoolean"
+	default:
+		return "getString"
+	}
+}
+
+// javaColumn 列在生成代码中的信息
+type javaColumn struct {
+	name    string // 列名
+	field   string // 字段名
+	typ     uint8  // gdb 类型
+	comment string // 注释
+}
+
+// Convert 生成表的行类型及加载方法
+func (c *JavaConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	typeName := javaTypeName(sheet.Name)
+	if typeName == javaJSONRuntime || typeName == javaGDBRuntime {
+		return nil, fmt.Errorf("类名 %s 与运行时类重复", typeName)
+	}
+
+	columns := make([]javaColumn, 0, len(sheet.Columns))
+	fields := make(map[string]string, len(sheet.Columns))
+	for _, col := range sheet.Columns {
+		field := javaField(col.Name)
+		if other, exists := fields[field]; exists {
+			return nil, fmt.Errorf("列 %s 与 %s 对应相同的字段名 %s", col.Name, other, field)
+		}
+		fields[field] = col.Name
+		columns = append(columns, javaColumn{name: col.Name, field: field, typ: gdbColumnType(col.Type), comment: javaDoc(columnDoc(col))})
+	}
+
+	var builder strings.Builder
+	builder.WriteString("// 由 game-data-builder 生成，请勿手动修改\n")
+	builder.WriteString(fmt.Sprintf("package %s;\n\n", c.packageName))
+	builder.WriteString("import java.util.ArrayList;\nimport java.util.List;\n")
+	if c.hasLoader(javaLoaderJSON) {
+		builder.WriteString("import org.json.JSONException;\nimport org.json.JSONObject;\n")
+	}
+	builder.WriteString("\n")
+
+	params := make([]string, 0, len(columns))
+	for _, col := range columns {
+		params = append(params, fmt.Sprintf("%s %s", javaType(col.typ), col.field))
+	}
+
+	if c.style == javaStyleRecord {
+		builder.WriteString(fmt.Sprintf("/**\n * %s\n", javaDoc(sheet.Name)))
+		if len(columns) > 0 {
+			builder.WriteString(" *\n")
+		}
+		for _, col := range columns {
+			builder.WriteString(strings.TrimRight(fmt.Sprintf(" * @param %s %s", col.field, col.comment), " ") + "\n")
+		}
+		builder.WriteString(" */\n")
+		builder.WriteString(fmt.Sprintf("public record %s(%s) {\n", typeName, strings.Join(params, ", ")))
+	} else {
+		builder.WriteString(fmt.Sprintf("/** %s */\npublic final class %s {\n", javaDoc(sheet.Name), typeName))
+		for _, col := range columns {
+			if col.comment != "" {
+				builder.WriteString(fmt.Sprintf("    /** %s */\n", col.comment))
+			}
+			builder.WriteString(fmt.Sprintf("    private final %s %s;\n", javaType(col.typ), col.field))
+		}
+		if len(columns) > 0 {
+			builder.WriteString("\n")
+		}
+
+		builder.WriteString(fmt.Sprintf("    public %s(%s) {\n", typeName, strings.Join(params, ", ")))
+		for _, col := range columns {
+			builder.WriteString(fmt.Sprintf("        this.%s = %s;\n", col.field, col.field))
+		}
+		builder.WriteString("    }\n")
+
+		for _, col := range columns {
+			prefix := "get"
+			if col.typ == GDBTypeBool {
+				prefix = "is"
+			}
+			builder.WriteString("\n")
+			if col.comment != "" {
+				builder.WriteString(fmt.Sprintf("    /** %s */\n", col.comment))
+			}
+			builder.WriteString(fmt.Sprintf("    public %s %s%s() {\n        return %s;\n    }\n", javaType(col.typ), prefix, pascalIdent(col.name), col.field))
+		}
+	}
+
+	if c.hasLoader(javaLoaderJSON) {
+		args := make([]string, 0, len(columns))
+		for _, col := range columns {
+			args = append(args, fmt.Sprintf("%s.%s(row, %q)", javaJSONRuntime, javaJSONGetter(col.typ), col.name))
+		}
+		builder.WriteString(fmt.Sprintf("\n    /** 解析 JSON 转换器输出的 %s */\n", javaDoc(sheet.Name)))
+		builder.WriteString(fmt.Sprintf("    public static List<%s> fromJson(String json) throws JSONException {\n", typeName))
+		builder.WriteString(fmt.Sprintf("        List<%s> rows = new ArrayList<>();\n", typeName))
+		builder.WriteString(fmt.Sprintf("        for (JSONObject row : %s.rows(json)) {\n", javaJSONRuntime))
+		builder.WriteString(fmt.Sprintf("            rows.add(new %s(%s));\n", typeName, javaArgs(args, "                    ")))
+		builder.WriteString("        }\n        return rows;\n    }\n")
+	}
+
+	if c.hasLoader(javaLoaderGDB) {
+		args := make([]string, 0, len(columns))
+		for i, col := range columns {
+			args = append(args, fmt.Sprintf("c%d >= 0 ? table.%s(r, c%d) : %s", i, javaGDBGetter(col.typ), i, javaZero(col.typ)))
+		}
+		builder.WriteString(fmt.Sprintf("\n    /** 解析 gdb 转换器输出的 %s */\n", javaDoc(sheet.Name)))
+		builder.WriteString(fmt.Sprintf("    public static List<%s> fromGdb(byte[] data) {\n", typeName))
+		builder.WriteString(fmt.Sprintf("        %s table = new %s(data);\n", javaGDBRuntime, javaGDBRuntime))
+		for i, col := range columns {
+			builder.WriteString(fmt.Sprintf("        int c%d = table.columnIndex(%q);\n", i, col.name))
+		}
+		builder.WriteString(fmt.Sprintf("        List<%s> rows = new ArrayList<>(table.getRowCount());\n", typeName))
+		builder.WriteString("        for (int r = 0; r < table.getRowCount(); r++) {\n")
+		builder.WriteString(fmt.Sprintf("            rows.add(new %s(%s));\n", typeName, javaArgs(args, "                    ")))
+		builder.WriteString("        }\n        return rows;\n    }\n")
+	}
+
+	builder.WriteString("}\n")
+
+	result := &model.ConvertResult{
+		FileName: c.sourcePath(typeName),
+		Content:  []byte(builder.String()),
+		Format:   "java",
+	}
+	return result, nil
+}
+
+// javaArgs 构造函数参数列表，每个参数单独一行
+func javaArgs(args []string, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return "\n" + indent + strings.Join(args, ",\n"+indent)
+}
+
+// javaGDBGetter 读取 gdb 字段的方法
+func javaGDBGetter(typ uint8) string {
+	switch typ {
+	case GDBTypeInt:
+		return "getInt"
+	case GDBTypeFloat:
+		return "getFloat"
+	case GDBTypeBool:
+		return "getBool"
+	default:
+		return "getString"
+	}
+}
+
+// ConvertIndex 生成加载方法依赖的运行时类
+func (c *JavaConverter) ConvertIndex(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	results := make([]*model.ConvertResult, 0, len(c.loaders))
+	header := fmt.Sprintf("// 由 game-data-builder 生成，请勿手动修改\npackage %s;\n\n", c.packageName)
+	if c.hasLoader(javaLoaderJSON) {
+		results = append(results, &model.ConvertResult{
+			FileName: c.sourcePath(javaJSONRuntime),
+			Content:  []byte(header + javaJSONRuntimeSource),
+			Format:   "java",
+		})
+	}
+	if c.hasLoader(javaLoaderGDB) {
+		results = append(results, &model.ConvertResult{
+			FileName: c.sourcePath(javaGDBRuntime),
+			Content:  []byte(header + javaGDBRuntimeSource),
+			Format:   "java",
+		})
+	}
+	return results, nil
+}
+
+// GetFormat 获取支持的格式类型
+func (c *JavaConverter) GetFormat() string {
+	return "java"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *JavaConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
+
+// javaJSONRuntimeSource 读取 JSON 输出的辅助类
+const javaJSONRuntimeSource = `import java.util.ArrayList;
+import java.util.Iterator;
+import java.util.List;
+import org.json.JSONArray;
+import org.json.JSONException;
+import org.json.JSONObject;
+
+/** 读取 JSON 转换器输出的辅助方法，支持数组和按主键组织的 rows，空值返回类型默认值 */
+public final class GameDataJson {
+    private GameDataJson() {
+    }
+
+    /** 按顺序返回 rows 中的每一行 */
+    public static List<JSONObject> rows(String json) throws JSONException {
+        Object rows = new JSONObject(json).get("rows");
+        List<JSONObject> result = new ArrayList<>();
+        if (rows instanceof JSONArray) {
+            JSONArray array = (JSONArray) rows;
+            for (int i = 0; i < array.length(); i++) {
+                result.add(array.getJSONObject(i));
+            }
+        } else {
+            JSONObject object = (JSONObject) rows;
+            Iterator<String> keys = object.keys();
+            while (keys.hasNext()) {
+                result.add(object.getJSONObject(keys.next()));
+            }
+        }
+        return result;
+    }
+
+    /** 按 reward.itemId 形式的路径取值，不存在或为 null 时返回 null */
+    public static Object get(JSONObject row, String path) {
+        Object value = row;
+        for (String part : path.split("\\.")) {
+            if (!(value instanceof JSONObject)) {
+                return null;
+            }
+            value = ((JSONObject) value).opt(part);
+        }
+        return value == JSONObject.NULL ? null : value;
+    }
+
+    public static long getLong(JSONObject row, String path) {
+        Object value = get(row, path);
+        return value instanceof Number ? ((Number) value).longValue() : 0L;
+    }
+
+    public static double getDouble(JSONObject row, String path) {
+        Object value = get(row, path);
+        return value instanceof Number ? ((Number) value).doubleValue() : 0.0;
+    }
+
+    public static boolean getBoolean(JSONObject row, String path) {
+        Object value = get(row, path);
+        return value instanceof Boolean && (Boolean) value;
+    }
+
+    /** 字符串原样返回，列表和对象返回 JSON 文本 */
+    public static String getString(JSONObject row, String path) {
+        Object value = get(row, path);
+        return value == null ? null : value.toString();
+    }
+}
+`
+
+// javaGDBRuntimeSource 读取 gdb 输出的运行时类
+const javaGDBRuntimeSource = `import java.nio.ByteBuffer;
+import java.nio.ByteOrder;
+import java.nio.charset.StandardCharsets;
+
+/** gdb 二进制表，按列下标读取各行的值，空值返回类型默认值 */
+public final class GdbTable {
+    public static final byte TYPE_INT = 1, TYPE_FLOAT = 2, TYPE_BOOL = 3, TYPE_STRING = 4;
+
+    private final ByteBuffer data;
+    private final String name;
+    private final int rowCount;
+    private final int rowStart;
+    private final int rowSize;
+    private final String[] columnNames;
+    private final byte[] columnTypes;
+    private final int[] columnOffsets;
+    private final String[] strings;
+
+    public GdbTable(byte[] bytes) {
+        if (bytes.length < 28 || bytes[0] != 'G' || bytes[1] != 'D' || bytes[2] != 'B' || bytes[3] != '1') {
+            throw new IllegalArgumentException("not a gdb file");
+        }
+        data = ByteBuffer.wrap(bytes).order(ByteOrder.LITTLE_ENDIAN);
+        if ((data.getShort(4) & 0xffff) != 1) {
+            throw new IllegalArgumentException("unsupported gdb version");
+        }
+
+        int columnCount = data.getShort(6) & 0xffff;
+        rowCount = data.getInt(8);
+        rowSize = data.getInt(12);
+        int sheetName = data.getInt(16);
+        int stringCount = data.getInt(20);
+        int stringDataSize = data.getInt(24);
+
+        int pos = 28;
+        int stringTable = pos + columnCount * 5;
+        int stringData = stringTable + stringCount * 8;
+        strings = new String[stringCount];
+        for (int i = 0; i < stringCount; i++) {
+            int offset = data.getInt(stringTable + i * 8);
+            int length = data.getInt(stringTable + i * 8 + 4);
+            strings[i] = new String(bytes, stringData + offset, length, StandardCharsets.UTF_8);
+        }
+        rowStart = stringData + stringDataSize;
+        if ((long) rowStart + (long) rowCount * rowSize > bytes.length) {
+            throw new IllegalArgumentException("truncated gdb file");
+        }
+        name = strings[sheetName];
+
+        columnNames = new String[columnCount];
+        columnTypes = new byte[columnCount];
+        columnOffsets = new int[columnCount];
+        int fieldOffset = (columnCount + 7) / 8;
+        for (int i = 0; i < columnCount; i++) {
+            columnNames[i] = strings[data.getInt(pos + i * 5)];
+            columnTypes[i] = bytes[pos + i * 5 + 4];
+            columnOffsets[i] = fieldOffset;
+            fieldOffset += columnTypes[i] == TYPE_BOOL ? 1 : columnTypes[i] == TYPE_STRING ? 4 : 8;
+        }
+    }
+
+    public String getName() {
+        return name;
+    }
+
+    public int getRowCount() {
+        return rowCount;
+    }
+
+    public int getColumnCount() {
+        return columnNames.length;
+    }
+
+    public String columnName(int column) {
+        return columnNames[column];
+    }
+
+    public byte columnType(int column) {
+        return columnTypes[column];
+    }
+
+    /** 按列名查找列下标，不存在时返回 -1 */
+    public int columnIndex(String columnName) {
+        for (int i = 0; i < columnNames.length; i++) {
+            if (columnNames[i].equals(columnName)) {
+                return i;
+            }
+        }
+        return -1;
+    }
+
+    public boolean isNull(int row, int column) {
+        return (data.get(rowStart + row * rowSize + column / 8) & (1 << (column % 8))) != 0;
+    }
+
+    public long getInt(int row, int column) {
+        return isNull(row, column) ? 0L : data.getLong(field(row, column));
+    }
+
+    public double getFloat(int row, int column) {
+        return isNull(row, column) ? 0.0 : data.getDouble(field(row, column));
+    }
+
+    public boolean getBool(int row, int column) {
+        return !isNull(row, column) && data.get(field(row, column)) != 0;
+    }
+
+    public String getString(int row, int column) {
+        return isNull(row, column) ? null : strings[data.getInt(field(row, column))];
+    }
+
+    private int field(int row, int column) {
+        return rowStart + row * rowSize + columnOffsets[column];
+    }
+}
+`
@@ -10,6 +10,7 @@ import (
 // JSONConverter JSON转换器实现
 type JSONConverter struct {
 	config map[string]interface{}
+	text   textPolicy
 }
 
 // NewJSONConverter 创建JSON转换器
@@ -20,21 +21,56 @@ func NewJSONConverter() *JSONConverter {
 // Init 初始化转换器
 func (c *JSONConverter) Init(config map[string]interface{}) error {
 	c.config = config
+
+	text, err := parseTextPolicy(config)
+	if err != nil {
+		return err
+	}
+	c.text = text
 	return nil
 }
 
 // Convert 将数据转换为JSON格式
 func (c *JSONConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
-	// 转换数据
-	data := make(map[string]interface{})
-	data["name"] = sheet.Name
-	data["columns"] = sheet.Columns
-	data["rows"] = sheet.Rows
-	data["meta"] = sheet.Meta
+	// 按稳定的顺序组织数据：固定的顶层字段、按列顺序的行字段、排序后的元数据
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	columnTree := buildColumnTree(sheet.Columns)
+	orderedRows := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		orderedRows = append(orderedRows, orderedRow(row, columnTree))
+	}
+
+	data := &orderedMap{
+		keys: []string{"name", "columns", "rows", "meta"},
+		values: map[string]interface{}{
+			"name":    sheet.Name,
+			"columns": sheet.Columns,
+			"rows":    orderedRows,
+			"meta":    sheet.Meta,
+		},
+	}
+
+	// 按主键输出行数据，保持行顺序
+	if rowsAsMap(c.config) {
+		if _, err := rowKeys(sheet); err != nil {
+			return nil, err
+		}
+
+		keyedRows := &orderedMap{keys: make([]string, 0, len(rows)), values: make(map[string]interface{}, len(rows))}
+		for i, row := range rows {
+			key := fmt.Sprintf("%v", rowKey(sheet, row))
+			keyedRows.keys = append(keyedRows.keys, key)
+			keyedRows.values[key] = orderedRows[i]
+		}
+		data.values["rows"] = keyedRows
+	}
 
 	// 格式化JSON
 	var content []byte
-	var err error
 
 	// 检查是否需要格式化输出
 	if indent, ok := c.config["indent"].(bool); ok && indent {
@@ -49,8 +85,8 @@ func (c *JSONConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, e
 
 	// 创建转换结果
 	result := &model.ConvertResult{
-		FileName: fmt.Sprintf("%s.json", sheet.Name),
-		Content:  content,
+		FileName: fmt.Sprintf("%s.json", model.SheetPath(sheet.Name)),
+		Content:  c.text.apply(content),
 		Format:   "json",
 	}
 
@@ -62,17 +98,7 @@ func (c *JSONConverter) GetFormat() string {
 	return "json"
 }
 
-// BatchConvert 批量转换多个数据表
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
 func (c *JSONConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
-	results := make([]*model.ConvertResult, 0)
-
-	for _, sheet := range sheets {
-		result, err := c.Convert(sheet)
-		if err != nil {
-			return nil, err
-		}
-		results = append(results, result)
-	}
-
-	return results, nil
+	return batchConvert(c, sheets)
 }
@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// orderedMap 按指定键顺序序列化的JSON对象
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// MarshalJSON 实现json.Marshaler接口
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueJSON, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(valueJSON)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// orderedRow 按列顺序组织行数据，不在列定义中的字段按键名排序追加在后
+func orderedRow(row map[string]interface{}, nodes []*columnNode) *orderedMap {
+	ordered := &orderedMap{keys: make([]string, 0, len(row)), values: make(map[string]interface{}, len(row))}
+	known := make(map[string]bool)
+
+	for _, node := range nodes {
+		val, exists := row[node.Name]
+		if !exists {
+			continue
+		}
+		known[node.Name] = true
+		ordered.keys = append(ordered.keys, node.Name)
+
+		if child, ok := val.(map[string]interface{}); ok && len(node.Children) > 0 {
+			ordered.values[node.Name] = orderedRow(child, node.Children)
+		} else {
+			ordered.values[node.Name] = val
+		}
+	}
+
+	extra := make([]string, 0)
+	for key := range row {
+		if !known[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		ordered.keys = append(ordered.keys, key)
+		ordered.values[key] = row[key]
+	}
+
+	return ordered
+}
+
+// sortedMetaKeys 获取排序后的元数据键
+func sortedMetaKeys(meta map[string]interface{}) []string {
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedRows 按 sortRowsBy 选项返回排序后的行数据副本，未配置时保持原顺序
+func sortedRows(sheet *model.DataSheet, config map[string]interface{}) ([]map[string]interface{}, error) {
+	var specs []string
+	switch v := config["sortRowsBy"].(type) {
+	case nil:
+		return sheet.Rows, nil
+	case string:
+		specs = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			spec, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("sortRowsBy 必须是列名或列名数组: %v", item)
+			}
+			specs = append(specs, spec)
+		}
+	default:
+		return nil, fmt.Errorf("sortRowsBy 必须是列名或列名数组: %v", v)
+	}
+
+	keys := model.ParseSortKeys(specs)
+	for _, key := range keys {
+		if !containsColumn(sheet.Columns, key.Column) {
+			return nil, fmt.Errorf("sheet %s: 排序列 %s 不存在", sheet.Name, key.Column)
+		}
+	}
+
+	rows := make([]map[string]interface{}, len(sheet.Rows))
+	copy(rows, sheet.Rows)
+	model.SortRows(rows, keys)
+	return rows, nil
+}
+
+// containsColumn 检查列是否存在
+func containsColumn(columns []model.ColumnInfo, name string) bool {
+	for _, col := range columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 // PHPConverter PHP转换器实现
 type PHPConverter struct {
 	config map[string]interface{}
+	text   textPolicy
 }
 
 // NewPHPConverter 创建PHP转换器
@@ -20,6 +21,12 @@ func NewPHPConverter() *PHPConverter {
 // Init 初始化转换器
 func (c *PHPConverter) Init(config map[string]interface{}) error {
 	c.config = config
+
+	text, err := parseTextPolicy(config)
+	if err != nil {
+		return err
+	}
+	c.text = text
 	return nil
 }
 
@@ -77,25 +84,35 @@ func (c *PHPConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, er
 	}
 	builder.WriteString("    ],\n")
 
-	// 添加行数据
+	// 添加行数据，按主键输出时使用主键作为数组键
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+	asMap := rowsAsMap(c.config)
+	if asMap {
+		if _, err := rowKeys(sheet); err != nil {
+			return nil, err
+		}
+	}
+
+	columnTree := buildColumnTree(sheet.Columns)
 	builder.WriteString("    'rows' => [\n")
-	for i, row := range sheet.Rows {
-		builder.WriteString(fmt.Sprintf("        %d => [\n", i))
-		for _, col := range sheet.Columns {
-			if val, exists := row[col.Name]; exists {
-				builder.WriteString(fmt.Sprintf("            '%s' => %s,\n", col.Name, c.valueToString(val)))
-			} else {
-				builder.WriteString(fmt.Sprintf("            '%s' => null,\n", col.Name))
-			}
+	for i, row := range rows {
+		if asMap {
+			builder.WriteString(fmt.Sprintf("        %s => [\n", c.valueToString(rowKey(sheet, row))))
+		} else {
+			builder.WriteString(fmt.Sprintf("        %d => [\n", i))
 		}
+		c.writeRowFields(&builder, columnTree, row, "            ")
 		builder.WriteString("        ],\n")
 	}
 	builder.WriteString("    ],\n")
 
 	// 添加元数据
 	builder.WriteString("    'meta' => [\n")
-	for key, val := range sheet.Meta {
-		builder.WriteString(fmt.Sprintf("        '%s' => %s,\n", key, c.valueToString(val)))
+	for _, key := range sortedMetaKeys(sheet.Meta) {
+		builder.WriteString(fmt.Sprintf("        '%s' => %s,\n", key, c.valueToString(sheet.Meta[key])))
 	}
 	builder.WriteString("    ],\n")
 
@@ -104,8 +121,8 @@ func (c *PHPConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, er
 
 	// 创建转换结果
 	result := &model.ConvertResult{
-		FileName: fmt.Sprintf("%s.php", sheet.Name),
-		Content:  []byte(builder.String()),
+		FileName: fmt.Sprintf("%s.php", model.SheetPath(sheet.Name)),
+		Content:  c.text.apply([]byte(builder.String())),
 		Format:   "php",
 	}
 
@@ -117,19 +134,26 @@ func (c *PHPConverter) GetFormat() string {
 	return "php"
 }
 
-// BatchConvert 批量转换多个数据表
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
 func (c *PHPConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
-	results := make([]*model.ConvertResult, 0)
+	return batchConvert(c, sheets)
+}
 
-	for _, sheet := range sheets {
-		result, err := c.Convert(sheet)
-		if err != nil {
-			return nil, err
+// writeRowFields 按列结构输出行字段，嵌套列输出为子数组
+func (c *PHPConverter) writeRowFields(builder *strings.Builder, nodes []*columnNode, row map[string]interface{}, indent string) {
+	for _, node := range nodes {
+		val, exists := row[node.Name]
+		if len(node.Children) > 0 {
+			child, _ := val.(map[string]interface{})
+			builder.WriteString(fmt.Sprintf("%s'%s' => [\n", indent, node.Name))
+			c.writeRowFields(builder, node.Children, child, indent+"    ")
+			builder.WriteString(fmt.Sprintf("%s],\n", indent))
+		} else if exists {
+			builder.WriteString(fmt.Sprintf("%s'%s' => %s,\n", indent, node.Name, c.valueToString(val)))
+		} else {
+			builder.WriteString(fmt.Sprintf("%s'%s' => null,\n", indent, node.Name))
 		}
-		results = append(results, result)
 	}
-
-	return results, nil
 }
 
 // valueToString 将值转换为PHP字符串
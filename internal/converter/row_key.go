@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"fmt"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// rowsAsMap 检查是否需要按主键输出行数据
+func rowsAsMap(config map[string]interface{}) bool {
+	asMap, ok := config["rowsAsMap"].(bool)
+	return ok && asMap
+}
+
+// rowKey 获取行的主键值
+func rowKey(sheet *model.DataSheet, row map[string]interface{}) interface{} {
+	key, _ := model.RowValue(row, sheet.PrimaryKey())
+	return key
+}
+
+// rowKeys 按行顺序获取主键值，主键为空或重复时报错
+func rowKeys(sheet *model.DataSheet) ([]interface{}, error) {
+	keyColumn := sheet.PrimaryKey()
+	if keyColumn == "" {
+		return nil, fmt.Errorf("sheet %s: 没有可用的主键列", sheet.Name)
+	}
+
+	keys := make([]interface{}, 0, len(sheet.Rows))
+	seen := make(map[string]int)
+	for rowIndex, row := range sheet.Rows {
+		key, _ := model.RowValue(row, keyColumn)
+		if key == nil || key == "" {
+			return nil, fmt.Errorf("sheet %s, row %d: 主键 %s 为空", sheet.Name, sheet.RowNumber(rowIndex), keyColumn)
+		}
+
+		keyStr := fmt.Sprintf("%v", key)
+		if prevIndex, exists := seen[keyStr]; exists {
+			return nil, fmt.Errorf("sheet %s, row %d: 主键 %s 的值 %v 与第 %d 行重复",
+				sheet.Name, sheet.RowNumber(rowIndex), keyColumn, key, sheet.RowNumber(prevIndex))
+		}
+		seen[keyStr] = rowIndex
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
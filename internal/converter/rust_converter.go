@@ -0,0 +1,321 @@
+package converter
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// rustKeywords Rust 关键字，作为字段名时使用原始标识符 r#
+var rustKeywords = map[string]bool{
+	"as": true, "async": true, "await": true, "break": true, "const": true, "continue": true,
+	"dyn": true, "else": true, "enum": true, "extern": true, "false": true, "fn": true,
+	"for": true, "if": true, "impl": true, "in": true, "let": true, "loop": true, "match": true,
+	"mod": true, "move": true, "mut": true, "pub": true, "ref": true, "return": true,
+	"static": true, "struct": true, "trait": true, "true": true, "type": true, "unsafe": true,
+	"use": true, "where": true, "while": true, "abstract": true, "become": true, "box": true,
+	"do": true, "final": true, "gen": true, "macro": true, "override": true, "priv": true,
+	"try": true, "typeof": true, "unsized": true, "virtual": true, "yield": true,
+}
+
+// rustReserved 不能作为原始标识符的关键字，作为字段名或模块名时加下划线后缀
+var rustReserved = map[string]bool{"crate": true, "self": true, "super": true, "Self": true}
+
+// RustConverter Rust代码生成器，为每张表生成一个模块：serde 反序列化的行结构体、解析 JSON 输出的函数，以及可选的嵌入数据和主键索引
+type RustConverter struct {
+	config   map[string]interface{}
+	embed    bool   // 是否用 include_bytes! 嵌入 JSON 数据并生成 lazy_static 索引
+	embedDir string // JSON 转换器输出目录相对于本转换器输出目录的路径
+}
+
+// NewRustConverter 创建Rust代码生成器
+func NewRustConverter() *RustConverter {
+	return &RustConverter{}
+}
+
+// Init 初始化转换器
+func (c *RustConverter) Init(config map[string]interface{}) error {
+	c.config = config
+
+	c.embed, _ = config["embed"].(bool)
+	c.embedDir = "../json"
+	if dir, ok := config["embedDir"].(string); ok && dir != "" {
+		if path.IsAbs(filepath.ToSlash(dir)) || filepath.IsAbs(dir) {
+			return fmt.Errorf("embedDir 必须是相对路径: %s", dir)
+		}
+		c.embedDir = strings.TrimSuffix(filepath.ToSlash(dir), "/")
+	}
+	return nil
+}
+
+// RustFileName 表生成的模块文件名，如 shop.item -> shop_item.rs
+func RustFileName(sheetName string) string {
+	return rustModule(sheetName) + ".rs"
+}
+
+// rustModule 表对应的模块名
+func rustModule(sheetName string) string {
+	ident := rustSnake(model.SheetIdent(sheetName))
+	if rustKeywords[ident] || rustReserved[ident] || ident == "mod" {
+		ident += "_"
+	}
+	return ident
+}
+
+// rustSnake 转换为小写下划线形式，如 itemId -> item_id，非字母数字的字符替换为下划线
+func rustSnake(name string) string {
+	var builder strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+				builder.WriteByte('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'):
+			builder.WriteRune(r)
+		default:
+			builder.WriteByte('_')
+		}
+	}
+	ident := builder.String()
+	if ident == "" || unicode.IsDigit(rune(ident[0])) {
+		ident = "_" + ident
+	}
+	return ident
+}
+
+// rustField 字段名，关键字使用原始标识符
+func rustField(name string) string {
+	ident := rustSnake(name)
+	switch {
+	case rustReserved[ident]:
+		return ident + "_"
+	case rustKeywords[ident]:
+		return "r#" + ident
+	}
+	return ident
+}
+
+// rustType gdb 类型对应的 Rust 类型，列表、对象等结构化的列保留为 serde_json::Value
+func rustType(typ uint8, colType string) string {
+	switch typ {
+	case GDBTypeInt:
+		return "i64"
+	case GDBTypeFloat:
+		return "f64"
+	case GDBTypeBool:
+		return "bool"
+	}
+	switch lower := strings.ToLower(colType); {
+	case lower == "json", lower == "list", lower == "array", lower == "map", lower == "object", strings.HasSuffix(lower, "[]"):
+		return "serde_json::Value"
+	}
+	return "String"
+}
+
+// rustDoc 文档注释文本
+func rustDoc(text string) string {
+	return strings.NewReplacer("\r", "", "\n", " ").Replace(text)
+}
+
+// rustStruct 待输出的结构体
+type rustStruct struct {
+	name  string
+	doc   string
+	nodes []*columnNode
+	path  string // 嵌套列的前缀，如 reward.
+}
+
+// Convert 生成表的 Rust 模块
+func (c *RustConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	typeName := pascalIdent(model.SheetIdent(sheet.Name))
+	columns := make(map[string]model.ColumnInfo, len(sheet.Columns))
+	for _, col := range sheet.Columns {
+		columns[col.Name] = col
+	}
+
+	var builder strings.Builder
+	builder.WriteString("// 由 game-data-builder 生成，请勿手动修改\n\n")
+	builder.WriteString("use serde::Deserialize;\n\n")
+
+	// 嵌套列生成嵌套的结构体，按广度优先的顺序输出
+	queue := []rustStruct{{name: typeName, doc: sheet.Name, nodes: buildColumnTree(sheet.Columns)}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		builder.WriteString(fmt.Sprintf("/// %s\n", rustDoc(current.doc)))
+		builder.WriteString("#[derive(Debug, Clone, Default, PartialEq, Deserialize)]\n")
+		builder.WriteString(fmt.Sprintf("pub struct %s {\n", current.name))
+		fields := make(map[string]string, len(current.nodes))
+		for _, node := range current.nodes {
+			field := rustField(node.Name)
+			if other, exists := fields[field]; exists {
+				return nil, fmt.Errorf("列 %s%s 与 %s%s 对应相同的字段名 %s", current.path, node.Name, current.path, other, field)
+			}
+			fields[field] = node.Name
+
+			var fieldType string
+			if len(node.Children) > 0 {
+				fieldType = current.name + pascalIdent(node.Name)
+				queue = append(queue, rustStruct{name: fieldType, doc: current.path + node.Name, nodes: node.Children, path: current.path + node.Name + "."})
+			} else {
+				col := columns[current.path+node.Name]
+				fieldType = rustType(gdbColumnType(col.Type), col.Type)
+				if doc := columnDoc(col); doc != "" {
+					builder.WriteString(fmt.Sprintf("    /// %s\n", rustDoc(doc)))
+				}
+			}
+
+			attrs := "default, deserialize_with = \"super::null_default\""
+			if strings.TrimPrefix(field, "r#") != node.Name {
+				attrs = fmt.Sprintf("rename = %q, %s", node.Name, attrs)
+			}
+			builder.WriteString(fmt.Sprintf("    #[serde(%s)]\n    pub %s: %s,\n", attrs, field, fieldType))
+		}
+		builder.WriteString("}\n\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("/// 解析 JSON 转换器输出的 %s，支持数组和按主键组织的 rows\n", rustDoc(sheet.Name)))
+	builder.WriteString(fmt.Sprintf("pub fn parse(json: &[u8]) -> serde_json::Result<Vec<%s>> {\n", typeName))
+	builder.WriteString(fmt.Sprintf("    Ok(serde_json::from_slice::<super::Table<%s>>(json)?.rows.0)\n}\n", typeName))
+
+	if c.embed {
+		c.writeEmbedded(&builder, sheet, typeName, columns)
+	}
+
+	result := &model.ConvertResult{
+		FileName: RustFileName(sheet.Name),
+		Content:  []byte(builder.String()),
+		Format:   "rust",
+	}
+	return result, nil
+}
+
+// writeEmbedded 输出编译时嵌入的数据 ROWS，主键为整数、字符串或布尔值时输出索引 INDEX 和查找函数 get
+func (c *RustConverter) writeEmbedded(builder *strings.Builder, sheet *model.DataSheet, typeName string, columns map[string]model.ColumnInfo) {
+	dataPath := c.embedDir + "/" + filepath.ToSlash(model.SheetPath(sheet.Name)) + ".json"
+	fileName := path.Base(dataPath)
+
+	builder.WriteString("\nlazy_static::lazy_static! {\n")
+	builder.WriteString(fmt.Sprintf("    /// 编译时嵌入的 %s\n", path.Base(dataPath)))
+	builder.WriteString(fmt.Sprintf("    pub static ref ROWS: Vec<%s> =\n", typeName))
+	builder.WriteString(fmt.Sprintf("        parse(include_bytes!(%q)).expect(%q);\n", dataPath, fileName+" 格式不正确"))
+
+	key := sheet.PrimaryKey()
+	col, exists := columns[key]
+	keyType := rustType(gdbColumnType(col.Type), col.Type)
+	if !exists || strings.Contains(key, ".") || (keyType != "i64" && keyType != "String" && keyType != "bool") {
+		builder.WriteString("}\n")
+		return
+	}
+
+	field := rustField(key)
+	builder.WriteString(fmt.Sprintf("    /// 按主键 %s 索引 ROWS 中的行\n", key))
+	builder.WriteString(fmt.Sprintf("    pub static ref INDEX: std::collections::HashMap<%s, usize> =\n", keyType))
+	if keyType == "String" {
+		builder.WriteString(fmt.Sprintf("        ROWS.iter().enumerate().map(|(i, row)| (row.%s.clone(), i)).collect();\n}\n", field))
+	} else {
+		builder.WriteString(fmt.Sprintf("        ROWS.iter().enumerate().map(|(i, row)| (row.%s, i)).collect();\n}\n", field))
+	}
+
+	builder.WriteString(fmt.Sprintf("\n/// 按主键 %s 查找，不存在时返回 None\n", key))
+	if keyType == "String" {
+		builder.WriteString(fmt.Sprintf("pub fn get(key: &str) -> Option<&'static %s> {\n    INDEX.get(key).map(|&i| &ROWS[i])\n}\n", typeName))
+	} else {
+		builder.WriteString(fmt.Sprintf("pub fn get(key: %s) -> Option<&'static %s> {\n    INDEX.get(&key).map(|&i| &ROWS[i])\n}\n", keyType, typeName))
+	}
+}
+
+// ConvertIndex 生成 mod.rs：声明各表的模块，以及解析 rows 的公共类型
+func (c *RustConverter) ConvertIndex(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	modules := make([]string, 0, len(sheets))
+	for _, sheet := range sheets {
+		modules = append(modules, rustModule(sheet.Name))
+	}
+	sort.Strings(modules)
+
+	var builder strings.Builder
+	builder.WriteString("// 由 game-data-builder 生成，请勿手动修改\n\n")
+	for _, module := range modules {
+		builder.WriteString(fmt.Sprintf("pub mod %s;\n", module))
+	}
+	builder.WriteString("\n" + rustRuntimeSource)
+
+	return []*model.ConvertResult{{
+		FileName: "mod.rs",
+		Content:  []byte(builder.String()),
+		Format:   "rust",
+	}}, nil
+}
+
+// GetFormat 获取支持的格式类型
+func (c *RustConverter) GetFormat() string {
+	return "rust"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *RustConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
+
+// rustRuntimeSource 各表模块共用的解析代码
+const rustRuntimeSource = `use serde::{Deserialize, Deserializer};
+
+/// JSON 转换器输出的表，只读取 rows
+#[derive(Deserialize)]
+pub(crate) struct Table<T> {
+    pub rows: RowList<T>,
+}
+
+/// 按顺序读取的行，rows 可以是数组或按主键组织的对象
+pub(crate) struct RowList<T>(pub Vec<T>);
+
+impl<'de, T: Deserialize<'de>> Deserialize<'de> for RowList<T> {
+    fn deserialize<D: Deserializer<'de>>(deserializer: D) -> Result<Self, D::Error> {
+        struct RowsVisitor<T>(std::marker::PhantomData<T>);
+
+        impl<'de, T: Deserialize<'de>> serde::de::Visitor<'de> for RowsVisitor<T> {
+            type Value = RowList<T>;
+
+            fn expecting(&self, f: &mut std::fmt::Formatter) -> std::fmt::Result {
+                f.write_str("an array or object of rows")
+            }
+
+            fn visit_seq<A: serde::de::SeqAccess<'de>>(self, mut seq: A) -> Result<Self::Value, A::Error> {
+                let mut rows = Vec::new();
+                while let Some(row) = seq.next_element()? {
+                    rows.push(row);
+                }
+                Ok(RowList(rows))
+            }
+
+            fn visit_map<A: serde::de::MapAccess<'de>>(self, mut map: A) -> Result<Self::Value, A::Error> {
+                let mut rows = Vec::new();
+                while let Some((_, row)) = map.next_entry::<serde::de::IgnoredAny, T>()? {
+                    rows.push(row);
+                }
+                Ok(RowList(rows))
+            }
+        }
+
+        deserializer.deserialize_any(RowsVisitor(std::marker::PhantomData))
+    }
+}
+
+/// 空值和缺失的字段使用类型的默认值
+pub(crate) fn null_default<'de, D, T>(deserializer: D) -> Result<T, D::Error>
+where
+    D: Deserializer<'de>,
+    T: Default + Deserialize<'de>,
+{
+    Ok(Option::<T>::deserialize(deserializer)?.unwrap_or_default())
+}
+`
@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// utf8BOM UTF-8 字节顺序标记
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// textPolicy 文本输出的编码细节，未配置的选项保持转换器的原始输出
+type textPolicy struct {
+	bom          bool   // 是否添加 UTF-8 BOM
+	lineEnding   string // 换行符：lf 或 crlf，为空时不处理
+	finalNewline *bool  // 是否以换行结尾，为空时不处理
+}
+
+// parseTextPolicy 解析转换器选项 bom、lineEnding 和 finalNewline
+func parseTextPolicy(config map[string]interface{}) (textPolicy, error) {
+	var policy textPolicy
+	if bom, ok := config["bom"].(bool); ok {
+		policy.bom = bom
+	}
+	if lineEnding, ok := config["lineEnding"].(string); ok {
+		if lineEnding != "lf" && lineEnding != "crlf" {
+			return policy, fmt.Errorf("不支持的换行符: %s", lineEnding)
+		}
+		policy.lineEnding = lineEnding
+	}
+	if finalNewline, ok := config["finalNewline"].(bool); ok {
+		policy.finalNewline = &finalNewline
+	}
+	return policy, nil
+}
+
+// apply 按策略处理文本内容
+func (p textPolicy) apply(content []byte) []byte {
+	content = bytes.TrimPrefix(content, utf8BOM)
+
+	// 统一为 LF 后再处理结尾换行，最后按需要转换为 CRLF
+	if p.lineEnding != "" {
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	}
+	if p.finalNewline != nil {
+		content = bytes.TrimRight(content, "\r\n")
+		if *p.finalNewline {
+			content = append(content, '\n')
+		}
+	}
+	if p.lineEnding == "crlf" {
+		content = bytes.ReplaceAll(content, []byte("\n"), []byte("\r\n"))
+	}
+
+	if p.bom {
+		content = append(append([]byte(nil), utf8BOM...), content...)
+	}
+	return content
+}
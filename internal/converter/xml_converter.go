@@ -0,0 +1,219 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// 单元格的输出方式
+const (
+	xmlCellAttribute = "attribute" // 单元格作为行元素的属性（默认）
+	xmlCellElement   = "element"   // 单元格作为行元素的子元素
+)
+
+// XMLConverter XML转换器实现，输出 <Table name="..."><Row .../></Table>
+type XMLConverter struct {
+	config      map[string]interface{}
+	text        textPolicy
+	cellMode    string // 单元格输出方式：attribute 或 element
+	rootElement string // 根元素名
+	rowElement  string // 行元素名
+	declaration bool   // 是否输出 XML 声明
+	encoding    string // 输出编码：UTF-8 或 UTF-16
+	indent      bool   // 是否缩进
+}
+
+// NewXMLConverter 创建XML转换器
+func NewXMLConverter() *XMLConverter {
+	return &XMLConverter{}
+}
+
+// Init 初始化转换器
+func (c *XMLConverter) Init(config map[string]interface{}) error {
+	c.config = config
+
+	text, err := parseTextPolicy(config)
+	if err != nil {
+		return err
+	}
+	c.text = text
+
+	c.cellMode = xmlCellAttribute
+	if mode, ok := config["cellMode"].(string); ok && mode != "" {
+		if mode != xmlCellAttribute && mode != xmlCellElement {
+			return fmt.Errorf("不支持的 cellMode: %s", mode)
+		}
+		c.cellMode = mode
+	}
+
+	c.rootElement = "Table"
+	if root, ok := config["rootElement"].(string); ok && root != "" {
+		c.rootElement = root
+	}
+	c.rowElement = "Row"
+	if row, ok := config["rowElement"].(string); ok && row != "" {
+		c.rowElement = row
+	}
+
+	c.declaration = true
+	if declaration, ok := config["declaration"].(bool); ok {
+		c.declaration = declaration
+	}
+
+	c.encoding = "UTF-8"
+	if encoding, ok := config["encoding"].(string); ok && encoding != "" {
+		switch strings.ToUpper(encoding) {
+		case "UTF-8", "UTF8":
+			c.encoding = "UTF-8"
+		case "UTF-16", "UTF16":
+			c.encoding = "UTF-16"
+		default:
+			return fmt.Errorf("不支持的编码: %s", encoding)
+		}
+	}
+
+	if indent, ok := config["indent"].(bool); ok {
+		c.indent = indent
+	}
+	return nil
+}
+
+// Convert 将数据转换为XML格式
+func (c *XMLConverter) Convert(sheet *model.DataSheet) (*model.ConvertResult, error) {
+	rows, err := sortedRows(sheet, c.config)
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	if c.declaration {
+		builder.WriteString(fmt.Sprintf("<?xml version=\"1.0\" encoding=\"%s\"?>", c.encoding))
+		c.newline(&builder)
+	}
+
+	builder.WriteString(fmt.Sprintf("<%s name=\"%s\">", c.rootElement, xmlEscape(sheet.Name)))
+	c.newline(&builder)
+
+	columnTree := buildColumnTree(sheet.Columns)
+	for _, row := range rows {
+		if c.cellMode == xmlCellElement {
+			c.writeIndent(&builder, 1)
+			builder.WriteString(fmt.Sprintf("<%s>", c.rowElement))
+			c.newline(&builder)
+			c.writeElements(&builder, columnTree, row, 2)
+			c.writeIndent(&builder, 1)
+			builder.WriteString(fmt.Sprintf("</%s>", c.rowElement))
+		} else {
+			c.writeIndent(&builder, 1)
+			builder.WriteString("<" + c.rowElement)
+			for _, col := range sheet.Columns {
+				val, exists := model.RowValue(row, col.Name)
+				if !exists || val == nil {
+					continue
+				}
+				builder.WriteString(fmt.Sprintf(" %s=\"%s\"", col.Name, xmlEscape(xmlValue(val))))
+			}
+			builder.WriteString("/>")
+		}
+		c.newline(&builder)
+	}
+
+	builder.WriteString(fmt.Sprintf("</%s>", c.rootElement))
+	c.newline(&builder)
+
+	content := c.text.apply([]byte(builder.String()))
+	if c.encoding == "UTF-16" {
+		content = encodeUTF16(content)
+	}
+
+	result := &model.ConvertResult{
+		FileName: fmt.Sprintf("%s.xml", model.SheetPath(sheet.Name)),
+		Content:  content,
+		Format:   "xml",
+	}
+	return result, nil
+}
+
+// writeElements 按列结构输出子元素，嵌套列输出为嵌套元素，空值不输出
+func (c *XMLConverter) writeElements(builder *strings.Builder, nodes []*columnNode, row map[string]interface{}, depth int) {
+	for _, node := range nodes {
+		val, exists := row[node.Name]
+		if len(node.Children) > 0 {
+			child, _ := val.(map[string]interface{})
+			c.writeIndent(builder, depth)
+			builder.WriteString(fmt.Sprintf("<%s>", node.Name))
+			c.newline(builder)
+			c.writeElements(builder, node.Children, child, depth+1)
+			c.writeIndent(builder, depth)
+			builder.WriteString(fmt.Sprintf("</%s>", node.Name))
+			c.newline(builder)
+		} else if exists && val != nil {
+			c.writeIndent(builder, depth)
+			builder.WriteString(fmt.Sprintf("<%s>%s</%s>", node.Name, xmlEscape(xmlValue(val)), node.Name))
+			c.newline(builder)
+		}
+	}
+}
+
+// writeIndent 缩进模式下输出缩进
+func (c *XMLConverter) writeIndent(builder *strings.Builder, depth int) {
+	if c.indent {
+		builder.WriteString(strings.Repeat("  ", depth))
+	}
+}
+
+// newline 缩进模式下输出换行
+func (c *XMLConverter) newline(builder *strings.Builder) {
+	if c.indent {
+		builder.WriteString("\n")
+	}
+}
+
+// GetFormat 获取支持的格式类型
+func (c *XMLConverter) GetFormat() string {
+	return "xml"
+}
+
+// BatchConvert 批量转换多个数据表，单张表失败时继续转换其余的表，错误以 model.ConvertErrors 一起返回
+func (c *XMLConverter) BatchConvert(sheets []*model.DataSheet) ([]*model.ConvertResult, error) {
+	return batchConvert(c, sheets)
+}
+
+// xmlValue 单元格值的文本形式
+func xmlValue(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// xmlEscape 转义文本中的 XML 特殊字符
+func xmlEscape(text string) string {
+	var buffer bytes.Buffer
+	xml.EscapeText(&buffer, []byte(text))
+	return buffer.String()
+}
+
+// encodeUTF16 将 UTF-8 内容转换为带 BOM 的 UTF-16（小端序）
+func encodeUTF16(content []byte) []byte {
+	units := utf16.Encode([]rune(string(bytes.TrimPrefix(content, utf8BOM))))
+	encoded := make([]byte, 2+len(units)*2)
+	binary.LittleEndian.PutUint16(encoded, 0xFEFF)
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(encoded[2+i*2:], unit)
+	}
+	return encoded
+}
@@ -0,0 +1,114 @@
+package devpush
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// 消息类型
+const (
+	MessageFile   = "file"   // 单个变更文件
+	MessageCommit = "commit" // 本次推送结束，调试端可以统一应用变更
+)
+
+// DefaultTimeout 默认连接和写入超时
+const DefaultTimeout = 3 * time.Second
+
+// Message 推送消息，每条消息为一行 JSON
+type Message struct {
+	Type    string `json:"type"`              // 消息类型
+	Sheet   string `json:"sheet,omitempty"`   // 来源表名
+	Format  string `json:"format,omitempty"`  // 格式类型
+	File    string `json:"file,omitempty"`    // 输出文件名
+	Content []byte `json:"content,omitempty"` // 文件内容（base64）
+	Count   int    `json:"count,omitempty"`   // commit 消息中的文件数量
+}
+
+// Pusher 将变更的转换结果通过 TCP 推送到运行中的游戏调试端
+type Pusher struct {
+	addr    string
+	timeout time.Duration
+	hashes  map[string]string // 格式/文件名 -> 上次推送成功的内容哈希
+}
+
+// NewPusher 创建推送器，timeout 为 0 时使用默认超时
+func NewPusher(addr string, timeout time.Duration) *Pusher {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Pusher{
+		addr:    addr,
+		timeout: timeout,
+		hashes:  make(map[string]string),
+	}
+}
+
+// Addr 调试端地址
+func (p *Pusher) Addr() string {
+	return p.addr
+}
+
+// Changed 返回与上次推送相比内容发生变化的结果
+func (p *Pusher) Changed(results []*model.ConvertResult) []*model.ConvertResult {
+	changed := make([]*model.ConvertResult, 0)
+	for _, result := range results {
+		if p.hashes[resultKey(result)] != contentHash(result.Content) {
+			changed = append(changed, result)
+		}
+	}
+	return changed
+}
+
+// Push 推送变更的结果，返回推送的文件数量；推送失败时不记录哈希，下次构建会重新推送
+func (p *Pusher) Push(results []*model.ConvertResult) (int, error) {
+	changed := p.Changed(results)
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", p.addr, p.timeout)
+	if err != nil {
+		return 0, fmt.Errorf("连接调试端 %s 失败: %v", p.addr, err)
+	}
+	defer conn.Close()
+	conn.SetWriteDeadline(time.Now().Add(p.timeout))
+
+	encoder := json.NewEncoder(conn)
+	for _, result := range changed {
+		message := Message{
+			Type:    MessageFile,
+			Sheet:   result.Sheet,
+			Format:  result.Format,
+			File:    result.FileName,
+			Content: result.Content,
+		}
+		if err := encoder.Encode(message); err != nil {
+			return 0, fmt.Errorf("推送 %s 失败: %v", result.FileName, err)
+		}
+	}
+	if err := encoder.Encode(Message{Type: MessageCommit, Count: len(changed)}); err != nil {
+		return 0, fmt.Errorf("推送提交消息失败: %v", err)
+	}
+
+	for _, result := range changed {
+		p.hashes[resultKey(result)] = contentHash(result.Content)
+	}
+	return len(changed), nil
+}
+
+// resultKey 转换结果的唯一标识
+func resultKey(result *model.ConvertResult) string {
+	return result.Format + "/" + result.FileName
+}
+
+// contentHash 计算内容哈希
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
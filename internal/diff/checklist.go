@@ -0,0 +1,256 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// NoOwner 没有负责人的行所在的分组
+const NoOwner = "未指定"
+
+// Checklist 单张表的 QA 检查清单，列出相对旧版本新增和修改的行，按负责人分组
+type Checklist struct {
+	Sheet  string            `json:"sheet"`  // 表名
+	Old    string            `json:"old"`    // 旧版本来源
+	New    string            `json:"new"`    // 新版本来源
+	Key    string            `json:"key"`    // 主键列
+	Fields []string          `json:"fields"` // 每行列出的关键字段，不含主键
+	Groups []*ChecklistGroup `json:"groups"` // 按负责人排序的分组，未指定负责人的分组在最后
+}
+
+// ChecklistGroup 同一负责人的检查项
+type ChecklistGroup struct {
+	Owner string           `json:"owner"` // 负责人
+	Items []*ChecklistItem `json:"items"` // 检查项，按行在表中的顺序排列
+}
+
+// ChecklistItem 需要回归测试的一行
+type ChecklistItem struct {
+	Key     string            `json:"key"`               // 主键值
+	Status  string            `json:"status"`            // 变化类型：added 或 modified
+	Fields  map[string]string `json:"fields"`            // 关键字段的当前值
+	Changed []string          `json:"changed,omitempty"` // 修改的行中值有变化的列
+}
+
+// BuildChecklists 根据差异报告为每张有新增或修改行的表生成检查清单
+//
+// 负责人优先取行中 ownerColumn 列的值，其次取表元数据 owner（或 负责人），都没有时归入 NoOwner；
+// fields 中不存在于表中的列会被忽略。删除的行和删除的表不需要回归测试，不会列出。
+func BuildChecklists(report *Report, newSheets []*model.DataSheet, ownerColumn string, fields []string) []*Checklist {
+	sheetIndex := make(map[string]*model.DataSheet, len(newSheets))
+	for _, sheet := range newSheets {
+		sheetIndex[sheet.Name] = sheet
+	}
+
+	checklists := make([]*Checklist, 0)
+	for _, sheetDiff := range report.Sheets {
+		sheet := sheetIndex[sheetDiff.Name]
+		if sheet == nil || sheetDiff.Status == Removed {
+			continue
+		}
+
+		checklist := &Checklist{
+			Sheet:  sheet.Name,
+			Old:    report.Old,
+			New:    report.New,
+			Key:    sheetDiff.Key,
+			Fields: make([]string, 0, len(fields)),
+			Groups: make([]*ChecklistGroup, 0),
+		}
+		columns := columnNames(sheet)
+		for _, field := range fields {
+			if field != sheetDiff.Key && containsString(columns, field) {
+				checklist.Fields = append(checklist.Fields, field)
+			}
+		}
+
+		rows := make(map[string]map[string]interface{}, len(sheet.Rows))
+		for _, row := range sheet.Rows {
+			rows[keyOf(row, sheetDiff.Key)] = row
+		}
+
+		sheetOwner := sheetMetaString(sheet, "owner", "负责人")
+		groups := make(map[string]*ChecklistGroup)
+		for _, rowDiff := range sheetDiff.Rows {
+			row, exists := rows[rowDiff.Key]
+			if rowDiff.Status == Removed || !exists {
+				continue
+			}
+
+			item := &ChecklistItem{Key: rowDiff.Key, Status: rowDiff.Status, Fields: make(map[string]string, len(checklist.Fields))}
+			for _, field := range checklist.Fields {
+				value, _ := model.RowValue(row, field)
+				item.Fields[field] = FormatValue(value)
+			}
+			if rowDiff.Status == Modified {
+				for _, cell := range rowDiff.Cells {
+					item.Changed = append(item.Changed, cell.Column)
+				}
+			}
+
+			owner := sheetOwner
+			if ownerColumn != "" {
+				if value, _ := model.RowValue(row, ownerColumn); FormatValue(value) != "" {
+					owner = FormatValue(value)
+				}
+			}
+			if owner == "" {
+				owner = NoOwner
+			}
+			group, exists := groups[owner]
+			if !exists {
+				group = &ChecklistGroup{Owner: owner, Items: make([]*ChecklistItem, 0)}
+				groups[owner] = group
+				checklist.Groups = append(checklist.Groups, group)
+			}
+			group.Items = append(group.Items, item)
+		}
+		if len(checklist.Groups) == 0 {
+			continue
+		}
+
+		sort.SliceStable(checklist.Groups, func(i, j int) bool {
+			a, b := checklist.Groups[i].Owner, checklist.Groups[j].Owner
+			if (a == NoOwner) != (b == NoOwner) {
+				return b == NoOwner
+			}
+			return a < b
+		})
+		checklists = append(checklists, checklist)
+	}
+	return checklists
+}
+
+// sheetMetaString 获取表元数据中第一个非空的字符串值
+func sheetMetaString(sheet *model.DataSheet, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := sheet.Meta[key].(string); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// containsString 检查切片中是否包含指定的字符串
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
+
+// checklistStatus 检查清单中变化类型的显示文本
+var checklistStatus = map[string]string{
+	Added:    "新增",
+	Modified: "修改",
+}
+
+// Count 检查项总数
+func (c *Checklist) Count() int {
+	count := 0
+	for _, group := range c.Groups {
+		count += len(group.Items)
+	}
+	return count
+}
+
+// header 表格的列标题
+func (c *Checklist) header() []string {
+	header := []string{c.Key}
+	header = append(header, c.Fields...)
+	return append(header, "变化", "修改的列")
+}
+
+// cells 检查项在表格中的各列
+func (c *Checklist) cells(item *ChecklistItem) []string {
+	cells := []string{item.Key}
+	for _, field := range c.Fields {
+		cells = append(cells, item.Fields[field])
+	}
+	return append(cells, checklistStatus[item.Status], strings.Join(item.Changed, ", "))
+}
+
+// WriteMarkdown 输出 Markdown 格式的检查清单，每个检查项为一行任务列表
+func (c *Checklist) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s 回归测试清单\n\n", c.Sheet)
+	fmt.Fprintf(&b, "%s → %s，共 %d 行需要测试\n", c.Old, c.New, c.Count())
+
+	for _, group := range c.Groups {
+		fmt.Fprintf(&b, "\n## %s（%d 行）\n\n", group.Owner, len(group.Items))
+		for _, item := range group.Items {
+			fmt.Fprintf(&b, "- [ ] %s %s=%s", checklistStatus[item.Status], c.Key, markdownEscape(item.Key))
+			for _, field := range c.Fields {
+				fmt.Fprintf(&b, "，%s=%s", field, markdownEscape(item.Fields[field]))
+			}
+			if len(item.Changed) > 0 {
+				fmt.Fprintf(&b, "（修改: %s）", markdownEscape(strings.Join(item.Changed, ", ")))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscape 转义会影响 Markdown 列表项显示的字符，并把换行替换为空格
+func markdownEscape(text string) string {
+	return strings.NewReplacer("\\", "\\\\", "*", "\\*", "_", "\\_", "`", "\\`", "[", "\\[", "]", "\\]", "\r", "", "\n", " ").Replace(text)
+}
+
+// WriteXLSX 输出 xlsx 格式的检查清单，每个负责人一个工作表，最后一列供 QA 填写测试结果
+func (c *Checklist) WriteXLSX(w io.Writer) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	header := append(c.header(), "测试结果")
+	for i, group := range c.Groups {
+		name := xlsxSheetName(group.Owner, i)
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), name); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+
+		if err := f.SetSheetRow(name, "A1", &header); err != nil {
+			return err
+		}
+		for rowIndex, item := range group.Items {
+			cell, err := excelize.CoordinatesToCellName(1, rowIndex+2)
+			if err != nil {
+				return err
+			}
+			cells := c.cells(item)
+			if err := f.SetSheetRow(name, cell, &cells); err != nil {
+				return err
+			}
+		}
+	}
+	return f.Write(w)
+}
+
+// xlsxSheetName 工作表名称，去除 Excel 不允许的字符并限制在 31 个字符以内，以序号前缀保证唯一
+func xlsxSheetName(owner string, index int) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '_'
+		}
+		return r
+	}, owner)
+	name = fmt.Sprintf("%d %s", index+1, name)
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	return name
+}
@@ -0,0 +1,285 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// 表和行的变化类型
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Modified = "modified"
+)
+
+// Report 两次构建之间的数据差异
+type Report struct {
+	Old    string       `json:"old"`    // 旧版本来源
+	New    string       `json:"new"`    // 新版本来源
+	Sheets []*SheetDiff `json:"sheets"` // 有变化的表
+}
+
+// SheetDiff 单张表的差异
+type SheetDiff struct {
+	Name           string     `json:"name"`                     // 表名
+	Status         string     `json:"status"`                   // 变化类型
+	Key            string     `json:"key"`                      // 用于匹配行的主键列
+	ColumnsAdded   []string   `json:"columnsAdded,omitempty"`   // 新增的列
+	ColumnsRemoved []string   `json:"columnsRemoved,omitempty"` // 删除的列
+	Rows           []*RowDiff `json:"rows"`                     // 有变化的行
+}
+
+// RowDiff 单行的差异
+type RowDiff struct {
+	Key    string      `json:"key"`             // 主键值
+	Status string      `json:"status"`          // 变化类型
+	Cells  []*CellDiff `json:"cells,omitempty"` // 有变化的单元格，新增和删除的行包含所有非空单元格
+}
+
+// CellDiff 单元格的差异
+type CellDiff struct {
+	Column   string      `json:"column"`             // 列名
+	Old      interface{} `json:"old,omitempty"`      // 旧值
+	New      interface{} `json:"new,omitempty"`      // 新值
+	OldStyle string      `json:"oldStyle,omitempty"` // 旧值满足源文件中条件格式时的样式，如负数标红
+	NewStyle string      `json:"newStyle,omitempty"` // 新值满足源文件中条件格式时的样式
+	Comment  string      `json:"comment,omitempty"`  // 源文件中单元格的批注，新旧都有时使用新的
+}
+
+// Empty 是否没有任何差异
+func (r *Report) Empty() bool {
+	return len(r.Sheets) == 0
+}
+
+// Compare 按表名匹配表、按主键匹配行，比较两组数据表
+func Compare(oldSheets, newSheets []*model.DataSheet) *Report {
+	report := &Report{Sheets: make([]*SheetDiff, 0)}
+
+	oldIndex := make(map[string]*model.DataSheet, len(oldSheets))
+	for _, sheet := range oldSheets {
+		oldIndex[sheet.Name] = sheet
+	}
+	newIndex := make(map[string]*model.DataSheet, len(newSheets))
+	for _, sheet := range newSheets {
+		newIndex[sheet.Name] = sheet
+	}
+
+	names := make([]string, 0, len(oldIndex)+len(newIndex))
+	for name := range oldIndex {
+		names = append(names, name)
+	}
+	for name := range newIndex {
+		if _, exists := oldIndex[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if sheetDiff := compareSheet(oldIndex[name], newIndex[name]); sheetDiff != nil {
+			report.Sheets = append(report.Sheets, sheetDiff)
+		}
+	}
+	return report
+}
+
+// compareSheet 比较同名的两张表，任一方为空表示新增或删除，没有差异时返回 nil
+func compareSheet(oldSheet, newSheet *model.DataSheet) *SheetDiff {
+	switch {
+	case oldSheet == nil:
+		return wholeSheet(newSheet, Added)
+	case newSheet == nil:
+		return wholeSheet(oldSheet, Removed)
+	}
+
+	sheetDiff := &SheetDiff{
+		Name:   newSheet.Name,
+		Status: Modified,
+		Key:    newSheet.PrimaryKey(),
+		Rows:   make([]*RowDiff, 0),
+	}
+
+	oldColumns := columnNames(oldSheet)
+	newColumns := columnNames(newSheet)
+	sheetDiff.ColumnsAdded = missing(newColumns, oldColumns)
+	sheetDiff.ColumnsRemoved = missing(oldColumns, newColumns)
+
+	// 主键列变化时按新表的主键匹配旧表
+	oldRows := make(map[string]int, len(oldSheet.Rows))
+	for i, row := range oldSheet.Rows {
+		oldRows[keyOf(row, sheetDiff.Key)] = i
+	}
+
+	seen := make(map[string]bool, len(newSheet.Rows))
+	for i, row := range newSheet.Rows {
+		key := keyOf(row, sheetDiff.Key)
+		seen[key] = true
+		oldIndex, exists := oldRows[key]
+		if !exists {
+			sheetDiff.Rows = append(sheetDiff.Rows, wholeRow(newSheet, i, key, newColumns, Added))
+			continue
+		}
+		if cells := compareRow(oldSheet, oldIndex, newSheet, i, union(oldColumns, newColumns)); len(cells) > 0 {
+			sheetDiff.Rows = append(sheetDiff.Rows, &RowDiff{Key: key, Status: Modified, Cells: cells})
+		}
+	}
+	for i, row := range oldSheet.Rows {
+		key := keyOf(row, sheetDiff.Key)
+		if !seen[key] {
+			sheetDiff.Rows = append(sheetDiff.Rows, wholeRow(oldSheet, i, key, oldColumns, Removed))
+		}
+	}
+
+	if len(sheetDiff.Rows) == 0 && len(sheetDiff.ColumnsAdded) == 0 && len(sheetDiff.ColumnsRemoved) == 0 {
+		return nil
+	}
+	return sheetDiff
+}
+
+// wholeSheet 新增或删除的表，所有行都记为同样的变化
+func wholeSheet(sheet *model.DataSheet, status string) *SheetDiff {
+	sheetDiff := &SheetDiff{
+		Name:   sheet.Name,
+		Status: status,
+		Key:    sheet.PrimaryKey(),
+		Rows:   make([]*RowDiff, 0, len(sheet.Rows)),
+	}
+	columns := columnNames(sheet)
+	for i, row := range sheet.Rows {
+		sheetDiff.Rows = append(sheetDiff.Rows, wholeRow(sheet, i, keyOf(row, sheetDiff.Key), columns, status))
+	}
+	return sheetDiff
+}
+
+// wholeRow 新增或删除的行，记录所有非空单元格
+func wholeRow(sheet *model.DataSheet, rowIndex int, key string, columns []string, status string) *RowDiff {
+	row := sheet.Rows[rowIndex]
+	rowDiff := &RowDiff{Key: key, Status: status, Cells: make([]*CellDiff, 0, len(columns))}
+	for _, column := range columns {
+		value, exists := row[column]
+		if !exists || value == nil {
+			continue
+		}
+		cell := &CellDiff{Column: column, Comment: sheet.CellComment(rowIndex, column)}
+		if status == Added {
+			cell.New, cell.NewStyle = value, sheet.CellStyle(column, value)
+		} else {
+			cell.Old, cell.OldStyle = value, sheet.CellStyle(column, value)
+		}
+		rowDiff.Cells = append(rowDiff.Cells, cell)
+	}
+	return rowDiff
+}
+
+// compareRow 比较同一主键的两行，返回值不同的单元格
+func compareRow(oldSheet *model.DataSheet, oldIndex int, newSheet *model.DataSheet, newIndex int, columns []string) []*CellDiff {
+	oldRow, newRow := oldSheet.Rows[oldIndex], newSheet.Rows[newIndex]
+	cells := make([]*CellDiff, 0)
+	for _, column := range columns {
+		oldValue, newValue := oldRow[column], newRow[column]
+		if equal(oldValue, newValue) {
+			continue
+		}
+		comment := newSheet.CellComment(newIndex, column)
+		if comment == "" {
+			comment = oldSheet.CellComment(oldIndex, column)
+		}
+		cells = append(cells, &CellDiff{
+			Column:   column,
+			Old:      oldValue,
+			New:      newValue,
+			OldStyle: oldSheet.CellStyle(column, oldValue),
+			NewStyle: newSheet.CellStyle(column, newValue),
+			Comment:  comment,
+		})
+	}
+	return cells
+}
+
+// equal 按 JSON 表示比较两个值，使源数据中的整数与输出文件中解析出的数字可以比较
+func equal(a, b interface{}) bool {
+	return FormatValue(a) == FormatValue(b)
+}
+
+// FormatValue 把单元格的值格式化为紧凑的 JSON 文本，空值返回空字符串
+func FormatValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(content)
+}
+
+// keyOf 行的主键值
+func keyOf(row map[string]interface{}, key string) string {
+	return FormatValue(row[key])
+}
+
+// columnNames 按顺序获取表的列名
+func columnNames(sheet *model.DataSheet) []string {
+	names := make([]string, 0, len(sheet.Columns))
+	for _, column := range sheet.Columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
+
+// missing 返回在 a 中但不在 b 中的名称
+func missing(a, b []string) []string {
+	index := make(map[string]bool, len(b))
+	for _, name := range b {
+		index[name] = true
+	}
+	result := make([]string, 0)
+	for _, name := range a {
+		if !index[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// union 合并两组列名，保持先后顺序
+func union(a, b []string) []string {
+	return append(append([]string(nil), a...), missing(b, a)...)
+}
+
+// LoadOutputDir 读取输出目录中 JSON 转换器生成的表
+func LoadOutputDir(dir string) ([]*model.DataSheet, error) {
+	sheets := make([]*model.DataSheet, 0)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sheet, err := reader.ParseOutputSheet(content)
+		if err != nil {
+			return nil // 版本文件、清单等不是表数据
+		}
+		sheets = append(sheets, sheet)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取输出目录 %s 失败: %v", dir, err)
+	}
+	return sheets, nil
+}
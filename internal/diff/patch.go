@@ -0,0 +1,470 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// PatchOp RFC 6902 JSON Patch 中的一个操作
+type PatchOp struct {
+	Op    string      // add、remove、replace、move 或 test
+	Path  string      // 目标位置的 JSON Pointer
+	From  string      // move 的来源位置
+	Value interface{} // add、replace 和 test 的值
+}
+
+// MarshalJSON 按操作类型输出字段，值为 null 时同样输出 value
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	fields := struct {
+		Op    string           `json:"op"`
+		From  string           `json:"from,omitempty"`
+		Path  string           `json:"path"`
+		Value *json.RawMessage `json:"value,omitempty"`
+	}{Op: op.Op, Path: op.Path}
+	switch op.Op {
+	case "move":
+		fields.From = op.From
+	case "add", "replace", "test":
+		value, err := json.Marshal(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(value)
+		fields.Value = &raw
+	}
+	return json.Marshal(fields)
+}
+
+// JSONPatch 按主键比较 JSON 转换器先后生成的同一张表，返回把旧文件变为新文件的 JSON Patch；
+// 旧文件带有数据版本时，第一个操作检查该版本，避免补丁应用到错误的基础版本上
+func JSONPatch(oldContent, newContent []byte, versionKey string) ([]PatchOp, error) {
+	oldSheet, err := reader.ParseOutputSheet(oldContent)
+	if err != nil {
+		return nil, fmt.Errorf("解析旧文件失败: %v", err)
+	}
+	newSheet, err := reader.ParseOutputSheet(newContent)
+	if err != nil {
+		return nil, fmt.Errorf("解析新文件失败: %v", err)
+	}
+	oldDoc, err := decodeDocument(oldContent)
+	if err != nil {
+		return nil, err
+	}
+	newDoc, err := decodeDocument(newContent)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &patchBuilder{ops: make([]PatchOp, 0)}
+	for _, field := range []string{"name", "columns"} {
+		if !sameJSON(oldDoc[field], newDoc[field]) {
+			patch.set("/"+field, oldDoc, field, newDoc[field])
+		}
+	}
+
+	oldRows, newRows := oldDoc["rows"], newDoc["rows"]
+	oldArray, oldIsArray := oldRows.([]interface{})
+	newArray, newIsArray := newRows.([]interface{})
+	oldObject, oldIsObject := oldRows.(map[string]interface{})
+	newObject, newIsObject := newRows.(map[string]interface{})
+	switch {
+	case oldIsArray && newIsArray && oldSheet.PrimaryKey() == newSheet.PrimaryKey():
+		if !patch.diffArray("/rows", oldArray, newArray, newSheet.PrimaryKey()) {
+			patch.set("/rows", oldDoc, "rows", newRows)
+		}
+	case oldIsObject && newIsObject:
+		patch.diffObject("/rows", oldObject, newObject, 2)
+	case !sameJSON(oldRows, newRows):
+		patch.set("/rows", oldDoc, "rows", newRows)
+	}
+
+	oldMeta, oldIsObject := oldDoc["meta"].(map[string]interface{})
+	newMeta, newIsObject := newDoc["meta"].(map[string]interface{})
+	if oldIsObject && newIsObject {
+		patch.diffObject("/meta", oldMeta, newMeta, 0)
+	} else if !sameJSON(oldDoc["meta"], newDoc["meta"]) {
+		patch.set("/meta", oldDoc, "meta", newDoc["meta"])
+	}
+
+	if len(patch.ops) == 0 {
+		return patch.ops, nil
+	}
+	if version, exists := oldMeta[versionKey]; exists && versionKey != "" {
+		test := PatchOp{Op: "test", Path: "/meta/" + escapePointer(versionKey), Value: version}
+		patch.ops = append([]PatchOp{test}, patch.ops...)
+	}
+	return patch.ops, nil
+}
+
+// sameJSON 按 JSON 文本严格比较两个值，字符串 "1" 与数字 1 不相等
+func sameJSON(a, b interface{}) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	return err == nil && bytes.Equal(left, right)
+}
+
+// decodeDocument 解析 JSON 文件的顶层对象，数字保持原文以免丢失精度
+func decodeDocument(content []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// patchBuilder 依次收集补丁操作
+type patchBuilder struct {
+	ops []PatchOp
+}
+
+// set 新增、替换或删除对象的一个字段
+func (p *patchBuilder) set(path string, parent map[string]interface{}, field string, value interface{}) {
+	_, exists := parent[field]
+	switch {
+	case value == nil && !exists:
+	case !exists:
+		p.ops = append(p.ops, PatchOp{Op: "add", Path: path, Value: value})
+	default:
+		p.ops = append(p.ops, PatchOp{Op: "replace", Path: path, Value: value})
+	}
+}
+
+// diffObject 逐个字段比较对象，depth 大于 0 时对同时存在的对象字段继续逐个字段比较
+func (p *patchBuilder) diffObject(path string, oldObject, newObject map[string]interface{}, depth int) {
+	keys := make([]string, 0, len(oldObject)+len(newObject))
+	for key := range oldObject {
+		keys = append(keys, key)
+	}
+	for key := range newObject {
+		if _, exists := oldObject[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := path + "/" + escapePointer(key)
+		oldValue, oldExists := oldObject[key]
+		newValue, newExists := newObject[key]
+		switch {
+		case !newExists:
+			p.ops = append(p.ops, PatchOp{Op: "remove", Path: fieldPath})
+		case !oldExists:
+			p.ops = append(p.ops, PatchOp{Op: "add", Path: fieldPath, Value: newValue})
+		case sameJSON(oldValue, newValue):
+		default:
+			oldChild, oldIsObject := oldValue.(map[string]interface{})
+			newChild, newIsObject := newValue.(map[string]interface{})
+			if depth > 0 && oldIsObject && newIsObject {
+				p.diffObject(fieldPath, oldChild, newChild, depth-1)
+			} else {
+				p.ops = append(p.ops, PatchOp{Op: "replace", Path: fieldPath, Value: newValue})
+			}
+		}
+	}
+}
+
+// diffArray 按主键匹配数组形式的行：先删除不再存在的行，再按新顺序新增或移动行，最后比较各行的字段；
+// 主键为空或重复而无法匹配时返回 false
+func (p *patchBuilder) diffArray(path string, oldRows, newRows []interface{}, keyColumn string) bool {
+	oldKeys, ok := arrayKeys(oldRows, keyColumn)
+	if !ok {
+		return false
+	}
+	newKeys, ok := arrayKeys(newRows, keyColumn)
+	if !ok {
+		return false
+	}
+
+	oldIndex := make(map[string]int, len(oldKeys))
+	for i, key := range oldKeys {
+		oldIndex[key] = i
+	}
+	newIndex := make(map[string]bool, len(newKeys))
+	for _, key := range newKeys {
+		newIndex[key] = true
+	}
+
+	// current 模拟应用补丁过程中数组的主键顺序
+	current := make([]string, 0, len(oldKeys))
+	for _, key := range oldKeys {
+		current = append(current, key)
+	}
+	for i := len(oldKeys) - 1; i >= 0; i-- {
+		if !newIndex[oldKeys[i]] {
+			p.ops = append(p.ops, PatchOp{Op: "remove", Path: fmt.Sprintf("%s/%d", path, i)})
+			current = append(current[:i], current[i+1:]...)
+		}
+	}
+
+	for i, key := range newKeys {
+		rowPath := fmt.Sprintf("%s/%d", path, i)
+		oldPosition, exists := oldIndex[key]
+		if !exists {
+			p.ops = append(p.ops, PatchOp{Op: "add", Path: rowPath, Value: newRows[i]})
+			current = append(current[:i], append([]string{key}, current[i:]...)...)
+			continue
+		}
+
+		if current[i] != key {
+			j := i + 1
+			for current[j] != key {
+				j++
+			}
+			p.ops = append(p.ops, PatchOp{Op: "move", From: fmt.Sprintf("%s/%d", path, j), Path: rowPath})
+			current = append(current[:j], current[j+1:]...)
+			current = append(current[:i], append([]string{key}, current[i:]...)...)
+		}
+
+		oldRow, oldIsObject := oldRows[oldPosition].(map[string]interface{})
+		newRow, newIsObject := newRows[i].(map[string]interface{})
+		if oldIsObject && newIsObject {
+			p.diffObject(rowPath, oldRow, newRow, 1)
+		} else if !sameJSON(oldRows[oldPosition], newRows[i]) {
+			p.ops = append(p.ops, PatchOp{Op: "replace", Path: rowPath, Value: newRows[i]})
+		}
+	}
+	return true
+}
+
+// arrayKeys 数组形式的行的主键，主键为空或重复时返回 false
+func arrayKeys(rows []interface{}, keyColumn string) ([]string, bool) {
+	keys := make([]string, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, item := range rows {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, _ := model.RowValue(row, keyColumn)
+		key := FormatValue(value)
+		if key == "" || seen[key] {
+			return nil, false
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys, true
+}
+
+// ApplyPatch 把补丁应用到以 UseNumber 解析的 JSON 文档上，返回新文档，原文档不变
+func ApplyPatch(doc interface{}, ops []PatchOp) (interface{}, error) {
+	var err error
+	doc = deepCopy(doc)
+	for i, op := range ops {
+		switch op.Op {
+		case "add":
+			doc, err = pointerAdd(doc, op.Path, deepCopy(op.Value))
+		case "remove":
+			doc, _, err = pointerRemove(doc, op.Path)
+		case "replace":
+			if doc, _, err = pointerRemove(doc, op.Path); err == nil {
+				doc, err = pointerAdd(doc, op.Path, deepCopy(op.Value))
+			}
+		case "move":
+			var value interface{}
+			if doc, value, err = pointerRemove(doc, op.From); err == nil {
+				doc, err = pointerAdd(doc, op.Path, value)
+			}
+		case "test":
+			var value interface{}
+			if value, err = pointerGet(doc, op.Path); err == nil && !sameJSON(value, op.Value) {
+				err = fmt.Errorf("%s 的值为 %s，期望 %s", op.Path, FormatValue(value), FormatValue(op.Value))
+			}
+		default:
+			err = fmt.Errorf("不支持的操作 %s", op.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个操作: %v", i+1, err)
+		}
+	}
+	return doc, nil
+}
+
+// deepCopy 复制 JSON 值中的对象和数组
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+// splitPointer 拆分 JSON Pointer，返回父级路径的各段和最后一段
+func splitPointer(pointer string) ([]string, string, error) {
+	if pointer == "" || pointer[0] != '/' {
+		return nil, "", fmt.Errorf("路径 %q 无效", pointer)
+	}
+	segments := strings.Split(pointer[1:], "/")
+	for i := range segments {
+		segments[i] = unescapePointer(segments[i])
+	}
+	return segments[:len(segments)-1], segments[len(segments)-1], nil
+}
+
+// pointerGet 获取路径指向的值
+func pointerGet(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	parents, last, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	current := doc
+	for _, segment := range append(parents, last) {
+		switch container := current.(type) {
+		case map[string]interface{}:
+			value, exists := container[segment]
+			if !exists {
+				return nil, fmt.Errorf("路径 %s 不存在", pointer)
+			}
+			current = value
+		case []interface{}:
+			index, err := pointerIndex(segment, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = container[index]
+		default:
+			return nil, fmt.Errorf("路径 %s 不存在", pointer)
+		}
+	}
+	return current, nil
+}
+
+// pointerAdd 在路径处插入值，数组中的元素后移
+func pointerAdd(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	if pointer == "" {
+		return value, nil
+	}
+	parents, last, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return updateParent(doc, parents, func(parent interface{}) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			container[last] = value
+			return container, nil
+		case []interface{}:
+			index, err := pointerIndex(last, len(container))
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:index], append([]interface{}{value}, container[index:]...)...), nil
+		default:
+			return nil, fmt.Errorf("路径 %s 的父级不是对象或数组", pointer)
+		}
+	})
+}
+
+// pointerRemove 删除路径处的值并返回被删除的值
+func pointerRemove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	parents, last, err := splitPointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	var removed interface{}
+	doc, err = updateParent(doc, parents, func(parent interface{}) (interface{}, error) {
+		switch container := parent.(type) {
+		case map[string]interface{}:
+			value, exists := container[last]
+			if !exists {
+				return nil, fmt.Errorf("路径 %s 不存在", pointer)
+			}
+			removed = value
+			delete(container, last)
+			return container, nil
+		case []interface{}:
+			index, err := pointerIndex(last, len(container)-1)
+			if err != nil || last == "-" {
+				return nil, fmt.Errorf("路径 %s 不存在", pointer)
+			}
+			removed = container[index]
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			return nil, fmt.Errorf("路径 %s 不存在", pointer)
+		}
+	})
+	return doc, removed, err
+}
+
+// updateParent 找到父级容器并以 update 的结果替换它，数组长度变化时需要写回上一级
+func updateParent(doc interface{}, parents []string, update func(parent interface{}) (interface{}, error)) (interface{}, error) {
+	if len(parents) == 0 {
+		return update(doc)
+	}
+	segment := parents[0]
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		child, exists := container[segment]
+		if !exists {
+			return nil, fmt.Errorf("路径段 %s 不存在", segment)
+		}
+		updated, err := updateParent(child, parents[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		container[segment] = updated
+		return container, nil
+	case []interface{}:
+		index, err := pointerIndex(segment, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := updateParent(container[index], parents[1:], update)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	default:
+		return nil, fmt.Errorf("路径段 %s 不存在", segment)
+	}
+}
+
+// escapePointer 转义 JSON Pointer 中的一段
+func escapePointer(segment string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+}
+
+// unescapePointer 还原 JSON Pointer 中转义的一段
+func unescapePointer(segment string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+}
+
+// pointerIndex 解析数组下标，下标不能超过 max，- 表示 max
+func pointerIndex(segment string, max int) (int, error) {
+	if segment == "-" {
+		return max, nil
+	}
+	index, err := strconv.Atoi(segment)
+	if err != nil || index < 0 || index > max {
+		return 0, fmt.Errorf("数组下标 %s 无效", segment)
+	}
+	return index, nil
+}
@@ -0,0 +1,138 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// statusMarks 文本报告中各变化类型的标记
+var statusMarks = map[string]string{
+	Added:    "+",
+	Removed:  "-",
+	Modified: "~",
+}
+
+// WriteText 输出文本格式的差异报告
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", r.Old, r.New)
+	if r.Empty() {
+		b.WriteString("没有数据变化\n")
+	}
+
+	for _, sheet := range r.Sheets {
+		added, removed, modified := sheet.Counts()
+		fmt.Fprintf(&b, "\n%s 表 %s（新增 %d 行，删除 %d 行，修改 %d 行）\n", statusMarks[sheet.Status], sheet.Name, added, removed, modified)
+		if len(sheet.ColumnsAdded) > 0 {
+			fmt.Fprintf(&b, "  新增列: %s\n", strings.Join(sheet.ColumnsAdded, ", "))
+		}
+		if len(sheet.ColumnsRemoved) > 0 {
+			fmt.Fprintf(&b, "  删除列: %s\n", strings.Join(sheet.ColumnsRemoved, ", "))
+		}
+
+		// 新增或删除整张表时只列出主键
+		if sheet.Status != Modified {
+			continue
+		}
+		for _, row := range sheet.Rows {
+			fmt.Fprintf(&b, "  %s %s=%s\n", statusMarks[row.Status], sheet.Key, row.Key)
+			if row.Status != Modified {
+				continue
+			}
+			for _, cell := range row.Cells {
+				fmt.Fprintf(&b, "      %s: %s -> %s\n", cell.Column, FormatValue(cell.Old), FormatValue(cell.New))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON 输出 JSON 格式的差异报告
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// htmlTemplate HTML 报告模板
+var htmlTemplate = template.Must(template.New("diff").Funcs(template.FuncMap{
+	"value": FormatValue,
+	"css":   func(style string) template.CSS { return template.CSS(style) }, // 样式由读取器按条件格式生成，颜色已校验
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>数据差异</title>
+<style>
+body { font-family: sans-serif; margin: 24px; }
+table { border-collapse: collapse; margin-bottom: 24px; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.added { background: #e6ffed; }
+.removed { background: #ffeef0; }
+.modified { background: #fff8c5; }
+.old { color: #b31d28; text-decoration: line-through; }
+.new { color: #22863a; }
+.commented { border-bottom: 1px dotted #999; cursor: help; }
+</style>
+</head>
+<body>
+<h1>数据差异</h1>
+<p>{{.Old}} → {{.New}}</p>
+{{if not .Sheets}}<p>没有数据变化</p>{{end}}
+{{range .Sheets}}
+<h2 class="{{.Status}}">{{.Name}}</h2>
+{{if .ColumnsAdded}}<p>新增列: {{range .ColumnsAdded}}{{.}} {{end}}</p>{{end}}
+{{if .ColumnsRemoved}}<p>删除列: {{range .ColumnsRemoved}}{{.}} {{end}}</p>{{end}}
+<table>
+<tr><th>{{.Key}}</th><th>变化</th><th>单元格</th></tr>
+{{range .Rows}}
+<tr class="{{.Status}}">
+<td>{{.Key}}</td>
+<td>{{.Status}}</td>
+<td>{{range $cell := .Cells}}<div{{with .Comment}} class="commented" title="{{.}}"{{end}}>{{.Column}}: {{with value .Old}}<span class="old"{{with $cell.OldStyle}} style="{{css .}}"{{end}}>{{.}}</span> {{end}}{{with value .New}}<span class="new"{{with $cell.NewStyle}} style="{{css .}}"{{end}}>{{.}}</span>{{end}}</div>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML 输出 HTML 格式的差异报告
+func (r *Report) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+// Write 按格式输出差异报告：text、html 或 json
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case "", "text":
+		return r.WriteText(w)
+	case "html":
+		return r.WriteHTML(w)
+	case "json":
+		return r.WriteJSON(w)
+	default:
+		return fmt.Errorf("不支持的报告格式: %s", format)
+	}
+}
+
+// Counts 统计新增、删除和修改的行数
+func (s *SheetDiff) Counts() (added, removed, modified int) {
+	for _, row := range s.Rows {
+		switch row.Status {
+		case Added:
+			added++
+		case Removed:
+			removed++
+		case Modified:
+			modified++
+		}
+	}
+	return added, removed, modified
+}
@@ -0,0 +1,321 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// Env 表达式求值环境，通常为一行数据
+type Env map[string]interface{}
+
+// Expr 编译后的表达式
+//
+// 支持数字、字符串、布尔字面量，四则运算与取模，比较运算，
+// 逻辑运算（&&、||、!，或 and、or、not），以及内置函数调用。
+type Expr struct {
+	source string
+	root   node
+}
+
+// Compile 编译表达式
+func Compile(src string) (*Expr, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("表达式 %q 无效: %v", src, err)
+	}
+	return &Expr{source: src, root: root}, nil
+}
+
+// String 返回表达式源码
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Eval 在指定环境中求值
+func (e *Expr) Eval(env Env) (interface{}, error) {
+	val, err := e.root.eval(env)
+	if err != nil {
+		return nil, fmt.Errorf("表达式 %q 求值失败: %v", e.source, err)
+	}
+	return val, nil
+}
+
+// EvalBool 求值并转换为布尔值
+func (e *Expr) EvalBool(env Env) (bool, error) {
+	val, err := e.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	return truthy(val), nil
+}
+
+// ToType 将表达式结果转换为指定的列类型
+func ToType(val interface{}, colType string) (interface{}, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	switch colType {
+	case "int", "integer":
+		num, ok := toFloat(val)
+		if !ok {
+			return nil, fmt.Errorf("无法将 %v 转换为 %s", val, colType)
+		}
+		return int(math.Round(num)), nil
+	case "float", "double", "number":
+		num, ok := toFloat(val)
+		if !ok {
+			return nil, fmt.Errorf("无法将 %v 转换为 %s", val, colType)
+		}
+		return num, nil
+	case "bool", "boolean":
+		return truthy(val), nil
+	case "string":
+		return toString(val), nil
+	default:
+		return val, nil
+	}
+}
+
+func (n *literalNode) eval(env Env) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *identNode) eval(env Env) (interface{}, error) {
+	val, exists := model.RowValue(env, n.name)
+	if !exists {
+		return nil, fmt.Errorf("未知的变量 %s", n.name)
+	}
+	return val, nil
+}
+
+func (n *unaryNode) eval(env Env) (interface{}, error) {
+	val, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "!" {
+		return !truthy(val), nil
+	}
+
+	switch v := val.(type) {
+	case int:
+		return -v, nil
+	case float64:
+		return -v, nil
+	default:
+		return nil, fmt.Errorf("无法对 %v 取负", val)
+	}
+}
+
+func (n *binaryNode) eval(env Env) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// 逻辑运算短路求值
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		return truthy(right), nil
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		return truthy(right), nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compareOp(n.op, left, right)
+	default:
+		return arithmetic(n.op, left, right)
+	}
+}
+
+func (n *callNode) eval(env Env) (interface{}, error) {
+	args := make([]interface{}, 0, len(n.args))
+	for _, arg := range n.args {
+		val, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, val)
+	}
+	return functions[n.name](args)
+}
+
+// arithmetic 四则运算，字符串与任意值相加时为拼接
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	if op == "+" {
+		_, leftIsStr := left.(string)
+		_, rightIsStr := right.(string)
+		if leftIsStr || rightIsStr {
+			return toString(left) + toString(right), nil
+		}
+	}
+
+	leftInt, leftIsInt := left.(int)
+	rightInt, rightIsInt := right.(int)
+	if leftIsInt && rightIsInt && op != "/" {
+		switch op {
+		case "+":
+			return leftInt + rightInt, nil
+		case "-":
+			return leftInt - rightInt, nil
+		case "*":
+			return leftInt * rightInt, nil
+		case "%":
+			if rightInt == 0 {
+				return nil, fmt.Errorf("除数为0")
+			}
+			return leftInt % rightInt, nil
+		}
+	}
+
+	leftNum, ok := toFloat(left)
+	if !ok {
+		return nil, fmt.Errorf("%v 不是数字", left)
+	}
+	rightNum, ok := toFloat(right)
+	if !ok {
+		return nil, fmt.Errorf("%v 不是数字", right)
+	}
+
+	switch op {
+	case "+":
+		return leftNum + rightNum, nil
+	case "-":
+		return leftNum - rightNum, nil
+	case "*":
+		return leftNum * rightNum, nil
+	case "/":
+		if rightNum == 0 {
+			return nil, fmt.Errorf("除数为0")
+		}
+		return leftNum / rightNum, nil
+	case "%":
+		if rightNum == 0 {
+			return nil, fmt.Errorf("除数为0")
+		}
+		return math.Mod(leftNum, rightNum), nil
+	default:
+		return nil, fmt.Errorf("未知运算符 %s", op)
+	}
+}
+
+// compareOp 大小比较，数字按数值比较，其余按字符串比较
+func compareOp(op string, left, right interface{}) (interface{}, error) {
+	var cmp int
+	leftNum, leftOk := toFloat(left)
+	rightNum, rightOk := toFloat(right)
+	if leftOk && rightOk {
+		switch {
+		case leftNum < rightNum:
+			cmp = -1
+		case leftNum > rightNum:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(toString(left), toString(right))
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// equal 相等比较，数字按数值比较
+func equal(left, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	leftNum, leftOk := toFloat(left)
+	rightNum, rightOk := toFloat(right)
+	if leftOk && rightOk {
+		return leftNum == rightNum
+	}
+	return toString(left) == toString(right)
+}
+
+// truthy 判断值的真假
+func truthy(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
+
+// toFloat 将数值转换为float64
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// toString 将值转换为字符串
+func toString(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,125 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// function 内置函数
+type function func(args []interface{}) (interface{}, error)
+
+// functions 内置函数表
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"lower": stringFunc(strings.ToLower),
+		"upper": stringFunc(strings.ToUpper),
+		"trim":  stringFunc(strings.TrimSpace),
+		"len": func(args []interface{}) (interface{}, error) {
+			if err := argCount("len", args, 1); err != nil {
+				return nil, err
+			}
+			return len([]rune(toString(args[0]))), nil
+		},
+		"contains": func(args []interface{}) (interface{}, error) {
+			if err := argCount("contains", args, 2); err != nil {
+				return nil, err
+			}
+			return strings.Contains(toString(args[0]), toString(args[1])), nil
+		},
+		"abs":   mathFunc("abs", math.Abs),
+		"floor": mathFunc("floor", math.Floor),
+		"ceil":  mathFunc("ceil", math.Ceil),
+		"round": mathFunc("round", math.Round),
+		"min": func(args []interface{}) (interface{}, error) {
+			return pick("min", args, func(a, b float64) bool { return a < b })
+		},
+		"max": func(args []interface{}) (interface{}, error) {
+			return pick("max", args, func(a, b float64) bool { return a > b })
+		},
+		"int": func(args []interface{}) (interface{}, error) {
+			if err := argCount("int", args, 1); err != nil {
+				return nil, err
+			}
+			return ToType(args[0], "int")
+		},
+		"float": func(args []interface{}) (interface{}, error) {
+			if err := argCount("float", args, 1); err != nil {
+				return nil, err
+			}
+			return ToType(args[0], "float")
+		},
+		"str": func(args []interface{}) (interface{}, error) {
+			if err := argCount("str", args, 1); err != nil {
+				return nil, err
+			}
+			return toString(args[0]), nil
+		},
+		"if": func(args []interface{}) (interface{}, error) {
+			if err := argCount("if", args, 3); err != nil {
+				return nil, err
+			}
+			if truthy(args[0]) {
+				return args[1], nil
+			}
+			return args[2], nil
+		},
+	}
+}
+
+// argCount 检查参数个数
+func argCount(name string, args []interface{}, count int) error {
+	if len(args) != count {
+		return fmt.Errorf("函数 %s 需要 %d 个参数，实际 %d 个", name, count, len(args))
+	}
+	return nil
+}
+
+// stringFunc 包装单参数字符串函数
+func stringFunc(fn func(string) string) function {
+	return func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("需要 1 个参数，实际 %d 个", len(args))
+		}
+		return fn(toString(args[0])), nil
+	}
+}
+
+// mathFunc 包装单参数数学函数
+func mathFunc(name string, fn func(float64) float64) function {
+	return func(args []interface{}) (interface{}, error) {
+		if err := argCount(name, args, 1); err != nil {
+			return nil, err
+		}
+		num, ok := toFloat(args[0])
+		if !ok {
+			return nil, fmt.Errorf("函数 %s 的参数 %v 不是数字", name, args[0])
+		}
+		return fn(num), nil
+	}
+}
+
+// pick 从参数中选出满足比较条件的数值
+func pick(name string, args []interface{}, better func(a, b float64) bool) (interface{}, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("函数 %s 至少需要 1 个参数", name)
+	}
+
+	result := args[0]
+	best, ok := toFloat(result)
+	if !ok {
+		return nil, fmt.Errorf("函数 %s 的参数 %v 不是数字", name, result)
+	}
+	for _, arg := range args[1:] {
+		num, ok := toFloat(arg)
+		if !ok {
+			return nil, fmt.Errorf("函数 %s 的参数 %v 不是数字", name, arg)
+		}
+		if better(num, best) {
+			best, result = num, arg
+		}
+	}
+	return result, nil
+}
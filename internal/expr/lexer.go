@@ -0,0 +1,131 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token 词法单元
+type token struct {
+	kind  tokenKind
+	text  string
+	value interface{} // 数字或字符串字面量的值
+	pos   int
+}
+
+// operators 支持的运算符，长运算符在前
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!"}
+
+// tokenize 将表达式拆分为词法单元
+func tokenize(src string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(src)
+	i := 0
+
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			var builder strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				builder.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("位置 %d: 字符串未结束", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[start:i]), value: builder.String(), pos: start})
+
+		case unicode.IsDigit(r):
+			start := i
+			isFloat := false
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				if runes[i] == '.' {
+					isFloat = true
+				}
+				i++
+			}
+			text := string(runes[start:i])
+			value, err := parseNumber(text, isFloat)
+			if err != nil {
+				return nil, fmt.Errorf("位置 %d: 无效的数字 %s", start, text)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: value, pos: start})
+
+		case isIdentStart(r):
+			start := i
+			for i < len(runes) && (isIdentStart(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			switch text {
+			case "and":
+				tokens = append(tokens, token{kind: tokenOperator, text: "&&", pos: start})
+			case "or":
+				tokens = append(tokens, token{kind: tokenOperator, text: "||", pos: start})
+			case "not":
+				tokens = append(tokens, token{kind: tokenOperator, text: "!", pos: start})
+			default:
+				tokens = append(tokens, token{kind: tokenIdent, text: text, pos: start})
+			}
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("位置 %d: 无法识别的字符 %q", i, r)
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// isIdentStart 检查是否可以作为标识符的字符
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
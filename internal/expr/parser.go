@@ -0,0 +1,195 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// node 语法树节点
+type node interface {
+	eval(env Env) (interface{}, error)
+}
+
+// literalNode 字面量
+type literalNode struct {
+	value interface{}
+}
+
+// identNode 变量引用，支持 reward.count 形式的嵌套字段
+type identNode struct {
+	name string
+}
+
+// unaryNode 一元运算
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+// binaryNode 二元运算
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+// callNode 函数调用
+type callNode struct {
+	name string
+	args []node
+}
+
+// binaryPrecedence 二元运算符优先级，数值越大优先级越高
+var binaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// parser 语法分析器
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse 解析完整表达式
+func parse(src string) (node, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("位置 %d: 多余的内容 %s", tok.pos, tok.text)
+	}
+	return n, nil
+}
+
+// peek 查看当前词法单元
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next 读取当前词法单元并前进
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseBinary 按优先级解析二元运算
+func (p *parser) parseBinary(minPrecedence int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		precedence, isBinary := binaryPrecedence[tok.text]
+		if tok.kind != tokenOperator || !isBinary || precedence < minPrecedence {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: tok.text, left: left, right: right}
+	}
+}
+
+// parseUnary 解析一元运算
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+	if tok.kind == tokenOperator && (tok.text == "!" || tok.text == "-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: tok.text, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary 解析字面量、变量、函数调用和括号表达式
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber, tokenString:
+		return &literalNode{value: tok.value}, nil
+
+	case tokenIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "nil", "null":
+			return &literalNode{value: nil}, nil
+		}
+
+		if p.peek().kind != tokenLParen {
+			return &identNode{name: tok.text}, nil
+		}
+
+		// 函数调用
+		p.next()
+		args := make([]node, 0)
+		if p.peek().kind != tokenRParen {
+			for {
+				arg, err := p.parseBinary(1)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.peek().kind != tokenComma {
+					break
+				}
+				p.next()
+			}
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("位置 %d: 函数 %s 缺少右括号", closing.pos, tok.text)
+		}
+		if _, exists := functions[tok.text]; !exists {
+			return nil, fmt.Errorf("位置 %d: 未知函数 %s", tok.pos, tok.text)
+		}
+		return &callNode{name: tok.text, args: args}, nil
+
+	case tokenLParen:
+		inner, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("位置 %d: 缺少右括号", closing.pos)
+		}
+		return inner, nil
+
+	case tokenEOF:
+		return nil, fmt.Errorf("表达式意外结束")
+
+	default:
+		return nil, fmt.Errorf("位置 %d: 意外的 %s", tok.pos, tok.text)
+	}
+}
+
+// parseNumber 解析数字字面量
+func parseNumber(text string, isFloat bool) (interface{}, error) {
+	if isFloat {
+		return strconv.ParseFloat(text, 64)
+	}
+	return strconv.Atoi(text)
+}
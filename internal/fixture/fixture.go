@@ -0,0 +1,133 @@
+package fixture
+
+import (
+	"sort"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// Select 从数据表中截取测试夹具：每张表保留前 rows 行，再补上这些行引用到的被引用表中的行，
+// 直到所有引用都能在夹具中找到；补入的行可能继续引用其他表，会一并补上
+//
+// 返回的表与原表一一对应，行保持原来的顺序，原表不会被修改。
+// 引用的值在原数据中就不存在时无法补齐，这类错误由引用验证报告
+func Select(sheets []*model.DataSheet, rows int) []*model.DataSheet {
+	if rows < 0 {
+		rows = 0
+	}
+
+	byName := make(map[string]int, len(sheets))
+	for i, sheet := range sheets {
+		byName[sheet.Name] = i
+	}
+
+	// 被引用表按引用的列（未指定时为主键）建立索引，与引用验证一致
+	refIndex := make(map[model.RefInfo]map[interface{}]int)
+	index := func(target *model.DataSheet, ref *model.RefInfo) map[interface{}]int {
+		column := ref.Column
+		if column == "" {
+			column = target.PrimaryKey()
+		}
+		key := model.RefInfo{Sheet: target.Name, Column: column}
+		if values, exists := refIndex[key]; exists {
+			return values
+		}
+		values := make(map[interface{}]int)
+		for rowIndex, row := range target.Rows {
+			val, _ := model.RowValue(row, column)
+			for _, item := range refValues(val) {
+				if _, duplicated := values[item]; !duplicated {
+					values[item] = rowIndex
+				}
+			}
+		}
+		refIndex[key] = values
+		return values
+	}
+
+	// 先选中每张表的前 rows 行，再逐行检查引用，新补入的行加入待检查队列
+	selected := make([]map[int]bool, len(sheets))
+	type pending struct{ sheet, row int }
+	queue := make([]pending, 0)
+	for i, sheet := range sheets {
+		selected[i] = make(map[int]bool)
+		for rowIndex := 0; rowIndex < len(sheet.Rows) && rowIndex < rows; rowIndex++ {
+			selected[i][rowIndex] = true
+			queue = append(queue, pending{i, rowIndex})
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		sheet := sheets[current.sheet]
+		row := sheet.Rows[current.row]
+		for _, col := range sheet.Columns {
+			if col.Ref == nil {
+				continue
+			}
+			target, exists := byName[col.Ref.Sheet]
+			if !exists {
+				continue
+			}
+			values := index(sheets[target], col.Ref)
+			val, _ := model.RowValue(row, col.Name)
+			for _, ref := range refValues(val) {
+				rowIndex, found := values[ref]
+				if found && !selected[target][rowIndex] {
+					selected[target][rowIndex] = true
+					queue = append(queue, pending{target, rowIndex})
+				}
+			}
+		}
+	}
+
+	result := make([]*model.DataSheet, len(sheets))
+	for i, sheet := range sheets {
+		indexes := make([]int, 0, len(selected[i]))
+		for rowIndex := range selected[i] {
+			indexes = append(indexes, rowIndex)
+		}
+		sort.Ints(indexes)
+
+		clone := *sheet
+		clone.Rows = make([]map[string]interface{}, len(indexes))
+		clone.RowNumbers = make([]int, len(indexes))
+		for j, rowIndex := range indexes {
+			clone.Rows[j] = sheet.Rows[rowIndex]
+			clone.RowNumbers[j] = sheet.RowNumber(rowIndex)
+		}
+		result[i] = &clone
+	}
+	return result
+}
+
+// refValues 引用列中的引用值，数组列的每个元素都是一个引用
+func refValues(val interface{}) []interface{} {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if hashable(item) {
+				values = append(values, item)
+			}
+		}
+		return values
+	default:
+		if hashable(v) {
+			return []interface{}{v}
+		}
+		return nil
+	}
+}
+
+// hashable 值能否作为索引的键，数组和对象不能
+func hashable(val interface{}) bool {
+	switch val.(type) {
+	case nil, []interface{}, map[string]interface{}:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,155 @@
+package freeze
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// 冻结表内容变化时的处理方式
+const (
+	ModeFail = "fail" // 构建失败
+	ModeWarn = "warn" // 仅警告
+)
+
+// Violation 冻结表未经批准的内容变化
+type Violation struct {
+	Sheet       string // 表名
+	FrozenHash  string // 冻结时的内容哈希
+	CurrentHash string // 当前内容哈希
+	Reason      string // 冻结原因
+	Missing     bool   // 冻结表已从构建中移除
+}
+
+// Error 实现error接口
+func (v *Violation) Error() string {
+	if v.Missing {
+		return fmt.Sprintf("冻结表 %s 已从构建中移除（冻结原因: %s）", v.Sheet, v.Reason)
+	}
+	return fmt.Sprintf("冻结表 %s 的内容已变化且未经批准（冻结原因: %s，当前哈希: %s）", v.Sheet, v.Reason, v.CurrentHash)
+}
+
+// Check 检查冻结表的内容是否变化，已批准的内容哈希视为合法，构建中缺少的冻结表同样视为变化
+func Check(sheets []*model.DataSheet, frozen *config.FrozenConfig) ([]*Violation, error) {
+	violations := make([]*Violation, 0)
+	if frozen == nil {
+		return violations, nil
+	}
+
+	sheetMap := make(map[string]*model.DataSheet, len(sheets))
+	for _, sheet := range sheets {
+		sheetMap[sheet.Name] = sheet
+	}
+	names := make([]string, 0, len(frozen.Sheets))
+	for name := range frozen.Sheets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		frozenSheet := frozen.Sheets[name]
+		sheet, exists := sheetMap[name]
+		if !exists {
+			violations = append(violations, &Violation{Sheet: name, FrozenHash: frozenSheet.Hash, Reason: frozenSheet.Reason, Missing: true})
+			continue
+		}
+
+		currentHash, err := sheet.ContentHash()
+		if err != nil {
+			return nil, err
+		}
+		if currentHash == frozenSheet.Hash || approved(frozen, frozenSheet, currentHash) {
+			continue
+		}
+
+		violations = append(violations, &Violation{
+			Sheet:       sheet.Name,
+			FrozenHash:  frozenSheet.Hash,
+			CurrentHash: currentHash,
+			Reason:      frozenSheet.Reason,
+		})
+	}
+
+	return violations, nil
+}
+
+// Freeze 将指定表以当前内容冻结
+func Freeze(sheets []*model.DataSheet, frozen *config.FrozenConfig, names []string, reason string) error {
+	sheetMap := make(map[string]*model.DataSheet)
+	for _, sheet := range sheets {
+		sheetMap[sheet.Name] = sheet
+	}
+
+	for _, name := range names {
+		sheet, exists := sheetMap[name]
+		if !exists {
+			return fmt.Errorf("表 %s 不存在", name)
+		}
+		hash, err := sheet.ContentHash()
+		if err != nil {
+			return err
+		}
+		frozen.Sheets[name] = config.FrozenSheet{Hash: hash, Reason: reason}
+	}
+	return nil
+}
+
+// Approve 以批准人的令牌批准冻结表的当前内容，令牌须与 approvers 中配置的哈希一致
+func Approve(sheets []*model.DataSheet, frozen *config.FrozenConfig, name, token, by string) error {
+	frozenSheet, exists := frozen.Sheets[name]
+	if !exists {
+		return fmt.Errorf("表 %s 未被冻结", name)
+	}
+	if len(frozen.Approvers) == 0 {
+		return fmt.Errorf("frozen.json 未配置 approvers，无法批准")
+	}
+	if !validToken(frozen, by, token) {
+		return fmt.Errorf("批准人 %s 的令牌无效", by)
+	}
+
+	for _, sheet := range sheets {
+		if sheet.Name == name {
+			hash, err := sheet.ContentHash()
+			if err != nil {
+				return err
+			}
+			frozenSheet.Approvals = append(frozenSheet.Approvals, config.Approval{
+				Hash: hash,
+				By:   by,
+			})
+			frozen.Sheets[name] = frozenSheet
+			return nil
+		}
+	}
+	return fmt.Errorf("表 %s 不存在", name)
+}
+
+// TokenHash 批准令牌的 SHA-256（十六进制），用于在 approvers 中配置而不保存令牌本身
+func TokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// validToken 检查令牌是否与批准人配置的哈希一致
+func validToken(frozen *config.FrozenConfig, by, token string) bool {
+	expected, exists := frozen.Approvers[by]
+	if !exists || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(TokenHash(token)), []byte(expected)) == 1
+}
+
+// approved 检查内容哈希是否已被仍在 approvers 中的批准人批准
+func approved(frozen *config.FrozenConfig, frozenSheet config.FrozenSheet, hash string) bool {
+	for _, approval := range frozenSheet.Approvals {
+		if _, exists := frozen.Approvers[approval.By]; exists && approval.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
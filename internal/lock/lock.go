@@ -0,0 +1,137 @@
+package lock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileName 锁文件名
+const FileName = "build.lock"
+
+// LockFile 构建描述文件，锁定一次构建的全部输入
+type LockFile struct {
+	Sources map[string]string `json:"sources"` // 源文件相对路径 -> SHA-256
+	Configs map[string]string `json:"configs"` // 配置文件相对路径 -> SHA-256
+	Tools   map[string]string `json:"tools"`   // 工具名 -> 版本
+}
+
+// NewLockFile 创建空的锁文件
+func NewLockFile() *LockFile {
+	return &LockFile{
+		Sources: make(map[string]string),
+		Configs: make(map[string]string),
+		Tools:   make(map[string]string),
+	}
+}
+
+// Load 加载锁文件
+func Load(path string) (*LockFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lockFile := NewLockFile()
+	if err := json.Unmarshal(content, lockFile); err != nil {
+		return nil, err
+	}
+	return lockFile, nil
+}
+
+// Save 保存锁文件
+func (l *LockFile) Save(path string) error {
+	content, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// Diff 对比两个锁文件，返回所有差异描述
+func (l *LockFile) Diff(current *LockFile) []string {
+	diffs := make([]string, 0)
+	diffs = append(diffs, diffMap("源文件", l.Sources, current.Sources)...)
+	diffs = append(diffs, diffMap("配置文件", l.Configs, current.Configs)...)
+	diffs = append(diffs, diffMap("工具", l.Tools, current.Tools)...)
+	return diffs
+}
+
+// diffMap 对比两个映射
+func diffMap(kind string, locked, current map[string]string) []string {
+	diffs := make([]string, 0)
+	for key, lockedVal := range locked {
+		currentVal, exists := current[key]
+		if !exists {
+			diffs = append(diffs, fmt.Sprintf("%s %s 已删除", kind, key))
+		} else if currentVal != lockedVal {
+			diffs = append(diffs, fmt.Sprintf("%s %s 已变化: %s -> %s", kind, key, lockedVal, currentVal))
+		}
+	}
+	for key := range current {
+		if _, exists := locked[key]; !exists {
+			diffs = append(diffs, fmt.Sprintf("%s %s 为新增", kind, key))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// HashFiles 计算目录下所有满足条件的文件哈希，键为使用/分隔的相对路径
+func HashFiles(root string, match func(path string) bool) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !match(path) {
+			return nil
+		}
+
+		hash, err := HashFile(path)
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		hashes[filepath.ToSlash(relPath)] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// HashFile 计算单个文件的SHA-256
+func HashFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ToolVersion 获取外部工具版本，工具不存在时返回空字符串
+func ToolVersion(name string, args ...string) string {
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+
+	output, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
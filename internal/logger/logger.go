@@ -0,0 +1,244 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level 日志级别
+type Level int
+
+// 日志级别，从低到高
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// levelNames 日志级别名称
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String 日志级别名称
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel 解析日志级别名称
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("不支持的日志级别: %s", name)
+}
+
+// 日志格式
+const (
+	FormatText = "text" // 文本格式，警告和错误带有 [WARN]/[ERROR] 前缀
+	FormatJSON = "json" // 每行一个 JSON 对象，便于 CI 采集和过滤
+)
+
+// Fields 附加到日志的结构化字段，只在 JSON 格式中输出
+type Fields map[string]interface{}
+
+// Logger 日志记录器
+type Logger struct {
+	mu     sync.Mutex
+	out    io.Writer // 为空时写入当前的标准输出
+	sinks  []*sink   // 额外的输出目标，如构建服务中单个任务的日志
+	level  Level
+	format string
+}
+
+// sink 额外的输出目标，以指针区分，同一个 io.Writer 可以添加多次
+type sink struct {
+	out io.Writer
+}
+
+// New 创建日志记录器，out 为空时写入当前的标准输出
+func New(out io.Writer) *Logger {
+	return &Logger{out: out, level: LevelInfo, format: FormatText}
+}
+
+// SetLevel 设置最低输出级别
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetFormat 设置日志格式：text 或 json
+func (l *Logger) SetFormat(format string) error {
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("不支持的日志格式: %s", format)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.format = format
+	return nil
+}
+
+// SetOutput 设置输出目标，为空时写入当前的标准输出
+func (l *Logger) SetOutput(out io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out = out
+}
+
+// AddSink 添加额外的输出目标，之后的每条日志同时写入输出和所有额外目标，返回移除该目标的函数
+func (l *Logger) AddSink(out io.Writer) (remove func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	added := &sink{out: out}
+	l.sinks = append(l.sinks, added)
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, item := range l.sinks {
+			if item == added {
+				l.sinks = append(l.sinks[:i:i], l.sinks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Enabled 指定级别的日志是否会输出
+func (l *Logger) Enabled(level Level) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return level >= l.level
+}
+
+// IsJSON 是否为 JSON 格式
+func (l *Logger) IsJSON() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.format == FormatJSON
+}
+
+// Log 输出一条日志
+func (l *Logger) Log(level Level, fields Fields, msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level < l.level {
+		return
+	}
+
+	// 每次写入时获取标准输出，兼容临时替换 os.Stdout 捕获输出的调用方
+	out := l.out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	line := l.formatLocked(level, fields, msg)
+	io.WriteString(out, line)
+	for _, item := range l.sinks {
+		io.WriteString(item.out, line)
+	}
+}
+
+// formatLocked 按日志格式生成一行日志，调用方需持有锁
+func (l *Logger) formatLocked(level Level, fields Fields, msg string) string {
+	if l.format == FormatJSON {
+		entry := make(map[string]interface{}, len(fields)+3)
+		for key, value := range fields {
+			entry[key] = value
+		}
+		entry["time"] = time.Now().Format(time.RFC3339)
+		entry["level"] = level.String()
+		entry["msg"] = msg
+		content, err := json.Marshal(entry)
+		if err != nil {
+			content, _ = json.Marshal(map[string]string{"level": level.String(), "msg": msg})
+		}
+		return fmt.Sprintf("%s\n", content)
+	}
+
+	switch level {
+	case LevelDebug:
+		return fmt.Sprintf("[DEBUG] %s\n", msg)
+	case LevelWarn:
+		return fmt.Sprintf("[WARN] %s\n", msg)
+	case LevelError:
+		return fmt.Sprintf("[ERROR] %s\n", msg)
+	default:
+		return fmt.Sprintf("%s\n", msg)
+	}
+}
+
+// Entry 带有结构化字段的日志
+type Entry struct {
+	logger *Logger
+	fields Fields
+}
+
+// WithFields 创建带有结构化字段的日志
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return &Entry{logger: l, fields: fields}
+}
+
+// Debugf 输出调试日志
+func (e *Entry) Debugf(format string, args ...interface{}) {
+	e.logger.Log(LevelDebug, e.fields, fmt.Sprintf(format, args...))
+}
+
+// Infof 输出信息日志
+func (e *Entry) Infof(format string, args ...interface{}) {
+	e.logger.Log(LevelInfo, e.fields, fmt.Sprintf(format, args...))
+}
+
+// Warnf 输出警告日志
+func (e *Entry) Warnf(format string, args ...interface{}) {
+	e.logger.Log(LevelWarn, e.fields, fmt.Sprintf(format, args...))
+}
+
+// Errorf 输出错误日志
+func (e *Entry) Errorf(format string, args ...interface{}) {
+	e.logger.Log(LevelError, e.fields, fmt.Sprintf(format, args...))
+}
+
+// std 默认日志记录器，写入标准输出
+var std = New(nil)
+
+// Default 获取默认日志记录器
+func Default() *Logger {
+	return std
+}
+
+// WithFields 使用默认日志记录器创建带有结构化字段的日志
+func WithFields(fields Fields) *Entry {
+	return std.WithFields(fields)
+}
+
+// Debugf 使用默认日志记录器输出调试日志
+func Debugf(format string, args ...interface{}) {
+	std.Log(LevelDebug, nil, fmt.Sprintf(format, args...))
+}
+
+// Infof 使用默认日志记录器输出信息日志
+func Infof(format string, args ...interface{}) {
+	std.Log(LevelInfo, nil, fmt.Sprintf(format, args...))
+}
+
+// Warnf 使用默认日志记录器输出警告日志
+func Warnf(format string, args ...interface{}) {
+	std.Log(LevelWarn, nil, fmt.Sprintf(format, args...))
+}
+
+// Errorf 使用默认日志记录器输出错误日志
+func Errorf(format string, args ...interface{}) {
+	std.Log(LevelError, nil, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/game-data-builder/internal/report"
+)
+
+// 耗时统计的对象类别
+const (
+	KindFile      = "file"      // 源文件读取
+	KindSheet     = "sheet"     // 单张表在所有格式下的转换
+	KindConverter = "converter" // 单个转换器处理所有表
+)
+
+// Timing 一项耗时
+type Timing struct {
+	Name     string        // 名称
+	Duration time.Duration // 耗时
+}
+
+// Recorder 记录构建各阶段以及每个文件、表和转换器的耗时，可并发使用
+type Recorder struct {
+	mu           sync.Mutex
+	stages       []Timing
+	current      string
+	currentStart time.Time
+	totals       map[string]map[string]time.Duration // 类别 -> 名称 -> 累计耗时
+}
+
+// NewRecorder 创建耗时记录器
+func NewRecorder() *Recorder {
+	return &Recorder{totals: make(map[string]map[string]time.Duration)}
+}
+
+// StartStage 开始一个阶段，同时结束上一个阶段
+func (r *Recorder) StartStage(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endStageLocked()
+	r.current = name
+	r.currentStart = time.Now()
+}
+
+// EndStage 结束当前阶段
+func (r *Recorder) EndStage() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endStageLocked()
+}
+
+// endStageLocked 结束当前阶段，调用方需持有锁
+func (r *Recorder) endStageLocked() {
+	if r.current == "" {
+		return
+	}
+	r.stages = append(r.stages, Timing{Name: r.current, Duration: time.Since(r.currentStart)})
+	r.current = ""
+}
+
+// Add 累加某个文件、表或转换器的耗时
+func (r *Recorder) Add(kind, name string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.totals[kind] == nil {
+		r.totals[kind] = make(map[string]time.Duration)
+	}
+	r.totals[kind][name] += duration
+}
+
+// Stages 按执行顺序返回各阶段耗时
+func (r *Recorder) Stages() []Timing {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Timing(nil), r.stages...)
+}
+
+// Top 返回某类别中耗时最长的 n 项，n 不大于 0 时返回全部
+func (r *Recorder) Top(kind string, n int) []Timing {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timings := make([]Timing, 0, len(r.totals[kind]))
+	for name, duration := range r.totals[kind] {
+		timings = append(timings, Timing{Name: name, Duration: duration})
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].Duration != timings[j].Duration {
+			return timings[i].Duration > timings[j].Duration
+		}
+		return timings[i].Name < timings[j].Name
+	})
+	if n > 0 && len(timings) > n {
+		timings = timings[:n]
+	}
+	return timings
+}
+
+// AddToReport 把各阶段耗时和耗时最长的文件、表、转换器写入构建报告
+func (r *Recorder) AddToReport(rep *report.Report, top int) {
+	stages := r.Stages()
+	var total time.Duration
+	for _, stage := range stages {
+		total += stage.Duration
+	}
+
+	section := rep.Section("阶段耗时")
+	for _, stage := range stages {
+		percent := 0.0
+		if total > 0 {
+			percent = float64(stage.Duration) * 100 / float64(total)
+		}
+		section.Addf("%10v %5.1f%%  %s", stage.Duration.Round(time.Microsecond), percent, stage.Name)
+	}
+
+	titles := []struct{ kind, title string }{
+		{KindFile, "读取耗时最长的文件"},
+		{KindSheet, "转换耗时最长的表"},
+		{KindConverter, "转换器耗时"},
+	}
+	for _, item := range titles {
+		section := rep.Section(item.title)
+		for _, timing := range r.Top(item.kind, top) {
+			section.Addf("%10v  %s", timing.Duration.Round(time.Microsecond), timing.Name)
+		}
+	}
+}
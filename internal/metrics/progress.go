@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressWidth 进度条宽度
+const progressWidth = 30
+
+// progressInterval 两次刷新之间的最小间隔
+const progressInterval = 100 * time.Millisecond
+
+// Progress 单个阶段的进度条，out 为空时不显示，可并发使用
+type Progress struct {
+	mu       sync.Mutex
+	out      io.Writer
+	label    string
+	total    int
+	done     int
+	lastDraw time.Time
+}
+
+// NewProgress 创建进度条，out 为空时所有操作都不输出
+func NewProgress(out io.Writer, label string, total int) *Progress {
+	p := &Progress{out: out, label: label, total: total}
+	p.draw(true)
+	return p
+}
+
+// Add 完成 n 项并刷新进度条
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	p.draw(p.done >= p.total)
+}
+
+// Finish 显示最终进度并换行
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.out == nil {
+		return
+	}
+	p.draw(true)
+	fmt.Fprintln(p.out)
+}
+
+// draw 刷新进度条，调用方需持有锁（构造时除外）；未到刷新间隔时跳过，force 为 true 时总是刷新
+func (p *Progress) draw(force bool) {
+	if p.out == nil {
+		return
+	}
+	if !force && time.Since(p.lastDraw) < progressInterval {
+		return
+	}
+	p.lastDraw = time.Now()
+
+	filled := progressWidth
+	if p.total > 0 {
+		filled = p.done * progressWidth / p.total
+	}
+	if filled > progressWidth {
+		filled = progressWidth
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+	fmt.Fprintf(p.out, "\r\033[K%s [%s] %d/%d", p.label, bar, p.done, p.total)
+}
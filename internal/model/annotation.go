@@ -0,0 +1,103 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CellComment 源文件中数据单元格的批注
+type CellComment struct {
+	Row    int    // 源文件中的行号，与 DataSheet.RowNumber 对应
+	Column string // 列名
+	Text   string // 批注内容
+}
+
+// ConditionalFormat 源文件中作用于数据列的条件格式，只保留按数值比较单元格的规则（如负数标红）
+type ConditionalFormat struct {
+	Column   string  // 列名
+	Criteria string  // 比较方式，与 Excel 一致：less than、between 等
+	Value    float64 // 比较的数值，between 和 not between 时为下限
+	MaxValue float64 // between 和 not between 时的上限
+	Color    string  // 命中时的字体颜色，如 #9C0006，为空表示不改变
+	Fill     string  // 命中时的背景颜色，为空表示不改变
+}
+
+// Match 值是否满足条件，非数值不满足
+func (f ConditionalFormat) Match(value interface{}) bool {
+	var n float64
+	switch v := value.(type) {
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	case float32:
+		n = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return false
+		}
+		n = parsed
+	default:
+		return false
+	}
+
+	switch f.Criteria {
+	case "less than":
+		return n < f.Value
+	case "less than or equal to":
+		return n <= f.Value
+	case "greater than":
+		return n > f.Value
+	case "greater than or equal to":
+		return n >= f.Value
+	case "equal to":
+		return n == f.Value
+	case "not equal to":
+		return n != f.Value
+	case "between":
+		return n >= f.Value && n <= f.MaxValue
+	case "not between":
+		return n < f.Value || n > f.MaxValue
+	}
+	return false
+}
+
+// CSS 满足条件时的样式，如 color:#9C0006;background-color:#FFC7CE
+func (f ConditionalFormat) CSS() string {
+	styles := make([]string, 0, 2)
+	if f.Color != "" {
+		styles = append(styles, fmt.Sprintf("color:%s", f.Color))
+	}
+	if f.Fill != "" {
+		styles = append(styles, fmt.Sprintf("background-color:%s", f.Fill))
+	}
+	return strings.Join(styles, ";")
+}
+
+// CellComment 获取数据单元格在源文件中的批注，没有批注时返回空字符串
+func (s *DataSheet) CellComment(rowIndex int, column string) string {
+	if len(s.Comments) == 0 {
+		return ""
+	}
+	row := s.RowNumber(rowIndex)
+	for _, comment := range s.Comments {
+		if comment.Row == row && comment.Column == column {
+			return comment.Text
+		}
+	}
+	return ""
+}
+
+// CellStyle 按源文件中该列的条件格式获取值的样式，多条规则满足时使用第一条，都不满足时返回空字符串
+func (s *DataSheet) CellStyle(column string, value interface{}) string {
+	for _, format := range s.ConditionalFormats {
+		if format.Column == column && format.Match(value) {
+			return format.CSS()
+		}
+	}
+	return ""
+}
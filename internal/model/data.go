@@ -1,11 +1,57 @@
 package model
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
 // DataSheet 表示一个数据表
 type DataSheet struct {
-	Name    string                   // 表名
-	Columns []ColumnInfo             // 列信息
-	Rows    []map[string]interface{} // 行数据
-	Meta    map[string]interface{}   // 元数据
+	Name         string                   // 表名
+	Columns      []ColumnInfo             // 列信息
+	Rows         []map[string]interface{} // 行数据
+	Meta         map[string]interface{}   // 元数据
+	DataStartRow int                      // 数据起始行号（从1开始，0表示默认的第4行）
+	KeyColumn    string                   // 主键列名，为空时使用第一列
+	Tags         []string                 // 表标签，来自表元数据 tags 和 sheets.json
+	RowNumbers   []int                    // 每行在源文件中的行号，去除过行时记录，为空时按 DataStartRow 连续计算
+
+	Comments           []CellComment       // 源文件中数据单元格的批注，目前只有 Excel 读取器读取
+	ConditionalFormats []ConditionalFormat // 源文件中数据列的条件格式，目前只有 Excel 读取器读取
+}
+
+// PrimaryKey 获取主键列名，未指定时使用第一列
+func (s *DataSheet) PrimaryKey() string {
+	if s.KeyColumn != "" {
+		return s.KeyColumn
+	}
+	if len(s.Columns) > 0 {
+		return s.Columns[0].Name
+	}
+	return ""
+}
+
+// HasColumn 检查表中是否有指定的列
+func (s *DataSheet) HasColumn(name string) bool {
+	for _, col := range s.Columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RowNumber 获取数据行在源文件中的行号
+func (s *DataSheet) RowNumber(rowIndex int) int {
+	if rowIndex < len(s.RowNumbers) {
+		return s.RowNumbers[rowIndex]
+	}
+	if s.DataStartRow > 0 {
+		return s.DataStartRow + rowIndex
+	}
+	return rowIndex + 4 // 默认数据行从第4行开始
 }
 
 // ColumnInfo 表示列信息
@@ -17,6 +63,11 @@ type ColumnInfo struct {
 	Default  interface{} // 默认值
 	Options  []string    // 可选值（枚举）
 	Ref      *RefInfo    // 引用信息
+	Tags     []string    // 列标签
+	IsKey    bool        // 是否主键
+
+	Transforms []string `json:"Transforms,omitempty"` // 类型行中声明的列转换，如 *1000、trim
+	Asset      string   `json:"Asset,omitempty"`      // 资源类型，来自注释中的 资源:prefab，列中的值为该类型资源的路径
 }
 
 // RefInfo 表示引用关系
@@ -30,12 +81,26 @@ type ConvertResult struct {
 	FileName string // 输出文件名
 	Content  []byte // 转换后的内容
 	Format   string // 格式类型
+	Sheet    string // 来源表名，汇总文件为空
 }
 
 // ErrorInfo 表示错误信息
 type ErrorInfo struct {
-	Sheet  string // 表名
-	Row    int    // 行号
-	Column string // 列名
-	Msg    string // 错误消息
+	Sheet  string `json:"sheet"`  // 表名
+	Row    int    `json:"row"`    // 行号
+	Column string `json:"column"` // 列名
+	Msg    string `json:"msg"`    // 错误消息
+}
+
+// ContentHash 计算表内容（列定义与行数据）的SHA-256，用于检测内容变化
+func (s *DataSheet) ContentHash() (string, error) {
+	content, err := json.Marshal(struct {
+		Columns []ColumnInfo
+		Rows    []map[string]interface{}
+	}{s.Columns, s.Rows})
+	if err != nil {
+		return "", fmt.Errorf("计算表 %s 的哈希失败: %v", s.Name, err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
 }
@@ -0,0 +1,23 @@
+package model
+
+import "strings"
+
+// EnumSheetName 枚举定义表名
+const EnumSheetName = "@enums"
+
+// EnumTypePrefix 枚举列类型前缀，如 enum:Quality
+const EnumTypePrefix = "enum:"
+
+// EnumDef 表示一个枚举定义
+type EnumDef struct {
+	Name    string         // 枚举名
+	Members map[string]int // 成员名 -> 值
+}
+
+// EnumName 解析枚举列类型，返回枚举名
+func EnumName(colType string) (string, bool) {
+	if !strings.HasPrefix(colType, EnumTypePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(colType, EnumTypePrefix), true
+}
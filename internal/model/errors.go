@@ -0,0 +1,144 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 错误类别，可通过 errors.Is 判断错误属于哪一类
+var (
+	ErrRead       = errors.New("读取失败")
+	ErrValidation = errors.New("验证失败")
+	ErrConvert    = errors.New("转换失败")
+	ErrOutput     = errors.New("输出失败")
+)
+
+// ReadError 读取源文件时的错误
+type ReadError struct {
+	File  string // 源文件路径
+	Sheet string // 表名，无法确定时为空
+	Err   error  // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *ReadError) Error() string {
+	if e.Sheet != "" {
+		return fmt.Sprintf("读取 %s[%s] 失败: %v", e.File, e.Sheet, e.Err)
+	}
+	return fmt.Sprintf("读取 %s 失败: %v", e.File, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// Is 判断是否属于读取错误
+func (e *ReadError) Is(target error) bool {
+	return target == ErrRead
+}
+
+// ValidationError 数据验证错误，包含所有单元格级别的错误
+type ValidationError struct {
+	Errors []*ErrorInfo // 验证错误列表
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("数据验证失败，共 %d 个错误", len(e.Errors))
+}
+
+// Is 判断是否属于验证错误
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ConvertError 转换数据时的错误
+type ConvertError struct {
+	Sheet  string // 表名，汇总文件为空
+	Format string // 格式类型
+	Err    error  // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *ConvertError) Error() string {
+	if e.Sheet != "" {
+		return fmt.Sprintf("将 %s 转换为 %s 失败: %v", e.Sheet, e.Format, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Format, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// Is 判断是否属于转换错误
+func (e *ConvertError) Is(target error) bool {
+	return target == ErrConvert
+}
+
+// ConvertErrors 多个表的转换错误，转换遇到错误时继续处理其余的表，最后一起报告
+type ConvertErrors struct {
+	Errors []*ConvertError // 每个失败的表（或汇总文件）的错误
+}
+
+// Add 记录一个转换错误，err 不是 ConvertError 时以 sheet 和 format 包装
+func (e *ConvertErrors) Add(sheet, format string, err error) {
+	var convertErr *ConvertError
+	if !errors.As(err, &convertErr) {
+		convertErr = &ConvertError{Sheet: sheet, Format: format, Err: err}
+	}
+	e.Errors = append(e.Errors, convertErr)
+}
+
+// Err 没有错误时返回 nil，否则返回自身
+func (e *ConvertErrors) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error 实现 error 接口
+func (e *ConvertErrors) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("数据转换失败，共 %d 个错误", len(e.Errors))
+}
+
+// Unwrap 返回每个表的转换错误
+func (e *ConvertErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Is 判断是否属于转换错误
+func (e *ConvertErrors) Is(target error) bool {
+	return target == ErrConvert
+}
+
+// OutputError 写入输出文件时的错误
+type OutputError struct {
+	Path string // 输出目录或文件路径
+	Err  error  // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *OutputError) Error() string {
+	return fmt.Sprintf("写入 %s 失败: %v", e.Path, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *OutputError) Unwrap() error {
+	return e.Err
+}
+
+// Is 判断是否属于输出错误
+func (e *OutputError) Is(target error) bool {
+	return target == ErrOutput
+}
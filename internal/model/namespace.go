@@ -0,0 +1,68 @@
+package model
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// NamespaceSeparator 命名空间与表名之间的分隔符，如 shop.item
+const NamespaceSeparator = "."
+
+// namespacePattern 命名空间只允许字母、数字和下划线，且不能以数字开头
+var namespacePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidNamespace 检查命名空间是否合法
+func ValidNamespace(namespace string) bool {
+	return namespacePattern.MatchString(namespace)
+}
+
+// QualifyName 为表名加上命名空间前缀，命名空间为空时原样返回
+func QualifyName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + NamespaceSeparator + name
+}
+
+// SplitName 拆分带命名空间的表名，没有命名空间时 namespace 为空
+func SplitName(name string) (namespace, local string) {
+	index := strings.LastIndex(name, NamespaceSeparator)
+	if index < 0 {
+		return "", name
+	}
+	return name[:index], name[index+1:]
+}
+
+// SheetPath 表对应的输出路径（不含扩展名），命名空间作为子目录，如 shop.item -> shop/item
+func SheetPath(name string) string {
+	return filepath.FromSlash(strings.ReplaceAll(name, NamespaceSeparator, "/"))
+}
+
+// SheetIdent 表名在生成代码中使用的标识符，如 shop.item -> shop_item
+func SheetIdent(name string) string {
+	return strings.ReplaceAll(name, NamespaceSeparator, "_")
+}
+
+// ApplyNamespace 为同一来源的表加上命名空间前缀；
+// 引用同一来源中的表时解析为带前缀的表名，其他引用保持不变（引用其他命名空间的表需写完整表名）
+func ApplyNamespace(sheets []*DataSheet, namespace string) {
+	if namespace == "" {
+		return
+	}
+
+	local := make(map[string]bool, len(sheets))
+	for _, sheet := range sheets {
+		local[sheet.Name] = true
+	}
+
+	for _, sheet := range sheets {
+		sheet.Name = QualifyName(namespace, sheet.Name)
+		for i := range sheet.Columns {
+			ref := sheet.Columns[i].Ref
+			if ref != nil && local[ref.Sheet] {
+				sheet.Columns[i].Ref = &RefInfo{Sheet: QualifyName(namespace, ref.Sheet), Column: ref.Column}
+			}
+		}
+	}
+}
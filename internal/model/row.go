@@ -0,0 +1,42 @@
+package model
+
+import "strings"
+
+// RowValue 获取行中指定列的值，支持 reward.itemId 形式的嵌套列
+func RowValue(row map[string]interface{}, name string) (interface{}, bool) {
+	if val, exists := row[name]; exists {
+		return val, true
+	}
+
+	parts := strings.Split(name, ".")
+	if len(parts) == 1 {
+		return nil, false
+	}
+
+	current := row
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+
+	val, exists := current[parts[len(parts)-1]]
+	return val, exists
+}
+
+// SetRowValue 设置行中指定列的值，嵌套列会按路径创建子对象
+func SetRowValue(row map[string]interface{}, name string, value interface{}) {
+	parts := strings.Split(name, ".")
+	current := row
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
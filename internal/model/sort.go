@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey 行排序键
+type SortKey struct {
+	Column string // 列名
+	Desc   bool   // 是否降序
+}
+
+// ParseSortKeys 解析排序键，列名前加 - 表示降序，如 ["-price", "id"]
+func ParseSortKeys(specs []string) []SortKey {
+	keys := make([]SortKey, 0, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if strings.HasPrefix(spec, "-") {
+			keys = append(keys, SortKey{Column: strings.TrimPrefix(spec, "-"), Desc: true})
+		} else {
+			keys = append(keys, SortKey{Column: strings.TrimPrefix(spec, "+")})
+		}
+	}
+	return keys
+}
+
+// SortRows 按排序键对行进行稳定排序
+func SortRows(rows []map[string]interface{}, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rowLess(rows[i], rows[j], keys)
+	})
+}
+
+// SortSheet 按排序键对表的行进行稳定排序，行号随行一起移动，错误信息仍指向源文件中的位置
+func SortSheet(sheet *DataSheet, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+
+	indexes := make([]int, len(sheet.Rows))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return rowLess(sheet.Rows[indexes[i]], sheet.Rows[indexes[j]], keys)
+	})
+
+	rows := make([]map[string]interface{}, len(indexes))
+	rowNumbers := make([]int, len(indexes))
+	for i, index := range indexes {
+		rows[i] = sheet.Rows[index]
+		rowNumbers[i] = sheet.RowNumber(index)
+	}
+	sheet.Rows = rows
+	sheet.RowNumbers = rowNumbers
+}
+
+// rowLess 按排序键比较两行，left 是否应排在 right 之前
+func rowLess(left, right map[string]interface{}, keys []SortKey) bool {
+	for _, key := range keys {
+		leftVal, _ := RowValue(left, key.Column)
+		rightVal, _ := RowValue(right, key.Column)
+		cmp := CompareValues(leftVal, rightVal)
+		if cmp == 0 {
+			continue
+		}
+		if key.Desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+	return false
+}
+
+// CompareValues 比较两个单元格值：空值最小，数字按数值比较，其余按字符串比较
+func CompareValues(left, right interface{}) int {
+	if left == nil || right == nil {
+		switch {
+		case left == nil && right == nil:
+			return 0
+		case left == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	leftNum, leftOk := toNumber(left)
+	rightNum, rightOk := toNumber(right)
+	if leftOk && rightOk {
+		switch {
+		case leftNum < rightNum:
+			return -1
+		case leftNum > rightNum:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return strings.Compare(fmt.Sprintf("%v", left), fmt.Sprintf("%v", right))
+}
+
+// toNumber 将数值转换为float64
+func toNumber(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// maxListedItems 文本消息中最多列出的文件或错误数
+const maxListedItems = 20
+
+// 发送时机
+const (
+	WhenAlways  = "always"  // 每次构建后发送（默认）
+	WhenChange  = "change"  // 构建成功且有文件变化时发送
+	WhenFailure = "failure" // 构建失败时发送
+)
+
+// Summary 构建摘要
+type Summary struct {
+	Success      bool               `json:"success"`            // 是否构建成功
+	DurationMs   int64              `json:"durationMs"`         // 构建耗时（毫秒）
+	ChangedFiles []string           `json:"changedFiles"`       // 内容有变化的输出文件
+	Errors       []*model.ErrorInfo `json:"errors,omitempty"`   // 数据验证错误
+	Message      string             `json:"message"`            // 失败原因或结果说明
+	Degraded     []string           `json:"degraded,omitempty"` // 不可用的可选功能，为空表示完整构建
+}
+
+// NewSummary 根据构建结果创建摘要
+func NewSummary(err error, duration time.Duration, changedFiles []string) *Summary {
+	summary := &Summary{
+		Success:      err == nil,
+		DurationMs:   duration.Milliseconds(),
+		ChangedFiles: changedFiles,
+	}
+	if summary.ChangedFiles == nil {
+		summary.ChangedFiles = make([]string, 0)
+	}
+
+	if err != nil {
+		summary.Message = err.Error()
+		var validation *model.ValidationError
+		if errors.As(err, &validation) {
+			summary.Errors = validation.Errors
+		}
+	} else {
+		summary.Message = fmt.Sprintf("构建成功，%d 个文件有变化", len(summary.ChangedFiles))
+	}
+	return summary
+}
+
+// Text 摘要的文本形式，用于聊天机器人消息
+func (s *Summary) Text() string {
+	var builder strings.Builder
+	if s.Success {
+		builder.WriteString(fmt.Sprintf("[数据构建] %s（耗时 %dms）", s.Message, s.DurationMs))
+		for i, file := range s.ChangedFiles {
+			if i == maxListedItems {
+				builder.WriteString(fmt.Sprintf("\n... 共 %d 个文件", len(s.ChangedFiles)))
+				break
+			}
+			builder.WriteString("\n- " + file)
+		}
+		for _, item := range s.Degraded {
+			builder.WriteString("\n降级: " + item)
+		}
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf("[数据构建] 构建失败: %s", s.Message))
+	for i, err := range s.Errors {
+		if i == maxListedItems {
+			builder.WriteString(fmt.Sprintf("\n... 共 %d 个错误", len(s.Errors)))
+			break
+		}
+		builder.WriteString(fmt.Sprintf("\n- %s:%s[%d]: %s", err.Sheet, err.Column, err.Row, err.Msg))
+	}
+	return builder.String()
+}
+
+// shouldSend 判断是否需要向该地址发送
+func shouldSend(webhook config.WebhookConfig, summary *Summary) bool {
+	switch webhook.When {
+	case WhenChange:
+		return summary.Success && len(summary.ChangedFiles) > 0
+	case WhenFailure:
+		return !summary.Success
+	default:
+		return true
+	}
+}
+
+// payload 按 Webhook 格式构造请求体
+func payload(format string, summary *Summary) (interface{}, error) {
+	switch format {
+	case "", "json":
+		return summary, nil
+	case "slack":
+		return map[string]interface{}{"text": summary.Text()}, nil
+	case "feishu":
+		return map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": summary.Text()},
+		}, nil
+	case "dingtalk":
+		return map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]string{"content": summary.Text()},
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的通知格式: %s", format)
+	}
+}
+
+// Send 向所有符合发送时机的 Webhook 发送构建摘要，返回发送失败的错误
+func Send(webhooks []config.WebhookConfig, summary *Summary) []error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	errs := make([]error, 0)
+
+	for _, webhook := range webhooks {
+		if !shouldSend(webhook, summary) {
+			continue
+		}
+
+		body, err := payload(webhook.Format, summary)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		content, err := json.Marshal(body)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		resp, err := client.Post(webhook.URL, "application/json; charset=utf-8", bytes.NewReader(content))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("通知 %s 失败: %v", webhook.URL, err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			errs = append(errs, fmt.Errorf("通知 %s 失败: HTTP %d", webhook.URL, resp.StatusCode))
+		}
+	}
+	return errs
+}
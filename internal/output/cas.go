@@ -0,0 +1,274 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 内容寻址存储的目录结构
+const (
+	BlobsDir    = "blobs"    // 按内容哈希命名的文件
+	VersionsDir = "versions" // 每个版本一个目录，包含版本文件和指向内容文件的符号链接
+	LatestLink  = "latest"   // 指向当前版本目录的符号链接
+)
+
+// CASStore 内容寻址输出存储：文件内容只保存一份，每个版本只记录路径到哈希的映射，切换 latest 即可回滚
+type CASStore struct {
+	root string
+	opts WriteOptions
+}
+
+// NewCASStore 创建内容寻址输出存储
+func NewCASStore(root string) *CASStore {
+	return &CASStore{root: root}
+}
+
+// NewCASStoreWithOptions 按写入选项创建内容寻址输出存储
+func NewCASStoreWithOptions(root string, opts WriteOptions) *CASStore {
+	return &CASStore{root: root, opts: opts}
+}
+
+// blobPath 内容哈希对应的文件路径（相对于存储根目录）
+func blobPath(hash string) string {
+	return filepath.Join(BlobsDir, hash[:2], hash)
+}
+
+// WriteBlob 写入内容文件，内容已存在时直接返回其哈希
+func (s *CASStore) WriteBlob(content []byte) (string, error) {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	path := filepath.Join(s.root, blobPath(hash))
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("创建内容目录失败: %v", err)
+	}
+
+	// 先写临时文件再重命名，避免中断时留下不完整的内容文件
+	if err := WriteFileAtomic(path, content, s.opts.Fsync); err != nil {
+		return "", fmt.Errorf("写入内容文件失败: %v", err)
+	}
+	return hash, nil
+}
+
+// WriteBlobs 并发写入多个内容文件，每写完一个调用一次 done
+func (s *CASStore) WriteBlobs(contents [][]byte, done func()) error {
+	return forEach(len(contents), s.opts.workers(), func(i int) error {
+		if _, err := s.WriteBlob(contents[i]); err != nil {
+			return err
+		}
+		if done != nil {
+			done()
+		}
+		return nil
+	})
+}
+
+// NewVersionID 根据构建时间生成版本号，同一秒内多次构建时追加序号
+func (s *CASStore) NewVersionID(buildTime time.Time) string {
+	base := buildTime.UTC().Format("20060102-150405")
+	id := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(s.root, VersionsDir, id)); os.IsNotExist(err) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// Publish 写入一个版本：生成版本文件和指向内容文件的符号链接，然后把 latest 切换到该版本
+// 版本中引用的内容文件必须已通过 WriteBlob 写入
+func (s *CASStore) Publish(id string, version *VersionFile) error {
+	dir := filepath.Join(s.root, VersionsDir, id)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("版本 %s 已存在", id)
+	}
+
+	if err := os.MkdirAll(filepath.Join(s.root, VersionsDir), 0755); err != nil {
+		return fmt.Errorf("创建版本目录失败: %v", err)
+	}
+
+	// 在临时目录中生成完整版本后再重命名，latest 不会指向不完整的版本
+	temp, err := os.MkdirTemp(s.root, ".version-")
+	if err != nil {
+		return fmt.Errorf("创建版本目录失败: %v", err)
+	}
+	if err := s.linkVersion(temp, version); err != nil {
+		os.RemoveAll(temp)
+		return err
+	}
+	if err := os.Rename(temp, dir); err != nil {
+		os.RemoveAll(temp)
+		return fmt.Errorf("创建版本目录失败: %v", err)
+	}
+	if s.opts.Fsync {
+		if err := syncDir(filepath.Join(s.root, VersionsDir)); err != nil {
+			return fmt.Errorf("同步版本目录失败: %v", err)
+		}
+	}
+
+	return s.SetLatest(id)
+}
+
+// linkVersion 在版本目录中写入版本文件，并为每个文件创建指向内容文件的相对符号链接
+func (s *CASStore) linkVersion(dir string, version *VersionFile) error {
+	content, err := version.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(dir, VersionFileName), content, s.opts.Fsync); err != nil {
+		return fmt.Errorf("写入版本文件失败: %v", err)
+	}
+
+	for _, entry := range version.Files {
+		relPath, err := cleanRelPath(entry.Path)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(s.root, blobPath(entry.SHA256))); err != nil {
+			return fmt.Errorf("文件 %s 的内容不存在: %v", entry.Path, err)
+		}
+
+		link := filepath.Join(dir, relPath)
+		if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+			return fmt.Errorf("创建目录失败: %v", err)
+		}
+		// 链接目标相对于最终的版本目录 versions/<id>/<path>
+		depth := strings.Count(filepath.ToSlash(relPath), "/") + 2
+		target := filepath.Join(strings.Repeat("../", depth), blobPath(entry.SHA256))
+		if err := os.Symlink(target, link); err != nil {
+			return fmt.Errorf("创建符号链接失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// SetLatest 原子地把 latest 切换到指定版本
+func (s *CASStore) SetLatest(id string) error {
+	if _, err := os.Stat(filepath.Join(s.root, VersionsDir, id)); err != nil {
+		return fmt.Errorf("版本 %s 不存在", id)
+	}
+
+	temp := filepath.Join(s.root, "."+LatestLink+".tmp")
+	os.Remove(temp)
+	if err := os.Symlink(filepath.Join(VersionsDir, id), temp); err != nil {
+		return fmt.Errorf("切换版本失败: %v", err)
+	}
+	if err := os.Rename(temp, filepath.Join(s.root, LatestLink)); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("切换版本失败: %v", err)
+	}
+	if s.opts.Fsync {
+		return syncDir(s.root)
+	}
+	return nil
+}
+
+// Latest 当前版本号，尚未发布任何版本时返回空
+func (s *CASStore) Latest() (string, error) {
+	target, err := os.Readlink(filepath.Join(s.root, LatestLink))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("读取当前版本失败: %v", err)
+	}
+	return filepath.Base(target), nil
+}
+
+// Versions 按时间顺序列出所有版本
+func (s *CASStore) Versions() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, VersionsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取版本列表失败: %v", err)
+	}
+
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// LoadVersion 读取指定版本的版本文件，版本号为空时返回空的版本文件
+func (s *CASStore) LoadVersion(id string) (*VersionFile, error) {
+	if id == "" {
+		return &VersionFile{Files: make([]VersionEntry, 0)}, nil
+	}
+	return LoadVersionFile(filepath.Join(s.root, VersionsDir, id))
+}
+
+// Retain 只保留最近的 keep 个版本（当前版本始终保留），并删除不再被任何版本引用的内容文件；返回删除的版本
+func (s *CASStore) Retain(keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+
+	versions, err := s.Versions()
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.Latest()
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]string, 0)
+	if len(versions) > keep {
+		for _, id := range versions[:len(versions)-keep] {
+			if id == latest {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(s.root, VersionsDir, id)); err != nil {
+				return removed, fmt.Errorf("删除版本 %s 失败: %v", id, err)
+			}
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+
+	// 收集仍被引用的内容
+	versions, err = s.Versions()
+	if err != nil {
+		return removed, err
+	}
+	referenced := make(map[string]bool)
+	for _, id := range versions {
+		version, err := s.LoadVersion(id)
+		if err != nil {
+			return removed, err
+		}
+		for _, entry := range version.Files {
+			referenced[entry.SHA256] = true
+		}
+	}
+
+	blobs, err := filepath.Glob(filepath.Join(s.root, BlobsDir, "*", "*"))
+	if err != nil {
+		return removed, err
+	}
+	for _, blob := range blobs {
+		if !referenced[filepath.Base(blob)] {
+			if err := os.Remove(blob); err != nil {
+				return removed, fmt.Errorf("删除内容文件失败: %v", err)
+			}
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DirDiff 将要写入的文件与目录现有内容的差异，路径相对于目录
+type DirDiff struct {
+	Added     []string // 目录中不存在的文件
+	Updated   []string // 内容不同的文件
+	Deleted   []string // 将被删除且目录中存在的文件
+	Unchanged int      // 内容相同的文件数量
+}
+
+// DiffDir 按内容哈希比较将要写入的文件与目录中的现有文件，removed 为将被删除的文件，不修改目录
+func DiffDir(root string, files []File, removed []string) (*DirDiff, error) {
+	diff := &DirDiff{Added: make([]string, 0), Updated: make([]string, 0), Deleted: make([]string, 0)}
+	for _, file := range files {
+		existing, err := os.ReadFile(filepath.Join(root, file.Path))
+		if os.IsNotExist(err) {
+			diff.Added = append(diff.Added, file.Path)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %v", file.Path, err)
+		}
+		oldSum, newSum := sha256.Sum256(existing), sha256.Sum256(file.Content)
+		if bytes.Equal(oldSum[:], newSum[:]) {
+			diff.Unchanged++
+		} else {
+			diff.Updated = append(diff.Updated, file.Path)
+		}
+	}
+	for _, relPath := range removed {
+		if _, err := os.Stat(filepath.Join(root, relPath)); err == nil {
+			diff.Deleted = append(diff.Deleted, relPath)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Updated)
+	sort.Strings(diff.Deleted)
+	return diff, nil
+}
+
+// Empty 是否没有任何变化
+func (d *DirDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Deleted) == 0
+}
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// EncryptMagic 加密文件头标识
+const EncryptMagic = "GDBE"
+
+// EncryptVersion 加密格式版本
+const EncryptVersion byte = 1
+
+// DefaultKeyID 未配置密钥编号时使用的默认值
+const DefaultKeyID = "default"
+
+// EncryptOptions 加密选项
+type EncryptOptions struct {
+	KeyID string // 密钥编号，写入文件头供客户端选择密钥
+	Key   []byte // AES-256 密钥
+}
+
+// parseEncryptOptions 解析转换器选项中的加密配置，未开启加密时返回 nil
+//
+// 密钥优先取 encryptKeyEnv 指定的环境变量，其次取 encryptKey，均为 hex 或 base64 编码的 32 字节
+func parseEncryptOptions(options map[string]interface{}) (*EncryptOptions, error) {
+	if enabled, ok := options["encrypt"].(bool); !ok || !enabled {
+		return nil, nil
+	}
+
+	opts := &EncryptOptions{KeyID: DefaultKeyID}
+	if keyID, ok := options["encryptKeyId"].(string); ok && keyID != "" {
+		opts.KeyID = keyID
+	}
+	if len(opts.KeyID) > 0xFF {
+		return nil, fmt.Errorf("密钥编号过长: %s", opts.KeyID)
+	}
+
+	encoded := ""
+	if envName, ok := options["encryptKeyEnv"].(string); ok && envName != "" {
+		encoded = os.Getenv(envName)
+		if encoded == "" {
+			return nil, fmt.Errorf("环境变量 %s 未设置加密密钥", envName)
+		}
+	} else if key, ok := options["encryptKey"].(string); ok {
+		encoded = key
+	}
+	if encoded == "" {
+		return nil, fmt.Errorf("开启加密时必须配置 encryptKey 或 encryptKeyEnv")
+	}
+
+	key, err := decodeKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+	opts.Key = key
+	return opts, nil
+}
+
+// decodeKey 解码 hex 或 base64 编码的 AES-256 密钥
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := hex.DecodeString(encoded)
+	if err != nil {
+		key, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("加密密钥必须为 hex 或 base64 编码")
+		}
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("加密密钥长度必须为 32 字节，实际为 %d", len(key))
+	}
+	return key, nil
+}
+
+// Encrypt 使用 AES-256-GCM 加密文件内容
+//
+// 格式：magic "GDBE" | version uint8 | keyIdLen uint8 | keyId | nonce[12] | 密文（含 16 字节认证标签）
+// nonce 由从密钥派生的独立 nonce 密钥对文件名和明文做 HMAC-SHA256 得到，相同输入得到相同输出，保证构建结果稳定。
+// 加密是确定性的：同一密钥下文件名和明文都相同的文件密文也相同，能看到密文的人可以判断两个文件内容是否一致
+func Encrypt(opts *EncryptOptions, name string, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, nonceKey(opts.Key))
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write(plaintext)
+	nonce := mac.Sum(nil)[:gcm.NonceSize()]
+
+	var buf bytes.Buffer
+	buf.WriteString(EncryptMagic)
+	buf.WriteByte(EncryptVersion)
+	buf.WriteByte(byte(len(opts.KeyID)))
+	buf.WriteString(opts.KeyID)
+	buf.Write(nonce)
+
+	// 文件头作为附加认证数据，防止篡改密钥编号
+	header := append([]byte(nil), buf.Bytes()...)
+	buf.Write(gcm.Seal(nil, nonce, plaintext, header))
+	return buf.Bytes(), nil
+}
+
+// nonceKey 从加密密钥派生 nonce 使用的 HMAC 密钥，避免同一密钥同时用于 AES 和 HMAC
+func nonceKey(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("GDBE nonce"))
+	return mac.Sum(nil)
+}
+
+// Decrypt 解密 Encrypt 生成的内容，keys 为密钥编号到密钥的映射
+func Decrypt(keys map[string][]byte, data []byte) ([]byte, error) {
+	prefix := len(EncryptMagic) + 2
+	if len(data) < prefix || string(data[:len(EncryptMagic)]) != EncryptMagic {
+		return nil, fmt.Errorf("不是加密文件")
+	}
+	if data[len(EncryptMagic)] != EncryptVersion {
+		return nil, fmt.Errorf("不支持的加密格式版本: %d", data[len(EncryptMagic)])
+	}
+
+	keyIDLen := int(data[len(EncryptMagic)+1])
+	if len(data) < prefix+keyIDLen {
+		return nil, fmt.Errorf("加密文件已损坏")
+	}
+	keyID := string(data[prefix : prefix+keyIDLen])
+	key, exists := keys[keyID]
+	if !exists {
+		return nil, fmt.Errorf("未找到密钥: %s", keyID)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	headerLen := prefix + keyIDLen + gcm.NonceSize()
+	if len(data) < headerLen+gcm.Overhead() {
+		return nil, fmt.Errorf("加密文件已损坏")
+	}
+
+	nonce := data[prefix+keyIDLen : headerLen]
+	return gcm.Open(nil, nonce, data[headerLen:], data[:headerLen])
+}
+
+// newGCM 创建 AES-GCM 实例
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ManifestFileName 输出清单文件名，记录上次构建生成的所有文件
+const ManifestFileName = ".builder-manifest.json"
+
+// Manifest 输出清单
+type Manifest struct {
+	Files        []string `json:"files"`                  // 相对于输出根目录的文件路径
+	FailedSheets []string `json:"failedSheets,omitempty"` // 预览构建中验证失败而未更新输出的表
+}
+
+// LoadManifest 读取输出目录中的清单，文件不存在时返回空清单
+func LoadManifest(root string) (*Manifest, error) {
+	manifest := &Manifest{Files: make([]string, 0)}
+
+	content, err := os.ReadFile(filepath.Join(root, ManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return nil, fmt.Errorf("读取输出清单失败: %v", err)
+	}
+
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, fmt.Errorf("解析输出清单失败: %v", err)
+	}
+	return manifest, nil
+}
+
+// Marshal 序列化清单，文件按路径排序
+func (m *Manifest) Marshal() ([]byte, error) {
+	files := append([]string(nil), m.Files...)
+	sort.Strings(files)
+	return json.MarshalIndent(&Manifest{Files: files, FailedSheets: m.FailedSheets}, "", "  ")
+}
+
+// Stale 返回清单中存在但本次未生成的文件
+func (m *Manifest) Stale(current []string) []string {
+	generated := make(map[string]bool, len(current))
+	for _, path := range current {
+		generated[filepath.Clean(path)] = true
+	}
+
+	stale := make([]string, 0)
+	for _, path := range m.Files {
+		if !generated[filepath.Clean(path)] {
+			stale = append(stale, path)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
@@ -0,0 +1,218 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/klauspost/compress/zstd"
+)
+
+// 压缩方式
+const (
+	CompressNone = ""
+	CompressGzip = "gzip"
+	CompressZstd = "zstd"
+)
+
+// 打包方式
+const (
+	BundleNone = ""
+	BundleZip  = "zip"
+	BundlePak  = "pak"
+)
+
+// PakMagic pak 文件头标识
+const PakMagic = "GDBP"
+
+// PakVersion pak 格式版本
+const PakVersion uint32 = 1
+
+// PackOptions 输出打包选项，来自转换器选项 compress、encrypt 和 bundle
+type PackOptions struct {
+	Compress string          // 单个文件的压缩方式
+	Encrypt  *EncryptOptions // 单个文件的加密选项，为空时不加密
+	Bundle   string          // 将同一格式的所有文件打成一个包
+}
+
+// ParsePackOptions 解析转换器选项中的打包配置
+func ParsePackOptions(options map[string]interface{}) (PackOptions, error) {
+	var opts PackOptions
+	if compress, ok := options["compress"].(string); ok {
+		opts.Compress = compress
+	}
+	if bundle, ok := options["bundle"].(string); ok {
+		opts.Bundle = bundle
+	}
+
+	encrypt, err := parseEncryptOptions(options)
+	if err != nil {
+		return opts, err
+	}
+	opts.Encrypt = encrypt
+
+	switch opts.Compress {
+	case CompressNone, CompressGzip, CompressZstd:
+	default:
+		return opts, fmt.Errorf("不支持的压缩方式: %s", opts.Compress)
+	}
+
+	switch opts.Bundle {
+	case BundleNone, BundleZip, BundlePak:
+	default:
+		return opts, fmt.Errorf("不支持的打包方式: %s", opts.Bundle)
+	}
+
+	return opts, nil
+}
+
+// Pack 对同一格式的转换结果按选项压缩和打包
+func Pack(format string, results []*model.ConvertResult, opts PackOptions) ([]*model.ConvertResult, error) {
+	// 先压缩后加密，加密后的内容无法再压缩
+	packed := make([]*model.ConvertResult, 0, len(results))
+	for _, result := range results {
+		fileName := result.FileName
+		content := result.Content
+		var err error
+
+		switch opts.Compress {
+		case CompressGzip:
+			if content, err = gzipContent(content); err != nil {
+				return nil, fmt.Errorf("压缩 %s 失败: %v", result.FileName, err)
+			}
+			fileName += ".gz"
+		case CompressZstd:
+			if content, err = zstdContent(content); err != nil {
+				return nil, fmt.Errorf("压缩 %s 失败: %v", result.FileName, err)
+			}
+			fileName += ".zst"
+		}
+		if opts.Encrypt != nil {
+			if content, err = Encrypt(opts.Encrypt, fileName, content); err != nil {
+				return nil, fmt.Errorf("加密 %s 失败: %v", result.FileName, err)
+			}
+			fileName += ".enc"
+		}
+
+		packed = append(packed, &model.ConvertResult{
+			FileName: fileName,
+			Content:  content,
+			Format:   result.Format,
+			Sheet:    result.Sheet,
+		})
+	}
+
+	var content []byte
+	var err error
+	switch opts.Bundle {
+	case BundleZip:
+		content, err = zipBundle(packed)
+	case BundlePak:
+		content, err = pakBundle(packed)
+	default:
+		return packed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打包 %s 失败: %v", format, err)
+	}
+
+	return []*model.ConvertResult{{
+		FileName: format + "." + opts.Bundle,
+		Content:  content,
+		Format:   format,
+	}}, nil
+}
+
+// gzipContent 以最高压缩级别 gzip 压缩内容，不写入文件名和时间以保证输出稳定
+func gzipContent(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// zstdContent 以最高压缩级别 zstd 压缩内容，单线程编码以保证输出稳定
+func zstdContent(content []byte) ([]byte, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	defer encoder.Close()
+	return encoder.EncodeAll(content, nil), nil
+}
+
+// zipBundle 将文件打成 zip 包，修改时间固定以保证输出稳定
+func zipBundle(results []*model.ConvertResult) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, result := range results {
+		header := &zip.FileHeader{
+			Name:     result.FileName,
+			Method:   zip.Deflate,
+			Modified: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		// 已压缩或加密的文件不再压缩
+		if strings.HasSuffix(result.FileName, ".gz") || strings.HasSuffix(result.FileName, ".zst") ||
+			strings.HasSuffix(result.FileName, ".enc") {
+			header.Method = zip.Store
+		}
+
+		fileWriter, err := writer.CreateHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fileWriter.Write(result.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pakBundle 将文件打成 pak 包
+//
+// 格式（小端序）：magic[4] | version uint32 | count uint32 |
+// count 个索引项 { nameLen uint16 | name | offset uint64 | size uint64 } | 数据区
+// offset 为文件内容相对于整个包起始位置的偏移
+func pakBundle(results []*model.ConvertResult) ([]byte, error) {
+	headerSize := len(PakMagic) + 4 + 4
+	for _, result := range results {
+		if len(result.FileName) > 0xFFFF {
+			return nil, fmt.Errorf("文件名过长: %s", result.FileName)
+		}
+		headerSize += 2 + len(result.FileName) + 8 + 8
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(PakMagic)
+	binary.Write(&buf, binary.LittleEndian, PakVersion)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(results)))
+
+	offset := uint64(headerSize)
+	for _, result := range results {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(result.FileName)))
+		buf.WriteString(result.FileName)
+		binary.Write(&buf, binary.LittleEndian, offset)
+		binary.Write(&buf, binary.LittleEndian, uint64(len(result.Content)))
+		offset += uint64(len(result.Content))
+	}
+	for _, result := range results {
+		buf.Write(result.Content)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// SheetVersionsFileName 表版本状态文件名，位于配置目录中，不计入锁文件的配置哈希
+const SheetVersionsFileName = "sheetVersions.json"
+
+// SheetVersionMetaKey 表版本写入输出元数据时使用的键
+const SheetVersionMetaKey = "dataVersion"
+
+// SheetVersion 单张表的数据版本
+type SheetVersion struct {
+	Version int    `json:"version"` // 版本号，内容变化时加一
+	Hash    string `json:"hash"`    // 上一次构建时的内容哈希
+}
+
+// SheetVersions 所有表的数据版本，供服务器按表失效缓存
+type SheetVersions struct {
+	Sheets  map[string]*SheetVersion `json:"sheets"`
+	changed bool
+}
+
+// LoadSheetVersions 读取表版本状态文件，文件不存在时返回空状态
+func LoadSheetVersions(path string) (*SheetVersions, error) {
+	versions := &SheetVersions{Sheets: make(map[string]*SheetVersion)}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return versions, nil
+		}
+		return nil, fmt.Errorf("读取表版本失败: %v", err)
+	}
+	if err := json.Unmarshal(content, versions); err != nil {
+		return nil, fmt.Errorf("解析表版本失败: %v", err)
+	}
+	if versions.Sheets == nil {
+		versions.Sheets = make(map[string]*SheetVersion)
+	}
+	return versions, nil
+}
+
+// Bump 根据表的内容哈希更新版本：首次出现的表从 1 开始，内容变化时加一；返回当前版本
+func (v *SheetVersions) Bump(sheet *model.DataSheet) (int, error) {
+	hash, err := sheet.ContentHash()
+	if err != nil {
+		return 0, err
+	}
+
+	current, exists := v.Sheets[sheet.Name]
+	switch {
+	case !exists:
+		current = &SheetVersion{Version: 1, Hash: hash}
+		v.Sheets[sheet.Name] = current
+		v.changed = true
+	case current.Hash != hash:
+		current.Version++
+		current.Hash = hash
+		v.changed = true
+	}
+	return current.Version, nil
+}
+
+// Versions 表名到版本号的映射
+func (v *SheetVersions) Versions() map[string]int {
+	versions := make(map[string]int, len(v.Sheets))
+	for name, version := range v.Sheets {
+		versions[name] = version.Version
+	}
+	return versions
+}
+
+// Changed 本次构建是否有表的版本发生变化
+func (v *SheetVersions) Changed() bool {
+	return v.changed
+}
+
+// Save 保存表版本状态文件
+func (v *SheetVersions) Save(path string) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
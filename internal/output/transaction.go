@@ -0,0 +1,257 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/game-data-builder/internal/logger"
+)
+
+// stagedFile 暂存的文件
+type stagedFile struct {
+	relPath string // 相对于输出根目录的路径
+	staged  string // 暂存文件路径，删除操作时为空
+	backup  string // 提交时原文件的备份路径，原文件不存在时为空
+	created bool   // 提交时是否新建了目标文件
+}
+
+// Transaction 输出事务：先把所有文件写入暂存目录，全部成功后再统一替换到输出目录，失败时回滚
+type Transaction struct {
+	root        string
+	stagingDir  string
+	keepStaging bool
+	opts        WriteOptions
+	files       []*stagedFile
+	index       map[string]*stagedFile
+}
+
+// Begin 在输出根目录下创建暂存目录并开始事务
+func Begin(root string, keepStaging bool) (*Transaction, error) {
+	return BeginWithOptions(root, keepStaging, WriteOptions{})
+}
+
+// BeginWithOptions 按写入选项开始事务
+func BeginWithOptions(root string, keepStaging bool, opts WriteOptions) (*Transaction, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("创建输出目录失败: %v", err)
+	}
+
+	// 暂存目录与输出目录位于同一文件系统，保证重命名是原子操作
+	stagingDir, err := os.MkdirTemp(root, ".builder-staging-")
+	if err != nil {
+		return nil, fmt.Errorf("创建暂存目录失败: %v", err)
+	}
+
+	return &Transaction{
+		root:        root,
+		stagingDir:  stagingDir,
+		keepStaging: keepStaging,
+		opts:        opts,
+		files:       make([]*stagedFile, 0),
+		index:       make(map[string]*stagedFile),
+	}, nil
+}
+
+// StagingDir 暂存目录路径
+func (t *Transaction) StagingDir() string {
+	return t.stagingDir
+}
+
+// Write 将文件写入暂存目录，relPath 为相对于输出根目录的路径
+func (t *Transaction) Write(relPath string, content []byte) error {
+	return t.WriteAll([]File{{Path: relPath, Content: content}}, nil)
+}
+
+// WriteAll 并发地将多个文件写入暂存目录，每写完一个调用一次 done；同一路径出现多次时以最后一次为准
+func (t *Transaction) WriteAll(files []File, done func()) error {
+	// 按原有顺序登记，提交顺序与并发无关
+	staged := make([]*stagedFile, len(files))
+	last := make(map[*stagedFile]int, len(files))
+	for i, file := range files {
+		relPath, err := cleanRelPath(file.Path)
+		if err != nil {
+			return err
+		}
+		staged[i] = t.stage(relPath)
+		last[staged[i]] = i
+	}
+
+	return forEach(len(files), t.opts.workers(), func(i int) error {
+		if last[staged[i]] != i {
+			if done != nil {
+				done()
+			}
+			return nil
+		}
+
+		stagedPath := filepath.Join(t.stagingDir, "files", staged[i].relPath)
+		if err := os.MkdirAll(filepath.Dir(stagedPath), 0755); err != nil {
+			return fmt.Errorf("创建暂存目录失败: %v", err)
+		}
+		if err := writeFile(stagedPath, files[i].Content, t.opts.Fsync); err != nil {
+			return fmt.Errorf("写入暂存文件失败: %v", err)
+		}
+		staged[i].staged = stagedPath
+		if done != nil {
+			done()
+		}
+		return nil
+	})
+}
+
+// Remove 在提交时删除输出目录中的文件，回滚时恢复
+func (t *Transaction) Remove(relPath string) error {
+	relPath, err := cleanRelPath(relPath)
+	if err != nil {
+		return err
+	}
+
+	t.stage(relPath).staged = ""
+	return nil
+}
+
+// stage 获取指定路径的暂存记录，不存在时创建
+func (t *Transaction) stage(relPath string) *stagedFile {
+	if file, exists := t.index[relPath]; exists {
+		return file
+	}
+
+	file := &stagedFile{relPath: relPath}
+	t.files = append(t.files, file)
+	t.index[relPath] = file
+	return file
+}
+
+// cleanRelPath 规范化相对路径并检查不超出输出目录
+func cleanRelPath(relPath string) (string, error) {
+	relPath = filepath.Clean(relPath)
+	if filepath.IsAbs(relPath) || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("输出路径 %s 超出输出目录", relPath)
+	}
+	return relPath, nil
+}
+
+// Commit 将暂存的文件逐个替换到输出目录，任一文件失败时回滚已替换的文件
+// 开启 fsync 时最后同步所有涉及的目录，使重命名持久化
+func (t *Transaction) Commit() error {
+	for i, file := range t.files {
+		if err := t.commitFile(file); err != nil {
+			t.rollback(t.files[:i+1])
+			t.cleanup()
+			return fmt.Errorf("提交 %s 失败，已回滚: %v", file.relPath, err)
+		}
+	}
+
+	if t.opts.Fsync {
+		dirs := make(map[string]bool)
+		for _, file := range t.files {
+			dir := filepath.Dir(filepath.Join(t.root, file.relPath))
+			if dirs[dir] {
+				continue
+			}
+			dirs[dir] = true
+			if err := syncDir(dir); err != nil {
+				t.cleanup()
+				return fmt.Errorf("同步目录 %s 失败: %v", dir, err)
+			}
+		}
+	}
+
+	t.cleanup()
+	return nil
+}
+
+// Rollback 放弃事务，输出目录保持不变
+func (t *Transaction) Rollback() {
+	t.cleanup()
+}
+
+// commitFile 备份原文件并替换为暂存文件
+func (t *Transaction) commitFile(file *stagedFile) error {
+	target := filepath.Join(t.root, file.relPath)
+
+	// 备份原文件
+	if _, err := os.Stat(target); err == nil {
+		backup := filepath.Join(t.stagingDir, "backup", file.relPath)
+		if err := os.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(target, backup); err != nil {
+			return err
+		}
+		file.backup = backup
+	}
+
+	// 删除操作只需备份原文件
+	if file.staged == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	// 保留暂存目录时复制后重命名，否则直接重命名
+	source := file.staged
+	if t.keepStaging {
+		source = target + ".tmp"
+		if err := copyFile(file.staged, source, t.opts.Fsync); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(source, target); err != nil {
+		return err
+	}
+	file.created = true
+	return nil
+}
+
+// rollback 恢复已提交文件的原内容
+func (t *Transaction) rollback(files []*stagedFile) {
+	for i := len(files) - 1; i >= 0; i-- {
+		file := files[i]
+		target := filepath.Join(t.root, file.relPath)
+		if file.created {
+			os.Remove(target)
+		}
+		if file.backup != "" {
+			os.Rename(file.backup, target)
+		}
+	}
+}
+
+// cleanup 删除暂存目录，保留暂存目录时只打印路径
+func (t *Transaction) cleanup() {
+	if t.keepStaging {
+		logger.Infof("保留暂存目录: %s", t.stagingDir)
+		return
+	}
+	os.RemoveAll(t.stagingDir)
+}
+
+// copyFile 复制文件，开启 fsync 时关闭前同步到磁盘
+func copyFile(src, dst string, fsync bool) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if fsync {
+		if err := out.Sync(); err != nil {
+			out.Close()
+			return err
+		}
+	}
+	return out.Close()
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// VersionFileName 版本文件名
+const VersionFileName = "version.json"
+
+// VersionFile 版本文件，供客户端/服务器比较文件哈希做差量热更新
+type VersionFile struct {
+	BuildTime    string         `json:"buildTime"`              // 构建时间（RFC 3339）
+	Commit       string         `json:"commit,omitempty"`       // 源数据所在仓库的 git 提交
+	Files        []VersionEntry `json:"files"`                  // 生成的文件
+	Sheets       map[string]int `json:"sheets,omitempty"`       // 各表的数据版本
+	FailedSheets []string       `json:"failedSheets,omitempty"` // 预览构建中验证失败而未更新输出的表
+}
+
+// VersionEntry 单个生成文件的校验信息
+type VersionEntry struct {
+	Path   string `json:"path"`   // 相对于输出根目录的路径（使用 / 分隔）
+	SHA256 string `json:"sha256"` // 内容的 SHA-256
+	Size   int    `json:"size"`   // 字节数
+}
+
+// NewVersionFile 创建版本文件
+func NewVersionFile(buildTime time.Time, commit string) *VersionFile {
+	return &VersionFile{
+		BuildTime: buildTime.UTC().Format(time.RFC3339),
+		Commit:    commit,
+		Files:     make([]VersionEntry, 0),
+	}
+}
+
+// Add 记录一个生成的文件
+func (v *VersionFile) Add(relPath string, content []byte) {
+	sum := sha256.Sum256(content)
+	v.Files = append(v.Files, VersionEntry{
+		Path:   filepath.ToSlash(relPath),
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   len(content),
+	})
+}
+
+// Inherit 从上一个版本沿用未重新生成的文件的校验信息
+func (v *VersionFile) Inherit(previous *VersionFile, relPaths []string) {
+	kept := make(map[string]bool, len(relPaths))
+	for _, relPath := range relPaths {
+		kept[filepath.ToSlash(relPath)] = true
+	}
+	for _, entry := range previous.Files {
+		if kept[entry.Path] {
+			v.Files = append(v.Files, entry)
+		}
+	}
+}
+
+// Marshal 序列化版本文件
+func (v *VersionFile) Marshal() ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// GitCommit 获取目录所在 git 仓库的当前提交，不在仓库中或 git 不可用时返回空
+func GitCommit(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// LoadVersionFile 读取输出目录中的版本文件，文件不存在时返回空的版本文件
+func LoadVersionFile(root string) (*VersionFile, error) {
+	version := &VersionFile{Files: make([]VersionEntry, 0)}
+
+	content, err := os.ReadFile(filepath.Join(root, VersionFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return version, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(content, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// Changed 返回与上一个版本相比新增或内容变化的文件
+func (v *VersionFile) Changed(previous *VersionFile) []string {
+	hashes := make(map[string]string, len(previous.Files))
+	for _, entry := range previous.Files {
+		hashes[entry.Path] = entry.SHA256
+	}
+
+	changed := make([]string, 0)
+	for _, entry := range v.Files {
+		if hashes[entry.Path] != entry.SHA256 {
+			changed = append(changed, entry.Path)
+		}
+	}
+	return changed
+}
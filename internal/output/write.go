@@ -0,0 +1,131 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// WriteOptions 输出文件的写入选项
+type WriteOptions struct {
+	Workers int  // 并发写入的文件数，0 表示使用 CPU 核数
+	Fsync   bool // 写入后同步到磁盘，重命名后同步所在目录，适用于网络文件系统等需要保证持久化的场景
+}
+
+// workers 实际使用的并发数
+func (o WriteOptions) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// File 待写入的文件
+type File struct {
+	Path    string // 相对于输出根目录的路径
+	Content []byte // 文件内容
+}
+
+// writeFile 写入文件，开启 fsync 时关闭前同步到磁盘
+func writeFile(path string, content []byte, fsync bool) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if fsync {
+		if err := file.Sync(); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// WriteFileAtomic 先写入同目录下的临时文件再重命名，读取方不会看到写了一半的文件
+func WriteFileAtomic(path string, content []byte, fsync bool) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+	temp.Close()
+
+	if err := writeFile(tempPath, content, fsync); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if fsync {
+		return syncDir(filepath.Dir(path))
+	}
+	return nil
+}
+
+// syncDir 同步目录，使其中的新建和重命名持久化；不支持同步目录的平台忽略错误
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := file.Sync(); err != nil && runtime.GOOS != "windows" {
+		return err
+	}
+	return nil
+}
+
+// forEach 以最多 workers 个协程并发处理 count 项，出错后不再开始新的项，返回第一个错误
+func forEach(count, workers int, fn func(i int) error) error {
+	if workers > count {
+		workers = count
+	}
+	if workers <= 1 {
+		for i := 0; i < count; i++ {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := fn(i); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
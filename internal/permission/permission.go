@@ -0,0 +1,134 @@
+package permission
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"reflect"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// Violation 越权修改
+type Violation struct {
+	Sheet  string      // 表名
+	Key    interface{} // 行主键
+	Column string      // 列名
+	Role   string      // 提交修改的角色
+}
+
+// Error 实现error接口
+func (v *Violation) Error() string {
+	return fmt.Sprintf("角色 %s 无权修改 %s[%v].%s", v.Role, v.Sheet, v.Key, v.Column)
+}
+
+// Checker 列级写权限检查器
+type Checker struct {
+	config *config.PermissionConfig
+}
+
+// NewChecker 创建权限检查器
+func NewChecker(cfg *config.PermissionConfig) *Checker {
+	if cfg == nil {
+		cfg = &config.PermissionConfig{Default: "allow"}
+	}
+	return &Checker{config: cfg}
+}
+
+// Role 返回访问令牌对应的角色，令牌未配置时返回 false
+func (c *Checker) Role(token string) (string, bool) {
+	for configured, role := range c.config.Tokens {
+		if subtle.ConstantTimeCompare([]byte(configured), []byte(token)) == 1 {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// CanWrite 检查角色是否可以写入指定列
+func (c *Checker) CanWrite(role, sheet, column string) bool {
+	columns, exists := c.config.Sheets[sheet]
+	if !exists {
+		return c.config.Default != "deny"
+	}
+
+	roles, exists := columns[column]
+	if !exists {
+		roles, exists = columns["*"]
+	}
+	if !exists {
+		return c.config.Default != "deny"
+	}
+
+	for _, allowed := range roles {
+		if allowed == role || allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckChanges 按主键对比修改前后的表，返回角色无权修改的单元格
+func (c *Checker) CheckChanges(role string, current, updated *model.DataSheet) []*Violation {
+	violations := make([]*Violation, 0)
+	keyColumn := updated.PrimaryKey()
+
+	currentRows := make(map[string]map[string]interface{})
+	if current != nil {
+		for _, row := range current.Rows {
+			key, _ := model.RowValue(row, keyColumn)
+			currentRows[fmt.Sprintf("%v", key)] = row
+		}
+	}
+
+	// 对比修改前后所有列的并集，删除的列视为修改该列的所有单元格
+	columns := make([]string, 0, len(updated.Columns))
+	removed := make(map[string]bool)
+	for _, col := range updated.Columns {
+		columns = append(columns, col.Name)
+	}
+	if current != nil {
+		for _, col := range current.Columns {
+			if !updated.HasColumn(col.Name) {
+				columns = append(columns, col.Name)
+				removed[col.Name] = true
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, row := range updated.Rows {
+		key, _ := model.RowValue(row, keyColumn)
+		keyStr := fmt.Sprintf("%v", key)
+		seen[keyStr] = true
+		oldRow := currentRows[keyStr] // 新增行与空行对比
+
+		for _, name := range columns {
+			newVal, _ := model.RowValue(row, name)
+			oldVal, _ := model.RowValue(oldRow, name)
+			if !removed[name] && reflect.DeepEqual(newVal, oldVal) {
+				continue
+			}
+			if !c.CanWrite(role, updated.Name, name) {
+				violations = append(violations, &Violation{Sheet: updated.Name, Key: key, Column: name, Role: role})
+			}
+		}
+	}
+
+	// 删除行视为修改该行的所有列
+	if current != nil {
+		for _, row := range current.Rows {
+			key, _ := model.RowValue(row, keyColumn)
+			if seen[fmt.Sprintf("%v", key)] {
+				continue
+			}
+			for _, col := range current.Columns {
+				if !c.CanWrite(role, current.Name, col.Name) {
+					violations = append(violations, &Violation{Sheet: current.Name, Key: key, Column: col.Name, Role: role})
+				}
+			}
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,140 @@
+package reader
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// MetaInclude 引入共享列组的表元数据键，多个列组以逗号分隔，如 include:reward,cost
+const MetaInclude = "include"
+
+// ResolveColumnGroups 将表引入的列组定义合并到表的列中
+//
+// 表中已有的同名列必须与列组定义一致：类型行为空时使用列组的类型并按该类型重新转换单元格，
+// 注释、默认值、可选值和引用为空时使用列组的定义；表中缺少的选填列追加在最后并填入默认值，缺少必填列时报错
+func ResolveColumnGroups(sheets []*model.DataSheet, cfg *config.ColumnGroupsConfig) error {
+	for _, sheet := range sheets {
+		include := metaString(sheet, MetaInclude, "包含")
+		if include == "" {
+			continue
+		}
+
+		for _, name := range strings.Split(include, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			var entries []config.ColumnGroupEntry
+			exists := false
+			if cfg != nil {
+				entries, exists = cfg.Groups[name]
+			}
+			if !exists {
+				return fmt.Errorf("sheet %s: 引入的列组 %s 不存在", sheet.Name, name)
+			}
+			for _, entry := range entries {
+				if err := includeGroupColumn(sheet, entry); err != nil {
+					return fmt.Errorf("sheet %s: 列组 %s 的列 %s: %v", sheet.Name, name, entry.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// includeGroupColumn 将列组中的一列合并到表中
+func includeGroupColumn(sheet *model.DataSheet, entry config.ColumnGroupEntry) error {
+	defaultValue, err := constantValue(entry.Default, entry.Type)
+	if err != nil {
+		return fmt.Errorf("默认值无效: %v", err)
+	}
+	var ref *model.RefInfo
+	if entry.Ref != "" {
+		index := strings.LastIndex(entry.Ref, ".")
+		ref = &model.RefInfo{Sheet: entry.Ref[:index], Column: entry.Ref[index+1:]}
+	}
+
+	index := -1
+	for i, col := range sheet.Columns {
+		if col.Name == entry.Name {
+			index = i
+			break
+		}
+	}
+
+	if index < 0 {
+		if entry.Required {
+			return fmt.Errorf("表中缺少列组的必填列")
+		}
+		sheet.Columns = append(sheet.Columns, model.ColumnInfo{
+			Name:     entry.Name,
+			Type:     entry.Type,
+			Comment:  entry.Comment,
+			Required: false,
+			Default:  defaultValue,
+			Options:  entry.Options,
+			Ref:      ref,
+		})
+		for _, row := range sheet.Rows {
+			model.SetRowValue(row, entry.Name, defaultValue)
+		}
+		return nil
+	}
+
+	col := &sheet.Columns[index]
+	untyped := col.Type == ""
+	if !untyped && !strings.EqualFold(col.Type, entry.Type) {
+		return fmt.Errorf("类型 %s 与列组定义的 %s 不一致", col.Type, entry.Type)
+	}
+	if col.Ref != nil && ref != nil && *col.Ref != *ref {
+		return fmt.Errorf("引用 %s.%s 与列组定义的 %s 不一致", col.Ref.Sheet, col.Ref.Column, entry.Ref)
+	}
+	if col.Options != nil && entry.Options != nil && !reflect.DeepEqual(col.Options, entry.Options) {
+		return fmt.Errorf("可选值 %v 与列组定义的 %v 不一致", col.Options, entry.Options)
+	}
+
+	// 类型行为空的列读取时按字符串处理，按列组的类型重新转换
+	if untyped {
+		col.Type = entry.Type
+		if col.Default != nil {
+			if col.Default, err = constantValue(col.Default, entry.Type); err != nil {
+				return fmt.Errorf("默认值无效: %v", err)
+			}
+		}
+		for i, row := range sheet.Rows {
+			value, exists := model.RowValue(row, entry.Name)
+			if !exists || value == nil {
+				continue
+			}
+			converted, err := constantValue(value, entry.Type)
+			if err != nil {
+				return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(i), err)
+			}
+			model.SetRowValue(row, entry.Name, converted)
+		}
+	}
+
+	if col.Comment == "" {
+		col.Comment = entry.Comment
+	}
+	if col.Options == nil {
+		col.Options = entry.Options
+	}
+	if col.Ref == nil {
+		col.Ref = ref
+	}
+	col.Required = entry.Required
+	if col.Default == nil && defaultValue != nil {
+		col.Default = defaultValue
+		for _, row := range sheet.Rows {
+			if value, exists := model.RowValue(row, entry.Name); !exists || value == nil {
+				model.SetRowValue(row, entry.Name, defaultValue)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package reader
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// columnTransform 类型行中声明的列转换，如 int|*1000、string|lower|trim，在解析单元格后依次执行
+type columnTransform struct {
+	dataType string          // 去掉转换后的列类型
+	numeric  bool            // 列类型是否为数值
+	integer  bool            // 列类型是否为整数，算术转换时先按浮点数解析，最后再转为整数
+	steps    []transformStep // 按声明顺序执行的转换
+}
+
+// transformStep 单个转换
+type transformStep struct {
+	name  string // 声明中的原文，如 *1000
+	apply func(value interface{}) interface{}
+}
+
+// splitColumnType 拆分类型行单元格中的类型与转换，如 "int|*1000" -> "int", ["*1000"]
+func splitColumnType(cell string) (string, []string) {
+	parts := strings.Split(cell, "|")
+	transforms := make([]string, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		if part = strings.TrimSpace(part); part != "" {
+			transforms = append(transforms, part)
+		}
+	}
+	return strings.TrimSpace(parts[0]), transforms
+}
+
+// parseColumnTransform 解析列的转换声明，转换与列类型不匹配或不支持时报错
+//
+// 数值列支持 *N、/N、+N、-N、round、floor、ceil；字符串列（以及枚举等按字符串读取的列）支持 trim、lower、upper
+func parseColumnTransform(dataType string, specs []string) (*columnTransform, error) {
+	t := &columnTransform{dataType: dataType}
+	switch strings.ToLower(dataType) {
+	case "int", "integer":
+		t.numeric, t.integer = true, true
+	case "float", "double", "number":
+		t.numeric = true
+	case "bool", "boolean":
+		if len(specs) > 0 {
+			return nil, fmt.Errorf("%s 类型的列不支持转换", dataType)
+		}
+	}
+
+	for _, spec := range specs {
+		var apply func(value interface{}) interface{}
+		if t.numeric {
+			apply = numericTransform(spec)
+		} else {
+			apply = stringTransform(spec)
+		}
+		if apply == nil {
+			return nil, fmt.Errorf("%s 类型的列不支持转换 %s", dataType, spec)
+		}
+		t.steps = append(t.steps, transformStep{name: spec, apply: apply})
+	}
+	return t, nil
+}
+
+// numericTransform 数值转换，不支持时返回 nil
+func numericTransform(spec string) func(value interface{}) interface{} {
+	switch spec {
+	case "round":
+		return func(value interface{}) interface{} { return math.Round(value.(float64)) }
+	case "floor":
+		return func(value interface{}) interface{} { return math.Floor(value.(float64)) }
+	case "ceil":
+		return func(value interface{}) interface{} { return math.Ceil(value.(float64)) }
+	}
+
+	if len(spec) < 2 {
+		return nil
+	}
+	operand, err := strconv.ParseFloat(spec[1:], 64)
+	if err != nil || math.IsNaN(operand) || math.IsInf(operand, 0) {
+		return nil
+	}
+	switch spec[0] {
+	case '*':
+		return func(value interface{}) interface{} { return value.(float64) * operand }
+	case '/':
+		if operand == 0 {
+			return nil
+		}
+		return func(value interface{}) interface{} { return value.(float64) / operand }
+	case '+':
+		return func(value interface{}) interface{} { return value.(float64) + operand }
+	case '-':
+		return func(value interface{}) interface{} { return value.(float64) - operand }
+	}
+	return nil
+}
+
+// stringTransform 字符串转换，不支持时返回 nil
+func stringTransform(spec string) func(value interface{}) interface{} {
+	var fn func(string) string
+	switch spec {
+	case "trim":
+		fn = strings.TrimSpace
+	case "lower":
+		fn = strings.ToLower
+	case "upper":
+		fn = strings.ToUpper
+	default:
+		return nil
+	}
+	return func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			return fn(s)
+		}
+		return value
+	}
+}
+
+// wrap 返回按列转换的值转换函数：整数列有转换时先按浮点数解析，执行全部转换后再转为整数
+func (t *columnTransform) wrap(convert valueConverter) valueConverter {
+	if len(t.steps) == 0 {
+		return convert
+	}
+	return func(value string, dataType string) (interface{}, error) {
+		parseType := dataType
+		if t.integer {
+			parseType = "float"
+		}
+		parsed, err := convert(value, parseType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, step := range t.steps {
+			parsed = step.apply(parsed)
+		}
+		if !t.integer {
+			return parsed, nil
+		}
+
+		// 允许浮点运算的微小误差，如 1.001*1000
+		result := parsed.(float64)
+		rounded := math.Round(result)
+		if math.Abs(result-rounded) > 1e-9*math.Max(1, math.Abs(result)) {
+			return nil, fmt.Errorf("%s 经过转换 %s 后为 %v，不是整数", value, strings.Join(t.names(), "|"), result)
+		}
+		if rounded < math.MinInt64 || rounded >= math.MaxInt64 {
+			return nil, fmt.Errorf("%s 经过转换 %s 后超出整数范围", value, strings.Join(t.names(), "|"))
+		}
+		return int(rounded), nil
+	}
+}
+
+// names 转换声明的原文
+func (t *columnTransform) names() []string {
+	names := make([]string, 0, len(t.steps))
+	for _, step := range t.steps {
+		names = append(names, step.name)
+	}
+	return names
+}
@@ -0,0 +1,85 @@
+package reader
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// ConstantsSheet 根据常量配置生成只有一行的虚拟表，每个常量是一列；没有常量时返回 nil
+func ConstantsSheet(cfg *config.ConstantsConfig) (*model.DataSheet, error) {
+	if cfg == nil || len(cfg.Constants) == 0 {
+		return nil, nil
+	}
+
+	sheet := &model.DataSheet{
+		Name:         cfg.Sheet,
+		Columns:      make([]model.ColumnInfo, 0, len(cfg.Constants)),
+		Meta:         make(map[string]interface{}),
+		DataStartRow: 1,
+	}
+	row := make(map[string]interface{}, len(cfg.Constants))
+
+	for _, entry := range cfg.Constants {
+		col := model.ColumnInfo{
+			Name:     entry.Key,
+			Type:     entry.Type,
+			Comment:  entry.Comment,
+			Required: entry.Required,
+		}
+		if entry.Ref != "" {
+			index := strings.LastIndex(entry.Ref, ".")
+			if index <= 0 || index == len(entry.Ref)-1 {
+				return nil, fmt.Errorf("常量 %s 的引用 %s 应为 表名.列名", entry.Key, entry.Ref)
+			}
+			col.Ref = &model.RefInfo{Sheet: entry.Ref[:index], Column: entry.Ref[index+1:]}
+		}
+		sheet.Columns = append(sheet.Columns, col)
+
+		value, err := constantValue(entry.Value, entry.Type)
+		if err != nil {
+			return nil, fmt.Errorf("常量 %s: %v", entry.Key, err)
+		}
+		model.SetRowValue(row, entry.Key, value)
+	}
+
+	sheet.Rows = []map[string]interface{}{row}
+	return sheet, nil
+}
+
+// constantValue 将 JSON 中的常量值转换为列类型对应的值，字符串按表格单元格的规则转换
+func constantValue(value interface{}, colType string) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return (&CSVReader{}).convertValue(v, colType)
+	case float64:
+		switch colType {
+		case "int", "integer":
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("%v 不是整数", v)
+			}
+			return int(v), nil
+		case "float", "double", "number":
+			return v, nil
+		}
+		if _, ok := model.EnumName(colType); ok {
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("%v 不是整数", v)
+			}
+			return int(v), nil
+		}
+	case bool:
+		if colType == "bool" || colType == "boolean" {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("值 %v 与类型 %s 不符", value, colType)
+}
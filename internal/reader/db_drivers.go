@@ -0,0 +1,7 @@
+package reader
+
+// 内置的数据库驱动，driver 选项使用 mysql 或 sqlite3
+import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+)
@@ -0,0 +1,269 @@
+package reader
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// sqlIdentifier 只包含表名（可带库名）的配置，其余视为查询语句
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// DBReader 数据库读取器，按配置把数据库中的表或查询结果读取为数据表
+//
+// 选项：driver 为 database/sql 的驱动名（内置 mysql 和 sqlite3），dsn 为连接串，也可以用 dsnEnv 指定保存连接串的环境变量；
+// tables 为表名 -> 数据库表名或 SELECT 查询。读取同样遵循远程数据源的重试选项，全部失败时可以使用上次成功读取的缓存
+type DBReader struct {
+	config map[string]interface{}
+	driver string
+	dsn    string
+	tables map[string]string
+	policy *RetryPolicy
+	cached []string // 本次读取中使用了缓存的数据源
+}
+
+// dbGrid 查询结果，与表格文件一样以列名、类型和注释三行表头加数据行的形式缓存
+type dbGrid [][]string
+
+// NewDBReader 创建数据库读取器
+func NewDBReader() *DBReader {
+	return &DBReader{}
+}
+
+// Init 初始化读取器
+func (r *DBReader) Init(config map[string]interface{}) error {
+	driver, _ := config["driver"].(string)
+	if driver == "" {
+		return fmt.Errorf("数据库读取器未配置 driver")
+	}
+
+	dsn, _ := config["dsn"].(string)
+	if env, _ := config["dsnEnv"].(string); env != "" {
+		dsn = os.Getenv(env)
+		if dsn == "" {
+			return fmt.Errorf("环境变量 %s 中没有数据库连接串", env)
+		}
+	}
+	if dsn == "" {
+		return fmt.Errorf("数据库读取器未配置 dsn 或 dsnEnv")
+	}
+
+	tables := make(map[string]string)
+	configured, _ := config["tables"].(map[string]interface{})
+	for name, value := range configured {
+		source, ok := value.(string)
+		if !ok || strings.TrimSpace(source) == "" {
+			return fmt.Errorf("表 %s 的数据库表名或查询无效", name)
+		}
+		tables[name] = strings.TrimSpace(source)
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("数据库读取器未配置 tables")
+	}
+
+	policy, err := ParseRetryPolicy(config)
+	if err != nil {
+		return err
+	}
+
+	r.config = config
+	r.driver = driver
+	r.dsn = dsn
+	r.tables = tables
+	r.policy = policy
+	return nil
+}
+
+// ReadAll 按表名顺序读取所有配置的表，source 为数据源名称，用于日志和缓存
+func (r *DBReader) ReadAll(source string) ([]*model.DataSheet, error) {
+	return r.ReadAllContext(context.Background(), source)
+}
+
+// ReadAllContext 与 ReadAll 相同，ctx 取消时中止正在执行的查询
+func (r *DBReader) ReadAllContext(ctx context.Context, source string) ([]*model.DataSheet, error) {
+	names := make([]string, 0, len(r.tables))
+	for name := range r.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sheets := make([]*model.DataSheet, 0, len(names))
+	for _, name := range names {
+		sheet, err := r.readSheet(ctx, source, name)
+		if err != nil {
+			return nil, err
+		}
+		if sheet != nil {
+			sheets = append(sheets, sheet)
+		}
+	}
+	return sheets, nil
+}
+
+// ReadSheet 读取指定的表，未配置的表返回 nil
+func (r *DBReader) ReadSheet(source string, sheetName string) (*model.DataSheet, error) {
+	return r.readSheet(context.Background(), source, sheetName)
+}
+
+// readSheet 读取指定的表，ctx 取消时中止查询
+func (r *DBReader) readSheet(ctx context.Context, source string, sheetName string) (*model.DataSheet, error) {
+	query, exists := r.tables[sheetName]
+	if !exists {
+		return nil, nil
+	}
+	if sqlIdentifier.MatchString(query) {
+		query = "SELECT * FROM " + query
+	}
+
+	// 缓存以数据源、表名和查询区分，查询修改后不会用到旧的缓存
+	cacheKey := fmt.Sprintf("db:%s/%s:%s", source, sheetName, query)
+	content, fromCache, err := r.policy.FetchContext(ctx, cacheKey, func(ctx context.Context) ([]byte, error) {
+		grid, err := r.query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(grid)
+	})
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("表 %s: %v", sheetName, err)
+	}
+	if fromCache {
+		r.cached = append(r.cached, fmt.Sprintf("%s/%s", source, sheetName))
+	}
+
+	var grid dbGrid
+	if err := json.Unmarshal(content, &grid); err != nil {
+		return nil, fmt.Errorf("表 %s 的缓存无效: %v", sheetName, err)
+	}
+	return parseGrid(sheetName, grid, DefaultHeaderLayout(), r.convertValue)
+}
+
+// CachedSources 使用了缓存数据的表
+func (r *DBReader) CachedSources() []string {
+	return r.cached
+}
+
+// GetSupportedFormats 数据库读取器不对应任何文件格式
+func (r *DBReader) GetSupportedFormats() []string {
+	return []string{}
+}
+
+// query 执行查询，列类型由数据库类型映射，可为空的列标记为选填
+func (r *DBReader) query(ctx context.Context, query string) (dbGrid, error) {
+	db, err := sql.Open(r.driver, r.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库失败（驱动 %s）: %v", r.driver, err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	grid := make(dbGrid, 3)
+	for _, columnType := range columnTypes {
+		grid[0] = append(grid[0], columnType.Name())
+		grid[1] = append(grid[1], sqlColumnType(columnType.DatabaseTypeName()))
+		comment := ""
+		if nullable, ok := columnType.Nullable(); ok && nullable {
+			comment = "选填"
+		}
+		grid[2] = append(grid[2], comment)
+	}
+
+	values := make([]interface{}, len(columnTypes))
+	pointers := make([]interface{}, len(columnTypes))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(values))
+		for i, value := range values {
+			row[i] = formatSQLValue(value)
+		}
+		grid = append(grid, row)
+	}
+	return grid, rows.Err()
+}
+
+// sqlColumnType 将数据库的列类型映射为表头类型：整数为 int，小数为 float，布尔和位为 bool，其余（文本、日期等）为 string
+//
+// SQLite 返回建表时声明的类型，可能带长度和精度（如 DECIMAL(10,2)），映射前去掉
+func sqlColumnType(databaseType string) string {
+	databaseType = strings.TrimPrefix(strings.ToUpper(databaseType), "UNSIGNED ")
+	if index := strings.Index(databaseType, "("); index >= 0 {
+		databaseType = strings.TrimSpace(databaseType[:index])
+	}
+	switch databaseType {
+	case "BOOL", "BOOLEAN", "BIT":
+		return "bool"
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "INT2", "INT4", "INT8", "SERIAL", "BIGSERIAL":
+		return "int"
+	case "DECIMAL", "NUMERIC", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "DOUBLE PRECISION", "REAL":
+		return "float"
+	default:
+		return "string"
+	}
+}
+
+// formatSQLValue 将查询结果中的值转换为单元格文本，NULL 为空单元格
+func formatSQLValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// convertValue 转换数据类型，与 CSV 读取器一致
+func (r *DBReader) convertValue(value string, dataType string) (interface{}, error) {
+	switch dataType {
+	case "int", "integer":
+		return strconv.Atoi(value)
+	case "float", "double", "number":
+		return strconv.ParseFloat(value, 64)
+	case "bool", "boolean":
+		return strconv.ParseBool(value)
+	case "string":
+		return value, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,128 @@
+package reader
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// DelimitedReader 制表符或其他分隔符分隔的文本文件读取器，表头约定与 CSV 相同
+//
+// .tsv 文件始终以制表符分隔；.txt 文件的分隔符由 delimiter 选项指定，默认为制表符
+type DelimitedReader struct {
+	config    map[string]interface{}
+	layout    *HeaderLayout
+	delimiter rune // .txt 文件的分隔符
+}
+
+// NewDelimitedReader 创建分隔符文本读取器
+func NewDelimitedReader() *DelimitedReader {
+	return &DelimitedReader{layout: DefaultHeaderLayout(), delimiter: '\t'}
+}
+
+// Init 初始化读取器
+func (r *DelimitedReader) Init(config map[string]interface{}) error {
+	layout, err := ParseHeaderLayout(config)
+	if err != nil {
+		return err
+	}
+
+	if value, exists := config["delimiter"]; exists {
+		text, _ := value.(string)
+		delimiter, err := parseDelimiter(text)
+		if err != nil {
+			return err
+		}
+		r.delimiter = delimiter
+	}
+
+	r.config = config
+	r.layout = layout
+	return nil
+}
+
+// parseDelimiter 解析分隔符选项：单个字符，或 tab、pipe、comma、semicolon
+func parseDelimiter(text string) (rune, error) {
+	switch strings.ToLower(text) {
+	case "tab":
+		return '\t', nil
+	case "pipe":
+		return '|', nil
+	case "comma":
+		return ',', nil
+	case "semicolon":
+		return ';', nil
+	}
+	delimiter, size := utf8.DecodeRuneInString(text)
+	if size == 0 || size != len(text) || delimiter == utf8.RuneError || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+		return 0, fmt.Errorf("无效的分隔符 %q，应为单个字符或 tab、pipe、comma、semicolon", text)
+	}
+	return delimiter, nil
+}
+
+// ReadAll 读取所有数据表
+func (r *DelimitedReader) ReadAll(filePath string) ([]*model.DataSheet, error) {
+	// 文本文件只有一个工作表
+	sheet, err := r.ReadSheet(filePath, "")
+	if err != nil {
+		return nil, err
+	}
+	if sheet == nil {
+		return []*model.DataSheet{}, nil // 表头不完整的文件不包含数据表
+	}
+	return []*model.DataSheet{sheet}, nil
+}
+
+// ReadSheet 读取指定工作表
+func (r *DelimitedReader) ReadSheet(filePath string, sheetName string) (*model.DataSheet, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ext := filepath.Ext(filePath)
+	reader := csv.NewReader(file)
+	reader.Comma = r.delimiter
+	if strings.EqualFold(ext, ".tsv") {
+		reader.Comma = '\t'
+	}
+	reader.LazyQuotes = true    // 旧工具导出的文件中常有未转义的引号
+	reader.FieldsPerRecord = -1 // 横幅行的列数可能与数据行不同
+
+	allLines, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// 文件名（去掉后缀）作为表名
+	tableName := strings.TrimSuffix(filepath.Base(filePath), ext)
+	return parseGrid(tableName, allLines, r.layout, r.convertValue)
+}
+
+// GetSupportedFormats 获取支持的文件格式
+func (r *DelimitedReader) GetSupportedFormats() []string {
+	return []string{".tsv", ".TSV", ".txt", ".TXT"}
+}
+
+// convertValue 转换数据类型，与 CSV 读取器一致
+func (r *DelimitedReader) convertValue(value string, dataType string) (interface{}, error) {
+	switch dataType {
+	case "int", "integer":
+		return strconv.Atoi(value)
+	case "float", "double", "number":
+		return strconv.ParseFloat(value, 64)
+	case "bool", "boolean":
+		return strconv.ParseBool(value)
+	case "string":
+		return value, nil
+	default:
+		return value, nil
+	}
+}
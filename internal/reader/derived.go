@@ -0,0 +1,73 @@
+package reader
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/expr"
+	"github.com/game-data-builder/internal/model"
+)
+
+// ResolveDerived 按 sheets.json 中的 derived 为表追加派生列，并逐行计算表达式写入值
+//
+// 表名完全相同和通配符匹配的配置都会应用，按配置的键排序后依次追加；
+// 派生列不能与已有的列重名，表达式可以引用之前追加的派生列
+func ResolveDerived(sheets []*model.DataSheet, cfg *config.SheetsConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(cfg.Sheets))
+	for pattern, settings := range cfg.Sheets {
+		if len(settings.Derived) > 0 {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+	sort.Strings(patterns)
+
+	for _, sheet := range sheets {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, sheet.Name); !matched {
+				continue
+			}
+			for _, derived := range cfg.Sheets[pattern].Derived {
+				if err := deriveColumn(sheet, derived); err != nil {
+					return fmt.Errorf("sheet %s: 派生列 %s: %v", sheet.Name, derived.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// deriveColumn 为表追加一个派生列
+func deriveColumn(sheet *model.DataSheet, derived config.DerivedColumn) error {
+	for _, col := range sheet.Columns {
+		if col.Name == derived.Name {
+			return fmt.Errorf("与已有的列重名")
+		}
+	}
+	compiled, err := expr.Compile(derived.Expr)
+	if err != nil {
+		return err
+	}
+
+	for rowIndex, row := range sheet.Rows {
+		val, err := compiled.Eval(row)
+		if err != nil {
+			return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(rowIndex), err)
+		}
+		typed, err := expr.ToType(val, derived.Type)
+		if err != nil {
+			return fmt.Errorf("第 %d 行: %v", sheet.RowNumber(rowIndex), err)
+		}
+		model.SetRowValue(row, derived.Name, typed)
+	}
+	sheet.Columns = append(sheet.Columns, model.ColumnInfo{Name: derived.Name, Type: derived.Type, Comment: derived.Comment})
+	return nil
+}
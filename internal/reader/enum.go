@@ -0,0 +1,91 @@
+package reader
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// ExtractEnums 从数据表中提取枚举定义表（@enums），返回剩余的数据表和枚举定义
+//
+// 枚举定义表包含 enum、name、value 三列，每行定义一个枚举成员。
+func ExtractEnums(sheets []*model.DataSheet) ([]*model.DataSheet, map[string]*model.EnumDef, error) {
+	enums := make(map[string]*model.EnumDef)
+	dataSheets := make([]*model.DataSheet, 0, len(sheets))
+
+	for _, sheet := range sheets {
+		if sheet.Name != model.EnumSheetName {
+			dataSheets = append(dataSheets, sheet)
+			continue
+		}
+
+		for rowIndex, row := range sheet.Rows {
+			enumName, _ := row["enum"].(string)
+			memberName, _ := row["name"].(string)
+			if enumName == "" || memberName == "" {
+				return nil, nil, fmt.Errorf("sheet %s, row %d: 枚举名和成员名不能为空", sheet.Name, sheet.RowNumber(rowIndex))
+			}
+
+			value, err := enumValue(row["value"])
+			if err != nil {
+				return nil, nil, fmt.Errorf("sheet %s, row %d: %v", sheet.Name, sheet.RowNumber(rowIndex), err)
+			}
+
+			enum, exists := enums[enumName]
+			if !exists {
+				enum = &model.EnumDef{Name: enumName, Members: make(map[string]int)}
+				enums[enumName] = enum
+			}
+			if _, exists := enum.Members[memberName]; exists {
+				return nil, nil, fmt.Errorf("sheet %s, row %d: 枚举 %s 的成员 %s 重复定义", sheet.Name, sheet.RowNumber(rowIndex), enumName, memberName)
+			}
+			enum.Members[memberName] = value
+		}
+	}
+
+	return dataSheets, enums, nil
+}
+
+// ResolveEnums 将枚举列中的成员名替换为对应的数值，无法识别的值保持原样交由验证器报告
+func ResolveEnums(sheets []*model.DataSheet, enums map[string]*model.EnumDef) {
+	for _, sheet := range sheets {
+		for _, col := range sheet.Columns {
+			enumName, ok := model.EnumName(col.Type)
+			if !ok {
+				continue
+			}
+			enum, exists := enums[enumName]
+			if !exists {
+				continue
+			}
+
+			for _, row := range sheet.Rows {
+				val, _ := model.RowValue(row, col.Name)
+				name, ok := val.(string)
+				if !ok {
+					continue
+				}
+				if value, exists := enum.Members[name]; exists {
+					model.SetRowValue(row, col.Name, value)
+				} else if value, err := strconv.Atoi(name); err == nil {
+					model.SetRowValue(row, col.Name, value) // 直接填写的数值，由验证器检查是否为合法成员
+				}
+			}
+		}
+	}
+}
+
+// enumValue 解析枚举成员的值
+func enumValue(val interface{}) (int, error) {
+	switch v := val.(type) {
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	case string:
+		return strconv.Atoi(v)
+	default:
+		return 0, fmt.Errorf("枚举值无效: %v", val)
+	}
+}
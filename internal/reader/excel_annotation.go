@@ -0,0 +1,149 @@
+package reader
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/logger"
+	"github.com/game-data-builder/internal/model"
+	"github.com/xuri/excelize/v2"
+)
+
+// hexColor Excel 样式中的 RGB 或 ARGB 颜色
+var hexColor = regexp.MustCompile(`^(?:[0-9A-Fa-f]{2})?([0-9A-Fa-f]{6})$`)
+
+// readAnnotations 读取数据区域中的单元格批注和按数值比较的条件格式，供差异报告等审阅产物保留策划的视觉提示
+//
+// 只保留位于数据列中的批注和条件格式；条件格式按列记录，不区分所在的行范围。
+// 批注和条件格式只是审阅时的提示，无法读取时记录警告并跳过，不影响数据的读取
+func readAnnotations(f *excelize.File, sheetName string, grid [][]string, layout *HeaderLayout, sheet *model.DataSheet) {
+	names := layout.row(grid, RoleName)
+	dataRows := make(map[int]bool, len(sheet.Rows))
+	for i := range sheet.Rows {
+		dataRows[sheet.RowNumber(i)] = true
+	}
+
+	comments, err := f.GetComments(sheetName)
+	if err != nil {
+		logger.Warnf("sheet %s: 读取批注失败，已忽略: %v", sheetName, err)
+	}
+	for _, comment := range comments {
+		col, row, err := excelize.CellNameToCoordinates(comment.Cell)
+		if err != nil || !dataRows[row] {
+			continue
+		}
+		name := cellAt(names, col-1)
+		text := commentText(comment)
+		if name == "" || text == "" {
+			continue
+		}
+		sheet.Comments = append(sheet.Comments, model.CellComment{Row: row, Column: name, Text: text})
+	}
+
+	formats, err := f.GetConditionalFormats(sheetName)
+	if err != nil {
+		logger.Warnf("sheet %s: 读取条件格式失败，已忽略: %v", sheetName, err)
+	}
+	refs := make([]string, 0, len(formats))
+	for sqref := range formats {
+		refs = append(refs, sqref)
+	}
+	sort.Strings(refs)
+	for _, sqref := range refs {
+		columns := rangeColumns(sqref, layout.HeaderRows())
+		for _, option := range formats[sqref] {
+			format, ok := conditionalFormat(f, option)
+			if !ok {
+				continue
+			}
+			for _, col := range columns {
+				if name := cellAt(names, col-1); name != "" {
+					format.Column = name
+					sheet.ConditionalFormats = append(sheet.ConditionalFormats, format)
+				}
+			}
+		}
+	}
+}
+
+// commentText 批注的文本，富文本批注拼接各段文字
+func commentText(comment excelize.Comment) string {
+	if comment.Text != "" {
+		return strings.TrimSpace(comment.Text)
+	}
+	var builder strings.Builder
+	for _, run := range comment.Paragraph {
+		builder.WriteString(run.Text)
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// rangeColumns 条件格式作用范围中与数据行有交集的列号（从1开始），范围可以是以空格分隔的多个区域
+func rangeColumns(sqref string, headerRows int) []int {
+	columns := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, ref := range strings.Fields(sqref) {
+		cells := strings.Split(ref, ":")
+		startCol, _, err := excelize.CellNameToCoordinates(cells[0])
+		if err != nil {
+			continue
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(cells[len(cells)-1])
+		if err != nil || endRow <= headerRows {
+			continue
+		}
+		for col := startCol; col <= endCol; col++ {
+			if !seen[col] {
+				seen[col] = true
+				columns = append(columns, col)
+			}
+		}
+	}
+	return columns
+}
+
+// conditionalFormat 转换按单元格值与数值比较的规则，其他类型的规则和以公式或单元格作为比较值的规则返回 false
+func conditionalFormat(f *excelize.File, option excelize.ConditionalFormatOptions) (model.ConditionalFormat, bool) {
+	format := model.ConditionalFormat{Criteria: option.Criteria}
+	if option.Type != "cell" || option.Format == nil {
+		return format, false
+	}
+
+	var err error
+	switch option.Criteria {
+	case "between", "not between":
+		if format.Value, err = strconv.ParseFloat(option.MinValue, 64); err != nil {
+			return format, false
+		}
+		if format.MaxValue, err = strconv.ParseFloat(option.MaxValue, 64); err != nil {
+			return format, false
+		}
+	default:
+		if format.Value, err = strconv.ParseFloat(option.Value, 64); err != nil {
+			return format, false
+		}
+	}
+
+	style, err := f.GetConditionalStyle(*option.Format)
+	if err != nil {
+		return format, false
+	}
+	if style.Font != nil {
+		format.Color = cssColor(style.Font.Color)
+	}
+	if len(style.Fill.Color) > 0 {
+		format.Fill = cssColor(style.Fill.Color[0])
+	}
+	return format, format.Color != "" || format.Fill != ""
+}
+
+// cssColor 将 Excel 的 RGB 或 ARGB 颜色转换为 #RRGGBB，无法识别（如主题色）时返回空字符串
+func cssColor(color string) string {
+	match := hexColor.FindStringSubmatch(strings.TrimPrefix(color, "#"))
+	if match == nil {
+		return ""
+	}
+	return "#" + strings.ToUpper(match[1])
+}
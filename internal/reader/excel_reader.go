@@ -1,6 +1,7 @@
 package reader
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,21 +13,33 @@ import (
 // ExcelReader Excel读取器实现
 type ExcelReader struct {
 	config map[string]interface{}
+	layout *HeaderLayout
 }
 
 // NewExcelReader 创建Excel读取器
 func NewExcelReader() *ExcelReader {
-	return &ExcelReader{}
+	return &ExcelReader{layout: DefaultHeaderLayout()}
 }
 
 // Init 初始化读取器
 func (r *ExcelReader) Init(config map[string]interface{}) error {
+	layout, err := ParseHeaderLayout(config)
+	if err != nil {
+		return err
+	}
+
 	r.config = config
+	r.layout = layout
 	return nil
 }
 
 // ReadAll 读取所有数据表
 func (r *ExcelReader) ReadAll(filePath string) ([]*model.DataSheet, error) {
+	return r.ReadAllContext(context.Background(), filePath)
+}
+
+// ReadAllContext 读取所有数据表，ctx 取消时在工作表之间和重新计算公式时中止
+func (r *ExcelReader) ReadAllContext(ctx context.Context, filePath string) ([]*model.DataSheet, error) {
 	// 打开Excel文件
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
@@ -45,7 +58,10 @@ func (r *ExcelReader) ReadAll(filePath string) ([]*model.DataSheet, error) {
 			continue
 		}
 
-		sheet, err := r.readSheet(f, sheetName)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sheet, err := r.readSheet(ctx, f, sheetName)
 		if err != nil {
 			return nil, err
 		}
@@ -75,87 +91,86 @@ func (r *ExcelReader) ReadSheet(filePath string, sheetName string) (*model.DataS
 		sheetName = sheetNames[0]
 	}
 
-	return r.readSheet(f, sheetName)
+	return r.readSheet(context.Background(), f, sheetName)
 }
 
 // readSheet 读取单个工作表
-func (r *ExcelReader) readSheet(f *excelize.File, sheetName string) (*model.DataSheet, error) {
+func (r *ExcelReader) readSheet(ctx context.Context, f *excelize.File, sheetName string) (*model.DataSheet, error) {
 	// 获取工作表的所有行
 	rows, err := f.GetRows(sheetName)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(rows) < 3 { // 至少需要表头、类型、注释行
-		return nil, nil
-	}
-
-	// 解析列信息
-	columns := make([]model.ColumnInfo, 0)
-	headerRow := rows[0]
-	typeRow := rows[1]
-	commentRow := rows[2]
-
-	for i, name := range headerRow {
-		if name == "" {
-			continue // 跳过空列
-		}
-
-		colInfo := model.ColumnInfo{
-			Name:     name,
-			Comment:  commentRow[i],
-			Required: true,
+	// 重新计算公式单元格，避免使用过期的缓存值
+	if evaluate, ok := r.config["evaluateFormulas"].(bool); ok && evaluate {
+		if rows, err = r.evaluateFormulas(ctx, f, sheetName, rows); err != nil {
+			return nil, err
 		}
+	}
 
-		// 解析类型
-		colType := typeRow[i]
-		colInfo.Type = colType
-
-		// 解析注释中的元数据
-		colInfo = r.parseCommentMetadata(colInfo, commentRow[i])
+	sheet, err := parseGrid(sheetName, rows, r.layout, r.convertValue)
+	if err != nil || sheet == nil {
+		return sheet, err
+	}
+	// 批注和条件格式只在差异报告等需要时读取
+	if annotate, ok := r.config["readAnnotations"].(bool); ok && annotate {
+		readAnnotations(f, sheetName, rows, r.layout, sheet)
+	}
+	return sheet, nil
+}
 
-		columns = append(columns, colInfo)
+// evaluateFormulas 使用公式计算结果替换单元格的缓存值
+//
+// GetRows 会裁掉空行和行末的空单元格，没有缓存结果的公式单元格因此可能不在 rows 中；
+// 这里逐行遍历工作表中实际存在的单元格，不依赖工作表声明的数据范围（可能远大于实际数据），并在需要时补齐行列
+func (r *ExcelReader) evaluateFormulas(ctx context.Context, f *excelize.File, sheetName string, rows [][]string) ([][]string, error) {
+	sheetRows, err := f.Rows(sheetName)
+	if err != nil {
+		return nil, err
 	}
+	defer sheetRows.Close()
 
-	// 解析数据行
-	dataRows := make([]map[string]interface{}, 0)
-	for rowIndex := 3; rowIndex < len(rows); rowIndex++ {
-		row := rows[rowIndex]
-		if len(row) == 0 || row[0] == "" {
-			continue // 跳过空行
+	for rowIndex := 0; sheetRows.Next(); rowIndex++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-
-		rowData := make(map[string]interface{})
-		for i, col := range columns {
-			var cellValue string
-			if i < len(row) {
-				cellValue = row[i]
+		cells, err := sheetRows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: 读取第 %d 行失败: %v", sheetName, rowIndex+1, err)
+		}
+		for colIndex := range cells {
+			cell, err := excelize.CoordinatesToCellName(colIndex+1, rowIndex+1)
+			if err != nil {
+				return nil, err
 			}
 
-			if cellValue == "" {
-				rowData[col.Name] = col.Default
+			formula, err := f.GetCellFormula(sheetName, cell)
+			if err != nil {
+				return nil, fmt.Errorf("sheet %s, cell %s: %v", sheetName, cell, err)
+			}
+			if formula == "" {
 				continue
 			}
 
-			// 转换数据类型
-			convertedValue, err := r.convertValue(cellValue, col.Type)
+			value, err := f.CalcCellValue(sheetName, cell)
 			if err != nil {
-				return nil, fmt.Errorf("sheet %s, row %d, column %s: %v", sheetName, rowIndex+1, col.Name, err)
+				return nil, fmt.Errorf("sheet %s, cell %s: 公式 %s 计算失败: %v", sheetName, cell, formula, err)
+			}
+
+			for len(rows) <= rowIndex {
+				rows = append(rows, nil)
 			}
-			rowData[col.Name] = convertedValue
+			for len(rows[rowIndex]) <= colIndex {
+				rows[rowIndex] = append(rows[rowIndex], "")
+			}
+			rows[rowIndex][colIndex] = value
 		}
-		dataRows = append(dataRows, rowData)
 	}
-
-	// 创建数据表
-	sheet := &model.DataSheet{
-		Name:    sheetName,
-		Columns: columns,
-		Rows:    dataRows,
-		Meta:    make(map[string]interface{}),
+	if err := sheetRows.Error(); err != nil {
+		return nil, err
 	}
-
-	return sheet, nil
+	return rows, nil
 }
 
 // GetSupportedFormats 获取支持的文件格式
@@ -163,37 +178,6 @@ func (r *ExcelReader) GetSupportedFormats() []string {
 	return []string{".xlsx", ".xlsm", ".xltx", ".xltm"}
 }
 
-// parseCommentMetadata 解析注释中的元数据
-func (r *ExcelReader) parseCommentMetadata(col model.ColumnInfo, comment string) model.ColumnInfo {
-	// 示例注释格式："必填|默认:0|选项:a,b,c|引用:table.column"
-	parts := strings.Split(comment, "|")
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if strings.HasPrefix(part, "必填") {
-			col.Required = true
-		} else if strings.HasPrefix(part, "选填") {
-			col.Required = false
-		} else if strings.HasPrefix(part, "默认:") {
-			defaultVal := strings.TrimPrefix(part, "默认:")
-			val, _ := r.convertValue(defaultVal, col.Type)
-			col.Default = val
-		} else if strings.HasPrefix(part, "选项:") {
-			optionsStr := strings.TrimPrefix(part, "选项:")
-			col.Options = strings.Split(optionsStr, ",")
-		} else if strings.HasPrefix(part, "引用:") {
-			refStr := strings.TrimPrefix(part, "引用:")
-			refParts := strings.Split(refStr, ".")
-			if len(refParts) == 2 {
-				col.Ref = &model.RefInfo{
-					Sheet:  refParts[0],
-					Column: refParts[1],
-				}
-			}
-		}
-	}
-	return col
-}
-
 // convertValue 转换数据类型
 func (r *ExcelReader) convertValue(value string, dataType string) (interface{}, error) {
 	// 这是一个简化的实现，实际项目中可能需要更复杂的类型转换
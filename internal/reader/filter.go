@@ -0,0 +1,73 @@
+package reader
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/expr"
+	"github.com/game-data-builder/internal/model"
+)
+
+// FilterRows 按 filters.json 去除表中满足任一过滤条件的行，返回每张表去除的行数（只包含去除了行的表）
+//
+// 表名完全相同和通配符匹配的条件都会应用；保留的行记录原来的行号，验证错误仍指向源文件中的位置
+func FilterRows(sheets []*model.DataSheet, cfg *config.FiltersConfig) (map[string]int, error) {
+	if cfg == nil || len(cfg.Filters) == 0 {
+		return nil, nil
+	}
+
+	patterns := make([]string, 0, len(cfg.Filters))
+	compiled := make(map[string][]*expr.Expr, len(cfg.Filters))
+	for pattern, conditions := range cfg.Filters {
+		patterns = append(patterns, pattern)
+		for _, condition := range conditions {
+			e, err := expr.Compile(condition)
+			if err != nil {
+				return nil, fmt.Errorf("filters.json: %s 的过滤条件无效: %v", pattern, err)
+			}
+			compiled[pattern] = append(compiled[pattern], e)
+		}
+	}
+	sort.Strings(patterns)
+
+	filtered := make(map[string]int)
+	for _, sheet := range sheets {
+		var conditions []*expr.Expr
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, sheet.Name); matched {
+				conditions = append(conditions, compiled[pattern]...)
+			}
+		}
+		if len(conditions) == 0 {
+			continue
+		}
+
+		rows := make([]map[string]interface{}, 0, len(sheet.Rows))
+		rowNumbers := make([]int, 0, len(sheet.Rows))
+		for rowIndex, row := range sheet.Rows {
+			drop := false
+			for _, condition := range conditions {
+				matched, err := condition.EvalBool(row)
+				if err != nil {
+					return nil, fmt.Errorf("sheet %s, row %d: %v", sheet.Name, sheet.RowNumber(rowIndex), err)
+				}
+				if matched {
+					drop = true
+					break
+				}
+			}
+			if !drop {
+				rows = append(rows, row)
+				rowNumbers = append(rowNumbers, sheet.RowNumber(rowIndex))
+			}
+		}
+		if len(rows) < len(sheet.Rows) {
+			filtered[sheet.Name] = len(sheet.Rows) - len(rows)
+			sheet.Rows = rows
+			sheet.RowNumbers = rowNumbers
+		}
+	}
+	return filtered, nil
+}
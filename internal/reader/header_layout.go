@@ -0,0 +1,286 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// 表头行角色
+const (
+	RoleName       = "name"       // 列名
+	RoleType       = "type"       // 数据类型
+	RoleComment    = "comment"    // 注释（可包含元数据）
+	RoleTag        = "tag"        // 列标签，逗号分隔
+	RoleDefault    = "default"    // 默认值
+	RoleValidation = "validation" // 校验元数据，格式同注释元数据
+	RoleMeta       = "meta"       // 表元数据，每个单元格为 key:value，如 extends:BaseMonster
+	RoleSkip       = "skip"       // 忽略的行（如横幅、说明）
+)
+
+// HeaderLayout 表头布局，描述数据前每一行的角色
+type HeaderLayout struct {
+	SkipRows int      // 表头前需要跳过的横幅行数
+	Roles    []string // 表头各行的角色
+}
+
+// DefaultHeaderLayout 默认表头布局：列名、类型、注释
+func DefaultHeaderLayout() *HeaderLayout {
+	return &HeaderLayout{Roles: []string{RoleName, RoleType, RoleComment}}
+}
+
+// ParseHeaderLayout 从读取器选项（skipRows、headerLayout）解析表头布局
+func ParseHeaderLayout(config map[string]interface{}) (*HeaderLayout, error) {
+	layout := DefaultHeaderLayout()
+
+	if skipRows, ok := config["skipRows"].(float64); ok {
+		if skipRows < 0 {
+			return nil, fmt.Errorf("skipRows 不能为负数: %v", skipRows)
+		}
+		layout.SkipRows = int(skipRows)
+	}
+
+	rawRoles, exists := config["headerLayout"]
+	if !exists {
+		return layout, nil
+	}
+
+	roleList, ok := rawRoles.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("headerLayout 必须是角色数组")
+	}
+
+	roles := make([]string, 0, len(roleList))
+	hasName := false
+	for _, rawRole := range roleList {
+		role, ok := rawRole.(string)
+		if !ok {
+			return nil, fmt.Errorf("headerLayout 角色必须是字符串: %v", rawRole)
+		}
+
+		role = strings.ToLower(strings.TrimSpace(role))
+		switch role {
+		case RoleName:
+			hasName = true
+		case RoleType, RoleComment, RoleTag, RoleDefault, RoleValidation, RoleMeta, RoleSkip:
+		default:
+			return nil, fmt.Errorf("未知的表头行角色: %s", role)
+		}
+		roles = append(roles, role)
+	}
+
+	if !hasName {
+		return nil, fmt.Errorf("headerLayout 必须包含 name 行")
+	}
+
+	layout.Roles = roles
+	return layout, nil
+}
+
+// HeaderRows 表头总行数（含跳过的横幅行）
+func (l *HeaderLayout) HeaderRows() int {
+	return l.SkipRows + len(l.Roles)
+}
+
+// row 获取指定角色对应的行，不存在时返回nil
+func (l *HeaderLayout) row(rows [][]string, role string) []string {
+	for i, r := range l.Roles {
+		if r == role && l.SkipRows+i < len(rows) {
+			return rows[l.SkipRows+i]
+		}
+	}
+	return nil
+}
+
+// valueConverter 将单元格文本转换为指定类型的值
+type valueConverter func(value string, dataType string) (interface{}, error)
+
+// cellAt 安全地获取单元格内容
+func cellAt(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// parseGrid 按表头布局将单元格矩阵解析为数据表
+func parseGrid(sheetName string, grid [][]string, layout *HeaderLayout, convert valueConverter) (*model.DataSheet, error) {
+	if len(grid) < layout.HeaderRows() { // 至少需要完整的表头
+		return nil, nil
+	}
+
+	// 解析列信息
+	columns := make([]model.ColumnInfo, 0)
+	columnIndexes := make([]int, 0)
+	columnConverters := make([]valueConverter, 0)
+	headerRow := layout.row(grid, RoleName)
+	typeRow := layout.row(grid, RoleType)
+	commentRow := layout.row(grid, RoleComment)
+	tagRow := layout.row(grid, RoleTag)
+	defaultRow := layout.row(grid, RoleDefault)
+	validationRow := layout.row(grid, RoleValidation)
+
+	for i, name := range headerRow {
+		if name == "" {
+			continue // 跳过空列
+		}
+
+		// 解析类型行中的列转换，如 int|*1000
+		dataType, transforms := splitColumnType(cellAt(typeRow, i))
+		transform, err := parseColumnTransform(dataType, transforms)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s, column %s: %v", sheetName, name, err)
+		}
+		columnConvert := transform.wrap(convert)
+
+		colInfo := model.ColumnInfo{
+			Name:     name,
+			Type:     dataType,
+			Comment:  cellAt(commentRow, i),
+			Required: true,
+		}
+		if len(transforms) > 0 {
+			colInfo.Transforms = transforms
+		}
+
+		// 解析注释与校验行中的元数据
+		colInfo = parseCommentMetadata(colInfo, colInfo.Comment, columnConvert)
+		colInfo = parseCommentMetadata(colInfo, cellAt(validationRow, i), columnConvert)
+
+		// 解析标签
+		if tags := cellAt(tagRow, i); tags != "" {
+			for _, tag := range strings.Split(tags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					colInfo.Tags = append(colInfo.Tags, tag)
+				}
+			}
+		}
+
+		// 解析默认值
+		if defaultVal := cellAt(defaultRow, i); defaultVal != "" {
+			val, err := columnConvert(defaultVal, colInfo.Type)
+			if err != nil {
+				return nil, fmt.Errorf("sheet %s, column %s: 默认值无效: %v", sheetName, name, err)
+			}
+			colInfo.Default = val
+		}
+
+		columns = append(columns, colInfo)
+		columnIndexes = append(columnIndexes, i)
+		columnConverters = append(columnConverters, columnConvert)
+	}
+
+	// 检查嵌套列是否与普通列冲突，如同时存在 reward 与 reward.count
+	columnNames := make(map[string]bool)
+	for _, col := range columns {
+		columnNames[col.Name] = true
+	}
+	for _, col := range columns {
+		parts := strings.Split(col.Name, ".")
+		for i := 1; i < len(parts); i++ {
+			if prefix := strings.Join(parts[:i], "."); columnNames[prefix] {
+				return nil, fmt.Errorf("sheet %s: 嵌套列 %s 与列 %s 冲突", sheetName, col.Name, prefix)
+			}
+		}
+	}
+
+	// 解析数据行
+	dataStartRow := layout.HeaderRows()
+	dataRows := make([]map[string]interface{}, 0)
+	rowNumbers := make([]int, 0)
+	skipped := false
+	for rowIndex := dataStartRow; rowIndex < len(grid); rowIndex++ {
+		row := grid[rowIndex]
+		if len(row) == 0 || row[0] == "" {
+			skipped = true
+			continue // 跳过空行
+		}
+		rowNumbers = append(rowNumbers, rowIndex+1)
+
+		rowData := make(map[string]interface{})
+		for i, col := range columns {
+			cellValue := cellAt(row, columnIndexes[i])
+			if cellValue == "" {
+				model.SetRowValue(rowData, col.Name, col.Default)
+				continue
+			}
+
+			// 转换数据类型
+			convertedValue, err := columnConverters[i](cellValue, col.Type)
+			if err != nil {
+				return nil, fmt.Errorf("sheet %s, row %d, column %s: %v", sheetName, rowIndex+1, col.Name, err)
+			}
+			model.SetRowValue(rowData, col.Name, convertedValue)
+		}
+		dataRows = append(dataRows, rowData)
+	}
+
+	// 创建数据表
+	sheet := &model.DataSheet{
+		Name:         sheetName,
+		Columns:      columns,
+		Rows:         dataRows,
+		Meta:         make(map[string]interface{}),
+		DataStartRow: dataStartRow + 1,
+	}
+	if skipped {
+		sheet.RowNumbers = rowNumbers // 跳过了空行，后面的行号不再连续
+	}
+
+	// 解析表元数据
+	for _, cell := range layout.row(grid, RoleMeta) {
+		key, value, found := strings.Cut(strings.TrimSpace(cell), ":")
+		if !found || key == "" {
+			continue
+		}
+		sheet.Meta[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	// 设置主键列
+	for _, col := range columns {
+		if col.IsKey {
+			if sheet.KeyColumn != "" {
+				return nil, fmt.Errorf("sheet %s: 主键列 %s 与 %s 重复", sheetName, sheet.KeyColumn, col.Name)
+			}
+			sheet.KeyColumn = col.Name
+		}
+	}
+
+	return sheet, nil
+}
+
+// parseCommentMetadata 解析注释中的元数据
+func parseCommentMetadata(col model.ColumnInfo, comment string, convert valueConverter) model.ColumnInfo {
+	// 示例注释格式："主键|必填|默认:0|选项:a,b,c|引用:table.column|资源:prefab"，主键也可以写作 key
+	parts := strings.Split(comment, "|")
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "主键" || strings.EqualFold(part, "key") {
+			col.IsKey = true
+		} else if strings.HasPrefix(part, "必填") {
+			col.Required = true
+		} else if strings.HasPrefix(part, "选填") {
+			col.Required = false
+		} else if strings.HasPrefix(part, "默认:") {
+			defaultVal := strings.TrimPrefix(part, "默认:")
+			val, _ := convert(defaultVal, col.Type)
+			col.Default = val
+		} else if strings.HasPrefix(part, "选项:") {
+			optionsStr := strings.TrimPrefix(part, "选项:")
+			col.Options = strings.Split(optionsStr, ",")
+		} else if strings.HasPrefix(part, "引用:") {
+			// 表名可以带命名空间，如 引用:shop.item.id，最后一段为列名
+			refStr := strings.TrimPrefix(part, "引用:")
+			if index := strings.LastIndex(refStr, "."); index > 0 && index < len(refStr)-1 {
+				col.Ref = &model.RefInfo{
+					Sheet:  refStr[:index],
+					Column: refStr[index+1:],
+				}
+			}
+		} else if strings.HasPrefix(part, "资源:") {
+			col.Asset = strings.TrimSpace(strings.TrimPrefix(part, "资源:"))
+		}
+	}
+	return col
+}
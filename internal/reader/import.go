@@ -0,0 +1,224 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+)
+
+// outputSheet JSON 转换器输出文件的结构
+type outputSheet struct {
+	Name    string                 `json:"name"`
+	Columns []model.ColumnInfo     `json:"columns"`
+	Rows    json.RawMessage        `json:"rows"`
+	Meta    map[string]interface{} `json:"meta"`
+}
+
+// ParseOutputSheet 解析 JSON 转换器生成的表，rowsAsMap 输出的按主键组织的行也能识别
+// 整数列中的数字还原为 int，与从源文件读取的表一致
+func ParseOutputSheet(content []byte) (*model.DataSheet, error) {
+	var out outputSheet
+	if err := json.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+	if out.Name == "" || out.Columns == nil {
+		return nil, fmt.Errorf("不是 JSON 转换器生成的表")
+	}
+
+	sheet := &model.DataSheet{Name: out.Name, Columns: out.Columns, Meta: out.Meta}
+	for _, column := range out.Columns {
+		if column.IsKey {
+			sheet.KeyColumn = column.Name
+			break
+		}
+	}
+
+	rows, err := decodeOutputRows(out.Rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for _, column := range out.Columns {
+			if column.Type != "int" && column.Type != "integer" {
+				continue
+			}
+			if value, ok := row[column.Name].(float64); ok && value == float64(int(value)) {
+				row[column.Name] = int(value)
+			}
+		}
+	}
+	sheet.Rows = rows
+	return sheet, nil
+}
+
+// decodeOutputRows 解析行数据，支持数组和按主键组织的对象两种形式
+func decodeOutputRows(raw json.RawMessage) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0)
+	if len(raw) == 0 || string(raw) == "null" {
+		return rows, nil
+	}
+	if raw[0] == '[' {
+		err := json.Unmarshal(raw, &rows)
+		return rows, err
+	}
+
+	// 按主键组织的对象保持文件中的顺序
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	for decoder.More() {
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ImportSource 其他项目的构建输出，通过其 version.json 定位并校验共享表
+type ImportSource struct {
+	from   string       // 输出目录或 HTTP(S) 地址
+	policy *RetryPolicy // 远程获取的重试与降级策略
+	cached []string     // 不可用而使用了缓存内容的远程地址
+}
+
+// NewImportSource 创建导入源，options 为远程获取的重试选项
+func NewImportSource(from string, options map[string]interface{}) (*ImportSource, error) {
+	policy, err := ParseRetryPolicy(options)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportSource{from: from, policy: policy}, nil
+}
+
+// isRemote 是否为 HTTP(S) 地址
+func (s *ImportSource) isRemote() bool {
+	return strings.HasPrefix(s.from, "http://") || strings.HasPrefix(s.from, "https://")
+}
+
+// fetch 获取输出中相对路径对应的文件
+func (s *ImportSource) fetch(ctx context.Context, relPath string) ([]byte, error) {
+	if !s.isRemote() {
+		return os.ReadFile(filepath.Join(s.from, filepath.FromSlash(relPath)))
+	}
+
+	base, err := url.Parse(s.from)
+	if err != nil {
+		return nil, err
+	}
+	base.Path = path.Join(base.Path, relPath)
+	source := base.String()
+
+	content, fromCache, err := s.policy.FetchContext(ctx, source, func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		return io.ReadAll(resp.Body)
+	})
+	if fromCache {
+		s.cached = append(s.cached, source)
+	}
+	return content, err
+}
+
+// CachedSources 读取过程中不可用、使用了上次缓存内容的远程地址
+func (s *ImportSource) CachedSources() []string {
+	return s.cached
+}
+
+// ReadSheets 读取导入的表，pins 为表名到固定数据版本的映射，版本为 0 时使用对方的最新版本
+// 文件内容必须与对方 version.json 中记录的 SHA-256 一致
+func (s *ImportSource) ReadSheets(pins map[string]int) ([]*model.DataSheet, error) {
+	return s.ReadSheetsContext(context.Background(), pins)
+}
+
+// ReadSheetsContext 与 ReadSheets 相同，ctx 取消时中止远程请求
+func (s *ImportSource) ReadSheetsContext(ctx context.Context, pins map[string]int) ([]*model.DataSheet, error) {
+	content, err := s.fetch(ctx, output.VersionFileName)
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %v", output.VersionFileName, err)
+	}
+	version := &output.VersionFile{}
+	if err := json.Unmarshal(content, version); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %v", output.VersionFileName, err)
+	}
+
+	names := make([]string, 0, len(pins))
+	for name := range pins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sheets := make([]*model.DataSheet, 0, len(pins))
+	for _, name := range names {
+		pin := pins[name]
+		current, exists := version.Sheets[name]
+		if pin != 0 && (!exists || current != pin) {
+			return nil, fmt.Errorf("表 %s 的版本为 %d，与固定的版本 %d 不一致", name, current, pin)
+		}
+
+		sheet, err := s.readSheet(ctx, version, name)
+		if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// readSheet 在版本文件中查找表的 JSON 输出并校验后解析
+func (s *ImportSource) readSheet(ctx context.Context, version *output.VersionFile, name string) (*model.DataSheet, error) {
+	// 带命名空间的表输出在命名空间子目录中，如 shop.item -> json/shop/item.json
+	suffix := "/" + filepath.ToSlash(model.SheetPath(name)) + ".json"
+	for _, entry := range version.Files {
+		if !strings.HasSuffix("/"+entry.Path, suffix) {
+			continue
+		}
+
+		content, err := s.fetch(ctx, entry.Path)
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %v", entry.Path, err)
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return nil, fmt.Errorf("%s 的内容与 %s 中的哈希不一致", entry.Path, output.VersionFileName)
+		}
+
+		sheet, err := ParseOutputSheet(content)
+		if err != nil {
+			continue // 同名的 .json 文件不一定是 JSON 转换器的输出
+		}
+		if sheet.Name == name {
+			return sheet, nil
+		}
+	}
+	return nil, fmt.Errorf("没有表 %s 的 JSON 输出", name)
+}
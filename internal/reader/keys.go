@@ -0,0 +1,68 @@
+package reader
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// ResolveKeys 应用 sheets.json 中配置的主键列，覆盖注释中的主键标记
+//
+// 与表名完全相同的配置优先；多个通配符配置匹配同一张表且主键不同时报错
+func ResolveKeys(sheets []*model.DataSheet, cfg *config.SheetsConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for _, sheet := range sheets {
+		key, err := configuredKey(sheet.Name, cfg)
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			continue
+		}
+
+		found := false
+		for i := range sheet.Columns {
+			sheet.Columns[i].IsKey = sheet.Columns[i].Name == key
+			found = found || sheet.Columns[i].IsKey
+		}
+		if !found {
+			return fmt.Errorf("sheet %s: sheets.json 中配置的主键列 %s 不存在", sheet.Name, key)
+		}
+		sheet.KeyColumn = key
+	}
+	return nil
+}
+
+// configuredKey 查找表在 sheets.json 中配置的主键列，未配置时返回空
+func configuredKey(name string, cfg *config.SheetsConfig) (string, error) {
+	if settings, exists := cfg.Sheets[name]; exists && settings.Key != "" {
+		return settings.Key, nil
+	}
+
+	patterns := make([]string, 0, len(cfg.Sheets))
+	for pattern := range cfg.Sheets {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	key, keyPattern := "", ""
+	for _, pattern := range patterns {
+		settings := cfg.Sheets[pattern]
+		if settings.Key == "" {
+			continue
+		}
+		if matched, _ := path.Match(pattern, name); !matched {
+			continue
+		}
+		if key != "" && key != settings.Key {
+			return "", fmt.Errorf("sheet %s: sheets.json 中 %s 和 %s 配置的主键 %s、%s 不一致", name, keyPattern, pattern, key, settings.Key)
+		}
+		key, keyPattern = settings.Key, pattern
+	}
+	return key, nil
+}
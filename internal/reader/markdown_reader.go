@@ -0,0 +1,291 @@
+package reader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// markdownDelimiterCell 表格分隔行的单元格，如 ---、:--:
+var markdownDelimiterCell = regexp.MustCompile(`^:?-+:?$`)
+
+// MarkdownReader Markdown 表格读取器，读取设计文档中带有元数据注释的 GitHub 风格表格
+//
+// 表格的表头为列名，紧挨在表格之前的 HTML 注释给出类型等信息，没有元数据注释的表格是普通文档内容，不会被读取：
+//
+//	<!-- table: items
+//	types:    | int    | string | float |
+//	comments: | ID\|主键 | 名称   | 价格  |
+//	meta:     | tags:ui |
+//	-->
+//	| id | name  | price |
+//	|----|-------|-------|
+//	| 1  | sword | 9.5   |
+type MarkdownReader struct {
+	config map[string]interface{}
+}
+
+// markdownTable 文档中的一个数据表格
+type markdownTable struct {
+	name     string
+	line     int // 表头所在行（从 1 开始）
+	metadata map[string][]string
+	header   []string
+	rows     map[int][]string // 行号（从 1 开始）-> 单元格
+	lastLine int
+}
+
+// NewMarkdownReader 创建 Markdown 表格读取器
+func NewMarkdownReader() *MarkdownReader {
+	return &MarkdownReader{}
+}
+
+// Init 初始化读取器，表头固定为列名、类型和注释，不使用 headerLayout 和 skipRows
+func (r *MarkdownReader) Init(config map[string]interface{}) error {
+	r.config = config
+	return nil
+}
+
+// ReadAll 读取文档中的所有数据表格
+func (r *MarkdownReader) ReadAll(filePath string) ([]*model.DataSheet, error) {
+	tables, err := readMarkdownTables(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := make([]*model.DataSheet, 0, len(tables))
+	for _, table := range tables {
+		sheet, err := table.sheet(r.convertValue)
+		if err != nil {
+			return nil, err
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// ReadSheet 读取指定名称的表格，未指定时读取第一个表格
+func (r *MarkdownReader) ReadSheet(filePath string, sheetName string) (*model.DataSheet, error) {
+	tables, err := readMarkdownTables(filePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if sheetName == "" || table.name == sheetName {
+			return table.sheet(r.convertValue)
+		}
+	}
+	return nil, nil
+}
+
+// GetSupportedFormats 获取支持的文件格式
+func (r *MarkdownReader) GetSupportedFormats() []string {
+	return []string{".md", ".MD", ".markdown"}
+}
+
+// convertValue 转换数据类型，与 CSV 读取器一致
+func (r *MarkdownReader) convertValue(value string, dataType string) (interface{}, error) {
+	switch dataType {
+	case "int", "integer":
+		return strconv.Atoi(value)
+	case "float", "double", "number":
+		return strconv.ParseFloat(value, 64)
+	case "bool", "boolean":
+		return strconv.ParseBool(value)
+	case "string":
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// readMarkdownTables 读取文档中带有元数据注释的表格，代码块中的内容被忽略
+func readMarkdownTables(filePath string) ([]*markdownTable, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	defaultName := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+
+	tables := make([]*markdownTable, 0)
+	names := make(map[string]int)
+	var pending map[string][]string // 等待表格的元数据
+	pendingLine := 0
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		// 跳过代码块
+		if fence != "" {
+			if strings.HasPrefix(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			fence = line[:3]
+			continue
+		}
+
+		if strings.HasPrefix(line, "<!--") {
+			start := i
+			text := strings.TrimPrefix(line, "<!--")
+			for !strings.Contains(text, "-->") {
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("第 %d 行: 注释没有结束", start+1)
+				}
+				text += "\n" + lines[i]
+			}
+			text, _, _ = strings.Cut(text, "-->")
+			if metadata := parseMarkdownMetadata(text); metadata != nil {
+				if pending != nil {
+					return nil, fmt.Errorf("第 %d 行: 元数据注释后没有表格", pendingLine)
+				}
+				pending, pendingLine = metadata, start+1
+			}
+			continue
+		}
+
+		if line == "" || pending == nil {
+			continue
+		}
+		if !strings.Contains(line, "|") || i+1 >= len(lines) || !isMarkdownDelimiterRow(lines[i+1]) {
+			return nil, fmt.Errorf("第 %d 行: 元数据注释后没有表格", pendingLine)
+		}
+
+		table := &markdownTable{
+			name:     defaultName,
+			line:     i + 1,
+			metadata: pending,
+			header:   splitMarkdownRow(line),
+			rows:     make(map[int][]string),
+		}
+		if name := pending["table"]; len(name) > 0 && name[0] != "" {
+			table.name = name[0]
+		}
+		if previous, exists := names[table.name]; exists {
+			return nil, fmt.Errorf("第 %d 行: 表 %s 与第 %d 行的表格重名，请用 table: 指定表名", table.line, table.name, previous)
+		}
+		names[table.name] = table.line
+
+		// 表格在空行或不含 | 的行处结束
+		i += 2
+		for ; i < len(lines); i++ {
+			row := strings.TrimSpace(lines[i])
+			if row == "" || !strings.Contains(row, "|") {
+				break
+			}
+			cells := splitMarkdownRow(row)
+			if len(cells) > len(table.header) {
+				return nil, fmt.Errorf("第 %d 行: 单元格数量 %d 多于表头的 %d 列", i+1, len(cells), len(table.header))
+			}
+			table.rows[i+1] = cells
+			table.lastLine = i + 1
+		}
+		i--
+		tables = append(tables, table)
+		pending = nil
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("第 %d 行: 元数据注释后没有表格", pendingLine)
+	}
+	return tables, nil
+}
+
+// parseMarkdownMetadata 解析注释中的元数据，每行为 key: value，table 为表名，types、comments、meta 为按表格行书写的单元格；
+// 不含 types 的注释是普通注释，返回 nil
+func parseMarkdownMetadata(text string) map[string][]string {
+	metadata := make(map[string][]string)
+	for _, line := range strings.Split(text, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		switch key = strings.ToLower(strings.TrimSpace(key)); key {
+		case "table":
+			metadata[key] = []string{strings.TrimSpace(value)}
+		case "types", "comments", "meta":
+			metadata[key] = splitMarkdownRow(value)
+		}
+	}
+	if _, exists := metadata["types"]; !exists {
+		return nil
+	}
+	return metadata
+}
+
+// isMarkdownDelimiterRow 是否为表头下的分隔行
+func isMarkdownDelimiterRow(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.Contains(line, "-") {
+		return false
+	}
+	for _, cell := range splitMarkdownRow(line) {
+		if !markdownDelimiterCell.MatchString(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitMarkdownRow 拆分表格行的单元格，去掉首尾的 | 和单元格两侧的空白，\| 表示单元格中的 |
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	cells := make([]string, 0)
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// sheet 将表格转换为数据表：单元格矩阵的行号与文档行号一致，错误信息和数据行号指向文档中的行
+func (t *markdownTable) sheet(convert valueConverter) (*model.DataSheet, error) {
+	types := t.metadata["types"]
+	if len(types) != len(t.header) {
+		return nil, fmt.Errorf("第 %d 行: 表 %s 的 types 有 %d 列，表头有 %d 列", t.line, t.name, len(types), len(t.header))
+	}
+
+	// 前三行为列名、类型和注释；元数据注释在表格之前，表格的数据行至少从第 4 行开始
+	grid := make([][]string, max(t.lastLine, 3))
+	grid[0] = t.header
+	grid[1] = types
+	grid[2] = t.metadata["comments"]
+	for line, cells := range t.rows {
+		grid[line-1] = cells
+	}
+
+	sheet, err := parseGrid(t.name, grid, DefaultHeaderLayout(), convert)
+	if err != nil {
+		return nil, err
+	}
+	sheet.DataStartRow = t.line + 2
+	for _, cell := range t.metadata["meta"] {
+		key, value, found := strings.Cut(cell, ":")
+		if found && strings.TrimSpace(key) != "" {
+			sheet.Meta[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return sheet, nil
+}
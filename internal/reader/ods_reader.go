@@ -0,0 +1,313 @@
+package reader
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// OpenDocument 命名空间
+const (
+	odsOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsTableNS  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsTextNS   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+)
+
+// ODSReader OpenDocument 电子表格（.ods）读取器，表头约定与 xlsx 相同，公式单元格使用文件中保存的计算结果
+type ODSReader struct {
+	config map[string]interface{}
+	layout *HeaderLayout
+}
+
+// odsSheet ods 文件中的一个工作表
+type odsSheet struct {
+	name string
+	rows [][]string
+}
+
+// NewODSReader 创建 ods 读取器
+func NewODSReader() *ODSReader {
+	return &ODSReader{layout: DefaultHeaderLayout()}
+}
+
+// Init 初始化读取器
+func (r *ODSReader) Init(config map[string]interface{}) error {
+	layout, err := ParseHeaderLayout(config)
+	if err != nil {
+		return err
+	}
+
+	r.config = config
+	r.layout = layout
+	return nil
+}
+
+// ReadAll 读取所有数据表
+func (r *ODSReader) ReadAll(filePath string) ([]*model.DataSheet, error) {
+	return r.ReadAllContext(context.Background(), filePath)
+}
+
+// ReadAllContext 读取所有数据表，ctx 取消时在工作表之间中止
+func (r *ODSReader) ReadAllContext(ctx context.Context, filePath string) ([]*model.DataSheet, error) {
+	tables, err := readODS(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := make([]*model.DataSheet, 0)
+	for _, table := range tables {
+		// 跳过以_开头的工作表（隐藏表）
+		if strings.HasPrefix(table.name, "_") {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		sheet, err := parseGrid(table.name, table.rows, r.layout, r.convertValue)
+		if err != nil {
+			return nil, err
+		}
+		if sheet != nil {
+			sheets = append(sheets, sheet)
+		}
+	}
+
+	return sheets, nil
+}
+
+// ReadSheet 读取指定工作表
+func (r *ODSReader) ReadSheet(filePath string, sheetName string) (*model.DataSheet, error) {
+	tables, err := readODS(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+
+	// 如果未指定工作表名，使用第一个工作表
+	if sheetName == "" {
+		return parseGrid(tables[0].name, tables[0].rows, r.layout, r.convertValue)
+	}
+	for _, table := range tables {
+		if table.name == sheetName {
+			return parseGrid(table.name, table.rows, r.layout, r.convertValue)
+		}
+	}
+	return nil, nil
+}
+
+// GetSupportedFormats 获取支持的文件格式
+func (r *ODSReader) GetSupportedFormats() []string {
+	return []string{".ods", ".ODS"}
+}
+
+// convertValue 转换数据类型，与 xlsx 读取器一致
+func (r *ODSReader) convertValue(value string, dataType string) (interface{}, error) {
+	switch strings.ToLower(dataType) {
+	case "int", "integer":
+		return strconv.Atoi(value)
+	case "float", "double", "number":
+		return strconv.ParseFloat(value, 64)
+	case "bool", "boolean":
+		value = strings.ToLower(value)
+		if value == "true" || value == "1" || value == "yes" {
+			return true, nil
+		}
+		return false, nil
+	case "string":
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// readODS 读取 ods 文件中 content.xml 的所有工作表
+func readODS(filePath string) ([]odsSheet, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的 ods 文件: %v", err)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.Name != "content.xml" {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer content.Close()
+		return parseODSContent(content)
+	}
+	return nil, fmt.Errorf("ods 文件中没有 content.xml")
+}
+
+// parseODSContent 解析 content.xml，返回与 excelize GetRows 相同形式的二维文本
+//
+// 重复的空行和空单元格（table:number-rows-repeated 等）只在其后还有内容时才展开，末尾的空行和空单元格被去掉
+func parseODSContent(r io.Reader) ([]odsSheet, error) {
+	decoder := xml.NewDecoder(r)
+	sheets := make([]odsSheet, 0)
+
+	var sheet *odsSheet
+	var row []string
+	emptyRows, emptyCells, rowRepeat := 0, 0, 1
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 content.xml 失败: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Space != odsTableNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "table":
+				sheets = append(sheets, odsSheet{name: odsAttr(t, odsTableNS, "name"), rows: make([][]string, 0)})
+				sheet = &sheets[len(sheets)-1]
+				emptyRows = 0
+			case "table-row":
+				row = make([]string, 0)
+				emptyCells = 0
+				rowRepeat = odsRepeat(t, "number-rows-repeated")
+			case "table-cell", "covered-table-cell":
+				repeat := odsRepeat(t, "number-columns-repeated")
+				value, err := odsCellValue(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				if value == "" {
+					emptyCells += repeat
+					continue
+				}
+				for ; emptyCells > 0; emptyCells-- {
+					row = append(row, "")
+				}
+				for i := 0; i < repeat; i++ {
+					row = append(row, value)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Space != odsTableNS || sheet == nil {
+				continue
+			}
+			switch t.Name.Local {
+			case "table-row":
+				if len(row) == 0 {
+					emptyRows += rowRepeat
+					continue
+				}
+				for ; emptyRows > 0; emptyRows-- {
+					sheet.rows = append(sheet.rows, []string{})
+				}
+				for i := 0; i < rowRepeat; i++ {
+					sheet.rows = append(sheet.rows, append([]string(nil), row...))
+				}
+			case "table":
+				sheet = nil
+			}
+		}
+	}
+	return sheets, nil
+}
+
+// odsAttr 获取元素的属性值
+func odsAttr(element xml.StartElement, space, local string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsRepeat 行或单元格的重复次数，未指定时为 1
+func odsRepeat(element xml.StartElement, local string) int {
+	repeat, err := strconv.Atoi(odsAttr(element, odsTableNS, local))
+	if err != nil || repeat < 1 {
+		return 1
+	}
+	return repeat
+}
+
+// odsCellValue 读取单元格的值并消费到单元格结束：数值使用未经格式化的原始值，布尔值为 TRUE/FALSE，
+// 日期和时间为 ISO 8601 文本，其他类型使用单元格中的文本，多个段落以换行连接
+func odsCellValue(decoder *xml.Decoder, cell xml.StartElement) (string, error) {
+	switch odsAttr(cell, odsOfficeNS, "value-type") {
+	case "float", "percentage", "currency":
+		value, err := strconv.ParseFloat(odsAttr(cell, odsOfficeNS, "value"), 64)
+		if err != nil {
+			return "", fmt.Errorf("单元格数值 %q 无效", odsAttr(cell, odsOfficeNS, "value"))
+		}
+		return formatXLSNumber(value), decoder.Skip()
+	case "boolean":
+		if odsAttr(cell, odsOfficeNS, "boolean-value") == "true" {
+			return "TRUE", decoder.Skip()
+		}
+		return "FALSE", decoder.Skip()
+	case "date":
+		return odsAttr(cell, odsOfficeNS, "date-value"), decoder.Skip()
+	case "time":
+		return odsAttr(cell, odsOfficeNS, "time-value"), decoder.Skip()
+	}
+
+	var builder strings.Builder
+	paragraphs, depth := 0, 0 // depth 为当前所在的段落层数，段落之外的空白不是单元格的内容
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("解析单元格失败: %v", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsOfficeNS && t.Name.Local == "annotation":
+				// 单元格批注不是单元格的值
+				if err := decoder.Skip(); err != nil {
+					return "", err
+				}
+			case t.Name.Space == odsTextNS && t.Name.Local == "p":
+				if paragraphs > 0 {
+					builder.WriteString("\n")
+				}
+				paragraphs++
+				depth++
+			case t.Name.Space == odsTextNS && t.Name.Local == "s":
+				count, err := strconv.Atoi(odsAttr(t, odsTextNS, "c"))
+				if err != nil || count < 1 {
+					count = 1
+				}
+				builder.WriteString(strings.Repeat(" ", count))
+			case t.Name.Space == odsTextNS && t.Name.Local == "tab":
+				builder.WriteString("\t")
+			case t.Name.Space == odsTextNS && t.Name.Local == "line-break":
+				builder.WriteString("\n")
+			}
+		case xml.CharData:
+			if depth > 0 {
+				builder.Write(t)
+			}
+		case xml.EndElement:
+			if t.Name.Space == odsTextNS && t.Name.Local == "p" {
+				depth--
+			}
+			if t.Name == cell.Name {
+				return builder.String(), nil
+			}
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package reader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// pbtxt 值的种类
+const (
+	pbtxtString  = iota // 字符串，相邻的字符串字面量已拼接
+	pbtxtNumber         // 数值，保留原文以便按列类型解析
+	pbtxtIdent          // 标识符，如 true、枚举成员名
+	pbtxtMessage        // 消息 { ... } 或 < ... >
+)
+
+// pbtxtValue Protobuf 文本格式中的一个值
+type pbtxtValue struct {
+	kind   int
+	text   string        // 字符串的内容、数值或标识符的原文
+	fields []*pbtxtField // 消息的字段
+	line   int
+}
+
+// pbtxtField 一个字段，列表写法 f: [1, 2] 的字段有多个值
+type pbtxtField struct {
+	name   string
+	values []*pbtxtValue
+	list   bool // 是否使用了列表写法
+	line   int
+	pos    int // 字段名在文件中的序号，用于按书写顺序排列列
+}
+
+// pbtxtToken 词法单元
+type pbtxtToken struct {
+	kind int    // pbtxtString、pbtxtNumber、pbtxtIdent 或 pbtxtPunct
+	text string // 字符串已去掉引号并处理转义
+	line int
+}
+
+// pbtxtPunct 标点，与值的种类一起使用
+const pbtxtPunct = -1
+
+// pbtxtParser Protobuf 文本格式（textproto）解析器，不需要 .proto 定义
+type pbtxtParser struct {
+	tokens []pbtxtToken
+	pos    int
+}
+
+// parsePbtxt 解析整个文件，返回顶层字段
+func parsePbtxt(content string) ([]*pbtxtField, error) {
+	tokens, err := pbtxtTokenize(content)
+	if err != nil {
+		return nil, err
+	}
+	p := &pbtxtParser{tokens: tokens}
+	fields, err := p.parseFields("")
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// peek 当前词法单元，到达末尾时返回空标点
+func (p *pbtxtParser) peek() pbtxtToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	line := 1
+	if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return pbtxtToken{kind: pbtxtPunct, line: line}
+}
+
+// next 读取当前词法单元
+func (p *pbtxtParser) next() pbtxtToken {
+	tok := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return tok
+}
+
+// isPunct 当前词法单元是否为指定标点
+func (p *pbtxtParser) isPunct(text string) bool {
+	tok := p.peek()
+	return tok.kind == pbtxtPunct && tok.text == text
+}
+
+// parseFields 解析字段直到遇到结束符，顶层的结束符为空（文件末尾）
+func (p *pbtxtParser) parseFields(end string) ([]*pbtxtField, error) {
+	fields := make([]*pbtxtField, 0)
+	for {
+		tok := p.peek()
+		if tok.kind == pbtxtPunct && tok.text == end {
+			if end != "" {
+				p.next()
+			}
+			return fields, nil
+		}
+		if tok.kind == pbtxtPunct && tok.text == "" {
+			return nil, fmt.Errorf("第 %d 行: 缺少 %s", tok.line, end)
+		}
+		if tok.kind == pbtxtPunct && tok.text == "[" {
+			return nil, fmt.Errorf("第 %d 行: 不支持扩展字段和 Any", tok.line)
+		}
+		if tok.kind != pbtxtIdent {
+			return nil, fmt.Errorf("第 %d 行: 期望字段名，实际为 %q", tok.line, tok.text)
+		}
+		pos := p.pos
+		p.next()
+
+		field, err := p.parseFieldValue(tok, pos)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+
+		// 字段之间可以用逗号或分号分隔
+		if p.isPunct(",") || p.isPunct(";") {
+			p.next()
+		}
+	}
+}
+
+// parseFieldValue 解析字段名之后的部分：标量前必须有冒号，消息前的冒号可以省略
+func (p *pbtxtParser) parseFieldValue(name pbtxtToken, pos int) (*pbtxtField, error) {
+	field := &pbtxtField{name: name.text, line: name.line, pos: pos}
+	colon := p.isPunct(":")
+	if colon {
+		p.next()
+	}
+
+	if p.isPunct("[") {
+		p.next()
+		field.list = true
+		for !p.isPunct("]") {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			field.values = append(field.values, value)
+			if p.isPunct(",") {
+				p.next()
+			} else if !p.isPunct("]") {
+				return nil, fmt.Errorf("第 %d 行: 列表 %s 缺少 ]", p.peek().line, name.text)
+			}
+		}
+		p.next()
+		return field, nil
+	}
+
+	if !colon && !p.isPunct("{") && !p.isPunct("<") {
+		return nil, fmt.Errorf("第 %d 行: 字段 %s 后缺少冒号", name.line, name.text)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	field.values = []*pbtxtValue{value}
+	return field, nil
+}
+
+// parseValue 解析一个值
+func (p *pbtxtParser) parseValue() (*pbtxtValue, error) {
+	tok := p.next()
+	switch tok.kind {
+	case pbtxtString:
+		// 相邻的字符串字面量拼接为一个字符串
+		text := tok.text
+		for p.peek().kind == pbtxtString {
+			text += p.next().text
+		}
+		return &pbtxtValue{kind: pbtxtString, text: text, line: tok.line}, nil
+	case pbtxtNumber, pbtxtIdent:
+		return &pbtxtValue{kind: tok.kind, text: tok.text, line: tok.line}, nil
+	}
+
+	end := map[string]string{"{": "}", "<": ">"}[tok.text]
+	if end == "" {
+		if tok.text == "" {
+			return nil, fmt.Errorf("第 %d 行: 缺少值", tok.line)
+		}
+		return nil, fmt.Errorf("第 %d 行: 期望值，实际为 %q", tok.line, tok.text)
+	}
+	fields, err := p.parseFields(end)
+	if err != nil {
+		return nil, err
+	}
+	return &pbtxtValue{kind: pbtxtMessage, fields: fields, line: tok.line}, nil
+}
+
+// pbtxtTokenize 词法分析，# 开始的内容为注释
+func pbtxtTokenize(content string) ([]pbtxtToken, error) {
+	tokens := make([]pbtxtToken, 0)
+	line := 1
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case c == '#':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case strings.IndexByte("{}<>[]:,;", c) >= 0:
+			tokens = append(tokens, pbtxtToken{kind: pbtxtPunct, text: string(c), line: line})
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := pbtxtUnquote(content[i:])
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %v", line, err)
+			}
+			tokens = append(tokens, pbtxtToken{kind: pbtxtString, text: text, line: line})
+			i += n
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			start := i
+			i++
+			for i < len(content) {
+				ch := content[i]
+				isExponentSign := (ch == '+' || ch == '-') && (content[i-1] == 'e' || content[i-1] == 'E') && !strings.HasPrefix(strings.ToLower(strings.TrimPrefix(content[start:i], "-")), "0x")
+				if !pbtxtIdentChar(ch) && ch != '.' && !isExponentSign {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, pbtxtToken{kind: pbtxtNumber, text: content[start:i], line: line})
+		case pbtxtIdentChar(c):
+			start := i
+			for i < len(content) && pbtxtIdentChar(content[i]) {
+				i++
+			}
+			tokens = append(tokens, pbtxtToken{kind: pbtxtIdent, text: content[start:i], line: line})
+		default:
+			r, _ := utf8.DecodeRuneInString(content[i:])
+			return nil, fmt.Errorf("第 %d 行: 无法识别的字符 %q", line, r)
+		}
+	}
+	return tokens, nil
+}
+
+// pbtxtIdentChar 标识符中可以出现的字符
+func pbtxtIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// pbtxtUnquote 解析以引号开始的字符串字面量，返回内容和字面量的长度，支持 C 风格的转义
+func pbtxtUnquote(s string) (string, int, error) {
+	quote := s[0]
+	var builder strings.Builder
+	for i := 1; i < len(s); {
+		switch c := s[i]; c {
+		case quote:
+			return builder.String(), i + 1, nil
+		case '\n':
+			return "", 0, fmt.Errorf("字符串中不能直接换行")
+		case '\\':
+			if strings.HasPrefix(s[i:], `\?`) {
+				builder.WriteByte('?')
+				i += 2
+				continue
+			}
+			value, multibyte, tail, err := strconv.UnquoteChar(s[i:], quote)
+			if err != nil {
+				return "", 0, fmt.Errorf("无效的转义 %s", s[i:min(i+4, len(s))])
+			}
+			if value < utf8.RuneSelf || !multibyte {
+				builder.WriteByte(byte(value))
+			} else {
+				builder.WriteRune(value)
+			}
+			i = len(s) - len(tail)
+		default:
+			builder.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, fmt.Errorf("字符串缺少结束引号")
+}
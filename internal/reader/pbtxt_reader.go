@@ -0,0 +1,526 @@
+package reader
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// pbtxt 文件顶层支持的字段
+const (
+	pbtxtFieldColumn = "column" // 列定义（可选），未定义的列按值推断类型
+	pbtxtFieldRow    = "row"    // 一行数据
+	pbtxtFieldMeta   = "meta"   // 表元数据 { key: "..." value: "..." }
+)
+
+// PbtxtReader Protobuf 文本格式（.pbtxt / .textproto）读取器，适合工程师手写的开发覆盖配置
+//
+// 每个文件是一张表，表名为文件名；顶层的 row 为数据行，字段即列，嵌套消息展开为 reward.itemId 形式的嵌套列，
+// 重复字段为 list 列；column 可以显式定义列的类型、注释、主键等，未定义的列按值推断类型且为选填
+type PbtxtReader struct {
+	config map[string]interface{}
+}
+
+// NewPbtxtReader 创建pbtxt读取器
+func NewPbtxtReader() *PbtxtReader {
+	return &PbtxtReader{}
+}
+
+// Init 初始化读取器
+func (r *PbtxtReader) Init(config map[string]interface{}) error {
+	r.config = config
+	return nil
+}
+
+// ReadAll 读取所有数据表
+func (r *PbtxtReader) ReadAll(filePath string) ([]*model.DataSheet, error) {
+	// pbtxt 文件只有一张表
+	sheet, err := r.ReadSheet(filePath, "")
+	if err != nil {
+		return nil, err
+	}
+	if sheet == nil {
+		return []*model.DataSheet{}, nil // 空文件不包含数据表
+	}
+	return []*model.DataSheet{sheet}, nil
+}
+
+// ReadSheet 读取指定工作表
+func (r *PbtxtReader) ReadSheet(filePath string, sheetName string) (*model.DataSheet, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parsePbtxt(string(content))
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	tableName := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	return buildPbtxtSheet(tableName, fields)
+}
+
+// GetSupportedFormats 获取支持的文件格式
+func (r *PbtxtReader) GetSupportedFormats() []string {
+	return []string{".pbtxt", ".textproto"}
+}
+
+// pbtxtCell 一行中某列的原始值
+type pbtxtCell struct {
+	values []*pbtxtValue
+	list   bool // 重复字段或列表写法
+	pos    int  // 首次出现的位置
+}
+
+// buildPbtxtSheet 将顶层字段组装为数据表
+func buildPbtxtSheet(sheetName string, fields []*pbtxtField) (*model.DataSheet, error) {
+	sheet := &model.DataSheet{
+		Name:         sheetName,
+		Columns:      make([]model.ColumnInfo, 0),
+		Rows:         make([]map[string]interface{}, 0),
+		Meta:         make(map[string]interface{}),
+		DataStartRow: 1, // 行号即第几个 row
+	}
+
+	// 先收集显式定义的列和元数据
+	declared := make(map[string]int)
+	rawRows := make([]map[string]*pbtxtCell, 0)
+	for _, field := range fields {
+		for _, value := range field.values {
+			if value.kind != pbtxtMessage {
+				return nil, fmt.Errorf("第 %d 行: %s 必须是消息", value.line, field.name)
+			}
+			switch field.name {
+			case pbtxtFieldColumn:
+				col, err := pbtxtColumn(value)
+				if err != nil {
+					return nil, err
+				}
+				if _, exists := declared[col.Name]; exists {
+					return nil, fmt.Errorf("第 %d 行: 列 %s 重复定义", value.line, col.Name)
+				}
+				declared[col.Name] = len(sheet.Columns)
+				sheet.Columns = append(sheet.Columns, col)
+				if col.IsKey {
+					if sheet.KeyColumn != "" {
+						return nil, fmt.Errorf("第 %d 行: 主键列 %s 与 %s 重复", value.line, sheet.KeyColumn, col.Name)
+					}
+					sheet.KeyColumn = col.Name
+				}
+			case pbtxtFieldRow:
+				cells := make(map[string]*pbtxtCell)
+				if err := flattenPbtxtRow(value.fields, "", sheet.Columns, declared, cells); err != nil {
+					return nil, err
+				}
+				rawRows = append(rawRows, cells)
+			case pbtxtFieldMeta:
+				key, err := pbtxtMetaField(value, "key")
+				if err != nil {
+					return nil, err
+				}
+				metaValue, err := pbtxtMetaField(value, "value")
+				if err != nil {
+					return nil, err
+				}
+				if key == "" {
+					return nil, fmt.Errorf("第 %d 行: meta 缺少 key", value.line)
+				}
+				sheet.Meta[key] = metaValue
+			default:
+				return nil, fmt.Errorf("第 %d 行: 未知的字段 %s，顶层只支持 column、row 和 meta", field.line, field.name)
+			}
+		}
+	}
+
+	// 未定义的列按首次出现的顺序追加，类型由所有行的值推断
+	inferred := make(map[string]int)
+	for _, cells := range rawRows {
+		for _, name := range pbtxtCellOrder(cells) {
+			if _, exists := declared[name]; exists {
+				continue
+			}
+			cell := cells[name]
+			typ, err := inferPbtxtType(cell)
+			if err != nil {
+				return nil, fmt.Errorf("列 %s: %v", name, err)
+			}
+			index, exists := inferred[name]
+			if !exists {
+				inferred[name] = len(sheet.Columns)
+				sheet.Columns = append(sheet.Columns, model.ColumnInfo{Name: name, Type: typ})
+				continue
+			}
+			merged, ok := mergePbtxtType(sheet.Columns[index].Type, typ)
+			if !ok {
+				return nil, fmt.Errorf("第 %d 行: 列 %s 的值类型 %s 与之前的 %s 不一致", cell.values[0].line, name, typ, sheet.Columns[index].Type)
+			}
+			sheet.Columns[index].Type = merged
+		}
+	}
+
+	// 检查嵌套列是否与普通列冲突，如同时存在 reward 与 reward.count
+	for _, col := range sheet.Columns {
+		for other := range declared {
+			if strings.HasPrefix(other, col.Name+".") {
+				return nil, fmt.Errorf("嵌套列 %s 与列 %s 冲突", other, col.Name)
+			}
+		}
+		for other := range inferred {
+			if strings.HasPrefix(other, col.Name+".") {
+				return nil, fmt.Errorf("嵌套列 %s 与列 %s 冲突", other, col.Name)
+			}
+		}
+	}
+
+	// 按列类型转换各行的值，缺少的列使用默认值
+	for i, cells := range rawRows {
+		row := make(map[string]interface{})
+		for _, col := range sheet.Columns {
+			cell, exists := cells[col.Name]
+			if !exists {
+				model.SetRowValue(row, col.Name, col.Default)
+				continue
+			}
+			value, err := pbtxtCellValue(cell, col.Type)
+			if err != nil {
+				return nil, fmt.Errorf("sheet %s, row %d, column %s: %v", sheetName, i+1, col.Name, err)
+			}
+			model.SetRowValue(row, col.Name, value)
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet, nil
+}
+
+// pbtxtColumn 解析列定义 { name type comment key required default options ref }
+func pbtxtColumn(value *pbtxtValue) (model.ColumnInfo, error) {
+	col := model.ColumnInfo{Required: true}
+	var defaultValue *pbtxtValue
+	for _, field := range value.fields {
+		for _, item := range field.values {
+			var err error
+			switch field.name {
+			case "name":
+				col.Name, err = pbtxtText(item)
+			case "type":
+				col.Type, err = pbtxtText(item)
+			case "comment":
+				col.Comment, err = pbtxtText(item)
+			case "key":
+				col.IsKey, err = pbtxtBool(item)
+			case "required":
+				col.Required, err = pbtxtBool(item)
+			case "default":
+				defaultValue = item
+			case "options":
+				var option string
+				option, err = pbtxtText(item)
+				col.Options = append(col.Options, option)
+			case "ref":
+				var ref string
+				if ref, err = pbtxtText(item); err == nil {
+					index := strings.LastIndex(ref, ".")
+					if index <= 0 || index == len(ref)-1 {
+						return col, fmt.Errorf("第 %d 行: 引用 %s 应为 表名.列名", item.line, ref)
+					}
+					col.Ref = &model.RefInfo{Sheet: ref[:index], Column: ref[index+1:]}
+				}
+			case "asset":
+				col.Asset, err = pbtxtText(item)
+			default:
+				return col, fmt.Errorf("第 %d 行: column 不支持字段 %s", field.line, field.name)
+			}
+			if err != nil {
+				return col, fmt.Errorf("第 %d 行: column 的 %s: %v", item.line, field.name, err)
+			}
+		}
+	}
+
+	if col.Name == "" {
+		return col, fmt.Errorf("第 %d 行: column 缺少 name", value.line)
+	}
+	if col.Type == "" {
+		col.Type = "string"
+	}
+	if defaultValue != nil {
+		val, err := pbtxtScalar(defaultValue, col.Type)
+		if err != nil {
+			return col, fmt.Errorf("第 %d 行: 列 %s 的默认值无效: %v", defaultValue.line, col.Name, err)
+		}
+		col.Default = val
+	}
+	return col, nil
+}
+
+// flattenPbtxtRow 将行中的字段展开为以列名为键的原始值：嵌套消息展开为嵌套列，重复字段和显式定义为列表的列保持为一个值
+func flattenPbtxtRow(fields []*pbtxtField, prefix string, columns []model.ColumnInfo, declared map[string]int, cells map[string]*pbtxtCell) error {
+	for _, field := range fields {
+		name := prefix + field.name
+		cell, exists := cells[name]
+		if !exists {
+			cell = &pbtxtCell{pos: field.pos}
+		}
+		cell.values = append(cell.values, field.values...)
+		cell.list = cell.list || field.list || exists
+		cells[name] = cell
+	}
+
+	for _, name := range pbtxtCellOrder(cells) {
+		cell := cells[name]
+		if !strings.HasPrefix(name, prefix) || strings.Contains(strings.TrimPrefix(name, prefix), ".") {
+			continue
+		}
+		if cell.list || len(cell.values) != 1 || cell.values[0].kind != pbtxtMessage {
+			continue
+		}
+		if index, exists := declared[name]; exists && isPbtxtListType(columns[index].Type) {
+			continue
+		}
+
+		// 单个消息展开为嵌套列
+		delete(cells, name)
+		if err := flattenPbtxtRow(cell.values[0].fields, name+".", columns, declared, cells); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pbtxtCellOrder 按书写顺序排列的列名
+func pbtxtCellOrder(cells map[string]*pbtxtCell) []string {
+	names := make([]string, 0, len(cells))
+	for name := range cells {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return cells[names[i]].pos < cells[names[j]].pos
+	})
+	return names
+}
+
+// isPbtxtListType 是否为列表类型的列
+func isPbtxtListType(colType string) bool {
+	lower := strings.ToLower(colType)
+	return lower == "list" || lower == "array" || strings.HasSuffix(lower, "[]")
+}
+
+// inferPbtxtType 根据值推断列类型
+func inferPbtxtType(cell *pbtxtCell) (string, error) {
+	if cell.list || len(cell.values) != 1 {
+		return "list", nil
+	}
+	value := cell.values[0]
+	switch value.kind {
+	case pbtxtNumber:
+		if _, err := strconv.ParseInt(value.text, 0, 64); err == nil {
+			return "int", nil
+		}
+		return "float", nil
+	case pbtxtIdent:
+		if _, ok := pbtxtBoolValue(value.text); ok {
+			return "bool", nil
+		}
+		if _, ok := pbtxtFloatIdent(value.text); ok {
+			return "float", nil
+		}
+		return "string", nil
+	case pbtxtMessage:
+		return "map", nil
+	default:
+		return "string", nil
+	}
+}
+
+// mergePbtxtType 合并不同行推断出的类型，整数与小数合并为 float
+func mergePbtxtType(a, b string) (string, bool) {
+	if a == b {
+		return a, true
+	}
+	if (a == "int" && b == "float") || (a == "float" && b == "int") {
+		return "float", true
+	}
+	return "", false
+}
+
+// pbtxtCellValue 按列类型转换一列的原始值
+func pbtxtCellValue(cell *pbtxtCell, colType string) (interface{}, error) {
+	lower := strings.ToLower(colType)
+	if !isPbtxtListType(colType) && lower != "map" && lower != "object" && lower != "json" {
+		if cell.list || len(cell.values) != 1 {
+			return nil, fmt.Errorf("%s 类型的列不能有多个值", colType)
+		}
+		return pbtxtScalar(cell.values[0], colType)
+	}
+
+	if !isPbtxtListType(colType) {
+		return pbtxtGeneric(cell.values[0])
+	}
+	elemType := strings.TrimSuffix(colType, "[]")
+	items := make([]interface{}, 0, len(cell.values))
+	for _, value := range cell.values {
+		var item interface{}
+		var err error
+		if elemType != colType {
+			item, err = pbtxtScalar(value, elemType)
+		} else {
+			item, err = pbtxtGeneric(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// pbtxtGeneric 按值本身的种类转换，用于 list、map 等结构化的列
+func pbtxtGeneric(value *pbtxtValue) (interface{}, error) {
+	if value.kind != pbtxtMessage {
+		typ, err := inferPbtxtType(&pbtxtCell{values: []*pbtxtValue{value}})
+		if err != nil {
+			return nil, err
+		}
+		return pbtxtScalar(value, typ)
+	}
+
+	cells := make(map[string]*pbtxtCell)
+	for _, field := range value.fields {
+		cell, exists := cells[field.name]
+		if !exists {
+			cell = &pbtxtCell{pos: field.pos}
+			cells[field.name] = cell
+		}
+		cell.values = append(cell.values, field.values...)
+		cell.list = cell.list || field.list || exists
+	}
+	result := make(map[string]interface{}, len(cells))
+	for name, cell := range cells {
+		typ, err := inferPbtxtType(cell)
+		if err != nil {
+			return nil, err
+		}
+		item, err := pbtxtCellValue(cell, typ)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		result[name] = item
+	}
+	return result, nil
+}
+
+// pbtxtScalar 按列类型转换标量值
+func pbtxtScalar(value *pbtxtValue, colType string) (interface{}, error) {
+	if value.kind == pbtxtMessage {
+		return nil, fmt.Errorf("第 %d 行: %s 类型的值不能是消息", value.line, colType)
+	}
+
+	switch strings.ToLower(colType) {
+	case "int", "integer":
+		if value.kind == pbtxtString {
+			return strconv.Atoi(value.text)
+		}
+		if value.kind != pbtxtNumber {
+			return nil, fmt.Errorf("第 %d 行: %s 不是整数", value.line, value.text)
+		}
+		n, err := strconv.ParseInt(value.text, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %s 不是整数", value.line, value.text)
+		}
+		return int(n), nil
+	case "float", "double", "number":
+		text := value.text
+		if value.kind == pbtxtIdent {
+			f, ok := pbtxtFloatIdent(text)
+			if !ok {
+				return nil, fmt.Errorf("第 %d 行: %s 不是数值", value.line, text)
+			}
+			return f, nil
+		}
+		if value.kind == pbtxtNumber && !strings.HasPrefix(strings.ToLower(strings.TrimPrefix(text, "-")), "0x") {
+			text = strings.TrimRight(text, "fF")
+		}
+		if n, err := strconv.ParseInt(text, 0, 64); err == nil && value.kind == pbtxtNumber {
+			return float64(n), nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %s 不是数值", value.line, value.text)
+		}
+		return f, nil
+	case "bool", "boolean":
+		if value.kind == pbtxtString {
+			return strconv.ParseBool(value.text)
+		}
+		if b, ok := pbtxtBoolValue(value.text); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("第 %d 行: %s 不是布尔值", value.line, value.text)
+	default:
+		return value.text, nil
+	}
+}
+
+// pbtxtBoolValue 文本格式中的布尔值写法
+func pbtxtBoolValue(text string) (bool, bool) {
+	switch text {
+	case "true", "True", "t", "1":
+		return true, true
+	case "false", "False", "f", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// pbtxtFloatIdent 以标识符书写的特殊浮点数 inf、nan
+func pbtxtFloatIdent(text string) (float64, bool) {
+	switch strings.ToLower(strings.TrimPrefix(text, "-")) {
+	case "inf", "infinity":
+		if strings.HasPrefix(text, "-") {
+			return math.Inf(-1), true
+		}
+		return math.Inf(1), true
+	case "nan":
+		return math.NaN(), true
+	}
+	return 0, false
+}
+
+// pbtxtText 字符串或标识符的文本
+func pbtxtText(value *pbtxtValue) (string, error) {
+	if value.kind == pbtxtMessage {
+		return "", fmt.Errorf("不能是消息")
+	}
+	return value.text, nil
+}
+
+// pbtxtBool 布尔字段
+func pbtxtBool(value *pbtxtValue) (bool, error) {
+	b, err := pbtxtScalar(value, "bool")
+	if err != nil {
+		return false, err
+	}
+	return b.(bool), nil
+}
+
+// pbtxtMetaField 读取消息中字符串字段的值，字段不存在时为空
+func pbtxtMetaField(message *pbtxtValue, name string) (string, error) {
+	for _, field := range message.fields {
+		if field.name == name && len(field.values) > 0 {
+			text, err := pbtxtText(field.values[0])
+			if err != nil {
+				return "", fmt.Errorf("第 %d 行: %s %v", field.line, name, err)
+			}
+			return text, nil
+		}
+	}
+	return "", nil
+}
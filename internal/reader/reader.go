@@ -1,6 +1,8 @@
 package reader
 
 import (
+	"context"
+
 	"github.com/game-data-builder/internal/model"
 )
 
@@ -18,3 +20,20 @@ type IReader interface {
 	// GetSupportedFormats 获取支持的文件格式
 	GetSupportedFormats() []string
 }
+
+// IContextReader 可选接口，大文件或远程数据源的读取器通过它支持取消，ctx 取消后应尽快返回 ctx 的错误
+type IContextReader interface {
+	// ReadAllContext 读取所有数据表
+	ReadAllContext(ctx context.Context, filePath string) ([]*model.DataSheet, error)
+}
+
+// ReadAllContext 读取文件中的所有数据表，读取器支持取消时传入 ctx
+func ReadAllContext(ctx context.Context, r IReader, filePath string) ([]*model.DataSheet, error) {
+	if contextReader, ok := r.(IContextReader); ok {
+		return contextReader.ReadAllContext(ctx, filePath)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.ReadAll(filePath)
+}
@@ -16,8 +16,13 @@ func NewReaderFactory() *ReaderFactory {
 	}
 
 	// 注册默认读取器
-	factory.RegisterReader(&CSVReader{})
-	factory.RegisterReader(&ExcelReader{})
+	factory.RegisterReader(NewCSVReader())
+	factory.RegisterReader(NewDelimitedReader())
+	factory.RegisterReader(NewExcelReader())
+	factory.RegisterReader(NewXLSReader())
+	factory.RegisterReader(NewODSReader())
+	factory.RegisterReader(NewPbtxtReader())
+	factory.RegisterReader(NewMarkdownReader())
 
 	return factory
 }
@@ -47,8 +52,18 @@ func (f *ReaderFactory) CreateReader(filePath string, config map[string]interfac
 	switch reader.(type) {
 	case *CSVReader:
 		newReader = NewCSVReader()
+	case *DelimitedReader:
+		newReader = NewDelimitedReader()
 	case *ExcelReader:
 		newReader = NewExcelReader()
+	case *XLSReader:
+		newReader = NewXLSReader()
+	case *ODSReader:
+		newReader = NewODSReader()
+	case *PbtxtReader:
+		newReader = NewPbtxtReader()
+	case *MarkdownReader:
+		newReader = NewMarkdownReader()
 	default:
 		return nil, nil
 	}
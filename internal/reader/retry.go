@@ -0,0 +1,139 @@
+package reader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/game-data-builder/internal/logger"
+)
+
+// DefaultSourceCacheDir 远程数据源缓存的默认目录
+const DefaultSourceCacheDir = ".builder-cache/sources"
+
+// RetryPolicy 远程数据源（HTTP、数据库等）的重试与降级策略
+type RetryPolicy struct {
+	Attempts        int           // 最大尝试次数
+	Backoff         time.Duration // 首次重试前的等待时间，之后每次翻倍
+	MaxBackoff      time.Duration // 最大等待时间
+	Timeout         time.Duration // 单次请求超时
+	FallbackToCache bool          // 全部失败时是否使用上次成功的缓存
+	CacheDir        string        // 缓存目录
+}
+
+// ParseRetryPolicy 从读取器选项解析重试策略
+//
+// 选项：retryAttempts、retryBackoffMs、retryMaxBackoffMs、timeoutMs、fallbackToCache、cacheDir
+func ParseRetryPolicy(config map[string]interface{}) (*RetryPolicy, error) {
+	policy := &RetryPolicy{
+		Attempts:   3,
+		Backoff:    500 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+		Timeout:    30 * time.Second,
+		CacheDir:   DefaultSourceCacheDir,
+	}
+
+	if attempts, ok := config["retryAttempts"].(float64); ok {
+		if attempts < 1 {
+			return nil, fmt.Errorf("retryAttempts 至少为1: %v", attempts)
+		}
+		policy.Attempts = int(attempts)
+	}
+	if backoff, ok := config["retryBackoffMs"].(float64); ok {
+		policy.Backoff = time.Duration(backoff) * time.Millisecond
+	}
+	if maxBackoff, ok := config["retryMaxBackoffMs"].(float64); ok {
+		policy.MaxBackoff = time.Duration(maxBackoff) * time.Millisecond
+	}
+	if timeout, ok := config["timeoutMs"].(float64); ok {
+		policy.Timeout = time.Duration(timeout) * time.Millisecond
+	}
+	if fallback, ok := config["fallbackToCache"].(bool); ok {
+		policy.FallbackToCache = fallback
+	}
+	if cacheDir, ok := config["cacheDir"].(string); ok && cacheDir != "" {
+		policy.CacheDir = cacheDir
+	}
+
+	return policy, nil
+}
+
+// Fetch 按策略获取远程内容，成功时写入缓存；全部失败且允许降级时返回缓存内容，fromCache 为 true
+func (p *RetryPolicy) Fetch(source string, fetch func(ctx context.Context) ([]byte, error)) (content []byte, fromCache bool, err error) {
+	return p.FetchContext(context.Background(), source, fetch)
+}
+
+// FetchContext 与 Fetch 相同，ctx 取消时中止当前请求和重试等待，直接返回 ctx 的错误，不降级到缓存
+func (p *RetryPolicy) FetchContext(ctx context.Context, source string, fetch func(ctx context.Context) ([]byte, error)) (content []byte, fromCache bool, err error) {
+	backoff := p.Backoff
+	for attempt := 1; attempt <= p.Attempts; attempt++ {
+		content, err = p.fetchOnce(ctx, fetch)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, false, ctxErr
+		}
+		if err == nil {
+			if cacheErr := p.saveCache(source, content); cacheErr != nil {
+				logger.Warnf("缓存数据源 %s 失败: %v", source, cacheErr)
+			}
+			return content, false, nil
+		}
+
+		if attempt < p.Attempts {
+			logger.Warnf("读取数据源 %s 失败（第 %d/%d 次），%v 后重试: %v", source, attempt, p.Attempts, backoff, err)
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, false, ctx.Err()
+			case <-timer.C:
+			}
+			backoff *= 2
+			if backoff > p.MaxBackoff {
+				backoff = p.MaxBackoff
+			}
+		}
+	}
+
+	if !p.FallbackToCache {
+		return nil, false, fmt.Errorf("读取数据源 %s 失败（已重试 %d 次）: %v", source, p.Attempts, err)
+	}
+
+	cached, cacheErr := os.ReadFile(p.cachePath(source))
+	if cacheErr != nil {
+		return nil, false, fmt.Errorf("读取数据源 %s 失败且没有可用缓存: %v", source, err)
+	}
+
+	logger.Warnf("==================================================")
+	logger.Warnf("数据源 %s 不可用，使用上次成功获取的缓存数据！", source)
+	logger.Warnf("原因: %v", err)
+	logger.Warnf("==================================================")
+	return cached, true, nil
+}
+
+// fetchOnce 在超时限制内执行一次获取
+func (p *RetryPolicy) fetchOnce(ctx context.Context, fetch func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	return fetch(ctx)
+}
+
+// cachePath 数据源对应的缓存文件路径
+func (p *RetryPolicy) cachePath(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(p.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// saveCache 保存最近一次成功获取的内容
+func (p *RetryPolicy) saveCache(source string, content []byte) error {
+	if err := os.MkdirAll(p.CacheDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p.cachePath(source), content, 0644)
+}
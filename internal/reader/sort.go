@@ -0,0 +1,71 @@
+package reader
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// MetaSort 行排序的元数据键，值为逗号分隔的排序键，如 sort:-price,id
+const MetaSort = "sort"
+
+// ResolveSort 按 sheets.json 中的 sort 或表元数据中的 sort:（排序:）对表的行排序，使输出顺序不受策划调整表格行顺序的影响
+//
+// sheets.json 的配置优先，其中与表名完全相同的配置优先于通配符；多个通配符配置的排序键不一致时报错
+func ResolveSort(sheets []*model.DataSheet, cfg *config.SheetsConfig) error {
+	for _, sheet := range sheets {
+		specs, err := configuredSort(sheet.Name, cfg)
+		if err != nil {
+			return err
+		}
+		if specs == nil {
+			specs = ParseTags(metaString(sheet, MetaSort, "排序"))
+		}
+
+		keys := model.ParseSortKeys(specs)
+		for _, key := range keys {
+			if !containsColumn(sheet.Columns, key.Column) {
+				return fmt.Errorf("sheet %s: 排序列 %s 不存在", sheet.Name, key.Column)
+			}
+		}
+		model.SortSheet(sheet, keys)
+	}
+	return nil
+}
+
+// configuredSort 查找表在 sheets.json 中配置的排序键，未配置时返回 nil
+func configuredSort(name string, cfg *config.SheetsConfig) ([]string, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if settings, exists := cfg.Sheets[name]; exists && len(settings.Sort) > 0 {
+		return settings.Sort, nil
+	}
+
+	patterns := make([]string, 0, len(cfg.Sheets))
+	for pattern := range cfg.Sheets {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	var specs []string
+	specsPattern := ""
+	for _, pattern := range patterns {
+		settings := cfg.Sheets[pattern]
+		if len(settings.Sort) == 0 {
+			continue
+		}
+		if matched, _ := path.Match(pattern, name); !matched {
+			continue
+		}
+		if specs != nil && strings.Join(specs, ",") != strings.Join(settings.Sort, ",") {
+			return nil, fmt.Errorf("sheet %s: sheets.json 中 %s 和 %s 配置的排序不一致", name, specsPattern, pattern)
+		}
+		specs, specsPattern = settings.Sort, pattern
+	}
+	return specs, nil
+}
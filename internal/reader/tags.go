@@ -0,0 +1,65 @@
+package reader
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// MetaTags 表标签的元数据键，值为逗号分隔的标签，如 tags:battle,economy
+const MetaTags = "tags"
+
+// ParseTags 解析逗号分隔的标签，去除空白和重复项
+func ParseTags(text string) []string {
+	tags := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, tag := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '，' }) {
+		if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ResolveTags 合并表元数据和 sheets.json 中配置的标签，结果按名称排序
+func ResolveTags(sheets []*model.DataSheet, cfg *config.SheetsConfig) {
+	for _, sheet := range sheets {
+		tags := ParseTags(metaString(sheet, MetaTags, "标签"))
+		if cfg != nil {
+			for pattern, settings := range cfg.Sheets {
+				if matched, _ := path.Match(pattern, sheet.Name); matched {
+					tags = append(tags, settings.Tags...)
+				}
+			}
+		}
+		sheet.Tags = ParseTags(strings.Join(tags, ","))
+		sort.Strings(sheet.Tags)
+	}
+}
+
+// SelectTags 选出带有任一标签的表
+func SelectTags(sheets []*model.DataSheet, tags []string) []*model.DataSheet {
+	selected := make([]*model.DataSheet, 0)
+	for _, sheet := range sheets {
+		if HasAnyTag(sheet, tags) {
+			selected = append(selected, sheet)
+		}
+	}
+	return selected
+}
+
+// HasAnyTag 检查表是否带有任一标签
+func HasAnyTag(sheet *model.DataSheet, tags []string) bool {
+	for _, tag := range tags {
+		for _, own := range sheet.Tags {
+			if own == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// 模板相关的表元数据键
+const (
+	MetaExtends  = "extends"  // 继承的模板表名
+	MetaTemplate = "template" // 标记为模板表，值为 true
+)
+
+// ResolveTemplates 处理模板继承：子表继承模板表的列定义和默认值，模板表本身不参与输出
+func ResolveTemplates(sheets []*model.DataSheet) ([]*model.DataSheet, error) {
+	sheetMap := make(map[string]*model.DataSheet)
+	for _, sheet := range sheets {
+		sheetMap[sheet.Name] = sheet
+	}
+
+	resolved := make(map[string]bool)
+	for _, sheet := range sheets {
+		if err := resolveTemplate(sheet, sheetMap, resolved, make(map[string]bool)); err != nil {
+			return nil, err
+		}
+	}
+
+	dataSheets := make([]*model.DataSheet, 0, len(sheets))
+	for _, sheet := range sheets {
+		if !isTemplate(sheet) {
+			dataSheets = append(dataSheets, sheet)
+		}
+	}
+	return dataSheets, nil
+}
+
+// resolveTemplate 递归处理单个表的继承关系
+func resolveTemplate(sheet *model.DataSheet, sheetMap map[string]*model.DataSheet, resolved, visiting map[string]bool) error {
+	if resolved[sheet.Name] {
+		return nil
+	}
+
+	baseName := metaString(sheet, MetaExtends, "继承")
+	if baseName == "" {
+		resolved[sheet.Name] = true
+		return nil
+	}
+
+	if visiting[sheet.Name] {
+		return fmt.Errorf("sheet %s: 模板继承存在循环", sheet.Name)
+	}
+	visiting[sheet.Name] = true
+
+	base, exists := sheetMap[baseName]
+	if !exists {
+		return fmt.Errorf("sheet %s: 继承的模板表 %s 不存在", sheet.Name, baseName)
+	}
+	if err := resolveTemplate(base, sheetMap, resolved, visiting); err != nil {
+		return err
+	}
+
+	// 先放模板的列，子表同名列覆盖模板定义，其余子表列追加在后
+	ownColumns := make(map[string]model.ColumnInfo)
+	for _, col := range sheet.Columns {
+		ownColumns[col.Name] = col
+	}
+
+	columns := make([]model.ColumnInfo, 0, len(base.Columns)+len(sheet.Columns))
+	inherited := make(map[string]bool)
+	for _, col := range base.Columns {
+		if own, exists := ownColumns[col.Name]; exists {
+			columns = append(columns, own)
+		} else {
+			columns = append(columns, col)
+			inherited[col.Name] = true
+		}
+	}
+	for _, col := range sheet.Columns {
+		if !containsColumn(base.Columns, col.Name) {
+			columns = append(columns, col)
+		}
+	}
+	sheet.Columns = columns
+
+	// 子表中缺少的继承列使用模板的默认值
+	for _, row := range sheet.Rows {
+		for _, col := range base.Columns {
+			if !inherited[col.Name] {
+				continue
+			}
+			if _, exists := model.RowValue(row, col.Name); !exists {
+				model.SetRowValue(row, col.Name, col.Default)
+			}
+		}
+	}
+
+	if sheet.KeyColumn == "" {
+		sheet.KeyColumn = base.KeyColumn
+	}
+
+	resolved[sheet.Name] = true
+	return nil
+}
+
+// isTemplate 检查是否为模板表
+func isTemplate(sheet *model.DataSheet) bool {
+	value := metaString(sheet, MetaTemplate, "模板")
+	return value == "true" || value == "是"
+}
+
+// metaString 获取字符串形式的表元数据，依次尝试多个键
+func metaString(sheet *model.DataSheet, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := sheet.Meta[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// containsColumn 检查列是否存在
+func containsColumn(columns []model.ColumnInfo, name string) bool {
+	for _, col := range columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
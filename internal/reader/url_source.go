@@ -0,0 +1,182 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/logger"
+)
+
+// urlContentTypes 响应的内容类型对应的文件扩展名
+var urlContentTypes = map[string]string{
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": ".xlsx",
+	"application/vnd.ms-excel":                       ".xls",
+	"application/vnd.oasis.opendocument.spreadsheet": ".ods",
+	"text/csv":                  ".csv",
+	"text/tab-separated-values": ".tsv",
+	"text/markdown":             ".md",
+}
+
+// urlCacheMeta 远程源文件的缓存信息，与缓存内容保存在一起，用于条件请求
+type urlCacheMeta struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"lastModified"`
+	FileName     string `json:"fileName"` // 由响应头推断的文件名
+}
+
+// URLSourceFetcher 下载 sources.json 中配置的远程源文件
+//
+// 下载内容缓存在 cacheDir 中，之后以 ETag（或 Last-Modified）发送条件请求，未修改时直接使用缓存；
+// 下载遵循远程数据源的重试选项，全部失败且开启 fallbackToCache 时使用上次下载的文件
+type URLSourceFetcher struct {
+	policy *RetryPolicy
+	cached []string // 不可用而使用了缓存的地址
+}
+
+// NewURLSourceFetcher 创建远程源文件下载器，options 为重试选项
+func NewURLSourceFetcher(options map[string]interface{}) (*URLSourceFetcher, error) {
+	policy, err := ParseRetryPolicy(options)
+	if err != nil {
+		return nil, err
+	}
+	return &URLSourceFetcher{policy: policy}, nil
+}
+
+// Fetch 下载源文件，返回保存到缓存目录中的本地文件路径，文件扩展名决定使用的读取器
+func (f *URLSourceFetcher) Fetch(source config.URLSource) (string, error) {
+	return f.FetchContext(context.Background(), source)
+}
+
+// FetchContext 与 Fetch 相同，ctx 取消时中止下载
+func (f *URLSourceFetcher) FetchContext(ctx context.Context, source config.URLSource) (string, error) {
+	metaPath := f.policy.cachePath(source.URL) + ".meta.json"
+	var meta urlCacheMeta
+	if content, err := os.ReadFile(metaPath); err == nil {
+		json.Unmarshal(content, &meta)
+	}
+
+	var fetched *urlCacheMeta
+	content, fromCache, err := f.policy.FetchContext(ctx, source.URL, func(ctx context.Context) ([]byte, error) {
+		content, next, err := f.get(ctx, source.URL, meta)
+		fetched = next
+		return content, err
+	})
+	if err != nil {
+		return "", err
+	}
+	if fromCache {
+		f.cached = append(f.cached, source.URL)
+	}
+	if fetched != nil {
+		meta = *fetched
+		if data, err := json.Marshal(meta); err == nil {
+			if err := os.WriteFile(metaPath, data, 0644); err != nil {
+				logger.Warnf("保存 %s 的缓存信息失败: %v", source.URL, err)
+			}
+		}
+	}
+
+	fileName := source.Name
+	if fileName == "" {
+		fileName = meta.FileName
+	}
+	if fileName == "" || filepath.Ext(fileName) == "" {
+		return "", fmt.Errorf("无法确定 %s 的文件类型，请在 sources.json 中配置 name", source.URL)
+	}
+
+	// 读取器按路径读取，内容按地址分目录保存，文件名与配置或响应中的一致
+	sum := sha256.Sum256([]byte(source.URL))
+	filePath := filepath.Join(f.policy.CacheDir, "files", hex.EncodeToString(sum[:8]), fileName)
+	if existing, err := os.ReadFile(filePath); err == nil && bytes.Equal(existing, content) {
+		return filePath, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// get 发送一次请求，有缓存时带上条件请求头；未修改时返回缓存的内容，next 为 nil
+func (f *URLSourceFetcher) get(ctx context.Context, rawURL string, meta urlCacheMeta) ([]byte, *urlCacheMeta, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	cached, cacheErr := os.ReadFile(f.policy.cachePath(rawURL))
+	if cacheErr == nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cacheErr == nil:
+		logger.Debugf("%s 未修改，使用缓存", rawURL)
+		return cached, nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	next := &urlCacheMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FileName:     urlFileName(rawURL, resp.Header),
+	}
+	return content, next, nil
+}
+
+// urlFileName 由响应推断文件名：优先使用 Content-Disposition 中带扩展名的文件名；
+// 否则以地址的最后一段为文件名，Content-Type 为已知的表格类型时按其替换扩展名（如 /export?format=csv）
+func urlFileName(rawURL string, header http.Header) string {
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		if name := path.Base(filepath.ToSlash(params["filename"])); filepath.Ext(name) != "" {
+			return name
+		}
+	}
+
+	name := "source"
+	if parsed, err := url.Parse(rawURL); err == nil {
+		if base := path.Base(parsed.Path); base != "/" && base != "." {
+			name = base
+		}
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if ext, known := urlContentTypes[strings.ToLower(mediaType)]; known {
+		return strings.TrimSuffix(name, path.Ext(name)) + ext
+	}
+	return name
+}
+
+// CachedSources 下载过程中不可用、使用了上次缓存的地址
+func (f *URLSourceFetcher) CachedSources() []string {
+	return f.cached
+}
@@ -0,0 +1,438 @@
+package reader
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// BIFF8 记录类型
+const (
+	biffFormula    = 0x0006
+	biffEOF        = 0x000A
+	biffContinue   = 0x003C
+	biffBoundSheet = 0x0085
+	biffMulRK      = 0x00BD
+	biffSST        = 0x00FC
+	biffLabelSST   = 0x00FD
+	biffNumber     = 0x0203
+	biffLabel      = 0x0204
+	biffBoolErr    = 0x0205
+	biffString     = 0x0207
+	biffRK         = 0x027E
+	biffBOF        = 0x0809
+)
+
+// biffVersion8 BOF 记录中 BIFF8（Excel 97-2003）的版本号
+const biffVersion8 = 0x0600
+
+// biffErrors BOOLERR 和公式结果中的错误码对应的显示文本
+var biffErrors = map[byte]string{
+	0x00: "#NULL!",
+	0x07: "#DIV/0!",
+	0x0F: "#VALUE!",
+	0x17: "#REF!",
+	0x1D: "#NAME?",
+	0x24: "#NUM!",
+	0x2A: "#N/A",
+}
+
+// biffRecord BIFF 记录，CONTINUE 记录的内容按顺序附在 continues 中
+type biffRecord struct {
+	id        uint16
+	data      []byte
+	continues [][]byte
+}
+
+// xlsSheet 工作簿中的一个工作表
+type xlsSheet struct {
+	name   string
+	offset uint32 // 工作表 BOF 记录在 Workbook 流中的位置
+}
+
+// xlsWorkbook 解析后的 BIFF8 工作簿
+type xlsWorkbook struct {
+	stream  []byte
+	sheets  []xlsSheet
+	strings []string // 共享字符串表
+}
+
+// openXLS 读取 .xls 文件的 Workbook 流并解析工作簿全局信息
+func openXLS(r io.ReaderAt) (*xlsWorkbook, error) {
+	doc, err := mscfb.New(r)
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的 Excel 97-2003 文件: %v", err)
+	}
+
+	var stream []byte
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		switch entry.Name {
+		case "Workbook":
+			if stream, err = io.ReadAll(entry); err != nil {
+				return nil, err
+			}
+		case "Book":
+			return nil, fmt.Errorf("不支持 Excel 5.0/95 格式（BIFF5），请另存为 Excel 97-2003 或 xlsx")
+		}
+	}
+	if stream == nil {
+		return nil, fmt.Errorf("文件中没有 Workbook 流")
+	}
+
+	workbook := &xlsWorkbook{stream: stream}
+	if err := workbook.parseGlobals(); err != nil {
+		return nil, err
+	}
+	return workbook, nil
+}
+
+// records 从 offset 开始读取一个子流（BOF 到 EOF）中的记录
+func (w *xlsWorkbook) records(offset uint32) ([]*biffRecord, error) {
+	records := make([]*biffRecord, 0)
+	pos := int(offset)
+	for {
+		if pos+4 > len(w.stream) {
+			return nil, fmt.Errorf("记录在位置 %d 处被截断", pos)
+		}
+		id := binary.LittleEndian.Uint16(w.stream[pos:])
+		size := int(binary.LittleEndian.Uint16(w.stream[pos+2:]))
+		if pos+4+size > len(w.stream) {
+			return nil, fmt.Errorf("记录 0x%04X 在位置 %d 处被截断", id, pos)
+		}
+		data := w.stream[pos+4 : pos+4+size]
+		pos += 4 + size
+
+		if id == biffContinue && len(records) > 0 {
+			last := records[len(records)-1]
+			last.continues = append(last.continues, data)
+			continue
+		}
+		records = append(records, &biffRecord{id: id, data: data})
+		if id == biffEOF {
+			return records, nil
+		}
+	}
+}
+
+// parseGlobals 解析工作簿全局子流：版本、工作表列表和共享字符串表
+func (w *xlsWorkbook) parseGlobals() error {
+	records, err := w.records(0)
+	if err != nil {
+		return err
+	}
+	if records[0].id != biffBOF || len(records[0].data) < 2 {
+		return fmt.Errorf("Workbook 流缺少 BOF 记录")
+	}
+	if version := binary.LittleEndian.Uint16(records[0].data); version != biffVersion8 {
+		return fmt.Errorf("不支持的 BIFF 版本 0x%04X，仅支持 Excel 97-2003（BIFF8）", version)
+	}
+
+	for _, record := range records {
+		switch record.id {
+		case biffBoundSheet:
+			// 只读取普通工作表，跳过图表、宏表等
+			if len(record.data) < 8 || record.data[5] != 0 {
+				continue
+			}
+			reader := &biffReader{segments: [][]byte{record.data[6:]}}
+			name, err := reader.shortString()
+			if err != nil {
+				return fmt.Errorf("工作表名无效: %v", err)
+			}
+			w.sheets = append(w.sheets, xlsSheet{name: name, offset: binary.LittleEndian.Uint32(record.data)})
+		case biffSST:
+			if len(record.data) < 8 {
+				return fmt.Errorf("共享字符串表无效")
+			}
+			count := int(binary.LittleEndian.Uint32(record.data[4:]))
+			reader := &biffReader{segments: append([][]byte{record.data[8:]}, record.continues...)}
+			w.strings = make([]string, 0, count)
+			for i := 0; i < count; i++ {
+				text, err := reader.longString()
+				if err != nil {
+					return fmt.Errorf("共享字符串表第 %d 项无效: %v", i, err)
+				}
+				w.strings = append(w.strings, text)
+			}
+		}
+	}
+	return nil
+}
+
+// sheetRows 读取工作表的单元格，返回与 excelize GetRows 相同形式的二维文本：去掉每行末尾的空单元格
+func (w *xlsWorkbook) sheetRows(sheet xlsSheet) ([][]string, error) {
+	records, err := w.records(sheet.offset)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := make(map[int]map[int]string)
+	set := func(row, col int, value string) {
+		if value == "" {
+			return
+		}
+		if cells[row] == nil {
+			cells[row] = make(map[int]string)
+		}
+		cells[row][col] = value
+	}
+
+	// 字符串类型的公式结果在紧随其后的 STRING 记录中
+	formulaRow, formulaCol := -1, -1
+	for _, record := range records {
+		data := record.data
+		switch record.id {
+		case biffLabelSST:
+			if len(data) < 10 {
+				return nil, fmt.Errorf("LABELSST 记录无效")
+			}
+			index := int(binary.LittleEndian.Uint32(data[6:]))
+			if index >= len(w.strings) {
+				return nil, fmt.Errorf("共享字符串索引 %d 超出范围", index)
+			}
+			set(biffCell(data), biffColumn(data), w.strings[index])
+		case biffLabel:
+			if len(data) < 6 {
+				return nil, fmt.Errorf("LABEL 记录无效")
+			}
+			reader := &biffReader{segments: append([][]byte{data[6:]}, record.continues...)}
+			text, err := reader.string16()
+			if err != nil {
+				return nil, fmt.Errorf("LABEL 记录无效: %v", err)
+			}
+			set(biffCell(data), biffColumn(data), text)
+		case biffNumber:
+			if len(data) < 14 {
+				return nil, fmt.Errorf("NUMBER 记录无效")
+			}
+			set(biffCell(data), biffColumn(data), formatXLSNumber(math.Float64frombits(binary.LittleEndian.Uint64(data[6:]))))
+		case biffRK:
+			if len(data) < 10 {
+				return nil, fmt.Errorf("RK 记录无效")
+			}
+			set(biffCell(data), biffColumn(data), formatXLSNumber(decodeRK(binary.LittleEndian.Uint32(data[6:]))))
+		case biffMulRK:
+			// 行、首列，之后每个单元格为 XF 索引和 RK 值，最后是末列
+			if len(data) < 6 {
+				return nil, fmt.Errorf("MULRK 记录无效")
+			}
+			row, first := biffCell(data), biffColumn(data)
+			for i := 0; i < (len(data)-6)/6; i++ {
+				set(row, first+i, formatXLSNumber(decodeRK(binary.LittleEndian.Uint32(data[4+i*6+2:]))))
+			}
+		case biffBoolErr:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("BOOLERR 记录无效")
+			}
+			set(biffCell(data), biffColumn(data), boolErrText(data[6], data[7] != 0))
+		case biffFormula:
+			if len(data) < 14 {
+				return nil, fmt.Errorf("FORMULA 记录无效")
+			}
+			row, col := biffCell(data), biffColumn(data)
+			result := data[6:14]
+			if result[6] != 0xFF || result[7] != 0xFF {
+				set(row, col, formatXLSNumber(math.Float64frombits(binary.LittleEndian.Uint64(result))))
+				continue
+			}
+			switch result[0] {
+			case 0:
+				formulaRow, formulaCol = row, col
+			case 1:
+				set(row, col, boolErrText(result[2], false))
+			case 2:
+				set(row, col, boolErrText(result[2], true))
+			}
+		case biffString:
+			if formulaRow < 0 {
+				continue
+			}
+			reader := &biffReader{segments: append([][]byte{data}, record.continues...)}
+			text, err := reader.string16()
+			if err != nil {
+				return nil, fmt.Errorf("STRING 记录无效: %v", err)
+			}
+			set(formulaRow, formulaCol, text)
+			formulaRow, formulaCol = -1, -1
+		}
+	}
+
+	lastRow := -1
+	for row := range cells {
+		lastRow = max(lastRow, row)
+	}
+	rows := make([][]string, lastRow+1)
+	for row, values := range cells {
+		lastCol := -1
+		for col := range values {
+			lastCol = max(lastCol, col)
+		}
+		rows[row] = make([]string, lastCol+1)
+		for col, value := range values {
+			rows[row][col] = value
+		}
+	}
+	return rows, nil
+}
+
+// biffCell 单元格记录中的行号
+func biffCell(data []byte) int {
+	return int(binary.LittleEndian.Uint16(data))
+}
+
+// biffColumn 单元格记录中的列号
+func biffColumn(data []byte) int {
+	return int(binary.LittleEndian.Uint16(data[2:]))
+}
+
+// boolErrText 布尔值或错误码的显示文本，与 excelize 读取 xlsx 时一致
+func boolErrText(value byte, isError bool) string {
+	if isError {
+		if text, exists := biffErrors[value]; exists {
+			return text
+		}
+		return "#ERROR!"
+	}
+	if value != 0 {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// decodeRK 解码 RK 压缩数值：最低位表示除以 100，次低位表示 30 位整数，否则为双精度浮点数的高 30 位
+func decodeRK(rk uint32) float64 {
+	var value float64
+	if rk&0x02 != 0 {
+		value = float64(int32(rk) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+// formatXLSNumber 按 Excel 常规格式的 15 位有效数字输出数值，避免 0.1+0.2 之类的二进制误差
+func formatXLSNumber(value float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 15, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// biffReader 跨 CONTINUE 记录读取字符串，字符被拆分到下一段时，新段以一个选项字节开头
+type biffReader struct {
+	segments [][]byte
+	segment  int
+	pos      int
+}
+
+// bytes 读取 n 个字节，可以跨段
+func (r *biffReader) bytes(n int) ([]byte, error) {
+	result := make([]byte, 0, n)
+	for len(result) < n {
+		if r.segment >= len(r.segments) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		current := r.segments[r.segment]
+		if r.pos >= len(current) {
+			r.segment++
+			r.pos = 0
+			continue
+		}
+		take := min(n-len(result), len(current)-r.pos)
+		result = append(result, current[r.pos:r.pos+take]...)
+		r.pos += take
+	}
+	return result, nil
+}
+
+// chars 读取 count 个字符，highByte 为 false 时每个字符一个字节（Latin-1），否则为 UTF-16LE
+func (r *biffReader) chars(count int, highByte bool) (string, error) {
+	units := make([]uint16, 0, count)
+	for len(units) < count {
+		if r.segment >= len(r.segments) {
+			return "", io.ErrUnexpectedEOF
+		}
+		current := r.segments[r.segment]
+		if r.pos >= len(current) {
+			// 字符串在 CONTINUE 记录处断开，新记录的第一个字节重新指定字符宽度
+			r.segment++
+			r.pos = 0
+			if r.segment >= len(r.segments) || len(r.segments[r.segment]) == 0 {
+				return "", io.ErrUnexpectedEOF
+			}
+			highByte = r.segments[r.segment][0]&0x01 != 0
+			r.pos = 1
+			continue
+		}
+		if highByte {
+			if r.pos+2 > len(current) {
+				return "", io.ErrUnexpectedEOF
+			}
+			units = append(units, binary.LittleEndian.Uint16(current[r.pos:]))
+			r.pos += 2
+		} else {
+			units = append(units, uint16(current[r.pos]))
+			r.pos++
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// shortString 读取 1 字节长度的字符串（ShortXLUnicodeString）
+func (r *biffReader) shortString() (string, error) {
+	header, err := r.bytes(2)
+	if err != nil {
+		return "", err
+	}
+	return r.chars(int(header[0]), header[1]&0x01 != 0)
+}
+
+// string16 读取 2 字节长度的字符串（XLUnicodeString）
+func (r *biffReader) string16() (string, error) {
+	header, err := r.bytes(3)
+	if err != nil {
+		return "", err
+	}
+	return r.chars(int(binary.LittleEndian.Uint16(header)), header[2]&0x01 != 0)
+}
+
+// longString 读取共享字符串表中的字符串（XLUnicodeRichExtendedString），跳过格式和扩展信息
+func (r *biffReader) longString() (string, error) {
+	header, err := r.bytes(3)
+	if err != nil {
+		return "", err
+	}
+	count := int(binary.LittleEndian.Uint16(header))
+	flags := header[2]
+
+	runs, extSize := 0, 0
+	if flags&0x08 != 0 {
+		data, err := r.bytes(2)
+		if err != nil {
+			return "", err
+		}
+		runs = int(binary.LittleEndian.Uint16(data))
+	}
+	if flags&0x04 != 0 {
+		data, err := r.bytes(4)
+		if err != nil {
+			return "", err
+		}
+		extSize = int(binary.LittleEndian.Uint32(data))
+	}
+
+	text, err := r.chars(count, flags&0x01 != 0)
+	if err != nil {
+		return "", err
+	}
+	if _, err := r.bytes(runs*4 + extSize); err != nil {
+		return "", err
+	}
+	return text, nil
+}
@@ -0,0 +1,134 @@
+package reader
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// XLSReader Excel 97-2003（.xls，BIFF8）读取器，表头约定与 xlsx 相同，公式单元格使用文件中缓存的计算结果
+type XLSReader struct {
+	config map[string]interface{}
+	layout *HeaderLayout
+}
+
+// NewXLSReader 创建 xls 读取器
+func NewXLSReader() *XLSReader {
+	return &XLSReader{layout: DefaultHeaderLayout()}
+}
+
+// Init 初始化读取器
+func (r *XLSReader) Init(config map[string]interface{}) error {
+	layout, err := ParseHeaderLayout(config)
+	if err != nil {
+		return err
+	}
+
+	r.config = config
+	r.layout = layout
+	return nil
+}
+
+// ReadAll 读取所有数据表
+func (r *XLSReader) ReadAll(filePath string) ([]*model.DataSheet, error) {
+	return r.ReadAllContext(context.Background(), filePath)
+}
+
+// ReadAllContext 读取所有数据表，ctx 取消时在工作表之间中止
+func (r *XLSReader) ReadAllContext(ctx context.Context, filePath string) ([]*model.DataSheet, error) {
+	workbook, err := r.open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := make([]*model.DataSheet, 0)
+	for _, xs := range workbook.sheets {
+		// 跳过以_开头的工作表（隐藏表）
+		if strings.HasPrefix(xs.name, "_") {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		sheet, err := r.readSheet(workbook, xs)
+		if err != nil {
+			return nil, err
+		}
+		if sheet != nil {
+			sheets = append(sheets, sheet)
+		}
+	}
+
+	return sheets, nil
+}
+
+// ReadSheet 读取指定工作表
+func (r *XLSReader) ReadSheet(filePath string, sheetName string) (*model.DataSheet, error) {
+	workbook, err := r.open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(workbook.sheets) == 0 {
+		return nil, nil
+	}
+
+	// 如果未指定工作表名，使用第一个工作表
+	if sheetName == "" {
+		return r.readSheet(workbook, workbook.sheets[0])
+	}
+	for _, xs := range workbook.sheets {
+		if xs.name == sheetName {
+			return r.readSheet(workbook, xs)
+		}
+	}
+	return nil, nil
+}
+
+// open 打开 xls 文件并解析工作簿
+func (r *XLSReader) open(filePath string) (*xlsWorkbook, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return openXLS(file)
+}
+
+// readSheet 读取单个工作表
+func (r *XLSReader) readSheet(workbook *xlsWorkbook, xs xlsSheet) (*model.DataSheet, error) {
+	rows, err := workbook.sheetRows(xs)
+	if err != nil {
+		return nil, err
+	}
+	return parseGrid(xs.name, rows, r.layout, r.convertValue)
+}
+
+// GetSupportedFormats 获取支持的文件格式
+func (r *XLSReader) GetSupportedFormats() []string {
+	return []string{".xls", ".XLS"}
+}
+
+// convertValue 转换数据类型，与 xlsx 读取器一致
+func (r *XLSReader) convertValue(value string, dataType string) (interface{}, error) {
+	switch strings.ToLower(dataType) {
+	case "int", "integer":
+		return strconv.Atoi(value)
+	case "float", "double", "number":
+		return strconv.ParseFloat(value, 64)
+	case "bool", "boolean":
+		value = strings.ToLower(value)
+		if value == "true" || value == "1" || value == "yes" {
+			return true, nil
+		}
+		return false, nil
+	case "string":
+		return value, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,352 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 分片上传的限制
+const (
+	minPartSize = 5     // S3 要求除最后一个分片外每个分片至少 5 MB
+	maxParts    = 10000 // 单个对象最多的分片数
+)
+
+// uploadState 分片上传的进度，保存在 ResumeDir 中，用于中断后续传
+type uploadState struct {
+	Key      string `json:"key"`      // 对象键
+	UploadID string `json:"uploadId"` // 分片上传编号
+	ETag     string `json:"etag"`     // 上传内容的分片 ETag，内容变化时放弃旧的上传
+	PartSize int    `json:"partSize"` // 分片大小（字节）
+}
+
+// partSize 文件使用的分片大小（字节），分片数超过上限时按上限均分
+func (s *ObjectStorageSyncer) partSize(size int) int {
+	partSize := s.cfg.PartSize * 1024 * 1024
+	if size > partSize*maxParts {
+		partSize = (size + maxParts - 1) / maxParts
+	}
+	return partSize
+}
+
+// splitParts 按分片大小切分内容
+func splitParts(content []byte, partSize int) [][]byte {
+	parts := make([][]byte, 0, len(content)/partSize+1)
+	for start := 0; start < len(content); start += partSize {
+		end := start + partSize
+		if end > len(content) {
+			end = len(content)
+		}
+		parts = append(parts, content[start:end])
+	}
+	return parts
+}
+
+// multipartETag 按 S3 的规则计算分片上传对象的 ETag：各分片 MD5 拼接后再取 MD5，加上 -分片数
+func multipartETag(parts [][]byte) string {
+	var sums []byte
+	for _, part := range parts {
+		sum := md5.Sum(part)
+		sums = append(sums, sum[:]...)
+	}
+	sum := md5.Sum(sums)
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), len(parts))
+}
+
+// statePath 对象的上传进度文件
+func (s *ObjectStorageSyncer) statePath(relPath string) string {
+	sum := sha256.Sum256([]byte(s.endpoint.String() + "\n" + s.cfg.Bucket + "\n" + s.cfg.Prefix + "\n" + relPath))
+	return filepath.Join(s.cfg.ResumeDir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// loadState 读取对象的上传进度，不存在或无法解析时返回 nil
+func (s *ObjectStorageSyncer) loadState(relPath string) *uploadState {
+	content, err := os.ReadFile(s.statePath(relPath))
+	if err != nil {
+		return nil
+	}
+	var state uploadState
+	if err := json.Unmarshal(content, &state); err != nil || state.UploadID == "" {
+		return nil
+	}
+	return &state
+}
+
+// saveState 保存对象的上传进度
+func (s *ObjectStorageSyncer) saveState(relPath string, state *uploadState) error {
+	if err := os.MkdirAll(s.cfg.ResumeDir, 0755); err != nil {
+		return err
+	}
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.statePath(relPath), content, 0644)
+}
+
+// uploadMultipart 分片上传文件：有同一内容未完成的上传时只上传缺少的分片，各分片并发上传并单独重试
+func (s *ObjectStorageSyncer) uploadMultipart(file File, etag string) error {
+	partSize := s.partSize(len(file.Content))
+	parts := splitParts(file.Content, partSize)
+	done := make(map[int]string)
+
+	state := s.loadState(file.Path)
+	if state != nil && (state.ETag != etag || state.PartSize != partSize) {
+		// 内容已变化，放弃旧的上传，失败时由存储桶的生命周期规则清理
+		s.abortUpload(file.Path, state.UploadID)
+		state = nil
+	}
+	if state != nil {
+		listed, err := s.listParts(file.Path, state.UploadID)
+		if err != nil {
+			state = nil
+		}
+		for number, partETag := range listed {
+			if number >= 1 && number <= len(parts) {
+				sum := md5.Sum(parts[number-1])
+				if partETag == hex.EncodeToString(sum[:]) {
+					done[number] = partETag
+				}
+			}
+		}
+	}
+	if state == nil {
+		uploadID, err := s.initiateUpload(file.Path)
+		if err != nil {
+			return err
+		}
+		state = &uploadState{Key: file.Path, UploadID: uploadID, ETag: etag, PartSize: partSize}
+		if err := s.saveState(file.Path, state); err != nil {
+			return fmt.Errorf("保存 %s 的上传进度失败: %v", file.Path, err)
+		}
+	}
+
+	numbers := make(chan int)
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for i := 0; i < s.cfg.PartParallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range numbers {
+				partETag, err := s.uploadPart(file.Path, state.UploadID, number, parts[number-1])
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if err == nil {
+					done[number] = partETag
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for number := 1; number <= len(parts); number++ {
+		mu.Lock()
+		_, uploaded := done[number]
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		if !uploaded {
+			numbers <- number
+		}
+	}
+	close(numbers)
+	wg.Wait()
+	if firstErr != nil {
+		// 保留上传进度，下次同步时续传
+		return firstErr
+	}
+
+	if err := s.completeUpload(file.Path, state.UploadID, done); err != nil {
+		return err
+	}
+	os.Remove(s.statePath(file.Path))
+	return nil
+}
+
+// initiateUpload 创建分片上传，返回上传编号
+func (s *ObjectStorageSyncer) initiateUpload(relPath string) (string, error) {
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err := s.withRetry("创建 "+relPath+" 的分片上传", func() error {
+		return s.doXML(http.MethodPost, relPath, url.Values{"uploads": {""}}, nil, s.objectHeaders(relPath), &result)
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("创建 %s 的分片上传失败: 响应中没有 UploadId", relPath)
+	}
+	return result.UploadID, nil
+}
+
+// uploadPart 上传一个分片，返回其 ETag
+func (s *ObjectStorageSyncer) uploadPart(relPath, uploadID string, number int, content []byte) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	op := fmt.Sprintf("上传 %s 的第 %d 个分片", relPath, number)
+	var etag string
+	err := s.withRetry(op, func() error {
+		resp, err := s.do(http.MethodPut, relPath, query, content, nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return &statusError{op: op, code: resp.StatusCode}
+		}
+		etag = strings.Trim(resp.Header.Get("ETag"), `"`)
+		return nil
+	})
+	return etag, err
+}
+
+// listParts 列出未完成的上传中已上传的分片及其 ETag
+func (s *ObjectStorageSyncer) listParts(relPath, uploadID string) (map[int]string, error) {
+	parts := make(map[int]string)
+	marker := ""
+	for {
+		var result struct {
+			IsTruncated          bool
+			NextPartNumberMarker string
+			Parts                []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		query := url.Values{"uploadId": {uploadID}}
+		if marker != "" {
+			query.Set("part-number-marker", marker)
+		}
+		err := s.withRetry("查询 "+relPath+" 的已上传分片", func() error {
+			return s.doXML(http.MethodGet, relPath, query, nil, nil, &result)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, part := range result.Parts {
+			parts[part.PartNumber] = strings.Trim(part.ETag, `"`)
+		}
+		if !result.IsTruncated || result.NextPartNumberMarker == "" || result.NextPartNumberMarker == marker {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// completeUpload 按分片号顺序合并所有分片
+func (s *ObjectStorageSyncer) completeUpload(relPath, uploadID string, done map[int]string) error {
+	var body bytes.Buffer
+	body.WriteString("<CompleteMultipartUpload>")
+	for number := 1; number <= len(done); number++ {
+		fmt.Fprintf(&body, "<Part><PartNumber>%d</PartNumber><ETag>\"%s\"</ETag></Part>", number, done[number])
+	}
+	body.WriteString("</CompleteMultipartUpload>")
+
+	return s.withRetry("合并 "+relPath+" 的分片", func() error {
+		var result struct {
+			XMLName xml.Name
+			Code    string
+			Message string
+		}
+		if err := s.doXML(http.MethodPost, relPath, url.Values{"uploadId": {uploadID}}, body.Bytes(), nil, &result); err != nil {
+			return err
+		}
+		// 合并失败时服务端也可能返回 200，错误在响应体中
+		if result.XMLName.Local == "Error" {
+			return fmt.Errorf("合并 %s 的分片失败: %s %s", relPath, result.Code, result.Message)
+		}
+		return nil
+	})
+}
+
+// abortUpload 放弃未完成的分片上传，失败时忽略
+func (s *ObjectStorageSyncer) abortUpload(relPath, uploadID string) {
+	resp, err := s.do(http.MethodDelete, relPath, url.Values{"uploadId": {uploadID}}, nil, nil)
+	if err == nil {
+		resp.Body.Close()
+	}
+	os.Remove(s.statePath(relPath))
+}
+
+// doXML 发送请求并解析 XML 响应
+func (s *ObjectStorageSyncer) doXML(method, relPath string, query url.Values, body []byte, headers map[string]string, result interface{}) error {
+	resp, err := s.do(method, relPath, query, body, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{op: method + " " + relPath, code: resp.StatusCode}
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(content, result); err != nil {
+		return fmt.Errorf("解析 %s 的响应失败: %v", relPath, err)
+	}
+	return nil
+}
+
+// bandwidthLimiter 多个并发请求共享的带宽限制，按字节数为每次发送预留时间
+type bandwidthLimiter struct {
+	bytesPerSecond int64
+	mu             sync.Mutex
+	next           time.Time // 下一次发送可以开始的时间
+}
+
+// newBandwidthLimiter 创建带宽限制
+func newBandwidthLimiter(bytesPerSecond int64) *bandwidthLimiter {
+	return &bandwidthLimiter{bytesPerSecond: bytesPerSecond}
+}
+
+// wait 为 n 个字节预留发送时间，等待到预留的时间开始
+func (l *bandwidthLimiter) wait(n int) {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.bytesPerSecond))
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
+
+// throttledReader 受带宽限制的请求体，每次最多读取 32 KB
+type throttledReader struct {
+	reader  io.Reader
+	limiter *bandwidthLimiter
+}
+
+// Read 实现 io.Reader 接口
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > 32*1024 {
+		p = p[:32*1024]
+	}
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.limiter.wait(n)
+	}
+	return n, err
+}
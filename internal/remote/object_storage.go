@@ -0,0 +1,308 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/game-data-builder/internal/config"
+)
+
+// requestTimeout 单个请求的基础超时时间，限制带宽时按请求体大小延长
+const requestTimeout = 60 * time.Second
+
+// ObjectStorageSyncer 通过 S3 兼容接口上传到对象存储（AWS S3、阿里云 OSS、腾讯云 COS），使用 AWS Signature V4 签名
+//
+// 大文件使用分片上传，进度保存在 ResumeDir 中，中断后下次同步只上传未完成的分片
+type ObjectStorageSyncer struct {
+	cfg      config.SyncTarget
+	endpoint *url.URL
+	signer   SigV4Signer
+	client   *http.Client
+	limiter  *bandwidthLimiter // 带宽限制，为 nil 表示不限制
+}
+
+// defaultEndpoint 各服务商 S3 兼容接口的默认地址
+func defaultEndpoint(provider, region string) string {
+	switch provider {
+	case "oss":
+		return fmt.Sprintf("https://oss-%s.aliyuncs.com", region)
+	case "cos":
+		return fmt.Sprintf("https://cos.%s.myqcloud.com", region)
+	default:
+		return fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+}
+
+// NewObjectStorageSyncer 创建对象存储同步器，访问密钥从配置指定的环境变量读取
+func NewObjectStorageSyncer(cfg config.SyncTarget) (*ObjectStorageSyncer, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, fmt.Errorf("%s 同步必须配置 bucket 和 region", cfg.Type)
+	}
+	if cfg.Parallel <= 0 {
+		cfg.Parallel = 4
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = 3
+	}
+	if cfg.RetryBackoffMs <= 0 {
+		cfg.RetryBackoffMs = 1000
+	}
+	if cfg.MultipartThreshold <= 0 {
+		cfg.MultipartThreshold = 64
+	}
+	if cfg.PartSize <= 0 {
+		cfg.PartSize = 8
+	}
+	if cfg.PartSize < minPartSize {
+		return nil, fmt.Errorf("partSize 不能小于 %d MB", minPartSize)
+	}
+	if cfg.PartParallel <= 0 {
+		cfg.PartParallel = 4
+	}
+	if cfg.ResumeDir == "" {
+		cfg.ResumeDir = filepath.Join(os.TempDir(), "game-data-builder-uploads")
+	}
+
+	rawEndpoint := cfg.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = defaultEndpoint(cfg.Type, cfg.Region)
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 endpoint: %v", err)
+	}
+
+	accessKey := os.Getenv(cfg.AccessKeyEnv)
+	secretKey := os.Getenv(cfg.SecretKeyEnv)
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("环境变量 %s 或 %s 未设置访问密钥", cfg.AccessKeyEnv, cfg.SecretKeyEnv)
+	}
+
+	syncer := &ObjectStorageSyncer{
+		cfg:      cfg,
+		endpoint: endpoint,
+		signer: SigV4Signer{
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			Region:    cfg.Region,
+			Service:   "s3",
+		},
+		client: &http.Client{},
+	}
+	if cfg.MaxBandwidth > 0 {
+		syncer.limiter = newBandwidthLimiter(int64(cfg.MaxBandwidth) * 1024)
+	}
+	return syncer, nil
+}
+
+// Sync 并发上传 ETag 与本地 MD5 不一致的文件
+func (s *ObjectStorageSyncer) Sync(files []File) (int, error) {
+	jobs := make(chan File)
+	var mu sync.Mutex
+	var firstErr error
+	uploaded := 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.cfg.Parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				changed, err := s.syncFile(file)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				if changed {
+					uploaded++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, file := range files {
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+
+	return uploaded, firstErr
+}
+
+// syncFile 比较远程 ETag，不一致时上传，返回是否上传；超过 multipartThreshold 的文件使用分片上传
+func (s *ObjectStorageSyncer) syncFile(file File) (bool, error) {
+	multipart := len(file.Content) > s.cfg.MultipartThreshold*1024*1024
+	var localETag string
+	if multipart {
+		localETag = multipartETag(splitParts(file.Content, s.partSize(len(file.Content))))
+	} else {
+		sum := md5.Sum(file.Content)
+		localETag = hex.EncodeToString(sum[:])
+	}
+
+	var remoteETag string
+	err := s.withRetry("查询 "+file.Path, func() error {
+		resp, err := s.do(http.MethodHead, file.Path, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		// 对象不存在时部分服务商返回 403，除可重试的错误外都按需要上传处理
+		if status := (&statusError{op: "查询 " + file.Path, code: resp.StatusCode}); status.retryable() {
+			return status
+		}
+		if resp.StatusCode == http.StatusOK {
+			remoteETag = strings.Trim(resp.Header.Get("ETag"), `"`)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if remoteETag == localETag {
+		return false, nil
+	}
+
+	if multipart {
+		return true, s.uploadMultipart(file, localETag)
+	}
+	err = s.withRetry("上传 "+file.Path, func() error {
+		resp, err := s.do(http.MethodPut, file.Path, nil, file.Content, s.objectHeaders(file.Path))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return &statusError{op: "上传 " + file.Path, code: resp.StatusCode}
+		}
+		return nil
+	})
+	return err == nil, err
+}
+
+// objectHeaders 创建对象时设置的请求头
+func (s *ObjectStorageSyncer) objectHeaders(relPath string) map[string]string {
+	headers := map[string]string{"Content-Type": contentType(relPath)}
+	if s.cfg.CacheControl != "" {
+		headers["Cache-Control"] = s.cfg.CacheControl
+	}
+	return headers
+}
+
+// statusError 服务端返回的错误状态
+type statusError struct {
+	op   string
+	code int
+}
+
+// Error 实现 error 接口
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s 失败: HTTP %d", e.op, e.code)
+}
+
+// retryable 服务端错误、超时和限流可以重试，其他客户端错误重试也不会成功
+func (e *statusError) retryable() bool {
+	return e.code >= 500 || e.code == http.StatusRequestTimeout || e.code == http.StatusTooManyRequests
+}
+
+// withRetry 执行请求，失败时按指数退避重试
+func (s *ObjectStorageSyncer) withRetry(op string, fn func() error) error {
+	backoff := time.Duration(s.cfg.RetryBackoffMs) * time.Millisecond
+	var err error
+	for attempt := 1; attempt <= s.cfg.Retries; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		var status *statusError
+		if errors.As(err, &status) && !status.retryable() {
+			return err
+		}
+		if attempt < s.cfg.Retries {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return fmt.Errorf("%s 失败（已尝试 %d 次）: %v", op, s.cfg.Retries, err)
+}
+
+// do 发送签名后的请求，请求体受带宽限制
+func (s *ObjectStorageSyncer) do(method, relPath string, query url.Values, body []byte, headers map[string]string) (*http.Response, error) {
+	key := strings.TrimPrefix(path.Join(s.cfg.Prefix, relPath), "/")
+
+	target := *s.endpoint
+	if s.cfg.PathStyle {
+		target.Path = "/" + s.cfg.Bucket + "/" + key
+	} else {
+		target.Host = s.cfg.Bucket + "." + s.endpoint.Host
+		target.Path = "/" + key
+	}
+	// 请求路径使用与签名相同的编码，Go 默认的路径转义不会编码 !*'()+ 等字符
+	target.RawPath = canonicalURI(target.Path)
+	target.RawQuery = canonicalQuery(query)
+
+	timeout := requestTimeout
+	var reader io.Reader = bytes.NewReader(body)
+	if s.limiter != nil && len(body) > 0 {
+		reader = &throttledReader{reader: reader, limiter: s.limiter}
+		timeout += time.Duration(int64(len(body)) * int64(time.Second) / s.limiter.bytesPerSecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	s.signer.Sign(req, payloadHash, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody 关闭响应体时释放请求的超时上下文
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// contentType 根据扩展名确定内容类型
+func contentType(name string) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,42 @@
+package remote
+
+import (
+	"fmt"
+
+	"github.com/game-data-builder/internal/config"
+)
+
+// File 待同步的文件
+type File struct {
+	Path    string // 相对于远程目录的路径（使用 / 分隔）
+	Content []byte // 文件内容
+}
+
+// ISyncer 定义了远程同步的接口
+type ISyncer interface {
+	// Sync 将内容有变化的文件上传到远程，返回上传的文件数量
+	Sync(files []File) (int, error)
+}
+
+// NewSyncer 根据配置创建远程同步器
+func NewSyncer(cfg config.RemoteSyncConfig) (ISyncer, error) {
+	switch cfg.Type {
+	case "", "sftp":
+		if cfg.Host == "" || cfg.RemoteDir == "" {
+			return nil, fmt.Errorf("sftp 同步必须配置 host 和 remoteDir")
+		}
+		return NewSFTPSyncer(cfg), nil
+	default:
+		return nil, fmt.Errorf("不支持的远程同步类型: %s", cfg.Type)
+	}
+}
+
+// NewTargetSyncer 根据对象存储同步目标创建同步器
+func NewTargetSyncer(target config.SyncTarget) (ISyncer, error) {
+	switch target.Type {
+	case "s3", "oss", "cos":
+		return NewObjectStorageSyncer(target)
+	default:
+		return nil, fmt.Errorf("不支持的同步目标类型: %s", target.Type)
+	}
+}
@@ -0,0 +1,225 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/output"
+)
+
+// SFTPSyncer 通过系统的 sftp 命令上传文件，使用远程目录中的 version.json 判断哪些文件有变化
+type SFTPSyncer struct {
+	cfg     config.RemoteSyncConfig
+	command string // sftp 命令路径
+}
+
+// NewSFTPSyncer 创建 SFTP 同步器
+func NewSFTPSyncer(cfg config.RemoteSyncConfig) *SFTPSyncer {
+	if cfg.Retries <= 0 {
+		cfg.Retries = 3
+	}
+	if cfg.RetryBackoffMs <= 0 {
+		cfg.RetryBackoffMs = 1000
+	}
+	if cfg.Parallel <= 0 {
+		cfg.Parallel = 1
+	}
+	return &SFTPSyncer{cfg: cfg, command: "sftp"}
+}
+
+// Sync 上传内容有变化的文件，全部成功后再更新远程的 version.json
+func (s *SFTPSyncer) Sync(files []File) (int, error) {
+	workDir, err := os.MkdirTemp("", "builder-sftp-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(workDir)
+
+	// 生成本次的版本文件，并与远程版本比较
+	version := output.NewVersionFile(time.Now(), "")
+	for _, file := range files {
+		version.Add(file.Path, file.Content)
+	}
+	remoteHashes := s.fetchRemoteHashes(workDir)
+
+	changed := make([]File, 0)
+	for i, file := range files {
+		if remoteHashes[file.Path] != version.Files[i].SHA256 {
+			changed = append(changed, file)
+		}
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	// 将变化的文件写入本地临时目录
+	for _, file := range changed {
+		localPath := filepath.Join(workDir, "files", filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(localPath, file.Content, 0644); err != nil {
+			return 0, err
+		}
+	}
+
+	// 按并发数分批上传
+	batches := make([][]File, s.cfg.Parallel)
+	for i, file := range changed {
+		batches[i%s.cfg.Parallel] = append(batches[i%s.cfg.Parallel], file)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(batches))
+	for i, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.runWithRetry(s.uploadCommands(workDir, batch))
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// 最后上传版本文件，上传中断时下次同步会重新上传未完成的文件
+	content, err := version.Marshal()
+	if err != nil {
+		return 0, err
+	}
+	versionPath := filepath.Join(workDir, output.VersionFileName)
+	if err := os.WriteFile(versionPath, content, 0644); err != nil {
+		return 0, err
+	}
+	commands := []string{fmt.Sprintf("put %s %s", quote(versionPath), quote(s.remotePath(output.VersionFileName)))}
+	if err := s.runWithRetry(commands); err != nil {
+		return 0, err
+	}
+
+	return len(changed), nil
+}
+
+// fetchRemoteHashes 下载远程的 version.json，不存在或无法解析时返回空，所有文件都视为有变化
+func (s *SFTPSyncer) fetchRemoteHashes(workDir string) map[string]string {
+	hashes := make(map[string]string)
+
+	localPath := filepath.Join(workDir, "remote-"+output.VersionFileName)
+	commands := []string{fmt.Sprintf("-get %s %s", quote(s.remotePath(output.VersionFileName)), quote(localPath))}
+	if err := s.run(commands); err != nil {
+		return hashes
+	}
+
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return hashes
+	}
+	var version output.VersionFile
+	if err := json.Unmarshal(content, &version); err != nil {
+		return hashes
+	}
+	for _, entry := range version.Files {
+		hashes[entry.Path] = entry.SHA256
+	}
+	return hashes
+}
+
+// uploadCommands 生成上传一批文件的 sftp 批处理命令
+func (s *SFTPSyncer) uploadCommands(workDir string, files []File) []string {
+	// 先创建所需的远程目录，目录已存在时忽略错误
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		for dir := path.Dir(file.Path); dir != "."; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	sortedDirs := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sortedDirs = append(sortedDirs, dir)
+	}
+	sort.Strings(sortedDirs)
+
+	commands := []string{fmt.Sprintf("-mkdir %s", quote(s.cfg.RemoteDir))}
+	for _, dir := range sortedDirs {
+		commands = append(commands, fmt.Sprintf("-mkdir %s", quote(s.remotePath(dir))))
+	}
+	for _, file := range files {
+		localPath := filepath.Join(workDir, "files", filepath.FromSlash(file.Path))
+		commands = append(commands, fmt.Sprintf("put %s %s", quote(localPath), quote(s.remotePath(file.Path))))
+	}
+	return commands
+}
+
+// runWithRetry 执行批处理命令，失败时按指数退避重试
+func (s *SFTPSyncer) runWithRetry(commands []string) error {
+	backoff := time.Duration(s.cfg.RetryBackoffMs) * time.Millisecond
+	var err error
+	for attempt := 1; attempt <= s.cfg.Retries; attempt++ {
+		if err = s.run(commands); err == nil {
+			return nil
+		}
+		if attempt < s.cfg.Retries {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return fmt.Errorf("上传到 %s 失败（已尝试 %d 次）: %v", s.cfg.Host, s.cfg.Retries, err)
+}
+
+// run 以批处理模式执行 sftp 命令
+func (s *SFTPSyncer) run(commands []string) error {
+	batch, err := os.CreateTemp("", "builder-sftp-batch-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(batch.Name())
+	batch.WriteString(strings.Join(commands, "\n") + "\n")
+	batch.Close()
+
+	args := []string{"-b", batch.Name(), "-o", "BatchMode=yes"}
+	if s.cfg.Port > 0 {
+		args = append(args, "-P", strconv.Itoa(s.cfg.Port))
+	}
+	if s.cfg.KeyFile != "" {
+		args = append(args, "-i", s.cfg.KeyFile)
+	}
+	target := s.cfg.Host
+	if s.cfg.User != "" {
+		target = s.cfg.User + "@" + s.cfg.Host
+	}
+	args = append(args, target)
+
+	cmd := exec.Command(s.command, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// remotePath 远程文件的完整路径
+func (s *SFTPSyncer) remotePath(relPath string) string {
+	return path.Join(s.cfg.RemoteDir, relPath)
+}
+
+// quote 为 sftp 批处理命令中的路径加引号
+func quote(p string) string {
+	return `"` + strings.ReplaceAll(p, `"`, `\"`) + `"`
+}
@@ -0,0 +1,133 @@
+package replace
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// rule 编译后的替换规则
+type rule struct {
+	column string             // 列名或通配符模式
+	from   string             // 原内容，为空时替换整个值
+	regex  *regexp.Regexp     // 正则模式下编译后的 from
+	to     string             // 不含模板时的替换内容
+	tmpl   *template.Template // to 中引用了其他列时编译后的模板
+}
+
+// Apply 按 replaceColumn.json 替换字符串列的值
+//
+// 表名和列名都可以是通配符模式，一个值匹配多条规则时按表名、列名排序后依次应用。
+// from 为空时整个值替换为 to；regex 为 true 时 from 为正则表达式，to 中可以用 $1、${name} 引用捕获组；
+// to 中可以用 {{.id}} 这样的模板引用同一行其他列的值，先于捕获组替换展开
+func Apply(sheets []*model.DataSheet, cfg *config.ReplaceColumnConfig) error {
+	if cfg == nil || len(cfg.Sheets) == 0 {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(cfg.Sheets))
+	compiled := make(map[string][]*rule, len(cfg.Sheets))
+	for pattern, rules := range cfg.Sheets {
+		patterns = append(patterns, pattern)
+		columns := make([]string, 0, len(rules.Columns))
+		for column := range rules.Columns {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			r, err := compile(column, rules.Columns[column])
+			if err != nil {
+				return fmt.Errorf("replaceColumn.json: %s.%s: %v", pattern, column, err)
+			}
+			compiled[pattern] = append(compiled[pattern], r)
+		}
+	}
+	sort.Strings(patterns)
+
+	for _, sheet := range sheets {
+		var rules []*rule
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, sheet.Name); matched {
+				rules = append(rules, compiled[pattern]...)
+			}
+		}
+		if len(rules) == 0 {
+			continue
+		}
+
+		for _, r := range rules {
+			for _, col := range sheet.Columns {
+				if matched, _ := path.Match(r.column, col.Name); !matched {
+					continue
+				}
+				for rowIndex, row := range sheet.Rows {
+					val, exists := model.RowValue(row, col.Name)
+					str, ok := val.(string)
+					if !exists || !ok {
+						continue
+					}
+					replaced, err := r.apply(str, row)
+					if err != nil {
+						return fmt.Errorf("sheet %s, row %d, column %s: %v", sheet.Name, sheet.RowNumber(rowIndex), col.Name, err)
+					}
+					model.SetRowValue(row, col.Name, replaced)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compile 编译一条替换规则
+func compile(column string, cfg config.ReplaceRule) (*rule, error) {
+	if _, err := path.Match(column, ""); err != nil {
+		return nil, fmt.Errorf("列名模式无效: %v", err)
+	}
+
+	r := &rule{column: column, from: cfg.From, to: cfg.To}
+	if cfg.Regex {
+		if cfg.From == "" {
+			return nil, fmt.Errorf("正则模式的 from 不能为空")
+		}
+		regex, err := regexp.Compile(cfg.From)
+		if err != nil {
+			return nil, fmt.Errorf("正则表达式无效: %v", err)
+		}
+		r.regex = regex
+	}
+	if strings.Contains(cfg.To, "{{") {
+		tmpl, err := template.New(column).Option("missingkey=error").Parse(cfg.To)
+		if err != nil {
+			return nil, fmt.Errorf("模板无效: %v", err)
+		}
+		r.tmpl = tmpl
+	}
+	return r, nil
+}
+
+// apply 对一个值应用替换规则，row 为值所在的行，供模板引用其他列
+func (r *rule) apply(val string, row map[string]interface{}) (string, error) {
+	to := r.to
+	if r.tmpl != nil {
+		var rendered strings.Builder
+		if err := r.tmpl.Execute(&rendered, row); err != nil {
+			return "", err
+		}
+		to = rendered.String()
+	}
+
+	switch {
+	case r.regex != nil:
+		return r.regex.ReplaceAllString(val, to), nil
+	case r.from == "":
+		return to, nil
+	default:
+		return strings.ReplaceAll(val, r.from, to), nil
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/game-data-builder/internal/logger"
+)
+
+// Report 构建报告，由多个章节组成
+type Report struct {
+	Sections []*Section
+}
+
+// Section 报告章节
+type Section struct {
+	Title string   // 章节标题
+	Lines []string // 章节内容
+}
+
+// NewReport 创建构建报告
+func NewReport() *Report {
+	return &Report{Sections: make([]*Section, 0)}
+}
+
+// Section 获取指定标题的章节，不存在时创建
+func (r *Report) Section(title string) *Section {
+	for _, section := range r.Sections {
+		if section.Title == title {
+			return section
+		}
+	}
+
+	section := &Section{Title: title, Lines: make([]string, 0)}
+	r.Sections = append(r.Sections, section)
+	return section
+}
+
+// Addf 向章节追加一行内容
+func (s *Section) Addf(format string, args ...interface{}) {
+	s.Lines = append(s.Lines, fmt.Sprintf(format, args...))
+}
+
+// Print 输出报告
+func (r *Report) Print(w io.Writer) {
+	for _, section := range r.Sections {
+		if len(section.Lines) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "==== %s ====\n", section.Title)
+		for _, line := range section.Lines {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
+
+// Log 通过日志输出报告，JSON 日志中每行内容带有所属章节
+func (r *Report) Log() {
+	for _, section := range r.Sections {
+		if len(section.Lines) == 0 {
+			continue
+		}
+
+		entry := logger.WithFields(logger.Fields{"section": section.Title})
+		if logger.Default().IsJSON() {
+			for _, line := range section.Lines {
+				entry.Infof("%s", line)
+			}
+			continue
+		}
+
+		logger.Infof("==== %s ====", section.Title)
+		for _, line := range section.Lines {
+			logger.Infof("  %s", line)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Task 调度任务
+type Task struct {
+	ID   string       // 任务唯一标识
+	Deps []string     // 依赖的任务ID，全部完成后才会执行
+	Run  func() error // 任务内容
+}
+
+// Scheduler 带依赖关系的有界并发调度器
+//
+// 每个工作协程拥有自己的任务队列，优先按后进先出执行本地任务，
+// 本地队列为空时从其他队列头部窃取任务。
+type Scheduler struct {
+	workers int
+}
+
+// NewScheduler 创建调度器
+func NewScheduler(workers int) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Scheduler{workers: workers}
+}
+
+// Run 执行所有任务；任务失败时跳过直接或间接依赖它的任务，其他任务继续执行，
+// 返回所有失败任务的错误（只有一个时原样返回，多个时用 errors.Join 合并）
+func (s *Scheduler) Run(tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	st, err := newRunState(tasks, s.workers)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			st.work(id)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(st.errs) == 1 {
+		return st.errs[0]
+	}
+	return errors.Join(st.errs...)
+}
+
+// runState 一次调度的运行状态
+type runState struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	queues     [][]*Task          // 每个工作协程的任务队列
+	pending    map[string]int     // 任务ID -> 未完成的依赖数
+	dependents map[string][]*Task // 任务ID -> 依赖它的任务
+	remaining  int                // 未完成的任务数
+	ready      int                // 队列中可执行的任务数
+	skipped    map[string]bool    // 因依赖失败而跳过的任务
+	errs       []error            // 按完成顺序记录的任务错误
+}
+
+// newRunState 校验任务依赖并初始化运行状态
+func newRunState(tasks []*Task, workers int) (*runState, error) {
+	st := &runState{
+		queues:     make([][]*Task, workers),
+		pending:    make(map[string]int),
+		dependents: make(map[string][]*Task),
+		skipped:    make(map[string]bool),
+		remaining:  len(tasks),
+	}
+	st.cond = sync.NewCond(&st.mu)
+
+	taskMap := make(map[string]*Task)
+	for _, task := range tasks {
+		if _, exists := taskMap[task.ID]; exists {
+			return nil, fmt.Errorf("任务 %s 重复", task.ID)
+		}
+		taskMap[task.ID] = task
+	}
+
+	for _, task := range tasks {
+		for _, dep := range task.Deps {
+			if _, exists := taskMap[dep]; !exists {
+				return nil, fmt.Errorf("任务 %s 依赖的任务 %s 不存在", task.ID, dep)
+			}
+			st.dependents[dep] = append(st.dependents[dep], task)
+		}
+		st.pending[task.ID] = len(task.Deps)
+	}
+
+	if err := checkCycle(tasks, st.pending, st.dependents); err != nil {
+		return nil, err
+	}
+
+	// 将无依赖的任务轮流分配到各个队列
+	next := 0
+	for _, task := range tasks {
+		if st.pending[task.ID] == 0 {
+			st.queues[next] = append(st.queues[next], task)
+			st.ready++
+			next = (next + 1) % workers
+		}
+	}
+
+	return st, nil
+}
+
+// checkCycle 检查任务依赖中是否存在环
+func checkCycle(tasks []*Task, pending map[string]int, dependents map[string][]*Task) error {
+	remaining := make(map[string]int, len(pending))
+	queue := make([]string, 0)
+	for id, count := range pending {
+		remaining[id] = count
+		if count == 0 {
+			queue = append(queue, id)
+		}
+	}
+
+	visited := 0
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, dependent := range dependents[id] {
+			remaining[dependent.ID]--
+			if remaining[dependent.ID] == 0 {
+				queue = append(queue, dependent.ID)
+			}
+		}
+	}
+
+	if visited != len(tasks) {
+		return fmt.Errorf("任务依赖存在循环")
+	}
+	return nil
+}
+
+// work 工作协程主循环
+func (st *runState) work(id int) {
+	for {
+		task := st.next(id)
+		if task == nil {
+			return
+		}
+		st.finish(id, task, task.Run())
+	}
+}
+
+// next 获取下一个任务，没有可执行的任务时等待，全部完成时返回nil
+func (st *runState) next(id int) *Task {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for {
+		if st.remaining == 0 {
+			return nil
+		}
+		if st.ready > 0 {
+			st.ready--
+			return st.take(id)
+		}
+		st.cond.Wait()
+	}
+}
+
+// take 从本地队列尾部取任务，本地为空时从其他队列头部窃取
+func (st *runState) take(id int) *Task {
+	if queue := st.queues[id]; len(queue) > 0 {
+		task := queue[len(queue)-1]
+		st.queues[id] = queue[:len(queue)-1]
+		return task
+	}
+
+	for offset := 1; offset < len(st.queues); offset++ {
+		victim := (id + offset) % len(st.queues)
+		if queue := st.queues[victim]; len(queue) > 0 {
+			task := queue[0]
+			st.queues[victim] = queue[1:]
+			return task
+		}
+	}
+	return nil
+}
+
+// finish 标记任务完成，并将依赖已满足的任务放入本地队列；任务失败时跳过依赖它的任务
+func (st *runState) finish(id int, task *Task, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.remaining--
+	if err != nil {
+		st.errs = append(st.errs, err)
+		st.skip(task)
+	} else {
+		for _, dependent := range st.dependents[task.ID] {
+			st.pending[dependent.ID]--
+			if st.pending[dependent.ID] == 0 && !st.skipped[dependent.ID] {
+				st.queues[id] = append(st.queues[id], dependent)
+				st.ready++
+			}
+		}
+	}
+	st.cond.Broadcast()
+}
+
+// skip 跳过直接或间接依赖指定任务的所有任务
+func (st *runState) skip(task *Task) {
+	for _, dependent := range st.dependents[task.ID] {
+		if st.skipped[dependent.ID] {
+			continue
+		}
+		st.skipped[dependent.ID] = true
+		st.remaining--
+		st.skip(dependent)
+	}
+}
@@ -0,0 +1,123 @@
+package sink
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/game-data-builder/internal/output"
+)
+
+// 归档格式
+const (
+	archiveZip   = "zip"
+	archiveTarGz = "tar.gz"
+)
+
+// archiveTime 归档中固定的修改时间，保证输出稳定
+var archiveTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ArchiveSink 将所有文件打成一个归档文件，每次构建整体替换
+type ArchiveSink struct {
+	path   string // 归档文件路径
+	format string // 归档格式：zip 或 tar.gz
+}
+
+// NewArchiveSink 创建归档输出目标，选项 path 为归档文件路径，format 默认按扩展名判断
+func NewArchiveSink(options map[string]interface{}) (*ArchiveSink, error) {
+	path, _ := options["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("archive 输出目标必须配置 path")
+	}
+
+	format, _ := options["format"].(string)
+	if format == "" {
+		format = archiveZip
+		if filepath.Ext(path) == ".gz" || filepath.Ext(path) == ".tgz" {
+			format = archiveTarGz
+		}
+	}
+	if format != archiveZip && format != archiveTarGz {
+		return nil, fmt.Errorf("不支持的归档格式: %s", format)
+	}
+	return &ArchiveSink{path: path, format: format}, nil
+}
+
+// Name 归档文件路径
+func (s *ArchiveSink) Name() string {
+	return s.path
+}
+
+// Write 生成归档后先写入临时文件再替换，避免留下不完整的归档
+func (s *ArchiveSink) Write(files []File) (int, error) {
+	var content []byte
+	var err error
+	if s.format == archiveTarGz {
+		content, err = tarGzArchive(files)
+	} else {
+		content, err = zipArchive(files)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return 0, err
+	}
+	if err := output.WriteFileAtomic(s.path, content, false); err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+// zipArchive 打成 zip 归档
+func zipArchive(files []File) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range files {
+		fileWriter, err := writer.CreateHeader(&zip.FileHeader{Name: file.Path, Method: zip.Deflate, Modified: archiveTime})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fileWriter.Write(file.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tarGzArchive 打成 tar.gz 归档
+func tarGzArchive(files []File) ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	writer := tar.NewWriter(gzipWriter)
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    file.Path,
+			Mode:    0644,
+			Size:    int64(len(file.Content)),
+			ModTime: archiveTime,
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(file.Content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// HTTPSink 将每个文件发送到 <url>/<相对路径>，适用于支持 PUT 上传的配置服务
+type HTTPSink struct {
+	url     string
+	method  string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPSink 创建 HTTP 输出目标，选项：url（必填）、method（默认 PUT）、headers、timeoutMs（默认 10000）
+func NewHTTPSink(options map[string]interface{}) (*HTTPSink, error) {
+	url, _ := options["url"].(string)
+	if url == "" {
+		return nil, fmt.Errorf("http 输出目标必须配置 url")
+	}
+
+	method := http.MethodPut
+	if m, ok := options["method"].(string); ok && m != "" {
+		method = strings.ToUpper(m)
+	}
+	if method != http.MethodPut && method != http.MethodPost {
+		return nil, fmt.Errorf("http 输出目标不支持的 method: %s", method)
+	}
+
+	headers := make(map[string]string)
+	if raw, ok := options["headers"].(map[string]interface{}); ok {
+		for name, value := range raw {
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("http 输出目标的请求头 %s 必须是字符串", name)
+			}
+			headers[name] = text
+		}
+	}
+
+	timeout := 10 * time.Second
+	if ms, ok := options["timeoutMs"].(float64); ok && ms > 0 {
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	return &HTTPSink{
+		url:     strings.TrimSuffix(url, "/"),
+		method:  method,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name 目标地址
+func (s *HTTPSink) Name() string {
+	return s.url
+}
+
+// Write 逐个发送文件，遇到失败立即返回
+func (s *HTTPSink) Write(files []File) (int, error) {
+	for i, file := range files {
+		if err := s.send(file); err != nil {
+			return i, err
+		}
+	}
+	return len(files), nil
+}
+
+// send 发送单个文件，2xx 以外的状态码视为失败
+func (s *HTTPSink) send(file File) error {
+	req, err := http.NewRequest(s.method, s.url+"/"+file.Path, bytes.NewReader(file.Content))
+	if err != nil {
+		return err
+	}
+	contentType := mime.TypeByExtension(path.Ext(file.Path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("发送 %s 失败: HTTP %d", file.Path, resp.StatusCode)
+	}
+	return nil
+}
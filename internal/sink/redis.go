@@ -0,0 +1,109 @@
+package sink
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RedisSink 将每个文件写入 Redis，键为 <keyPrefix><相对路径>，值为文件内容
+// 直接使用 RESP 协议，所有命令以流水线方式发送
+type RedisSink struct {
+	addr      string
+	password  string
+	db        int
+	keyPrefix string
+	timeout   time.Duration
+}
+
+// NewRedisSink 创建 Redis 输出目标，选项：addr（必填）、password、db、keyPrefix、timeoutMs（默认 10000）
+func NewRedisSink(options map[string]interface{}) (*RedisSink, error) {
+	addr, _ := options["addr"].(string)
+	if addr == "" {
+		return nil, fmt.Errorf("redis 输出目标必须配置 addr")
+	}
+
+	s := &RedisSink{addr: addr, timeout: 10 * time.Second}
+	s.password, _ = options["password"].(string)
+	s.keyPrefix, _ = options["keyPrefix"].(string)
+	if db, ok := options["db"].(float64); ok {
+		if db < 0 {
+			return nil, fmt.Errorf("redis 输出目标的 db 不能为负数")
+		}
+		s.db = int(db)
+	}
+	if ms, ok := options["timeoutMs"].(float64); ok && ms > 0 {
+		s.timeout = time.Duration(ms) * time.Millisecond
+	}
+	return s, nil
+}
+
+// Name 服务器地址
+func (s *RedisSink) Name() string {
+	return "redis://" + s.addr
+}
+
+// Write 以流水线方式发送 SET 命令，并逐条检查回复
+func (s *RedisSink) Write(files []File) (int, error) {
+	conn, err := net.DialTimeout("tcp", s.addr, s.timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	commands := make([][]string, 0, len(files)+2)
+	if s.password != "" {
+		commands = append(commands, []string{"AUTH", s.password})
+	}
+	if s.db != 0 {
+		commands = append(commands, []string{"SELECT", strconv.Itoa(s.db)})
+	}
+	setStart := len(commands)
+	for _, file := range files {
+		commands = append(commands, []string{"SET", s.keyPrefix + file.Path, string(file.Content)})
+	}
+
+	writer := bufio.NewWriter(conn)
+	for _, command := range commands {
+		writeCommand(writer, command)
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+
+	reader := bufio.NewReader(conn)
+	for i, command := range commands {
+		if err := readReply(reader); err != nil {
+			if i >= setStart {
+				return i - setStart, fmt.Errorf("写入 %s 失败: %v", command[1], err)
+			}
+			return 0, fmt.Errorf("%s 失败: %v", command[0], err)
+		}
+	}
+	return len(files), nil
+}
+
+// writeCommand 按 RESP 数组格式写入命令
+func writeCommand(writer *bufio.Writer, args []string) {
+	fmt.Fprintf(writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(writer, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+}
+
+// readReply 读取一条回复，错误回复转换为 error；AUTH、SELECT 和 SET 只会返回单行回复
+func readReply(reader *bufio.Reader) error {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "-") {
+		return fmt.Errorf("%s", line[1:])
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/remote"
+)
+
+// File 待输出的文件
+type File struct {
+	Path    string // 相对于输出目标根目录的路径
+	Format  string // 所属格式
+	Content []byte // 文件内容
+}
+
+// ISink 定义了输出目标的接口
+type ISink interface {
+	// Name 输出目标的名称，用于日志和错误信息
+	Name() string
+	// Write 将文件写入输出目标，返回实际写入或上传的文件数量
+	Write(files []File) (int, error)
+}
+
+// Filter 只保留指定格式的文件，formats 为空时返回全部文件
+func Filter(files []File, formats []string) []File {
+	if len(formats) == 0 {
+		return files
+	}
+	wanted := make(map[string]bool, len(formats))
+	for _, format := range formats {
+		wanted[format] = true
+	}
+	filtered := make([]File, 0, len(files))
+	for _, file := range files {
+		if wanted[file.Format] {
+			filtered = append(filtered, file)
+		}
+	}
+	return filtered
+}
+
+// New 根据配置创建输出目标；dir 类型需要输出清单和版本文件，由构建器自行处理
+func New(cfg config.SinkConfig) (ISink, error) {
+	switch cfg.Type {
+	case config.SinkArchive:
+		return NewArchiveSink(cfg.Options)
+	case config.SinkHTTP:
+		return NewHTTPSink(cfg.Options)
+	case config.SinkRedis:
+		return NewRedisSink(cfg.Options)
+	case config.SinkSFTP:
+		var remoteConfig config.RemoteSyncConfig
+		if err := decodeOptions(cfg.Options, &remoteConfig); err != nil {
+			return nil, err
+		}
+		syncer, err := remote.NewSyncer(remoteConfig)
+		if err != nil {
+			return nil, err
+		}
+		return NewSyncerSink(remoteConfig.Host, syncer), nil
+	case "s3", "oss", "cos":
+		var target config.SyncTarget
+		if err := decodeOptions(cfg.Options, &target); err != nil {
+			return nil, err
+		}
+		target.Type = cfg.Type
+		syncer, err := remote.NewTargetSyncer(target)
+		if err != nil {
+			return nil, err
+		}
+		return NewSyncerSink(fmt.Sprintf("%s://%s/%s", target.Type, target.Bucket, target.Prefix), syncer), nil
+	default:
+		return nil, fmt.Errorf("不支持的输出目标类型: %s", cfg.Type)
+	}
+}
+
+// decodeOptions 将选项解析到对应的配置结构
+func decodeOptions(options map[string]interface{}, target interface{}) error {
+	data, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("选项格式错误: %v", err)
+	}
+	return nil
+}
+
+// SyncerSink 将远程同步器包装为输出目标
+type SyncerSink struct {
+	name   string
+	syncer remote.ISyncer
+}
+
+// NewSyncerSink 创建远程同步输出目标
+func NewSyncerSink(name string, syncer remote.ISyncer) *SyncerSink {
+	return &SyncerSink{name: name, syncer: syncer}
+}
+
+// Name 远程地址
+func (s *SyncerSink) Name() string {
+	return s.name
+}
+
+// Write 上传内容有变化的文件
+func (s *SyncerSink) Write(files []File) (int, error) {
+	remoteFiles := make([]remote.File, 0, len(files))
+	for _, file := range files {
+		remoteFiles = append(remoteFiles, remote.File{Path: file.Path, Content: file.Content})
+	}
+	return s.syncer.Sync(remoteFiles)
+}
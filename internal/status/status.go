@@ -0,0 +1,162 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+)
+
+// 状态文件名
+const (
+	FileName  = "status.json" // 最近一次构建的状态
+	BadgeName = "status.svg"  // 状态徽章
+)
+
+// DefaultLabel 徽章左侧的默认文字
+const DefaultLabel = "data"
+
+// 徽章颜色
+const (
+	colorPassing  = "#4c1"
+	colorDegraded = "#dfb317"
+	colorFailing  = "#e05d44"
+)
+
+// Status 最近一次构建的状态，供 Wiki、策划门户等看板展示
+type Status struct {
+	BuildTime   string       `json:"buildTime"`             // 构建开始时间（RFC 3339）
+	Version     string       `json:"version,omitempty"`     // 输出版本：cas 结构为版本号，否则为源数据的 git 提交
+	Success     bool         `json:"success"`               // 是否构建成功
+	ErrorCount  int          `json:"errorCount"`            // 错误数：验证错误或转换错误的个数，其他失败计为 1
+	DurationMs  int64        `json:"durationMs"`            // 构建耗时（毫秒）
+	Message     string       `json:"message"`               // 失败原因或结果说明
+	Degraded    []string     `json:"degraded,omitempty"`    // 不可用的可选功能
+	LastSuccess *LastSuccess `json:"lastSuccess,omitempty"` // 最近一次成功的构建，构建失败时沿用上一个状态文件中的记录
+}
+
+// LastSuccess 最近一次成功的构建
+type LastSuccess struct {
+	BuildTime string `json:"buildTime"`
+	Version   string `json:"version,omitempty"`
+}
+
+// New 根据构建结果创建状态
+func New(err error, buildTime time.Time, duration time.Duration, version string) *Status {
+	s := &Status{
+		BuildTime:  buildTime.UTC().Format(time.RFC3339),
+		Version:    version,
+		Success:    err == nil,
+		DurationMs: duration.Milliseconds(),
+	}
+	if err == nil {
+		s.Message = "构建成功"
+		s.LastSuccess = &LastSuccess{BuildTime: s.BuildTime, Version: version}
+		return s
+	}
+
+	s.Message = err.Error()
+	s.ErrorCount = 1
+	var validation *model.ValidationError
+	var convertErrs *model.ConvertErrors
+	switch {
+	case errors.As(err, &validation) && len(validation.Errors) > 0:
+		s.ErrorCount = len(validation.Errors)
+	case errors.As(err, &convertErrs) && len(convertErrs.Errors) > 0:
+		s.ErrorCount = len(convertErrs.Errors)
+	}
+	return s
+}
+
+// Load 读取目录中的状态文件，不存在时返回 nil
+func Load(dir string) (*Status, error) {
+	content, err := os.ReadFile(filepath.Join(dir, FileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Status
+	if err := json.Unmarshal(content, &s); err != nil {
+		return nil, fmt.Errorf("%s: %v", FileName, err)
+	}
+	return &s, nil
+}
+
+// Write 以原子替换的方式写入 status.json 和 status.svg，看板读取时不会看到写了一半的文件
+func (s *Status) Write(dir, label string, fsync bool) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := output.WriteFileAtomic(filepath.Join(dir, FileName), content, fsync); err != nil {
+		return err
+	}
+	return output.WriteFileAtomic(filepath.Join(dir, BadgeName), s.Badge(label), fsync)
+}
+
+// Badge 生成 shields.io 风格的 SVG 徽章，如 data | passing 20240501-080000
+func (s *Status) Badge(label string) []byte {
+	if label == "" {
+		label = DefaultLabel
+	}
+
+	message, color := "passing", colorPassing
+	switch {
+	case !s.Success:
+		message, color = fmt.Sprintf("failing: %d errors", s.ErrorCount), colorFailing
+		if s.ErrorCount == 1 {
+			message = "failing: 1 error"
+		}
+	case len(s.Degraded) > 0:
+		message, color = "degraded", colorDegraded
+	}
+	if s.Success && s.Version != "" {
+		message += " " + s.Version
+	}
+
+	labelWidth := textWidth(label) + 10
+	messageWidth := textWidth(message) + 10
+	width := labelWidth + messageWidth
+	title := html.EscapeString(label + ": " + message)
+	label, message = html.EscapeString(label), html.EscapeString(message)
+
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">
+<title>%s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%d" y="14">%s</text>
+<text x="%d" y="14">%s</text>
+</g>
+</svg>
+`, width, title, title, width, labelWidth, labelWidth, messageWidth, color, width,
+		labelWidth/2, label, labelWidth+messageWidth/2, message)
+	return []byte(svg)
+}
+
+// textWidth 估算文字宽度（像素），中文等全角字符按两倍宽度计算
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		if utf8.RuneLen(r) > 1 {
+			width += 12
+		} else {
+			width += 7
+		}
+	}
+	return width
+}
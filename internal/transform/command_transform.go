@@ -0,0 +1,123 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// sheetPayload 与外部命令交换的表数据
+type sheetPayload struct {
+	Name    string                   `json:"name"`
+	Columns []columnPayload          `json:"columns"`
+	Rows    []map[string]interface{} `json:"rows"`
+	Meta    map[string]interface{}   `json:"meta"`
+}
+
+// columnPayload 与外部命令交换的列信息
+type columnPayload struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Comment string `json:"comment"`
+}
+
+// CommandTransform 调用外部命令处理数据表，通过标准输入传入JSON，从标准输出读取处理后的JSON
+type CommandTransform struct {
+	command []string
+	timeout time.Duration
+}
+
+// NewCommandTransform 创建外部命令处理步骤
+func NewCommandTransform(command []string, timeoutMs int) (*CommandTransform, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("command 处理必须指定 command")
+	}
+
+	timeout := time.Minute
+	if timeoutMs > 0 {
+		timeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+	return &CommandTransform{command: command, timeout: timeout}, nil
+}
+
+// Apply 处理单个数据表
+func (t *CommandTransform) Apply(sheet *model.DataSheet) error {
+	return t.ApplyContext(context.Background(), sheet)
+}
+
+// ApplyContext 处理单个数据表，ctx 取消或超时后终止命令
+func (t *CommandTransform) ApplyContext(ctx context.Context, sheet *model.DataSheet) error {
+	payload := sheetPayload{Name: sheet.Name, Rows: sheet.Rows, Meta: sheet.Meta}
+	for _, col := range sheet.Columns {
+		payload.Columns = append(payload.Columns, columnPayload{Name: col.Name, Type: col.Type, Comment: col.Comment})
+	}
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, t.command[0], t.command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("命令 %v 执行失败: %v: %s", t.command, err, stderr.String())
+	}
+
+	var output sheetPayload
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return fmt.Errorf("命令 %v 的输出不是有效的JSON: %v", t.command, err)
+	}
+
+	// 保留已有列的元数据，新增列使用命令返回的信息
+	if output.Columns != nil {
+		existing := make(map[string]model.ColumnInfo)
+		for _, col := range sheet.Columns {
+			existing[col.Name] = col
+		}
+
+		columns := make([]model.ColumnInfo, 0, len(output.Columns))
+		for _, col := range output.Columns {
+			colInfo, exists := existing[col.Name]
+			if !exists {
+				colInfo = model.ColumnInfo{Name: col.Name, Comment: col.Comment}
+			}
+			if col.Type != "" {
+				colInfo.Type = col.Type
+			}
+			columns = append(columns, colInfo)
+		}
+		sheet.Columns = columns
+	}
+
+	// JSON数字解码为float64，整数列和枚举列还原为int
+	for _, row := range output.Rows {
+		for _, col := range sheet.Columns {
+			_, isEnum := model.EnumName(col.Type)
+			if col.Type != "int" && col.Type != "integer" && !isEnum {
+				continue
+			}
+			if val, ok := model.RowValue(row, col.Name); ok {
+				if num, ok := val.(float64); ok {
+					model.SetRowValue(row, col.Name, int(num))
+				}
+			}
+		}
+	}
+
+	sheet.Rows = output.Rows
+	sheet.RowNumbers = nil // 外部命令可能增删行，无法再对应源文件的行号
+	if output.Meta != nil {
+		sheet.Meta = output.Meta
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/game-data-builder/internal/expr"
+	"github.com/game-data-builder/internal/model"
+)
+
+// ExprTransform 按表达式计算并写入列值，列不存在时新增
+type ExprTransform struct {
+	column     string
+	columnType string
+	expr       *expr.Expr
+}
+
+// NewExprTransform 创建表达式处理步骤
+func NewExprTransform(column, columnType, source string) (*ExprTransform, error) {
+	if column == "" {
+		return nil, fmt.Errorf("expr 处理必须指定 column")
+	}
+
+	compiled, err := expr.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+	return &ExprTransform{column: column, columnType: columnType, expr: compiled}, nil
+}
+
+// Apply 处理单个数据表
+func (t *ExprTransform) Apply(sheet *model.DataSheet) error {
+	colType := t.columnType
+	exists := false
+	for _, col := range sheet.Columns {
+		if col.Name == t.column {
+			exists = true
+			if colType == "" {
+				colType = col.Type
+			}
+			break
+		}
+	}
+	if !exists {
+		if colType == "" {
+			colType = "string"
+		}
+		sheet.Columns = append(sheet.Columns, model.ColumnInfo{Name: t.column, Type: colType})
+	}
+
+	for rowIndex, row := range sheet.Rows {
+		val, err := t.expr.Eval(row)
+		if err != nil {
+			return fmt.Errorf("row %d: %v", sheet.RowNumber(rowIndex), err)
+		}
+		typed, err := expr.ToType(val, colType)
+		if err != nil {
+			return fmt.Errorf("row %d, column %s: %v", sheet.RowNumber(rowIndex), t.column, err)
+		}
+		model.SetRowValue(row, t.column, typed)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package transform
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// ITransform 定义了数据表处理步骤的接口
+type ITransform interface {
+	// Apply 处理单个数据表
+	Apply(sheet *model.DataSheet) error
+}
+
+// IContextTransform 可选接口，调用外部命令的处理步骤通过它支持取消
+type IContextTransform interface {
+	// ApplyContext 处理单个数据表，ctx 取消后应尽快终止
+	ApplyContext(ctx context.Context, sheet *model.DataSheet) error
+}
+
+// step 处理步骤及其适用范围
+type step struct {
+	sheets    []string
+	transform ITransform
+}
+
+// Pipeline 按配置顺序执行的处理流水线
+type Pipeline struct {
+	steps []step
+}
+
+// NewPipeline 根据配置创建处理流水线
+func NewPipeline(cfg *config.TransformConfig) (*Pipeline, error) {
+	pipeline := &Pipeline{steps: make([]step, 0)}
+	if cfg == nil {
+		return pipeline, nil
+	}
+
+	for i, rule := range cfg.Transforms {
+		var transform ITransform
+		var err error
+		switch rule.Type {
+		case "expr":
+			transform, err = NewExprTransform(rule.Column, rule.ColumnType, rule.Expr)
+		case "command":
+			transform, err = NewCommandTransform(rule.Command, rule.TimeoutMs)
+		default:
+			err = fmt.Errorf("未知的处理类型 %q", rule.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个处理步骤无效: %v", i+1, err)
+		}
+
+		pipeline.steps = append(pipeline.steps, step{sheets: rule.Sheets, transform: transform})
+	}
+
+	return pipeline, nil
+}
+
+// Empty 检查流水线是否为空
+func (p *Pipeline) Empty() bool {
+	return len(p.steps) == 0
+}
+
+// Run 依次对所有适用的表执行处理步骤
+func (p *Pipeline) Run(sheets []*model.DataSheet) error {
+	return p.RunContext(context.Background(), sheets)
+}
+
+// RunContext 依次对所有适用的表执行处理步骤，ctx 取消后不再处理后续的表
+func (p *Pipeline) RunContext(ctx context.Context, sheets []*model.DataSheet) error {
+	for _, s := range p.steps {
+		for _, sheet := range sheets {
+			if !matchSheet(s.sheets, sheet.Name) {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var err error
+			if contextTransform, ok := s.transform.(IContextTransform); ok {
+				err = contextTransform.ApplyContext(ctx, sheet)
+			} else {
+				err = s.transform.Apply(sheet)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return fmt.Errorf("sheet %s: %v", sheet.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchSheet 检查表名是否匹配任一模式，模式为空时匹配所有表
+func matchSheet(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
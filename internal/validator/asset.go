@@ -0,0 +1,70 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// ValidateAssets 检查资源列（注释中带 资源:<类型> 的列）中的路径在资源目录中是否存在，缺失的文件按行报告
+//
+// 每个资源目录下先查找 <dir>/<值>，不存在时依次追加该类型配置的扩展名；数组列的每个元素分别检查，空值不检查
+func ValidateAssets(sheets []*model.DataSheet, cfg config.AssetConfig) []*model.ErrorInfo {
+	errors := make([]*model.ErrorInfo, 0)
+	if len(cfg.Roots) == 0 {
+		return errors
+	}
+
+	exists := make(map[string]bool)
+	fileExists := func(path string) bool {
+		found, checked := exists[path]
+		if !checked {
+			info, err := os.Stat(path)
+			found = err == nil && !info.IsDir()
+			exists[path] = found
+		}
+		return found
+	}
+	find := func(assetType config.AssetType, value string) bool {
+		for _, root := range cfg.Roots {
+			path := filepath.Join(root, assetType.Dir, filepath.FromSlash(value))
+			if fileExists(path) {
+				return true
+			}
+			for _, ext := range assetType.Extensions {
+				if fileExists(path + ext) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, sheet := range sheets {
+		for _, col := range sheet.Columns {
+			if col.Asset == "" {
+				continue
+			}
+			assetType := cfg.Types[col.Asset]
+			for rowIndex, row := range sheet.Rows {
+				val, _ := model.RowValue(row, col.Name)
+				for _, item := range refValues(val) {
+					value := fmt.Sprint(item)
+					if value == "" || find(assetType, value) {
+						continue
+					}
+					errors = append(errors, &model.ErrorInfo{
+						Sheet:  sheet.Name,
+						Row:    sheet.RowNumber(rowIndex),
+						Column: col.Name,
+						Msg:    fmt.Sprintf("%s 资源 %s 不存在", col.Asset, value),
+					})
+				}
+			}
+		}
+	}
+	return errors
+}
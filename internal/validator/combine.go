@@ -0,0 +1,184 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// keyOrigin 主键值首次出现的位置
+type keyOrigin struct {
+	sheet string
+	row   int
+}
+
+// CombinePart 合并表中来自一个源表的部分，列名已按合并配置映射，行已按条件过滤
+type CombinePart struct {
+	Sheet      string                   // 源表名
+	Columns    []model.ColumnInfo       // 映射后的列
+	Rows       []map[string]interface{} // 参与合并的行
+	RowNumbers []int                    // 每行在源表中的行号
+}
+
+// ValidateCombineColumns 严格合并时验证各源表映射后的列与第一个源表完全相同，缺少或多出的列记在合并表上
+func ValidateCombineColumns(name string, sources []*CombinePart) []*model.ErrorInfo {
+	errors := make([]*model.ErrorInfo, 0)
+	if len(sources) == 0 {
+		return errors
+	}
+
+	first := sources[0]
+	expected := columnNames(first.Columns)
+	for _, source := range sources[1:] {
+		actual := columnNames(source.Columns)
+		missing, extra := make([]string, 0), make([]string, 0)
+		for column := range expected {
+			if !actual[column] {
+				missing = append(missing, column)
+			}
+		}
+		for column := range actual {
+			if !expected[column] {
+				extra = append(extra, column)
+			}
+		}
+		sort.Strings(missing)
+		sort.Strings(extra)
+		if len(missing) > 0 {
+			errors = append(errors, &model.ErrorInfo{
+				Sheet: name,
+				Msg:   fmt.Sprintf("源表 %s 缺少 %s 中的列: %s", source.Sheet, first.Sheet, strings.Join(missing, ", ")),
+			})
+		}
+		if len(extra) > 0 {
+			errors = append(errors, &model.ErrorInfo{
+				Sheet: name,
+				Msg:   fmt.Sprintf("源表 %s 多出 %s 中没有的列: %s", source.Sheet, first.Sheet, strings.Join(extra, ", ")),
+			})
+		}
+	}
+	return errors
+}
+
+// columnNames 列名集合
+func columnNames(columns []model.ColumnInfo) map[string]bool {
+	names := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		names[col.Name] = true
+	}
+	return names
+}
+
+// ResolveCombineKeys 按主键去除合并表中的重复行：keepLast 为 false 时保留先出现的行，否则保留后出现的行。
+// 直接修改各源表部分的行，返回每个被去除的行的说明；主键为空的行保留，由 ValidateCombine 报告
+func ResolveCombineKeys(keyColumn string, sources []*CombinePart, keepLast bool) []string {
+	// 先找出每个主键最终保留的位置
+	type position struct{ source, row int }
+	kept := make(map[string]position)
+	for i, source := range sources {
+		for rowIndex, row := range source.Rows {
+			key, exists := model.RowValue(row, keyColumn)
+			if !exists || key == nil || key == "" {
+				continue
+			}
+			text := fmt.Sprint(key)
+			if _, seen := kept[text]; !seen || keepLast {
+				kept[text] = position{i, rowIndex}
+			}
+		}
+	}
+
+	dropped := make([]string, 0)
+	for i, source := range sources {
+		rows := make([]map[string]interface{}, 0, len(source.Rows))
+		rowNumbers := make([]int, 0, len(source.Rows))
+		for rowIndex, row := range source.Rows {
+			key, exists := model.RowValue(row, keyColumn)
+			if exists && key != nil && key != "" {
+				winner := kept[fmt.Sprint(key)]
+				if winner != (position{i, rowIndex}) {
+					dropped = append(dropped, fmt.Sprintf("源表 %s 第 %d 行的主键 %v 与源表 %s 第 %d 行重复，已去除",
+						source.Sheet, source.RowNumbers[rowIndex], key, sources[winner.source].Sheet, sources[winner.source].RowNumbers[winner.row]))
+					continue
+				}
+			}
+			rows = append(rows, row)
+			rowNumbers = append(rowNumbers, source.RowNumbers[rowIndex])
+		}
+		source.Rows = rows
+		source.RowNumbers = rowNumbers
+	}
+	return dropped
+}
+
+// ValidateCombine 验证合并表：各源表的同名列类型一致，合并后的主键非空且唯一
+// 错误记在合并表上，消息中注明冲突的行来自哪个源表
+func ValidateCombine(name, keyColumn string, sources []*CombinePart) []*model.ErrorInfo {
+	errors := make([]*model.ErrorInfo, 0)
+	if len(sources) == 0 {
+		return errors
+	}
+
+	// 同名列以最先出现该列的源表的类型为准
+	types := make(map[string]string)
+	owners := make(map[string]string)
+	for _, source := range sources {
+		for _, col := range source.Columns {
+			expected, exists := types[col.Name]
+			if !exists {
+				types[col.Name] = col.Type
+				owners[col.Name] = source.Sheet
+				continue
+			}
+			if expected != col.Type {
+				errors = append(errors, &model.ErrorInfo{
+					Sheet:  name,
+					Column: col.Name,
+					Msg:    fmt.Sprintf("源表 %s 的列类型 %s 与 %s 中的 %s 不一致", source.Sheet, col.Type, owners[col.Name], expected),
+				})
+			}
+		}
+	}
+
+	// 未指定主键时与普通表一样使用第一列
+	if keyColumn == "" {
+		if len(sources[0].Columns) == 0 {
+			return errors
+		}
+		keyColumn = sources[0].Columns[0].Name
+	}
+
+	// 主键按文本比较，避免列表等不可比较的值导致异常
+	seen := make(map[string]keyOrigin)
+	for _, source := range sources {
+		for rowIndex, row := range source.Rows {
+			rowNumber := source.RowNumbers[rowIndex]
+			key, exists := model.RowValue(row, keyColumn)
+			if !exists || key == nil || key == "" {
+				errors = append(errors, &model.ErrorInfo{
+					Sheet:  name,
+					Row:    rowNumber,
+					Column: keyColumn,
+					Msg:    fmt.Sprintf("源表 %s 第 %d 行的主键为空", source.Sheet, rowNumber),
+				})
+				continue
+			}
+
+			text := fmt.Sprint(key)
+			if origin, exists := seen[text]; exists {
+				errors = append(errors, &model.ErrorInfo{
+					Sheet:  name,
+					Row:    rowNumber,
+					Column: keyColumn,
+					Msg:    fmt.Sprintf("源表 %s 第 %d 行的主键 %v 与源表 %s 第 %d 行重复", source.Sheet, rowNumber, key, origin.sheet, origin.row),
+				})
+				continue
+			}
+			seen[text] = keyOrigin{sheet: source.Sheet, row: rowNumber}
+		}
+	}
+
+	return errors
+}
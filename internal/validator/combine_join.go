@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// JoinCombine 横向连接合并表：以第一个源表为左表，其余源表按连接键（on 中配置的列，未配置时为 keyColumn）
+// 与左表的 keyColumn 匹配，把它们的列连接到左表的行上，类似 SQL 的 LEFT JOIN；inner 为 true 时只保留在所有源表中都有匹配的行
+//
+// 被连接的列在左连接中改为选填；连接列本身不输出。左表主键为空或重复、被连接表的连接键重复、列名冲突记为错误
+func JoinCombine(name, keyColumn string, sources []*CombinePart, on map[string]string, inner bool) ([]model.ColumnInfo, []map[string]interface{}, []*model.ErrorInfo) {
+	if len(sources) == 0 {
+		return nil, nil, nil
+	}
+	left := sources[0]
+	if keyColumn == "" && len(left.Columns) > 0 {
+		keyColumn = left.Columns[0].Name
+	}
+	errors := ValidateCombine(name, keyColumn, sources[:1])
+
+	columns := append([]model.ColumnInfo{}, left.Columns...)
+	owners := make(map[string]string, len(columns))
+	for _, col := range columns {
+		owners[col.Name] = left.Sheet
+	}
+
+	// 为每个被连接的表按连接键建立索引，并确定要连接的列
+	type joined struct {
+		rows    map[string]map[string]interface{}
+		columns []string
+	}
+	joins := make([]joined, 0, len(sources)-1)
+	for _, source := range sources[1:] {
+		joinKey := on[source.Sheet]
+		if joinKey == "" {
+			joinKey = keyColumn
+		}
+
+		j := joined{rows: make(map[string]map[string]interface{})}
+		origins := make(map[string]int)
+		for rowIndex, row := range source.Rows {
+			key, exists := model.RowValue(row, joinKey)
+			if !exists || key == nil || key == "" {
+				continue
+			}
+			text := fmt.Sprint(key)
+			if origin, duplicated := origins[text]; duplicated {
+				errors = append(errors, &model.ErrorInfo{
+					Sheet:  name,
+					Row:    source.RowNumbers[rowIndex],
+					Column: joinKey,
+					Msg:    fmt.Sprintf("源表 %s 第 %d 行的连接键 %v 与第 %d 行重复", source.Sheet, source.RowNumbers[rowIndex], key, origin),
+				})
+				continue
+			}
+			origins[text] = source.RowNumbers[rowIndex]
+			j.rows[text] = row
+		}
+
+		for _, col := range source.Columns {
+			if col.Name == joinKey {
+				continue
+			}
+			if owner, exists := owners[col.Name]; exists {
+				errors = append(errors, &model.ErrorInfo{
+					Sheet:  name,
+					Column: col.Name,
+					Msg:    fmt.Sprintf("源表 %s 的列 %s 与 %s 中的列重名，请在 columns 中映射为其他名称", source.Sheet, col.Name, owner),
+				})
+				continue
+			}
+			owners[col.Name] = source.Sheet
+			col.IsKey = false
+			if !inner {
+				col.Required = false
+			}
+			columns = append(columns, col)
+			j.columns = append(j.columns, col.Name)
+		}
+		joins = append(joins, j)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(left.Rows))
+	for _, row := range left.Rows {
+		key, _ := model.RowValue(row, keyColumn)
+		merged := make(map[string]interface{}, len(row))
+		for column, val := range row {
+			merged[column] = val
+		}
+
+		matchedAll := true
+		for _, j := range joins {
+			match, exists := j.rows[fmt.Sprint(key)]
+			if key == nil || !exists {
+				matchedAll = false
+				continue
+			}
+			for _, column := range j.columns {
+				if val, exists := model.RowValue(match, column); exists {
+					model.SetRowValue(merged, column, val)
+				}
+			}
+		}
+		if inner && !matchedAll {
+			continue
+		}
+		rows = append(rows, merged)
+	}
+	return columns, rows, errors
+}
@@ -2,14 +2,27 @@ package validator
 
 import (
 	"fmt"
+	"math/rand"
 	"reflect"
+	"time"
 
+	"github.com/game-data-builder/checks"
+	"github.com/game-data-builder/internal/config"
 	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
 )
 
 // DefaultValidator 默认验证器实现
 type DefaultValidator struct {
-	config map[string]interface{}
+	config    map[string]interface{}
+	enums     map[string]*model.EnumDef
+	sampling  *Sampling          // 大表抽样验证的参数，为空时验证全部行
+	rng       *rand.Rand         // 抽样使用的随机数，每次验证抽取不同的行
+	refSheets []*model.DataSheet // 只用于建立引用索引、本身不验证的表
+	buildCfg  *config.Config     // 传给自定义规则的构建配置
+	manifest  *output.Manifest   // 传给自定义规则的上次构建输出清单
+
+	whitelists map[string]map[string]*Whitelist // 表名 -> 列名 -> 列值必须在其中的外部白名单
 }
 
 // NewDefaultValidator 创建默认验证器
@@ -23,19 +36,51 @@ func (v *DefaultValidator) Init(config map[string]interface{}) error {
 	return nil
 }
 
+// SetEnums 设置枚举定义，用于验证枚举列
+func (v *DefaultValidator) SetEnums(enums map[string]*model.EnumDef) {
+	v.enums = enums
+}
+
+// SetSampling 设置大表抽样验证的参数，为空时验证全部行
+func (v *DefaultValidator) SetSampling(sampling *Sampling) {
+	v.sampling = sampling
+	if sampling != nil && v.rng == nil {
+		v.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+}
+
+// SetRefSheets 设置只用于建立引用索引的表，按标签构建时未选中的表仍可被引用
+func (v *DefaultValidator) SetRefSheets(sheets []*model.DataSheet) {
+	v.refSheets = sheets
+}
+
+// SetCheckContext 设置自定义规则可以访问的构建配置和上次构建的输出清单
+func (v *DefaultValidator) SetCheckContext(cfg *config.Config, manifest *output.Manifest) {
+	v.buildCfg = cfg
+	v.manifest = manifest
+}
+
+// SetWhitelists 设置列的外部白名单，键为表名和列名
+func (v *DefaultValidator) SetWhitelists(whitelists map[string]map[string]*Whitelist) {
+	v.whitelists = whitelists
+}
+
 // Validate 验证单个数据表
 func (v *DefaultValidator) Validate(sheet *model.DataSheet) []*model.ErrorInfo {
 	errors := make([]*model.ErrorInfo, 0)
 
-	// 验证每行数据
-	for rowIndex, row := range sheet.Rows {
-		// 验证必填字段
+	// 验证每行数据，开启抽样时只验证抽中的行
+	for _, rowIndex := range v.sampling.rowIndexes(len(sheet.Rows), v.rng) {
+		row := sheet.Rows[rowIndex]
 		for _, col := range sheet.Columns {
+			val, exists := model.RowValue(row, col.Name)
+
+			// 验证必填字段
 			if col.Required {
-				if _, exists := row[col.Name]; !exists || row[col.Name] == nil || row[col.Name] == "" {
+				if !exists || val == nil || val == "" {
 					errors = append(errors, &model.ErrorInfo{
 						Sheet:  sheet.Name,
-						Row:    rowIndex + 4, // 数据行从第4行开始
+						Row:    sheet.RowNumber(rowIndex),
 						Column: col.Name,
 						Msg:    fmt.Sprintf("必填字段不能为空"),
 					})
@@ -43,20 +88,34 @@ func (v *DefaultValidator) Validate(sheet *model.DataSheet) []*model.ErrorInfo {
 			}
 
 			// 验证数据类型
-			if val, exists := row[col.Name]; exists && val != nil && val != "" {
+			if exists && val != nil && val != "" {
 				if !v.validateDataType(val, col.Type) {
 					errors = append(errors, &model.ErrorInfo{
 						Sheet:  sheet.Name,
-						Row:    rowIndex + 4,
+						Row:    sheet.RowNumber(rowIndex),
 						Column: col.Name,
 						Msg:    fmt.Sprintf("数据类型错误，期望 %s，实际 %T", col.Type, val),
 					})
 				}
 			}
 
+			// 验证枚举类型
+			if enumName, ok := model.EnumName(col.Type); ok {
+				if exists && val != nil && val != "" {
+					if msg := v.validateEnum(val, enumName); msg != "" {
+						errors = append(errors, &model.ErrorInfo{
+							Sheet:  sheet.Name,
+							Row:    sheet.RowNumber(rowIndex),
+							Column: col.Name,
+							Msg:    msg,
+						})
+					}
+				}
+			}
+
 			// 验证枚举值
 			if len(col.Options) > 0 {
-				if val, exists := row[col.Name]; exists && val != nil {
+				if exists && val != nil {
 					valStr, ok := val.(string)
 					if !ok {
 						continue // 非字符串类型跳过枚举验证
@@ -73,7 +132,7 @@ func (v *DefaultValidator) Validate(sheet *model.DataSheet) []*model.ErrorInfo {
 					if !valid {
 						errors = append(errors, &model.ErrorInfo{
 							Sheet:  sheet.Name,
-							Row:    rowIndex + 4,
+							Row:    sheet.RowNumber(rowIndex),
 							Column: col.Name,
 							Msg:    fmt.Sprintf("值不在可选范围内，可选值: %v", col.Options),
 						})
@@ -100,34 +159,59 @@ func (v *DefaultValidator) ValidateAll(sheets []*model.DataSheet) []*model.Error
 	refErrors := v.ValidateRef(sheets)
 	errors = append(errors, refErrors...)
 
+	// 验证跨表唯一的 ID 空间和资源文件是否存在
+	if v.buildCfg != nil {
+		errors = append(errors, ValidateIDSpaces(sheets, v.refSheets, v.buildCfg.IDSpaces)...)
+		errors = append(errors, ValidateAssets(sheets, v.buildCfg.Assets)...)
+	}
+
+	// 执行通过 checks 包注册的自定义规则
+	errors = append(errors, checks.Run(&checks.Context{
+		Sheets:           sheets,
+		RefSheets:        v.refSheets,
+		Config:           v.buildCfg,
+		PreviousManifest: v.manifest,
+	})...)
+
 	return errors
 }
 
-// ValidateRef 验证引用关系
+// ValidateRef 验证引用关系：引用值必须是被引用表中引用列的值（未指定列时为主键），配置了白名单的列的值必须在白名单中
+//
+// 数组列的每个元素分别检查
 func (v *DefaultValidator) ValidateRef(sheets []*model.DataSheet) []*model.ErrorInfo {
 	errors := make([]*model.ErrorInfo, 0)
 
-	// 构建引用索引
-	refIndex := make(map[string]map[interface{}]bool)
-	for _, sheet := range sheets {
-		refIndex[sheet.Name] = make(map[interface{}]bool)
-		for _, row := range sheet.Rows {
-			// 默认使用第一列作为主键
-			if len(sheet.Columns) > 0 {
-				primaryKey := sheet.Columns[0].Name
-				if val, exists := row[primaryKey]; exists && val != nil {
-					refIndex[sheet.Name][val] = true
-				}
+	byName := make(map[string]*model.DataSheet)
+	for _, sheet := range append(append([]*model.DataSheet{}, v.refSheets...), sheets...) {
+		byName[sheet.Name] = sheet
+	}
+
+	// 被引用的列按需建立索引，被引用表的值始终完整收集
+	refIndex := make(map[model.RefInfo]map[interface{}]bool)
+	index := func(target *model.DataSheet, column string) map[interface{}]bool {
+		key := model.RefInfo{Sheet: target.Name, Column: column}
+		if values, exists := refIndex[key]; exists {
+			return values
+		}
+		values := make(map[interface{}]bool)
+		for _, row := range target.Rows {
+			val, _ := model.RowValue(row, column)
+			for _, item := range refValues(val) {
+				values[item] = true
 			}
 		}
+		refIndex[key] = values
+		return values
 	}
 
 	// 验证每个表的引用关系
 	for _, sheet := range sheets {
 		for _, col := range sheet.Columns {
 			if col.Ref != nil {
-				// 检查引用的表是否存在
-				if _, exists := refIndex[col.Ref.Sheet]; !exists {
+				// 检查引用的表和列是否存在
+				target, exists := byName[col.Ref.Sheet]
+				if !exists {
 					errors = append(errors, &model.ErrorInfo{
 						Sheet:  sheet.Name,
 						Column: col.Name,
@@ -135,16 +219,51 @@ func (v *DefaultValidator) ValidateRef(sheets []*model.DataSheet) []*model.Error
 					})
 					continue
 				}
+				refColumn := col.Ref.Column
+				if refColumn == "" {
+					refColumn = target.PrimaryKey()
+				}
+				if !target.HasColumn(refColumn) {
+					errors = append(errors, &model.ErrorInfo{
+						Sheet:  sheet.Name,
+						Column: col.Name,
+						Msg:    fmt.Sprintf("引用的列 %s.%s 不存在", col.Ref.Sheet, refColumn),
+					})
+					continue
+				}
+				values := index(target, refColumn)
+				location := fmt.Sprintf("表 %s ", col.Ref.Sheet)
+				if refColumn != target.PrimaryKey() {
+					location = fmt.Sprintf("表 %s 的 %s 列", col.Ref.Sheet, refColumn)
+				}
 
 				// 验证每行数据的引用值
-				for rowIndex, row := range sheet.Rows {
-					if val, exists := row[col.Name]; exists && val != nil {
-						if !refIndex[col.Ref.Sheet][val] {
+				for _, rowIndex := range v.sampling.rowIndexes(len(sheet.Rows), v.rng) {
+					val, _ := model.RowValue(sheet.Rows[rowIndex], col.Name)
+					for _, item := range refValues(val) {
+						if !values[item] {
 							errors = append(errors, &model.ErrorInfo{
 								Sheet:  sheet.Name,
-								Row:    rowIndex + 4,
+								Row:    sheet.RowNumber(rowIndex),
 								Column: col.Name,
-								Msg:    fmt.Sprintf("引用值 %v 在表 %s 中不存在", val, col.Ref.Sheet),
+								Msg:    fmt.Sprintf("引用值 %v 在%s中不存在", item, location),
+							})
+						}
+					}
+				}
+			}
+
+			// 验证外部白名单
+			if whitelist := v.whitelists[sheet.Name][col.Name]; whitelist != nil {
+				for _, rowIndex := range v.sampling.rowIndexes(len(sheet.Rows), v.rng) {
+					val, _ := model.RowValue(sheet.Rows[rowIndex], col.Name)
+					for _, item := range refValues(val) {
+						if item != "" && !whitelist.Contains(item) {
+							errors = append(errors, &model.ErrorInfo{
+								Sheet:  sheet.Name,
+								Row:    sheet.RowNumber(rowIndex),
+								Column: col.Name,
+								Msg:    fmt.Sprintf("值 %v 不在白名单 %s 中", item, whitelist.Name),
 							})
 						}
 					}
@@ -156,6 +275,42 @@ func (v *DefaultValidator) ValidateRef(sheets []*model.DataSheet) []*model.Error
 	return errors
 }
 
+// refValues 引用列中需要检查的值，数组列的每个元素都是一个值，空值和无法比较的值被忽略
+func refValues(val interface{}) []interface{} {
+	switch v := val.(type) {
+	case nil, map[string]interface{}:
+		return nil
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			values = append(values, refValues(item)...)
+		}
+		return values
+	default:
+		return []interface{}{v}
+	}
+}
+
+// validateEnum 验证枚举值，返回错误消息
+func (v *DefaultValidator) validateEnum(value interface{}, enumName string) string {
+	enum, exists := v.enums[enumName]
+	if !exists {
+		return fmt.Sprintf("枚举 %s 未定义", enumName)
+	}
+
+	intVal, ok := value.(int)
+	if !ok {
+		return fmt.Sprintf("未知的枚举成员 %v，枚举: %s", value, enumName)
+	}
+
+	for _, memberVal := range enum.Members {
+		if memberVal == intVal {
+			return ""
+		}
+	}
+	return fmt.Sprintf("枚举值 %d 不是 %s 的成员", intVal, enumName)
+}
+
 // validateDataType 验证数据类型
 func (v *DefaultValidator) validateDataType(value interface{}, expectedType string) bool {
 	valType := reflect.TypeOf(value).String()
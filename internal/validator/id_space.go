@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+)
+
+// ValidateIDSpaces 验证 ID 空间：同一空间中所有表的主键必须全局唯一，配置了范围的表的主键必须在范围内
+//
+// refSheets 只参与唯一性检查，错误只报告在 sheets 中的表上；数字和文本形式相同的主键视为同一个 ID
+func ValidateIDSpaces(sheets, refSheets []*model.DataSheet, spaces map[string]config.IDSpaceConfig) []*model.ErrorInfo {
+	errors := make([]*model.ErrorInfo, 0)
+	if len(spaces) == 0 {
+		return errors
+	}
+
+	validated := make(map[*model.DataSheet]bool, len(sheets))
+	for _, sheet := range sheets {
+		validated[sheet] = true
+	}
+	all := append(append([]*model.DataSheet{}, sheets...), refSheets...)
+
+	names := make([]string, 0, len(spaces))
+	for name := range spaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type origin struct {
+		sheet string
+		row   int
+	}
+	for _, name := range names {
+		space := spaces[name]
+		seen := make(map[string]origin)
+		for _, sheet := range all {
+			idRange, member := idSpaceRange(sheet.Name, space)
+			keyColumn := sheet.PrimaryKey()
+			if !member || keyColumn == "" {
+				continue
+			}
+
+			for rowIndex, row := range sheet.Rows {
+				key, exists := model.RowValue(row, keyColumn)
+				if !exists || key == nil || key == "" {
+					continue
+				}
+				report := func(format string, args ...interface{}) {
+					if validated[sheet] {
+						errors = append(errors, &model.ErrorInfo{
+							Sheet:  sheet.Name,
+							Row:    sheet.RowNumber(rowIndex),
+							Column: keyColumn,
+							Msg:    fmt.Sprintf(format, args...),
+						})
+					}
+				}
+
+				if idRange.Limited() {
+					id, ok := integerID(key)
+					if !ok {
+						report("ID 空间 %s 中的主键 %v 不是整数", name, key)
+					} else if id < idRange.Min || id > idRange.Max {
+						report("主键 %v 超出 ID 空间 %s 中为该表分配的范围 %d-%d", key, name, idRange.Min, idRange.Max)
+					}
+				}
+
+				text := fmt.Sprint(key)
+				if first, duplicated := seen[text]; duplicated {
+					report("主键 %v 与表 %s 第 %d 行重复（ID 空间 %s）", key, first.sheet, first.row, name)
+					continue
+				}
+				seen[text] = origin{sheet.Name, sheet.RowNumber(rowIndex)}
+			}
+		}
+	}
+	return errors
+}
+
+// idSpaceRange 查找表在 ID 空间中的配置，与表名完全相同的配置优先，其次为按模式排序后第一个匹配的通配符
+func idSpaceRange(name string, space config.IDSpaceConfig) (config.IDRange, bool) {
+	if idRange, exists := space.Sheets[name]; exists {
+		return idRange, true
+	}
+
+	patterns := make([]string, 0, len(space.Sheets))
+	for pattern := range space.Sheets {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return space.Sheets[pattern], true
+		}
+	}
+	return config.IDRange{}, false
+}
+
+// integerID 将主键转换为整数，非整数的值返回 false
+func integerID(key interface{}) (int64, bool) {
+	switch v := key.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
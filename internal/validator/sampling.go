@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Sampling 大表抽样验证的参数：行数超过 Threshold 的表只验证前 Head 行和从其余行中随机抽取的 Random 行
+type Sampling struct {
+	Threshold int // 行数超过该值的表才抽样
+	Head      int // 始终验证的前若干行
+	Random    int // 从其余行中随机抽取的行数
+}
+
+// Applies 指定行数的表是否会被抽样
+func (s *Sampling) Applies(rowCount int) bool {
+	return s != nil && rowCount > s.Threshold && s.Head+s.Random < rowCount
+}
+
+// SampleSize 指定行数的表实际验证的行数
+func (s *Sampling) SampleSize(rowCount int) int {
+	if !s.Applies(rowCount) {
+		return rowCount
+	}
+	return s.Head + s.Random
+}
+
+// rowIndexes 需要验证的行下标（升序），不抽样时为全部行
+func (s *Sampling) rowIndexes(rowCount int, rng *rand.Rand) []int {
+	if !s.Applies(rowCount) {
+		indexes := make([]int, rowCount)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+
+	indexes := make([]int, 0, s.Head+s.Random)
+	for i := 0; i < s.Head; i++ {
+		indexes = append(indexes, i)
+	}
+	rest := rng.Perm(rowCount - s.Head)[:s.Random]
+	sort.Ints(rest)
+	for _, offset := range rest {
+		indexes = append(indexes, s.Head+offset)
+	}
+	return indexes
+}
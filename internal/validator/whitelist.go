@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Whitelist 外部白名单，如美术管线导出的有效资源路径列表
+type Whitelist struct {
+	Name   string          // 白名单名称，用于错误信息，通常为文件路径
+	Values map[string]bool // 有效的值
+}
+
+// Contains 检查值是否在白名单中，按文本形式比较
+func (w *Whitelist) Contains(val interface{}) bool {
+	return w.Values[fmt.Sprint(val)]
+}
+
+// LoadWhitelist 读取白名单文件：.json 文件为字符串数组，其他文件每行一个值，忽略空行和 # 开头的注释行
+func LoadWhitelist(path string) (*Whitelist, error) {
+	whitelist := &Whitelist{Name: path, Values: make(map[string]bool)}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var values []string
+		if err := json.Unmarshal(content, &values); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		for _, value := range values {
+			whitelist.Values[value] = true
+		}
+		return whitelist, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		whitelist.Values[line] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return whitelist, nil
+}
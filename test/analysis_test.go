@@ -0,0 +1,68 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/analysis"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestFindDeadColumns 测试死列检测
+func TestFindDeadColumns(t *testing.T) {
+	sheets := []*model.DataSheet{
+		{
+			Name:    "items",
+			Columns: []model.ColumnInfo{{Name: "id"}, {Name: "name"}, {Name: "description"}},
+		},
+		{
+			Name:    "weapons",
+			Columns: []model.ColumnInfo{{Name: "id"}},
+		},
+	}
+	manifests := []*analysis.UsageManifest{
+		{Consumer: "client", Sheets: map[string][]string{"items": {"id"}}},
+		{Consumer: "server", Sheets: map[string][]string{"items": {"name"}}},
+	}
+
+	deadColumns, unusedSheets := analysis.FindDeadColumns(sheets, manifests)
+
+	if len(deadColumns) != 1 || deadColumns[0].Column != "description" {
+		t.Errorf("Expected only items.description to be dead, got %v", deadColumns)
+	}
+
+	if len(unusedSheets) != 1 || unusedSheets[0] != "weapons" {
+		t.Errorf("Expected weapons to be unused, got %v", unusedSheets)
+	}
+}
+
+// TestEstimateBundleSizes 测试包体预估
+func TestEstimateBundleSizes(t *testing.T) {
+	sheets := []*model.DataSheet{
+		{Name: "items", Rows: []map[string]interface{}{{"id": 1}, {"id": 2}}},
+	}
+	content := []byte(strings.Repeat(`{"id": 1, "name": "sword"},`, 100))
+	results := []*model.ConvertResult{
+		{FileName: "items.json", Format: "json", Sheet: "items", Content: content},
+		{FileName: "index.json", Format: "json", Content: []byte("{}")},
+	}
+
+	estimates, err := analysis.EstimateBundleSizes(sheets, results)
+	if err != nil {
+		t.Fatalf("预估失败: %v", err)
+	}
+	if len(estimates) != 2 {
+		t.Fatalf("期望 2 条预估，实际为 %d", len(estimates))
+	}
+
+	items := estimates[0]
+	if items.Rows != 2 || items.RawSize != len(content) {
+		t.Errorf("预估结果错误: %+v", items)
+	}
+	if items.CompressedSize <= 0 || items.CompressedSize >= items.RawSize {
+		t.Errorf("期望压缩后更小，实际为 %d", items.CompressedSize)
+	}
+	if estimates[1].Rows != 0 || estimates[1].PerRow() != 0 {
+		t.Errorf("汇总文件不应计算行数: %+v", estimates[1])
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+	"github.com/game-data-builder/internal/validator"
+)
+
+// TestValidateAssets 测试资源检查：按类型的子目录和扩展名在多个资源目录中查找，缺失的文件按行报告
+func TestValidateAssets(t *testing.T) {
+	client, shared := t.TempDir(), t.TempDir()
+	for _, file := range []string{
+		filepath.Join(client, "Prefabs", "sword.prefab"),
+		filepath.Join(shared, "UI", "Icons", "common", "axe.png"),
+	} {
+		os.MkdirAll(filepath.Dir(file), 0755)
+		os.WriteFile(file, []byte("asset"), 0644)
+	}
+
+	path := filepath.Join(t.TempDir(), "items.csv")
+	os.WriteFile(path, []byte("id,prefab,icons\nint,string,list\n编号,预制体|资源:prefab,图标|选填|资源:icon\n1,sword,common/axe\n2,shield,\n"), 0644)
+	r, _ := reader.NewReaderFactory().CreateReader(path, nil)
+	sheets, err := r.ReadAll(path)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if sheets[0].Columns[1].Asset != "prefab" || sheets[0].Columns[2].Asset != "icon" {
+		t.Fatalf("Expected asset types from comments, got %+v", sheets[0].Columns)
+	}
+
+	// 数组列的每个元素分别检查
+	sheets[0].Rows[1]["icons"] = []interface{}{"common/axe.png", "bow"}
+
+	cfg := config.AssetConfig{
+		Roots: []string{client, shared},
+		Types: map[string]config.AssetType{
+			"prefab": {Dir: "Prefabs", Extensions: []string{".prefab"}},
+			"icon":   {Dir: "UI/Icons", Extensions: []string{".png", ".jpg"}},
+		},
+	}
+	messages := make([]string, 0)
+	for _, err := range validator.ValidateAssets(sheets, cfg) {
+		messages = append(messages, err.Column+": "+err.Msg)
+	}
+	expected := []string{"prefab: prefab 资源 shield 不存在", "icons: icon 资源 bow 不存在"}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Unexpected errors: %v", messages)
+	}
+
+	if errors := validator.ValidateAssets([]*model.DataSheet{sheets[0]}, config.AssetConfig{}); len(errors) != 0 {
+		t.Errorf("Expected no check without roots, got %d errors", len(errors))
+	}
+}
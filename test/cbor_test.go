@@ -0,0 +1,58 @@
+package test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestCBORConverterCanonical 测试确定性编码：最短整数和浮点数编码，映射键按编码后的字节序排列
+func TestCBORConverterCanonical(t *testing.T) {
+	sheet := &model.DataSheet{
+		Name:    "t",
+		Columns: []model.ColumnInfo{{Name: "b", Type: "float"}, {Name: "a", Type: "int"}},
+		Rows: []map[string]interface{}{
+			{"b": 1.5, "a": 1000},
+			{"b": 1.1, "a": -4},
+			{"b": 100000.0, "a": nil},
+			{"b": 5.960464477539063e-8},
+		},
+		Meta: map[string]interface{}{},
+	}
+
+	conv := converter.NewCBORConverter()
+	if err := conv.Init(map[string]interface{}{"stripMetadata": true}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"a2", "646e616d65", "6174", "64726f7773", "84",
+		"a2", "6161", "1903e8", "6162", "f93e00",
+		"a2", "6161", "23", "6162", "fb3ff199999999999a",
+		"a2", "6161", "f6", "6162", "fa47c35000",
+		"a1", "6162", "f90001",
+	}, "")
+	if got := hex.EncodeToString(result.Content); got != expected {
+		t.Errorf("Unexpected encoding:\n got %s\nwant %s", got, expected)
+	}
+	if result.FileName != "t.cbor" {
+		t.Errorf("Expected t.cbor, got %s", result.FileName)
+	}
+
+	// 保留列定义时输出 columns 和 meta
+	conv.Init(map[string]interface{}{"rowsAsMap": true})
+	withMeta, err := conv.Convert(newItemSheet())
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if !strings.Contains(string(withMeta.Content), "columns") || !strings.Contains(string(withMeta.Content), "meta") {
+		t.Errorf("Expected columns and meta in output")
+	}
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/game-data-builder/internal/diff"
+	"github.com/game-data-builder/internal/model"
+)
+
+// checklistSheets 生成检查清单用的新旧两版物品表
+func checklistSheets() (oldSheet, newSheet *model.DataSheet) {
+	columns := []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "name", Type: "string"}, {Name: "price", Type: "int"}, {Name: "owner", Type: "string"}}
+	oldSheet = &model.DataSheet{Name: "items", Columns: columns, Rows: []map[string]interface{}{
+		{"id": 1, "name": "sword", "price": 100, "owner": "alice"},
+		{"id": 2, "name": "shield", "price": 80},
+		{"id": 3, "name": "potion", "price": 20},
+	}}
+	newSheet = &model.DataSheet{Name: "items", Columns: columns, Meta: map[string]interface{}{"owner": "carol"}, Rows: []map[string]interface{}{
+		{"id": 1, "name": "sword", "price": 150, "owner": "alice"},
+		{"id": 2, "name": "shield", "price": 80},
+		{"id": 4, "name": "bow", "price": 60, "owner": "bob"},
+		{"id": 5, "name": "ring", "price": 30},
+	}}
+	return oldSheet, newSheet
+}
+
+// TestBuildChecklists 测试只列出新增和修改的行，并按负责人分组
+func TestBuildChecklists(t *testing.T) {
+	oldSheet, newSheet := checklistSheets()
+	report := diff.Compare([]*model.DataSheet{oldSheet}, []*model.DataSheet{newSheet})
+	report.Old, report.New = "v1", "工作区"
+
+	checklists := diff.BuildChecklists(report, []*model.DataSheet{newSheet}, "owner", []string{"name", "missing"})
+	if len(checklists) != 1 {
+		t.Fatalf("期望 1 份清单，实际为 %d", len(checklists))
+	}
+	checklist := checklists[0]
+	if checklist.Count() != 3 {
+		t.Errorf("期望 3 个检查项，实际为 %d", checklist.Count())
+	}
+	if len(checklist.Fields) != 1 || checklist.Fields[0] != "name" {
+		t.Errorf("不存在的关键字段应被忽略: %v", checklist.Fields)
+	}
+
+	owners := make([]string, 0)
+	for _, group := range checklist.Groups {
+		owners = append(owners, group.Owner)
+	}
+	if strings.Join(owners, ",") != "alice,bob,carol" {
+		t.Errorf("负责人分组不正确: %v", owners)
+	}
+	item := checklist.Groups[0].Items[0]
+	if item.Key != "1" || item.Status != diff.Modified || strings.Join(item.Changed, ",") != "price" {
+		t.Errorf("修改的行不正确: %+v", item)
+	}
+
+	var md bytes.Buffer
+	if err := checklist.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"v1 → 工作区，共 3 行需要测试", "## bob（1 行）", "- [ ] 修改 id=1，name=sword（修改: price）", "- [ ] 新增 id=5，name=ring"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown 清单缺少 %q:\n%s", want, md.String())
+		}
+	}
+
+	var xlsx bytes.Buffer
+	if err := checklist.WriteXLSX(&xlsx); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&xlsx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); strings.Join(sheets, ",") != "1 alice,2 bob,3 carol" {
+		t.Errorf("工作表不正确: %v", sheets)
+	}
+	rows, err := f.GetRows("3 carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || strings.Join(rows[0], ",") != "id,name,变化,修改的列,测试结果" || rows[1][0] != "5" {
+		t.Errorf("xlsx 内容不正确: %v", rows)
+	}
+}
+
+// TestBuildChecklistsNoOwner 测试没有负责人的行归入未指定分组，且排在最后
+func TestBuildChecklistsNoOwner(t *testing.T) {
+	oldSheet, newSheet := checklistSheets()
+	newSheet.Meta = nil
+	report := diff.Compare([]*model.DataSheet{oldSheet}, []*model.DataSheet{newSheet})
+
+	checklists := diff.BuildChecklists(report, []*model.DataSheet{newSheet}, "owner", nil)
+	groups := checklists[0].Groups
+	if len(groups) != 3 || groups[2].Owner != diff.NoOwner {
+		t.Fatalf("未指定负责人的分组应在最后: %+v", groups)
+	}
+
+	report = diff.Compare([]*model.DataSheet{newSheet}, []*model.DataSheet{newSheet})
+	if checklists := diff.BuildChecklists(report, []*model.DataSheet{newSheet}, "owner", nil); len(checklists) != 0 {
+		t.Errorf("没有变化时不应生成清单: %d", len(checklists))
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/checks"
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+	"github.com/game-data-builder/internal/validator"
+)
+
+// TestChecksRegisteredRules 测试注册的规则在 ValidateAll 中执行并能访问配置和上次的输出清单
+func TestChecksRegisteredRules(t *testing.T) {
+	items := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "price", Type: "int"}},
+		Rows:    []map[string]interface{}{{"id": 1, "price": 10}, {"id": 2, "price": -5}},
+	}
+
+	var seen *checks.Context
+	err := checks.RegisterFunc("test-price", func(ctx *checks.Context) []*checks.ErrorInfo {
+		seen = ctx
+		sheet := ctx.Sheet("items")
+		var errors []*checks.ErrorInfo
+		for i, row := range sheet.Rows {
+			if price, _ := row["price"].(int); price < 0 {
+				errors = append(errors, &checks.ErrorInfo{Sheet: sheet.Name, Row: sheet.RowNumber(i), Column: "price", Msg: "价格不能为负"})
+			}
+		}
+		return errors
+	})
+	if err != nil {
+		t.Fatalf("Failed to register rule: %v", err)
+	}
+	defer checks.Unregister("test-price")
+	if err := checks.RegisterFunc("test-price", func(*checks.Context) []*checks.ErrorInfo { return nil }); err == nil {
+		t.Errorf("Expected duplicate registration to fail")
+	}
+
+	if err := checks.RegisterFunc("test-panic", func(*checks.Context) []*checks.ErrorInfo { panic("boom") }); err != nil {
+		t.Fatalf("Failed to register rule: %v", err)
+	}
+	defer checks.Unregister("test-panic")
+
+	cfg := &config.Config{OutputDir: "out"}
+	manifest := &output.Manifest{Files: []string{"json/items.json"}}
+	v := validator.NewDefaultValidator()
+	v.SetCheckContext(cfg, manifest)
+	errors := v.ValidateAll([]*model.DataSheet{items})
+
+	if seen == nil || seen.Config != cfg || seen.PreviousManifest != manifest || len(seen.Sheets) != 1 {
+		t.Fatalf("Unexpected rule context %+v", seen)
+	}
+	if len(errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errors)
+	}
+	if !strings.HasPrefix(errors[0].Msg, "[test-panic] ") || !strings.Contains(errors[0].Msg, "boom") {
+		t.Errorf("Unexpected panic error %+v", errors[0])
+	}
+	if errors[1].Sheet != "items" || errors[1].Column != "price" || errors[1].Msg != "[test-price] 价格不能为负" {
+		t.Errorf("Unexpected rule error %+v", errors[1])
+	}
+}
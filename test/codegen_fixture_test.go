@@ -0,0 +1,74 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// codegenSheet 生成代码的转换器（C++、Erlang、Godot、Java、Rust）共用的测试表：
+// 列名包含各语言的保留字（default、end、type）和驼峰命名，数据包含需要转义的字符串、
+// 超出整数表示范围的浮点数、缺失值和嵌套列，行不按主键排序
+func codegenSheet(name string) *model.DataSheet {
+	return &model.DataSheet{
+		Name: name,
+		Columns: []model.ColumnInfo{
+			{Name: "id", Type: "int", Comment: "编号"},
+			{Name: "name", Type: "string"},
+			{Name: "type", Type: "string"},
+			{Name: "itemId", Type: "int"},
+			{Name: "price", Type: "float"},
+			{Name: "default", Type: "bool"},
+			{Name: "end", Type: "bool"},
+			{Name: "reward.count", Type: "int"},
+			{Name: "reward.ratio", Type: "float"},
+		},
+		Rows: []map[string]interface{}{
+			{"id": 2, "name": "shield \\ 'x'", "price": 1e21},
+			{"id": 1, "name": "\"剑\"\n", "type": "weapon", "itemId": 1001, "price": 10.5, "default": true, "end": false,
+				"reward": map[string]interface{}{"count": 2, "ratio": 0.5}},
+		},
+		Meta: map[string]interface{}{},
+	}
+}
+
+// lookTool 查找检查生成代码语法用的外部工具，未安装时跳过测试
+func lookTool(t *testing.T, name string) string {
+	t.Helper()
+	path, err := exec.LookPath(name)
+	if err != nil {
+		t.Skipf("未安装 %s，跳过语法检查", name)
+	}
+	return path
+}
+
+// writeResults 将转换结果按文件名写入目录，返回写入的文件路径
+func writeResults(t *testing.T, dir string, results ...*model.ConvertResult) []string {
+	t.Helper()
+	paths := make([]string, 0, len(results))
+	for _, result := range results {
+		path := filepath.Join(dir, filepath.FromSlash(result.FileName))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, result.Content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// runTool 在目录中执行外部工具，失败时输出工具的诊断信息
+func runTool(t *testing.T, dir string, env []string, name string, args ...string) {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("%s 检查失败: %v\n%s", filepath.Base(name), err, output)
+	}
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/validator"
+)
+
+// combinePart 以整张表作为合并的一部分
+func combinePart(sheet *model.DataSheet) *validator.CombinePart {
+	part := &validator.CombinePart{Sheet: sheet.Name, Columns: sheet.Columns, Rows: sheet.Rows}
+	for rowIndex := range sheet.Rows {
+		part.RowNumbers = append(part.RowNumbers, sheet.RowNumber(rowIndex))
+	}
+	return part
+}
+
+// TestValidateCombine 测试合并表的主键重复、主键为空和列类型不一致
+func TestValidateCombine(t *testing.T) {
+	weapons := newItemSheet()
+	weapons.Name = "weapons"
+	armors := &model.DataSheet{
+		Name: "armors",
+		Columns: []model.ColumnInfo{
+			{Name: "name", Type: "int"},
+			{Name: "id", Type: "int", IsKey: true},
+		},
+		Rows: []map[string]interface{}{
+			{"name": 3, "id": 2},
+			{"name": 4, "id": nil},
+		},
+	}
+
+	errors := validator.ValidateCombine("equipments", "id", []*validator.CombinePart{combinePart(weapons), combinePart(armors)})
+	if len(errors) != 3 {
+		t.Fatalf("Expected 3 errors, got %d: %+v", len(errors), errors)
+	}
+	for _, err := range errors {
+		if err.Sheet != "equipments" {
+			t.Errorf("Expected error on combined sheet, got %s", err.Sheet)
+		}
+	}
+	if !strings.Contains(errors[0].Msg, "armors") || errors[0].Column != "name" {
+		t.Errorf("Expected type mismatch from armors, got %+v", errors[0])
+	}
+	if errors[1].Row != 4 || !strings.Contains(errors[1].Msg, "weapons 第 5 行") {
+		t.Errorf("Expected duplicate key pointing at weapons row 5, got %+v", errors[1])
+	}
+	if errors[2].Row != 5 || !strings.Contains(errors[2].Msg, "为空") {
+		t.Errorf("Expected empty key error, got %+v", errors[2])
+	}
+
+	if errors := validator.ValidateCombine("equipments", "", []*validator.CombinePart{combinePart(newItemSheet())}); len(errors) != 0 {
+		t.Errorf("Expected no errors for a single valid source, got %+v", errors)
+	}
+}
+
+// TestCombineColumnsAndDuplicateKeys 测试严格列合并的校验和按策略去除重复主键
+func TestCombineColumnsAndDuplicateKeys(t *testing.T) {
+	base := &model.DataSheet{
+		Name:    "activity_base",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "name", Type: "string"}},
+		Rows:    []map[string]interface{}{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}},
+	}
+	override := &model.DataSheet{
+		Name:    "activity_override",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "reward", Type: "int"}},
+		Rows:    []map[string]interface{}{{"id": 2, "reward": 10}, {"id": 3, "reward": 20}},
+	}
+
+	errors := validator.ValidateCombineColumns("activity", []*validator.CombinePart{combinePart(base), combinePart(override)})
+	if len(errors) != 2 || !strings.Contains(errors[0].Msg, "缺少") || !strings.Contains(errors[1].Msg, "reward") {
+		t.Errorf("Expected missing and extra column errors, got %+v", errors)
+	}
+
+	parts := []*validator.CombinePart{combinePart(base), combinePart(override)}
+	dropped := validator.ResolveCombineKeys("id", parts, true)
+	if len(dropped) != 1 || len(parts[0].Rows) != 1 || parts[0].Rows[0]["id"] != 1 || len(parts[1].Rows) != 2 {
+		t.Errorf("Expected base row 2 to be replaced, got %v %+v", dropped, parts)
+	}
+	if errors := validator.ValidateCombine("activity", "id", parts); len(errors) != 0 {
+		t.Errorf("Expected no duplicate keys after resolving, got %+v", errors)
+	}
+
+	parts = []*validator.CombinePart{combinePart(base), combinePart(override)}
+	validator.ResolveCombineKeys("id", parts, false)
+	if len(parts[0].Rows) != 2 || len(parts[1].Rows) != 1 || parts[1].Rows[0]["id"] != 3 {
+		t.Errorf("Expected first occurrence to be kept, got %+v", parts)
+	}
+}
+
+// TestJoinCombine 测试横向连接：左连接保留所有左表行，内连接去掉未匹配的行，连接键重复和列名冲突报错
+func TestJoinCombine(t *testing.T) {
+	base := &model.DataSheet{
+		Name:    "ItemBase",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int", IsKey: true, Required: true}, {Name: "price", Type: "int"}},
+		Rows:    []map[string]interface{}{{"id": 1, "price": 10}, {"id": 2, "price": 20}},
+	}
+	locale := &model.DataSheet{
+		Name:    "ItemLocale",
+		Columns: []model.ColumnInfo{{Name: "itemId", Type: "int", IsKey: true}, {Name: "name", Type: "string", Required: true}},
+		Rows:    []map[string]interface{}{{"itemId": 1, "name": "sword"}},
+	}
+	on := map[string]string{"ItemLocale": "itemId"}
+
+	parts := []*validator.CombinePart{combinePart(base), combinePart(locale)}
+	columns, rows, errors := validator.JoinCombine("Item", "id", parts, on, false)
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %+v", errors)
+	}
+	if len(columns) != 3 || columns[2].Name != "name" || columns[2].Required || columns[2].IsKey {
+		t.Errorf("Unexpected joined columns: %+v", columns)
+	}
+	if len(rows) != 2 || rows[0]["name"] != "sword" || rows[1]["name"] != nil || rows[1]["price"] != 20 {
+		t.Errorf("Unexpected left join rows: %+v", rows)
+	}
+	if _, exists := base.Rows[0]["name"]; exists {
+		t.Error("Expected source rows to be unchanged")
+	}
+
+	_, rows, _ = validator.JoinCombine("Item", "id", parts, on, true)
+	if len(rows) != 1 || rows[0]["id"] != 1 {
+		t.Errorf("Unexpected inner join rows: %+v", rows)
+	}
+
+	locale.Rows = append(locale.Rows, map[string]interface{}{"itemId": 1, "name": "axe"})
+	locale.Columns = append(locale.Columns, model.ColumnInfo{Name: "price", Type: "int"})
+	_, _, errors = validator.JoinCombine("Item", "id", []*validator.CombinePart{combinePart(base), combinePart(locale)}, on, false)
+	if len(errors) != 2 || !strings.Contains(errors[0].Msg, "重复") || !strings.Contains(errors[1].Msg, "重名") {
+		t.Errorf("Expected duplicate join key and column conflict errors, got %+v", errors)
+	}
+}
@@ -0,0 +1,289 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+)
+
+// writeMainConfig 在临时目录写入主配置
+func writeMainConfig(t *testing.T, confDir, content string) {
+	if err := os.WriteFile(filepath.Join(confDir, "config.json"), []byte(content), 0644); err != nil {
+		t.Fatalf("写入配置失败: %v", err)
+	}
+}
+
+// TestConfigManagerReload 测试重新加载配置并通知订阅者
+func TestConfigManagerReload(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{"sourceDir": "./a", "outputDir": "./out"}`)
+
+	cm := config.NewConfigManager()
+	if err := cm.Load(confDir); err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	snapshot := cm.Snapshot()
+
+	notified := ""
+	cm.Subscribe(func(next *config.ConfigManager) {
+		notified = next.Config.SourceDir
+	})
+
+	writeMainConfig(t, confDir, `{"sourceDir": "./b", "outputDir": "./out"}`)
+	if err := cm.Reload(); err != nil {
+		t.Fatalf("重新加载失败: %v", err)
+	}
+	if notified != "./b" || cm.Config.SourceDir != "./b" {
+		t.Errorf("期望新配置生效，实际通知 %q，当前 %q", notified, cm.Config.SourceDir)
+	}
+	if snapshot.Config.SourceDir != "./a" {
+		t.Errorf("快照不应受重新加载影响，实际为 %q", snapshot.Config.SourceDir)
+	}
+}
+
+// TestConfigManagerReloadInvalid 测试校验失败时保留原配置
+func TestConfigManagerReloadInvalid(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{"sourceDir": "./a", "outputDir": "./out"}`)
+
+	cm := config.NewConfigManager()
+	if err := cm.Load(confDir); err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	writeMainConfig(t, confDir, `{"sourceDir": "./a", "outputDir": "./out", "formats": ["json"]}`)
+	if err := cm.Reload(); err == nil {
+		t.Fatalf("期望缺少转换器配置时校验失败")
+	}
+	if len(cm.Config.Formats) != 0 {
+		t.Errorf("期望保留原配置")
+	}
+}
+
+// TestConfigGameTrashDirRequiresMirror 测试回收目录只能在镜像模式下配置
+func TestConfigGameTrashDirRequiresMirror(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{"sourceDir": "./a", "outputDir": "./out", "syncToGame": true, "gameDir": "./game", "gameTrashDir": "./trash"}`)
+	if err := config.NewConfigManager().Load(confDir); err == nil {
+		t.Fatalf("期望未开启 gameMirror 时校验失败")
+	}
+
+	writeMainConfig(t, confDir, `{"sourceDir": "./a", "outputDir": "./out", "syncToGame": true, "gameDir": "./game", "gameMirror": true, "gameTrashDir": "./trash"}`)
+	if err := config.NewConfigManager().Load(confDir); err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+}
+
+// TestConfigStatePath 测试状态文件的位置
+func TestConfigStatePath(t *testing.T) {
+	cfg := &config.Config{}
+	if path := cfg.StatePath("conf", "build.lock"); path != filepath.Join("conf", "build.lock") {
+		t.Errorf("Expected state file in conf dir, got %s", path)
+	}
+	if dir := cfg.StateCacheDir("sources"); dir != "" {
+		t.Errorf("Expected no cache dir without stateDir, got %s", dir)
+	}
+
+	cfg.StateDir = "/tmp/state"
+	if path := cfg.StatePath("conf", "build.lock"); path != filepath.Join("/tmp/state", "build.lock") {
+		t.Errorf("Expected state file in state dir, got %s", path)
+	}
+	if dir := cfg.StateCacheDir("sources"); dir != filepath.Join("/tmp/state", "sources") {
+		t.Errorf("Expected cache dir in state dir, got %s", dir)
+	}
+}
+
+// TestConfigSourceFilters 测试源文件的 include/exclude 和表名过滤
+func TestConfigSourceFilters(t *testing.T) {
+	cfg := &config.Config{
+		Exclude:       []string{"~$*", "*_backup.xlsx", "drafts/*"},
+		ExcludeSheets: []string{"Draft*"},
+	}
+	cases := map[string]bool{
+		"items.xlsx":                true,
+		"~$items.xlsx":              false,
+		"shop/~$shop.xlsx":          false,
+		"items_backup.xlsx":         false,
+		"drafts/new.xlsx":           false,
+		"shop/drafts/new.xlsx":      true,
+		filepath.Join("a", "b.csv"): true,
+	}
+	for relPath, expected := range cases {
+		if got := cfg.MatchSourceFile(relPath); got != expected {
+			t.Errorf("MatchSourceFile(%s): expected %v, got %v", relPath, expected, got)
+		}
+	}
+
+	cfg.Include = []string{"*.csv"}
+	if cfg.MatchSourceFile("items.xlsx") || !cfg.MatchSourceFile("a/items.csv") {
+		t.Error("Expected include to limit source files")
+	}
+	if cfg.MatchSheet("DraftItems") || !cfg.MatchSheet("items") {
+		t.Error("Expected excludeSheets to skip draft sheets")
+	}
+
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{"sourceDir": "./examples", "outputDir": "./output", "exclude": ["[a-"]}`)
+	if err := config.NewConfigManager().Load(confDir); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+// TestConfigReaderOptions 测试读取选项按 default、扩展名和文件依次合并
+func TestConfigReaderOptions(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{
+		"sourceDir": "./examples",
+		"outputDir": "./output",
+		"readers": {
+			"default": {"type": "default", "enabled": true, "options": {"skipEmptyRows": true, "skipRows": 0}},
+			"xlsx": {"enabled": true, "options": {"skipRows": 1}},
+			"csv": {"enabled": false, "options": {"skipRows": 3}},
+			"legacy": {"enabled": true, "files": ["legacy/*.xlsx"], "options": {"skipRows": 2}}
+		}
+	}`)
+	cm := config.NewConfigManager()
+	if err := cm.Load(confDir); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]float64{
+		"items.xlsx":       1,
+		"ITEMS.XLSX":       1,
+		"legacy/old.xlsx":  2,
+		"items.csv":        0,
+		"legacy/items.csv": 0,
+	}
+	for relPath, expected := range cases {
+		options := cm.ReaderOptions(relPath)
+		if options["skipRows"] != expected || options["skipEmptyRows"] != true {
+			t.Errorf("%s: unexpected options %v", relPath, options)
+		}
+	}
+}
+
+// TestConverterSheetOverrides 测试按表覆盖转换器的输出路径和选项
+func TestConverterSheetOverrides(t *testing.T) {
+	cfg := &config.ConverterConfig{
+		OutputPath: "json",
+		Options:    map[string]interface{}{"indent": true, "rowsAsMap": true},
+		SheetOverrides: map[string]config.SheetOverride{
+			"drops": {OutputPath: "json/drops", Options: map[string]interface{}{"rowsAsMap": false}},
+		},
+	}
+	if cfg.ForSheet("items") != cfg {
+		t.Error("Expected sheets without override to use the converter config")
+	}
+
+	drops := cfg.ForSheet("drops")
+	if drops.OutputPath != "json/drops" || drops.Options["rowsAsMap"] != false || drops.Options["indent"] != true {
+		t.Errorf("Unexpected override: %+v", drops)
+	}
+	if cfg.Options["rowsAsMap"] != true {
+		t.Error("Expected converter options to be unchanged")
+	}
+}
+
+// TestConfigProfile 测试环境配置与主配置的深度合并
+func TestConfigProfile(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{
+		"sourceDir": "./examples",
+		"outputDir": "./output",
+		"formats": ["json", "php"],
+		"converters": {
+			"json": {"type": "json", "enabled": true, "outputPath": "json", "options": {"indent": true, "rowsAsMap": true}},
+			"php": {"type": "php", "enabled": true, "outputPath": "php"}
+		},
+		"devPush": {"addr": "127.0.0.1:9000"}
+	}`)
+	os.WriteFile(filepath.Join(confDir, "config.production.json"), []byte(`{
+		"outputDir": "/data/release",
+		"formats": ["json"],
+		"converters": {"json": {"options": {"indent": false}}},
+		"devPush": null
+	}`), 0644)
+
+	cm := config.NewConfigManager()
+	cm.SetProfile("production")
+	if err := cm.Load(confDir); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cfg := cm.Config
+	if cfg.OutputDir != "/data/release" || len(cfg.Formats) != 1 || cfg.DevPush.Addr != "" {
+		t.Errorf("Unexpected merged config: %+v", cfg)
+	}
+	json := cfg.Converters["json"]
+	if json.Options["indent"] != false || json.Options["rowsAsMap"] != true || json.OutputPath != "json" {
+		t.Errorf("Expected converter options to be deep merged, got %+v", json)
+	}
+
+	// 重新加载沿用选择的环境配置
+	if err := cm.Reload(); err != nil || cm.Config.OutputDir != "/data/release" {
+		t.Errorf("Expected reload to keep profile: %v", err)
+	}
+
+	cm.SetProfile("staging")
+	if err := cm.Load(confDir); err == nil {
+		t.Error("Expected error for missing profile")
+	}
+}
+
+// TestConfigFragments 测试 fragments 引入的配置片段与主配置合并，include/exclude 仍是源文件过滤
+func TestConfigFragments(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{
+		"fragments": ["converters/*.json", "ids/*.json"],
+		"include": ["*.csv"],
+		"exclude": ["~$*"],
+		"sourceDir": "./examples",
+		"outputDir": "./output",
+		"formats": ["json", "php"],
+		"converters": {"json": {"type": "json", "enabled": true, "outputPath": "json"}}
+	}`)
+	os.MkdirAll(filepath.Join(confDir, "converters"), 0755)
+	os.WriteFile(filepath.Join(confDir, "converters", "php.json"), []byte(`{
+		"converters": {"php": {"type": "php", "enabled": true, "outputPath": "php"}}
+	}`), 0644)
+	os.WriteFile(filepath.Join(confDir, "converters", "json.json"), []byte(`{
+		"converters": {"json": {"options": {"indent": true}}}
+	}`), 0644)
+	os.MkdirAll(filepath.Join(confDir, "ids"), 0755)
+	os.WriteFile(filepath.Join(confDir, "ids", "items.json"), []byte(`{
+		"idSpaces": {"global": {"sheets": {"items": {"min": 9007199254740993, "max": 9223372036854775807}}}}
+	}`), 0644)
+
+	cm := config.NewConfigManager()
+	if err := cm.Load(confDir); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cm.Config.Converters["php"].OutputPath != "php" || cm.Config.Converters["json"].Options["indent"] != true {
+		t.Errorf("Unexpected converters: %+v", cm.Config.Converters)
+	}
+	if cm.Config.MatchSourceFile("items.xlsx") || cm.Config.MatchSourceFile("~$items.csv") || !cm.Config.MatchSourceFile("items.csv") {
+		t.Error("Expected include/exclude to filter source files")
+	}
+	// 片段中超过 2^53 的整数不丢失精度
+	if r := cm.Config.IDSpaces["global"].Sheets["items"]; r.Min != 9007199254740993 || r.Max != 9223372036854775807 {
+		t.Errorf("Unexpected id range: %+v", r)
+	}
+
+	// 没有匹配任何文件的模式报错，避免片段中的配置静默缺失
+	os.RemoveAll(filepath.Join(confDir, "ids"))
+	if err := cm.Load(confDir); err == nil || !strings.Contains(err.Error(), "ids/*.json") {
+		t.Errorf("Expected error for unmatched fragments pattern, got %v", err)
+	}
+	os.MkdirAll(filepath.Join(confDir, "ids"), 0755)
+	os.WriteFile(filepath.Join(confDir, "ids", "items.json"), []byte(`{}`), 0644)
+
+	// 不同文件重复定义同一个值时报错
+	os.WriteFile(filepath.Join(confDir, "converters", "json.json"), []byte(`{
+		"converters": {"json": {"outputPath": "data"}}
+	}`), 0644)
+	if err := cm.Load(confDir); err == nil || !strings.Contains(err.Error(), "converters.json.outputPath") {
+		t.Errorf("Expected duplicate definition error, got %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// newItemSheet 创建测试用的物品表
+func newItemSheet() *model.DataSheet {
+	return &model.DataSheet{
+		Name: "items",
+		Columns: []model.ColumnInfo{
+			{Name: "name", Type: "string"},
+			{Name: "id", Type: "int", IsKey: true},
+		},
+		Rows: []map[string]interface{}{
+			{"name": "sword", "id": 1},
+			{"name": "shield", "id": 2},
+		},
+		Meta:      make(map[string]interface{}),
+		KeyColumn: "id",
+	}
+}
+
+// newSheets 将数据表组成列表
+func newSheets(sheets ...*model.DataSheet) []*model.DataSheet {
+	return sheets
+}
+
+// TestJSONConverterRowsAsMap 测试按主键输出JSON行数据
+func TestJSONConverterRowsAsMap(t *testing.T) {
+	conv := converter.NewJSONConverter()
+	if err := conv.Init(map[string]interface{}{"rowsAsMap": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := conv.Convert(newItemSheet())
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	var data struct {
+		Rows map[string]map[string]interface{} `json:"rows"`
+	}
+	if err := json.Unmarshal(result.Content, &data); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if data.Rows["2"]["name"] != "shield" {
+		t.Errorf("Expected row keyed by id 2 to be shield, got %v", data.Rows)
+	}
+}
+
+// TestRowsAsMapDuplicateKey 测试主键重复时报错
+func TestRowsAsMapDuplicateKey(t *testing.T) {
+	sheet := newItemSheet()
+	sheet.Rows[1]["id"] = 1
+
+	conv := converter.NewPHPConverter()
+	if err := conv.Init(map[string]interface{}{"rowsAsMap": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conv.Convert(sheet); err == nil {
+		t.Error("Expected error for duplicate primary key")
+	}
+}
+
+// TestBatchConvertCollectsErrors 测试批量转换时继续处理其余的表并汇总所有错误
+func TestBatchConvertCollectsErrors(t *testing.T) {
+	broken := func(name string) *model.DataSheet {
+		sheet := newItemSheet()
+		sheet.Name = name
+		sheet.Rows[1]["id"] = 1
+		return sheet
+	}
+
+	conv := converter.NewJSONConverter()
+	if err := conv.Init(map[string]interface{}{"rowsAsMap": true}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := conv.BatchConvert(newSheets(broken("weapons"), newItemSheet(), broken("armors")))
+	if len(results) != 1 || results[0].FileName != "items.json" {
+		t.Errorf("Expected the valid sheet to be converted, got %d results", len(results))
+	}
+
+	var convertErrs *model.ConvertErrors
+	if !errors.As(err, &convertErrs) || !errors.Is(err, model.ErrConvert) {
+		t.Fatalf("Expected ConvertErrors, got %v", err)
+	}
+	if len(convertErrs.Errors) != 2 || convertErrs.Errors[0].Sheet != "weapons" || convertErrs.Errors[1].Sheet != "armors" {
+		t.Errorf("Expected errors for weapons and armors, got %+v", convertErrs.Errors)
+	}
+	if convertErrs.Errors[0].Format != "json" {
+		t.Errorf("Expected json format, got %s", convertErrs.Errors[0].Format)
+	}
+}
+
+// TestPHPConverterNestedColumns 测试PHP输出嵌套列
+func TestPHPConverterNestedColumns(t *testing.T) {
+	sheet := &model.DataSheet{
+		Name: "quests",
+		Columns: []model.ColumnInfo{
+			{Name: "id", Type: "int"},
+			{Name: "reward.itemId", Type: "int"},
+		},
+		Rows: []map[string]interface{}{
+			{"id": 1, "reward": map[string]interface{}{"itemId": 1001}},
+		},
+		Meta: make(map[string]interface{}),
+	}
+
+	conv := converter.NewPHPConverter()
+	if err := conv.Init(nil); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	expected := "            'reward' => [\n                'itemId' => 1001,\n            ],\n"
+	if !strings.Contains(string(result.Content), expected) {
+		t.Errorf("Expected nested reward array, got:\n%s", result.Content)
+	}
+}
+
+// TestJSONConverterDeterministicOrder 测试JSON输出按列顺序和 sortRowsBy 排序
+func TestJSONConverterDeterministicOrder(t *testing.T) {
+	conv := converter.NewJSONConverter()
+	if err := conv.Init(map[string]interface{}{"sortRowsBy": "-id"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet := newItemSheet()
+	sheet.Meta["version"] = 2
+	sheet.Meta["author"] = "alice"
+
+	first, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	second, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if string(first.Content) != string(second.Content) {
+		t.Error("Expected identical output across runs")
+	}
+
+	content := string(first.Content)
+	if !strings.Contains(content, `"rows":[{"name":"shield","id":2},{"name":"sword","id":1}]`) {
+		t.Errorf("Expected rows in column order sorted by id desc, got %s", content)
+	}
+	if !strings.Contains(content, `"meta":{"author":"alice","version":2}`) {
+		t.Errorf("Expected sorted meta keys, got %s", content)
+	}
+}
+
+// TestFBSConverterCheckToolchain 测试只有开启 verify 时才依赖 flatc
+func TestFBSConverterCheckToolchain(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	conv := converter.NewFBSConverter()
+	conv.Init(map[string]interface{}{})
+	var checker converter.IToolchainConverter = conv
+	if err := checker.CheckToolchain(); err != nil {
+		t.Errorf("未开启 verify 时不应依赖 flatc: %v", err)
+	}
+
+	conv.Init(map[string]interface{}{"verify": true})
+	if err := checker.CheckToolchain(); err == nil {
+		t.Errorf("期望开启 verify 且 flatc 缺失时返回错误")
+	}
+}
+
+// TestFBSConverterEmitSchema 测试输出共用的 common.fbs 和引用它的表 schema
+func TestFBSConverterEmitSchema(t *testing.T) {
+	conv := converter.NewFBSConverter()
+	if err := conv.Init(map[string]interface{}{"namespace": "Game-Data"}); err == nil {
+		t.Errorf("期望非法的命名空间返回错误")
+	}
+
+	if err := conv.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := conv.ConvertIndex([]*model.DataSheet{newItemSheet()}); len(results) != 0 {
+		t.Errorf("未开启 emitSchema 时不应输出 schema")
+	}
+
+	shopItem := newItemSheet()
+	shopItem.Name = "shop.item"
+	conv.Init(map[string]interface{}{"emitSchema": true, "namespace": "Game.Data"})
+	results, err := conv.ConvertIndex([]*model.DataSheet{newItemSheet(), shopItem})
+	if err != nil {
+		t.Fatalf("生成 schema 失败: %v", err)
+	}
+	files := make(map[string]string)
+	for _, result := range results {
+		files[filepath.ToSlash(result.FileName)] = string(result.Content)
+	}
+	if len(files) != 3 {
+		t.Fatalf("unexpected files %v", files)
+	}
+
+	common := files["common.fbs"]
+	if !strings.Contains(common, "namespace Game.Data;") || !strings.Contains(common, "table ColumnInfo") {
+		t.Errorf("unexpected common schema:\n%s", common)
+	}
+	items := files["items.fbs"]
+	if !strings.Contains(items, `include "common.fbs";`) || strings.Contains(items, "table ColumnInfo") || !strings.Contains(items, "root_type Data_items;") {
+		t.Errorf("unexpected items schema:\n%s", items)
+	}
+	if nested := files["shop/item.fbs"]; !strings.Contains(nested, `include "../common.fbs";`) || !strings.Contains(nested, "table RowData_shop_item") {
+		t.Errorf("unexpected shop.item schema:\n%s", nested)
+	}
+}
+
+// TestFBSConverterEncode 测试进程内编码的二进制可按 schema 读取，且多次编码结果一致
+func TestFBSConverterEncode(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	sheet := newItemSheet()
+	sheet.Columns = append(sheet.Columns, model.ColumnInfo{Name: "price", Type: "float"}, model.ColumnInfo{Name: "rare", Type: "bool"})
+	sheet.Rows[0]["price"] = 12.5
+	sheet.Rows[1]["rare"] = true
+
+	conv := converter.NewFBSConverter()
+	conv.Init(map[string]interface{}{"sortRowsBy": "-id"})
+	result, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("转换失败: %v", err)
+	}
+	if result.FileName != "items.bin" {
+		t.Errorf("unexpected file name %s", result.FileName)
+	}
+	again, _ := conv.Convert(sheet)
+	if !bytes.Equal(result.Content, again.Content) {
+		t.Errorf("多次编码结果不一致")
+	}
+
+	// 按生成代码的方式读取：Data_items { name, columns, rows, meta }，RowData_items { name, id, price, rare }
+	buf := result.Content
+	root := &flatbuffers.Table{Bytes: buf, Pos: flatbuffers.GetUOffsetT(buf)}
+	if name := root.String(flatbuffers.UOffsetT(root.Offset(4)) + root.Pos); name != "items" {
+		t.Errorf("unexpected name %q", name)
+	}
+	rowsField := flatbuffers.UOffsetT(root.Offset(8))
+	if rowsField == 0 || root.VectorLen(rowsField) != 2 {
+		t.Fatalf("期望 2 行")
+	}
+	first := &flatbuffers.Table{Bytes: buf, Pos: root.Indirect(root.Vector(rowsField))}
+	if name := first.String(flatbuffers.UOffsetT(first.Offset(4)) + first.Pos); name != "shield" {
+		t.Errorf("期望按 id 降序输出，第一行为 %q", name)
+	}
+	if id := first.GetInt32Slot(6, 0); id != 2 {
+		t.Errorf("unexpected id %d", id)
+	}
+	if !first.GetBoolSlot(10, false) || first.GetFloat64Slot(8, -1) != -1 {
+		t.Errorf("期望第一行 rare 为 true 且没有写入 price")
+	}
+
+	sheet.Rows[0]["id"] = 1.5
+	if _, err := conv.Convert(sheet); err == nil || !strings.Contains(err.Error(), "int32") {
+		t.Errorf("期望 int 列中的小数转换失败: %v", err)
+	}
+}
+
+// TestConverterTextPolicy 测试 BOM、换行符和结尾换行选项
+func TestConverterTextPolicy(t *testing.T) {
+	conv := converter.NewPHPConverter()
+	if err := conv.Init(map[string]interface{}{"bom": true, "lineEnding": "crlf", "finalNewline": true}); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+
+	result, err := conv.Convert(newItemSheet())
+	if err != nil {
+		t.Fatalf("转换失败: %v", err)
+	}
+	content := string(result.Content)
+	if !strings.HasPrefix(content, "\xEF\xBB\xBF<?php\r\n") {
+		t.Errorf("期望以 BOM 和 CRLF 开头: %q", content[:12])
+	}
+	if strings.Contains(strings.ReplaceAll(content, "\r\n", ""), "\n") {
+		t.Errorf("期望所有换行均为 CRLF")
+	}
+	if !strings.HasSuffix(content, "];\r\n") || strings.HasSuffix(content, "\r\n\r\n") {
+		t.Errorf("期望以单个换行结尾: %q", content[len(content)-8:])
+	}
+
+	json := converter.NewJSONConverter()
+	json.Init(map[string]interface{}{"finalNewline": true})
+	result, _ = json.Convert(newItemSheet())
+	if !strings.HasSuffix(string(result.Content), "}\n") {
+		t.Errorf("期望 JSON 以换行结尾")
+	}
+
+	if err := conv.Init(map[string]interface{}{"lineEnding": "cr"}); err == nil {
+		t.Errorf("期望不支持的换行符返回错误")
+	}
+}
+
+// TestXMLConverter 测试XML输出的属性和子元素两种方式
+func TestXMLConverter(t *testing.T) {
+	sheet := newItemSheet()
+	sheet.Rows[0]["name"] = "sword & <shield>"
+
+	conv := converter.NewXMLConverter()
+	if err := conv.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?><Table name="items"><Row name="sword &amp; &lt;shield&gt;" id="1"/><Row name="shield" id="2"/></Table>`
+	if string(result.Content) != expected || result.FileName != "items.xml" {
+		t.Errorf("Unexpected output %s: %s", result.FileName, result.Content)
+	}
+
+	conv = converter.NewXMLConverter()
+	options := map[string]interface{}{"cellMode": "element", "rootElement": "Items", "rowElement": "Item", "declaration": false, "indent": true}
+	if err := conv.Init(options); err != nil {
+		t.Fatal(err)
+	}
+	result, err = conv.Convert(newItemSheet())
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if !strings.HasPrefix(string(result.Content), "<Items name=\"items\">\n  <Item>\n    <name>sword</name>\n    <id>1</id>\n  </Item>\n") {
+		t.Errorf("Unexpected element output: %s", result.Content)
+	}
+
+	if err := converter.NewXMLConverter().Init(map[string]interface{}{"encoding": "GBK"}); err == nil {
+		t.Error("Expected error for unsupported encoding")
+	}
+}
+
+// TestConvertSheet 测试对单张表并发转换多个格式
+func TestConvertSheet(t *testing.T) {
+	opts := converter.ConvertOptions{
+		Converters: map[string]map[string]interface{}{"json": {"rowsAsMap": true}},
+	}
+	results, err := converter.ConvertSheet(newItemSheet(), []string{"php", "json", "yaml", "csv"}, opts)
+
+	var convertErrs *model.ConvertErrors
+	if !errors.As(err, &convertErrs) || len(convertErrs.Errors) != 1 || convertErrs.Errors[0].Format != "yaml" {
+		t.Fatalf("Expected only the unknown format to fail, got %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for i, format := range []string{"php", "json", "csv"} {
+		if results[i].Format != format || results[i].Sheet != "items" {
+			t.Errorf("Result %d: expected %s of items, got %s of %s", i, format, results[i].Format, results[i].Sheet)
+		}
+	}
+	if !strings.Contains(string(results[1].Content), `"2"`) {
+		t.Errorf("Expected json options to be applied, got %s", results[1].Content)
+	}
+}
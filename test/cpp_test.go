@@ -0,0 +1,143 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestCppConverterConstexpr 测试小表生成 constexpr 数组和按主键查找的函数
+func TestCppConverterConstexpr(t *testing.T) {
+	conv := converter.NewCppConverter()
+	if err := conv.Init(map[string]interface{}{"namespace": "game::data"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.FileName != "shop/items.h" {
+		t.Errorf("unexpected file name: %s", result.FileName)
+	}
+
+	content := string(result.Content)
+	for _, want := range []string{
+		"namespace game::data {",
+		"struct ShopItems {\n    int64_t id; // 编号\n    std::string_view name;\n    std::string_view type;\n    int64_t itemId;\n" +
+			"    double price;\n    bool default_;\n    bool end;\n    int64_t reward_count;\n    double reward_ratio;\n};",
+		"inline constexpr std::array<ShopItems, 2> kShopItems{{",
+		`{2, "shield \\ 'x'", {}, 0, 1e+21, false, false, 0, 0.0},`,
+		`{1, "\"\345\211\221\"\n", "weapon", 1001, 10.5, true, false, 2, 0.5},`,
+		"constexpr const ShopItems* FindShopItems(int64_t key) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+
+	runtime, err := conv.ConvertIndex([]*model.DataSheet{codegenSheet("shop.items")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runtime) != 0 {
+		t.Errorf("constexpr sheets should not need the gdb runtime: %+v", runtime)
+	}
+}
+
+// TestCppConverterLoader 测试按表选择 loader 方式和按行数自动选择
+func TestCppConverterLoader(t *testing.T) {
+	conv := converter.NewCppConverter()
+	options := map[string]interface{}{
+		"constexprMaxRows": float64(1),
+		"sheetModes":       map[string]interface{}{"shop.*": "constexpr"},
+	}
+	if err := conv.Init(options); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if !strings.Contains(string(result.Content), "kShopItems") {
+		t.Errorf("sheetModes should select constexpr:\n%s", result.Content)
+	}
+
+	result, err = conv.Convert(codegenSheet("battle.skills"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	content := string(result.Content)
+	for _, want := range []string{
+		`#include "../gdb_table.h"`,
+		"    std::string name;\n",
+		"inline bool LoadBattleSkills(const uint8_t* data, size_t size, std::vector<BattleSkills>& rows) {",
+		`const int c7 = table.ColumnIndex("reward.count");`,
+		"row.name = std::string(table.GetString(r, c1));",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+
+	runtime, err := conv.ConvertIndex([]*model.DataSheet{codegenSheet("shop.items"), codegenSheet("battle.skills")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runtime) != 1 || runtime[0].FileName != "gdb_table.h" || !strings.Contains(string(runtime[0].Content), "class GdbTable {") {
+		t.Errorf("unexpected runtime files: %+v", runtime)
+	}
+}
+
+// TestCppConverterCompiles 使用本机的 C++ 编译器检查两种方式生成的头文件，
+// 并在编译期通过 constexpr 查找函数核对数据
+func TestCppConverterCompiles(t *testing.T) {
+	compiler := lookTool(t, "c++")
+
+	conv := converter.NewCppConverter()
+	if err := conv.Init(map[string]interface{}{"sheetModes": map[string]interface{}{"shop.*": "constexpr", "battle.*": "loader"}}); err != nil {
+		t.Fatal(err)
+	}
+	sheets := []*model.DataSheet{codegenSheet("shop.items"), codegenSheet("battle.skills")}
+	results := make([]*model.ConvertResult, 0)
+	for _, sheet := range sheets {
+		result, err := conv.Convert(sheet)
+		if err != nil {
+			t.Fatalf("Convert failed: %v", err)
+		}
+		results = append(results, result)
+	}
+	runtime, err := conv.ConvertIndex(sheets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = append(results, runtime...)
+	results = append(results, &model.ConvertResult{FileName: "main.cpp", Content: []byte(`#include "shop/items.h"
+#include "battle/skills.h"
+
+static_assert(gamedata::FindShopItems(1)->itemId == 1001);
+static_assert(gamedata::FindShopItems(1)->name == "\"\xe5\x89\x91\"\n");
+static_assert(gamedata::FindShopItems(2)->name == "shield \\ 'x'");
+static_assert(gamedata::FindShopItems(3) == nullptr);
+`)})
+
+	dir := t.TempDir()
+	writeResults(t, dir, results...)
+	runTool(t, dir, nil, compiler, "-std=c++17", "-fsyntax-only", "-Wall", "main.cpp")
+}
+
+// TestCppConverterErrors 测试不合法的选项
+func TestCppConverterErrors(t *testing.T) {
+	for _, options := range []map[string]interface{}{
+		{"namespace": "game.data"},
+		{"mode": "inline"},
+		{"constexprMaxRows": float64(-1)},
+		{"sheetModes": map[string]interface{}{"items": "binary"}},
+	} {
+		if err := converter.NewCppConverter().Init(options); err == nil {
+			t.Errorf("expected error for options %v", options)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// csvOutputSheet CSV 转换器测试用的表
+func csvOutputSheet() *model.DataSheet {
+	return &model.DataSheet{
+		Name: "items",
+		Columns: []model.ColumnInfo{
+			{Name: "id", Type: "int", Comment: "编号"},
+			{Name: "name", Type: "string", Comment: "名称"},
+			{Name: "reward.items", Type: "[]int", Comment: "奖励"},
+		},
+		Rows: []map[string]interface{}{
+			{"id": 1, "name": "sword, \"big\"", "reward": map[string]interface{}{"items": []interface{}{1, 2}}},
+			{"id": 2, "name": " shield"},
+		},
+	}
+}
+
+// TestCSVConverterDialect 测试 CSV 方言选项
+func TestCSVConverterDialect(t *testing.T) {
+	cases := []struct {
+		options  map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{},
+			"id,name,reward.items\n1,\"sword, \"\"big\"\"\",\"[1,2]\"\n2,\" shield\",\n",
+		},
+		{
+			map[string]interface{}{"delimiter": "tab", "headerRows": []interface{}{"type", "comment"}, "lineEnding": "crlf"},
+			"int\tstring\t[]int\r\n编号\t名称\t奖励\r\n1\t\"sword, \"\"big\"\"\"\t[1,2]\r\n2\t\" shield\"\t\r\n",
+		},
+		{
+			map[string]interface{}{"quoting": "all", "headerRows": []interface{}{}, "bom": true},
+			"\xEF\xBB\xBF\"1\",\"sword, \"\"big\"\"\",\"[1,2]\"\n\"2\",\" shield\",\"\"\n",
+		},
+	}
+
+	for _, tc := range cases {
+		conv := converter.NewCSVConverter()
+		if err := conv.Init(tc.options); err != nil {
+			t.Fatal(err)
+		}
+		result, err := conv.Convert(csvOutputSheet())
+		if err != nil {
+			t.Fatalf("Convert failed: %v", err)
+		}
+		if string(result.Content) != tc.expected {
+			t.Errorf("Options %v:\nexpected %q\ngot      %q", tc.options, tc.expected, result.Content)
+		}
+	}
+
+	// 不加引号时无法输出包含分隔符的字段
+	conv := converter.NewCSVConverter()
+	if err := conv.Init(map[string]interface{}{"quoting": "none"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conv.Convert(csvOutputSheet()); err == nil {
+		t.Error("Expected error for unquoted delimiter")
+	}
+}
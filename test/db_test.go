@@ -0,0 +1,240 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/game-data-builder/internal/reader"
+)
+
+// fakeColumn 测试数据库的列
+type fakeColumn struct {
+	name     string
+	dbType   string
+	nullable bool
+}
+
+// fakeResult 测试数据库中一条查询的结果
+type fakeResult struct {
+	columns []fakeColumn
+	rows    [][]driver.Value
+}
+
+// fakeDatabase 测试数据库：查询语句 -> 结果，nil 表示数据库不可用
+var fakeDatabase map[string]*fakeResult
+
+func init() {
+	sql.Register("fakedb", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("不支持预处理")
+}
+func (fakeConn) Close() error              { return nil }
+func (fakeConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("不支持事务") }
+
+func (fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if fakeDatabase == nil {
+		return nil, fmt.Errorf("连接被拒绝")
+	}
+	result, exists := fakeDatabase[query]
+	if !exists {
+		return nil, fmt.Errorf("未知查询: %s", query)
+	}
+	return &fakeRows{result: result}, nil
+}
+
+type fakeRows struct {
+	result *fakeResult
+	next   int
+}
+
+func (r *fakeRows) Columns() []string {
+	names := make([]string, len(r.result.columns))
+	for i, column := range r.result.columns {
+		names[i] = column.name
+	}
+	return names
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.result.columns[index].dbType
+}
+
+func (r *fakeRows) ColumnTypeNullable(index int) (bool, bool) {
+	return r.result.columns[index].nullable, true
+}
+
+// TestDBReader 测试从数据库读取表和查询结果，以及数据库不可用时使用缓存
+func TestDBReader(t *testing.T) {
+	fakeDatabase = map[string]*fakeResult{
+		"SELECT * FROM balance": {
+			columns: []fakeColumn{{"id", "BIGINT", false}, {"rate", "DECIMAL", false}, {"note", "VARCHAR", true}, {"enabled", "BOOLEAN", false}},
+			rows: [][]driver.Value{
+				{int64(1), []byte("0.25"), "boss", true},
+				{int64(2), []byte("1.5"), nil, false},
+			},
+		},
+		"SELECT id, price FROM shop WHERE price > 0": {
+			columns: []fakeColumn{{"id", "UNSIGNED INT", false}, {"price", "DOUBLE", false}},
+			rows:    [][]driver.Value{{int64(7), float64(9.5)}},
+		},
+	}
+	defer func() { fakeDatabase = nil }()
+
+	options := map[string]interface{}{
+		"driver": "fakedb",
+		"dsn":    "live-ops",
+		"tables": map[string]interface{}{
+			"balance": "balance",
+			"shop":    "SELECT id, price FROM shop WHERE price > 0",
+		},
+		"retryAttempts":   float64(1),
+		"fallbackToCache": true,
+		"cacheDir":        t.TempDir(),
+	}
+	r := reader.NewDBReader()
+	if err := r.Init(options); err != nil {
+		t.Fatal(err)
+	}
+	sheets, err := r.ReadAll("liveops")
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(sheets) != 2 || sheets[0].Name != "balance" || sheets[1].Name != "shop" {
+		t.Fatalf("Unexpected sheets: %+v", sheets)
+	}
+
+	balance := sheets[0]
+	types := make([]string, 0)
+	for _, column := range balance.Columns {
+		types = append(types, column.Type)
+	}
+	if !reflect.DeepEqual(types, []string{"int", "float", "string", "bool"}) {
+		t.Errorf("Unexpected column types: %v", types)
+	}
+	if balance.Columns[2].Required || !balance.Columns[0].Required {
+		t.Errorf("Expected only nullable columns to be optional: %+v", balance.Columns)
+	}
+	expected := []map[string]interface{}{
+		{"id": 1, "rate": 0.25, "note": "boss", "enabled": true},
+		{"id": 2, "rate": 1.5, "note": nil, "enabled": false},
+	}
+	for i, want := range expected {
+		for key, value := range want {
+			if got := balance.Rows[i][key]; !reflect.DeepEqual(got, value) {
+				t.Errorf("Row %d column %s: expected %v, got %v", i, key, value, got)
+			}
+		}
+	}
+	if got := sheets[1].Rows[0]["price"]; got != 9.5 {
+		t.Errorf("Expected query result price 9.5, got %v", got)
+	}
+
+	// 数据库不可用时使用上次成功读取的缓存
+	fakeDatabase = nil
+	cached := reader.NewDBReader()
+	if err := cached.Init(options); err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := cached.ReadSheet("liveops", "balance")
+	if err != nil {
+		t.Fatalf("Expected cached data, got %v", err)
+	}
+	if len(sheet.Rows) != 2 || !reflect.DeepEqual(cached.CachedSources(), []string{"liveops/balance"}) {
+		t.Errorf("Expected cached balance, got %d rows, cached %v", len(sheet.Rows), cached.CachedSources())
+	}
+
+	if err := reader.NewDBReader().Init(map[string]interface{}{"driver": "fakedb", "dsn": "x"}); err == nil {
+		t.Error("Expected error without tables")
+	}
+}
+
+// TestDBReaderSQLite 测试通过内置的 SQLite 驱动读取内存数据库，并按建表时声明的类型映射列类型
+func TestDBReaderSQLite(t *testing.T) {
+	// 共享缓存的内存数据库在至少一个连接打开期间保留，读取器打开的连接能看到同一份数据
+	dsn := "file:db_reader_test?mode=memory&cache=shared"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	statements := []string{
+		`CREATE TABLE balance (
+			id INTEGER NOT NULL,
+			level SMALLINT NOT NULL,
+			rate DECIMAL(10,2) NOT NULL,
+			weight REAL NOT NULL,
+			enabled BOOLEAN NOT NULL,
+			name VARCHAR(32) NOT NULL,
+			note TEXT
+		)`,
+		`INSERT INTO balance VALUES (1, 3, 0.25, 1.5, 1, 'boss', 'first')`,
+		`INSERT INTO balance VALUES (2, 5, 2, 0.75, 0, 'minion', NULL)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+	}
+
+	r := reader.NewDBReader()
+	err = r.Init(map[string]interface{}{
+		"driver":        "sqlite3",
+		"dsn":           dsn,
+		"tables":        map[string]interface{}{"balance": "balance"},
+		"retryAttempts": float64(1),
+		"cacheDir":      t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := r.ReadSheet("liveops", "balance")
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+
+	types := make([]string, 0)
+	for _, column := range sheet.Columns {
+		types = append(types, column.Type)
+	}
+	if !reflect.DeepEqual(types, []string{"int", "int", "float", "float", "bool", "string", "string"}) {
+		t.Errorf("Unexpected column types: %v", types)
+	}
+	expected := []map[string]interface{}{
+		{"id": 1, "level": 3, "rate": 0.25, "weight": 1.5, "enabled": true, "name": "boss", "note": "first"},
+		{"id": 2, "level": 5, "rate": 2.0, "weight": 0.75, "enabled": false, "name": "minion", "note": nil},
+	}
+	if len(sheet.Rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d", len(expected), len(sheet.Rows))
+	}
+	for i, want := range expected {
+		for key, value := range want {
+			if got := sheet.Rows[i][key]; !reflect.DeepEqual(got, value) {
+				t.Errorf("Row %d column %s: expected %v (%T), got %v (%T)", i, key, value, value, got, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/game-data-builder/internal/devpush"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestPusherPushesChangedResults 测试只推送内容变化的文件
+func TestPusherPushesChangedResults(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []devpush.Message, 4)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			messages := make([]devpush.Message, 0)
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				var message devpush.Message
+				json.Unmarshal(scanner.Bytes(), &message)
+				messages = append(messages, message)
+			}
+			conn.Close()
+			received <- messages
+		}
+	}()
+
+	pusher := devpush.NewPusher(listener.Addr().String(), 0)
+	results := []*model.ConvertResult{
+		{FileName: "items.json", Format: "json", Sheet: "items", Content: []byte(`{"a":1}`)},
+		{FileName: "weapons.json", Format: "json", Sheet: "weapons", Content: []byte(`{"b":1}`)},
+	}
+
+	// 首次推送所有文件
+	if count, err := pusher.Push(results); err != nil || count != 2 {
+		t.Fatalf("期望推送 2 个文件，实际为 %d: %v", count, err)
+	}
+	messages := <-received
+	if len(messages) != 3 || messages[2].Type != devpush.MessageCommit || messages[2].Count != 2 {
+		t.Fatalf("推送消息错误: %+v", messages)
+	}
+	if string(messages[0].Content) != `{"a":1}` || messages[0].Sheet != "items" {
+		t.Errorf("文件消息错误: %+v", messages[0])
+	}
+
+	// 内容未变化时不推送
+	if count, _ := pusher.Push(results); count != 0 {
+		t.Errorf("期望不推送，实际推送 %d 个文件", count)
+	}
+
+	// 只推送变化的文件
+	results[1].Content = []byte(`{"b":2}`)
+	if count, err := pusher.Push(results); err != nil || count != 1 {
+		t.Fatalf("期望推送 1 个文件，实际为 %d: %v", count, err)
+	}
+	messages = <-received
+	if len(messages) != 2 || messages[0].File != "weapons.json" {
+		t.Errorf("推送消息错误: %+v", messages)
+	}
+}
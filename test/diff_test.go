@@ -0,0 +1,207 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/diff"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+	"github.com/xuri/excelize/v2"
+)
+
+// diffSheet 创建用于比较的物品表
+func diffSheet(rows ...map[string]interface{}) *model.DataSheet {
+	return &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "name", Type: "string"}, {Name: "price", Type: "int"}},
+		Rows:    rows,
+	}
+}
+
+// TestDiffCompare 测试按主键匹配行并报告新增、删除和修改的单元格
+func TestDiffCompare(t *testing.T) {
+	oldSheet := diffSheet(
+		map[string]interface{}{"id": 1, "name": "sword", "price": 100},
+		map[string]interface{}{"id": 2, "name": "shield", "price": 80},
+	)
+	newSheet := diffSheet(
+		map[string]interface{}{"id": 1, "name": "sword", "price": 150},
+		map[string]interface{}{"id": 3, "name": "potion", "price": 20},
+	)
+	weapons := &model.DataSheet{Name: "weapons", Columns: []model.ColumnInfo{{Name: "id"}}}
+
+	report := diff.Compare([]*model.DataSheet{oldSheet}, []*model.DataSheet{newSheet, weapons})
+	if len(report.Sheets) != 2 {
+		t.Fatalf("期望 2 张表有变化，实际为 %d", len(report.Sheets))
+	}
+
+	items := report.Sheets[0]
+	added, removed, modified := items.Counts()
+	if items.Name != "items" || added != 1 || removed != 1 || modified != 1 {
+		t.Fatalf("items 的差异不正确: %+v", items)
+	}
+	cells := items.Rows[0].Cells
+	if items.Rows[0].Key != "1" || len(cells) != 1 || cells[0].Column != "price" || cells[0].Old != 100 || cells[0].New != 150 {
+		t.Errorf("修改的单元格不正确: %+v", cells)
+	}
+	if report.Sheets[1].Name != "weapons" || report.Sheets[1].Status != diff.Added {
+		t.Errorf("weapons 应为新增的表: %+v", report.Sheets[1])
+	}
+
+	// 源数据中的整数与输出文件中解析出的数字视为相同
+	same := diff.Compare(
+		[]*model.DataSheet{diffSheet(map[string]interface{}{"id": 1, "price": 100})},
+		[]*model.DataSheet{diffSheet(map[string]interface{}{"id": float64(1), "price": float64(100)})},
+	)
+	if !same.Empty() {
+		t.Errorf("相同的数据不应有差异: %+v", same.Sheets)
+	}
+}
+
+// TestDiffLoadOutputDir 测试从 JSON 输出目录读取表，包括按主键组织的行
+func TestDiffLoadOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "version.json"), []byte(`{"buildTime": "", "files": []}`), 0644)
+	os.WriteFile(filepath.Join(dir, "items.json"), []byte(`{
+  "name": "items",
+  "columns": [{"Name": "id", "Type": "int"}, {"Name": "name", "Type": "string", "IsKey": true}],
+  "rows": {"sword": {"id": 1, "name": "sword"}, "shield": {"id": 2, "name": "shield"}}
+}`), 0644)
+
+	sheets, err := diff.LoadOutputDir(dir)
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(sheets) != 1 || sheets[0].PrimaryKey() != "name" || len(sheets[0].Rows) != 2 || sheets[0].Rows[0]["name"] != "sword" {
+		t.Fatalf("读取结果不正确: %+v", sheets)
+	}
+}
+
+// TestDiffReportFormats 测试文本、HTML 和 JSON 报告
+func TestDiffReportFormats(t *testing.T) {
+	report := diff.Compare(
+		[]*model.DataSheet{diffSheet(map[string]interface{}{"id": 1, "name": "<sword>", "price": 100})},
+		[]*model.DataSheet{diffSheet(map[string]interface{}{"id": 1, "name": "<sword>", "price": 120})},
+	)
+
+	var text bytes.Buffer
+	report.Write(&text, "text")
+	if !strings.Contains(text.String(), "~ id=1") || !strings.Contains(text.String(), "price: 100 -> 120") {
+		t.Errorf("文本报告不正确:\n%s", text.String())
+	}
+
+	var html bytes.Buffer
+	report.Write(&html, "html")
+	if !strings.Contains(html.String(), `<span class="new">120</span>`) {
+		t.Errorf("HTML 报告不正确:\n%s", html.String())
+	}
+
+	var jsonReport bytes.Buffer
+	report.Write(&jsonReport, "json")
+	if !strings.Contains(jsonReport.String(), `"status": "modified"`) {
+		t.Errorf("JSON 报告不正确:\n%s", jsonReport.String())
+	}
+
+	if err := report.Write(&text, "xml"); err == nil {
+		t.Error("不支持的格式应该返回错误")
+	}
+}
+
+// writeAnnotatedItems 写入带有负数标红的条件格式和价格批注的物品表，数据中间有一个空行
+func writeAnnotatedItems(t *testing.T, path string, rows [][]interface{}, comments map[string]string) {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	grid := append([][]interface{}{
+		{"id", "name", "price"},
+		{"int", "string", "int"},
+		{"ID", "名称", "价格"},
+	}, rows...)
+	for i, values := range grid {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow("Sheet1", cell, &values); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	style, err := f.NewConditionalStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "9C0006"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFC7CE"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetConditionalFormat("Sheet1", "C4:C100", []excelize.ConditionalFormatOptions{
+		{Type: "cell", Criteria: "<", Value: "0", Format: &style},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for cell, text := range comments {
+		if err := f.AddComment("Sheet1", excelize.Comment{Cell: cell, Author: "策划", Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestDiffHTMLAnnotations 测试 HTML 报告保留源文件中的条件格式和单元格批注
+func TestDiffHTMLAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.xlsx")
+	newPath := filepath.Join(dir, "new.xlsx")
+	writeAnnotatedItems(t, oldPath, [][]interface{}{{1, "sword", 100}, {}, {2, "shield", -3}}, nil)
+	writeAnnotatedItems(t, newPath, [][]interface{}{{1, "sword", -5}, {}, {2, "shield", 80}}, map[string]string{
+		"C4": "促销价，活动结束后恢复",
+		"B6": "名称待定",
+	})
+
+	// 默认不读取批注和条件格式
+	excelReader := reader.NewExcelReader()
+	plain, err := excelReader.ReadAll(newPath)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(plain[0].Comments) != 0 || len(plain[0].ConditionalFormats) != 0 {
+		t.Errorf("未开启 readAnnotations 时不应读取批注和条件格式: %+v", plain[0])
+	}
+
+	if err := excelReader.Init(map[string]interface{}{"readAnnotations": true}); err != nil {
+		t.Fatal(err)
+	}
+	oldSheets, err := excelReader.ReadAll(oldPath)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	newSheets, err := excelReader.ReadAll(newPath)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	sheet := newSheets[0]
+	if len(sheet.ConditionalFormats) != 1 || sheet.ConditionalFormats[0].Column != "price" || sheet.ConditionalFormats[0].Color != "#9C0006" {
+		t.Errorf("条件格式不正确: %+v", sheet.ConditionalFormats)
+	}
+	// 空行之后的行号仍与源文件一致
+	if sheet.CellComment(0, "price") != "促销价，活动结束后恢复" || sheet.CellComment(1, "name") != "名称待定" {
+		t.Errorf("批注不正确: %+v", sheet.Comments)
+	}
+
+	var html bytes.Buffer
+	if err := diff.Compare(oldSheets, newSheets).WriteHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<div class="commented" title="促销价，活动结束后恢复">price: <span class="old">100</span> <span class="new" style="color:#9C0006;background-color:#FFC7CE">-5</span></div>`,
+		`<div>price: <span class="old" style="color:#9C0006;background-color:#FFC7CE">-3</span> <span class="new">80</span></div>`,
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("HTML 报告中缺少 %s:\n%s", want, html.String())
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+	"github.com/game-data-builder/internal/validator"
+)
+
+// TestEnumResolveAndValidate 测试枚举解析与验证
+func TestEnumResolveAndValidate(t *testing.T) {
+	enumSheet := &model.DataSheet{
+		Name: model.EnumSheetName,
+		Rows: []map[string]interface{}{
+			{"enum": "Quality", "name": "White", "value": 1},
+			{"enum": "Quality", "name": "Blue", "value": 2},
+		},
+	}
+	itemSheet := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "quality", Type: "enum:Quality"}},
+		Rows: []map[string]interface{}{
+			{"quality": "Blue"},
+			{"quality": "Purple"},
+		},
+	}
+
+	sheets, enums, err := reader.ExtractEnums([]*model.DataSheet{enumSheet, itemSheet})
+	if err != nil {
+		t.Fatalf("ExtractEnums failed: %v", err)
+	}
+	if len(sheets) != 1 {
+		t.Fatalf("Expected enum sheet to be removed, got %d sheets", len(sheets))
+	}
+
+	reader.ResolveEnums(sheets, enums)
+	if itemSheet.Rows[0]["quality"] != 2 {
+		t.Errorf("Expected Blue to resolve to 2, got %v", itemSheet.Rows[0]["quality"])
+	}
+
+	v := validator.NewDefaultValidator()
+	v.SetEnums(enums)
+	errors := v.Validate(itemSheet)
+	if len(errors) != 1 || errors[0].Row != 5 {
+		t.Errorf("Expected one error for unknown member on row 5, got %v", errors)
+	}
+}
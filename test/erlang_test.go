@@ -0,0 +1,107 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+)
+
+// TestErlangConverterTerms 测试项式文件输出
+func TestErlangConverterTerms(t *testing.T) {
+	conv := converter.NewErlangConverter()
+	if err := conv.Init(map[string]interface{}{"rowsAsMap": true, "termExtension": "config"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.FileName != "shop/items.config" {
+		t.Errorf("Unexpected file name %s", result.FileName)
+	}
+
+	content := string(result.Content)
+	expected := []string{
+		`{2, #{id => 2, name => <<"shield \\ 'x'"/utf8>>, type => undefined, itemId => undefined, price => 1.0e+21, ` +
+			`default => undefined, 'end' => undefined, reward => #{count => undefined, ratio => undefined}}}.`,
+		`{1, #{id => 1, name => <<"\"剑\"\n"/utf8>>, type => <<"weapon"/utf8>>, itemId => 1001, price => 10.5, ` +
+			`default => true, 'end' => false, reward => #{count => 2, ratio => 0.5}}}.`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(content, line+"\n") {
+			t.Errorf("Expected line %s in:\n%s", line, content)
+		}
+	}
+}
+
+// TestErlangConverterModule 测试模块源码输出
+func TestErlangConverterModule(t *testing.T) {
+	conv := converter.NewErlangConverter()
+	if err := conv.Init(map[string]interface{}{"mode": "module"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.FileName != "shop/cfg_shop_items.erl" {
+		t.Errorf("Unexpected file name %s", result.FileName)
+	}
+	if name := converter.ErlangFileName("shop.items", map[string]interface{}{"mode": "module"}); name != result.FileName {
+		t.Errorf("ErlangFileName = %s, expected %s", name, result.FileName)
+	}
+
+	content := string(result.Content)
+	for _, part := range []string{
+		"-module(cfg_shop_items).\n",
+		"get(1) -> #{id => 1,",
+		"get(_) -> undefined.\n",
+		"keys() -> [2, 1].\n",
+	} {
+		if !strings.Contains(content, part) {
+			t.Errorf("Expected %q in:\n%s", part, content)
+		}
+	}
+
+	// 模块方式要求主键唯一
+	sheet := codegenSheet("shop.items")
+	sheet.Rows[1]["id"] = 2
+	if _, err := conv.Convert(sheet); err == nil {
+		t.Error("Expected duplicate key error")
+	}
+}
+
+// TestErlangConverterCompiles 使用本机的 erlc 编译模块源码，并用 file:consult 读取项式文件
+func TestErlangConverterCompiles(t *testing.T) {
+	erlc := lookTool(t, "erlc")
+	erl := lookTool(t, "erl")
+
+	dir := t.TempDir()
+	for _, options := range []map[string]interface{}{{"mode": "module"}, {"rowsAsMap": true}} {
+		conv := converter.NewErlangConverter()
+		if err := conv.Init(options); err != nil {
+			t.Fatal(err)
+		}
+		result, err := conv.Convert(codegenSheet("shop.items"))
+		if err != nil {
+			t.Fatalf("Convert failed: %v", err)
+		}
+		writeResults(t, dir, result)
+	}
+
+	runTool(t, dir, nil, erlc, "-Werror", "-o", dir, filepath.Join("shop", "cfg_shop_items.erl"))
+	// 名称为 "剑" 加换行的 UTF-8 字节，避免 -eval 参数的编码影响比较
+	runTool(t, dir, nil, erl, "-noshell", "-pa", dir, "-eval", `
+		try
+			{ok, [{2, _}, {1, #{itemId := 1001}}]} = file:consult("shop/items.term"),
+			#{name := <<34, 229, 137, 145, 34, 10>>} = cfg_shop_items:get(1),
+			#{name := <<"shield \\ 'x'">>} = cfg_shop_items:get(2),
+			[2, 1] = cfg_shop_items:keys(),
+			halt(0)
+		catch Class:Reason ->
+			io:format("~p:~p~n", [Class, Reason]),
+			halt(1)
+		end.`)
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestTypedErrors 测试错误类别判断和原始错误的获取
+func TestTypedErrors(t *testing.T) {
+	readErr := fmt.Errorf("读取源文件失败: %w", &model.ReadError{File: "items.csv", Err: os.ErrNotExist})
+	if !errors.Is(readErr, model.ErrRead) || errors.Is(readErr, model.ErrConvert) {
+		t.Errorf("读取错误类别判断错误")
+	}
+	if !errors.Is(readErr, os.ErrNotExist) {
+		t.Errorf("期望可以获取原始错误")
+	}
+
+	var target *model.ReadError
+	if !errors.As(readErr, &target) || target.File != "items.csv" {
+		t.Errorf("期望获取读取错误的文件信息")
+	}
+
+	validationErr := error(&model.ValidationError{Errors: []*model.ErrorInfo{{Sheet: "items", Row: 4, Column: "price"}}})
+	var validation *model.ValidationError
+	if !errors.Is(validationErr, model.ErrValidation) || !errors.As(validationErr, &validation) {
+		t.Fatalf("验证错误类别判断错误")
+	}
+	if validation.Errors[0].Row != 4 {
+		t.Errorf("期望携带单元格信息")
+	}
+
+	convertErr := fmt.Errorf("转换数据失败: %w", &model.ConvertError{Sheet: "items", Format: "json", Err: errors.New("bad")})
+	if !errors.Is(convertErr, model.ErrConvert) {
+		t.Errorf("转换错误类别判断错误")
+	}
+	if !errors.Is(&model.OutputError{Path: "./output", Err: os.ErrPermission}, model.ErrOutput) {
+		t.Errorf("输出错误类别判断错误")
+	}
+}
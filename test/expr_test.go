@@ -0,0 +1,56 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/expr"
+)
+
+// TestExprEval 测试表达式求值
+func TestExprEval(t *testing.T) {
+	env := expr.Env{
+		"attack":      10,
+		"attackSpeed": 1.5,
+		"status":      "deprecated",
+		"reward":      map[string]interface{}{"count": 3},
+	}
+
+	cases := []struct {
+		source   string
+		expected interface{}
+	}{
+		{"attack * attackSpeed", 15.0},
+		{"attack + reward.count * 2", 16},
+		{"status == \"deprecated\"", true},
+		{"status != 'deprecated' or attack > 5", true},
+		{"not (attack >= 10)", false},
+		{"upper(status) + \"_\" + str(attack)", "DEPRECATED_10"},
+		{"max(1, attack, 3)", 10},
+		{"if(attack > 5, \"high\", \"low\")", "high"},
+	}
+
+	for _, c := range cases {
+		compiled, err := expr.Compile(c.source)
+		if err != nil {
+			t.Errorf("Compile %q failed: %v", c.source, err)
+			continue
+		}
+		val, err := compiled.Eval(env)
+		if err != nil {
+			t.Errorf("Eval %q failed: %v", c.source, err)
+			continue
+		}
+		if val != c.expected {
+			t.Errorf("Eval %q = %v (%T), expected %v (%T)", c.source, val, val, c.expected, c.expected)
+		}
+	}
+}
+
+// TestExprCompileError 测试无效表达式
+func TestExprCompileError(t *testing.T) {
+	for _, source := range []string{"attack *", "(attack", "unknown(1)", "'open"} {
+		if _, err := expr.Compile(source); err == nil {
+			t.Errorf("Expected compile error for %q", source)
+		}
+	}
+}
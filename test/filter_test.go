@@ -0,0 +1,52 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// TestFilterRows 测试按 filters.json 去除行：精确表名和通配符的条件都会应用，保留的行记录原来的行号
+func TestFilterRows(t *testing.T) {
+	items := &model.DataSheet{
+		Name:    "shop.items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "status", Type: "string"}, {Name: "region", Type: "string"}},
+		Rows: []map[string]interface{}{
+			{"id": 1, "status": "deprecated", "region": "CN"},
+			{"id": 2, "status": "", "region": "CN"},
+			{"id": 3, "status": "", "region": "US"},
+			{"id": 4, "status": "", "region": "CN"},
+		},
+	}
+	drops := &model.DataSheet{Name: "drops", Columns: []model.ColumnInfo{{Name: "id", Type: "int"}}, Rows: []map[string]interface{}{{"id": 1}}}
+
+	cfg := &config.FiltersConfig{Filters: map[string][]string{
+		"shop.items": {`status == "deprecated"`},
+		"shop.*":     {`region != "CN"`},
+	}}
+	filtered, err := reader.FilterRows([]*model.DataSheet{items, drops}, cfg)
+	if err != nil {
+		t.Fatalf("FilterRows failed: %v", err)
+	}
+	if !reflect.DeepEqual(filtered, map[string]int{"shop.items": 2}) {
+		t.Errorf("Unexpected filtered counts: %v", filtered)
+	}
+	if len(items.Rows) != 2 || items.Rows[0]["id"] != 2 || items.Rows[1]["id"] != 4 {
+		t.Errorf("Unexpected remaining rows: %v", items.Rows)
+	}
+	if items.RowNumber(0) != 5 || items.RowNumber(1) != 7 {
+		t.Errorf("Expected original row numbers, got %d and %d", items.RowNumber(0), items.RowNumber(1))
+	}
+	if len(drops.Rows) != 1 {
+		t.Error("Expected unmatched sheet to be unchanged")
+	}
+
+	cfg = &config.FiltersConfig{Filters: map[string][]string{"drops": {`missing == 1`}}}
+	if _, err := reader.FilterRows([]*model.DataSheet{drops}, cfg); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected error for unknown column, got %v", err)
+	}
+}
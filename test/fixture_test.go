@@ -0,0 +1,50 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/game-data-builder/internal/fixture"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestFixtureSelect 测试截取测试夹具：保留前几行，并补齐（包括间接的）引用
+func TestFixtureSelect(t *testing.T) {
+	items := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "drop", Type: "int", Ref: &model.RefInfo{Sheet: "drops", Column: "id"}}},
+		Rows: []map[string]interface{}{
+			{"id": 1, "drop": 30},
+			{"id": 2, "drop": nil},
+			{"id": 3, "drop": 10},
+		},
+	}
+	drops := &model.DataSheet{
+		Name:    "drops",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "items", Type: "[]int", Ref: &model.RefInfo{Sheet: "items", Column: "id"}}},
+		Rows: []map[string]interface{}{
+			{"id": 10, "items": []interface{}{1}},
+			{"id": 20, "items": []interface{}{2}},
+			{"id": 30, "items": []interface{}{3, 99}},
+		},
+	}
+
+	fixtures := fixture.Select([]*model.DataSheet{items, drops}, 1)
+	ids := func(sheet *model.DataSheet) []interface{} {
+		values := make([]interface{}, 0)
+		for _, row := range sheet.Rows {
+			values = append(values, row["id"])
+		}
+		return values
+	}
+	// items 1 -> drops 30 -> items 3 -> drops 10；不存在的 99 被忽略
+	if got := ids(fixtures[0]); !reflect.DeepEqual(got, []interface{}{1, 3}) {
+		t.Errorf("Unexpected items fixture: %v", got)
+	}
+	if got := ids(fixtures[1]); !reflect.DeepEqual(got, []interface{}{10, 30}) {
+		t.Errorf("Unexpected drops fixture: %v", got)
+	}
+	if len(items.Rows) != 3 || len(drops.Rows) != 3 {
+		t.Error("Expected source sheets to be unchanged")
+	}
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/freeze"
+)
+
+// TestFreezeAndApprove 测试冻结表的变更检测与批准
+func TestFreezeAndApprove(t *testing.T) {
+	sheet := newItemSheet()
+	frozen := &config.FrozenConfig{
+		Sheets:    make(map[string]config.FrozenSheet),
+		Approvers: map[string]string{"qa": freeze.TokenHash("T-1")},
+	}
+
+	if err := freeze.Freeze(newSheets(sheet), frozen, []string{"items"}, "cert"); err != nil {
+		t.Fatalf("Freeze failed: %v", err)
+	}
+	if violations, err := freeze.Check(newSheets(sheet), frozen); err != nil || len(violations) != 0 {
+		t.Fatalf("Expected no violations right after freezing, got %v", violations)
+	}
+
+	// 修改内容后应检测到变化
+	sheet.Rows[0]["name"] = "axe"
+	if violations, err := freeze.Check(newSheets(sheet), frozen); err != nil || len(violations) != 1 {
+		t.Fatalf("Expected one violation after edit, got %d", len(violations))
+	}
+
+	// 令牌与批准人不匹配时拒绝批准
+	if err := freeze.Approve(newSheets(sheet), frozen, "items", "T-2", "qa"); err == nil {
+		t.Error("Expected approval with wrong token to fail")
+	}
+	if err := freeze.Approve(newSheets(sheet), frozen, "items", "T-1", "mallory"); err == nil {
+		t.Error("Expected approval by unknown approver to fail")
+	}
+
+	// 批准后不再报告
+	if err := freeze.Approve(newSheets(sheet), frozen, "items", "T-1", "qa"); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	if violations, err := freeze.Check(newSheets(sheet), frozen); err != nil || len(violations) != 0 {
+		t.Errorf("Expected approved change to pass, got %v", violations)
+	}
+}
+
+// TestFreezeMissingSheet 测试冻结表从构建中移除时报告变化
+func TestFreezeMissingSheet(t *testing.T) {
+	sheet := newItemSheet()
+	frozen := &config.FrozenConfig{Sheets: make(map[string]config.FrozenSheet)}
+	if err := freeze.Freeze(newSheets(sheet), frozen, []string{"items"}, "cert"); err != nil {
+		t.Fatalf("Freeze failed: %v", err)
+	}
+
+	violations, err := freeze.Check(newSheets(), frozen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || !violations[0].Missing || violations[0].Sheet != "items" {
+		t.Errorf("Expected missing frozen sheet to be reported, got %v", violations)
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestGDBConverter 测试 gdb 文件头、字符串池和定长行记录
+func TestGDBConverter(t *testing.T) {
+	sheet := newItemSheet()
+	sheet.Columns = append(sheet.Columns, model.ColumnInfo{Name: "price", Type: "float"}, model.ColumnInfo{Name: "sold", Type: "bool"})
+	sheet.Rows[0]["price"] = 9.5
+	sheet.Rows[0]["sold"] = true
+	sheet.Rows[1]["name"] = "sword"
+
+	conv := converter.NewGDBConverter()
+	if err := conv.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	data := result.Content
+	if result.FileName != "items.gdb" || string(data[:4]) != converter.GDBMagic {
+		t.Fatalf("Unexpected file %s with magic %q", result.FileName, data[:4])
+	}
+
+	columnCount := int(binary.LittleEndian.Uint16(data[6:]))
+	rowCount := int(binary.LittleEndian.Uint32(data[8:]))
+	rowSize := int(binary.LittleEndian.Uint32(data[12:]))
+	stringCount := int(binary.LittleEndian.Uint32(data[20:]))
+	stringDataSize := int(binary.LittleEndian.Uint32(data[24:]))
+	// 空值位图 1 字节 + string 4 + int 8 + float 8 + bool 1
+	if columnCount != 4 || rowCount != 2 || rowSize != 22 {
+		t.Fatalf("Unexpected header: %d columns, %d rows, row size %d", columnCount, rowCount, rowSize)
+	}
+	// 表名、4 个列名和 sword，两行的 sword 只存一次
+	if stringCount != 6 {
+		t.Errorf("Expected 6 pooled strings, got %d", stringCount)
+	}
+
+	rowStart := converter.GDBHeaderSize + columnCount*5 + stringCount*8 + stringDataSize
+	first := data[rowStart : rowStart+rowSize]
+	second := data[rowStart+rowSize:]
+	if first[0] != 0 || second[0] != 0b1100 {
+		t.Errorf("Unexpected null bitmaps: %08b, %08b", first[0], second[0])
+	}
+	if id := binary.LittleEndian.Uint64(second[5:]); id != 2 {
+		t.Errorf("Expected id 2, got %d", id)
+	}
+	if binary.LittleEndian.Uint32(first[1:]) != binary.LittleEndian.Uint32(second[1:]) {
+		t.Errorf("Expected equal strings to share a pool entry")
+	}
+	if first[21] != 1 {
+		t.Errorf("Expected sold flag set")
+	}
+}
+
+// TestGDBStubs 测试按 stubs 选项生成 C# 和 Go 读取代码
+func TestGDBStubs(t *testing.T) {
+	conv := converter.NewGDBConverter()
+	if err := conv.Init(map[string]interface{}{"stubs": []interface{}{"csharp", "go"}, "stubPackage": "config"}); err != nil {
+		t.Fatal(err)
+	}
+	sheet := newItemSheet()
+	sheet.Name = "mall.items"
+	sheet.Columns[1].Comment = "编号|主键"
+	sheet.Columns = append(sheet.Columns,
+		model.ColumnInfo{Name: "rarity", Type: "string", Comment: "稀有度|选项:common,epic drop,普通,common", Options: []string{"common", "epic drop", "普通", "common"}},
+		model.ColumnInfo{Name: "slot", Type: "int", Options: []string{"1", "2", "x"}})
+	results, err := conv.ConvertIndex(newSheets(sheet))
+	if err != nil {
+		t.Fatalf("ConvertIndex failed: %v", err)
+	}
+	if len(results) != 2 || results[0].FileName != "GdbTables.cs" || results[1].FileName != "gdb_tables.go" {
+		t.Fatalf("Unexpected stub files: %+v", results)
+	}
+
+	csharp := string(results[0].Content)
+	if !strings.Contains(csharp, "public sealed class MallItems") || !strings.Contains(csharp, "row.Id = table.GetInt(r, c1)") {
+		t.Errorf("Unexpected C# stub:\n%s", csharp)
+	}
+	golang := string(results[1].Content)
+	if !strings.HasPrefix(golang, "// Code generated") || !strings.Contains(golang, "package config") || !strings.Contains(golang, "func LoadMallItems(data []byte) ([]*MallItems, error)") {
+		t.Errorf("Unexpected Go stub:\n%s", golang)
+	}
+
+	// 列注释去掉元数据后作为字段说明，可选值生成带说明的常量
+	for _, want := range []string{"/// <summary>编号</summary>", "/// <summary>稀有度: epic drop</summary>", `public const string RarityEpicDrop = "epic drop";`, "public const long Slot2 = 2;"} {
+		if !strings.Contains(csharp, want) {
+			t.Errorf("Expected C# stub to contain %q:\n%s", want, csharp)
+		}
+	}
+	for _, want := range []string{"\t// Id 编号\n", "// MallItemsRarity普通 稀有度: 普通", `MallItemsRarityOption4 string = "common"`, "MallItemsSlot1 int64 = 1"} {
+		if !strings.Contains(golang, want) {
+			t.Errorf("Expected Go stub to contain %q:\n%s", want, golang)
+		}
+	}
+	if strings.Contains(golang, "MallItemsSlotX") {
+		t.Errorf("Expected options not matching the column type to be skipped:\n%s", golang)
+	}
+
+	if err := conv.Init(map[string]interface{}{"stubs": []interface{}{"java"}}); err == nil {
+		t.Error("Expected error for unsupported stub language")
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestGodotScript 测试 gd 脚本的行数据、主键索引和字面量转义
+func TestGodotScript(t *testing.T) {
+	conv := converter.NewGodotConverter()
+	if err := conv.Init(map[string]interface{}{"classPrefix": "Cfg", "sortRowsBy": "id"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.FileName != "shop/items.gd" {
+		t.Errorf("unexpected file name: %s", result.FileName)
+	}
+
+	content := string(result.Content)
+	for _, want := range []string{
+		"class_name CfgShopItems\nextends RefCounted\n",
+		"const KEY := \"id\"\n",
+		"\t" + `{"id": 1, "name": "\"剑\"\n", "type": "weapon", "itemId": 1001, "price": 10.5, "default": true, "end": false, ` +
+			`"reward": {"count": 2, "ratio": 0.5}},` + "\n",
+		"\t" + `{"id": 2, "name": "shield \\ 'x'", "type": null, "itemId": null, "price": 1e+21, "default": null, "end": null, ` +
+			`"reward": {"count": null, "ratio": null}},` + "\n",
+		"const INDEX := {\n\t1: 0,\n\t2: 1,\n}\n",
+		"static func get_row(key) -> Dictionary:\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+
+	index, err := conv.ConvertIndex([]*model.DataSheet{codegenSheet("shop.items")})
+	if err != nil || len(index) != 0 {
+		t.Errorf("gd mode should not generate index files: %v %v", index, err)
+	}
+}
+
+// TestGodotResource 测试 tres 资源引用共享脚本
+func TestGodotResource(t *testing.T) {
+	conv := converter.NewGodotConverter()
+	if err := conv.Init(map[string]interface{}{"mode": "tres", "resPath": "res://gen/"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.FileName != "shop/items.tres" {
+		t.Errorf("unexpected file name: %s", result.FileName)
+	}
+
+	content := string(result.Content)
+	for _, want := range []string{
+		"[gd_resource type=\"Resource\" script_class=\"GameDataTable\" load_steps=2 format=3]\n",
+		"[ext_resource type=\"Script\" path=\"res://gen/game_data_table.gd\" id=\"1\"]\n",
+		"script = ExtResource(\"1\")\nsheet = \"shop.items\"\nkey = \"id\"\nrows = [{\"id\": 2, ",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+
+	index, err := conv.ConvertIndex([]*model.DataSheet{codegenSheet("shop.items")})
+	if err != nil || len(index) != 1 || index[0].FileName != "game_data_table.gd" {
+		t.Fatalf("unexpected index files: %v %v", index, err)
+	}
+	if !strings.Contains(string(index[0].Content), "class_name GameDataTable\nextends Resource\n") {
+		t.Errorf("unexpected table script:\n%s", index[0].Content)
+	}
+
+	if err := converter.NewGodotConverter().Init(map[string]interface{}{"resPath": "/data"}); err == nil {
+		t.Error("expected resPath without res:// to be rejected")
+	}
+}
+
+// TestGodotScriptParses 使用本机的 Godot 检查 gd 脚本和 tres 共享脚本的语法
+func TestGodotScriptParses(t *testing.T) {
+	godot := lookTool(t, "godot")
+
+	dir := t.TempDir()
+	writeResults(t, dir, &model.ConvertResult{FileName: "project.godot", Content: []byte("config_version=5\n")})
+	scripts := make([]string, 0)
+	for _, options := range []map[string]interface{}{{"classPrefix": "Cfg"}, {"mode": "tres"}} {
+		conv := converter.NewGodotConverter()
+		if err := conv.Init(options); err != nil {
+			t.Fatal(err)
+		}
+		result, err := conv.Convert(codegenSheet("shop.items"))
+		if err != nil {
+			t.Fatalf("Convert failed: %v", err)
+		}
+		index, err := conv.ConvertIndex([]*model.DataSheet{codegenSheet("shop.items")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range writeResults(t, dir, append(index, result)...) {
+			if filepath.Ext(path) == ".gd" {
+				scripts = append(scripts, path)
+			}
+		}
+	}
+
+	for _, script := range scripts {
+		runTool(t, dir, nil, godot, "--headless", "--path", dir, "--check-only", "--script", script)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/validator"
+)
+
+// TestValidateIDSpaces 测试 ID 空间：跨表重复的主键和超出分配范围的主键报错，被引用表只参与唯一性检查
+func TestValidateIDSpaces(t *testing.T) {
+	idSheet := func(name string, ids ...interface{}) *model.DataSheet {
+		sheet := &model.DataSheet{Name: name, Columns: []model.ColumnInfo{{Name: "id", Type: "int"}}}
+		for _, id := range ids {
+			sheet.Rows = append(sheet.Rows, map[string]interface{}{"id": id})
+		}
+		return sheet
+	}
+	weapons := idSheet("weapons", 1000, 1001, 2500)
+	armors := idSheet("armors", 2000, 2001)
+	shop := idSheet("shop.items", 1001, "x")
+	monsters := idSheet("monsters", 1000)
+
+	spaces := map[string]config.IDSpaceConfig{"items": {Sheets: map[string]config.IDRange{
+		"weapons": {Min: 1000, Max: 1999},
+		"armors":  {Min: 2000, Max: 2999},
+		"shop.*":  {},
+	}}}
+	errors := validator.ValidateIDSpaces([]*model.DataSheet{weapons, monsters}, []*model.DataSheet{armors, shop}, spaces)
+	if len(errors) != 1 || errors[0].Sheet != "weapons" || errors[0].Row != 6 || !strings.Contains(errors[0].Msg, "1000-1999") {
+		t.Fatalf("Expected out of range error on weapons only, got %+v", errors)
+	}
+
+	errors = validator.ValidateIDSpaces([]*model.DataSheet{weapons, armors, shop}, nil, spaces)
+	var duplicated *model.ErrorInfo
+	for _, err := range errors {
+		if strings.Contains(err.Msg, "重复") {
+			duplicated = err
+		}
+	}
+	if len(errors) != 2 || duplicated == nil || duplicated.Sheet != "shop.items" || !strings.Contains(duplicated.Msg, "weapons 第 5 行") {
+		t.Errorf("Expected a range error and a duplicate id, got %+v", errors)
+	}
+}
+
+// TestIDSpaceConfigValidation 测试 ID 空间的范围不能重叠
+func TestIDSpaceConfigValidation(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{"sourceDir": "./examples", "outputDir": "./output",
+		"idSpaces": {"items": {"sheets": {"weapons": {"min": 1000, "max": 1999}, "armors": {"min": 1500, "max": 2999}}}}}`)
+	cm := config.NewConfigManager()
+	if err := cm.Load(confDir); err == nil || !strings.Contains(err.Error(), "重叠") {
+		t.Errorf("Expected overlapping range error, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(confDir, "config.json"), []byte(`{"sourceDir": "./examples", "outputDir": "./output",
+		"idSpaces": {"items": {"sheets": {"weapons": {"min": 1000, "max": 1999}, "armors": {"min": 2000, "max": 2999}, "shop.*": {}}}}}`), 0644)
+	if err := cm.Load(confDir); err != nil {
+		t.Errorf("Load failed: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/game-data-builder/internal/output"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// writeSharedOutput 模拟另一个项目的构建输出，包含一张物品表和版本文件
+func writeSharedOutput(t *testing.T, dataVersion int) string {
+	root := t.TempDir()
+	content := []byte(`{
+  "name": "items",
+  "columns": [{"Name": "id", "Type": "int"}, {"Name": "name", "Type": "string"}],
+  "rows": [{"id": 1, "name": "sword"}, {"id": 2, "name": "shield"}],
+  "meta": {"dataVersion": 1}
+}`)
+	os.MkdirAll(filepath.Join(root, "json"), 0755)
+	os.WriteFile(filepath.Join(root, "json", "items.json"), content, 0644)
+
+	version := output.NewVersionFile(time.Now(), "")
+	version.Add("json/items.json", content)
+	version.Sheets = map[string]int{"items": dataVersion}
+	versionContent, _ := version.Marshal()
+	os.WriteFile(filepath.Join(root, output.VersionFileName), versionContent, 0644)
+	return root
+}
+
+// TestImportSourceDir 测试从输出目录导入共享表并还原整数类型
+func TestImportSourceDir(t *testing.T) {
+	source, err := reader.NewImportSource(writeSharedOutput(t, 3), nil)
+	if err != nil {
+		t.Fatalf("创建导入源失败: %v", err)
+	}
+
+	sheets, err := source.ReadSheets(map[string]int{"items": 3})
+	if err != nil {
+		t.Fatalf("导入失败: %v", err)
+	}
+	if len(sheets) != 1 || len(sheets[0].Rows) != 2 {
+		t.Fatalf("导入结果不正确: %+v", sheets)
+	}
+	if id, ok := sheets[0].Rows[0]["id"].(int); !ok || id != 1 {
+		t.Errorf("整数列应还原为 int，实际为 %T %v", sheets[0].Rows[0]["id"], sheets[0].Rows[0]["id"])
+	}
+
+	// 版本不一致或表不存在时失败
+	if _, err := source.ReadSheets(map[string]int{"items": 2}); err == nil || !strings.Contains(err.Error(), "固定的版本") {
+		t.Errorf("版本不一致应该失败: %v", err)
+	}
+	if _, err := source.ReadSheets(map[string]int{"weapons": 0}); err == nil {
+		t.Error("导入不存在的表应该失败")
+	}
+}
+
+// TestImportSourceHTTP 测试通过 HTTP 导入并校验内容哈希
+func TestImportSourceHTTP(t *testing.T) {
+	root := writeSharedOutput(t, 1)
+	server := httptest.NewServer(http.FileServer(http.Dir(root)))
+	defer server.Close()
+
+	source, _ := reader.NewImportSource(server.URL+"/", map[string]interface{}{"retryAttempts": float64(1), "cacheDir": t.TempDir()})
+	if sheets, err := source.ReadSheets(map[string]int{"items": 0}); err != nil || len(sheets) != 1 {
+		t.Fatalf("通过 HTTP 导入失败: %v", err)
+	}
+
+	// 文件被修改后哈希校验失败
+	os.WriteFile(filepath.Join(root, "json", "items.json"), []byte(`{"name": "items", "columns": [], "rows": []}`), 0644)
+	if _, err := source.ReadSheets(map[string]int{"items": 0}); err == nil || !strings.Contains(err.Error(), "哈希") {
+		t.Errorf("内容被修改时应该失败: %v", err)
+	}
+}
+
+// TestImportSourceCachedSources 测试远程数据源不可用时使用缓存并记录降级的地址
+func TestImportSourceCachedSources(t *testing.T) {
+	root := writeSharedOutput(t, 1)
+	server := httptest.NewServer(http.FileServer(http.Dir(root)))
+	options := map[string]interface{}{"retryAttempts": float64(1), "cacheDir": t.TempDir(), "fallbackToCache": true}
+
+	source, _ := reader.NewImportSource(server.URL+"/", options)
+	if _, err := source.ReadSheets(map[string]int{"items": 0}); err != nil {
+		t.Fatalf("通过 HTTP 导入失败: %v", err)
+	}
+	if cached := source.CachedSources(); len(cached) != 0 {
+		t.Errorf("数据源可用时不应使用缓存: %v", cached)
+	}
+
+	server.Close()
+	source, _ = reader.NewImportSource(server.URL+"/", options)
+	if sheets, err := source.ReadSheets(map[string]int{"items": 0}); err != nil || len(sheets) != 1 {
+		t.Fatalf("数据源不可用时应使用缓存: %v", err)
+	}
+	if cached := source.CachedSources(); len(cached) != 2 || !strings.HasSuffix(cached[0], "/version.json") {
+		t.Errorf("unexpected cached sources %v", cached)
+	}
+}
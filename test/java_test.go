@@ -0,0 +1,138 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestJavaConverterClass 测试生成不可变类和 JSON 加载方法
+func TestJavaConverterClass(t *testing.T) {
+	conv := converter.NewJavaConverter()
+	if err := conv.Init(map[string]interface{}{"package": "com.example.data"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.FileName != filepath.Join("com", "example", "data", "ShopItems.java") {
+		t.Errorf("unexpected file name: %s", result.FileName)
+	}
+
+	content := string(result.Content)
+	for _, want := range []string{
+		"package com.example.data;",
+		"public final class ShopItems {",
+		"    /** 编号 */\n    private final long id;",
+		"private final boolean default_;",
+		"private final long rewardCount;",
+		"public boolean isDefault() {",
+		"public static List<ShopItems> fromJson(String json) throws JSONException {",
+		`GameDataJson.getLong(row, "reward.count"),`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "fromGdb") {
+		t.Error("gdb loader should not be generated by default")
+	}
+
+	runtime, err := conv.ConvertIndex([]*model.DataSheet{codegenSheet("shop.items")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runtime) != 1 || runtime[0].FileName != filepath.Join("com", "example", "data", "GameDataJson.java") {
+		t.Errorf("unexpected runtime files: %+v", runtime)
+	}
+}
+
+// TestJavaConverterRecord 测试生成 record 和 gdb 加载方法
+func TestJavaConverterRecord(t *testing.T) {
+	conv := converter.NewJavaConverter()
+	if err := conv.Init(map[string]interface{}{"style": "record", "loaders": []interface{}{"gdb"}}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(codegenSheet("shop.items"))
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	content := string(result.Content)
+	for _, want := range []string{
+		"package gamedata;",
+		" * @param id 编号\n",
+		"public record ShopItems(long id, String name, String type, long itemId, double price, boolean default_, boolean end, " +
+			"long rewardCount, double rewardRatio) {",
+		`int c7 = table.columnIndex("reward.count");`,
+		"c5 >= 0 ? table.getBool(r, c5) : false",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "org.json") {
+		t.Error("json loader should not be generated")
+	}
+}
+
+// TestJavaConverterCompiles 使用本机的 javac 编译两种风格生成的类和 gdb 运行时，
+// JSON 加载方法依赖 org.json，不在检查范围内
+func TestJavaConverterCompiles(t *testing.T) {
+	javac := lookTool(t, "javac")
+
+	for _, style := range []string{"class", "record"} {
+		conv := converter.NewJavaConverter()
+		if err := conv.Init(map[string]interface{}{"package": "com.example.data", "style": style, "loaders": []interface{}{"gdb"}}); err != nil {
+			t.Fatal(err)
+		}
+		sheets := []*model.DataSheet{codegenSheet("shop.items"), codegenSheet("battle.skills")}
+		results := make([]*model.ConvertResult, 0)
+		for _, sheet := range sheets {
+			result, err := conv.Convert(sheet)
+			if err != nil {
+				t.Fatalf("Convert failed: %v", err)
+			}
+			results = append(results, result)
+		}
+		runtime, err := conv.ConvertIndex(sheets)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dir := t.TempDir()
+		args := append([]string{"-d", filepath.Join(dir, "classes")}, writeResults(t, dir, append(results, runtime...)...)...)
+		runTool(t, dir, nil, javac, args...)
+	}
+}
+
+// TestJavaConverterErrors 测试不合法的选项和字段名冲突
+func TestJavaConverterErrors(t *testing.T) {
+	for _, options := range []map[string]interface{}{
+		{"package": "com.example-data"},
+		{"package": "com.new"},
+		{"style": "interface"},
+		{"loaders": []interface{}{"xml"}},
+	} {
+		if err := converter.NewJavaConverter().Init(options); err == nil {
+			t.Errorf("expected error for options %v", options)
+		}
+	}
+
+	conv := converter.NewJavaConverter()
+	if err := conv.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	sheet := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "item_id", Type: "int"}, {Name: "itemId", Type: "int"}},
+		Meta:    map[string]interface{}{},
+	}
+	if _, err := conv.Convert(sheet); err == nil {
+		t.Error("expected error for duplicate field names")
+	}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/game-data-builder/internal/lock"
+)
+
+// TestLockFileDiff 测试锁文件保存、加载以及修改、新增和删除的差异
+func TestLockFileDiff(t *testing.T) {
+	locked := lock.NewLockFile()
+	locked.Sources["items.csv"] = "aaa"
+	locked.Sources["skills.csv"] = "bbb"
+	locked.Configs["config.json"] = "ccc"
+	locked.Tools["builder"] = "1.0.0"
+
+	path := filepath.Join(t.TempDir(), lock.FileName)
+	if err := locked.Save(path); err != nil {
+		t.Fatalf("保存锁文件失败: %v", err)
+	}
+	loaded, err := lock.Load(path)
+	if err != nil {
+		t.Fatalf("加载锁文件失败: %v", err)
+	}
+	if diffs := loaded.Diff(locked); len(diffs) != 0 {
+		t.Errorf("期望加载后没有差异，实际为 %v", diffs)
+	}
+
+	current := lock.NewLockFile()
+	current.Sources["items.csv"] = "aaa2"
+	current.Sources["monsters.csv"] = "ddd"
+	current.Configs["config.json"] = "ccc"
+	current.Tools["builder"] = "1.0.1"
+
+	expected := []string{
+		"源文件 items.csv 已变化: aaa -> aaa2",
+		"源文件 monsters.csv 为新增",
+		"源文件 skills.csv 已删除",
+		"工具 builder 已变化: 1.0.0 -> 1.0.1",
+	}
+	if diffs := loaded.Diff(current); !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("期望差异 %v，实际为 %v", expected, diffs)
+	}
+}
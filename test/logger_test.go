@@ -0,0 +1,96 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/logger"
+)
+
+// TestLoggerTextLevels 测试文本格式的级别前缀和级别过滤
+func TestLoggerTextLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(&buf)
+
+	log.WithFields(nil).Debugf("debug")
+	log.WithFields(nil).Infof("读取文件: %s", "items.csv")
+	log.WithFields(nil).Warnf("warn")
+	log.WithFields(nil).Errorf("error")
+	expected := "读取文件: items.csv\n[WARN] warn\n[ERROR] error\n"
+	if buf.String() != expected {
+		t.Errorf("期望:\n%s实际:\n%s", expected, buf.String())
+	}
+
+	// 安静模式只输出警告和错误
+	buf.Reset()
+	log.SetLevel(logger.LevelWarn)
+	log.WithFields(nil).Infof("info")
+	log.WithFields(nil).Warnf("warn")
+	if buf.String() != "[WARN] warn\n" {
+		t.Errorf("安静模式输出不正确: %s", buf.String())
+	}
+
+	buf.Reset()
+	log.SetLevel(logger.LevelDebug)
+	log.WithFields(nil).Debugf("debug")
+	if buf.String() != "[DEBUG] debug\n" {
+		t.Errorf("调试模式输出不正确: %s", buf.String())
+	}
+}
+
+// TestLoggerJSON 测试 JSON 格式包含级别、消息和结构化字段
+func TestLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(&buf)
+	if err := log.SetFormat(logger.FormatJSON); err != nil {
+		t.Fatalf("设置格式失败: %v", err)
+	}
+
+	log.WithFields(logger.Fields{"sheet": "items", "row": 5}).Errorf("价格不能为负数")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("期望 1 行日志，实际为 %d", len(lines))
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("日志不是合法的 JSON: %v", err)
+	}
+	if entry["level"] != "error" || entry["msg"] != "价格不能为负数" || entry["sheet"] != "items" || entry["row"] != float64(5) || entry["time"] == nil {
+		t.Errorf("日志内容不正确: %v", entry)
+	}
+
+	if err := log.SetFormat("xml"); err == nil {
+		t.Error("不支持的格式应该返回错误")
+	}
+	if level, err := logger.ParseLevel("WARN"); err != nil || level != logger.LevelWarn {
+		t.Errorf("解析级别失败: %v %v", level, err)
+	}
+}
+
+// TestLoggerSink 测试额外输出目标接收相同的日志，移除后不再接收
+func TestLoggerSink(t *testing.T) {
+	var out, first, second bytes.Buffer
+	log := logger.New(&out)
+	removeFirst := log.AddSink(&first)
+	removeSecond := log.AddSink(&second)
+
+	log.WithFields(nil).Warnf("价格为负数")
+	removeFirst()
+	log.WithFields(nil).Infof("构建完成")
+	removeSecond()
+	removeSecond()
+	log.WithFields(nil).Infof("已停止")
+
+	if out.String() != "[WARN] 价格为负数\n构建完成\n已停止\n" {
+		t.Errorf("输出不正确: %q", out.String())
+	}
+	if first.String() != "[WARN] 价格为负数\n" {
+		t.Errorf("第一个目标的输出不正确: %q", first.String())
+	}
+	if second.String() != "[WARN] 价格为负数\n构建完成\n" {
+		t.Errorf("第二个目标的输出不正确: %q", second.String())
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/reader"
+)
+
+// TestMarkdownReader 测试读取设计文档中带有元数据注释的表格
+func TestMarkdownReader(t *testing.T) {
+	content := "# 商店设计\n" +
+		"\n" +
+		"普通表格不会被读取：\n" +
+		"\n" +
+		"| 版本 | 说明 |\n" +
+		"|------|------|\n" +
+		"| 1.0  | 初版 |\n" +
+		"\n" +
+		"<!-- table: shop.limits\n" +
+		"types:    | int | string | bool |\n" +
+		"comments: | ID\\|主键 | 名称 | 选项:a,b |\n" +
+		"meta:     | tags:economy |\n" +
+		"-->\n" +
+		"| id | name | enabled |\n" +
+		"|---:|:-----|:-------:|\n" +
+		"| 1  | 每日 \\| 限购 | true |\n" +
+		"| 2  | 每周 |\n" +
+		"\n" +
+		"```markdown\n" +
+		"<!-- types: | int | -->\n" +
+		"```\n" +
+		"\n" +
+		"<!-- types: | int | float | -->\n" +
+		"| id | rate |\n" +
+		"| --- | --- |\n" +
+		"| 1 | 0.5 |\n"
+	filePath := filepath.Join(t.TempDir(), "shop_design.md")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := reader.NewReaderFactory().CreateReader(filePath, nil)
+	if err != nil || r == nil {
+		t.Fatalf("Expected markdown reader, got %v (%v)", r, err)
+	}
+	sheets, err := r.ReadAll(filePath)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(sheets) != 2 || sheets[0].Name != "shop.limits" || sheets[1].Name != "shop_design" {
+		t.Fatalf("Unexpected sheets: %+v", sheets)
+	}
+
+	limits := sheets[0]
+	if limits.KeyColumn != "id" || limits.Meta["tags"] != "economy" || len(limits.Columns[2].Options) != 2 {
+		t.Errorf("Unexpected header: key=%s meta=%v columns=%+v", limits.KeyColumn, limits.Meta, limits.Columns)
+	}
+	if len(limits.Rows) != 2 || limits.Rows[0]["name"] != "每日 | 限购" || limits.Rows[0]["enabled"] != true || limits.Rows[1]["name"] != "每周" {
+		t.Errorf("Unexpected rows: %+v", limits.Rows)
+	}
+	if limits.RowNumber(1) != 17 {
+		t.Errorf("Expected second row at line 17, got %d", limits.RowNumber(1))
+	}
+	if sheets[1].Rows[0]["rate"] != 0.5 {
+		t.Errorf("Unexpected rows: %+v", sheets[1].Rows)
+	}
+
+	// 转换错误指向文档中的行
+	bad := strings.Replace(content, "| 2  | 每周 |", "| x  | 每周 |", 1)
+	if err := os.WriteFile(filePath, []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadAll(filePath); err == nil || !strings.Contains(err.Error(), "row 17") {
+		t.Errorf("Expected error at row 17, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/game-data-builder/internal/metrics"
+	"github.com/game-data-builder/internal/report"
+)
+
+// TestRecorderTop 测试按类别累计耗时并按耗时排序
+func TestRecorderTop(t *testing.T) {
+	recorder := metrics.NewRecorder()
+	recorder.Add(metrics.KindSheet, "items", 3*time.Millisecond)
+	recorder.Add(metrics.KindSheet, "weapons", 2*time.Millisecond)
+	recorder.Add(metrics.KindSheet, "items", 2*time.Millisecond)
+	recorder.Add(metrics.KindSheet, "skills", time.Millisecond)
+
+	top := recorder.Top(metrics.KindSheet, 2)
+	if len(top) != 2 || top[0].Name != "items" || top[0].Duration != 5*time.Millisecond || top[1].Name != "weapons" {
+		t.Errorf("排序结果不正确: %+v", top)
+	}
+	if len(recorder.Top(metrics.KindFile, 0)) != 0 {
+		t.Error("没有记录的类别应返回空")
+	}
+}
+
+// TestRecorderStages 测试阶段按顺序记录，并写入构建报告
+func TestRecorderStages(t *testing.T) {
+	recorder := metrics.NewRecorder()
+	recorder.StartStage("读取")
+	recorder.StartStage("转换")
+	recorder.EndStage()
+	recorder.EndStage()
+
+	stages := recorder.Stages()
+	if len(stages) != 2 || stages[0].Name != "读取" || stages[1].Name != "转换" {
+		t.Fatalf("阶段记录不正确: %+v", stages)
+	}
+
+	recorder.Add(metrics.KindFile, "items.xlsx", time.Second)
+	rep := report.NewReport()
+	recorder.AddToReport(rep, 10)
+	if lines := rep.Section("读取耗时最长的文件").Lines; len(lines) != 1 || !strings.Contains(lines[0], "items.xlsx") {
+		t.Errorf("报告内容不正确: %v", lines)
+	}
+}
+
+// TestProgress 测试进度条输出，未指定输出目标时不输出
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+	progress := metrics.NewProgress(&buf, "读取文件", 2)
+	progress.Add(1)
+	progress.Add(1)
+	progress.Finish()
+	if !strings.Contains(buf.String(), "读取文件 [") || !strings.HasSuffix(buf.String(), "2/2\n") {
+		t.Errorf("进度条输出不正确: %q", buf.String())
+	}
+
+	silent := metrics.NewProgress(nil, "转换", 1)
+	silent.Add(1)
+	silent.Finish()
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// TestApplyNamespace 测试命名空间前缀及同一来源内引用的解析
+func TestApplyNamespace(t *testing.T) {
+	items := newItemSheet()
+	shop := &model.DataSheet{
+		Name: "shop",
+		Columns: []model.ColumnInfo{
+			{Name: "itemId", Type: "int", Ref: &model.RefInfo{Sheet: "items", Column: "id"}},
+			{Name: "currency", Type: "int", Ref: &model.RefInfo{Sheet: "currency", Column: "id"}},
+		},
+	}
+
+	model.ApplyNamespace(newSheets(items, shop), "mall")
+
+	if items.Name != "mall.items" || shop.Name != "mall.shop" {
+		t.Fatalf("Expected prefixed names, got %s, %s", items.Name, shop.Name)
+	}
+	if ref := shop.Columns[0].Ref; ref.Sheet != "mall.items" || ref.Column != "id" {
+		t.Errorf("Expected local ref resolved to mall.items.id, got %+v", ref)
+	}
+	if ref := shop.Columns[1].Ref; ref.Sheet != "currency" {
+		t.Errorf("Expected ref outside the namespace unchanged, got %+v", ref)
+	}
+
+	if namespace, local := model.SplitName("mall.items"); namespace != "mall" || local != "items" {
+		t.Errorf("Expected mall/items, got %s/%s", namespace, local)
+	}
+	if model.ValidNamespace("1mall") || model.ValidNamespace("a.b") || !model.ValidNamespace("mall_2") {
+		t.Errorf("Unexpected namespace validation result")
+	}
+}
+
+// TestNamespaceOutputPath 测试带命名空间的表输出到命名空间子目录
+func TestNamespaceOutputPath(t *testing.T) {
+	sheet := newItemSheet()
+	sheet.Name = "mall.items"
+
+	conv := converter.NewJSONConverter()
+	if err := conv.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if expected := filepath.Join("mall", "items.json"); result.FileName != expected {
+		t.Errorf("Expected %s, got %s", expected, result.FileName)
+	}
+}
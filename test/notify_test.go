@@ -0,0 +1,71 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/notify"
+)
+
+// TestNotifySend 测试按格式和发送时机发送构建通知
+func TestNotifySend(t *testing.T) {
+	received := make(map[string]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(content, &body)
+		received[r.URL.Path] = body
+	}))
+	defer server.Close()
+
+	webhooks := []config.WebhookConfig{
+		{URL: server.URL + "/json"},
+		{URL: server.URL + "/dingtalk", Format: "dingtalk"},
+		{URL: server.URL + "/failure", Format: "slack", When: notify.WhenFailure},
+	}
+
+	summary := notify.NewSummary(nil, time.Second, []string{"json/items.json"})
+	if errs := notify.Send(webhooks, summary); len(errs) != 0 {
+		t.Fatalf("发送失败: %v", errs)
+	}
+	if received["/json"]["success"] != true {
+		t.Errorf("JSON 通知内容错误: %v", received["/json"])
+	}
+	text := received["/dingtalk"]["text"].(map[string]interface{})["content"].(string)
+	if !strings.Contains(text, "json/items.json") {
+		t.Errorf("钉钉通知内容错误: %s", text)
+	}
+	if _, sent := received["/failure"]; sent {
+		t.Errorf("构建成功时不应发送 failure 通知")
+	}
+
+	// 降级构建在摘要和文本消息中列出不可用的功能
+	degraded := notify.NewSummary(nil, time.Second, nil)
+	degraded.Degraded = []string{"[上传] oss://game-configs/: HTTP 503"}
+	notify.Send(webhooks, degraded)
+	if items, _ := received["/json"]["degraded"].([]interface{}); len(items) != 1 {
+		t.Errorf("JSON 通知缺少降级项: %v", received["/json"])
+	}
+	text = received["/dingtalk"]["text"].(map[string]interface{})["content"].(string)
+	if !strings.Contains(text, "\n降级: [上传] oss://game-configs/: HTTP 503") {
+		t.Errorf("钉钉通知缺少降级项: %s", text)
+	}
+
+	// 构建失败时携带验证错误
+	err := &model.ValidationError{Errors: []*model.ErrorInfo{{Sheet: "items", Row: 4, Column: "price", Msg: "不能为空"}}}
+	notify.Send(webhooks, notify.NewSummary(err, time.Second, nil))
+	slack := received["/failure"]["text"].(string)
+	if !strings.Contains(slack, "items:price[4]: 不能为空") {
+		t.Errorf("Slack 通知内容错误: %s", slack)
+	}
+	if errs := notify.Send([]config.WebhookConfig{{URL: server.URL, Format: "wechat"}}, summary); len(errs) != 1 {
+		t.Errorf("期望不支持的格式返回错误")
+	}
+}
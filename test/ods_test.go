@@ -0,0 +1,114 @@
+package test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/game-data-builder/internal/reader"
+)
+
+// odsContent 测试用 content.xml：包含重复单元格、段落、空格、批注和 LibreOffice 在末尾写入的大量重复空行
+const odsContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+    xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+    xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" office:version="1.3">
+  <office:body>
+    <office:spreadsheet>
+      <table:table table:name="items">
+        <table:table-row>
+          <table:table-cell office:value-type="string"><text:p>id</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>name</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>price</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>enabled</text:p></table:table-cell>
+          <table:table-cell table:number-columns-repeated="16380"/>
+        </table:table-row>
+        <table:table-row>
+          <table:table-cell office:value-type="string"><text:p>int</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>string</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>float</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>bool</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row>
+          <table:table-cell office:value-type="string"><text:p>编号|主键</text:p></table:table-cell>
+          <table:table-cell table:number-columns-repeated="2" office:value-type="string"><text:p>说明</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row>
+          <table:table-cell office:value-type="float" office:value="1"><text:p>1.00</text:p></table:table-cell>
+          <table:table-cell office:value-type="string">
+            <office:annotation><text:p>批注</text:p></office:annotation>
+            <text:p>long<text:s text:c="2"/>sword</text:p>
+            <text:p>第二行</text:p>
+          </table:table-cell>
+          <table:table-cell office:value-type="percentage" office:value="0.25"><text:p>25%</text:p></table:table-cell>
+          <table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row table:number-rows-repeated="2"><table:table-cell table:number-columns-repeated="4"/></table:table-row>
+        <table:table-row>
+          <table:table-cell office:value-type="float" office:value="2"><text:p>2</text:p></table:table-cell>
+          <table:table-cell table:number-columns-repeated="2"/>
+          <table:table-cell office:value-type="boolean" office:boolean-value="false"><text:p>FALSE</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row table:number-rows-repeated="1048570"><table:table-cell table:number-columns-repeated="16384"/></table:table-row>
+      </table:table>
+      <table:table table:name="_notes">
+        <table:table-row><table:table-cell office:value-type="string"><text:p>notes</text:p></table:table-cell></table:table-row>
+      </table:table>
+    </office:spreadsheet>
+  </office:body>
+</office:document-content>
+`
+
+// TestODSReader 测试读取 OpenDocument 电子表格
+func TestODSReader(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "items.ods")
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	for name, content := range map[string]string{"mimetype": "application/vnd.oasis.opendocument.spreadsheet", "content.xml": odsContent} {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	r, err := reader.NewReaderFactory().CreateReader(filePath, nil)
+	if err != nil || r == nil {
+		t.Fatalf("Expected ods reader, got %v (%v)", r, err)
+	}
+	sheets, err := r.ReadAll(filePath)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(sheets) != 1 || sheets[0].Name != "items" || sheets[0].KeyColumn != "id" {
+		t.Fatalf("Unexpected sheets: %+v", sheets)
+	}
+	sheet := sheets[0]
+	if sheet.Columns[2].Comment != "说明" {
+		t.Errorf("Expected repeated comment cell, got %+v", sheet.Columns)
+	}
+
+	expected := []map[string]interface{}{
+		{"id": 1, "name": "long  sword\n第二行", "price": 0.25, "enabled": true},
+		{"id": 2, "name": nil, "price": nil, "enabled": false},
+	}
+	if len(sheet.Rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d: %v", len(expected), len(sheet.Rows), sheet.Rows)
+	}
+	for i, want := range expected {
+		for key, value := range want {
+			if got := sheet.Rows[i][key]; !reflect.DeepEqual(got, value) {
+				t.Errorf("Row %d column %s: expected %v, got %v", i, key, value, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+)
+
+// TestTransactionCommit 测试事务提交后替换文件并清理暂存目录
+func TestTransactionCommit(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "items.json"), []byte("old"), 0644)
+
+	tx, err := output.Begin(root, false)
+	if err != nil {
+		t.Fatalf("开始事务失败: %v", err)
+	}
+	if err := tx.Write("items.json", []byte("new")); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if err := tx.Write(filepath.Join("php", "items.php"), []byte("<?php")); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	// 提交前目标文件保持不变
+	if content, _ := os.ReadFile(filepath.Join(root, "items.json")); string(content) != "old" {
+		t.Errorf("提交前文件被修改: %s", content)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("提交失败: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "items.json")); string(content) != "new" {
+		t.Errorf("期望 new，实际为 %s", content)
+	}
+	if _, err := os.Stat(filepath.Join(root, "php", "items.php")); err != nil {
+		t.Errorf("期望生成 php/items.php: %v", err)
+	}
+	if _, err := os.Stat(tx.StagingDir()); !os.IsNotExist(err) {
+		t.Errorf("期望暂存目录被删除")
+	}
+}
+
+// TestTransactionRollback 测试提交失败时回滚已替换的文件
+func TestTransactionRollback(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "a.json"), []byte("old"), 0644)
+	// 目标父目录被普通文件占用，导致第二个文件提交失败
+	os.WriteFile(filepath.Join(root, "php"), []byte("file"), 0644)
+
+	tx, err := output.Begin(root, false)
+	if err != nil {
+		t.Fatalf("开始事务失败: %v", err)
+	}
+	tx.Write("a.json", []byte("new"))
+	tx.Write(filepath.Join("php", "b.php"), []byte("new"))
+
+	if err := tx.Commit(); err == nil {
+		t.Fatalf("期望提交失败")
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "a.json")); string(content) != "old" {
+		t.Errorf("期望回滚为 old，实际为 %s", content)
+	}
+}
+
+// TestTransactionWriteAll 测试并发写入暂存目录并同步到磁盘，同一路径以最后一次为准
+func TestTransactionWriteAll(t *testing.T) {
+	root := t.TempDir()
+	tx, err := output.BeginWithOptions(root, false, output.WriteOptions{Workers: 8, Fsync: true})
+	if err != nil {
+		t.Fatalf("开始事务失败: %v", err)
+	}
+
+	files := make([]output.File, 0)
+	for i := 0; i < 200; i++ {
+		files = append(files, output.File{Path: filepath.Join("json", fmt.Sprintf("t%d.json", i)), Content: []byte(fmt.Sprint(i))})
+	}
+	files = append(files, output.File{Path: filepath.Join("json", "t0.json"), Content: []byte("last")})
+
+	var done int32
+	if err := tx.WriteAll(files, func() { atomic.AddInt32(&done, 1) }); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	if done != int32(len(files)) {
+		t.Errorf("期望回调 %d 次，实际为 %d", len(files), done)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("提交失败: %v", err)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(root, "json", "t0.json")); string(content) != "last" {
+		t.Errorf("期望 last，实际为 %s", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "json", "t199.json")); string(content) != "199" {
+		t.Errorf("期望 199，实际为 %s", content)
+	}
+}
+
+// TestTransactionRemove 测试事务中删除文件
+func TestTransactionRemove(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "old.json"), []byte("old"), 0644)
+
+	tx, err := output.Begin(root, false)
+	if err != nil {
+		t.Fatalf("开始事务失败: %v", err)
+	}
+	tx.Remove("old.json")
+	if _, err := os.Stat(filepath.Join(root, "old.json")); err != nil {
+		t.Errorf("提交前文件不应被删除")
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("提交失败: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "old.json")); !os.IsNotExist(err) {
+		t.Errorf("期望文件被删除")
+	}
+}
+
+// TestManifestStale 测试找出过期的输出文件
+func TestManifestStale(t *testing.T) {
+	root := t.TempDir()
+	manifest, err := output.LoadManifest(root)
+	if err != nil || len(manifest.Files) != 0 {
+		t.Fatalf("清单不存在时期望返回空清单: %v", err)
+	}
+
+	manifest.Files = []string{"json/items.json", "json/old.json", "php/old.php"}
+	stale := manifest.Stale([]string{"json/items.json"})
+	if len(stale) != 2 || stale[0] != "json/old.json" || stale[1] != "php/old.php" {
+		t.Errorf("过期文件错误: %v", stale)
+	}
+}
+
+// TestDiffDir 测试按内容哈希比较将写入的文件与目录现有内容
+func TestDiffDir(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{"items.json": "[1]", "shop.json": "[2]", "old.json": "[3]"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := []output.File{
+		{Path: "items.json", Content: []byte("[1]")},
+		{Path: "shop.json", Content: []byte("[2, 3]")},
+		{Path: "monsters.json", Content: []byte("[]")},
+	}
+	diff, err := output.DiffDir(root, files, []string{"old.json", "missing.json"})
+	if err != nil {
+		t.Fatalf("DiffDir failed: %v", err)
+	}
+	if fmt.Sprint(diff.Added, diff.Updated, diff.Deleted, diff.Unchanged) != "[monsters.json] [shop.json] [old.json] 1" {
+		t.Errorf("差异错误: %+v", diff)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "shop.json")); string(content) != "[2]" {
+		t.Errorf("比较时不应修改目录: %s", content)
+	}
+}
+
+// TestVersionFile 测试版本文件记录文件哈希和大小
+func TestVersionFile(t *testing.T) {
+	version := output.NewVersionFile(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), "abc123")
+	version.Add(filepath.Join("json", "items.json"), []byte("hello"))
+
+	content, err := version.Marshal()
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+
+	var decoded output.VersionFile
+	json.Unmarshal(content, &decoded)
+	if decoded.BuildTime != "2024-05-01T08:00:00Z" || decoded.Commit != "abc123" {
+		t.Errorf("版本信息错误: %+v", decoded)
+	}
+	if len(decoded.Files) != 1 {
+		t.Fatalf("期望 1 个文件，实际为 %d", len(decoded.Files))
+	}
+	entry := decoded.Files[0]
+	if entry.Path != "json/items.json" || entry.Size != 5 ||
+		entry.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("文件校验信息错误: %+v", entry)
+	}
+}
+
+// TestVersionFileInheritAndChanged 测试预览构建沿用未生成文件的校验信息
+func TestVersionFileInheritAndChanged(t *testing.T) {
+	previous := output.NewVersionFile(time.Now(), "")
+	previous.Add("json/items.json", []byte("items"))
+	previous.Add("json/weapons.json", []byte("weapons"))
+
+	current := output.NewVersionFile(time.Now(), "")
+	current.Add("json/items.json", []byte("items v2"))
+	current.Inherit(previous, []string{"json/weapons.json"})
+	current.FailedSheets = []string{"weapons"}
+
+	if len(current.Files) != 2 || current.Files[1].Path != "json/weapons.json" {
+		t.Fatalf("期望沿用 weapons 的校验信息: %+v", current.Files)
+	}
+	changed := current.Changed(previous)
+	if len(changed) != 1 || changed[0] != "json/items.json" {
+		t.Errorf("变化的文件错误: %v", changed)
+	}
+
+	content, _ := current.Marshal()
+	if !strings.Contains(string(content), `"failedSheets"`) {
+		t.Errorf("期望记录失败的表: %s", content)
+	}
+}
+
+// publishCAS 写入内容并发布一个版本
+func publishCAS(t *testing.T, store *output.CASStore, buildTime time.Time, files map[string]string) string {
+	version := output.NewVersionFile(buildTime, "")
+	for path, content := range files {
+		if _, err := store.WriteBlob([]byte(content)); err != nil {
+			t.Fatalf("写入内容失败: %v", err)
+		}
+		version.Add(path, []byte(content))
+	}
+	id := store.NewVersionID(buildTime)
+	if err := store.Publish(id, version); err != nil {
+		t.Fatalf("发布版本失败: %v", err)
+	}
+	return id
+}
+
+// TestCASStorePublishAndRollback 测试内容寻址存储的发布、latest 链接和回滚
+func TestCASStorePublishAndRollback(t *testing.T) {
+	root := t.TempDir()
+	store := output.NewCASStore(root)
+	buildTime := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	first := publishCAS(t, store, buildTime, map[string]string{"json/items.json": "v1", "json/weapons.json": "w"})
+	second := publishCAS(t, store, buildTime, map[string]string{"json/items.json": "v2", "json/weapons.json": "w"})
+	if first != "20240501-080000" || second != "20240501-080000-2" {
+		t.Fatalf("版本号不正确: %s, %s", first, second)
+	}
+
+	// 相同内容只保存一份
+	blobs, _ := filepath.Glob(filepath.Join(root, output.BlobsDir, "*", "*"))
+	if len(blobs) != 3 {
+		t.Errorf("期望 3 个内容文件，实际为 %d", len(blobs))
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(root, output.LatestLink, "json", "items.json")); string(content) != "v2" {
+		t.Errorf("latest 应指向第二个版本，实际内容为 %s", content)
+	}
+
+	if err := store.SetLatest(first); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, output.LatestLink, "json", "items.json")); string(content) != "v1" {
+		t.Errorf("回滚后内容应为 v1，实际为 %s", content)
+	}
+	if err := store.SetLatest("missing"); err == nil {
+		t.Error("切换到不存在的版本应该失败")
+	}
+}
+
+// TestCASStoreRetain 测试清理历史版本时保留当前版本和仍被引用的内容
+func TestCASStoreRetain(t *testing.T) {
+	root := t.TempDir()
+	store := output.NewCASStore(root)
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	first := publishCAS(t, store, start, map[string]string{"items.json": "v1", "shared.json": "s"})
+	publishCAS(t, store, start.Add(time.Minute), map[string]string{"items.json": "v2", "shared.json": "s"})
+	third := publishCAS(t, store, start.Add(2*time.Minute), map[string]string{"items.json": "v3", "shared.json": "s"})
+
+	// 回滚到最早的版本后，清理时仍保留该版本
+	store.SetLatest(first)
+	removed, err := store.Retain(1)
+	if err != nil {
+		t.Fatalf("清理失败: %v", err)
+	}
+	if len(removed) != 1 {
+		t.Fatalf("期望删除 1 个版本，实际为 %v", removed)
+	}
+
+	versions, _ := store.Versions()
+	if len(versions) != 2 || versions[0] != first || versions[1] != third {
+		t.Errorf("保留的版本不正确: %v", versions)
+	}
+	blobs, _ := filepath.Glob(filepath.Join(root, output.BlobsDir, "*", "*"))
+	if len(blobs) != 3 {
+		t.Errorf("期望保留 3 个内容文件，实际为 %d", len(blobs))
+	}
+}
+
+// TestSheetVersionsBump 测试表内容变化时版本加一，内容不变时保持不变
+func TestSheetVersionsBump(t *testing.T) {
+	path := filepath.Join(t.TempDir(), output.SheetVersionsFileName)
+	versions, err := output.LoadSheetVersions(path)
+	if err != nil {
+		t.Fatalf("读取表版本失败: %v", err)
+	}
+
+	sheet := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}},
+		Rows:    []map[string]interface{}{{"id": 1}},
+	}
+	if version, _ := versions.Bump(sheet); version != 1 {
+		t.Errorf("首次出现的表版本应为 1，实际为 %d", version)
+	}
+	if err := versions.Save(path); err != nil {
+		t.Fatalf("保存表版本失败: %v", err)
+	}
+
+	// 元数据不影响内容哈希
+	versions, _ = output.LoadSheetVersions(path)
+	sheet.Meta = map[string]interface{}{output.SheetVersionMetaKey: 1}
+	if version, _ := versions.Bump(sheet); version != 1 || versions.Changed() {
+		t.Errorf("内容不变时版本不应变化，实际为 %d", version)
+	}
+
+	sheet.Rows = append(sheet.Rows, map[string]interface{}{"id": 2})
+	if version, _ := versions.Bump(sheet); version != 2 || !versions.Changed() {
+		t.Errorf("内容变化后版本应为 2，实际为 %d", version)
+	}
+	if versions.Versions()["items"] != 2 {
+		t.Errorf("版本映射不正确: %v", versions.Versions())
+	}
+}
@@ -0,0 +1,199 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/output"
+	"github.com/klauspost/compress/zstd"
+)
+
+// newPackResults 创建打包测试用的转换结果
+func newPackResults() []*model.ConvertResult {
+	return []*model.ConvertResult{
+		{FileName: "items.json", Format: "json", Sheet: "items", Content: []byte(`{"name":"items"}`)},
+		{FileName: "weapons.json", Format: "json", Sheet: "weapons", Content: []byte(`{"name":"weapons"}`)},
+	}
+}
+
+// TestParsePackOptions 测试打包选项校验
+func TestParsePackOptions(t *testing.T) {
+	if _, err := output.ParsePackOptions(map[string]interface{}{"compress": "gzip", "bundle": "pak"}); err != nil {
+		t.Errorf("期望选项有效: %v", err)
+	}
+	if _, err := output.ParsePackOptions(map[string]interface{}{"compress": "zstd"}); err != nil {
+		t.Errorf("期望 zstd 有效: %v", err)
+	}
+	if _, err := output.ParsePackOptions(map[string]interface{}{"compress": "brotli"}); err == nil {
+		t.Errorf("期望不支持的压缩方式返回错误")
+	}
+	if _, err := output.ParsePackOptions(map[string]interface{}{"bundle": "rar"}); err == nil {
+		t.Errorf("期望不支持的打包方式返回错误")
+	}
+}
+
+// TestPackGzip 测试单文件 gzip 压缩
+func TestPackGzip(t *testing.T) {
+	packed, err := output.Pack("json", newPackResults(), output.PackOptions{Compress: output.CompressGzip})
+	if err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+	if len(packed) != 2 || packed[0].FileName != "items.json.gz" || packed[0].Sheet != "items" {
+		t.Fatalf("压缩结果错误: %+v", packed[0])
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(packed[0].Content))
+	if err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	content, _ := io.ReadAll(reader)
+	if string(content) != `{"name":"items"}` {
+		t.Errorf("解压内容错误: %s", content)
+	}
+}
+
+// TestPackZstd 测试单文件 zstd 压缩
+func TestPackZstd(t *testing.T) {
+	packed, err := output.Pack("json", newPackResults(), output.PackOptions{Compress: output.CompressZstd})
+	if err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+	if len(packed) != 2 || packed[1].FileName != "weapons.json.zst" || packed[1].Sheet != "weapons" {
+		t.Fatalf("压缩结果错误: %+v", packed[1])
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		t.Fatalf("创建解压器失败: %v", err)
+	}
+	defer decoder.Close()
+	content, err := decoder.DecodeAll(packed[1].Content, nil)
+	if err != nil {
+		t.Fatalf("解压失败: %v", err)
+	}
+	if string(content) != `{"name":"weapons"}` {
+		t.Errorf("解压内容错误: %s", content)
+	}
+}
+
+// TestPackZipBundle 测试打成 zip 包
+func TestPackZipBundle(t *testing.T) {
+	packed, err := output.Pack("json", newPackResults(), output.PackOptions{Bundle: output.BundleZip})
+	if err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+	if len(packed) != 1 || packed[0].FileName != "json.zip" {
+		t.Fatalf("打包结果错误: %+v", packed)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(packed[0].Content), int64(len(packed[0].Content)))
+	if err != nil {
+		t.Fatalf("读取 zip 失败: %v", err)
+	}
+	if len(archive.File) != 2 || archive.File[1].Name != "weapons.json" {
+		t.Errorf("zip 内容错误")
+	}
+}
+
+// TestPackPakBundle 测试打成 pak 包并按索引读取
+func TestPackPakBundle(t *testing.T) {
+	packed, err := output.Pack("json", newPackResults(), output.PackOptions{Bundle: output.BundlePak})
+	if err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+	pak := packed[0].Content
+	if string(pak[:4]) != output.PakMagic {
+		t.Fatalf("pak 文件头错误")
+	}
+	if count := binary.LittleEndian.Uint32(pak[8:12]); count != 2 {
+		t.Fatalf("期望 2 个文件，实际为 %d", count)
+	}
+
+	// 读取第一个索引项
+	nameLen := int(binary.LittleEndian.Uint16(pak[12:14]))
+	name := string(pak[14 : 14+nameLen])
+	offset := binary.LittleEndian.Uint64(pak[14+nameLen:])
+	size := binary.LittleEndian.Uint64(pak[22+nameLen:])
+	if name != "items.json" || string(pak[offset:offset+size]) != `{"name":"items"}` {
+		t.Errorf("pak 索引错误: %s %d %d", name, offset, size)
+	}
+}
+
+// TestPackEncrypt 测试加密输出及解密
+func TestPackEncrypt(t *testing.T) {
+	key := strings.Repeat("ab", 32)
+	t.Setenv("TEST_DATA_KEY", key)
+
+	opts, err := output.ParsePackOptions(map[string]interface{}{
+		"compress":      "gzip",
+		"encrypt":       true,
+		"encryptKeyId":  "v1",
+		"encryptKeyEnv": "TEST_DATA_KEY",
+	})
+	if err != nil {
+		t.Fatalf("解析选项失败: %v", err)
+	}
+
+	packed, err := output.Pack("json", newPackResults(), opts)
+	if err != nil {
+		t.Fatalf("打包失败: %v", err)
+	}
+	if packed[0].FileName != "items.json.gz.enc" {
+		t.Fatalf("期望文件名 items.json.gz.enc，实际为 %s", packed[0].FileName)
+	}
+
+	// 相同输入的加密结果一致
+	again, _ := output.Pack("json", newPackResults(), opts)
+	if !bytes.Equal(packed[0].Content, again[0].Content) {
+		t.Errorf("期望加密结果稳定")
+	}
+
+	keys := map[string][]byte{"v1": bytes.Repeat([]byte{0xab}, 32)}
+	compressed, err := output.Decrypt(keys, packed[0].Content)
+	if err != nil {
+		t.Fatalf("解密失败: %v", err)
+	}
+	reader, _ := gzip.NewReader(bytes.NewReader(compressed))
+	content, _ := io.ReadAll(reader)
+	if string(content) != `{"name":"items"}` {
+		t.Errorf("解密内容错误: %s", content)
+	}
+
+	// nonce 不直接以加密密钥作为 HMAC 密钥派生
+	mac := hmac.New(sha256.New, keys["v1"])
+	mac.Write([]byte("items.json.gz"))
+	mac.Write([]byte{0})
+	mac.Write(compressed)
+	nonceStart := len(output.EncryptMagic) + 2 + len("v1")
+	if bytes.Equal(packed[0].Content[nonceStart:nonceStart+12], mac.Sum(nil)[:12]) {
+		t.Errorf("nonce 不应使用加密密钥直接派生")
+	}
+
+	// 篡改内容或缺少密钥时解密失败
+	tampered := append([]byte(nil), packed[0].Content...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := output.Decrypt(keys, tampered); err == nil {
+		t.Errorf("期望篡改后解密失败")
+	}
+	if _, err := output.Decrypt(map[string][]byte{}, packed[0].Content); err == nil {
+		t.Errorf("期望缺少密钥时解密失败")
+	}
+}
+
+// TestParseEncryptOptionsInvalidKey 测试加密密钥校验
+func TestParseEncryptOptionsInvalidKey(t *testing.T) {
+	if _, err := output.ParsePackOptions(map[string]interface{}{"encrypt": true}); err == nil {
+		t.Errorf("期望未配置密钥时返回错误")
+	}
+	if _, err := output.ParsePackOptions(map[string]interface{}{"encrypt": true, "encryptKey": "abcd"}); err == nil {
+		t.Errorf("期望密钥长度不足时返回错误")
+	}
+}
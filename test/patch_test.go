@@ -0,0 +1,103 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/diff"
+	"github.com/game-data-builder/internal/model"
+)
+
+// patchSheet JSON Patch 测试用的表
+func patchSheet(version int, rows ...map[string]interface{}) *model.DataSheet {
+	return &model.DataSheet{
+		Name: "items",
+		Columns: []model.ColumnInfo{
+			{Name: "id", Type: "int"},
+			{Name: "name", Type: "string"},
+			{Name: "reward.count", Type: "int"},
+		},
+		Rows: rows,
+		Meta: map[string]interface{}{"dataVersion": version},
+	}
+}
+
+// convertJSON 使用 JSON 转换器生成文件内容
+func convertJSON(t *testing.T, sheet *model.DataSheet, options map[string]interface{}) []byte {
+	conv := converter.NewJSONConverter()
+	if err := conv.Init(options); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(sheet)
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	return result.Content
+}
+
+// decodeJSON 以 UseNumber 解析 JSON
+func decodeJSON(t *testing.T, content []byte) interface{} {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// TestJSONPatch 测试按主键生成的补丁应用到旧文件后与新文件一致
+func TestJSONPatch(t *testing.T) {
+	oldSheet := patchSheet(1,
+		map[string]interface{}{"id": 1, "name": "sword", "reward": map[string]interface{}{"count": 1}},
+		map[string]interface{}{"id": 2, "name": "shield"},
+		map[string]interface{}{"id": 3, "name": "bow"},
+		map[string]interface{}{"id": 4, "name": "a/b~c"},
+	)
+	newSheet := patchSheet(2,
+		map[string]interface{}{"id": 4, "name": "a/b~c"},
+		map[string]interface{}{"id": 5, "name": "axe"},
+		map[string]interface{}{"id": 1, "name": "sword", "reward": map[string]interface{}{"count": 2}},
+		map[string]interface{}{"id": 3, "name": "1"},
+	)
+
+	for _, options := range []map[string]interface{}{{}, {"rowsAsMap": true}} {
+		oldContent := convertJSON(t, oldSheet, options)
+		newContent := convertJSON(t, newSheet, options)
+
+		ops, err := diff.JSONPatch(oldContent, newContent, "dataVersion")
+		if err != nil {
+			t.Fatalf("JSONPatch failed: %v", err)
+		}
+		if len(ops) == 0 || ops[0].Op != "test" || ops[0].Path != "/meta/dataVersion" {
+			t.Fatalf("Expected a leading version test, got %+v", ops)
+		}
+
+		content, err := json.Marshal(ops)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patched, err := diff.ApplyPatch(decodeJSON(t, oldContent), ops)
+		if err != nil {
+			t.Fatalf("ApplyPatch failed: %v", err)
+		}
+		patchedJSON, _ := json.Marshal(patched)
+		expectedJSON, _ := json.Marshal(decodeJSON(t, newContent))
+		if !bytes.Equal(patchedJSON, expectedJSON) {
+			t.Errorf("Patched document mismatch (%v):\n%s\n%s\npatch: %s", options, patchedJSON, expectedJSON, content)
+		}
+
+		// 补丁不能应用到其他版本上
+		if _, err := diff.ApplyPatch(decodeJSON(t, newContent), ops); err == nil {
+			t.Error("Expected version test to fail")
+		}
+	}
+
+	// 没有变化时补丁为空
+	content := convertJSON(t, oldSheet, map[string]interface{}{})
+	if ops, err := diff.JSONPatch(content, content, "dataVersion"); err != nil || len(ops) != 0 {
+		t.Errorf("Expected empty patch, got %v %v", ops, err)
+	}
+}
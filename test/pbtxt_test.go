@@ -0,0 +1,104 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/game-data-builder/internal/reader"
+)
+
+// TestPbtxtReader 测试读取 Protobuf 文本格式的开发覆盖配置
+func TestPbtxtReader(t *testing.T) {
+	content := `# 开发环境覆盖的道具配置
+meta { key: "tags" value: "dev" }
+column { name: "id" type: "int" comment: "道具ID" key: true }
+column { name: "quality" type: "enum:Quality" }
+
+row {
+  id: 1
+  quality: RARE
+  name: "sword" " of fire"
+  price: 10
+  reward { itemId: 1001 count: 2 }
+  tags: "melee"
+  tags: "fire"
+}
+row <
+  id: 0x2,
+  quality: COMMON;
+  name: 'shield\n'
+  price: 12.5
+  enabled: true
+  tags: []
+>
+`
+	filePath := filepath.Join(t.TempDir(), "dev_items.pbtxt")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := reader.NewReaderFactory().GetReader(filePath)
+	if r == nil {
+		t.Fatal("Expected pbtxt reader")
+	}
+	sheets, err := r.ReadAll(filePath)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	sheet := sheets[0]
+	if sheet.Name != "dev_items" || sheet.KeyColumn != "id" || sheet.Meta["tags"] != "dev" {
+		t.Errorf("Unexpected sheet: %s key=%s meta=%v", sheet.Name, sheet.KeyColumn, sheet.Meta)
+	}
+
+	types := make(map[string]string)
+	names := make([]string, 0)
+	for _, col := range sheet.Columns {
+		types[col.Name] = col.Type
+		names = append(names, col.Name)
+	}
+	wantNames := []string{"id", "quality", "name", "price", "reward.itemId", "reward.count", "tags", "enabled"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("Unexpected columns %v", names)
+	}
+	if types["price"] != "float" || types["tags"] != "list" || types["enabled"] != "bool" || types["reward.itemId"] != "int" {
+		t.Errorf("Unexpected inferred types %v", types)
+	}
+
+	first, second := sheet.Rows[0], sheet.Rows[1]
+	if first["name"] != "sword of fire" || first["quality"] != "RARE" || first["price"] != 10.0 {
+		t.Errorf("Unexpected first row %v", first)
+	}
+	if reward := first["reward"].(map[string]interface{}); reward["itemId"] != 1001 || reward["count"] != 2 {
+		t.Errorf("Unexpected reward %v", reward)
+	}
+	if !reflect.DeepEqual(first["tags"], []interface{}{"melee", "fire"}) {
+		t.Errorf("Unexpected tags %v", first["tags"])
+	}
+	if second["id"] != 2 || second["name"] != "shield\n" || second["enabled"] != true || first["enabled"] != nil {
+		t.Errorf("Unexpected second row %v", second)
+	}
+}
+
+// TestPbtxtReaderErrors 测试格式错误和类型不符时报告行号
+func TestPbtxtReaderErrors(t *testing.T) {
+	cases := map[string]string{
+		"unclosed":  "row { id: 1\n",
+		"unknown":   "rows { id: 1 }\n",
+		"mismatch":  "row { id: 1 }\nrow { id: \"a\" }\n",
+		"notInt":    "column { name: \"id\" type: \"int\" }\nrow { id: 1.5 }\n",
+		"noColon":   "row { id 1 }\n",
+		"extension": "row { [ext.field]: 1 }\n",
+	}
+	dir := t.TempDir()
+	for name, content := range cases {
+		filePath := filepath.Join(dir, name+".textproto")
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := reader.NewPbtxtReader().ReadAll(filePath); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/permission"
+)
+
+// TestPermissionCheckChanges 测试列级写权限
+func TestPermissionCheckChanges(t *testing.T) {
+	checker := permission.NewChecker(&config.PermissionConfig{
+		Default: "allow",
+		Sheets: map[string]map[string][]string{
+			"items": {"price": {"economy"}},
+		},
+	})
+
+	current := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id"}, {Name: "name"}, {Name: "price"}},
+		Rows:    []map[string]interface{}{{"id": 1, "name": "sword", "price": 100}},
+	}
+	updated := &model.DataSheet{
+		Name:    "items",
+		Columns: current.Columns,
+		Rows:    []map[string]interface{}{{"id": 1, "name": "long sword", "price": 120}},
+	}
+
+	violations := checker.CheckChanges("designer", current, updated)
+	if len(violations) != 1 || violations[0].Column != "price" {
+		t.Errorf("Expected designer to be denied price change only, got %v", violations)
+	}
+
+	if violations := checker.CheckChanges("economy", current, updated); len(violations) != 0 {
+		t.Errorf("Expected economy designer to be allowed, got %v", violations)
+	}
+}
+
+// TestPermissionRemovedColumn 测试上传的表缺少受保护的列时视为修改该列
+func TestPermissionRemovedColumn(t *testing.T) {
+	checker := permission.NewChecker(&config.PermissionConfig{
+		Default: "allow",
+		Sheets: map[string]map[string][]string{
+			"items": {"price": {"economy"}},
+		},
+	})
+
+	current := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id"}, {Name: "name"}, {Name: "price"}},
+		Rows:    []map[string]interface{}{{"id": 1, "name": "sword", "price": 100}},
+	}
+	updated := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id"}, {Name: "name"}},
+		Rows:    []map[string]interface{}{{"id": 1, "name": "sword"}},
+	}
+
+	violations := checker.CheckChanges("designer", current, updated)
+	if len(violations) != 1 || violations[0].Column != "price" {
+		t.Errorf("Expected removing price column to be denied, got %v", violations)
+	}
+
+	if violations := checker.CheckChanges("economy", current, updated); len(violations) != 0 {
+		t.Errorf("Expected economy designer to be allowed, got %v", violations)
+	}
+}
@@ -1,9 +1,16 @@
 package test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/game-data-builder/internal/config"
 	"github.com/game-data-builder/internal/reader"
+	"github.com/xuri/excelize/v2"
 )
 
 // TestReaderFactory 测试读取器工厂
@@ -23,7 +30,7 @@ func TestReaderFactory(t *testing.T) {
 	}
 
 	// 测试获取不支持的读取器
-	invalidReader := factory.GetReader("test.txt")
+	invalidReader := factory.GetReader("test.doc")
 	if invalidReader != nil {
 		t.Error("Expected nil for invalid file type, got reader")
 	}
@@ -72,3 +79,283 @@ func TestExcelReaderFormats(t *testing.T) {
 		}
 	}
 }
+
+// TestCSVReaderHeaderLayout 测试自定义表头布局
+func TestCSVReaderHeaderLayout(t *testing.T) {
+	content := "道具表,,\n" +
+		"id,name,price\n" +
+		"ID,名称,价格\n" +
+		"int,string,int\n" +
+		",,100\n" +
+		"1,sword,\n"
+	filePath := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	csvReader := reader.NewCSVReader()
+	err := csvReader.Init(map[string]interface{}{
+		"skipRows":     float64(1),
+		"headerLayout": []interface{}{"name", "comment", "type", "default"},
+	})
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	sheet, err := csvReader.ReadSheet(filePath, "")
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+
+	if len(sheet.Columns) != 3 || sheet.Columns[2].Type != "int" {
+		t.Fatalf("Unexpected columns: %+v", sheet.Columns)
+	}
+	if len(sheet.Rows) != 1 || sheet.Rows[0]["price"] != 100 {
+		t.Errorf("Expected default price 100, got %+v", sheet.Rows)
+	}
+	if sheet.RowNumber(0) != 6 {
+		t.Errorf("Expected first data row at line 6, got %d", sheet.RowNumber(0))
+	}
+}
+
+// TestDelimitedReader 测试读取制表符和竖线分隔的文本文件
+func TestDelimitedReader(t *testing.T) {
+	dir := t.TempDir()
+	tsvPath := filepath.Join(dir, "items.tsv")
+	tsv := "id\tname\tprice\nint\tstring\tfloat\nID|主键\t名称\t价格\n1\tsword, \"long\"\t1.5\n"
+	if err := os.WriteFile(tsvPath, []byte(tsv), 0644); err != nil {
+		t.Fatal(err)
+	}
+	txtPath := filepath.Join(dir, "shops.txt")
+	if err := os.WriteFile(txtPath, []byte("id|name\nint|string\nID|名称\n7|general\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// .txt 按 delimiter 选项分隔，.tsv 始终按制表符分隔
+	factory := reader.NewReaderFactory()
+	options := map[string]interface{}{"delimiter": "pipe"}
+	for _, filePath := range []string{tsvPath, txtPath} {
+		r, err := factory.CreateReader(filePath, options)
+		if err != nil || r == nil {
+			t.Fatalf("Expected delimited reader for %s, got %v (%v)", filePath, r, err)
+		}
+		sheets, err := r.ReadAll(filePath)
+		if err != nil {
+			t.Fatalf("ReadAll %s failed: %v", filePath, err)
+		}
+		row := sheets[0].Rows[0]
+		switch sheets[0].Name {
+		case "items":
+			if sheets[0].KeyColumn != "id" || row["name"] != `sword, "long"` || row["price"] != 1.5 {
+				t.Errorf("Unexpected tsv row: %+v", row)
+			}
+		case "shops":
+			if row["id"] != 7 || row["name"] != "general" {
+				t.Errorf("Unexpected txt row: %+v", row)
+			}
+		default:
+			t.Errorf("Unexpected sheet %s", sheets[0].Name)
+		}
+	}
+
+	if _, err := factory.CreateReader(txtPath, map[string]interface{}{"delimiter": "||"}); err == nil {
+		t.Error("Expected error for multi-character delimiter")
+	}
+}
+
+// TestHeaderLayoutRequiresName 测试表头布局必须包含列名行
+func TestHeaderLayoutRequiresName(t *testing.T) {
+	_, err := reader.ParseHeaderLayout(map[string]interface{}{
+		"headerLayout": []interface{}{"type", "comment"},
+	})
+	if err == nil {
+		t.Error("Expected error for layout without name row")
+	}
+}
+
+// TestCSVReaderNestedColumns 测试点号分隔的嵌套列
+func TestCSVReaderNestedColumns(t *testing.T) {
+	content := "id,reward.itemId,reward.count\n" +
+		"int,int,int\n" +
+		"ID,奖励物品,奖励数量\n" +
+		"1,1001,5\n"
+	filePath := filepath.Join(t.TempDir(), "quests.csv")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := reader.NewCSVReader().ReadSheet(filePath, "")
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+
+	reward, ok := sheet.Rows[0]["reward"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected nested reward object, got %+v", sheet.Rows[0])
+	}
+	if reward["itemId"] != 1001 || reward["count"] != 5 {
+		t.Errorf("Unexpected reward: %+v", reward)
+	}
+}
+
+// TestCSVReaderColumnTransforms 测试类型行中声明的列转换
+func TestCSVReaderColumnTransforms(t *testing.T) {
+	content := "id,cooldown,code,rate\n" +
+		"int,int|*1000,string|trim|lower,float|/100|round\n" +
+		"ID,冷却,代码,概率\n" +
+		"1,1.5, SWORD ,1250\n"
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "skills.csv")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := reader.NewCSVReader().ReadSheet(filePath, "")
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+
+	row := sheet.Rows[0]
+	if row["cooldown"] != 1500 || row["code"] != "sword" || row["rate"] != 13.0 {
+		t.Errorf("Unexpected row: %+v", row)
+	}
+	col := sheet.Columns[1]
+	if col.Type != "int" || len(col.Transforms) != 1 || col.Transforms[0] != "*1000" {
+		t.Errorf("Unexpected column: %+v", col)
+	}
+
+	// 整数列的转换结果必须为整数
+	badPath := filepath.Join(dir, "bad.csv")
+	bad := "id,cooldown\nint,int|*10\nID,冷却\n1,1.25\n"
+	if err := os.WriteFile(badPath, []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.NewCSVReader().ReadSheet(badPath, ""); err == nil {
+		t.Error("Expected error for non-integer result")
+	}
+
+	// 与列类型不匹配的转换
+	if err := os.WriteFile(badPath, []byte("id,name\nint,string|*10\nID,名称\n1,a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.NewCSVReader().ReadSheet(badPath, ""); err == nil {
+		t.Error("Expected error for numeric transform on string column")
+	}
+}
+
+// TestConstantsSheet 测试由常量配置生成的虚拟表
+func TestConstantsSheet(t *testing.T) {
+	sheet, err := reader.ConstantsSheet(&config.ConstantsConfig{
+		Sheet: "constants",
+		Constants: []config.ConstantEntry{
+			{Key: "maxLevel", Type: "int", Value: float64(100), Comment: "最大等级"},
+			{Key: "dropRate", Type: "float", Value: "0.25"},
+			{Key: "starterItem", Type: "int", Value: float64(1), Ref: "items.id"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ConstantsSheet failed: %v", err)
+	}
+
+	if len(sheet.Columns) != 3 || len(sheet.Rows) != 1 {
+		t.Fatalf("Expected 3 columns and 1 row, got %d, %d", len(sheet.Columns), len(sheet.Rows))
+	}
+	if sheet.Rows[0]["maxLevel"] != 100 || sheet.Rows[0]["dropRate"] != 0.25 {
+		t.Errorf("Unexpected values: %v", sheet.Rows[0])
+	}
+	if ref := sheet.Columns[2].Ref; ref == nil || ref.Sheet != "items" || ref.Column != "id" {
+		t.Errorf("Expected ref to items.id, got %+v", ref)
+	}
+
+	_, err = reader.ConstantsSheet(&config.ConstantsConfig{
+		Sheet:     "constants",
+		Constants: []config.ConstantEntry{{Key: "maxLevel", Type: "int", Value: 1.5}},
+	})
+	if err == nil {
+		t.Error("Expected error for non-integer value")
+	}
+}
+
+// TestExcelReaderEvaluateFormulas 测试读取时计算未缓存结果的公式，包括位于最后一列的公式和声明范围远大于数据的工作表
+func TestExcelReaderEvaluateFormulas(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheetName := "items"
+	if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+		t.Fatal(err)
+	}
+	grid := [][]interface{}{
+		{"id", "base", "bonus", "total"},
+		{"int", "int", "int", "int"},
+		{"ID", "基础值", "加成", "合计"},
+		{1, 10, 5},
+		{2, 20, 7},
+	}
+	for rowIndex, values := range grid {
+		cell, _ := excelize.CoordinatesToCellName(1, rowIndex+1)
+		if err := f.SetSheetRow(sheetName, cell, &values); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// SetCellFormula 只写入公式，不写入缓存的计算结果
+	for _, rowNumber := range []int{4, 5} {
+		formula := fmt.Sprintf("B%d+C%d", rowNumber, rowNumber)
+		if err := f.SetCellFormula(sheetName, fmt.Sprintf("D%d", rowNumber), formula); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 声明的数据范围覆盖整张工作表时只遍历实际存在的单元格
+	if err := f.SetSheetDimension(sheetName, "A1:XFD1048576"); err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(t.TempDir(), "items.xlsx")
+	if err := f.SaveAs(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	excelReader := reader.NewExcelReader()
+	if err := excelReader.Init(map[string]interface{}{"evaluateFormulas": true}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	sheet, err := excelReader.ReadSheet(filePath, sheetName)
+	if err != nil {
+		t.Fatalf("ReadSheet failed: %v", err)
+	}
+	if len(sheet.Rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %+v", sheet.Rows)
+	}
+	if sheet.Rows[0]["total"] != 15 || sheet.Rows[1]["total"] != 27 {
+		t.Errorf("Expected evaluated totals 15 and 27, got %v and %v", sheet.Rows[0]["total"], sheet.Rows[1]["total"])
+	}
+}
+
+// TestReadAllContextCanceled 测试取消后读取器不再读取文件，不支持取消的读取器在读取前检查
+func TestReadAllContextCanceled(t *testing.T) {
+	dir := t.TempDir()
+	f := excelize.NewFile()
+	defer f.Close()
+	values := []interface{}{"id"}
+	if err := f.SetSheetRow("Sheet1", "A1", &values); err != nil {
+		t.Fatal(err)
+	}
+	xlsxPath := filepath.Join(dir, "items.xlsx")
+	if err := f.SaveAs(xlsxPath); err != nil {
+		t.Fatal(err)
+	}
+	csvPath := filepath.Join(dir, "quality.csv")
+	if err := os.WriteFile(csvPath, []byte("id\nint\nID\n1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, path := range []string{xlsxPath, csvPath} {
+		r, err := reader.NewReaderFactory().CreateReader(path, nil)
+		if err != nil || r == nil {
+			t.Fatalf("CreateReader(%s) failed: %v", path, err)
+		}
+		if _, err := reader.ReadAllContext(ctx, r, path); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", filepath.Base(path), err)
+		}
+	}
+}
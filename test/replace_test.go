@@ -0,0 +1,49 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/replace"
+)
+
+// TestReplaceApply 测试列替换：字面量、正则捕获组、引用其他列的模板和通配符表名列名
+func TestReplaceApply(t *testing.T) {
+	items := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "name", Type: "string"}, {Name: "icon", Type: "string"}, {Name: "key", Type: "string"}, {Name: "name_desc", Type: "string"}},
+		Rows: []map[string]interface{}{
+			{"id": 1, "name": "iron_sword", "icon": "icons/sword.png", "key": "", "name_desc": "sharp  "},
+		},
+	}
+	cfg := &config.ReplaceColumnConfig{Sheets: map[string]config.ReplaceRules{
+		"items": {Columns: map[string]config.ReplaceRule{
+			"name": {From: "_", To: "-"},
+			"icon": {From: `^icons/(\w+)\.png$`, To: "ui/$1", Regex: true},
+			"key":  {To: "{{.id}}_{{.name}}"},
+		}},
+		"*": {Columns: map[string]config.ReplaceRule{
+			"*_desc": {From: `\s+$`, Regex: true},
+			"id":     {From: "1", To: "2"},
+		}},
+	}}
+	if err := replace.Apply([]*model.DataSheet{items}, cfg); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	// 规则按表名、列名排序后应用，key 先于 name 替换，模板引用的是原来的 name；数字值不替换
+	row := items.Rows[0]
+	expected := map[string]interface{}{"id": 1, "name": "iron-sword", "icon": "ui/sword", "key": "1_iron_sword", "name_desc": "sharp"}
+	for column, val := range expected {
+		if row[column] != val {
+			t.Errorf("Column %s: expected %v, got %v", column, val, row[column])
+		}
+	}
+
+	cfg = &config.ReplaceColumnConfig{Sheets: map[string]config.ReplaceRules{
+		"items": {Columns: map[string]config.ReplaceRule{"key": {To: "{{.missing}}"}}},
+	}}
+	if err := replace.Apply([]*model.DataSheet{items}, cfg); err == nil {
+		t.Error("Expected error for template referencing unknown column")
+	}
+}
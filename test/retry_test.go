@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/game-data-builder/internal/reader"
+)
+
+// TestRetryPolicyFallbackToCache 测试重试失败后降级使用缓存
+func TestRetryPolicyFallbackToCache(t *testing.T) {
+	policy, err := reader.ParseRetryPolicy(map[string]interface{}{
+		"retryAttempts":   float64(2),
+		"retryBackoffMs":  float64(1),
+		"fallbackToCache": true,
+		"cacheDir":        t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 第一次成功，写入缓存
+	content, fromCache, err := policy.Fetch("http://example/items.csv", func(ctx context.Context) ([]byte, error) {
+		return []byte("cached"), nil
+	})
+	if err != nil || fromCache || string(content) != "cached" {
+		t.Fatalf("Unexpected first fetch: %q %v %v", content, fromCache, err)
+	}
+
+	// 之后持续失败，使用缓存
+	calls := 0
+	content, fromCache, err = policy.Fetch("http://example/items.csv", func(ctx context.Context) ([]byte, error) {
+		calls++
+		return nil, errors.New("unavailable")
+	})
+	if err != nil || !fromCache || string(content) != "cached" {
+		t.Errorf("Expected cached fallback, got %q %v %v", content, fromCache, err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls)
+	}
+}
+
+// TestRetryPolicyFetchContextCanceled 测试取消后不再重试，也不降级使用缓存
+func TestRetryPolicyFetchContextCanceled(t *testing.T) {
+	policy, err := reader.ParseRetryPolicy(map[string]interface{}{
+		"retryAttempts":   float64(3),
+		"retryBackoffMs":  float64(60000),
+		"fallbackToCache": true,
+		"cacheDir":        t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := policy.Fetch("db:main/items", func(ctx context.Context) ([]byte, error) {
+		return []byte("cached"), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 请求进行中取消，不应等待退避时间后重试
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	content, fromCache, err := policy.FetchContext(ctx, "db:main/items", func(ctx context.Context) ([]byte, error) {
+		calls++
+		cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) || fromCache || content != nil {
+		t.Errorf("Expected cancellation without cache fallback, got %q %v %v", content, fromCache, err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls)
+	}
+}
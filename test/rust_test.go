@@ -0,0 +1,118 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/converter"
+	"github.com/game-data-builder/internal/model"
+)
+
+// rustSheet 在公共测试表上追加一个 json 列
+func rustSheet() *model.DataSheet {
+	sheet := codegenSheet("shop.items")
+	sheet.Columns = append(sheet.Columns, model.ColumnInfo{Name: "tags", Type: "json"})
+	return sheet
+}
+
+// TestRustConverter 测试生成的结构体、嵌套结构体和字段重命名
+func TestRustConverter(t *testing.T) {
+	conv := converter.NewRustConverter()
+	if err := conv.Init(map[string]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(rustSheet())
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if result.FileName != "shop_items.rs" {
+		t.Errorf("unexpected file name: %s", result.FileName)
+	}
+
+	content := string(result.Content)
+	for _, want := range []string{
+		"pub struct ShopItems {\n    /// 编号\n    #[serde(default, deserialize_with = \"super::null_default\")]\n    pub id: i64,",
+		"    pub r#type: String,",
+		"    #[serde(rename = \"itemId\", default, deserialize_with = \"super::null_default\")]\n    pub item_id: i64,",
+		"    pub tags: serde_json::Value,",
+		"    pub reward: ShopItemsReward,",
+		"pub struct ShopItemsReward {",
+		"    pub ratio: f64,",
+		"pub fn parse(json: &[u8]) -> serde_json::Result<Vec<ShopItems>> {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "lazy_static") {
+		t.Error("embedded data should not be generated by default")
+	}
+
+	index, err := conv.ConvertIndex([]*model.DataSheet{rustSheet(), {Name: "mod"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index) != 1 || index[0].FileName != "mod.rs" || !strings.HasPrefix(string(index[0].Content), "// 由 game-data-builder 生成，请勿手动修改\n\npub mod mod_;\npub mod shop_items;\n") {
+		t.Errorf("unexpected mod.rs: %+v", index)
+	}
+}
+
+// TestRustConverterEmbed 测试嵌入数据和按主键的索引
+func TestRustConverterEmbed(t *testing.T) {
+	conv := converter.NewRustConverter()
+	if err := conv.Init(map[string]interface{}{"embed": true, "embedDir": "../../json/"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conv.Convert(rustSheet())
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	content := string(result.Content)
+	for _, want := range []string{
+		`parse(include_bytes!("../../json/shop/items.json")).expect("items.json 格式不正确");`,
+		"pub static ref INDEX: std::collections::HashMap<i64, usize> =",
+		"pub fn get(key: i64) -> Option<&'static ShopItems> {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("missing %q in:\n%s", want, content)
+		}
+	}
+
+	if err := converter.NewRustConverter().Init(map[string]interface{}{"embedDir": "/srv/json"}); err == nil {
+		t.Error("expected error for absolute embedDir")
+	}
+}
+
+// TestRustConverterParses 使用本机的 rustc 解析生成的文件，serde 等依赖不可用，
+// 所以只检查语法，不做类型检查
+func TestRustConverterParses(t *testing.T) {
+	rustc := lookTool(t, "rustc")
+
+	for _, config := range []map[string]interface{}{{}, {"embed": true}} {
+		conv := converter.NewRustConverter()
+		if err := conv.Init(config); err != nil {
+			t.Fatal(err)
+		}
+		sheets := []*model.DataSheet{rustSheet(), codegenSheet("battle.skills")}
+		results := make([]*model.ConvertResult, 0)
+		for _, sheet := range sheets {
+			result, err := conv.Convert(sheet)
+			if err != nil {
+				t.Fatalf("Convert failed: %v", err)
+			}
+			results = append(results, result)
+		}
+		index, err := conv.ConvertIndex(sheets)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dir := t.TempDir()
+		for _, file := range writeResults(t, dir, append(results, index...)...) {
+			runTool(t, dir, []string{"RUSTC_BOOTSTRAP=1"}, rustc, "-Zparse-crate-root-only", "--edition", "2021",
+				"--crate-type", "lib", "--out-dir", filepath.Join(dir, "out"), file)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/validator"
+)
+
+// requiredSheet 生成 count 行 id 列全部为空的表，每行都有一个必填错误
+func requiredSheet(count int) *model.DataSheet {
+	sheet := &model.DataSheet{
+		Name:    "big",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int", Required: true}},
+	}
+	for i := 0; i < count; i++ {
+		sheet.Rows = append(sheet.Rows, map[string]interface{}{})
+	}
+	return sheet
+}
+
+// TestValidatorSampling 测试大表抽样验证
+func TestValidatorSampling(t *testing.T) {
+	v := validator.NewDefaultValidator()
+	v.SetSampling(&validator.Sampling{Threshold: 50, Head: 10, Random: 5})
+
+	errors := v.Validate(requiredSheet(100))
+	if len(errors) != 15 {
+		t.Fatalf("Expected 15 sampled errors, got %d", len(errors))
+	}
+	// 前 10 行始终验证
+	first := errors[0].Row
+	for i := 0; i < 10; i++ {
+		if errors[i].Row != first+i {
+			t.Errorf("Expected head row %d, got %d", first+i, errors[i].Row)
+		}
+	}
+	for i := 10; i < 15; i++ {
+		if errors[i].Row < first+10 || errors[i].Row <= errors[i-1].Row {
+			t.Errorf("Unexpected sampled row %d", errors[i].Row)
+		}
+	}
+
+	// 不超过阈值的表完整验证
+	if errors := v.Validate(requiredSheet(50)); len(errors) != 50 {
+		t.Errorf("Expected small sheet to be fully validated, got %d errors", len(errors))
+	}
+
+	// 关闭抽样后完整验证
+	v.SetSampling(nil)
+	if errors := v.Validate(requiredSheet(100)); len(errors) != 100 {
+		t.Errorf("Expected full validation, got %d errors", len(errors))
+	}
+}
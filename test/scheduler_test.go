@@ -0,0 +1,110 @@
+package test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/game-data-builder/internal/scheduler"
+)
+
+// TestSchedulerDependencyOrder 测试任务按依赖顺序执行
+func TestSchedulerDependencyOrder(t *testing.T) {
+	var mu sync.Mutex
+	finished := make(map[string]bool)
+	record := func(id string, deps ...string) *scheduler.Task {
+		return &scheduler.Task{
+			ID:   id,
+			Deps: deps,
+			Run: func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, dep := range deps {
+					if !finished[dep] {
+						t.Errorf("Task %s ran before dependency %s", id, dep)
+					}
+				}
+				finished[id] = true
+				return nil
+			},
+		}
+	}
+
+	tasks := []*scheduler.Task{
+		record("json/index", "json/items", "json/weapons"),
+		record("json/items"),
+		record("json/weapons"),
+		record("php/items"),
+	}
+	if err := scheduler.NewScheduler(4).Run(tasks); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(finished) != len(tasks) {
+		t.Errorf("Expected %d tasks to finish, got %d", len(tasks), len(finished))
+	}
+}
+
+// TestSchedulerError 测试任务失败时返回错误且不执行依赖它的任务
+func TestSchedulerError(t *testing.T) {
+	expected := errors.New("boom")
+	ran := false
+	tasks := []*scheduler.Task{
+		{ID: "a", Run: func() error { return expected }},
+		{ID: "b", Deps: []string{"a"}, Run: func() error { ran = true; return nil }},
+	}
+
+	if err := scheduler.NewScheduler(2).Run(tasks); err != expected {
+		t.Errorf("Expected boom error, got %v", err)
+	}
+	if ran {
+		t.Error("Dependent task should not run after failure")
+	}
+}
+
+// TestSchedulerAggregateErrors 测试任务失败后其他任务继续执行，并返回所有错误
+func TestSchedulerAggregateErrors(t *testing.T) {
+	first := errors.New("items failed")
+	second := errors.New("weapons failed")
+	var mu sync.Mutex
+	ran := make(map[string]bool)
+	mark := func(id string) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			ran[id] = true
+			return nil
+		}
+	}
+
+	tasks := []*scheduler.Task{
+		{ID: "json/items", Run: func() error { return first }},
+		{ID: "json/weapons", Run: func() error { return second }},
+		{ID: "json/skills", Run: mark("json/skills")},
+		{ID: "json/index", Deps: []string{"json/items", "json/skills"}, Run: mark("json/index")},
+		{ID: "json/report", Deps: []string{"json/index"}, Run: mark("json/report")},
+	}
+
+	err := scheduler.NewScheduler(1).Run(tasks)
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("Expected both errors, got %v", err)
+	}
+	if !ran["json/skills"] {
+		t.Error("Independent task should still run after a failure")
+	}
+	if ran["json/index"] || ran["json/report"] {
+		t.Error("Tasks depending on a failed task should be skipped")
+	}
+}
+
+// TestSchedulerCycle 测试循环依赖检测
+func TestSchedulerCycle(t *testing.T) {
+	noop := func() error { return nil }
+	tasks := []*scheduler.Task{
+		{ID: "a", Deps: []string{"b"}, Run: noop},
+		{ID: "b", Deps: []string{"a"}, Run: noop},
+	}
+
+	if err := scheduler.NewScheduler(2).Run(tasks); err == nil {
+		t.Error("Expected cycle error")
+	}
+}
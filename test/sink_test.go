@@ -0,0 +1,159 @@
+package test
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/sink"
+)
+
+// sinkFiles 两种格式的待输出文件
+func sinkFiles() []sink.File {
+	return []sink.File{
+		{Path: "json/items.json", Format: "json", Content: []byte(`[{"id":1}]`)},
+		{Path: "lua/items.lua", Format: "lua", Content: []byte("return {}")},
+	}
+}
+
+// TestSinkFilter 测试按格式筛选输出目标接收的文件
+func TestSinkFilter(t *testing.T) {
+	files := sinkFiles()
+	if filtered := sink.Filter(files, nil); len(filtered) != 2 {
+		t.Errorf("Expected all files without formats, got %d", len(filtered))
+	}
+	filtered := sink.Filter(files, []string{"lua"})
+	if len(filtered) != 1 || filtered[0].Path != "lua/items.lua" {
+		t.Errorf("Expected only lua file, got %+v", filtered)
+	}
+}
+
+// TestArchiveSink 测试将文件打成 zip 归档
+func TestArchiveSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dist", "configs.zip")
+	target, err := sink.New(config.SinkConfig{Type: config.SinkArchive, Options: map[string]interface{}{"path": path}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count, err := target.Write(sinkFiles()); err != nil || count != 2 {
+		t.Fatalf("Write failed: %d, %v", count, err)
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("Open archive failed: %v", err)
+	}
+	defer archive.Close()
+	if len(archive.File) != 2 || archive.File[0].Name != "json/items.json" {
+		t.Fatalf("Unexpected archive entries: %+v", archive.File)
+	}
+
+	if _, err := sink.New(config.SinkConfig{Type: config.SinkArchive, Options: map[string]interface{}{}}); err == nil {
+		t.Error("Expected error without path")
+	}
+}
+
+// TestHTTPSink 测试逐个 PUT 文件并带上配置的请求头
+func TestHTTPSink(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received[r.URL.Path] = string(body)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	target, err := sink.New(config.SinkConfig{Type: config.SinkHTTP, Options: map[string]interface{}{
+		"url":     server.URL + "/configs/",
+		"headers": map[string]interface{}{"Authorization": "Bearer token"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count, err := target.Write(sinkFiles()); err != nil || count != 2 {
+		t.Fatalf("Write failed: %d, %v", count, err)
+	}
+	if received["/configs/lua/items.lua"] != "return {}" {
+		t.Errorf("Unexpected uploads: %+v", received)
+	}
+}
+
+// TestRedisSink 测试以 RESP 流水线写入每个文件，错误回复作为失败返回
+func TestRedisSink(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	commands := make(chan []string, 8)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			header, err := reader.ReadString('\n')
+			if err != nil {
+				close(commands)
+				return
+			}
+			var count int
+			if _, err := fmt.Sscanf(header, "*%d", &count); err != nil {
+				return
+			}
+			args := make([]string, 0, count)
+			for i := 0; i < count; i++ {
+				var size int
+				line, _ := reader.ReadString('\n')
+				fmt.Sscanf(line, "$%d", &size)
+				data := make([]byte, size+2)
+				io.ReadFull(reader, data)
+				args = append(args, string(data[:size]))
+			}
+			commands <- args
+			if args[0] == "SET" && strings.HasSuffix(args[1], ".lua") {
+				conn.Write([]byte("-ERR readonly\r\n"))
+			} else {
+				conn.Write([]byte("+OK\r\n"))
+			}
+		}
+	}()
+
+	target, err := sink.New(config.SinkConfig{Type: config.SinkRedis, Options: map[string]interface{}{
+		"addr":      listener.Addr().String(),
+		"db":        float64(2),
+		"keyPrefix": "cfg:",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := target.Write(sinkFiles())
+	if err == nil || !strings.Contains(err.Error(), "readonly") || count != 1 {
+		t.Fatalf("Expected failure on second file, got %d, %v", count, err)
+	}
+
+	if first := <-commands; first[0] != "SELECT" || first[1] != "2" {
+		t.Errorf("Expected SELECT 2 first, got %v", first)
+	}
+	if set := <-commands; set[1] != "cfg:json/items.json" || set[2] != `[{"id":1}]` {
+		t.Errorf("Unexpected SET command: %v", set)
+	}
+}
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/status"
+)
+
+// TestStatusWrite 测试状态文件和徽章的内容
+func TestStatusWrite(t *testing.T) {
+	dir := t.TempDir()
+	buildTime := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	passed := status.New(nil, buildTime, time.Second, "20240501-080000")
+	if err := passed.Write(dir, "", false); err != nil {
+		t.Fatalf("写入状态文件失败: %v", err)
+	}
+	loaded, err := status.Load(dir)
+	if err != nil || loaded == nil {
+		t.Fatalf("读取状态文件失败: %v", err)
+	}
+	if !loaded.Success || loaded.Version != "20240501-080000" || loaded.LastSuccess == nil || loaded.LastSuccess.BuildTime != "2024-05-01T08:00:00Z" {
+		t.Errorf("unexpected status %+v", loaded)
+	}
+	if badge := string(passed.Badge("")); !strings.Contains(badge, "passing 20240501-080000") || !strings.Contains(badge, "#4c1") {
+		t.Errorf("unexpected badge:\n%s", badge)
+	}
+
+	// 验证错误按错误个数计数
+	validation := &model.ValidationError{Errors: []*model.ErrorInfo{{Sheet: "items"}, {Sheet: "items"}, {Sheet: "skills"}}}
+	failed := status.New(fmt.Errorf("构建失败: %w", validation), buildTime, time.Second, "")
+	if failed.Success || failed.ErrorCount != 3 {
+		t.Errorf("unexpected status %+v", failed)
+	}
+	if badge := string(failed.Badge("配置表")); !strings.Contains(badge, "failing: 3 errors") || !strings.Contains(badge, "配置表") {
+		t.Errorf("unexpected badge:\n%s", badge)
+	}
+	if other := status.New(fmt.Errorf("读取源文件失败"), buildTime, 0, ""); other.ErrorCount != 1 {
+		t.Errorf("expected error count 1, got %d", other.ErrorCount)
+	}
+}
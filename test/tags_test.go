@@ -0,0 +1,227 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+	"github.com/game-data-builder/internal/validator"
+)
+
+// TestResolveTags 测试合并表元数据和 sheets.json 中的标签
+func TestResolveTags(t *testing.T) {
+	monsters := &model.DataSheet{Name: "monsters", Meta: map[string]interface{}{"tags": "battle, ui"}}
+	shop := &model.DataSheet{Name: "shop.goods", Meta: map[string]interface{}{"标签": "economy"}}
+	items := &model.DataSheet{Name: "items", Meta: map[string]interface{}{}}
+	sheets := []*model.DataSheet{monsters, shop, items}
+
+	reader.ResolveTags(sheets, &config.SheetsConfig{Sheets: map[string]config.SheetSettings{
+		"monsters": {Tags: []string{"battle"}},
+		"shop.*":   {Tags: []string{"ui"}},
+	}})
+
+	if !reflect.DeepEqual(monsters.Tags, []string{"battle", "ui"}) {
+		t.Errorf("Unexpected monsters tags %v", monsters.Tags)
+	}
+	if !reflect.DeepEqual(shop.Tags, []string{"economy", "ui"}) {
+		t.Errorf("Unexpected shop tags %v", shop.Tags)
+	}
+	if len(items.Tags) != 0 {
+		t.Errorf("Expected items to have no tags, got %v", items.Tags)
+	}
+
+	selected := reader.SelectTags(sheets, reader.ParseTags("economy,battle"))
+	if len(selected) != 2 || selected[0] != monsters || selected[1] != shop {
+		t.Errorf("Unexpected selection %v", selected)
+	}
+}
+
+// TestValidatorRefSheets 测试未选中的表仍可被引用
+func TestValidatorRefSheets(t *testing.T) {
+	items := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}},
+		Rows:    []map[string]interface{}{{"id": 1}},
+	}
+	drops := &model.DataSheet{
+		Name:    "drops",
+		Columns: []model.ColumnInfo{{Name: "item", Type: "int", Ref: &model.RefInfo{Sheet: "items", Column: "id"}}},
+		Rows:    []map[string]interface{}{{"item": 1}, {"item": 2}},
+	}
+
+	v := validator.NewDefaultValidator()
+	v.SetRefSheets([]*model.DataSheet{items})
+	errors := v.ValidateRef([]*model.DataSheet{drops})
+	if len(errors) != 1 || errors[0].Msg != "引用值 2 在表 items 中不存在" {
+		t.Errorf("Unexpected errors %v", errors)
+	}
+}
+
+// TestResolveKeys 测试 sheets.json 中配置的主键列，以及引用校验使用主键而不是第一列
+func TestResolveKeys(t *testing.T) {
+	items := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "name", Type: "string"}, {Name: "id", Type: "int"}},
+		Rows:    []map[string]interface{}{{"name": "sword", "id": 1}},
+	}
+	drops := &model.DataSheet{
+		Name: "shop.drops",
+		Columns: []model.ColumnInfo{
+			{Name: "dropId", Type: "int", IsKey: true},
+			{Name: "item", Type: "int", Ref: &model.RefInfo{Sheet: "items", Column: "id"}},
+		},
+		Rows:      []map[string]interface{}{{"dropId": 1, "item": 1}, {"dropId": 2, "item": 2}},
+		KeyColumn: "dropId",
+	}
+	cfg := &config.SheetsConfig{Sheets: map[string]config.SheetSettings{
+		"items":      {Key: "id"},
+		"shop.*":     {Key: "item"},
+		"shop.drops": {Key: "dropId"},
+	}}
+
+	if err := reader.ResolveKeys([]*model.DataSheet{items, drops}, cfg); err != nil {
+		t.Fatalf("ResolveKeys failed: %v", err)
+	}
+	if items.PrimaryKey() != "id" || !items.Columns[1].IsKey || items.Columns[0].IsKey {
+		t.Errorf("Unexpected items key %q: %+v", items.KeyColumn, items.Columns)
+	}
+	if drops.PrimaryKey() != "dropId" {
+		t.Errorf("Expected exact sheet name to win, got %q", drops.KeyColumn)
+	}
+
+	v := validator.NewDefaultValidator()
+	errors := v.ValidateRef([]*model.DataSheet{items, drops})
+	if len(errors) != 1 || errors[0].Msg != "引用值 2 在表 items 中不存在" {
+		t.Errorf("Unexpected errors %v", errors)
+	}
+
+	// 配置的主键列不存在
+	cfg.Sheets["items"] = config.SheetSettings{Key: "code"}
+	if err := reader.ResolveKeys([]*model.DataSheet{items}, cfg); err == nil {
+		t.Error("Expected error for missing key column")
+	}
+}
+
+// TestResolveDerived 测试派生列：按顺序计算并转换类型，可以引用之前的派生列，重名时报错
+func TestResolveDerived(t *testing.T) {
+	weapons := &model.DataSheet{
+		Name:    "weapons",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "attack", Type: "int"}, {Name: "attackSpeed", Type: "float"}},
+		Rows:    []map[string]interface{}{{"id": 1, "attack": 120, "attackSpeed": 1.5}},
+	}
+	cfg := &config.SheetsConfig{Sheets: map[string]config.SheetSettings{
+		"weapons": {Derived: []config.DerivedColumn{
+			{Name: "dps", Type: "float", Expr: "attack * attackSpeed", Comment: "每秒伤害"},
+			{Name: "dpsLevel", Type: "int", Expr: "floor(dps / 100)"},
+		}},
+	}}
+	if err := reader.ResolveDerived([]*model.DataSheet{weapons}, cfg); err != nil {
+		t.Fatalf("ResolveDerived failed: %v", err)
+	}
+	if len(weapons.Columns) != 5 || weapons.Columns[3].Name != "dps" || weapons.Columns[3].Comment != "每秒伤害" || weapons.Columns[4].Type != "int" {
+		t.Errorf("Unexpected columns: %+v", weapons.Columns)
+	}
+	if row := weapons.Rows[0]; row["dps"] != 180.0 || row["dpsLevel"] != 1 {
+		t.Errorf("Unexpected derived values: %v", row)
+	}
+
+	cfg = &config.SheetsConfig{Sheets: map[string]config.SheetSettings{
+		"weap*": {Derived: []config.DerivedColumn{{Name: "attack", Type: "int", Expr: "1"}}},
+	}}
+	if err := reader.ResolveDerived([]*model.DataSheet{weapons}, cfg); err == nil {
+		t.Error("Expected error for derived column with existing name")
+	}
+}
+
+// TestResolveSort 测试行排序：sheets.json 优先于表元数据，行号随行移动
+func TestResolveSort(t *testing.T) {
+	newSheet := func() *model.DataSheet {
+		return &model.DataSheet{
+			Name:    "items",
+			Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "type", Type: "string"}, {Name: "price", Type: "int"}},
+			Rows: []map[string]interface{}{
+				{"id": 3, "type": "b", "price": 10},
+				{"id": 1, "type": "a", "price": 5},
+				{"id": 2, "type": "a", "price": 20},
+			},
+			Meta: map[string]interface{}{"排序": "id"},
+		}
+	}
+	ids := func(sheet *model.DataSheet) []interface{} {
+		values := make([]interface{}, 0)
+		for _, row := range sheet.Rows {
+			values = append(values, row["id"])
+		}
+		return values
+	}
+
+	sheet := newSheet()
+	if err := reader.ResolveSort([]*model.DataSheet{sheet}, nil); err != nil {
+		t.Fatalf("ResolveSort failed: %v", err)
+	}
+	if got := ids(sheet); !reflect.DeepEqual(got, []interface{}{1, 2, 3}) {
+		t.Errorf("Unexpected order from meta: %v", got)
+	}
+	if sheet.RowNumber(0) != 5 || sheet.RowNumber(2) != 4 {
+		t.Errorf("Expected row numbers to follow rows, got %d and %d", sheet.RowNumber(0), sheet.RowNumber(2))
+	}
+
+	sheet = newSheet()
+	cfg := &config.SheetsConfig{Sheets: map[string]config.SheetSettings{"it*": {Sort: []string{"type", "-price"}}}}
+	if err := reader.ResolveSort([]*model.DataSheet{sheet}, cfg); err != nil {
+		t.Fatalf("ResolveSort failed: %v", err)
+	}
+	if got := ids(sheet); !reflect.DeepEqual(got, []interface{}{2, 1, 3}) {
+		t.Errorf("Unexpected order from sheets.json: %v", got)
+	}
+
+	cfg = &config.SheetsConfig{Sheets: map[string]config.SheetSettings{"items": {Sort: []string{"missing"}}}}
+	if err := reader.ResolveSort([]*model.DataSheet{newSheet()}, cfg); err == nil {
+		t.Error("Expected error for unknown sort column")
+	}
+}
+
+// TestValidateRefColumnAndWhitelist 测试引用非主键列、数组列逐个元素检查，以及外部白名单
+func TestValidateRefColumnAndWhitelist(t *testing.T) {
+	items := &model.DataSheet{
+		Name:    "items",
+		Columns: []model.ColumnInfo{{Name: "id", Type: "int"}, {Name: "code", Type: "string"}, {Name: "icon", Type: "string"}},
+		Rows:    []map[string]interface{}{{"id": 1, "code": "sword", "icon": "ui/sword.png"}, {"id": 2, "code": "axe", "icon": "ui/missing.png"}},
+	}
+	drops := &model.DataSheet{
+		Name: "drops",
+		Columns: []model.ColumnInfo{
+			{Name: "items", Type: "[]string", Ref: &model.RefInfo{Sheet: "items", Column: "code"}},
+			{Name: "bad", Type: "int", Ref: &model.RefInfo{Sheet: "items", Column: "missing"}},
+		},
+		Rows: []map[string]interface{}{{"items": []interface{}{"sword", "1"}, "bad": nil}},
+	}
+
+	path := filepath.Join(t.TempDir(), "assets.txt")
+	os.WriteFile(path, []byte("# exported by art pipeline\nui/sword.png\n\n"), 0644)
+	whitelist, err := validator.LoadWhitelist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := validator.NewDefaultValidator()
+	v.SetWhitelists(map[string]map[string]*validator.Whitelist{"items": {"icon": whitelist}})
+	errors := v.ValidateRef([]*model.DataSheet{items, drops})
+	messages := make([]string, 0)
+	for _, err := range errors {
+		messages = append(messages, err.Sheet+": "+err.Msg)
+	}
+	expected := []string{
+		"items: 值 ui/missing.png 不在白名单 " + path + " 中",
+		"drops: 引用值 1 在表 items 的 code 列中不存在",
+		"drops: 引用的列 items.missing 不存在",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Unexpected errors:\n%v", strings.Join(messages, "\n"))
+	}
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/model"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// TestResolveTemplates 测试模板表继承
+func TestResolveTemplates(t *testing.T) {
+	base := &model.DataSheet{
+		Name: "BaseMonster",
+		Columns: []model.ColumnInfo{
+			{Name: "id", Type: "int"},
+			{Name: "hp", Type: "int", Default: 100},
+			{Name: "speed", Type: "float", Default: 1.0},
+		},
+		Meta: map[string]interface{}{"template": "true"},
+	}
+	goblin := &model.DataSheet{
+		Name: "goblin",
+		Columns: []model.ColumnInfo{
+			{Name: "id", Type: "int"},
+			{Name: "speed", Type: "float", Default: 2.0},
+			{Name: "loot", Type: "string"},
+		},
+		Rows: []map[string]interface{}{{"id": 1, "speed": 1.5, "loot": "coin"}},
+		Meta: map[string]interface{}{"extends": "BaseMonster"},
+	}
+
+	sheets, err := reader.ResolveTemplates([]*model.DataSheet{base, goblin})
+	if err != nil {
+		t.Fatalf("ResolveTemplates failed: %v", err)
+	}
+	if len(sheets) != 1 || sheets[0] != goblin {
+		t.Fatalf("Expected only goblin to remain, got %d sheets", len(sheets))
+	}
+
+	names := make([]string, 0)
+	for _, col := range goblin.Columns {
+		names = append(names, col.Name)
+	}
+	if len(names) != 4 || names[0] != "id" || names[1] != "hp" || names[2] != "speed" || names[3] != "loot" {
+		t.Errorf("Unexpected column order: %v", names)
+	}
+	if goblin.Columns[2].Default != 2.0 {
+		t.Errorf("Expected child to override speed default, got %v", goblin.Columns[2].Default)
+	}
+	if goblin.Rows[0]["hp"] != 100 {
+		t.Errorf("Expected inherited hp default 100, got %v", goblin.Rows[0]["hp"])
+	}
+}
+
+// TestResolveTemplatesMissingBase 测试继承不存在的模板表
+func TestResolveTemplatesMissingBase(t *testing.T) {
+	sheet := &model.DataSheet{Name: "goblin", Meta: map[string]interface{}{"extends": "Nothing"}}
+	if _, err := reader.ResolveTemplates([]*model.DataSheet{sheet}); err == nil {
+		t.Error("Expected error for missing template")
+	}
+}
+
+// TestResolveColumnGroups 测试引入共享列组
+func TestResolveColumnGroups(t *testing.T) {
+	groups := &config.ColumnGroupsConfig{Groups: map[string][]config.ColumnGroupEntry{
+		"reward": {
+			{Name: "reward.itemId", Type: "int", Comment: "奖励物品", Required: true, Ref: "items.id"},
+			{Name: "reward.count", Type: "int", Comment: "奖励数量", Default: float64(1)},
+		},
+	}}
+	quest := &model.DataSheet{
+		Name: "quests",
+		Columns: []model.ColumnInfo{
+			{Name: "id", Type: "int"},
+			{Name: "reward.itemId", Required: true},
+		},
+		Rows: []map[string]interface{}{{"id": 1, "reward": map[string]interface{}{"itemId": "1001"}}},
+		Meta: map[string]interface{}{"include": "reward"},
+	}
+
+	if err := reader.ResolveColumnGroups([]*model.DataSheet{quest}, groups); err != nil {
+		t.Fatalf("ResolveColumnGroups failed: %v", err)
+	}
+	if len(quest.Columns) != 3 {
+		t.Fatalf("Expected reward.count to be appended, got %+v", quest.Columns)
+	}
+	itemID := quest.Columns[1]
+	if itemID.Type != "int" || itemID.Comment != "奖励物品" || itemID.Ref == nil || itemID.Ref.Sheet != "items" {
+		t.Errorf("Unexpected merged column: %+v", itemID)
+	}
+	reward := quest.Rows[0]["reward"].(map[string]interface{})
+	if reward["itemId"] != 1001 || reward["count"] != 1 {
+		t.Errorf("Unexpected reward: %+v", reward)
+	}
+
+	// 与列组定义不一致的类型
+	mismatch := &model.DataSheet{
+		Name:    "shops",
+		Columns: []model.ColumnInfo{{Name: "reward.itemId", Type: "string"}},
+		Meta:    map[string]interface{}{"include": "reward"},
+	}
+	if err := reader.ResolveColumnGroups([]*model.DataSheet{mismatch}, groups); err == nil {
+		t.Error("Expected error for inconsistent column type")
+	}
+
+	// 缺少必填列
+	missing := &model.DataSheet{Name: "mails", Meta: map[string]interface{}{"include": "reward"}}
+	if err := reader.ResolveColumnGroups([]*model.DataSheet{missing}, groups); err == nil {
+		t.Error("Expected error for missing required column")
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/transform"
+)
+
+// TestTransformPipelineExpr 测试表达式处理步骤
+func TestTransformPipelineExpr(t *testing.T) {
+	pipeline, err := transform.NewPipeline(&config.TransformConfig{
+		Transforms: []config.TransformRule{
+			{Sheets: []string{"item*"}, Type: "expr", Column: "priceCents", ColumnType: "int", Expr: "id * 100"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewPipeline failed: %v", err)
+	}
+
+	sheet := newItemSheet()
+	if err := pipeline.Run(newSheets(sheet)); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if sheet.Rows[1]["priceCents"] != 200 {
+		t.Errorf("Expected priceCents 200, got %v", sheet.Rows[1]["priceCents"])
+	}
+	if last := sheet.Columns[len(sheet.Columns)-1]; last.Name != "priceCents" || last.Type != "int" {
+		t.Errorf("Expected new priceCents column, got %+v", last)
+	}
+}
+
+// TestTransformPipelineCancel 测试取消构建时终止正在执行的外部命令
+func TestTransformPipelineCancel(t *testing.T) {
+	pipeline, err := transform.NewPipeline(&config.TransformConfig{
+		Transforms: []config.TransformRule{
+			{Type: "command", Command: []string{"sleep", "10"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewPipeline failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = pipeline.RunContext(ctx, newSheets(newItemSheet()))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected command to be interrupted, took %v", elapsed)
+	}
+
+	canceled, stop := context.WithCancel(context.Background())
+	stop()
+	if err := pipeline.RunContext(canceled, newSheets(newItemSheet())); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
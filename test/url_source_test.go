@@ -0,0 +1,94 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/game-data-builder/internal/config"
+	"github.com/game-data-builder/internal/reader"
+)
+
+// TestURLSourceFetcher 测试下载远程源文件：按内容类型确定文件类型，之后以 ETag 条件请求复用缓存
+func TestURLSourceFetcher(t *testing.T) {
+	content := "id,name\nint,string\n编号,名称\n1,sword\n"
+	downloads, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	options := map[string]interface{}{"retryAttempts": float64(1), "cacheDir": t.TempDir()}
+	source := config.URLSource{URL: server.URL + "/sheets/items/export?format=csv"}
+	for i := 0; i < 2; i++ {
+		fetcher, err := reader.NewURLSourceFetcher(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path, err := fetcher.Fetch(source)
+		if err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+		if filepath.Base(path) != "export.csv" {
+			t.Errorf("Expected file type from content type, got %s", path)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != content {
+			t.Errorf("Unexpected cached content: %q", data)
+		}
+	}
+	if downloads != 1 || notModified != 1 {
+		t.Errorf("Expected one download and one conditional request, got %d and %d", downloads, notModified)
+	}
+
+	// 配置的文件名决定读取器和单表文件的表名
+	fetcher, _ := reader.NewURLSourceFetcher(options)
+	path, err := fetcher.Fetch(config.URLSource{URL: server.URL + "/download", Name: "items.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := reader.NewReaderFactory().CreateReader(path, nil)
+	if err != nil || r == nil {
+		t.Fatalf("Expected csv reader for %s: %v", path, err)
+	}
+	sheets, err := r.ReadAll(path)
+	if err != nil || len(sheets) != 1 || sheets[0].Name != "items" || sheets[0].Rows[0]["name"] != "sword" {
+		t.Errorf("Unexpected sheets: %+v (%v)", sheets, err)
+	}
+}
+
+// TestSourcesConfigValidation 测试 sources.json 的校验
+func TestSourcesConfigValidation(t *testing.T) {
+	confDir := t.TempDir()
+	writeMainConfig(t, confDir, `{"sourceDir": "./examples", "outputDir": "./output"}`)
+
+	cm := config.NewConfigManager()
+	os.WriteFile(filepath.Join(confDir, "sources.json"), []byte(`{"sources": [{"url": "ftp://example.com/items.csv"}]}`), 0644)
+	if err := cm.Load(confDir); err == nil || !strings.Contains(err.Error(), "HTTP(S)") {
+		t.Errorf("Expected error for non-HTTP source, got %v", err)
+	}
+
+	os.WriteFile(filepath.Join(confDir, "sources.json"), []byte(`{"sources": [{"url": "https://example.com/x", "name": "items"}]}`), 0644)
+	if err := cm.Load(confDir); err == nil {
+		t.Error("Expected error for name without extension")
+	}
+
+	os.WriteFile(filepath.Join(confDir, "sources.json"), []byte(`{"sources": [{"url": "https://example.com/x", "name": "items.xlsx", "namespace": "ops"}]}`), 0644)
+	if err := cm.Load(confDir); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cm.Sources.Sources) != 1 || cm.Sources.Sources[0].Namespace != "ops" {
+		t.Errorf("Unexpected sources: %+v", cm.Sources)
+	}
+}
@@ -0,0 +1,244 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/game-data-builder/internal/reader"
+)
+
+// xlsCell 测试用 xls 单元格，值为 string、int、float64 或 bool
+type xlsCell struct {
+	row, col int
+	value    interface{}
+}
+
+// xlsTestSheet 测试用 xls 工作表
+type xlsTestSheet struct {
+	name  string
+	cells []xlsCell
+}
+
+// biffRecordBytes 编码一条 BIFF 记录
+func biffRecordBytes(id uint16, data []byte) []byte {
+	record := binary.LittleEndian.AppendUint16(nil, id)
+	record = binary.LittleEndian.AppendUint16(record, uint16(len(data)))
+	return append(record, data...)
+}
+
+// utf16Bytes 编码 UTF-16LE 字符
+func utf16Bytes(text string) []byte {
+	data := make([]byte, 0)
+	for _, unit := range utf16.Encode([]rune(text)) {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	return data
+}
+
+// buildWorkbookStream 构建 BIFF8 的 Workbook 流；共享字符串表的最后一个字符串为 ASCII 时拆到 CONTINUE 记录中，并改用单字节字符
+func buildWorkbookStream(sheets []xlsTestSheet) []byte {
+	sst := make([]string, 0)
+	sstIndex := make(map[string]int)
+	for _, sheet := range sheets {
+		for _, cell := range sheet.cells {
+			if text, ok := cell.value.(string); ok {
+				if _, exists := sstIndex[text]; !exists {
+					sstIndex[text] = len(sst)
+					sst = append(sst, text)
+				}
+			}
+		}
+	}
+
+	bof := func(kind uint16) []byte {
+		data := make([]byte, 16)
+		binary.LittleEndian.PutUint16(data, 0x0600)
+		binary.LittleEndian.PutUint16(data[2:], kind)
+		return biffRecordBytes(0x0809, data)
+	}
+
+	globals := bof(0x0005)
+	boundSheetPositions := make([]int, 0)
+	for _, sheet := range sheets {
+		boundSheetPositions = append(boundSheetPositions, len(globals)+4)
+		data := make([]byte, 6)
+		data = append(data, byte(len([]rune(sheet.name))), 1)
+		globals = append(globals, biffRecordBytes(0x0085, append(data, utf16Bytes(sheet.name)...))...)
+	}
+
+	sstData := binary.LittleEndian.AppendUint32(nil, uint32(len(sst)))
+	sstData = binary.LittleEndian.AppendUint32(sstData, uint32(len(sst)))
+	var continueData []byte
+	for i, text := range sst {
+		units := utf16.Encode([]rune(text))
+		sstData = binary.LittleEndian.AppendUint16(sstData, uint16(len(units)))
+		sstData = append(sstData, 1)
+		if i == len(sst)-1 && len(units) > 1 && len(units) == len(text) {
+			half := len(units) / 2
+			sstData = append(sstData, utf16Bytes(string(utf16.Decode(units[:half])))...)
+			continueData = append([]byte{0}, []byte(string(utf16.Decode(units[half:])))...)
+			continue
+		}
+		sstData = append(sstData, utf16Bytes(text)...)
+	}
+	globals = append(globals, biffRecordBytes(0x00FC, sstData)...)
+	if continueData != nil {
+		globals = append(globals, biffRecordBytes(0x003C, continueData)...)
+	}
+	globals = append(globals, biffRecordBytes(0x000A, nil)...)
+
+	stream := globals
+	for i, sheet := range sheets {
+		binary.LittleEndian.PutUint32(stream[boundSheetPositions[i]:], uint32(len(stream)))
+		stream = append(stream, bof(0x0010)...)
+		for _, cell := range sheet.cells {
+			data := binary.LittleEndian.AppendUint16(nil, uint16(cell.row))
+			data = binary.LittleEndian.AppendUint16(data, uint16(cell.col))
+			data = binary.LittleEndian.AppendUint16(data, 0)
+			switch value := cell.value.(type) {
+			case string:
+				stream = append(stream, biffRecordBytes(0x00FD, binary.LittleEndian.AppendUint32(data, uint32(sstIndex[value])))...)
+			case int:
+				stream = append(stream, biffRecordBytes(0x027E, binary.LittleEndian.AppendUint32(data, uint32(value<<2|2)))...)
+			case float64:
+				stream = append(stream, biffRecordBytes(0x0203, binary.LittleEndian.AppendUint64(data, math.Float64bits(value)))...)
+			case bool:
+				flag := byte(0)
+				if value {
+					flag = 1
+				}
+				stream = append(stream, biffRecordBytes(0x0205, append(data, flag, 0))...)
+			}
+		}
+		stream = append(stream, biffRecordBytes(0x000A, nil)...)
+	}
+	return stream
+}
+
+// writeXLS 将 Workbook 流封装为只有一个流的复合文档，流补齐到 4096 字节以避免使用迷你流
+func writeXLS(t *testing.T, filePath string, sheets []xlsTestSheet) {
+	const sectorSize = 512
+	const endOfChain, freeSector, noStream = 0xFFFFFFFE, 0xFFFFFFFF, 0xFFFFFFFF
+
+	stream := buildWorkbookStream(sheets)
+	streamSize := len(stream)
+	if streamSize < 4096 {
+		streamSize = 4096
+	}
+	stream = append(stream, make([]byte, (streamSize+sectorSize-1)/sectorSize*sectorSize-len(stream))...)
+	streamSectors := len(stream) / sectorSize
+
+	header := make([]byte, sectorSize)
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	binary.LittleEndian.PutUint16(header[24:], 0x003E)
+	binary.LittleEndian.PutUint16(header[26:], 0x0003)
+	binary.LittleEndian.PutUint16(header[28:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[30:], 9)
+	binary.LittleEndian.PutUint16(header[32:], 6)
+	binary.LittleEndian.PutUint32(header[44:], 1) // FAT 扇区数
+	binary.LittleEndian.PutUint32(header[48:], 1) // 目录起始扇区
+	binary.LittleEndian.PutUint32(header[56:], 4096)
+	binary.LittleEndian.PutUint32(header[60:], endOfChain)
+	binary.LittleEndian.PutUint32(header[68:], endOfChain)
+	binary.LittleEndian.PutUint32(header[76:], 0) // FAT 位于扇区 0
+	for i := 1; i < 109; i++ {
+		binary.LittleEndian.PutUint32(header[76+i*4:], freeSector)
+	}
+
+	// 扇区 0 为 FAT，扇区 1 为目录，之后为 Workbook 流
+	fat := make([]byte, sectorSize)
+	for i := 0; i < sectorSize/4; i++ {
+		binary.LittleEndian.PutUint32(fat[i*4:], freeSector)
+	}
+	binary.LittleEndian.PutUint32(fat, 0xFFFFFFFD)
+	binary.LittleEndian.PutUint32(fat[4:], endOfChain)
+	for i := 0; i < streamSectors; i++ {
+		next := uint32(2 + i + 1)
+		if i == streamSectors-1 {
+			next = endOfChain
+		}
+		binary.LittleEndian.PutUint32(fat[(2+i)*4:], next)
+	}
+
+	directory := make([]byte, sectorSize)
+	entry := func(index int, name string, kind byte, child, start uint32, size int) {
+		data := directory[index*128 : (index+1)*128]
+		copy(data, utf16Bytes(name))
+		binary.LittleEndian.PutUint16(data[64:], uint16(len(name)*2+2))
+		data[66], data[67] = kind, 1
+		binary.LittleEndian.PutUint32(data[68:], noStream)
+		binary.LittleEndian.PutUint32(data[72:], noStream)
+		binary.LittleEndian.PutUint32(data[76:], child)
+		binary.LittleEndian.PutUint32(data[116:], start)
+		binary.LittleEndian.PutUint32(data[120:], uint32(size))
+	}
+	entry(0, "Root Entry", 5, 1, endOfChain, 0)
+	entry(1, "Workbook", 2, noStream, 2, streamSize)
+	for i := 2; i < 4; i++ {
+		entry(i, "", 0, noStream, 0, 0)
+	}
+
+	var file bytes.Buffer
+	file.Write(header)
+	file.Write(fat)
+	file.Write(directory)
+	file.Write(stream)
+	if err := os.WriteFile(filePath, file.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestXLSReader 测试读取 Excel 97-2003 格式的文件
+func TestXLSReader(t *testing.T) {
+	row := func(index int, values ...interface{}) []xlsCell {
+		cells := make([]xlsCell, 0, len(values))
+		for col, value := range values {
+			if value != nil {
+				cells = append(cells, xlsCell{row: index, col: col, value: value})
+			}
+		}
+		return cells
+	}
+	items := xlsTestSheet{name: "items"}
+	items.cells = append(items.cells, row(0, "id", "name", "price", "enabled")...)
+	items.cells = append(items.cells, row(1, "int", "string", "float", "bool")...)
+	items.cells = append(items.cells, row(2, "编号|主键", "名称", "价格", "启用")...)
+	items.cells = append(items.cells, row(3, 1, "长剑", 9.5, true)...)
+	items.cells = append(items.cells, row(4, 2, nil, math.Nextafter(0.3, 1), false)...)
+	items.cells = append(items.cells, row(5, 3, "shield", 12.0, nil)...)
+	notes := xlsTestSheet{name: "_notes", cells: row(0, "notes")}
+
+	filePath := filepath.Join(t.TempDir(), "items.xls")
+	writeXLS(t, filePath, []xlsTestSheet{items, notes})
+
+	r, err := reader.NewReaderFactory().CreateReader(filePath, nil)
+	if err != nil || r == nil {
+		t.Fatalf("Expected xls reader, got %v (%v)", r, err)
+	}
+	sheets, err := r.ReadAll(filePath)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(sheets) != 1 || sheets[0].Name != "items" || sheets[0].KeyColumn != "id" {
+		t.Fatalf("Unexpected sheets: %+v", sheets)
+	}
+
+	expected := []map[string]interface{}{
+		{"id": 1, "name": "长剑", "price": 9.5, "enabled": true},
+		{"id": 2, "name": nil, "price": 0.3, "enabled": false},
+		{"id": 3, "name": "shield", "price": 12.0, "enabled": nil},
+	}
+	for i, want := range expected {
+		for key, value := range want {
+			if got := sheets[0].Rows[i][key]; !reflect.DeepEqual(got, value) {
+				t.Errorf("Row %d column %s: expected %v, got %v", i, key, value, got)
+			}
+		}
+	}
+}